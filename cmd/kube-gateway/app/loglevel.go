@@ -0,0 +1,47 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"k8s.io/component-base/logs"
+	"k8s.io/klog"
+)
+
+// resetLogLevelOnSIGHUP restores klog verbosity to the given level when SIGHUP is received,
+// so that verbosity raised at runtime via PUT /debug/flags/v can be dialed back without restart.
+func resetLogLevelOnSIGHUP(level string, stopCh <-chan struct{}) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP)
+	go func() {
+		defer signal.Stop(sigCh)
+		for {
+			select {
+			case <-sigCh:
+				msg, err := logs.GlogSetter(level)
+				if err != nil {
+					klog.Errorf("failed to reset log level on SIGHUP: %v", err)
+					continue
+				}
+				klog.Infof("received SIGHUP, %s", msg)
+			case <-stopCh:
+				return
+			}
+		}
+	}()
+}
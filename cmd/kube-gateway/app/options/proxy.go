@@ -27,6 +27,8 @@ type ProxyOptions struct {
 	SecureServing  *proxyoptions.SecureServingOptions
 	ProcessInfo    *genericoptions.ProcessInfo
 	Logging        *proxyoptions.LoggingOptions
+	Dispatcher     *proxyoptions.DispatcherOptions
+	Upstream       *proxyoptions.UpstreamOptions
 }
 
 func NewProxyOptions() *ProxyOptions {
@@ -36,6 +38,8 @@ func NewProxyOptions() *ProxyOptions {
 		SecureServing:  proxyoptions.NewSecureServingOptions(),
 		ProcessInfo:    genericoptions.NewProcessInfo("kube-gateway-proxy", "kube-system"),
 		Logging:        proxyoptions.NewLoggingOptions(),
+		Dispatcher:     proxyoptions.NewDispatcherOptions(),
+		Upstream:       proxyoptions.NewUpstreamOptions(),
 	}
 }
 
@@ -46,5 +50,7 @@ func (s *ProxyOptions) Flags() (fss cliflag.NamedFlagSets) {
 	s.Authorization.AddFlags(fs)
 	s.SecureServing.AddFlags(fs)
 	s.Logging.AddFlags(fs)
+	s.Dispatcher.AddFlags(fs)
+	s.Upstream.AddFlags(fs)
 	return
 }
@@ -15,10 +15,18 @@
 package options
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+
 	"github.com/kubewharf/apiserver-runtime/pkg/scheme"
 	apiextensionsapiserver "k8s.io/apiextensions-apiserver/pkg/apiserver"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/component-base/metrics"
 	aggregatorscheme "k8s.io/kube-aggregator/pkg/apiserver/scheme"
+
+	proxyoptions "github.com/kubewharf/kubegateway/pkg/gateway/proxy/options"
 )
 
 func (s *ControlPlaneServerRunOptions) Validate() []error {
@@ -37,6 +45,9 @@ func (o *ProxyOptions) Validate(controlplane *ControlPlaneServerRunOptions) []er
 	errs = append(errs, o.Authentication.Validate()...)
 	errs = append(errs, o.Authorization.Validate()...)
 	errs = append(errs, o.SecureServing.ValidateWith(*controlplane.SecureServing)...)
+	errs = append(errs, o.Logging.Validate()...)
+	errs = append(errs, o.Dispatcher.Validate()...)
+	errs = append(errs, o.Upstream.Validate()...)
 	return errs
 }
 
@@ -46,3 +57,39 @@ func (o *Options) Validate() []error {
 	errs = append(errs, o.Proxy.Validate(o.ControlPlane)...)
 	return errs
 }
+
+// ValidationError is the result of Validate(), it prints one error per line attributed
+// to the invalid flag or field, instead of concatenating them into a wall of text.
+type ValidationError struct {
+	Errors []error
+}
+
+// NewValidationError returns nil if there is no error, nested aggregates are flattened.
+func NewValidationError(errs []error) error {
+	flattened := utilerrors.Flatten(utilerrors.NewAggregate(errs))
+	if flattened == nil {
+		return nil
+	}
+	return &ValidationError{Errors: flattened.Errors()}
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "invalid options, %d error(s) found:", len(e.Errors))
+	for i, err := range e.Errors {
+		var flagErr *proxyoptions.FlagError
+		var fieldErr *field.Error
+		switch {
+		case errors.As(err, &flagErr):
+			fmt.Fprintf(&b, "\n  %d) --%s: %s", i+1, flagErr.Flag, flagErr.Detail)
+			if len(flagErr.Hint) > 0 {
+				fmt.Fprintf(&b, "\n     how to fix: %s", flagErr.Hint)
+			}
+		case errors.As(err, &fieldErr):
+			fmt.Fprintf(&b, "\n  %d) %s: %s", i+1, fieldErr.Field, fieldErr.ErrorBody())
+		default:
+			fmt.Fprintf(&b, "\n  %d) %v", i+1, err)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,89 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	proxyoptions "github.com/kubewharf/kubegateway/pkg/gateway/proxy/options"
+)
+
+func TestNewValidationError(t *testing.T) {
+	flagErr := &proxyoptions.FlagError{Flag: "proxy-max-request-duration", Detail: "can not be negative, got -1s", Hint: "set it to 0 to disable it"}
+	tests := []struct {
+		name string
+		errs []error
+		want string
+	}{
+		{"no error", nil, ""},
+		{
+			"flag error with hint",
+			[]error{flagErr},
+			"invalid options, 1 error(s) found:\n" +
+				"  1) --proxy-max-request-duration: can not be negative, got -1s\n" +
+				"     how to fix: set it to 0 to disable it",
+		},
+		{
+			"flag error without hint",
+			[]error{&proxyoptions.FlagError{Flag: "proxy-list-affinity", Detail: "is invalid"}},
+			"invalid options, 1 error(s) found:\n" +
+				"  1) --proxy-list-affinity: is invalid",
+		},
+		{
+			"wrapped flag error",
+			[]error{fmt.Errorf("proxy: %w", flagErr)},
+			"invalid options, 1 error(s) found:\n" +
+				"  1) --proxy-max-request-duration: can not be negative, got -1s\n" +
+				"     how to fix: set it to 0 to disable it",
+		},
+		{
+			"field error",
+			[]error{field.Invalid(field.NewPath("spec", "qps"), -1, "must be positive")},
+			"invalid options, 1 error(s) found:\n" +
+				"  1) spec.qps: Invalid value: -1: must be positive",
+		},
+		{
+			"nested aggregate and plain error",
+			[]error{utilerrors.NewAggregate([]error{errors.New("first"), errors.New("second")}), errors.New("third")},
+			"invalid options, 3 error(s) found:\n" +
+				"  1) first\n" +
+				"  2) second\n" +
+				"  3) third",
+		},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewValidationError(tt.errs)
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Errorf("NewValidationError() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("NewValidationError() = nil, want %q", tt.want)
+			}
+			if got := err.Error(); got != tt.want {
+				t.Errorf("ValidationError.Error() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
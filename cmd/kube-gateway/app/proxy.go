@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"log"
 	"net/http"
+	"strings"
 
 	"github.com/kubewharf/apiserver-runtime/pkg/scheme"
 	apiserver "github.com/kubewharf/apiserver-runtime/pkg/server"
@@ -33,6 +34,7 @@ import (
 	"github.com/kubewharf/kubegateway/pkg/clusters"
 	"github.com/kubewharf/kubegateway/pkg/gateway/controllers"
 	controlplaneserver "github.com/kubewharf/kubegateway/pkg/gateway/controlplane"
+	"github.com/kubewharf/kubegateway/pkg/gateway/debug"
 	gatewayfilters "github.com/kubewharf/kubegateway/pkg/gateway/endpoints/filters"
 	"github.com/kubewharf/kubegateway/pkg/gateway/endpoints/request"
 	proxyserver "github.com/kubewharf/kubegateway/pkg/gateway/proxy"
@@ -53,20 +55,41 @@ func CreateProxyConfig(
 	// openapi
 	recommendedConfig.WithOpenapiConfig("KubeGatewayProxy", GetNativeOpenAPIDefinitions)
 
-	if lastErr = o.SecureServing.ApplyTo(&recommendedConfig.SecureServing, *controlplaneOptions.SecureServing); lastErr != nil {
+	// trusted proxies are validated in ProxyOptions.Validate()
+	trustedProxies, _ := o.Dispatcher.TrustedProxies()
+	if lastErr = o.SecureServing.ApplyTo(&recommendedConfig.SecureServing, *controlplaneOptions.SecureServing, trustedProxies); lastErr != nil {
 		return
 	}
 
 	// customize http error log to filter out some noisy log
 	// referred to k8s.io/component-base/logs/logs.go#InitLogs()
-	recommendedConfig.SecureServing.ErrorLog = log.New(proxyHTTPErrorLogWriter{}, "", 0)
+	recommendedConfig.SecureServing.ErrorLog = log.New(proxyHTTPErrorLogWriter{logTLSHandshakeErrors: o.Logging.EnableTLSHandshakeLog}, "", 0)
 
 	// create upstream controller
-	clusterController := controllers.NewUpstreamClusterController(controlplaneServerConfig.ExtraConfig.GatewaySharedInformerFactory.Proxy().V1alpha1().UpstreamClusters())
+	clusterController := controllers.NewUpstreamClusterController(
+		controlplaneServerConfig.ExtraConfig.GatewaySharedInformerFactory.Proxy().V1alpha1().UpstreamClusters(),
+		controllers.UpstreamClusterControllerConfig{
+			Cluster: clusters.ClusterInfoConfig{
+				// guard against a misconfiguration registering too many endpoints
+				MaxEndpoints:                     o.Upstream.MaxEndpointsPerCluster,
+				DispatchPoliciesWarningThreshold: o.Upstream.DispatchPoliciesWarningThreshold,
+				MaxDispatchPolicies:              o.Upstream.MaxDispatchPolicies,
+				ReconcileEphemeralEndpoints:      o.Upstream.ReconcileEphemeralEndpoints,
+			},
+			HealthCheckVerboseReadyz: o.Upstream.HealthCheckVerboseReadyz,
+		},
+	)
+	clusterController.SetRequireSNI(o.SecureServing.RequireSNI)
+	clusterController.SetConfigFailureThreshold(int(o.Upstream.ConfigFailureThreshold))
 	// Dynamic SNI for upstream cluster
 	recommendedConfig.Config.SecureServing.DynamicClientConfig = clusterController
+	// drain in-flight proxied requests during shutdown
+	var drainer *gatewayfilters.RequestDrainer
+	if o.SecureServing.GracefulDrainTimeout > 0 {
+		drainer = gatewayfilters.NewRequestDrainer()
+	}
 	// Proxy handler
-	recommendedConfig.Config.BuildHandlerChainFunc = buildProxyHandlerChainFunc(clusterController, o.Logging.EnableProxyAccessLog)
+	recommendedConfig.Config.BuildHandlerChainFunc = buildProxyHandlerChainFunc(clusterController, o, drainer)
 
 	// Proxy authentication
 	if lastErr = o.Authentication.ApplyTo(
@@ -94,7 +117,12 @@ func CreateProxyConfig(
 	serverConfig = &proxyserver.Config{
 		RecommendedConfig: recommendedConfig,
 		ExtraConfig: proxyserver.ExtraConfig{
-			UpstreamClusterController: clusterController,
+			UpstreamClusterController:        clusterController,
+			RequestDrainer:                   drainer,
+			GracefulDrainTimeout:             o.SecureServing.GracefulDrainTimeout,
+			SelfTestPolicy:                   controllers.SelfTestPolicy(o.Upstream.SelfTestPolicy),
+			EmptyUpstreamsPolicy:             controllers.EmptyUpstreamsPolicy(o.Upstream.EmptyUpstreamsPolicy),
+			DegradeReadinessOnConfigFailures: o.Upstream.DegradeReadinessOnConfigFailures,
 		},
 	}
 	return serverConfig, nil
@@ -109,12 +137,34 @@ func buildProxyRecommenedOptions(o *options.ProxyOptions, controlplaneOptions *o
 	return recommenedOptions
 }
 
-func buildProxyHandlerChainFunc(clusterManager clusters.Manager, enableAccessLog bool) func(apiHandler http.Handler, c *genericapiserver.Config) http.Handler {
+func buildProxyHandlerChainFunc(clusterManager clusters.Manager, o *options.ProxyOptions, drainer *gatewayfilters.RequestDrainer) func(apiHandler http.Handler, c *genericapiserver.Config) http.Handler {
 	return func(apiHandler http.Handler, c *genericapiserver.Config) http.Handler {
+		// access log fields and trusted proxies are validated in ProxyOptions.Validate()
+		accessLogFields, _ := o.Logging.AccessLogFields()
+		trustedProxies, _ := o.Dispatcher.TrustedProxies()
 		// new gateway handler chain
-		handler := gatewayfilters.WithDispatcher(apiHandler, proxydispatcher.NewDispatcher(clusterManager, enableAccessLog))
+		handler := gatewayfilters.WithDispatcher(apiHandler, proxydispatcher.NewDispatcher(clusterManager, proxydispatcher.DispatcherConfig{
+			EnableAccessLog:        o.Logging.EnableProxyAccessLog,
+			AccessLogFields:        accessLogFields,
+			AccessLogFormat:        proxydispatcher.AccessLogFormat(o.Logging.AccessLogFormat),
+			MalformedRequestPolicy: proxydispatcher.MalformedRequestPolicy(o.Dispatcher.MalformedRequestPolicy),
+			LongRunningFunc:        c.LongRunningFunc,
+			MaxReplayableBodyBytes: o.Dispatcher.MaxReplayableBodyBytes,
+			FlowControlAuditPolicy: proxydispatcher.FlowControlAuditPolicy(o.Dispatcher.FlowControlAuditPolicy),
+			ListAffinity:           o.Dispatcher.ListAffinity,
+			WatchAbortPolicy:       proxydispatcher.WatchAbortPolicy(o.Dispatcher.WatchAbortPolicy),
+			MaxWatchDuration:       o.Dispatcher.MaxWatchDuration,
+		}))
+		// well-known paths like /version are served by the gateway itself if configured
+		handler = gatewayfilters.WithGatewayServedPaths(handler, apiHandler, o.Dispatcher.GatewayServedPaths)
+		// health of upstream clusters is served by the gateway for users authorized to get the path
+		handler = gatewayfilters.WithAuthorizedPath(handler, debug.ClusterHealthPath, debug.ClusterHealthHandler(clusterManager), c.Authorization.Authorizer, c.Serializer)
+		// count and limit impersonation requests after they are authorized
+		handler = gatewayfilters.WithImpersonationLimit(handler, o.Authorization.ImpersonationQPS, o.Authorization.ImpersonationBurst)
 		// without impersonation log
 		handler = gatewayfilters.WithNoLoggingImpersonation(handler, c.Authorization.Authorizer, c.Serializer)
+		// impersonation targets forbidden by clusters are rejected before impersonation is authorized
+		handler = gatewayfilters.WithImpersonationPolicy(handler, clusterManager, c.Serializer)
 		// new gateway handler chain, add impersonator userInfo
 		handler = gatewayfilters.WithImpersonator(handler)
 		handler = genericapifilters.WithAudit(handler, c.AuditBackend, c.AuditPolicyChecker, c.LongRunningFunc)
@@ -122,17 +172,24 @@ func buildProxyHandlerChainFunc(clusterManager clusters.Manager, enableAccessLog
 		failedHandler = genericapifilters.WithFailedAuthenticationAudit(failedHandler, c.AuditBackend, c.AuditPolicyChecker)
 		handler = genericapifilters.WithAuthentication(handler, c.Authentication.Authenticator, failedHandler, c.Authentication.APIAudiences)
 		handler = genericfilters.WithCORS(handler, c.CorsAllowedOriginList, nil, nil, nil, "true")
-		// disabel timeout, let upstream cluster handle it
-		// handler = gatewayfilters.WithTimeoutForNonLongRunningRequests(handler, c.LongRunningFunc, c.RequestTimeout)
+		// timeouts are handled by upstream clusters, only the ceiling of request duration is enforced if configured
+		handler = gatewayfilters.WithMaxRequestDuration(handler, c.LongRunningFunc, o.Dispatcher.MaxRequestDuration)
 		handler = genericfilters.WithWaitGroup(handler, c.LongRunningFunc, c.HandlerChainWaitGroup)
+		// track all proxied requests including long-running ones, it is a no-op if drainer is nil
+		handler = gatewayfilters.WithRequestDrainer(handler, drainer)
+		handler = gatewayfilters.WithMaxInflightPerConnection(handler, o.SecureServing.MaxRequestsInflightPerConnection, c.LongRunningFunc)
 		// new gateway handler chain
 		handler = gatewayfilters.WithPreProcessingMetrics(handler)
-		handler = gatewayfilters.WithExtraRequestInfo(handler, &request.ExtraRequestInfoFactory{})
-		handler = gatewayfilters.WithTerminationMetrics(handler)
-		handler = genericapifilters.WithRequestInfo(handler, c.RequestInfoResolver)
 		if c.SecureServing != nil && !c.SecureServing.DisableHTTP2 && c.GoawayChance > 0 {
-			handler = genericfilters.WithProbabilisticGoaway(handler, c.GoawayChance)
+			// probabilistic goaway can be disabled per cluster, so it needs extra request info
+			handler = gatewayfilters.WithProbabilisticGoaway(handler, c.GoawayChance, clusterManager)
 		}
+		handler = gatewayfilters.WithExtraRequestInfo(handler, &request.ExtraRequestInfoFactory{
+			HostnameMismatchPolicy: request.HostnameMismatchPolicy(o.Dispatcher.HostnameMismatchPolicy),
+			TrustedProxies:         trustedProxies,
+		})
+		handler = gatewayfilters.WithTerminationMetrics(handler)
+		handler = genericapifilters.WithRequestInfo(handler, c.RequestInfoResolver)
 		handler = genericapifilters.WithCacheControl(handler)
 		handler = gatewayfilters.WithNoLoggingPanicRecovery(handler)
 		return handler
@@ -143,15 +200,50 @@ func GetNativeOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common
 	return nativeopenapi.GetOpenAPIDefinitions(ref)
 }
 
+var tlsHandshakeErrorPrefix = []byte("http: TLS handshake error from ")
+
 // proxyHTTPErrorLogWriter serves as a bridge between the standard log package and the klog package.
 // It also filter out some noisy http error log
-type proxyHTTPErrorLogWriter struct{}
+type proxyHTTPErrorLogWriter struct {
+	// logTLSHandshakeErrors writes failed tls handshakes to connection logs instead of dropping them
+	logTLSHandshakeErrors bool
+}
 
 // Write implements the io.Writer interface.
 func (writer proxyHTTPErrorLogWriter) Write(data []byte) (n int, err error) {
-	if bytes.HasPrefix(data, []byte("http: TLS handshake error from")) {
-		return 0, nil
+	if bytes.HasPrefix(data, tlsHandshakeErrorPrefix) {
+		if writer.logTLSHandshakeErrors {
+			logTLSHandshakeError(string(bytes.TrimSpace(data[len(tlsHandshakeErrorPrefix):])))
+		}
+		return len(data), nil
 	}
 	klog.InfoDepth(1, string(data))
 	return len(data), nil
 }
+
+// logTLSHandshakeError writes a connection log of a failed tls handshake, message is in the
+// format of "<client address>: <error>" which is written by net/http server.
+func logTLSHandshakeError(message string) {
+	client, reason := message, ""
+	if i := strings.Index(message, ": "); i >= 0 {
+		client, reason = message[:i], message[i+2:]
+	}
+	klog.Infof("[tls handshake] failed, client=%q category=%q reason=%q", client, tlsHandshakeFailureCategory(reason), reason)
+}
+
+// tlsHandshakeFailureCategory groups reasons of failed tls handshakes to make client certificate
+// problems easy to filter out of clients simply going away
+func tlsHandshakeFailureCategory(reason string) string {
+	switch {
+	case strings.Contains(reason, "certificate"):
+		return "client_certificate"
+	case strings.Contains(reason, "server name indication"):
+		return "missing_sni"
+	case reason == "EOF", strings.Contains(reason, "connection reset"), strings.Contains(reason, "broken pipe"), strings.Contains(reason, "timeout"):
+		return "connection_closed"
+	case strings.Contains(reason, "protocol version"), strings.Contains(reason, "cipher"), strings.Contains(reason, "first record does not look like"):
+		return "protocol"
+	default:
+		return "other"
+	}
+}
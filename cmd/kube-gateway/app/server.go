@@ -22,7 +22,6 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
-	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	genericapiserver "k8s.io/apiserver/pkg/server"
 	"k8s.io/apiserver/pkg/util/term"
 	cliflag "k8s.io/component-base/cli/flag"
@@ -36,6 +35,7 @@ import (
 	"github.com/kubewharf/apiserver-runtime/pkg/server"
 
 	"github.com/kubewharf/kubegateway/cmd/kube-gateway/app/options"
+	"github.com/kubewharf/kubegateway/pkg/gateway/debug"
 )
 
 const (
@@ -63,11 +63,16 @@ cluster's shared state through which all other components interact.`,
 			}
 
 			// validate options
-			if errs := s.Validate(); len(errs) != 0 {
-				return utilerrors.NewAggregate(errs)
+			if err := options.NewValidationError(s.Validate()); err != nil {
+				return err
 			}
 
-			return Run(s, genericapiserver.SetupSignalHandler())
+			stopCh := genericapiserver.SetupSignalHandler()
+			// log level can be raised at runtime, SIGHUP resets it to the startup level
+			if v := cmd.Flags().Lookup("v"); v != nil {
+				resetLogLevelOnSIGHUP(v.Value.String(), stopCh)
+			}
+			return Run(s, stopCh)
 		},
 		SilenceUsage: true,
 	}
@@ -128,6 +133,14 @@ func CreateKubeGatewayServer(o *options.Options, stopCh <-chan struct{}) (*serve
 		return nil, err
 	}
 
+	// debug endpoints of proxy are served by control plane which has authentication and authorization
+	debug.InstallFlowControlHandler(controlPlaneServer.GenericAPIServer.Handler.NonGoRestfulMux, proxyConfig.ExtraConfig.UpstreamClusterController)
+	debug.InstallFlowControlOverridesHandler(controlPlaneServer.GenericAPIServer.Handler.NonGoRestfulMux, proxyConfig.ExtraConfig.UpstreamClusterController)
+	debug.InstallIsolationsHandler(controlPlaneServer.GenericAPIServer.Handler.NonGoRestfulMux, proxyConfig.ExtraConfig.UpstreamClusterController)
+	debug.InstallEphemeralEndpointsHandler(controlPlaneServer.GenericAPIServer.Handler.NonGoRestfulMux, proxyConfig.ExtraConfig.UpstreamClusterController)
+	debug.InstallPausesHandler(controlPlaneServer.GenericAPIServer.Handler.NonGoRestfulMux, proxyConfig.ExtraConfig.UpstreamClusterController)
+	debug.InstallHealthHistoryHandler(controlPlaneServer.GenericAPIServer.Handler.NonGoRestfulMux, proxyConfig.ExtraConfig.UpstreamClusterController)
+
 	controlPlaneServer.AddSidecarServers(proxyServer)
 	return controlPlaneServer, nil
 }
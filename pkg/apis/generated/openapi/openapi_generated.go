@@ -33,79 +33,178 @@ import (
 
 func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
 	return map[string]common.OpenAPIDefinition{
-		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.ClientConfig":                         schema_pkg_apis_proxy_v1alpha1_ClientConfig(ref),
-		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.DispatchPolicy":                       schema_pkg_apis_proxy_v1alpha1_DispatchPolicy(ref),
-		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.DispatchPolicyRule":                   schema_pkg_apis_proxy_v1alpha1_DispatchPolicyRule(ref),
-		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.ExemptFlowControlSchema":              schema_pkg_apis_proxy_v1alpha1_ExemptFlowControlSchema(ref),
-		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.FlowControl":                          schema_pkg_apis_proxy_v1alpha1_FlowControl(ref),
-		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.FlowControlSchema":                    schema_pkg_apis_proxy_v1alpha1_FlowControlSchema(ref),
-		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.FlowControlSchemaConfiguration":       schema_pkg_apis_proxy_v1alpha1_FlowControlSchemaConfiguration(ref),
-		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.LoggingConfig":                        schema_pkg_apis_proxy_v1alpha1_LoggingConfig(ref),
-		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.MaxRequestsInflightFlowControlSchema": schema_pkg_apis_proxy_v1alpha1_MaxRequestsInflightFlowControlSchema(ref),
-		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.SecretReferecence":                    schema_pkg_apis_proxy_v1alpha1_SecretReferecence(ref),
-		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.SecureServing":                        schema_pkg_apis_proxy_v1alpha1_SecureServing(ref),
-		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.ServiceAccountRef":                    schema_pkg_apis_proxy_v1alpha1_ServiceAccountRef(ref),
-		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.TokenBucketFlowControlSchema":         schema_pkg_apis_proxy_v1alpha1_TokenBucketFlowControlSchema(ref),
-		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.UpstreamCluster":                      schema_pkg_apis_proxy_v1alpha1_UpstreamCluster(ref),
-		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.UpstreamClusterList":                  schema_pkg_apis_proxy_v1alpha1_UpstreamClusterList(ref),
-		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.UpstreamClusterServer":                schema_pkg_apis_proxy_v1alpha1_UpstreamClusterServer(ref),
-		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.UpstreamClusterSpec":                  schema_pkg_apis_proxy_v1alpha1_UpstreamClusterSpec(ref),
-		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.UpstreamClusterStatus":                schema_pkg_apis_proxy_v1alpha1_UpstreamClusterStatus(ref),
-		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.matcher":                              schema_pkg_apis_proxy_v1alpha1_matcher(ref),
-		"k8s.io/apimachinery/pkg/api/resource.Quantity":                                                 schema_apimachinery_pkg_api_resource_Quantity(ref),
-		"k8s.io/apimachinery/pkg/api/resource.int64Amount":                                              schema_apimachinery_pkg_api_resource_int64Amount(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.APIGroup":                                                 schema_pkg_apis_meta_v1_APIGroup(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.APIGroupList":                                             schema_pkg_apis_meta_v1_APIGroupList(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.APIResource":                                              schema_pkg_apis_meta_v1_APIResource(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.APIResourceList":                                          schema_pkg_apis_meta_v1_APIResourceList(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.APIVersions":                                              schema_pkg_apis_meta_v1_APIVersions(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.CreateOptions":                                            schema_pkg_apis_meta_v1_CreateOptions(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.DeleteOptions":                                            schema_pkg_apis_meta_v1_DeleteOptions(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.Duration":                                                 schema_pkg_apis_meta_v1_Duration(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.ExportOptions":                                            schema_pkg_apis_meta_v1_ExportOptions(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.FieldsV1":                                                 schema_pkg_apis_meta_v1_FieldsV1(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.GetOptions":                                               schema_pkg_apis_meta_v1_GetOptions(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupKind":                                                schema_pkg_apis_meta_v1_GroupKind(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupResource":                                            schema_pkg_apis_meta_v1_GroupResource(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupVersion":                                             schema_pkg_apis_meta_v1_GroupVersion(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupVersionForDiscovery":                                 schema_pkg_apis_meta_v1_GroupVersionForDiscovery(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupVersionKind":                                         schema_pkg_apis_meta_v1_GroupVersionKind(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupVersionResource":                                     schema_pkg_apis_meta_v1_GroupVersionResource(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.InternalEvent":                                            schema_pkg_apis_meta_v1_InternalEvent(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector":                                            schema_pkg_apis_meta_v1_LabelSelector(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelectorRequirement":                                 schema_pkg_apis_meta_v1_LabelSelectorRequirement(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.List":                                                     schema_pkg_apis_meta_v1_List(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta":                                                 schema_pkg_apis_meta_v1_ListMeta(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.ListOptions":                                              schema_pkg_apis_meta_v1_ListOptions(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.ManagedFieldsEntry":                                       schema_pkg_apis_meta_v1_ManagedFieldsEntry(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime":                                                schema_pkg_apis_meta_v1_MicroTime(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta":                                               schema_pkg_apis_meta_v1_ObjectMeta(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.OwnerReference":                                           schema_pkg_apis_meta_v1_OwnerReference(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.PartialObjectMetadata":                                    schema_pkg_apis_meta_v1_PartialObjectMetadata(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.PartialObjectMetadataList":                                schema_pkg_apis_meta_v1_PartialObjectMetadataList(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.Patch":                                                    schema_pkg_apis_meta_v1_Patch(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.PatchOptions":                                             schema_pkg_apis_meta_v1_PatchOptions(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.Preconditions":                                            schema_pkg_apis_meta_v1_Preconditions(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.RootPaths":                                                schema_pkg_apis_meta_v1_RootPaths(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.ServerAddressByClientCIDR":                                schema_pkg_apis_meta_v1_ServerAddressByClientCIDR(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.Status":                                                   schema_pkg_apis_meta_v1_Status(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.StatusCause":                                              schema_pkg_apis_meta_v1_StatusCause(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.StatusDetails":                                            schema_pkg_apis_meta_v1_StatusDetails(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.Table":                                                    schema_pkg_apis_meta_v1_Table(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.TableColumnDefinition":                                    schema_pkg_apis_meta_v1_TableColumnDefinition(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.TableOptions":                                             schema_pkg_apis_meta_v1_TableOptions(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.TableRow":                                                 schema_pkg_apis_meta_v1_TableRow(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.TableRowCondition":                                        schema_pkg_apis_meta_v1_TableRowCondition(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.Time":                                                     schema_pkg_apis_meta_v1_Time(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.Timestamp":                                                schema_pkg_apis_meta_v1_Timestamp(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.TypeMeta":                                                 schema_pkg_apis_meta_v1_TypeMeta(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.UpdateOptions":                                            schema_pkg_apis_meta_v1_UpdateOptions(ref),
-		"k8s.io/apimachinery/pkg/apis/meta/v1.WatchEvent":                                               schema_pkg_apis_meta_v1_WatchEvent(ref),
-		"k8s.io/apimachinery/pkg/runtime.RawExtension":                                                  schema_k8sio_apimachinery_pkg_runtime_RawExtension(ref),
-		"k8s.io/apimachinery/pkg/runtime.TypeMeta":                                                      schema_k8sio_apimachinery_pkg_runtime_TypeMeta(ref),
-		"k8s.io/apimachinery/pkg/runtime.Unknown":                                                       schema_k8sio_apimachinery_pkg_runtime_Unknown(ref),
-		"k8s.io/apimachinery/pkg/util/intstr.IntOrString":                                               schema_apimachinery_pkg_util_intstr_IntOrString(ref),
-		"k8s.io/apimachinery/pkg/version.Info":                                                          schema_k8sio_apimachinery_pkg_version_Info(ref),
+		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.APIResourceConfig":                          schema_pkg_apis_proxy_v1alpha1_APIResourceConfig(ref),
+		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.APIResourceRule":                            schema_pkg_apis_proxy_v1alpha1_APIResourceRule(ref),
+		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.ClientConfig":                               schema_pkg_apis_proxy_v1alpha1_ClientConfig(ref),
+		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.DispatchPolicy":                             schema_pkg_apis_proxy_v1alpha1_DispatchPolicy(ref),
+		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.DispatchPolicyRule":                         schema_pkg_apis_proxy_v1alpha1_DispatchPolicyRule(ref),
+		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.ExemptFlowControlSchema":                    schema_pkg_apis_proxy_v1alpha1_ExemptFlowControlSchema(ref),
+		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.FlowControl":                                schema_pkg_apis_proxy_v1alpha1_FlowControl(ref),
+		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.FlowControlSchema":                          schema_pkg_apis_proxy_v1alpha1_FlowControlSchema(ref),
+		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.FlowControlSchemaConfiguration":             schema_pkg_apis_proxy_v1alpha1_FlowControlSchemaConfiguration(ref),
+		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.HealthCheckPolicy":                          schema_pkg_apis_proxy_v1alpha1_HealthCheckPolicy(ref),
+		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.HiddenResourceConfig":                       schema_pkg_apis_proxy_v1alpha1_HiddenResourceConfig(ref),
+		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.ImpersonationPolicy":                        schema_pkg_apis_proxy_v1alpha1_ImpersonationPolicy(ref),
+		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.LoggingConfig":                              schema_pkg_apis_proxy_v1alpha1_LoggingConfig(ref),
+		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.MaxRequestsInflightByVerbFlowControlSchema": schema_pkg_apis_proxy_v1alpha1_MaxRequestsInflightByVerbFlowControlSchema(ref),
+		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.MaxRequestsInflightFlowControlSchema":       schema_pkg_apis_proxy_v1alpha1_MaxRequestsInflightFlowControlSchema(ref),
+		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.NamespaceFlowControlSchema":                 schema_pkg_apis_proxy_v1alpha1_NamespaceFlowControlSchema(ref),
+		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.OutlierDetectionPolicy":                     schema_pkg_apis_proxy_v1alpha1_OutlierDetectionPolicy(ref),
+		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.QueuedFlowControlSchema":                    schema_pkg_apis_proxy_v1alpha1_QueuedFlowControlSchema(ref),
+		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.RejectionResponse":                          schema_pkg_apis_proxy_v1alpha1_RejectionResponse(ref),
+		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.RetryPolicy":                                schema_pkg_apis_proxy_v1alpha1_RetryPolicy(ref),
+		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.SecretReferecence":                          schema_pkg_apis_proxy_v1alpha1_SecretReferecence(ref),
+		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.SecureServing":                              schema_pkg_apis_proxy_v1alpha1_SecureServing(ref),
+		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.ServiceAccountRef":                          schema_pkg_apis_proxy_v1alpha1_ServiceAccountRef(ref),
+		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.ShadowConfig":                               schema_pkg_apis_proxy_v1alpha1_ShadowConfig(ref),
+		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.StubConfig":                                 schema_pkg_apis_proxy_v1alpha1_StubConfig(ref),
+		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.TokenBucketFlowControlSchema":               schema_pkg_apis_proxy_v1alpha1_TokenBucketFlowControlSchema(ref),
+		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.UpstreamCluster":                            schema_pkg_apis_proxy_v1alpha1_UpstreamCluster(ref),
+		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.UpstreamClusterList":                        schema_pkg_apis_proxy_v1alpha1_UpstreamClusterList(ref),
+		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.UpstreamClusterServer":                      schema_pkg_apis_proxy_v1alpha1_UpstreamClusterServer(ref),
+		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.UpstreamClusterSpec":                        schema_pkg_apis_proxy_v1alpha1_UpstreamClusterSpec(ref),
+		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.UpstreamClusterStatus":                      schema_pkg_apis_proxy_v1alpha1_UpstreamClusterStatus(ref),
+		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.UserAgentFlowControlSchema":                 schema_pkg_apis_proxy_v1alpha1_UserAgentFlowControlSchema(ref),
+		"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.matcher":                                    schema_pkg_apis_proxy_v1alpha1_matcher(ref),
+		"k8s.io/apimachinery/pkg/api/resource.Quantity":                                                       schema_apimachinery_pkg_api_resource_Quantity(ref),
+		"k8s.io/apimachinery/pkg/api/resource.int64Amount":                                                    schema_apimachinery_pkg_api_resource_int64Amount(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.APIGroup":                                                       schema_pkg_apis_meta_v1_APIGroup(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.APIGroupList":                                                   schema_pkg_apis_meta_v1_APIGroupList(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.APIResource":                                                    schema_pkg_apis_meta_v1_APIResource(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.APIResourceList":                                                schema_pkg_apis_meta_v1_APIResourceList(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.APIVersions":                                                    schema_pkg_apis_meta_v1_APIVersions(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.CreateOptions":                                                  schema_pkg_apis_meta_v1_CreateOptions(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.DeleteOptions":                                                  schema_pkg_apis_meta_v1_DeleteOptions(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.Duration":                                                       schema_pkg_apis_meta_v1_Duration(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.ExportOptions":                                                  schema_pkg_apis_meta_v1_ExportOptions(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.FieldsV1":                                                       schema_pkg_apis_meta_v1_FieldsV1(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.GetOptions":                                                     schema_pkg_apis_meta_v1_GetOptions(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupKind":                                                      schema_pkg_apis_meta_v1_GroupKind(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupResource":                                                  schema_pkg_apis_meta_v1_GroupResource(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupVersion":                                                   schema_pkg_apis_meta_v1_GroupVersion(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupVersionForDiscovery":                                       schema_pkg_apis_meta_v1_GroupVersionForDiscovery(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupVersionKind":                                               schema_pkg_apis_meta_v1_GroupVersionKind(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.GroupVersionResource":                                           schema_pkg_apis_meta_v1_GroupVersionResource(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.InternalEvent":                                                  schema_pkg_apis_meta_v1_InternalEvent(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector":                                                  schema_pkg_apis_meta_v1_LabelSelector(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelectorRequirement":                                       schema_pkg_apis_meta_v1_LabelSelectorRequirement(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.List":                                                           schema_pkg_apis_meta_v1_List(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta":                                                       schema_pkg_apis_meta_v1_ListMeta(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.ListOptions":                                                    schema_pkg_apis_meta_v1_ListOptions(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.ManagedFieldsEntry":                                             schema_pkg_apis_meta_v1_ManagedFieldsEntry(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime":                                                      schema_pkg_apis_meta_v1_MicroTime(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta":                                                     schema_pkg_apis_meta_v1_ObjectMeta(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.OwnerReference":                                                 schema_pkg_apis_meta_v1_OwnerReference(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.PartialObjectMetadata":                                          schema_pkg_apis_meta_v1_PartialObjectMetadata(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.PartialObjectMetadataList":                                      schema_pkg_apis_meta_v1_PartialObjectMetadataList(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.Patch":                                                          schema_pkg_apis_meta_v1_Patch(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.PatchOptions":                                                   schema_pkg_apis_meta_v1_PatchOptions(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.Preconditions":                                                  schema_pkg_apis_meta_v1_Preconditions(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.RootPaths":                                                      schema_pkg_apis_meta_v1_RootPaths(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.ServerAddressByClientCIDR":                                      schema_pkg_apis_meta_v1_ServerAddressByClientCIDR(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.Status":                                                         schema_pkg_apis_meta_v1_Status(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.StatusCause":                                                    schema_pkg_apis_meta_v1_StatusCause(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.StatusDetails":                                                  schema_pkg_apis_meta_v1_StatusDetails(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.Table":                                                          schema_pkg_apis_meta_v1_Table(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.TableColumnDefinition":                                          schema_pkg_apis_meta_v1_TableColumnDefinition(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.TableOptions":                                                   schema_pkg_apis_meta_v1_TableOptions(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.TableRow":                                                       schema_pkg_apis_meta_v1_TableRow(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.TableRowCondition":                                              schema_pkg_apis_meta_v1_TableRowCondition(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.Time":                                                           schema_pkg_apis_meta_v1_Time(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.Timestamp":                                                      schema_pkg_apis_meta_v1_Timestamp(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.TypeMeta":                                                       schema_pkg_apis_meta_v1_TypeMeta(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.UpdateOptions":                                                  schema_pkg_apis_meta_v1_UpdateOptions(ref),
+		"k8s.io/apimachinery/pkg/apis/meta/v1.WatchEvent":                                                     schema_pkg_apis_meta_v1_WatchEvent(ref),
+		"k8s.io/apimachinery/pkg/runtime.RawExtension":                                                        schema_k8sio_apimachinery_pkg_runtime_RawExtension(ref),
+		"k8s.io/apimachinery/pkg/runtime.TypeMeta":                                                            schema_k8sio_apimachinery_pkg_runtime_TypeMeta(ref),
+		"k8s.io/apimachinery/pkg/runtime.Unknown":                                                             schema_k8sio_apimachinery_pkg_runtime_Unknown(ref),
+		"k8s.io/apimachinery/pkg/util/intstr.IntOrString":                                                     schema_apimachinery_pkg_util_intstr_IntOrString(ref),
+		"k8s.io/apimachinery/pkg/version.Info":                                                                schema_k8sio_apimachinery_pkg_version_Info(ref),
+	}
+}
+
+func schema_pkg_apis_proxy_v1alpha1_APIResourceConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "APIResourceConfig describes API resources which are served by the upstream cluster",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"rules": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Rules is an allowlist of API resources served by the upstream cluster. Resource requests matching none of rules are rejected with 404 by the gateway instead of being proxied. Non-resource requests, e.g. discovery and /healthz, are always proxied.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.APIResourceRule"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"rules"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.APIResourceRule"},
+	}
+}
+
+func schema_pkg_apis_proxy_v1alpha1_APIResourceRule(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "APIResourceRule matches resources of API group versions",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"apiGroups": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIGroups is a list of API groups, \"\" is the core group. - \"*\" represents all APIGroups. - use '-' prefix to invert apiGroups matching, e.g. \"-apps\" means match all apiGroups except \"apps\"",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"apiVersions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersions is a list of API versions in APIGroups. - \"*\" represents all APIVersions. - use '-' prefix to invert apiVersions matching, e.g. \"-v1beta1\" means match all apiVersions except \"v1beta1\"",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"resources": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Resources is a list of resources in APIGroups and APIVersions. - \"*\" represents all Resources. - use \"{resource}/{subresource}\" to match one resource's subresource - use \"*/{subresource}\" to match all resources' subresource, but \"{resource}/*\" is not allowed. - use '-' prefix to invert resources matching, e.g. \"-deployments\" means match all resources except \"deployments\".",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"apiGroups", "apiVersions", "resources"},
+			},
+		},
 	}
 }
 
@@ -171,6 +270,41 @@ func schema_pkg_apis_proxy_v1alpha1_ClientConfig(ref common.ReferenceCallback) c
 							Format:      "int32",
 						},
 					},
+					"tlsSessionCacheSize": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TLSSessionCacheSize is the capacity of the TLS client session cache of each upstream endpoint, sessions are resumed to reduce handshake overhead. Zero means session resumption is disabled.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"tlsRenegotiation": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TLSRenegotiation is the renegotiation policy of TLS connections to upstream endpoints, valid values are Never, OnceAsClient and FreelyAsClient. Defaults to Never.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"h2c": {
+						SchemaProps: spec.SchemaProps{
+							Description: "H2C proxies requests to plaintext (http) upstream endpoints with HTTP/2 prior knowledge instead of HTTP/1.1. Upgrade requests, e.g. exec and port-forward, are still proxied with HTTP/1.1. It is only allowed with http scheme.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"disableKeepAlives": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DisableKeepAlives closes the upstream connection after each request so that every request is sent on a new connection. It is a workaround for upstreams or load balancers which handle keep-alive connections poorly, it should be used with care because of the cost of dialing and TLS handshakes. It is not allowed with h2c. Defaults to false.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"sourceAddress": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SourceAddress is the local IP address which connections to upstream endpoints are dialed from, e.g. on multi-homed hosts where upstream firewalls only allow a given source IP. Connections are dialed from the default interface if it is empty.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
@@ -231,6 +365,13 @@ func schema_pkg_apis_proxy_v1alpha1_DispatchPolicy(ref common.ReferenceCallback)
 							Format:      "",
 						},
 					},
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name is an optional name of this policy, it must be unique in a cluster. It is used as the policy label of proxy request metrics. If not set, the policy index in the list is used instead, e.g. \"#0\".",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
@@ -369,8 +510,17 @@ func schema_pkg_apis_proxy_v1alpha1_ExemptFlowControlSchema(ref common.Reference
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Represents no limit flow control.",
+				Description: "Represents no limit flow control. Exempt requests are still counted, and they can be capped by an optional high safety ceiling to prevent truly unbounded load.",
 				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"maxRequestsInflight": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaxRequestsInflight is an optional safety ceiling of concurrent requests in flight, it is supposed to be much higher than normal limits. Defaults to 0, which means no limit.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
 			},
 		},
 	}
@@ -394,11 +544,43 @@ func schema_pkg_apis_proxy_v1alpha1_FlowControl(ref common.ReferenceCallback) co
 							},
 						},
 					},
+					"namespaceSchemas": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NamespaceSchemas overrides the flow control schema of the matched dispatch policy for requests to the given namespaces, e.g. protecting control plane traffic in kube-system from being throttled with workloads. Only the first matched one takes effect.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.NamespaceFlowControlSchema"),
+									},
+								},
+							},
+						},
+					},
+					"rejectionResponse": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RejectionResponse customizes responses to requests rejected by flow control schemas of this cluster.",
+							Ref:         ref("github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.RejectionResponse"),
+						},
+					},
+					"userAgentSchemas": {
+						SchemaProps: spec.SchemaProps{
+							Description: "UserAgentSchemas overrides the flow control schema of the matched dispatch policy for requests from clients with the given User-Agent, e.g. prioritizing kube-controller-manager and kube-scheduler over kubectl under load. They take precedence over NamespaceSchemas. Only the first matched one takes effect. User-Agent is set by clients and is not authenticated, so it should be used to prioritize trusted traffic only.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.UserAgentFlowControlSchema"),
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.FlowControlSchema"},
+			"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.FlowControlSchema", "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.NamespaceFlowControlSchema", "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.RejectionResponse", "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.UserAgentFlowControlSchema"},
 	}
 }
 
@@ -433,11 +615,36 @@ func schema_pkg_apis_proxy_v1alpha1_FlowControlSchema(ref common.ReferenceCallba
 							Ref:         ref("github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.TokenBucketFlowControlSchema"),
 						},
 					},
+					"maxRequestsInflightByVerb": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaxRequestsInflightByVerb represents separate maximum concurrent numbers of mutating and read-only requests in flight at a given time.",
+							Ref:         ref("github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.MaxRequestsInflightByVerbFlowControlSchema"),
+						},
+					},
+					"queued": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Queued represents a maximum concurrent number of requests in flight, requests over it wait in queues instead of being rejected immediately.",
+							Ref:         ref("github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.QueuedFlowControlSchema"),
+						},
+					},
+					"rejectionStatusCode": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RejectionStatusCode is the http status code responded to requests rejected by this schema, only 429 and 503 are allowed. Defaults to 429. Retry-After header is always set no matter which code is used.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"rejectionResponse": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RejectionResponse customizes responses to requests rejected by this schema, it takes precedence over spec.flowControl.rejectionResponse.",
+							Ref:         ref("github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.RejectionResponse"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.ExemptFlowControlSchema", "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.MaxRequestsInflightFlowControlSchema", "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.TokenBucketFlowControlSchema"},
+			"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.ExemptFlowControlSchema", "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.MaxRequestsInflightByVerbFlowControlSchema", "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.MaxRequestsInflightFlowControlSchema", "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.QueuedFlowControlSchema", "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.RejectionResponse", "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.TokenBucketFlowControlSchema"},
 	}
 }
 
@@ -466,105 +673,94 @@ func schema_pkg_apis_proxy_v1alpha1_FlowControlSchemaConfiguration(ref common.Re
 							Ref:         ref("github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.TokenBucketFlowControlSchema"),
 						},
 					},
-				},
-			},
-		},
-		Dependencies: []string{
-			"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.ExemptFlowControlSchema", "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.MaxRequestsInflightFlowControlSchema", "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.TokenBucketFlowControlSchema"},
-	}
-}
-
-func schema_pkg_apis_proxy_v1alpha1_LoggingConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
-				Properties: map[string]spec.Schema{
-					"mode": {
+					"maxRequestsInflightByVerb": {
 						SchemaProps: spec.SchemaProps{
-							Description: "upstream cluster level log mode - if set to off, all access logs of requests to this cluster will be disabled. - if set to on, access logs of requests to this cluster will be enabled. But it\n  can be override by dispatchPolicy.LogMode\n- if unset, the logging is controlled by dispatchPolicy.LogMode",
-							Type:        []string{"string"},
-							Format:      "",
+							Description: "MaxRequestsInflightByVerb represents separate maximum concurrent numbers of mutating and read-only requests in flight at a given time.",
+							Ref:         ref("github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.MaxRequestsInflightByVerbFlowControlSchema"),
+						},
+					},
+					"queued": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Queued represents a maximum concurrent number of requests in flight, requests over it wait in queues instead of being rejected immediately.",
+							Ref:         ref("github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.QueuedFlowControlSchema"),
 						},
 					},
 				},
 			},
 		},
+		Dependencies: []string{
+			"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.ExemptFlowControlSchema", "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.MaxRequestsInflightByVerbFlowControlSchema", "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.MaxRequestsInflightFlowControlSchema", "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.QueuedFlowControlSchema", "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.TokenBucketFlowControlSchema"},
 	}
 }
 
-func schema_pkg_apis_proxy_v1alpha1_MaxRequestsInflightFlowControlSchema(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_proxy_v1alpha1_HealthCheckPolicy(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Represents a maximum concurrent number of requests in flight at a given time.",
+				Description: "HealthCheckPolicy describes how results of health checks decide endpoint health",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"max": {
+					"successRateWindow": {
 						SchemaProps: spec.SchemaProps{
-							Description: "maximum concurrent number of requests",
+							Description: "SuccessRateWindow is the number of recent health checks of an endpoint whose success rate decides its health, it tolerates occasional failures of upstreams with noisy readyz. It is at most 100, zero means only the last health check decides.",
 							Type:        []string{"integer"},
 							Format:      "int32",
 						},
 					},
-				},
-			},
-		},
-	}
-}
-
-func schema_pkg_apis_proxy_v1alpha1_SecretReferecence(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
-				Properties: map[string]spec.Schema{
-					"namespace": {
+					"minSuccessPercent": {
 						SchemaProps: spec.SchemaProps{
-							Description: "`namespace` is the namespace of the secret. Required",
+							Description: "MinSuccessPercent is the minimum percentage of succeeded health checks in the window for an endpoint to be healthy, from 1 to 100. Before the window is full, the rate is calculated with the health checks so far.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"intervalMilliseconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "IntervalMilliseconds is the interval between health checks of an endpoint. A longer interval reduces load on busy upstreams, and a shorter one finds failures sooner. It is at least 100 if it is set, defaults to 5000.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"timeoutMilliseconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TimeoutMilliseconds is the timeout of a health check request, it is at least 100 if it is set, defaults to 5000.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"path": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Path is the path requested by health checks, e.g. /readyz, /livez, or a custom probe path of the load balancer in front of upstream servers. It must start with \"/\". Defaults to /healthz, or /readyz if verbose readyz is enabled for the gateway.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"name": {
+					"fallbackToHealthz": {
 						SchemaProps: spec.SchemaProps{
-							Description: "`name` is the name of the secret. Required",
-							Type:        []string{"string"},
+							Description: "FallbackToHealthz requests /healthz instead if /readyz is not found, e.g. on apiservers older than v1.16. It only applies to health checks requesting /readyz.",
+							Type:        []string{"boolean"},
 							Format:      "",
 						},
 					},
-				},
-				Required: []string{"namespace", "name"},
-			},
-		},
-	}
-}
-
-func schema_pkg_apis_proxy_v1alpha1_SecureServing(ref common.ReferenceCallback) common.OpenAPIDefinition {
-	return common.OpenAPIDefinition{
-		Schema: spec.Schema{
-			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
-				Properties: map[string]spec.Schema{
-					"keyData": {
+					"unhealthyThreshold": {
 						SchemaProps: spec.SchemaProps{
-							Description: "KeyData contains PEM-encoded data from a client key file for TLS. The serialized form of data is a base64 encoded string",
-							Type:        []string{"string"},
-							Format:      "byte",
+							Description: "UnhealthyThreshold is the number of consecutive failed health checks for a healthy endpoint to become unhealthy, it keeps the endpoint from flapping on brief network blips. Defaults to 3. It can not be set together with successRateWindow.",
+							Type:        []string{"integer"},
+							Format:      "int32",
 						},
 					},
-					"certData": {
+					"healthyThreshold": {
 						SchemaProps: spec.SchemaProps{
-							Description: "CertData contains PEM-encoded data from a client cert file for TLS. The serialized form of data is a base64 encoded string",
-							Type:        []string{"string"},
-							Format:      "byte",
+							Description: "HealthyThreshold is the number of consecutive succeeded health checks for an unhealthy endpoint to become healthy. Defaults to 1. It can not be set together with successRateWindow.",
+							Type:        []string{"integer"},
+							Format:      "int32",
 						},
 					},
-					"clientCAData": {
+					"maxBackoffMilliseconds": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ClientCAData contains PEM-encoded data from a ca file for TLS. The serialized form of data is a base64 encoded string",
-							Type:        []string{"string"},
-							Format:      "byte",
+							Description: "MaxBackoffMilliseconds caps the interval between health checks of an unhealthy endpoint, which doubles after each health check until the endpoint recovers. A longer cap reduces load on endpoints down for long, and a shorter one finds their recovery sooner. It is at least 100 if it is set, defaults to 30000, and the interval is never shortened by it.",
+							Type:        []string{"integer"},
+							Format:      "int32",
 						},
 					},
 				},
@@ -573,35 +769,605 @@ func schema_pkg_apis_proxy_v1alpha1_SecureServing(ref common.ReferenceCallback)
 	}
 }
 
-func schema_pkg_apis_proxy_v1alpha1_ServiceAccountRef(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_proxy_v1alpha1_HiddenResourceConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Type: []string{"object"},
+				Description: "HiddenResourceConfig describes requests which are responded as if the resource does not exist",
+				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"name": {
-						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
-						},
-					},
-					"namespace": {
+					"rules": {
 						SchemaProps: spec.SchemaProps{
-							Type:   []string{"string"},
-							Format: "",
+							Description: "Rules match verbs, resources and users of requests the same as rules of dispatch policies, requests matching any of them are rejected with 404 before being dispatched.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.DispatchPolicyRule"),
+									},
+								},
+							},
 						},
 					},
 				},
+				Required: []string{"rules"},
 			},
 		},
+		Dependencies: []string{
+			"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.DispatchPolicyRule"},
 	}
 }
 
-func schema_pkg_apis_proxy_v1alpha1_TokenBucketFlowControlSchema(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_proxy_v1alpha1_ImpersonationPolicy(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "Represents token bucket rate limit approach.",
+				Description: "ImpersonationPolicy restricts targets of impersonation requests. A user or group is forbidden if it is denied, or allowed ones are set and it is not one of them. Requests impersonating a forbidden target are rejected with 403 even if the impersonator is authorized to impersonate it.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"allowedUsers": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AllowedUsers are the only users which can be impersonated if it is set. Service accounts are matched by their user names, e.g. system:serviceaccount:default:builder.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"deniedUsers": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DeniedUsers are users which can not be impersonated.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"allowedGroups": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AllowedGroups are the only groups which can be impersonated if it is set.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"deniedGroups": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DeniedGroups are groups which can not be impersonated.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_proxy_v1alpha1_LoggingConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"mode": {
+						SchemaProps: spec.SchemaProps{
+							Description: "upstream cluster level log mode - if set to off, all access logs of requests to this cluster will be disabled. - if set to on, access logs of requests to this cluster will be enabled. But it\n  can be override by dispatchPolicy.LogMode\n- if unset, the logging is controlled by dispatchPolicy.LogMode",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"userGroups": {
+						SchemaProps: spec.SchemaProps{
+							Description: "UserGroups decides whether groups of the authenticated user are logged in access logs of requests to this cluster, e.g. for compliance reporting on group-based access. - if set to on, user groups are logged even if the userGroup field is not selected by gateway flags. - if set to off, user groups are not logged. - if unset, it follows the access log fields selected by gateway flags.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_proxy_v1alpha1_MaxRequestsInflightByVerbFlowControlSchema(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Represents separate maximum concurrent numbers of mutating and read-only requests in flight, like --max-mutating-requests-inflight and --max-requests-inflight of kube-apiserver, so that the write path of upstreams is protected independently. Requests with get, list and watch verbs are read-only, the others are mutating.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"maxMutating": {
+						SchemaProps: spec.SchemaProps{
+							Description: "maximum concurrent number of mutating requests",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"maxReadOnly": {
+						SchemaProps: spec.SchemaProps{
+							Description: "maximum concurrent number of read-only requests",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_proxy_v1alpha1_MaxRequestsInflightFlowControlSchema(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Represents a maximum concurrent number of requests in flight at a given time.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"max": {
+						SchemaProps: spec.SchemaProps{
+							Description: "maximum concurrent number of requests",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_proxy_v1alpha1_NamespaceFlowControlSchema(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "NamespaceFlowControlSchema binds a flow control schema to a set of namespaces",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"namespaces": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Namespaces of requests this schema applies to",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"flowControlSchemaName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FlowControlSchemaName indicates to which flow control schema in spec.FlowControl will take effect on requests to these namespaces. If not set, these requests are exempt from flow control.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"namespaces"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_proxy_v1alpha1_OutlierDetectionPolicy(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "OutlierDetectionPolicy describes how endpoints are ejected by passive health signals, i.e. results of proxied requests. An ejected endpoint receives no requests until the ejection ends, then it is half-open and receives a small share of requests to test recovery. It is reinstated after a request succeeds, or ejected again after a request fails. Endpoints are never all ejected, requests are dispatched to ejected endpoints if no other is ready.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"consecutiveErrors": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ConsecutiveErrors is the number of consecutive 5xx responses or connection errors for an endpoint to be ejected. Defaults to 5.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"intervalSeconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "IntervalSeconds is the window in which consecutive errors are counted, errors spread over a longer time do not eject the endpoint. Defaults to 10.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"ejectionSeconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "EjectionSeconds is how long an endpoint is ejected before it is half-open. Defaults to 30.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"halfOpenPercent": {
+						SchemaProps: spec.SchemaProps{
+							Description: "HalfOpenPercent is the percentage of requests a half-open endpoint is considered for, from 1 to 100. Defaults to 10.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_proxy_v1alpha1_QueuedFlowControlSchema(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Represents a maximum concurrent number of requests in flight, requests over it wait in queues and are admitted as capacity frees, like API priority and fairness of kube-apiserver. Requests are shuffle sharded into queues by user, each user is dealt handSize of the queues and enqueues into the shortest one, and queues take turns to be admitted, so that a user flooding requests does not starve the others. Requests are rejected if the queue is full, or they have waited for maxWaitSeconds.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"concurrency": {
+						SchemaProps: spec.SchemaProps{
+							Description: "maximum concurrent number of requests",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"queueLength": {
+						SchemaProps: spec.SchemaProps{
+							Description: "QueueLength is the maximum number of requests waiting in each queue. Defaults to 50.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"queues": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Queues is the number of queues. Defaults to 64.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"handSize": {
+						SchemaProps: spec.SchemaProps{
+							Description: "HandSize is the number of queues dealt to each user, it is at most queues. Defaults to 8, or queues if there are fewer.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"maxWaitSeconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaxWaitSeconds is how long a request waits in queue at most before it is rejected. Defaults to 15.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_proxy_v1alpha1_RejectionResponse(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "RejectionResponse customizes responses to requests rejected by flow control",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Message replaces the message of Status in response body, it can give clients actionable guidance, e.g. a link to request quota increases.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"headers": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Headers are additional headers set in responses. Retry-After, Content-Type and Content-Length can not be set.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_proxy_v1alpha1_RetryPolicy(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "RetryPolicy describes transient upstream errors which should be retried on another ready endpoint instead of being responded to clients, e.g. \"etcdserver: leader changed\" or \"apiserver is shutting down\". Only idempotent requests (get, list and watch) and requests of opted-in verbs whose body can be replayed are retried.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"statusReasons": {
+						SchemaProps: spec.SchemaProps{
+							Description: "StatusReasons are reasons of upstream Status responses to retry, e.g. ServiceUnavailable.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"statusMessages": {
+						SchemaProps: spec.SchemaProps{
+							Description: "StatusMessages are substrings of upstream Status messages to retry, e.g. \"leader changed\".",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"tooManyRequests": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TooManyRequests decides how 429 responses of upstreams are handled, e.g. requests throttled by API priority and fairness of an upstream. Valid values are: - PassThrough: responds them to clients; - Retry: retries them on another ready endpoint, they are responded to clients if\n  there are no more endpoints to retry;\n- Backoff: delays them by TooManyRequestsBackoffSeconds before responding to clients. Defaults to PassThrough.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"tooManyRequestsBackoffSeconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TooManyRequestsBackoffSeconds is how long 429 responses of upstreams are delayed with the Backoff policy. Defaults to 1 if it is 0.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"statusCodes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "StatusCodes are status codes of upstream error responses to retry no matter what their bodies are, e.g. 503 responded by a load balancer in front of apiservers.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"integer"},
+										Format: "int32",
+									},
+								},
+							},
+						},
+					},
+					"connectionErrors": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ConnectionErrors retries requests which fail with connection-level errors, e.g. the connection to the endpoint is refused or reset.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"verbs": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Verbs are verbs of requests to retry in addition to get, list and watch, e.g. create. Requests which are not idempotent may be applied twice if the upstream fails after handling them.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"maxRetries": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaxRetries is the maximum number of times a request is retried on other endpoints. Defaults to 2 if it is 0.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_proxy_v1alpha1_SecretReferecence(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"namespace": {
+						SchemaProps: spec.SchemaProps{
+							Description: "`namespace` is the namespace of the secret. Required",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "`name` is the name of the secret. Required",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"namespace", "name"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_proxy_v1alpha1_SecureServing(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"keyData": {
+						SchemaProps: spec.SchemaProps{
+							Description: "KeyData contains PEM-encoded data from a client key file for TLS. The serialized form of data is a base64 encoded string",
+							Type:        []string{"string"},
+							Format:      "byte",
+						},
+					},
+					"certData": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CertData contains PEM-encoded data from a client cert file for TLS. The serialized form of data is a base64 encoded string",
+							Type:        []string{"string"},
+							Format:      "byte",
+						},
+					},
+					"clientCAData": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ClientCAData contains PEM-encoded data from a ca file for TLS. The serialized form of data is a base64 encoded string",
+							Type:        []string{"string"},
+							Format:      "byte",
+						},
+					},
+					"clientAuth": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ClientAuth decides how client certificates are handled in TLS handshake when ClientCAData is set. - Request: client certificates are requested but not required, connections without a valid\n  certificate can still be authenticated by other methods such as tokens.\n- RequireAndVerify: connections without a certificate signed by ClientCAData are rejected\n  in TLS handshake.\nDefaults to Request if unset.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiAudiences": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIAudiences are the audiences required of bearer tokens authenticated for this cluster, a token is accepted only if the upstream token review returns at least one of them, so that a token issued for another cluster is not accepted. They override the global API audiences of the gateway for this cluster. Upstream apiservers must support audiences in token reviews.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_proxy_v1alpha1_ServiceAccountRef(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"namespace": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_proxy_v1alpha1_ShadowConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ShadowConfig describes how requests are mirrored to a shadow cluster",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"cluster": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Cluster is the name of the shadow UpstreamCluster, it must be proxied by the same gateway.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"percentage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Percentage of get and list requests mirrored to the shadow cluster, from 0 to 100. Defaults to 100 if it is 0.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"compareResponses": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CompareResponses compares status code and body hash of shadow responses with the primary ones, and logs the differences.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"cluster"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_proxy_v1alpha1_StubConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "StubConfig describes responses of a stub cluster",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Message is the message of 503 responses, defaults to \"cluster(<name>) is being provisioned\".",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"retryAfterSeconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RetryAfterSeconds is the value of Retry-After header in 503 responses.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_proxy_v1alpha1_TokenBucketFlowControlSchema(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Represents token bucket rate limit approach.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"qps": {
@@ -618,6 +1384,20 @@ func schema_pkg_apis_proxy_v1alpha1_TokenBucketFlowControlSchema(ref common.Refe
 							Format:      "int32",
 						},
 					},
+					"slowStartSeconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SlowStartSeconds is the duration for the rate limiter to ramp up linearly to the new qps and burst after they are raised, so that the upstream is not hit by a burst right after a limit is raised. Lowering them takes effect immediately. Defaults to 0, which means raising takes effect immediately too.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"keyBy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "KeyBy makes the rate limiter keep a token bucket of the qps and burst for each key of requests instead of sharing one bucket among all of them, so that a single client can not exhaust the limit of others. Buckets of keys idle for a while are dropped. Defaults to empty, which means all requests share one bucket.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
@@ -735,6 +1515,13 @@ func schema_pkg_apis_proxy_v1alpha1_UpstreamClusterServer(ref common.ReferenceCa
 							Format:      "",
 						},
 					},
+					"weight": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Weight is the relative weight of the server in dispatch policies with the WeightedRandom and LatencyWeightedRandom strategies. Defaults to 1. A server with weight 0 is drained, no requests are dispatched to it by any strategy, but it is still health checked.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
 				},
 			},
 		},
@@ -798,11 +1585,73 @@ func schema_pkg_apis_proxy_v1alpha1_UpstreamClusterSpec(ref common.ReferenceCall
 							Ref:         ref("github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.LoggingConfig"),
 						},
 					},
+					"minHealthyEndpoints": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MinHealthyEndpoints is the minimum number of healthy endpoints required to serve this cluster. If fewer endpoints are healthy, requests to this cluster will be rejected with 503 instead of overwhelming the surviving endpoints. Defaults to 0, which means no limit.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"disabled": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Disabled marks the whole cluster down manually, all requests to this cluster will be rejected with 503. Health checking of endpoints keeps running, so endpoints status still shows whether the cluster recovers.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"shadow": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Shadow mirrors get and list requests to another cluster, e.g. a migration target, so that it can be verified with real traffic. Shadow responses are discarded and never affect clients.",
+							Ref:         ref("github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.ShadowConfig"),
+						},
+					},
+					"retry": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Retry describes which upstream errors are retried on another endpoint.",
+							Ref:         ref("github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.RetryPolicy"),
+						},
+					},
+					"stub": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Stub makes the cluster a placeholder which is not backed by upstream servers yet, e.g. a hostname registered during onboarding before the real cluster is provisioned. All requests to a stub cluster are responded with 503 without being proxied, servers and dispatchPolicies are optional.",
+							Ref:         ref("github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.StubConfig"),
+						},
+					},
+					"apiResources": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIResources overrides API resources which are proxied to the cluster, e.g. an upstream which does not serve some API versions. By default, all resource requests are proxied.",
+							Ref:         ref("github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.APIResourceConfig"),
+						},
+					},
+					"healthCheck": {
+						SchemaProps: spec.SchemaProps{
+							Description: "HealthCheck describes how results of health checks decide whether an endpoint is healthy. By default, an endpoint becomes unhealthy after 3 consecutive failed health checks, and becomes healthy after a succeeded one.",
+							Ref:         ref("github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.HealthCheckPolicy"),
+						},
+					},
+					"hiddenResources": {
+						SchemaProps: spec.SchemaProps{
+							Description: "HiddenResources hides resources from some users more strictly than upstream RBAC, matched requests are responded with 404 by the gateway as if the resource does not exist, instead of a 403 from upstream which tells that the resource exists.",
+							Ref:         ref("github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.HiddenResourceConfig"),
+						},
+					},
+					"outlierDetection": {
+						SchemaProps: spec.SchemaProps{
+							Description: "OutlierDetection temporarily ejects endpoints which keep failing proxied requests from dispatching, independent of health checks.",
+							Ref:         ref("github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.OutlierDetectionPolicy"),
+						},
+					},
+					"impersonation": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Impersonation restricts users and groups which can be impersonated in this cluster, on top of the impersonate permission of the impersonator, e.g. to forbid impersonating system:masters.",
+							Ref:         ref("github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.ImpersonationPolicy"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.ClientConfig", "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.DispatchPolicy", "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.FlowControl", "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.LoggingConfig", "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.SecureServing", "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.UpstreamClusterServer"},
+			"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.APIResourceConfig", "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.ClientConfig", "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.DispatchPolicy", "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.FlowControl", "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.HealthCheckPolicy", "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.HiddenResourceConfig", "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.ImpersonationPolicy", "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.LoggingConfig", "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.OutlierDetectionPolicy", "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.RetryPolicy", "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.SecureServing", "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.ShadowConfig", "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.StubConfig", "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1.UpstreamClusterServer"},
 	}
 }
 
@@ -817,6 +1666,41 @@ func schema_pkg_apis_proxy_v1alpha1_UpstreamClusterStatus(ref common.ReferenceCa
 	}
 }
 
+func schema_pkg_apis_proxy_v1alpha1_UserAgentFlowControlSchema(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "UserAgentFlowControlSchema binds a flow control schema to clients with matched User-Agent",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"userAgentPrefixes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "UserAgentPrefixes are prefixes of User-Agent header of requests this schema applies to, e.g. \"kube-controller-manager/\".",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"flowControlSchemaName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FlowControlSchemaName indicates to which flow control schema in spec.FlowControl will take effect on requests from these clients. It is required because User-Agent can not be trusted to exempt requests from flow control.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"userAgentPrefixes", "flowControlSchemaName"},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_proxy_v1alpha1_matcher(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
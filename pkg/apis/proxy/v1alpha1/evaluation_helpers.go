@@ -37,6 +37,10 @@ func APIGroupMatches(apiGroups []string, request string) bool {
 	return simpleMatches(apiGroups, []string{request})
 }
 
+func APIVersionMatches(apiVersions []string, request string) bool {
+	return simpleMatches(apiVersions, []string{request})
+}
+
 func ResourceMatches(resources []string, combinedRequestedResource, requestedSubresource string) bool {
 	return simpleMatches(resources, []string{combinedRequestedResource}, func(m matcher) bool {
 		// We can also match a */subresource.
@@ -24,6 +24,7 @@ import (
 	io "io"
 
 	proto "github.com/gogo/protobuf/proto"
+	github_com_gogo_protobuf_sortkeys "github.com/gogo/protobuf/sortkeys"
 
 	math "math"
 	math_bits "math/bits"
@@ -42,10 +43,66 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 
+func (m *APIResourceConfig) Reset()      { *m = APIResourceConfig{} }
+func (*APIResourceConfig) ProtoMessage() {}
+func (*APIResourceConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d037ab291b4fff89, []int{0}
+}
+func (m *APIResourceConfig) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *APIResourceConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *APIResourceConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_APIResourceConfig.Merge(m, src)
+}
+func (m *APIResourceConfig) XXX_Size() int {
+	return m.Size()
+}
+func (m *APIResourceConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_APIResourceConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_APIResourceConfig proto.InternalMessageInfo
+
+func (m *APIResourceRule) Reset()      { *m = APIResourceRule{} }
+func (*APIResourceRule) ProtoMessage() {}
+func (*APIResourceRule) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d037ab291b4fff89, []int{1}
+}
+func (m *APIResourceRule) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *APIResourceRule) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *APIResourceRule) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_APIResourceRule.Merge(m, src)
+}
+func (m *APIResourceRule) XXX_Size() int {
+	return m.Size()
+}
+func (m *APIResourceRule) XXX_DiscardUnknown() {
+	xxx_messageInfo_APIResourceRule.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_APIResourceRule proto.InternalMessageInfo
+
 func (m *ClientConfig) Reset()      { *m = ClientConfig{} }
 func (*ClientConfig) ProtoMessage() {}
 func (*ClientConfig) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d037ab291b4fff89, []int{0}
+	return fileDescriptor_d037ab291b4fff89, []int{2}
 }
 func (m *ClientConfig) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -73,7 +130,7 @@ var xxx_messageInfo_ClientConfig proto.InternalMessageInfo
 func (m *DispatchPolicy) Reset()      { *m = DispatchPolicy{} }
 func (*DispatchPolicy) ProtoMessage() {}
 func (*DispatchPolicy) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d037ab291b4fff89, []int{1}
+	return fileDescriptor_d037ab291b4fff89, []int{3}
 }
 func (m *DispatchPolicy) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -101,7 +158,7 @@ var xxx_messageInfo_DispatchPolicy proto.InternalMessageInfo
 func (m *DispatchPolicyRule) Reset()      { *m = DispatchPolicyRule{} }
 func (*DispatchPolicyRule) ProtoMessage() {}
 func (*DispatchPolicyRule) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d037ab291b4fff89, []int{2}
+	return fileDescriptor_d037ab291b4fff89, []int{4}
 }
 func (m *DispatchPolicyRule) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -129,7 +186,7 @@ var xxx_messageInfo_DispatchPolicyRule proto.InternalMessageInfo
 func (m *ExemptFlowControlSchema) Reset()      { *m = ExemptFlowControlSchema{} }
 func (*ExemptFlowControlSchema) ProtoMessage() {}
 func (*ExemptFlowControlSchema) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d037ab291b4fff89, []int{3}
+	return fileDescriptor_d037ab291b4fff89, []int{5}
 }
 func (m *ExemptFlowControlSchema) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -157,7 +214,7 @@ var xxx_messageInfo_ExemptFlowControlSchema proto.InternalMessageInfo
 func (m *FlowControl) Reset()      { *m = FlowControl{} }
 func (*FlowControl) ProtoMessage() {}
 func (*FlowControl) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d037ab291b4fff89, []int{4}
+	return fileDescriptor_d037ab291b4fff89, []int{6}
 }
 func (m *FlowControl) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -185,7 +242,7 @@ var xxx_messageInfo_FlowControl proto.InternalMessageInfo
 func (m *FlowControlSchema) Reset()      { *m = FlowControlSchema{} }
 func (*FlowControlSchema) ProtoMessage() {}
 func (*FlowControlSchema) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d037ab291b4fff89, []int{5}
+	return fileDescriptor_d037ab291b4fff89, []int{7}
 }
 func (m *FlowControlSchema) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -213,7 +270,7 @@ var xxx_messageInfo_FlowControlSchema proto.InternalMessageInfo
 func (m *FlowControlSchemaConfiguration) Reset()      { *m = FlowControlSchemaConfiguration{} }
 func (*FlowControlSchemaConfiguration) ProtoMessage() {}
 func (*FlowControlSchemaConfiguration) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d037ab291b4fff89, []int{6}
+	return fileDescriptor_d037ab291b4fff89, []int{8}
 }
 func (m *FlowControlSchemaConfiguration) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -238,10 +295,94 @@ func (m *FlowControlSchemaConfiguration) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_FlowControlSchemaConfiguration proto.InternalMessageInfo
 
+func (m *HealthCheckPolicy) Reset()      { *m = HealthCheckPolicy{} }
+func (*HealthCheckPolicy) ProtoMessage() {}
+func (*HealthCheckPolicy) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d037ab291b4fff89, []int{9}
+}
+func (m *HealthCheckPolicy) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *HealthCheckPolicy) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *HealthCheckPolicy) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HealthCheckPolicy.Merge(m, src)
+}
+func (m *HealthCheckPolicy) XXX_Size() int {
+	return m.Size()
+}
+func (m *HealthCheckPolicy) XXX_DiscardUnknown() {
+	xxx_messageInfo_HealthCheckPolicy.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HealthCheckPolicy proto.InternalMessageInfo
+
+func (m *HiddenResourceConfig) Reset()      { *m = HiddenResourceConfig{} }
+func (*HiddenResourceConfig) ProtoMessage() {}
+func (*HiddenResourceConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d037ab291b4fff89, []int{10}
+}
+func (m *HiddenResourceConfig) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *HiddenResourceConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *HiddenResourceConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HiddenResourceConfig.Merge(m, src)
+}
+func (m *HiddenResourceConfig) XXX_Size() int {
+	return m.Size()
+}
+func (m *HiddenResourceConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_HiddenResourceConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HiddenResourceConfig proto.InternalMessageInfo
+
+func (m *ImpersonationPolicy) Reset()      { *m = ImpersonationPolicy{} }
+func (*ImpersonationPolicy) ProtoMessage() {}
+func (*ImpersonationPolicy) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d037ab291b4fff89, []int{11}
+}
+func (m *ImpersonationPolicy) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ImpersonationPolicy) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *ImpersonationPolicy) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImpersonationPolicy.Merge(m, src)
+}
+func (m *ImpersonationPolicy) XXX_Size() int {
+	return m.Size()
+}
+func (m *ImpersonationPolicy) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImpersonationPolicy.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImpersonationPolicy proto.InternalMessageInfo
+
 func (m *LoggingConfig) Reset()      { *m = LoggingConfig{} }
 func (*LoggingConfig) ProtoMessage() {}
 func (*LoggingConfig) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d037ab291b4fff89, []int{7}
+	return fileDescriptor_d037ab291b4fff89, []int{12}
 }
 func (m *LoggingConfig) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -266,10 +407,40 @@ func (m *LoggingConfig) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_LoggingConfig proto.InternalMessageInfo
 
+func (m *MaxRequestsInflightByVerbFlowControlSchema) Reset() {
+	*m = MaxRequestsInflightByVerbFlowControlSchema{}
+}
+func (*MaxRequestsInflightByVerbFlowControlSchema) ProtoMessage() {}
+func (*MaxRequestsInflightByVerbFlowControlSchema) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d037ab291b4fff89, []int{13}
+}
+func (m *MaxRequestsInflightByVerbFlowControlSchema) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *MaxRequestsInflightByVerbFlowControlSchema) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *MaxRequestsInflightByVerbFlowControlSchema) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MaxRequestsInflightByVerbFlowControlSchema.Merge(m, src)
+}
+func (m *MaxRequestsInflightByVerbFlowControlSchema) XXX_Size() int {
+	return m.Size()
+}
+func (m *MaxRequestsInflightByVerbFlowControlSchema) XXX_DiscardUnknown() {
+	xxx_messageInfo_MaxRequestsInflightByVerbFlowControlSchema.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MaxRequestsInflightByVerbFlowControlSchema proto.InternalMessageInfo
+
 func (m *MaxRequestsInflightFlowControlSchema) Reset()      { *m = MaxRequestsInflightFlowControlSchema{} }
 func (*MaxRequestsInflightFlowControlSchema) ProtoMessage() {}
 func (*MaxRequestsInflightFlowControlSchema) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d037ab291b4fff89, []int{8}
+	return fileDescriptor_d037ab291b4fff89, []int{14}
 }
 func (m *MaxRequestsInflightFlowControlSchema) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -294,10 +465,150 @@ func (m *MaxRequestsInflightFlowControlSchema) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_MaxRequestsInflightFlowControlSchema proto.InternalMessageInfo
 
+func (m *NamespaceFlowControlSchema) Reset()      { *m = NamespaceFlowControlSchema{} }
+func (*NamespaceFlowControlSchema) ProtoMessage() {}
+func (*NamespaceFlowControlSchema) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d037ab291b4fff89, []int{15}
+}
+func (m *NamespaceFlowControlSchema) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *NamespaceFlowControlSchema) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *NamespaceFlowControlSchema) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NamespaceFlowControlSchema.Merge(m, src)
+}
+func (m *NamespaceFlowControlSchema) XXX_Size() int {
+	return m.Size()
+}
+func (m *NamespaceFlowControlSchema) XXX_DiscardUnknown() {
+	xxx_messageInfo_NamespaceFlowControlSchema.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NamespaceFlowControlSchema proto.InternalMessageInfo
+
+func (m *OutlierDetectionPolicy) Reset()      { *m = OutlierDetectionPolicy{} }
+func (*OutlierDetectionPolicy) ProtoMessage() {}
+func (*OutlierDetectionPolicy) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d037ab291b4fff89, []int{16}
+}
+func (m *OutlierDetectionPolicy) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *OutlierDetectionPolicy) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *OutlierDetectionPolicy) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OutlierDetectionPolicy.Merge(m, src)
+}
+func (m *OutlierDetectionPolicy) XXX_Size() int {
+	return m.Size()
+}
+func (m *OutlierDetectionPolicy) XXX_DiscardUnknown() {
+	xxx_messageInfo_OutlierDetectionPolicy.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OutlierDetectionPolicy proto.InternalMessageInfo
+
+func (m *QueuedFlowControlSchema) Reset()      { *m = QueuedFlowControlSchema{} }
+func (*QueuedFlowControlSchema) ProtoMessage() {}
+func (*QueuedFlowControlSchema) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d037ab291b4fff89, []int{17}
+}
+func (m *QueuedFlowControlSchema) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueuedFlowControlSchema) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *QueuedFlowControlSchema) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueuedFlowControlSchema.Merge(m, src)
+}
+func (m *QueuedFlowControlSchema) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueuedFlowControlSchema) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueuedFlowControlSchema.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueuedFlowControlSchema proto.InternalMessageInfo
+
+func (m *RejectionResponse) Reset()      { *m = RejectionResponse{} }
+func (*RejectionResponse) ProtoMessage() {}
+func (*RejectionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d037ab291b4fff89, []int{18}
+}
+func (m *RejectionResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *RejectionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *RejectionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RejectionResponse.Merge(m, src)
+}
+func (m *RejectionResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *RejectionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RejectionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RejectionResponse proto.InternalMessageInfo
+
+func (m *RetryPolicy) Reset()      { *m = RetryPolicy{} }
+func (*RetryPolicy) ProtoMessage() {}
+func (*RetryPolicy) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d037ab291b4fff89, []int{19}
+}
+func (m *RetryPolicy) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *RetryPolicy) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *RetryPolicy) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RetryPolicy.Merge(m, src)
+}
+func (m *RetryPolicy) XXX_Size() int {
+	return m.Size()
+}
+func (m *RetryPolicy) XXX_DiscardUnknown() {
+	xxx_messageInfo_RetryPolicy.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RetryPolicy proto.InternalMessageInfo
+
 func (m *SecretReferecence) Reset()      { *m = SecretReferecence{} }
 func (*SecretReferecence) ProtoMessage() {}
 func (*SecretReferecence) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d037ab291b4fff89, []int{9}
+	return fileDescriptor_d037ab291b4fff89, []int{20}
 }
 func (m *SecretReferecence) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -325,7 +636,7 @@ var xxx_messageInfo_SecretReferecence proto.InternalMessageInfo
 func (m *SecureServing) Reset()      { *m = SecureServing{} }
 func (*SecureServing) ProtoMessage() {}
 func (*SecureServing) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d037ab291b4fff89, []int{10}
+	return fileDescriptor_d037ab291b4fff89, []int{21}
 }
 func (m *SecureServing) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -353,7 +664,7 @@ var xxx_messageInfo_SecureServing proto.InternalMessageInfo
 func (m *ServiceAccountRef) Reset()      { *m = ServiceAccountRef{} }
 func (*ServiceAccountRef) ProtoMessage() {}
 func (*ServiceAccountRef) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d037ab291b4fff89, []int{11}
+	return fileDescriptor_d037ab291b4fff89, []int{22}
 }
 func (m *ServiceAccountRef) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -378,10 +689,66 @@ func (m *ServiceAccountRef) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_ServiceAccountRef proto.InternalMessageInfo
 
+func (m *ShadowConfig) Reset()      { *m = ShadowConfig{} }
+func (*ShadowConfig) ProtoMessage() {}
+func (*ShadowConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d037ab291b4fff89, []int{23}
+}
+func (m *ShadowConfig) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ShadowConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *ShadowConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ShadowConfig.Merge(m, src)
+}
+func (m *ShadowConfig) XXX_Size() int {
+	return m.Size()
+}
+func (m *ShadowConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_ShadowConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ShadowConfig proto.InternalMessageInfo
+
+func (m *StubConfig) Reset()      { *m = StubConfig{} }
+func (*StubConfig) ProtoMessage() {}
+func (*StubConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d037ab291b4fff89, []int{24}
+}
+func (m *StubConfig) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *StubConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *StubConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StubConfig.Merge(m, src)
+}
+func (m *StubConfig) XXX_Size() int {
+	return m.Size()
+}
+func (m *StubConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_StubConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StubConfig proto.InternalMessageInfo
+
 func (m *TokenBucketFlowControlSchema) Reset()      { *m = TokenBucketFlowControlSchema{} }
 func (*TokenBucketFlowControlSchema) ProtoMessage() {}
 func (*TokenBucketFlowControlSchema) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d037ab291b4fff89, []int{12}
+	return fileDescriptor_d037ab291b4fff89, []int{25}
 }
 func (m *TokenBucketFlowControlSchema) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -409,7 +776,7 @@ var xxx_messageInfo_TokenBucketFlowControlSchema proto.InternalMessageInfo
 func (m *UpstreamCluster) Reset()      { *m = UpstreamCluster{} }
 func (*UpstreamCluster) ProtoMessage() {}
 func (*UpstreamCluster) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d037ab291b4fff89, []int{13}
+	return fileDescriptor_d037ab291b4fff89, []int{26}
 }
 func (m *UpstreamCluster) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -437,7 +804,7 @@ var xxx_messageInfo_UpstreamCluster proto.InternalMessageInfo
 func (m *UpstreamClusterList) Reset()      { *m = UpstreamClusterList{} }
 func (*UpstreamClusterList) ProtoMessage() {}
 func (*UpstreamClusterList) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d037ab291b4fff89, []int{14}
+	return fileDescriptor_d037ab291b4fff89, []int{27}
 }
 func (m *UpstreamClusterList) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -465,7 +832,7 @@ var xxx_messageInfo_UpstreamClusterList proto.InternalMessageInfo
 func (m *UpstreamClusterServer) Reset()      { *m = UpstreamClusterServer{} }
 func (*UpstreamClusterServer) ProtoMessage() {}
 func (*UpstreamClusterServer) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d037ab291b4fff89, []int{15}
+	return fileDescriptor_d037ab291b4fff89, []int{28}
 }
 func (m *UpstreamClusterServer) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -493,7 +860,7 @@ var xxx_messageInfo_UpstreamClusterServer proto.InternalMessageInfo
 func (m *UpstreamClusterSpec) Reset()      { *m = UpstreamClusterSpec{} }
 func (*UpstreamClusterSpec) ProtoMessage() {}
 func (*UpstreamClusterSpec) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d037ab291b4fff89, []int{16}
+	return fileDescriptor_d037ab291b4fff89, []int{29}
 }
 func (m *UpstreamClusterSpec) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -521,7 +888,7 @@ var xxx_messageInfo_UpstreamClusterSpec proto.InternalMessageInfo
 func (m *UpstreamClusterStatus) Reset()      { *m = UpstreamClusterStatus{} }
 func (*UpstreamClusterStatus) ProtoMessage() {}
 func (*UpstreamClusterStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d037ab291b4fff89, []int{17}
+	return fileDescriptor_d037ab291b4fff89, []int{30}
 }
 func (m *UpstreamClusterStatus) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -546,25 +913,68 @@ func (m *UpstreamClusterStatus) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_UpstreamClusterStatus proto.InternalMessageInfo
 
-func init() {
-	proto.RegisterType((*ClientConfig)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.ClientConfig")
-	proto.RegisterType((*DispatchPolicy)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.DispatchPolicy")
-	proto.RegisterType((*DispatchPolicyRule)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.DispatchPolicyRule")
-	proto.RegisterType((*ExemptFlowControlSchema)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.ExemptFlowControlSchema")
-	proto.RegisterType((*FlowControl)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.FlowControl")
-	proto.RegisterType((*FlowControlSchema)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.FlowControlSchema")
-	proto.RegisterType((*FlowControlSchemaConfiguration)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.FlowControlSchemaConfiguration")
-	proto.RegisterType((*LoggingConfig)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.LoggingConfig")
-	proto.RegisterType((*MaxRequestsInflightFlowControlSchema)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.MaxRequestsInflightFlowControlSchema")
-	proto.RegisterType((*SecretReferecence)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.SecretReferecence")
+func (m *UserAgentFlowControlSchema) Reset()      { *m = UserAgentFlowControlSchema{} }
+func (*UserAgentFlowControlSchema) ProtoMessage() {}
+func (*UserAgentFlowControlSchema) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d037ab291b4fff89, []int{31}
+}
+func (m *UserAgentFlowControlSchema) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *UserAgentFlowControlSchema) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	b = b[:cap(b)]
+	n, err := m.MarshalToSizedBuffer(b)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+func (m *UserAgentFlowControlSchema) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UserAgentFlowControlSchema.Merge(m, src)
+}
+func (m *UserAgentFlowControlSchema) XXX_Size() int {
+	return m.Size()
+}
+func (m *UserAgentFlowControlSchema) XXX_DiscardUnknown() {
+	xxx_messageInfo_UserAgentFlowControlSchema.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UserAgentFlowControlSchema proto.InternalMessageInfo
+
+func init() {
+	proto.RegisterType((*APIResourceConfig)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.APIResourceConfig")
+	proto.RegisterType((*APIResourceRule)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.APIResourceRule")
+	proto.RegisterType((*ClientConfig)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.ClientConfig")
+	proto.RegisterType((*DispatchPolicy)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.DispatchPolicy")
+	proto.RegisterType((*DispatchPolicyRule)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.DispatchPolicyRule")
+	proto.RegisterType((*ExemptFlowControlSchema)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.ExemptFlowControlSchema")
+	proto.RegisterType((*FlowControl)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.FlowControl")
+	proto.RegisterType((*FlowControlSchema)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.FlowControlSchema")
+	proto.RegisterType((*FlowControlSchemaConfiguration)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.FlowControlSchemaConfiguration")
+	proto.RegisterType((*HealthCheckPolicy)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.HealthCheckPolicy")
+	proto.RegisterType((*HiddenResourceConfig)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.HiddenResourceConfig")
+	proto.RegisterType((*ImpersonationPolicy)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.ImpersonationPolicy")
+	proto.RegisterType((*LoggingConfig)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.LoggingConfig")
+	proto.RegisterType((*MaxRequestsInflightByVerbFlowControlSchema)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.MaxRequestsInflightByVerbFlowControlSchema")
+	proto.RegisterType((*MaxRequestsInflightFlowControlSchema)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.MaxRequestsInflightFlowControlSchema")
+	proto.RegisterType((*NamespaceFlowControlSchema)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.NamespaceFlowControlSchema")
+	proto.RegisterType((*OutlierDetectionPolicy)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.OutlierDetectionPolicy")
+	proto.RegisterType((*QueuedFlowControlSchema)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.QueuedFlowControlSchema")
+	proto.RegisterType((*RejectionResponse)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.RejectionResponse")
+	proto.RegisterMapType((map[string]string)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.RejectionResponse.HeadersEntry")
+	proto.RegisterType((*RetryPolicy)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.RetryPolicy")
+	proto.RegisterType((*SecretReferecence)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.SecretReferecence")
 	proto.RegisterType((*SecureServing)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.SecureServing")
 	proto.RegisterType((*ServiceAccountRef)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.ServiceAccountRef")
+	proto.RegisterType((*ShadowConfig)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.ShadowConfig")
+	proto.RegisterType((*StubConfig)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.StubConfig")
 	proto.RegisterType((*TokenBucketFlowControlSchema)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.TokenBucketFlowControlSchema")
 	proto.RegisterType((*UpstreamCluster)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.UpstreamCluster")
 	proto.RegisterType((*UpstreamClusterList)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.UpstreamClusterList")
 	proto.RegisterType((*UpstreamClusterServer)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.UpstreamClusterServer")
 	proto.RegisterType((*UpstreamClusterSpec)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.UpstreamClusterSpec")
 	proto.RegisterType((*UpstreamClusterStatus)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.UpstreamClusterStatus")
+	proto.RegisterType((*UserAgentFlowControlSchema)(nil), "github.com.kubewharf.kubegateway.pkg.apis.proxy.v1alpha1.UserAgentFlowControlSchema")
 }
 
 func init() {
@@ -572,102 +982,294 @@ func init() {
 }
 
 var fileDescriptor_d037ab291b4fff89 = []byte{
-	// 1515 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x57, 0x4f, 0x6f, 0xdb, 0xc6,
-	0x12, 0x37, 0x25, 0xcb, 0x96, 0x96, 0xfe, 0xbb, 0x7e, 0x86, 0xf5, 0x8c, 0x44, 0x32, 0xf8, 0x5e,
-	0x0b, 0x03, 0x69, 0xa9, 0x5a, 0x08, 0xda, 0xa0, 0x68, 0x0f, 0xa6, 0xed, 0x24, 0x46, 0xec, 0xc4,
-	0x59, 0xc5, 0x41, 0x51, 0x14, 0x45, 0x29, 0x7a, 0x4d, 0xb3, 0x96, 0x48, 0x7a, 0x77, 0xe9, 0x3f,
-	0x45, 0x0f, 0x39, 0xe4, 0x52, 0xa0, 0x28, 0x7a, 0xea, 0xa9, 0xe8, 0xbd, 0xdf, 0xc4, 0xb7, 0xe6,
-	0x98, 0x43, 0x2b, 0x34, 0xcc, 0xa9, 0x5f, 0x21, 0xa7, 0x62, 0x97, 0x4b, 0x91, 0x14, 0xed, 0xd8,
-	0x70, 0x7c, 0x23, 0x67, 0x7e, 0x3b, 0x33, 0x3b, 0x3b, 0x33, 0xfb, 0x5b, 0x70, 0xdf, 0x76, 0xd8,
-	0x5e, 0xd0, 0xd6, 0x2d, 0xaf, 0xdb, 0xd8, 0x0f, 0xda, 0xf8, 0x68, 0xcf, 0x24, 0xbb, 0xe2, 0xcb,
-	0x36, 0x19, 0x3e, 0x32, 0x4f, 0x1a, 0xfe, 0xbe, 0xdd, 0x30, 0x7d, 0x87, 0x36, 0x7c, 0xe2, 0x1d,
-	0x9f, 0x34, 0x0e, 0x97, 0xcc, 0x8e, 0xbf, 0x67, 0x2e, 0x35, 0x6c, 0xec, 0x62, 0x62, 0x32, 0xbc,
-	0xa3, 0xfb, 0xc4, 0x63, 0x1e, 0xbc, 0x93, 0x58, 0xd2, 0xfb, 0x96, 0xf4, 0x94, 0x25, 0xdd, 0xdf,
-	0xb7, 0x75, 0x6e, 0x49, 0x17, 0x96, 0xf4, 0xd8, 0xd2, 0xfc, 0x87, 0xa9, 0x18, 0x6c, 0xcf, 0xf6,
-	0x1a, 0xc2, 0x60, 0x3b, 0xd8, 0x15, 0x7f, 0xe2, 0x47, 0x7c, 0x45, 0x8e, 0xe6, 0x6f, 0xef, 0xdf,
-	0xa1, 0xba, 0xe3, 0xf1, 0xa0, 0xba, 0xa6, 0xb5, 0xe7, 0xb8, 0x98, 0xa4, 0xa2, 0xec, 0x62, 0x66,
-	0x36, 0x0e, 0x73, 0xe1, 0xcd, 0x37, 0xce, 0x5b, 0x45, 0x02, 0x97, 0x39, 0x5d, 0x9c, 0x5b, 0xf0,
-	0xf1, 0x45, 0x0b, 0xa8, 0xb5, 0x87, 0xbb, 0xe6, 0xe0, 0x3a, 0xed, 0xcf, 0x02, 0x18, 0x5b, 0xe9,
-	0x38, 0xd8, 0x65, 0x2b, 0x9e, 0xbb, 0xeb, 0xd8, 0xf0, 0x03, 0x50, 0x76, 0x5c, 0x8a, 0xad, 0x80,
-	0xe0, 0xaa, 0xb2, 0xa0, 0x2c, 0x96, 0x8d, 0xa9, 0xd3, 0x5e, 0x7d, 0x28, 0xec, 0xd5, 0xcb, 0xeb,
-	0x52, 0x8e, 0xfa, 0x08, 0xb8, 0x04, 0xd4, 0x36, 0x36, 0x09, 0x26, 0x4f, 0xbc, 0x7d, 0xec, 0x56,
-	0x0b, 0x0b, 0xca, 0xe2, 0x98, 0x31, 0x19, 0xf6, 0xea, 0xaa, 0x91, 0x88, 0x51, 0x1a, 0x03, 0xdf,
-	0x03, 0xa3, 0xfb, 0xf8, 0x64, 0xd5, 0x64, 0x66, 0xb5, 0x28, 0xe0, 0x6a, 0xd8, 0xab, 0x8f, 0x3e,
-	0x88, 0x44, 0x28, 0xd6, 0xc1, 0x45, 0x50, 0xb6, 0x30, 0x61, 0x02, 0x37, 0x2c, 0x70, 0x63, 0x3c,
-	0x86, 0x15, 0x29, 0x43, 0x7d, 0x2d, 0xd4, 0xc0, 0x88, 0x65, 0x0a, 0x5c, 0x49, 0xe0, 0x40, 0xd8,
-	0xab, 0x8f, 0xac, 0x2c, 0x0b, 0x94, 0xd4, 0xc0, 0x9b, 0xa0, 0x78, 0xe0, 0xd3, 0xea, 0xc8, 0x82,
-	0xb2, 0x58, 0x32, 0x54, 0xb9, 0xa1, 0xe2, 0xe3, 0xad, 0x16, 0xe2, 0x72, 0xf8, 0x3f, 0x50, 0x6a,
-	0x07, 0x84, 0xb2, 0xea, 0xa8, 0x00, 0x8c, 0x4b, 0x40, 0xc9, 0xe0, 0x42, 0x14, 0xe9, 0x60, 0x13,
-	0x80, 0x03, 0x9f, 0xae, 0x3a, 0x87, 0x0e, 0xf5, 0x48, 0xb5, 0x2c, 0x90, 0x50, 0x22, 0xc1, 0xe3,
-	0xad, 0x96, 0xd4, 0xa0, 0x14, 0x4a, 0x7b, 0x5e, 0x04, 0x13, 0xab, 0x0e, 0xf5, 0x4d, 0x66, 0xed,
-	0x6d, 0x79, 0x1d, 0xc7, 0x3a, 0x81, 0x77, 0x40, 0x99, 0x32, 0x7e, 0x04, 0xf6, 0x89, 0x48, 0x70,
-	0xc5, 0xb8, 0x11, 0x27, 0xb8, 0x25, 0xe5, 0x6f, 0x52, 0xdf, 0xa8, 0x8f, 0x86, 0x9f, 0x82, 0x89,
-	0xc0, 0xa7, 0x8c, 0x60, 0xb3, 0xdb, 0x0a, 0xda, 0x14, 0xb3, 0x6a, 0x61, 0xa1, 0xb8, 0x58, 0x31,
-	0x60, 0xd8, 0xab, 0x4f, 0x6c, 0x67, 0x34, 0x68, 0x00, 0x09, 0x0f, 0x40, 0x89, 0x04, 0x1d, 0x4c,
-	0xab, 0xc5, 0x85, 0xe2, 0xa2, 0xda, 0xdc, 0xd0, 0xaf, 0x5a, 0xff, 0x7a, 0x76, 0x3b, 0x28, 0xe8,
-	0xe0, 0x24, 0x5f, 0xfc, 0x8f, 0xa2, 0xc8, 0x13, 0x6c, 0x81, 0xd9, 0xdd, 0x8e, 0x77, 0xb4, 0xe2,
-	0xb9, 0x8c, 0x78, 0x9d, 0x96, 0xa8, 0xbf, 0x87, 0x66, 0x17, 0x8b, 0xe3, 0xac, 0x18, 0x37, 0xe5,
-	0xa2, 0xd9, 0xbb, 0x67, 0x81, 0xd0, 0xd9, 0x6b, 0xe1, 0x6d, 0x30, 0xda, 0xf1, 0xec, 0x4d, 0x6f,
-	0x07, 0x8b, 0xd3, 0xae, 0x18, 0xf3, 0xd2, 0xcc, 0xe8, 0x46, 0x24, 0x7e, 0x93, 0x7c, 0xa2, 0x18,
-	0xaa, 0xfd, 0x53, 0x04, 0x30, 0x1f, 0x37, 0xac, 0x83, 0xd2, 0x21, 0x26, 0x6d, 0x5a, 0x55, 0x44,
-	0x1e, 0x2b, 0x7c, 0x0b, 0x4f, 0xb9, 0x00, 0x45, 0x72, 0x78, 0x0b, 0x54, 0x4c, 0xdf, 0xb9, 0x47,
-	0xbc, 0xc0, 0xa7, 0x32, 0xd9, 0xe3, 0x61, 0xaf, 0x5e, 0x59, 0xde, 0x5a, 0x8f, 0x84, 0x28, 0xd1,
-	0x73, 0x30, 0xc1, 0xd4, 0x0b, 0x88, 0x25, 0xd3, 0x2c, 0xc1, 0x28, 0x16, 0xa2, 0x44, 0x0f, 0x3f,
-	0x01, 0xe3, 0xf1, 0x0f, 0xdf, 0x17, 0xad, 0x0e, 0x8b, 0x05, 0xd3, 0x61, 0xaf, 0x3e, 0x8e, 0xd2,
-	0x0a, 0x94, 0xc5, 0xf1, 0x98, 0x03, 0x8a, 0x09, 0xad, 0x96, 0x92, 0x98, 0xb7, 0xb9, 0x00, 0x45,
-	0x72, 0xf8, 0x93, 0x02, 0x26, 0x29, 0x26, 0x87, 0x8e, 0x85, 0x97, 0x2d, 0xcb, 0x0b, 0x5c, 0xc6,
-	0xeb, 0x9e, 0x1f, 0xfa, 0x83, 0xab, 0x1f, 0x7a, 0x2b, 0x63, 0x10, 0xe1, 0x5d, 0x63, 0x4e, 0xe6,
-	0x7d, 0x32, 0xab, 0xa2, 0x68, 0xd0, 0x39, 0xd4, 0x01, 0xe0, 0x91, 0xc9, 0x2c, 0x8e, 0x8a, 0xb0,
-	0x27, 0x78, 0xcf, 0x6c, 0xf7, 0xa5, 0x28, 0x85, 0x80, 0x9f, 0x83, 0x49, 0xd7, 0x73, 0xe3, 0x24,
-	0x6c, 0xa3, 0x0d, 0x5a, 0x2d, 0x8b, 0x45, 0x33, 0xdc, 0xdd, 0xc3, 0xac, 0x0a, 0x0d, 0x62, 0xb5,
-	0xff, 0x82, 0xb9, 0xb5, 0x63, 0xdc, 0xf5, 0x59, 0xae, 0xae, 0xb4, 0x5f, 0x15, 0xa0, 0xa6, 0xa4,
-	0xf0, 0x47, 0x05, 0xc0, 0x5c, 0x99, 0x45, 0xd5, 0xf0, 0x4e, 0xd9, 0xca, 0x79, 0x36, 0x26, 0xe3,
-	0x2a, 0x95, 0x3e, 0xd0, 0x19, 0x7e, 0xb5, 0x67, 0x05, 0x30, 0x9d, 0x5b, 0x0a, 0x17, 0xc0, 0xb0,
-	0xcb, 0xbb, 0x26, 0x9a, 0x15, 0x63, 0xd2, 0xd0, 0xb0, 0x68, 0x12, 0xa1, 0x81, 0xa7, 0x0a, 0xa8,
-	0xe5, 0xcc, 0x45, 0xe3, 0x3c, 0x20, 0x26, 0x73, 0xbc, 0x68, 0x30, 0xab, 0xcd, 0x2f, 0xae, 0x71,
-	0x4b, 0x19, 0xfb, 0xc6, 0xfb, 0x32, 0xac, 0xda, 0xdb, 0x71, 0xe8, 0x82, 0x38, 0xb5, 0x3f, 0x8a,
-	0xe0, 0x02, 0x13, 0x30, 0x00, 0x23, 0x58, 0x9c, 0xaf, 0xc8, 0x88, 0xda, 0x7c, 0x7c, 0xf5, 0x4d,
-	0x9d, 0x53, 0x27, 0xd1, 0x0d, 0x12, 0x29, 0x91, 0x74, 0x06, 0x7f, 0x57, 0xc0, 0x4c, 0xd7, 0x3c,
-	0x46, 0xf8, 0x20, 0xc0, 0x94, 0xd1, 0x75, 0x77, 0xb7, 0xe3, 0xd8, 0x7b, 0x4c, 0x66, 0xf6, 0xeb,
-	0xab, 0x07, 0xb1, 0x99, 0x37, 0x9a, 0x8f, 0x68, 0x2e, 0xec, 0xd5, 0x67, 0xce, 0x40, 0xa2, 0xb3,
-	0x62, 0x82, 0x3f, 0x28, 0x40, 0x65, 0xfc, 0xb2, 0x35, 0x02, 0x6b, 0x1f, 0x33, 0x71, 0xcf, 0xaa,
-	0xcd, 0xa7, 0x57, 0x8f, 0xf1, 0x49, 0x62, 0xec, 0x8c, 0xda, 0xe6, 0xd7, 0x7d, 0x0a, 0x81, 0xd2,
-	0xbe, 0xb5, 0xcf, 0xc0, 0xf8, 0x86, 0x67, 0xdb, 0x8e, 0x6b, 0x4b, 0x82, 0x71, 0x0b, 0x0c, 0x77,
-	0xf9, 0xf8, 0x8e, 0xea, 0x39, 0x1e, 0x23, 0xc3, 0x83, 0xb3, 0x5b, 0x80, 0xb4, 0x35, 0xf0, 0xff,
-	0xcb, 0xe4, 0x87, 0xdf, 0xef, 0x5d, 0xf3, 0x58, 0xd8, 0x4c, 0xdd, 0xef, 0x7c, 0x29, 0x97, 0x6b,
-	0xbb, 0x60, 0xba, 0x85, 0x2d, 0x82, 0xf9, 0xe4, 0xc2, 0x04, 0x5b, 0xd8, 0xb5, 0x30, 0x6c, 0x80,
-	0x0a, 0x6f, 0x1f, 0xea, 0x9b, 0x56, 0x1c, 0xcd, 0xb4, 0x5c, 0x59, 0x79, 0x18, 0x2b, 0x50, 0x82,
-	0xe9, 0x77, 0x62, 0xe1, 0xbc, 0x4e, 0xd4, 0x7e, 0x51, 0xc0, 0x78, 0x4b, 0x30, 0x23, 0x31, 0x15,
-	0x5d, 0x3b, 0xcd, 0x76, 0x94, 0x4b, 0xb2, 0x9d, 0xc2, 0x5b, 0xd9, 0xce, 0x6d, 0x30, 0x66, 0x45,
-	0x7c, 0x6d, 0x39, 0xc5, 0xa1, 0xa6, 0xc2, 0x5e, 0x3d, 0xe6, 0x71, 0x11, 0xf3, 0xc9, 0xa0, 0xa2,
-	0x04, 0x0c, 0x8c, 0xf0, 0x4b, 0x4c, 0x96, 0x4c, 0x8a, 0x0a, 0x17, 0xa7, 0x48, 0x6b, 0x83, 0x1b,
-	0x6f, 0xab, 0x95, 0x98, 0x87, 0x29, 0x17, 0xf1, 0xb0, 0xc2, 0xf9, 0x3c, 0x4c, 0xfb, 0xab, 0x00,
-	0x26, 0x63, 0xb6, 0xb3, 0xd2, 0x09, 0x28, 0xc3, 0x04, 0x7e, 0x03, 0xca, 0x9c, 0x4a, 0xef, 0xc4,
-	0x79, 0x56, 0x9b, 0x1f, 0xe9, 0x11, 0x23, 0xd6, 0xd3, 0x8c, 0x38, 0x29, 0x70, 0x8e, 0xd6, 0x0f,
-	0x97, 0xf4, 0x47, 0xed, 0x6f, 0xb1, 0xc5, 0x36, 0x31, 0x33, 0x13, 0x2e, 0x97, 0xc8, 0x50, 0xdf,
-	0x2a, 0xf4, 0xc0, 0x30, 0xf5, 0xb1, 0x25, 0xfb, 0x7d, 0xf3, 0xea, 0xbd, 0x34, 0x10, 0x7a, 0xcb,
-	0xc7, 0x56, 0x92, 0x7b, 0xfe, 0x87, 0x84, 0x23, 0x78, 0x04, 0x46, 0x28, 0x33, 0x59, 0x40, 0x65,
-	0xfb, 0x3e, 0xba, 0x3e, 0x97, 0xc2, 0xac, 0x31, 0x21, 0x9d, 0x8e, 0x44, 0xff, 0x48, 0xba, 0xd3,
-	0x5e, 0x2b, 0x60, 0x66, 0x60, 0xc5, 0x86, 0x43, 0x19, 0xfc, 0x2a, 0x97, 0x63, 0xfd, 0x72, 0x39,
-	0xe6, 0xab, 0x45, 0x86, 0xfb, 0x2f, 0x89, 0x58, 0x92, 0xca, 0xaf, 0x0b, 0x4a, 0x0e, 0xc3, 0xdd,
-	0x88, 0x66, 0xa9, 0xcd, 0xf5, 0x6b, 0xdb, 0x6d, 0x52, 0x45, 0xeb, 0xdc, 0x3e, 0x8a, 0xdc, 0x68,
-	0x1e, 0x98, 0x1d, 0x4c, 0x0b, 0x26, 0x87, 0x98, 0xf0, 0x07, 0x10, 0x76, 0x77, 0x7c, 0xcf, 0x71,
-	0x99, 0xec, 0x8c, 0x7e, 0xd8, 0x6b, 0x52, 0x8e, 0xfa, 0x08, 0xde, 0xb8, 0x3b, 0x0e, 0x35, 0xdb,
-	0x1d, 0xbc, 0x23, 0x4a, 0xa3, 0x1c, 0x35, 0xee, 0xaa, 0x94, 0xa1, 0xbe, 0x56, 0xfb, 0x6d, 0x24,
-	0x97, 0x56, 0x7e, 0xda, 0xf0, 0x3b, 0x30, 0x4a, 0x85, 0xe7, 0x98, 0x78, 0x5c, 0xe3, 0x41, 0x0b,
-	0xbb, 0x29, 0xf2, 0x11, 0xf9, 0x41, 0xb1, 0x43, 0xf8, 0x4c, 0xe9, 0x4f, 0x13, 0x31, 0x9c, 0x65,
-	0x75, 0xdf, 0xbd, 0x7a, 0x04, 0xe9, 0xb7, 0xa4, 0xf1, 0x1f, 0xe9, 0x38, 0xf3, 0xc2, 0x44, 0x19,
-	0x8f, 0xf0, 0xb9, 0x02, 0xc6, 0x69, 0x7a, 0x64, 0xca, 0x72, 0xbf, 0xf7, 0x2e, 0x64, 0x35, 0x65,
-	0xce, 0x98, 0x95, 0x41, 0x64, 0x07, 0x33, 0xca, 0x3a, 0x85, 0xdf, 0x03, 0x35, 0x45, 0x4d, 0xc4,
-	0x13, 0x45, 0x6d, 0xae, 0x5d, 0x0b, 0x5f, 0x32, 0x66, 0x64, 0x04, 0x69, 0xee, 0x89, 0xd2, 0xee,
-	0x38, 0x67, 0x9f, 0xda, 0x49, 0xbf, 0x4f, 0x1c, 0x1c, 0x11, 0x7c, 0xb5, 0x79, 0xff, 0xba, 0x5e,
-	0x6a, 0x46, 0x55, 0x86, 0x31, 0xb5, 0x3a, 0xe0, 0x09, 0xe5, 0x7c, 0x43, 0x22, 0x9e, 0x59, 0xfc,
-	0xd6, 0x16, 0x6f, 0xe6, 0x77, 0x3a, 0x8e, 0xcc, 0xf5, 0x9f, 0x14, 0xa3, 0x14, 0xa3, 0xd8, 0x91,
-	0x36, 0x97, 0xef, 0xc8, 0x68, 0x50, 0xe9, 0xa7, 0xaf, 0x6a, 0x43, 0x2f, 0x5e, 0xd5, 0x86, 0x5e,
-	0xbe, 0xaa, 0x0d, 0x3d, 0x0b, 0x6b, 0xca, 0x69, 0x58, 0x53, 0x5e, 0x84, 0x35, 0xe5, 0x65, 0x58,
-	0x53, 0xfe, 0x0e, 0x6b, 0xca, 0xcf, 0xaf, 0x6b, 0x43, 0x5f, 0x96, 0x63, 0x87, 0xff, 0x06, 0x00,
-	0x00, 0xff, 0xff, 0x18, 0xb6, 0xc3, 0xdc, 0x26, 0x12, 0x00, 0x00,
+	// 3185 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x5a, 0xcb, 0x6f, 0x1b, 0xd7,
+	0xd5, 0xf7, 0x50, 0xa2, 0x1e, 0x97, 0x7a, 0x5e, 0xbf, 0x26, 0xfe, 0x62, 0xc9, 0x98, 0x2f, 0x09,
+	0x9c, 0x2f, 0x5f, 0xa9, 0x58, 0x70, 0x1a, 0x23, 0x45, 0x0a, 0x88, 0x94, 0x63, 0xa9, 0x16, 0x6d,
+	0xf9, 0x50, 0x72, 0x8a, 0xa0, 0x08, 0x3a, 0x1a, 0x5e, 0x91, 0x13, 0x91, 0x33, 0xf4, 0xdc, 0x3b,
+	0x92, 0x98, 0x16, 0x85, 0x83, 0x14, 0x05, 0x52, 0xb4, 0x45, 0x90, 0xae, 0x9a, 0xa2, 0x05, 0xba,
+	0x29, 0xd0, 0xae, 0x0a, 0x14, 0xe8, 0xbe, 0x3b, 0x2f, 0xb3, 0xcc, 0xa6, 0x6a, 0xa3, 0xac, 0xb2,
+	0xe8, 0x3f, 0xe0, 0x55, 0x71, 0x1f, 0x33, 0x73, 0x67, 0x86, 0x94, 0x1d, 0xd1, 0x6d, 0x77, 0x9c,
+	0x73, 0x7e, 0xe7, 0x31, 0x67, 0xce, 0x3d, 0xf7, 0xdc, 0x73, 0x89, 0xd6, 0x9a, 0x2e, 0x6b, 0x85,
+	0x3b, 0x65, 0xc7, 0xef, 0x2c, 0xed, 0x85, 0x3b, 0xe4, 0xa0, 0x65, 0x07, 0xbb, 0xe2, 0x57, 0xd3,
+	0x66, 0xe4, 0xc0, 0xee, 0x2d, 0x75, 0xf7, 0x9a, 0x4b, 0x76, 0xd7, 0xa5, 0x4b, 0xdd, 0xc0, 0x3f,
+	0xec, 0x2d, 0xed, 0x5f, 0xb3, 0xdb, 0xdd, 0x96, 0x7d, 0x6d, 0xa9, 0x49, 0x3c, 0x12, 0xd8, 0x8c,
+	0x34, 0xca, 0xdd, 0xc0, 0x67, 0x3e, 0xbe, 0x91, 0x68, 0x2a, 0xc7, 0x9a, 0xca, 0x9a, 0xa6, 0x72,
+	0x77, 0xaf, 0x59, 0xe6, 0x9a, 0xca, 0x42, 0x53, 0x39, 0xd2, 0x74, 0xe9, 0x1b, 0x9a, 0x0f, 0x4d,
+	0xbf, 0xe9, 0x2f, 0x09, 0x85, 0x3b, 0xe1, 0xae, 0x78, 0x12, 0x0f, 0xe2, 0x97, 0x34, 0x74, 0xe9,
+	0xfa, 0xde, 0x0d, 0x5a, 0x76, 0x7d, 0xee, 0x54, 0xc7, 0x76, 0x5a, 0xae, 0x47, 0x02, 0xcd, 0xcb,
+	0x0e, 0x61, 0xf6, 0xd2, 0x7e, 0xce, 0xbd, 0x4b, 0x4b, 0x83, 0xa4, 0x82, 0xd0, 0x63, 0x6e, 0x87,
+	0xe4, 0x04, 0xbe, 0xf9, 0x24, 0x01, 0xea, 0xb4, 0x48, 0xc7, 0xce, 0xca, 0x59, 0x1f, 0x1a, 0x68,
+	0x7e, 0x65, 0x73, 0x1d, 0x08, 0xf5, 0xc3, 0xc0, 0x21, 0x55, 0xdf, 0xdb, 0x75, 0x9b, 0xd8, 0x43,
+	0xc5, 0x20, 0x6c, 0x13, 0x6a, 0x1a, 0x57, 0x46, 0xae, 0x96, 0x96, 0xd7, 0xcb, 0xa7, 0x8d, 0x56,
+	0x59, 0xd3, 0x0d, 0x61, 0x9b, 0x54, 0xa6, 0x1f, 0x1d, 0x2d, 0x9e, 0x39, 0x3e, 0x5a, 0x2c, 0xf2,
+	0x27, 0x0a, 0xd2, 0x8c, 0xf5, 0x5b, 0x03, 0xcd, 0x66, 0x90, 0xf8, 0x15, 0x34, 0x69, 0x77, 0xdd,
+	0x5b, 0x81, 0x1f, 0x76, 0xa5, 0x1f, 0x93, 0x95, 0xe9, 0xe3, 0xa3, 0xc5, 0xc9, 0x95, 0xcd, 0x75,
+	0x49, 0x84, 0x84, 0x8f, 0xaf, 0xa1, 0x92, 0xdd, 0x75, 0xef, 0x93, 0x80, 0xba, 0xbe, 0x47, 0xcd,
+	0x82, 0x80, 0xcf, 0x1e, 0x1f, 0x2d, 0x96, 0x56, 0x36, 0xd7, 0x23, 0x32, 0xe8, 0x18, 0xae, 0x3f,
+	0x50, 0xf6, 0xa8, 0x39, 0x92, 0xe8, 0x8f, 0x9c, 0xa0, 0x90, 0xf0, 0xad, 0x3f, 0x15, 0xd1, 0x54,
+	0xb5, 0xed, 0x12, 0x8f, 0xa9, 0x08, 0xfd, 0x3f, 0x9a, 0x70, 0x3d, 0x4a, 0x9c, 0x30, 0x20, 0xa6,
+	0x71, 0xc5, 0xb8, 0x3a, 0x51, 0x99, 0x53, 0x6f, 0x36, 0xb1, 0xae, 0xe8, 0x10, 0x23, 0xb8, 0x7b,
+	0x3b, 0xc4, 0x0e, 0x48, 0xb0, 0xe5, 0xef, 0x11, 0xcf, 0x2c, 0x5c, 0x31, 0xae, 0x4e, 0x49, 0xf7,
+	0x2a, 0x09, 0x19, 0x74, 0x0c, 0x7e, 0x11, 0x8d, 0xef, 0x91, 0xde, 0xaa, 0xcd, 0x6c, 0x73, 0x44,
+	0xc0, 0x4b, 0xc7, 0x47, 0x8b, 0xe3, 0xb7, 0x25, 0x09, 0x22, 0x1e, 0xbe, 0x8a, 0x26, 0x1c, 0x12,
+	0x30, 0x81, 0x1b, 0x15, 0xb8, 0x29, 0xee, 0x43, 0x55, 0xd1, 0x20, 0xe6, 0x62, 0x0b, 0x8d, 0x39,
+	0xb6, 0xc0, 0x15, 0x05, 0x0e, 0x1d, 0x1f, 0x2d, 0x8e, 0x55, 0x57, 0x04, 0x4a, 0x71, 0xf0, 0x65,
+	0x34, 0xf2, 0xa0, 0x4b, 0xcd, 0xb1, 0x2b, 0xc6, 0xd5, 0x62, 0xa5, 0xa4, 0x5e, 0x68, 0xe4, 0xde,
+	0x66, 0x1d, 0x38, 0x1d, 0xff, 0x2f, 0x2a, 0xee, 0x84, 0x01, 0x65, 0xe6, 0xb8, 0x00, 0xc4, 0xdf,
+	0xb2, 0xc2, 0x89, 0x20, 0x79, 0x78, 0x19, 0xa1, 0x07, 0x5d, 0xba, 0xea, 0xee, 0xbb, 0xd4, 0x0f,
+	0xcc, 0x09, 0x81, 0xc4, 0x0a, 0x89, 0xee, 0x6d, 0xd6, 0x15, 0x07, 0x34, 0x14, 0xae, 0xa1, 0xb3,
+	0xac, 0x4d, 0xeb, 0x84, 0xf2, 0x4f, 0x53, 0xb5, 0x9d, 0x16, 0xa9, 0xbb, 0xef, 0x13, 0x73, 0x52,
+	0x08, 0xff, 0x8f, 0x12, 0x3e, 0xbb, 0xb5, 0x51, 0xcf, 0x42, 0xa0, 0x9f, 0x1c, 0x7e, 0x17, 0xcd,
+	0xb1, 0x36, 0x05, 0xe2, 0x91, 0xa6, 0xcf, 0x5c, 0x9b, 0xb9, 0xbe, 0x67, 0xa2, 0x2b, 0xc6, 0xd5,
+	0xc9, 0xca, 0xb2, 0xd2, 0x35, 0xb7, 0xb5, 0x51, 0x4f, 0xf1, 0x1f, 0x1f, 0x2d, 0x5e, 0xc8, 0xd2,
+	0x36, 0xfd, 0xb6, 0xeb, 0xf4, 0x20, 0xa7, 0x8b, 0x87, 0xa9, 0xb5, 0xec, 0x98, 0x25, 0xf1, 0xdd,
+	0xe3, 0x30, 0xad, 0x2d, 0x57, 0x81, 0xd3, 0xf1, 0x2d, 0x34, 0xdf, 0x70, 0xa9, 0xbd, 0xd3, 0x26,
+	0xb7, 0x09, 0xe9, 0xae, 0xb4, 0xdd, 0x7d, 0x42, 0xcd, 0x29, 0x01, 0x7e, 0x4e, 0x81, 0xe7, 0x57,
+	0xb3, 0x00, 0xc8, 0xcb, 0xe0, 0x6f, 0xa1, 0x69, 0x99, 0x80, 0x2b, 0x8d, 0x46, 0x40, 0x28, 0x35,
+	0xa7, 0xc5, 0x4b, 0x9c, 0x57, 0x4a, 0xa6, 0xeb, 0x3a, 0x13, 0xd2, 0x58, 0xeb, 0xf7, 0x23, 0x68,
+	0x66, 0xd5, 0xa5, 0x5d, 0x9b, 0x39, 0x2d, 0xf9, 0x26, 0xf8, 0x06, 0x9a, 0xa0, 0x8c, 0xaf, 0xfe,
+	0x66, 0x4f, 0x24, 0xed, 0x64, 0xe5, 0xf9, 0x28, 0x69, 0xeb, 0x8a, 0xfe, 0x58, 0xfb, 0x0d, 0x31,
+	0x1a, 0xbf, 0x81, 0x66, 0xc2, 0x2e, 0x65, 0x01, 0xb1, 0x3b, 0xf5, 0x70, 0x87, 0x12, 0xa6, 0x96,
+	0x18, 0x3e, 0x3e, 0x5a, 0x9c, 0xd9, 0x4e, 0x71, 0x20, 0x83, 0xc4, 0x0f, 0xa2, 0x62, 0x32, 0x22,
+	0x8a, 0xc9, 0xc6, 0xe9, 0x8b, 0x49, 0xfa, 0x75, 0x06, 0xd7, 0x13, 0x5c, 0x47, 0xe7, 0x77, 0xdb,
+	0xfe, 0x41, 0xd5, 0xf7, 0x58, 0xe0, 0xb7, 0xeb, 0xa2, 0xf4, 0xdd, 0xb1, 0x3b, 0x44, 0x2c, 0x91,
+	0xc9, 0xca, 0x65, 0x25, 0x74, 0xfe, 0xad, 0x7e, 0x20, 0xe8, 0x2f, 0x8b, 0xaf, 0xa3, 0xf1, 0xb6,
+	0xdf, 0xac, 0xf9, 0x0d, 0x22, 0x56, 0xd0, 0x64, 0xe5, 0x92, 0x52, 0x33, 0xbe, 0x21, 0xc9, 0x8f,
+	0x93, 0x9f, 0x10, 0x41, 0xf1, 0x15, 0x34, 0xea, 0x71, 0xcb, 0x63, 0x42, 0x64, 0x4a, 0x89, 0x8c,
+	0x0a, 0x43, 0x82, 0x63, 0x7d, 0x35, 0x82, 0x70, 0xfe, 0xcd, 0xf0, 0x22, 0x2a, 0xee, 0x93, 0x60,
+	0x27, 0xaa, 0x7d, 0x93, 0xfc, 0x25, 0xef, 0x73, 0x02, 0x48, 0x7a, 0xba, 0x40, 0x16, 0x9e, 0x50,
+	0x20, 0xbf, 0x4e, 0xb5, 0xc3, 0xaf, 0xa3, 0xe9, 0xe8, 0x81, 0xfb, 0x49, 0xcd, 0x51, 0x21, 0x30,
+	0xcf, 0x73, 0x0e, 0x74, 0x06, 0xa4, 0x71, 0xdc, 0xe7, 0x90, 0x92, 0x80, 0x9a, 0xc5, 0xc4, 0xe7,
+	0x6d, 0x4e, 0x00, 0x49, 0xc7, 0xbf, 0x30, 0xd0, 0x2c, 0x25, 0xc1, 0xbe, 0xeb, 0x90, 0x15, 0xc7,
+	0xf1, 0x43, 0x8f, 0xf1, 0x6a, 0xc3, 0xd3, 0xe2, 0xf6, 0xe9, 0xd3, 0xa2, 0x9e, 0x52, 0x08, 0x64,
+	0xb7, 0x72, 0x51, 0x85, 0x79, 0x36, 0xcd, 0xa2, 0x90, 0x35, 0x8e, 0xcb, 0x08, 0x71, 0xcf, 0x54,
+	0x14, 0xc7, 0x85, 0xdb, 0x33, 0xbc, 0x52, 0x6d, 0xc7, 0x54, 0xd0, 0x10, 0xf8, 0x4d, 0x34, 0xeb,
+	0xf9, 0x5e, 0x14, 0x84, 0x6d, 0xd8, 0xa0, 0xe6, 0x84, 0x10, 0x3a, 0xcb, 0xcd, 0xdd, 0x49, 0xb3,
+	0x20, 0x8b, 0xb5, 0x5a, 0xe8, 0xe2, 0xcd, 0x43, 0xd2, 0xe9, 0xb2, 0x5c, 0xe6, 0xf1, 0x1a, 0xd8,
+	0xb1, 0x0f, 0x81, 0x3c, 0x08, 0x09, 0x65, 0x74, 0xdd, 0xdb, 0x6d, 0xbb, 0xcd, 0x16, 0x33, 0x8d,
+	0x74, 0x0d, 0xac, 0xe5, 0x21, 0xd0, 0x4f, 0xce, 0xfa, 0x6a, 0x14, 0x95, 0x34, 0x23, 0xf8, 0x67,
+	0x06, 0xc2, 0xb9, 0xbc, 0x8e, 0x36, 0xf8, 0x21, 0x82, 0x9f, 0x7b, 0x91, 0xca, 0x6c, 0xb4, 0x2c,
+	0x94, 0x0d, 0xe8, 0x63, 0x17, 0x7f, 0x6a, 0xa0, 0x39, 0x9e, 0xfd, 0xb4, 0x6b, 0x3b, 0x24, 0x72,
+	0xa6, 0x20, 0x9c, 0xd9, 0x3a, 0xbd, 0x33, 0x77, 0x22, 0x8d, 0x79, 0xaf, 0xcc, 0xa8, 0xf2, 0xdf,
+	0xc9, 0x58, 0x85, 0x9c, 0x1f, 0xf8, 0x63, 0x03, 0xcd, 0x07, 0xe4, 0x3d, 0xe2, 0xf0, 0x6a, 0x0f,
+	0x84, 0x76, 0x7d, 0x8f, 0x12, 0xb1, 0x0d, 0x0f, 0x15, 0x2a, 0xc8, 0xaa, 0xac, 0x9c, 0xe7, 0x5b,
+	0x41, 0x8e, 0x0c, 0x79, 0xe3, 0x22, 0x5e, 0x3c, 0x0d, 0x57, 0x9a, 0xc4, 0x63, 0x51, 0xbc, 0x46,
+	0x87, 0x8d, 0xd7, 0x76, 0xa4, 0xf1, 0x84, 0x78, 0x6d, 0x67, 0xac, 0x42, 0xce, 0x0f, 0xeb, 0x78,
+	0x04, 0xcd, 0xe7, 0x13, 0x3a, 0xaa, 0x7c, 0xc6, 0xa0, 0xca, 0x87, 0x1f, 0x19, 0x68, 0x21, 0x97,
+	0x1b, 0xb2, 0xc1, 0x0a, 0x03, 0xb9, 0x6d, 0x17, 0x44, 0xd0, 0xbf, 0xfb, 0x0c, 0xf3, 0x33, 0xa5,
+	0xbf, 0xf2, 0x92, 0x72, 0x6b, 0xe1, 0x64, 0x1c, 0x3c, 0xc1, 0x4f, 0xbe, 0x7a, 0xe3, 0x8f, 0x56,
+	0x67, 0x36, 0x0b, 0x69, 0xd5, 0x6f, 0xc8, 0x9c, 0xd1, 0x56, 0x2f, 0xe4, 0x21, 0xd0, 0x4f, 0x6e,
+	0x40, 0x06, 0x8e, 0xfe, 0x17, 0x33, 0xd0, 0xfa, 0xe9, 0x18, 0x7a, 0x42, 0x90, 0x70, 0x88, 0xc6,
+	0x88, 0xa8, 0x6e, 0xe2, 0x9b, 0x97, 0x96, 0xef, 0x9d, 0xde, 0xd3, 0x01, 0x55, 0x52, 0x76, 0xad,
+	0x92, 0x09, 0xca, 0x18, 0xfe, 0x83, 0xd1, 0xbf, 0x74, 0xca, 0xdc, 0x79, 0xf7, 0xf4, 0x4e, 0xf4,
+	0x29, 0xb6, 0x79, 0x8f, 0x2e, 0x7e, 0x9d, 0xb2, 0x8c, 0x3f, 0x32, 0x50, 0x89, 0xf1, 0x06, 0xbf,
+	0x12, 0x3a, 0x7b, 0x84, 0xa9, 0xa2, 0x72, 0xff, 0xf4, 0x3e, 0x6e, 0x25, 0xca, 0xfa, 0x94, 0x62,
+	0x7e, 0xc4, 0xd0, 0x10, 0xa0, 0xdb, 0xc6, 0x7f, 0x35, 0xd0, 0x73, 0x7d, 0x7c, 0xac, 0xf4, 0x78,
+	0x9b, 0xa1, 0x92, 0xad, 0xf1, 0x4c, 0xa3, 0x27, 0x55, 0xe7, 0xfd, 0xbc, 0x7c, 0x7c, 0xb4, 0xf8,
+	0xdc, 0x40, 0x3c, 0x0c, 0xf6, 0x92, 0xa7, 0xdc, 0x83, 0x90, 0x84, 0xa4, 0x61, 0x16, 0x87, 0x4d,
+	0xb9, 0x7b, 0x42, 0xcf, 0x80, 0x94, 0x93, 0x4c, 0x50, 0xc6, 0xac, 0x5f, 0x17, 0xd1, 0xfc, 0x1a,
+	0xb1, 0xdb, 0xac, 0x55, 0x6d, 0x11, 0x67, 0x4f, 0xf5, 0xd7, 0xb7, 0xd0, 0x3c, 0x0d, 0x1d, 0x87,
+	0x37, 0xe3, 0x36, 0x23, 0x6f, 0xbb, 0x5e, 0xc3, 0x3f, 0x50, 0x1b, 0x78, 0xdc, 0xf8, 0xd7, 0xb3,
+	0x00, 0xc8, 0xcb, 0x70, 0x45, 0x1d, 0xd7, 0x53, 0xd0, 0x4d, 0x12, 0x38, 0xc4, 0x93, 0xe9, 0xac,
+	0x29, 0xaa, 0x65, 0x01, 0x90, 0x97, 0xc1, 0x9b, 0xe8, 0x9c, 0xeb, 0x31, 0x12, 0xec, 0xdb, 0xed,
+	0x9a, 0xdb, 0x6e, 0xbb, 0x94, 0x38, 0xbe, 0xd7, 0xa0, 0xaa, 0x2e, 0x45, 0xdd, 0xff, 0xb9, 0xf5,
+	0x3e, 0x18, 0xe8, 0x2b, 0x29, 0x8e, 0x6a, 0x6e, 0x87, 0xf8, 0x21, 0x4b, 0x29, 0x1c, 0xcd, 0x1c,
+	0xd5, 0xf2, 0x10, 0xe8, 0x27, 0xc7, 0x37, 0x89, 0xae, 0xcd, 0x5a, 0x66, 0x31, 0xbd, 0x49, 0x6c,
+	0xda, 0xac, 0x05, 0x82, 0xc3, 0x63, 0xb1, 0x6b, 0xb7, 0xdb, 0x3b, 0xb6, 0xb3, 0xb7, 0xe5, 0xcb,
+	0x98, 0xbf, 0x6f, 0x8e, 0xa5, 0x4f, 0x53, 0x6f, 0x65, 0x01, 0x90, 0x97, 0xc1, 0xdf, 0x41, 0x38,
+	0xf4, 0x5a, 0xe2, 0xa1, 0xb7, 0xd5, 0x0a, 0x08, 0x6d, 0xf9, 0xed, 0x86, 0x3a, 0xca, 0x46, 0xad,
+	0x3c, 0xde, 0xce, 0x21, 0xa0, 0x8f, 0x14, 0x5e, 0x45, 0x73, 0x39, 0x4d, 0xf2, 0xa8, 0x1b, 0xef,
+	0x9b, 0x6b, 0x59, 0x3d, 0x39, 0x09, 0x7c, 0x1f, 0x5d, 0xe8, 0xd8, 0x87, 0x15, 0xdb, 0xd9, 0xf3,
+	0x77, 0x77, 0x53, 0xe1, 0x94, 0x27, 0xdf, 0x05, 0xa5, 0xeb, 0x42, 0xad, 0x2f, 0x0a, 0x06, 0x48,
+	0x5b, 0x1f, 0x19, 0xe8, 0xdc, 0x9a, 0xdb, 0x68, 0x10, 0x2f, 0x33, 0xd7, 0x79, 0x90, 0x9e, 0xeb,
+	0xfc, 0x07, 0x8e, 0x62, 0xd6, 0x3f, 0x0d, 0x74, 0x76, 0xbd, 0xd3, 0x25, 0x01, 0xf5, 0x3d, 0xed,
+	0x54, 0x8d, 0xaf, 0xa3, 0x29, 0xbb, 0xdd, 0xf6, 0x0f, 0x48, 0x43, 0x1c, 0x10, 0xd4, 0x29, 0x67,
+	0xee, 0xf8, 0x68, 0x71, 0x6a, 0x45, 0xa3, 0x43, 0x0a, 0xc5, 0x07, 0x29, 0x0d, 0xe2, 0xb9, 0x91,
+	0x90, 0x36, 0xe7, 0x59, 0x4d, 0xc8, 0xa0, 0x63, 0xf8, 0x61, 0x46, 0xa9, 0x50, 0x4d, 0xfe, 0x48,
+	0x72, 0x98, 0x59, 0xd1, 0x19, 0x90, 0xc6, 0x71, 0x0f, 0xa5, 0x1e, 0x25, 0x37, 0x9a, 0x78, 0xb8,
+	0xaa, 0xd1, 0x21, 0x85, 0xb2, 0x7e, 0x80, 0xa6, 0x37, 0xfc, 0x66, 0xd3, 0xf5, 0x9a, 0x2a, 0xe6,
+	0xaf, 0xa0, 0xd1, 0x0e, 0x6f, 0x05, 0x64, 0x1b, 0x14, 0x9d, 0x4c, 0x46, 0xb3, 0x07, 0x46, 0x01,
+	0xc2, 0x6f, 0xa6, 0x8e, 0x23, 0x85, 0xd4, 0x69, 0x55, 0x3b, 0x92, 0xe8, 0x82, 0x9a, 0x80, 0xf5,
+	0xa9, 0x81, 0xfe, 0xef, 0xe9, 0xcb, 0x2e, 0x7e, 0x0d, 0x95, 0x3a, 0xf6, 0x61, 0x2d, 0x64, 0x36,
+	0x73, 0xbd, 0xa6, 0xaa, 0x54, 0x67, 0x95, 0xb9, 0x52, 0x2d, 0x61, 0x81, 0x8e, 0x53, 0x62, 0x40,
+	0xec, 0xc6, 0x5d, 0xaf, 0xdd, 0x33, 0x0b, 0x39, 0xb1, 0x88, 0x05, 0x3a, 0xce, 0xba, 0x89, 0x5e,
+	0x78, 0x9a, 0x0d, 0x95, 0x4f, 0x57, 0x3a, 0xf6, 0xa1, 0xf2, 0x26, 0x9e, 0xae, 0x70, 0x51, 0x4e,
+	0xb7, 0x7e, 0x67, 0xa0, 0x4b, 0x83, 0xfb, 0x7c, 0x7e, 0xa0, 0x8b, 0xfb, 0xf9, 0x28, 0xab, 0xc4,
+	0x81, 0x2e, 0x96, 0xa1, 0xa0, 0x21, 0x06, 0x8f, 0x0a, 0x0a, 0xa7, 0x1f, 0x15, 0x58, 0x7f, 0x2c,
+	0xa0, 0x0b, 0x77, 0x43, 0xd6, 0x76, 0x49, 0xb0, 0x4a, 0x18, 0x71, 0xb4, 0xbc, 0xbf, 0x85, 0xe6,
+	0x1d, 0x5f, 0xcc, 0x05, 0x99, 0xbb, 0x4f, 0x6e, 0x06, 0x81, 0x1f, 0x50, 0xf5, 0xae, 0x71, 0x39,
+	0xab, 0x66, 0x01, 0x90, 0x97, 0xc1, 0x2b, 0x68, 0x36, 0x2a, 0xd0, 0x75, 0x55, 0x35, 0xe4, 0x97,
+	0x88, 0x0f, 0xbf, 0xeb, 0x69, 0x36, 0x64, 0xf1, 0x5c, 0x45, 0xdc, 0x7b, 0xa6, 0x36, 0x86, 0x58,
+	0xc5, 0xcd, 0x34, 0x1b, 0xb2, 0x78, 0xae, 0xa2, 0x65, 0xb7, 0x77, 0xef, 0x76, 0x89, 0x17, 0xed,
+	0x53, 0xa3, 0x69, 0x15, 0x6b, 0x69, 0x36, 0x64, 0xf1, 0xd6, 0x6f, 0x0a, 0xe8, 0xe2, 0x80, 0xbd,
+	0x97, 0xa7, 0x9a, 0xe3, 0x7b, 0x4e, 0x18, 0x04, 0xc4, 0x73, 0x7a, 0xd9, 0x0c, 0xad, 0x26, 0x2c,
+	0xd0, 0x71, 0x5c, 0x4c, 0x6c, 0xd4, 0x1b, 0xc4, 0x6b, 0xb2, 0x56, 0x36, 0x43, 0xef, 0x25, 0x2c,
+	0xd0, 0x71, 0xf8, 0x25, 0xd5, 0x4c, 0x44, 0x61, 0x98, 0x51, 0x12, 0x72, 0xf7, 0xa7, 0x6a, 0xf7,
+	0xa7, 0x7c, 0xf8, 0xdb, 0xb2, 0xbd, 0x86, 0x98, 0x51, 0xca, 0xb7, 0x8d, 0x87, 0xbf, 0x6b, 0x8a,
+	0x0e, 0x31, 0x02, 0x7f, 0x1b, 0xcd, 0x74, 0xec, 0xc3, 0xb7, 0x6d, 0x97, 0x45, 0x41, 0x2e, 0x0a,
+	0x99, 0x0b, 0x4a, 0x66, 0xa6, 0x96, 0xe2, 0x42, 0x06, 0x6d, 0x3d, 0x2c, 0xa0, 0x7c, 0x87, 0x8e,
+	0x5f, 0x46, 0xe3, 0x1d, 0x42, 0xa9, 0xdd, 0x8c, 0x2a, 0x4b, 0x7c, 0xec, 0xae, 0x49, 0x32, 0x44,
+	0x7c, 0xfc, 0xa1, 0x81, 0xc6, 0x5b, 0xc4, 0x6e, 0x44, 0x15, 0x73, 0xa8, 0xf3, 0x54, 0xce, 0x93,
+	0xf2, 0x9a, 0x54, 0x7d, 0xd3, 0x63, 0x41, 0x2f, 0xf1, 0x42, 0x51, 0x21, 0xb2, 0x7c, 0xe9, 0x0d,
+	0x34, 0xa5, 0x23, 0xf1, 0x1c, 0x1a, 0xd9, 0x23, 0x6a, 0x0e, 0x09, 0xfc, 0x27, 0x3e, 0x87, 0x8a,
+	0xfb, 0x76, 0x3b, 0x54, 0x4b, 0x0f, 0xe4, 0xc3, 0x1b, 0x85, 0x1b, 0x86, 0xf5, 0xab, 0x51, 0x54,
+	0x02, 0xc2, 0x82, 0x9e, 0x5a, 0x44, 0xaf, 0xa3, 0x69, 0x2a, 0x0e, 0x4b, 0x40, 0x6c, 0xea, 0x7b,
+	0xd1, 0x3a, 0x17, 0x35, 0xbd, 0xae, 0x33, 0x20, 0x8d, 0xe3, 0x73, 0x4c, 0x49, 0x50, 0x41, 0xa2,
+	0xfa, 0x1c, 0xb3, 0x9e, 0xe2, 0x40, 0x06, 0x89, 0xdf, 0x41, 0xb3, 0xcc, 0xf7, 0x6b, 0xb6, 0xd7,
+	0x8b, 0x6a, 0x98, 0x48, 0x93, 0xc9, 0xca, 0xab, 0x51, 0xaa, 0x6f, 0xa5, 0xd9, 0x8f, 0x8f, 0x16,
+	0xcf, 0x67, 0x48, 0x6a, 0xbb, 0xcc, 0x2a, 0xc2, 0x7b, 0xe8, 0x72, 0x86, 0xa4, 0xf6, 0xf5, 0x7a,
+	0xaa, 0xbf, 0x7a, 0x51, 0x59, 0xba, 0xbc, 0x75, 0x12, 0x18, 0x4e, 0xd6, 0xc5, 0x37, 0x51, 0x1a,
+	0x1f, 0x35, 0xe5, 0xac, 0xae, 0x28, 0x37, 0xd1, 0xe4, 0x04, 0x4a, 0x41, 0xc7, 0xf0, 0x7e, 0xc7,
+	0xf1, 0x3d, 0x4f, 0x7e, 0x79, 0x55, 0xb4, 0x64, 0x0f, 0x16, 0xf7, 0x3b, 0xd5, 0x0c, 0x1f, 0x72,
+	0x12, 0xc9, 0x48, 0x73, 0x7c, 0xc0, 0x48, 0x73, 0x19, 0x21, 0xb1, 0x63, 0xb0, 0xc0, 0x25, 0x34,
+	0x7b, 0x77, 0x50, 0x8b, 0x39, 0xa0, 0xa1, 0xac, 0x5d, 0x34, 0x5f, 0x27, 0x4e, 0x40, 0xf8, 0xe0,
+	0x8f, 0x04, 0xc4, 0x21, 0x9e, 0x43, 0xf0, 0x12, 0x9a, 0x8c, 0x6b, 0xbc, 0x5a, 0x1f, 0xf3, 0x4a,
+	0xcf, 0x64, 0xbc, 0x11, 0x40, 0x82, 0x89, 0x87, 0x15, 0x85, 0x81, 0x63, 0xda, 0x4f, 0x0a, 0x68,
+	0xba, 0x2e, 0xae, 0x73, 0xc4, 0x50, 0xd1, 0x6b, 0xea, 0x57, 0x34, 0xc6, 0x53, 0x5e, 0xd1, 0x14,
+	0x4e, 0xbc, 0xa2, 0xb9, 0x8e, 0xa6, 0x1c, 0x79, 0xc9, 0xb4, 0xa2, 0x5d, 0xfc, 0x88, 0x8e, 0xa3,
+	0xaa, 0xd1, 0x21, 0x85, 0xc2, 0xab, 0x08, 0xc9, 0xe7, 0x95, 0x90, 0xb5, 0xd4, 0x84, 0xfb, 0x85,
+	0x28, 0x68, 0xd5, 0x98, 0xf3, 0xf8, 0x68, 0x71, 0x26, 0x79, 0x92, 0xad, 0x43, 0x22, 0xc7, 0x6d,
+	0xdb, 0x5d, 0x77, 0x25, 0x6c, 0xb8, 0x3c, 0x80, 0xd1, 0x04, 0x57, 0xf6, 0x63, 0x9b, 0xeb, 0x31,
+	0x1d, 0x52, 0x28, 0x19, 0xfc, 0xcc, 0xf4, 0xf5, 0x29, 0x06, 0x3f, 0xa9, 0xcf, 0x53, 0x78, 0xf2,
+	0xe7, 0xb1, 0xfe, 0x6c, 0xa0, 0xa9, 0x7a, 0xcb, 0x6e, 0xf8, 0x07, 0xaa, 0xab, 0x7a, 0x19, 0x8d,
+	0x3b, 0xed, 0x90, 0x32, 0x12, 0x64, 0xcb, 0x5f, 0x55, 0x92, 0x21, 0xe2, 0xf3, 0xa4, 0xea, 0xca,
+	0xad, 0xc6, 0x6e, 0x4a, 0x6b, 0x5a, 0x52, 0x6d, 0xc6, 0x1c, 0xd0, 0x50, 0x32, 0xdf, 0x3b, 0x5d,
+	0x3b, 0x20, 0x51, 0x99, 0x93, 0x8b, 0x3d, 0x95, 0xef, 0x69, 0x3e, 0xe4, 0x24, 0xac, 0x87, 0x06,
+	0x42, 0x75, 0x16, 0xee, 0x24, 0x3e, 0x3f, 0x6d, 0xc9, 0xbe, 0xc5, 0xc7, 0x3f, 0x2c, 0xe8, 0xad,
+	0xec, 0x32, 0x12, 0xa4, 0xb7, 0xf7, 0xb8, 0x4b, 0x80, 0x2c, 0x00, 0xf2, 0x32, 0xd6, 0xdf, 0x0d,
+	0xf4, 0xfc, 0x49, 0x23, 0x82, 0xe8, 0xca, 0xcf, 0x78, 0xd2, 0x95, 0x5f, 0xe1, 0x84, 0x2b, 0xbf,
+	0x55, 0x34, 0x47, 0xdb, 0xfe, 0x41, 0x9d, 0xd9, 0x01, 0x4b, 0x37, 0x12, 0x71, 0xb4, 0xea, 0x19,
+	0x3e, 0xe4, 0x24, 0xf0, 0x6b, 0xa8, 0xb8, 0x47, 0x7a, 0x95, 0x9e, 0x4a, 0xe1, 0xc5, 0xc8, 0xd4,
+	0x6d, 0x4e, 0xe4, 0xd9, 0xab, 0xbd, 0xc7, 0x6d, 0xd2, 0x03, 0x89, 0xb6, 0xfe, 0x56, 0x40, 0xb3,
+	0xd1, 0x0d, 0x94, 0xfa, 0xf6, 0xf8, 0xfb, 0x68, 0x82, 0xdf, 0xac, 0x37, 0xa2, 0xa5, 0x59, 0x5a,
+	0x7e, 0xb5, 0x2c, 0x2f, 0xc8, 0xcb, 0xfa, 0x05, 0x79, 0xb2, 0xc9, 0x71, 0x74, 0x79, 0xff, 0x5a,
+	0xf9, 0xee, 0x0e, 0xdf, 0xdd, 0x6a, 0x84, 0xd9, 0x49, 0x8a, 0x24, 0x34, 0x88, 0xb5, 0x62, 0x1f,
+	0x8d, 0xd2, 0x2e, 0x71, 0xd4, 0x8c, 0xa9, 0x36, 0xc4, 0x08, 0x36, 0xed, 0x7a, 0xbd, 0x4b, 0x9c,
+	0x64, 0xc9, 0xf0, 0x27, 0x10, 0x86, 0xf0, 0x01, 0x1a, 0x93, 0x05, 0x59, 0x8d, 0x8c, 0xee, 0x3e,
+	0x3b, 0x93, 0x42, 0x6d, 0xd2, 0xec, 0xc8, 0x67, 0x50, 0xe6, 0xac, 0x2f, 0x0d, 0x74, 0x36, 0x23,
+	0xb1, 0xe1, 0x52, 0x86, 0xbf, 0x97, 0x8b, 0x71, 0xf9, 0xe9, 0x62, 0xcc, 0xa5, 0x45, 0x84, 0xe3,
+	0xa6, 0x29, 0xa2, 0x68, 0xf1, 0xf5, 0x50, 0xd1, 0x65, 0xa4, 0x13, 0x35, 0x2c, 0xeb, 0xcf, 0xec,
+	0x6d, 0x93, 0x14, 0x5e, 0xe7, 0xfa, 0x41, 0x9a, 0xb1, 0x7e, 0x69, 0xa0, 0xf3, 0xd9, 0xb8, 0x90,
+	0x60, 0x9f, 0x04, 0xbc, 0xd9, 0x23, 0x5e, 0xa3, 0xeb, 0xbb, 0x1e, 0x53, 0xcb, 0x36, 0xf6, 0xfb,
+	0xa6, 0xa2, 0x43, 0x8c, 0xe0, 0xc5, 0x5e, 0xdd, 0xe3, 0x36, 0x44, 0x6e, 0x4c, 0xc8, 0x62, 0xaf,
+	0xae, 0x7b, 0x1b, 0x10, 0x73, 0xf9, 0x7d, 0xfc, 0x01, 0x11, 0x73, 0x4a, 0xb9, 0x54, 0xc4, 0x98,
+	0xe9, 0x6d, 0x41, 0x01, 0xc5, 0xb1, 0x3e, 0x98, 0xcd, 0xc5, 0x9e, 0xa7, 0x04, 0x7e, 0x1f, 0x8d,
+	0x53, 0xe1, 0x5d, 0x74, 0x92, 0x7f, 0x86, 0xd9, 0x20, 0xf4, 0x6a, 0x97, 0x38, 0xd2, 0x0e, 0x44,
+	0x06, 0xf1, 0x43, 0x23, 0xde, 0xa5, 0x44, 0x59, 0x53, 0x4b, 0xe0, 0xad, 0xd3, 0x7b, 0xa0, 0xff,
+	0xb1, 0xa2, 0x72, 0x4e, 0x19, 0x4e, 0xfd, 0xdd, 0x02, 0x52, 0x16, 0xf1, 0x8f, 0x0d, 0x34, 0x4d,
+	0xf5, 0xad, 0x58, 0xad, 0x89, 0x5b, 0xc3, 0xdc, 0x21, 0x6a, 0xea, 0xb4, 0x1b, 0x76, 0x9d, 0x0c,
+	0x69, 0xa3, 0xf8, 0x87, 0xa8, 0xa4, 0x1d, 0xff, 0xd4, 0xc0, 0xf4, 0xe6, 0x33, 0xb9, 0xaa, 0x48,
+	0x0e, 0x2b, 0x1a, 0x11, 0x74, 0x73, 0xfc, 0x2a, 0x75, 0xae, 0xa1, 0x4f, 0x61, 0x5c, 0xb5, 0x6b,
+	0x97, 0x96, 0xd7, 0x9e, 0xd5, 0x5c, 0x27, 0xa9, 0xdf, 0xab, 0x19, 0x4b, 0x90, 0xb3, 0x8d, 0x03,
+	0x71, 0x3f, 0xce, 0x27, 0x1f, 0xe6, 0xd8, 0xb0, 0x9f, 0x23, 0x35, 0x42, 0x49, 0x92, 0x51, 0x91,
+	0x21, 0x32, 0x24, 0x2e, 0x4d, 0x5d, 0x4f, 0x8d, 0xda, 0xa2, 0xe5, 0x48, 0xcd, 0xf1, 0xf4, 0x34,
+	0xb2, 0x96, 0x87, 0x40, 0x3f, 0xb9, 0xd4, 0xea, 0x9d, 0x38, 0x71, 0xf5, 0xbe, 0x87, 0xc6, 0xa8,
+	0xe8, 0x47, 0xcc, 0xc9, 0x61, 0xd3, 0x5f, 0xef, 0x6b, 0x64, 0x15, 0x90, 0x14, 0x50, 0x16, 0xf0,
+	0x2e, 0x2a, 0x8a, 0x8d, 0xdd, 0x44, 0xc3, 0x66, 0x98, 0x76, 0x86, 0x92, 0xdd, 0xb7, 0x20, 0x80,
+	0x54, 0x8f, 0x77, 0xd0, 0x28, 0x65, 0xe1, 0x8e, 0xf8, 0x5f, 0x4b, 0x69, 0x79, 0x75, 0x88, 0x37,
+	0x8a, 0x7b, 0x9e, 0xca, 0x84, 0xd8, 0xc6, 0x58, 0xb8, 0x03, 0x42, 0x37, 0xfe, 0xc0, 0x10, 0x7d,
+	0x66, 0xfc, 0xb7, 0x03, 0x73, 0x6a, 0xd8, 0x3b, 0xad, 0xdc, 0xbf, 0xd7, 0xe2, 0xa6, 0x35, 0x36,
+	0x02, 0x29, 0x93, 0xf8, 0x47, 0xa8, 0xd4, 0x4a, 0x66, 0xf7, 0xe6, 0xf4, 0xb0, 0x1e, 0xe4, 0x2e,
+	0x02, 0xe4, 0x61, 0x4a, 0x23, 0x83, 0x6e, 0x10, 0xff, 0xdc, 0x40, 0xb3, 0xad, 0xd4, 0x78, 0x96,
+	0x9a, 0x33, 0xc2, 0x89, 0x3b, 0x43, 0x38, 0xd1, 0x67, 0xde, 0x2b, 0xff, 0x94, 0x90, 0xe6, 0x50,
+	0xc8, 0xda, 0xc6, 0x9f, 0x18, 0x68, 0xce, 0xcf, 0x4c, 0xab, 0xcc, 0x59, 0xe1, 0xd0, 0xe6, 0xe9,
+	0x1d, 0xea, 0x3f, 0xff, 0xaa, 0x9c, 0xe3, 0xd5, 0x24, 0xcb, 0x83, 0x9c, 0x7d, 0xfc, 0x13, 0x03,
+	0x4d, 0xbb, 0xfa, 0xdc, 0xd8, 0x9c, 0x1b, 0xb6, 0xd5, 0xea, 0x33, 0x86, 0x96, 0x23, 0x83, 0x14,
+	0x03, 0xd2, 0x66, 0xad, 0x8b, 0xf9, 0xc6, 0x40, 0x36, 0x46, 0x7f, 0x31, 0xd0, 0xa5, 0xc1, 0x17,
+	0xe8, 0xb8, 0x8a, 0xe6, 0xe3, 0x8b, 0xf2, 0xcd, 0x80, 0xec, 0xba, 0x87, 0xf1, 0x3c, 0x52, 0x5c,
+	0xba, 0x6e, 0x67, 0x99, 0x90, 0xc7, 0xff, 0x5b, 0xa6, 0x93, 0x95, 0xf2, 0xa3, 0x2f, 0x16, 0xce,
+	0x7c, 0xf6, 0xc5, 0xc2, 0x99, 0xcf, 0xbf, 0x58, 0x38, 0xf3, 0xf0, 0x78, 0xc1, 0x78, 0x74, 0xbc,
+	0x60, 0x7c, 0x76, 0xbc, 0x60, 0x7c, 0x7e, 0xbc, 0x60, 0xfc, 0xe3, 0x78, 0xc1, 0xf8, 0xf8, 0xcb,
+	0x85, 0x33, 0xef, 0x4c, 0x44, 0x71, 0xfb, 0xd7, 0x00, 0xdc, 0x50, 0x11, 0x3d, 0x76, 0x2b, 0x00,
+	0x00,
+}
+
+func (m *APIResourceConfig) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *APIResourceConfig) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *APIResourceConfig) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Rules) > 0 {
+		for iNdEx := len(m.Rules) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Rules[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *APIResourceRule) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *APIResourceRule) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *APIResourceRule) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Resources) > 0 {
+		for iNdEx := len(m.Resources) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Resources[iNdEx])
+			copy(dAtA[i:], m.Resources[iNdEx])
+			i = encodeVarintGenerated(dAtA, i, uint64(len(m.Resources[iNdEx])))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.APIVersions) > 0 {
+		for iNdEx := len(m.APIVersions) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.APIVersions[iNdEx])
+			copy(dAtA[i:], m.APIVersions[iNdEx])
+			i = encodeVarintGenerated(dAtA, i, uint64(len(m.APIVersions[iNdEx])))
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.APIGroups) > 0 {
+		for iNdEx := len(m.APIGroups) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.APIGroups[iNdEx])
+			copy(dAtA[i:], m.APIGroups[iNdEx])
+			i = encodeVarintGenerated(dAtA, i, uint64(len(m.APIGroups[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
 }
 
 func (m *ClientConfig) Marshal() (dAtA []byte, err error) {
@@ -690,6 +1292,35 @@ func (m *ClientConfig) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	i -= len(m.SourceAddress)
+	copy(dAtA[i:], m.SourceAddress)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.SourceAddress)))
+	i--
+	dAtA[i] = 0x6a
+	i--
+	if m.DisableKeepAlives {
+		dAtA[i] = 1
+	} else {
+		dAtA[i] = 0
+	}
+	i--
+	dAtA[i] = 0x60
+	i--
+	if m.H2C {
+		dAtA[i] = 1
+	} else {
+		dAtA[i] = 0
+	}
+	i--
+	dAtA[i] = 0x58
+	i -= len(m.TLSRenegotiation)
+	copy(dAtA[i:], m.TLSRenegotiation)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.TLSRenegotiation)))
+	i--
+	dAtA[i] = 0x52
+	i = encodeVarintGenerated(dAtA, i, uint64(m.TLSSessionCacheSize))
+	i--
+	dAtA[i] = 0x48
 	i = encodeVarintGenerated(dAtA, i, uint64(m.QPSDivisor))
 	i--
 	dAtA[i] = 0x40
@@ -758,6 +1389,11 @@ func (m *DispatchPolicy) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	i -= len(m.Name)
+	copy(dAtA[i:], m.Name)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Name)))
+	i--
+	dAtA[i] = 0x32
 	i -= len(m.LogMode)
 	copy(dAtA[i:], m.LogMode)
 	i = encodeVarintGenerated(dAtA, i, uint64(len(m.LogMode)))
@@ -919,6 +1555,9 @@ func (m *ExemptFlowControlSchema) MarshalToSizedBuffer(dAtA []byte) (int, error)
 	_ = i
 	var l int
 	_ = l
+	i = encodeVarintGenerated(dAtA, i, uint64(m.MaxRequestsInflight))
+	i--
+	dAtA[i] = 0x8
 	return len(dAtA) - i, nil
 }
 
@@ -942,6 +1581,46 @@ func (m *FlowControl) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.UserAgentSchemas) > 0 {
+		for iNdEx := len(m.UserAgentSchemas) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.UserAgentSchemas[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if m.RejectionResponse != nil {
+		{
+			size, err := m.RejectionResponse.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintGenerated(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.NamespaceSchemas) > 0 {
+		for iNdEx := len(m.NamespaceSchemas) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.NamespaceSchemas[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
 	if len(m.Schemas) > 0 {
 		for iNdEx := len(m.Schemas) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -979,6 +1658,21 @@ func (m *FlowControlSchema) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.RejectionResponse != nil {
+		{
+			size, err := m.RejectionResponse.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintGenerated(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x22
+	}
+	i = encodeVarintGenerated(dAtA, i, uint64(m.RejectionStatusCode))
+	i--
+	dAtA[i] = 0x18
 	{
 		size, err := m.FlowControlSchemaConfiguration.MarshalToSizedBuffer(dAtA[:i])
 		if err != nil {
@@ -1017,6 +1711,30 @@ func (m *FlowControlSchemaConfiguration) MarshalToSizedBuffer(dAtA []byte) (int,
 	_ = i
 	var l int
 	_ = l
+	if m.Queued != nil {
+		{
+			size, err := m.Queued.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintGenerated(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x2a
+	}
+	if m.MaxRequestsInflightByVerb != nil {
+		{
+			size, err := m.MaxRequestsInflightByVerb.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintGenerated(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x22
+	}
 	if m.TokenBucket != nil {
 		{
 			size, err := m.TokenBucket.MarshalToSizedBuffer(dAtA[:i])
@@ -1056,7 +1774,7 @@ func (m *FlowControlSchemaConfiguration) MarshalToSizedBuffer(dAtA []byte) (int,
 	return len(dAtA) - i, nil
 }
 
-func (m *LoggingConfig) Marshal() (dAtA []byte, err error) {
+func (m *HealthCheckPolicy) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -1066,25 +1784,54 @@ func (m *LoggingConfig) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *LoggingConfig) MarshalTo(dAtA []byte) (int, error) {
+func (m *HealthCheckPolicy) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *LoggingConfig) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *HealthCheckPolicy) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	i -= len(m.Mode)
-	copy(dAtA[i:], m.Mode)
-	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Mode)))
+	i = encodeVarintGenerated(dAtA, i, uint64(m.MaxBackoffMilliseconds))
 	i--
-	dAtA[i] = 0xa
+	dAtA[i] = 0x48
+	i = encodeVarintGenerated(dAtA, i, uint64(m.HealthyThreshold))
+	i--
+	dAtA[i] = 0x40
+	i = encodeVarintGenerated(dAtA, i, uint64(m.UnhealthyThreshold))
+	i--
+	dAtA[i] = 0x38
+	i--
+	if m.FallbackToHealthz {
+		dAtA[i] = 1
+	} else {
+		dAtA[i] = 0
+	}
+	i--
+	dAtA[i] = 0x30
+	i -= len(m.Path)
+	copy(dAtA[i:], m.Path)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Path)))
+	i--
+	dAtA[i] = 0x2a
+	i = encodeVarintGenerated(dAtA, i, uint64(m.TimeoutMilliseconds))
+	i--
+	dAtA[i] = 0x20
+	i = encodeVarintGenerated(dAtA, i, uint64(m.IntervalMilliseconds))
+	i--
+	dAtA[i] = 0x18
+	i = encodeVarintGenerated(dAtA, i, uint64(m.MinSuccessPercent))
+	i--
+	dAtA[i] = 0x10
+	i = encodeVarintGenerated(dAtA, i, uint64(m.SuccessRateWindow))
+	i--
+	dAtA[i] = 0x8
 	return len(dAtA) - i, nil
 }
 
-func (m *MaxRequestsInflightFlowControlSchema) Marshal() (dAtA []byte, err error) {
+func (m *HiddenResourceConfig) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -1094,23 +1841,34 @@ func (m *MaxRequestsInflightFlowControlSchema) Marshal() (dAtA []byte, err error
 	return dAtA[:n], nil
 }
 
-func (m *MaxRequestsInflightFlowControlSchema) MarshalTo(dAtA []byte) (int, error) {
+func (m *HiddenResourceConfig) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *MaxRequestsInflightFlowControlSchema) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *HiddenResourceConfig) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	i = encodeVarintGenerated(dAtA, i, uint64(m.Max))
-	i--
-	dAtA[i] = 0x8
+	if len(m.Rules) > 0 {
+		for iNdEx := len(m.Rules) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Rules[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
 	return len(dAtA) - i, nil
 }
 
-func (m *SecretReferecence) Marshal() (dAtA []byte, err error) {
+func (m *ImpersonationPolicy) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -1120,74 +1878,89 @@ func (m *SecretReferecence) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *SecretReferecence) MarshalTo(dAtA []byte) (int, error) {
+func (m *ImpersonationPolicy) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *SecretReferecence) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *ImpersonationPolicy) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	i -= len(m.Name)
-	copy(dAtA[i:], m.Name)
-	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Name)))
-	i--
-	dAtA[i] = 0x12
-	i -= len(m.Namespace)
-	copy(dAtA[i:], m.Namespace)
-	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Namespace)))
-	i--
-	dAtA[i] = 0xa
-	return len(dAtA) - i, nil
-}
-
-func (m *SecureServing) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
+	if len(m.DeniedGroups) > 0 {
+		for iNdEx := len(m.DeniedGroups) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.DeniedGroups[iNdEx])
+			copy(dAtA[i:], m.DeniedGroups[iNdEx])
+			i = encodeVarintGenerated(dAtA, i, uint64(len(m.DeniedGroups[iNdEx])))
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if len(m.AllowedGroups) > 0 {
+		for iNdEx := len(m.AllowedGroups) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.AllowedGroups[iNdEx])
+			copy(dAtA[i:], m.AllowedGroups[iNdEx])
+			i = encodeVarintGenerated(dAtA, i, uint64(len(m.AllowedGroups[iNdEx])))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.DeniedUsers) > 0 {
+		for iNdEx := len(m.DeniedUsers) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.DeniedUsers[iNdEx])
+			copy(dAtA[i:], m.DeniedUsers[iNdEx])
+			i = encodeVarintGenerated(dAtA, i, uint64(len(m.DeniedUsers[iNdEx])))
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.AllowedUsers) > 0 {
+		for iNdEx := len(m.AllowedUsers) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.AllowedUsers[iNdEx])
+			copy(dAtA[i:], m.AllowedUsers[iNdEx])
+			i = encodeVarintGenerated(dAtA, i, uint64(len(m.AllowedUsers[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
 }
 
-func (m *SecureServing) MarshalTo(dAtA []byte) (int, error) {
+func (m *LoggingConfig) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LoggingConfig) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *SecureServing) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *LoggingConfig) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.ClientCAData != nil {
-		i -= len(m.ClientCAData)
-		copy(dAtA[i:], m.ClientCAData)
-		i = encodeVarintGenerated(dAtA, i, uint64(len(m.ClientCAData)))
-		i--
-		dAtA[i] = 0x1a
-	}
-	if m.CertData != nil {
-		i -= len(m.CertData)
-		copy(dAtA[i:], m.CertData)
-		i = encodeVarintGenerated(dAtA, i, uint64(len(m.CertData)))
-		i--
-		dAtA[i] = 0x12
-	}
-	if m.KeyData != nil {
-		i -= len(m.KeyData)
-		copy(dAtA[i:], m.KeyData)
-		i = encodeVarintGenerated(dAtA, i, uint64(len(m.KeyData)))
-		i--
-		dAtA[i] = 0xa
-	}
+	i -= len(m.UserGroups)
+	copy(dAtA[i:], m.UserGroups)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.UserGroups)))
+	i--
+	dAtA[i] = 0x12
+	i -= len(m.Mode)
+	copy(dAtA[i:], m.Mode)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Mode)))
+	i--
+	dAtA[i] = 0xa
 	return len(dAtA) - i, nil
 }
 
-func (m *ServiceAccountRef) Marshal() (dAtA []byte, err error) {
+func (m *MaxRequestsInflightByVerbFlowControlSchema) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -1197,30 +1970,26 @@ func (m *ServiceAccountRef) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *ServiceAccountRef) MarshalTo(dAtA []byte) (int, error) {
+func (m *MaxRequestsInflightByVerbFlowControlSchema) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *ServiceAccountRef) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *MaxRequestsInflightByVerbFlowControlSchema) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	i -= len(m.Namespace)
-	copy(dAtA[i:], m.Namespace)
-	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Namespace)))
+	i = encodeVarintGenerated(dAtA, i, uint64(m.MaxReadOnly))
 	i--
-	dAtA[i] = 0x12
-	i -= len(m.Name)
-	copy(dAtA[i:], m.Name)
-	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Name)))
+	dAtA[i] = 0x10
+	i = encodeVarintGenerated(dAtA, i, uint64(m.MaxMutating))
 	i--
-	dAtA[i] = 0xa
+	dAtA[i] = 0x8
 	return len(dAtA) - i, nil
 }
 
-func (m *TokenBucketFlowControlSchema) Marshal() (dAtA []byte, err error) {
+func (m *MaxRequestsInflightFlowControlSchema) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -1230,26 +1999,23 @@ func (m *TokenBucketFlowControlSchema) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *TokenBucketFlowControlSchema) MarshalTo(dAtA []byte) (int, error) {
+func (m *MaxRequestsInflightFlowControlSchema) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *TokenBucketFlowControlSchema) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *MaxRequestsInflightFlowControlSchema) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	i = encodeVarintGenerated(dAtA, i, uint64(m.Burst))
-	i--
-	dAtA[i] = 0x10
-	i = encodeVarintGenerated(dAtA, i, uint64(m.QPS))
+	i = encodeVarintGenerated(dAtA, i, uint64(m.Max))
 	i--
 	dAtA[i] = 0x8
 	return len(dAtA) - i, nil
 }
 
-func (m *UpstreamCluster) Marshal() (dAtA []byte, err error) {
+func (m *NamespaceFlowControlSchema) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -1259,50 +2025,34 @@ func (m *UpstreamCluster) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *UpstreamCluster) MarshalTo(dAtA []byte) (int, error) {
+func (m *NamespaceFlowControlSchema) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *UpstreamCluster) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *NamespaceFlowControlSchema) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	{
-		size, err := m.Status.MarshalToSizedBuffer(dAtA[:i])
-		if err != nil {
-			return 0, err
-		}
-		i -= size
-		i = encodeVarintGenerated(dAtA, i, uint64(size))
-	}
-	i--
-	dAtA[i] = 0x1a
-	{
-		size, err := m.Spec.MarshalToSizedBuffer(dAtA[:i])
-		if err != nil {
-			return 0, err
-		}
-		i -= size
-		i = encodeVarintGenerated(dAtA, i, uint64(size))
-	}
+	i -= len(m.FlowControlSchemaName)
+	copy(dAtA[i:], m.FlowControlSchemaName)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.FlowControlSchemaName)))
 	i--
 	dAtA[i] = 0x12
-	{
-		size, err := m.ObjectMeta.MarshalToSizedBuffer(dAtA[:i])
-		if err != nil {
-			return 0, err
+	if len(m.Namespaces) > 0 {
+		for iNdEx := len(m.Namespaces) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Namespaces[iNdEx])
+			copy(dAtA[i:], m.Namespaces[iNdEx])
+			i = encodeVarintGenerated(dAtA, i, uint64(len(m.Namespaces[iNdEx])))
+			i--
+			dAtA[i] = 0xa
 		}
-		i -= size
-		i = encodeVarintGenerated(dAtA, i, uint64(size))
 	}
-	i--
-	dAtA[i] = 0xa
 	return len(dAtA) - i, nil
 }
 
-func (m *UpstreamClusterList) Marshal() (dAtA []byte, err error) {
+func (m *OutlierDetectionPolicy) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -1312,44 +2062,32 @@ func (m *UpstreamClusterList) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *UpstreamClusterList) MarshalTo(dAtA []byte) (int, error) {
+func (m *OutlierDetectionPolicy) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *UpstreamClusterList) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *OutlierDetectionPolicy) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Items) > 0 {
-		for iNdEx := len(m.Items) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Items[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintGenerated(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x12
-		}
-	}
-	{
-		size, err := m.ListMeta.MarshalToSizedBuffer(dAtA[:i])
-		if err != nil {
-			return 0, err
-		}
-		i -= size
-		i = encodeVarintGenerated(dAtA, i, uint64(size))
-	}
+	i = encodeVarintGenerated(dAtA, i, uint64(m.HalfOpenPercent))
 	i--
-	dAtA[i] = 0xa
+	dAtA[i] = 0x20
+	i = encodeVarintGenerated(dAtA, i, uint64(m.EjectionSeconds))
+	i--
+	dAtA[i] = 0x18
+	i = encodeVarintGenerated(dAtA, i, uint64(m.IntervalSeconds))
+	i--
+	dAtA[i] = 0x10
+	i = encodeVarintGenerated(dAtA, i, uint64(m.ConsecutiveErrors))
+	i--
+	dAtA[i] = 0x8
 	return len(dAtA) - i, nil
 }
 
-func (m *UpstreamClusterServer) Marshal() (dAtA []byte, err error) {
+func (m *QueuedFlowControlSchema) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -1359,35 +2097,35 @@ func (m *UpstreamClusterServer) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *UpstreamClusterServer) MarshalTo(dAtA []byte) (int, error) {
+func (m *QueuedFlowControlSchema) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *UpstreamClusterServer) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *QueuedFlowControlSchema) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.Disabled != nil {
-		i--
-		if *m.Disabled {
-			dAtA[i] = 1
-		} else {
-			dAtA[i] = 0
-		}
-		i--
-		dAtA[i] = 0x10
-	}
-	i -= len(m.Endpoint)
-	copy(dAtA[i:], m.Endpoint)
-	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Endpoint)))
+	i = encodeVarintGenerated(dAtA, i, uint64(m.MaxWaitSeconds))
 	i--
-	dAtA[i] = 0xa
+	dAtA[i] = 0x28
+	i = encodeVarintGenerated(dAtA, i, uint64(m.HandSize))
+	i--
+	dAtA[i] = 0x20
+	i = encodeVarintGenerated(dAtA, i, uint64(m.Queues))
+	i--
+	dAtA[i] = 0x18
+	i = encodeVarintGenerated(dAtA, i, uint64(m.QueueLength))
+	i--
+	dAtA[i] = 0x10
+	i = encodeVarintGenerated(dAtA, i, uint64(m.Concurrency))
+	i--
+	dAtA[i] = 0x8
 	return len(dAtA) - i, nil
 }
 
-func (m *UpstreamClusterSpec) Marshal() (dAtA []byte, err error) {
+func (m *RejectionResponse) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -1397,88 +2135,49 @@ func (m *UpstreamClusterSpec) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *UpstreamClusterSpec) MarshalTo(dAtA []byte) (int, error) {
+func (m *RejectionResponse) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *UpstreamClusterSpec) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *RejectionResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	{
-		size, err := m.Logging.MarshalToSizedBuffer(dAtA[:i])
-		if err != nil {
-			return 0, err
+	if len(m.Headers) > 0 {
+		keysForHeaders := make([]string, 0, len(m.Headers))
+		for k := range m.Headers {
+			keysForHeaders = append(keysForHeaders, string(k))
 		}
-		i -= size
-		i = encodeVarintGenerated(dAtA, i, uint64(size))
-	}
-	i--
-	dAtA[i] = 0x32
-	if len(m.DispatchPolicies) > 0 {
-		for iNdEx := len(m.DispatchPolicies) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.DispatchPolicies[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintGenerated(dAtA, i, uint64(size))
-			}
+		github_com_gogo_protobuf_sortkeys.Strings(keysForHeaders)
+		for iNdEx := len(keysForHeaders) - 1; iNdEx >= 0; iNdEx-- {
+			v := m.Headers[string(keysForHeaders[iNdEx])]
+			baseI := i
+			i -= len(v)
+			copy(dAtA[i:], v)
+			i = encodeVarintGenerated(dAtA, i, uint64(len(v)))
 			i--
-			dAtA[i] = 0x2a
-		}
-	}
-	{
-		size, err := m.FlowControl.MarshalToSizedBuffer(dAtA[:i])
-		if err != nil {
-			return 0, err
-		}
-		i -= size
-		i = encodeVarintGenerated(dAtA, i, uint64(size))
-	}
-	i--
-	dAtA[i] = 0x22
-	{
-		size, err := m.SecureServing.MarshalToSizedBuffer(dAtA[:i])
-		if err != nil {
-			return 0, err
-		}
-		i -= size
-		i = encodeVarintGenerated(dAtA, i, uint64(size))
-	}
-	i--
-	dAtA[i] = 0x1a
-	{
-		size, err := m.ClientConfig.MarshalToSizedBuffer(dAtA[:i])
-		if err != nil {
-			return 0, err
-		}
-		i -= size
-		i = encodeVarintGenerated(dAtA, i, uint64(size))
-	}
-	i--
-	dAtA[i] = 0x12
-	if len(m.Servers) > 0 {
-		for iNdEx := len(m.Servers) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Servers[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintGenerated(dAtA, i, uint64(size))
-			}
+			dAtA[i] = 0x12
+			i -= len(keysForHeaders[iNdEx])
+			copy(dAtA[i:], keysForHeaders[iNdEx])
+			i = encodeVarintGenerated(dAtA, i, uint64(len(keysForHeaders[iNdEx])))
 			i--
 			dAtA[i] = 0xa
+			i = encodeVarintGenerated(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x12
 		}
 	}
+	i -= len(m.Message)
+	copy(dAtA[i:], m.Message)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Message)))
+	i--
+	dAtA[i] = 0xa
 	return len(dAtA) - i, nil
 }
 
-func (m *UpstreamClusterStatus) Marshal() (dAtA []byte, err error) {
+func (m *RetryPolicy) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -1488,622 +2187,4220 @@ func (m *UpstreamClusterStatus) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *UpstreamClusterStatus) MarshalTo(dAtA []byte) (int, error) {
+func (m *RetryPolicy) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *UpstreamClusterStatus) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *RetryPolicy) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	return len(dAtA) - i, nil
-}
-
-func encodeVarintGenerated(dAtA []byte, offset int, v uint64) int {
-	offset -= sovGenerated(v)
-	base := offset
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
-	}
-	dAtA[offset] = uint8(v)
-	return base
-}
-func (m *ClientConfig) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	n += 2
-	if m.BearerToken != nil {
-		l = len(m.BearerToken)
-		n += 1 + l + sovGenerated(uint64(l))
-	}
-	if m.KeyData != nil {
-		l = len(m.KeyData)
-		n += 1 + l + sovGenerated(uint64(l))
-	}
-	if m.CertData != nil {
-		l = len(m.CertData)
-		n += 1 + l + sovGenerated(uint64(l))
-	}
-	if m.CAData != nil {
-		l = len(m.CAData)
-		n += 1 + l + sovGenerated(uint64(l))
-	}
-	n += 1 + sovGenerated(uint64(m.QPS))
-	n += 1 + sovGenerated(uint64(m.Burst))
-	n += 1 + sovGenerated(uint64(m.QPSDivisor))
-	return n
-}
-
-func (m *DispatchPolicy) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	l = len(m.Strategy)
-	n += 1 + l + sovGenerated(uint64(l))
-	if len(m.UpstreamSubset) > 0 {
-		for _, s := range m.UpstreamSubset {
-			l = len(s)
-			n += 1 + l + sovGenerated(uint64(l))
-		}
-	}
-	if len(m.Rules) > 0 {
-		for _, e := range m.Rules {
-			l = e.Size()
-			n += 1 + l + sovGenerated(uint64(l))
-		}
-	}
-	l = len(m.FlowControlSchemaName)
-	n += 1 + l + sovGenerated(uint64(l))
-	l = len(m.LogMode)
-	n += 1 + l + sovGenerated(uint64(l))
-	return n
-}
-
-func (m *DispatchPolicyRule) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
+	i = encodeVarintGenerated(dAtA, i, uint64(m.MaxRetries))
+	i--
+	dAtA[i] = 0x40
 	if len(m.Verbs) > 0 {
-		for _, s := range m.Verbs {
-			l = len(s)
-			n += 1 + l + sovGenerated(uint64(l))
-		}
-	}
-	if len(m.APIGroups) > 0 {
-		for _, s := range m.APIGroups {
-			l = len(s)
-			n += 1 + l + sovGenerated(uint64(l))
-		}
-	}
-	if len(m.Resources) > 0 {
-		for _, s := range m.Resources {
-			l = len(s)
-			n += 1 + l + sovGenerated(uint64(l))
-		}
-	}
-	if len(m.ResourceNames) > 0 {
-		for _, s := range m.ResourceNames {
-			l = len(s)
-			n += 1 + l + sovGenerated(uint64(l))
+		for iNdEx := len(m.Verbs) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Verbs[iNdEx])
+			copy(dAtA[i:], m.Verbs[iNdEx])
+			i = encodeVarintGenerated(dAtA, i, uint64(len(m.Verbs[iNdEx])))
+			i--
+			dAtA[i] = 0x3a
 		}
 	}
-	if len(m.Users) > 0 {
-		for _, s := range m.Users {
-			l = len(s)
-			n += 1 + l + sovGenerated(uint64(l))
-		}
+	i--
+	if m.ConnectionErrors {
+		dAtA[i] = 1
+	} else {
+		dAtA[i] = 0
 	}
-	if len(m.ServiceAccounts) > 0 {
-		for _, e := range m.ServiceAccounts {
-			l = e.Size()
-			n += 1 + l + sovGenerated(uint64(l))
+	i--
+	dAtA[i] = 0x30
+	if len(m.StatusCodes) > 0 {
+		for iNdEx := len(m.StatusCodes) - 1; iNdEx >= 0; iNdEx-- {
+			i = encodeVarintGenerated(dAtA, i, uint64(m.StatusCodes[iNdEx]))
+			i--
+			dAtA[i] = 0x28
 		}
 	}
-	if len(m.UserGroups) > 0 {
-		for _, s := range m.UserGroups {
-			l = len(s)
-			n += 1 + l + sovGenerated(uint64(l))
+	i = encodeVarintGenerated(dAtA, i, uint64(m.TooManyRequestsBackoffSeconds))
+	i--
+	dAtA[i] = 0x20
+	i -= len(m.TooManyRequests)
+	copy(dAtA[i:], m.TooManyRequests)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.TooManyRequests)))
+	i--
+	dAtA[i] = 0x1a
+	if len(m.StatusMessages) > 0 {
+		for iNdEx := len(m.StatusMessages) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.StatusMessages[iNdEx])
+			copy(dAtA[i:], m.StatusMessages[iNdEx])
+			i = encodeVarintGenerated(dAtA, i, uint64(len(m.StatusMessages[iNdEx])))
+			i--
+			dAtA[i] = 0x12
 		}
 	}
-	if len(m.NonResourceURLs) > 0 {
-		for _, s := range m.NonResourceURLs {
-			l = len(s)
-			n += 1 + l + sovGenerated(uint64(l))
+	if len(m.StatusReasons) > 0 {
+		for iNdEx := len(m.StatusReasons) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.StatusReasons[iNdEx])
+			copy(dAtA[i:], m.StatusReasons[iNdEx])
+			i = encodeVarintGenerated(dAtA, i, uint64(len(m.StatusReasons[iNdEx])))
+			i--
+			dAtA[i] = 0xa
 		}
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *ExemptFlowControlSchema) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *SecretReferecence) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	var l int
-	_ = l
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *FlowControl) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if len(m.Schemas) > 0 {
-		for _, e := range m.Schemas {
-			l = e.Size()
-			n += 1 + l + sovGenerated(uint64(l))
-		}
-	}
-	return n
+func (m *SecretReferecence) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *FlowControlSchema) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+func (m *SecretReferecence) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Name)
-	n += 1 + l + sovGenerated(uint64(l))
-	l = m.FlowControlSchemaConfiguration.Size()
-	n += 1 + l + sovGenerated(uint64(l))
-	return n
+	i -= len(m.Name)
+	copy(dAtA[i:], m.Name)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Name)))
+	i--
+	dAtA[i] = 0x12
+	i -= len(m.Namespace)
+	copy(dAtA[i:], m.Namespace)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Namespace)))
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
 }
 
-func (m *FlowControlSchemaConfiguration) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *SecureServing) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *SecureServing) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SecureServing) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if m.Exempt != nil {
-		l = m.Exempt.Size()
-		n += 1 + l + sovGenerated(uint64(l))
+	if len(m.APIAudiences) > 0 {
+		for iNdEx := len(m.APIAudiences) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.APIAudiences[iNdEx])
+			copy(dAtA[i:], m.APIAudiences[iNdEx])
+			i = encodeVarintGenerated(dAtA, i, uint64(len(m.APIAudiences[iNdEx])))
+			i--
+			dAtA[i] = 0x2a
+		}
 	}
-	if m.MaxRequestsInflight != nil {
-		l = m.MaxRequestsInflight.Size()
-		n += 1 + l + sovGenerated(uint64(l))
+	i -= len(m.ClientAuth)
+	copy(dAtA[i:], m.ClientAuth)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.ClientAuth)))
+	i--
+	dAtA[i] = 0x22
+	if m.ClientCAData != nil {
+		i -= len(m.ClientCAData)
+		copy(dAtA[i:], m.ClientCAData)
+		i = encodeVarintGenerated(dAtA, i, uint64(len(m.ClientCAData)))
+		i--
+		dAtA[i] = 0x1a
 	}
-	if m.TokenBucket != nil {
-		l = m.TokenBucket.Size()
-		n += 1 + l + sovGenerated(uint64(l))
+	if m.CertData != nil {
+		i -= len(m.CertData)
+		copy(dAtA[i:], m.CertData)
+		i = encodeVarintGenerated(dAtA, i, uint64(len(m.CertData)))
+		i--
+		dAtA[i] = 0x12
 	}
-	return n
-}
-
-func (m *LoggingConfig) Size() (n int) {
-	if m == nil {
-		return 0
+	if m.KeyData != nil {
+		i -= len(m.KeyData)
+		copy(dAtA[i:], m.KeyData)
+		i = encodeVarintGenerated(dAtA, i, uint64(len(m.KeyData)))
+		i--
+		dAtA[i] = 0xa
 	}
-	var l int
-	_ = l
-	l = len(m.Mode)
-	n += 1 + l + sovGenerated(uint64(l))
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *MaxRequestsInflightFlowControlSchema) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *ServiceAccountRef) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	var l int
-	_ = l
-	n += 1 + sovGenerated(uint64(m.Max))
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *SecretReferecence) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+func (m *ServiceAccountRef) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ServiceAccountRef) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Namespace)
-	n += 1 + l + sovGenerated(uint64(l))
-	l = len(m.Name)
-	n += 1 + l + sovGenerated(uint64(l))
-	return n
+	i -= len(m.Namespace)
+	copy(dAtA[i:], m.Namespace)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Namespace)))
+	i--
+	dAtA[i] = 0x12
+	i -= len(m.Name)
+	copy(dAtA[i:], m.Name)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Name)))
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
 }
 
-func (m *SecureServing) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *ShadowConfig) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *ShadowConfig) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ShadowConfig) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if m.KeyData != nil {
-		l = len(m.KeyData)
-		n += 1 + l + sovGenerated(uint64(l))
-	}
-	if m.CertData != nil {
-		l = len(m.CertData)
-		n += 1 + l + sovGenerated(uint64(l))
-	}
-	if m.ClientCAData != nil {
-		l = len(m.ClientCAData)
-		n += 1 + l + sovGenerated(uint64(l))
+	i--
+	if m.CompareResponses {
+		dAtA[i] = 1
+	} else {
+		dAtA[i] = 0
 	}
-	return n
+	i--
+	dAtA[i] = 0x18
+	i = encodeVarintGenerated(dAtA, i, uint64(m.Percentage))
+	i--
+	dAtA[i] = 0x10
+	i -= len(m.Cluster)
+	copy(dAtA[i:], m.Cluster)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Cluster)))
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
 }
 
-func (m *ServiceAccountRef) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *StubConfig) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	var l int
-	_ = l
-	l = len(m.Name)
-	n += 1 + l + sovGenerated(uint64(l))
-	l = len(m.Namespace)
-	n += 1 + l + sovGenerated(uint64(l))
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *TokenBucketFlowControlSchema) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	n += 1 + sovGenerated(uint64(m.QPS))
-	n += 1 + sovGenerated(uint64(m.Burst))
-	return n
+func (m *StubConfig) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *UpstreamCluster) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+func (m *StubConfig) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = m.ObjectMeta.Size()
-	n += 1 + l + sovGenerated(uint64(l))
-	l = m.Spec.Size()
-	n += 1 + l + sovGenerated(uint64(l))
-	l = m.Status.Size()
-	n += 1 + l + sovGenerated(uint64(l))
-	return n
+	i = encodeVarintGenerated(dAtA, i, uint64(m.RetryAfterSeconds))
+	i--
+	dAtA[i] = 0x10
+	i -= len(m.Message)
+	copy(dAtA[i:], m.Message)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Message)))
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
 }
 
-func (m *UpstreamClusterList) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	l = m.ListMeta.Size()
-	n += 1 + l + sovGenerated(uint64(l))
-	if len(m.Items) > 0 {
-		for _, e := range m.Items {
-			l = e.Size()
-			n += 1 + l + sovGenerated(uint64(l))
-		}
+func (m *TokenBucketFlowControlSchema) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *UpstreamClusterServer) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+func (m *TokenBucketFlowControlSchema) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TokenBucketFlowControlSchema) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Endpoint)
-	n += 1 + l + sovGenerated(uint64(l))
-	if m.Disabled != nil {
-		n += 2
-	}
-	return n
+	i -= len(m.KeyBy)
+	copy(dAtA[i:], m.KeyBy)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.KeyBy)))
+	i--
+	dAtA[i] = 0x22
+	i = encodeVarintGenerated(dAtA, i, uint64(m.SlowStartSeconds))
+	i--
+	dAtA[i] = 0x18
+	i = encodeVarintGenerated(dAtA, i, uint64(m.Burst))
+	i--
+	dAtA[i] = 0x10
+	i = encodeVarintGenerated(dAtA, i, uint64(m.QPS))
+	i--
+	dAtA[i] = 0x8
+	return len(dAtA) - i, nil
 }
 
-func (m *UpstreamClusterSpec) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *UpstreamCluster) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *UpstreamCluster) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *UpstreamCluster) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if len(m.Servers) > 0 {
-		for _, e := range m.Servers {
-			l = e.Size()
-			n += 1 + l + sovGenerated(uint64(l))
+	{
+		size, err := m.Status.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
 		}
+		i -= size
+		i = encodeVarintGenerated(dAtA, i, uint64(size))
 	}
-	l = m.ClientConfig.Size()
-	n += 1 + l + sovGenerated(uint64(l))
-	l = m.SecureServing.Size()
-	n += 1 + l + sovGenerated(uint64(l))
-	l = m.FlowControl.Size()
-	n += 1 + l + sovGenerated(uint64(l))
-	if len(m.DispatchPolicies) > 0 {
-		for _, e := range m.DispatchPolicies {
-			l = e.Size()
-			n += 1 + l + sovGenerated(uint64(l))
+	i--
+	dAtA[i] = 0x1a
+	{
+		size, err := m.Spec.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintGenerated(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x12
+	{
+		size, err := m.ObjectMeta.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
 		}
+		i -= size
+		i = encodeVarintGenerated(dAtA, i, uint64(size))
 	}
-	l = m.Logging.Size()
-	n += 1 + l + sovGenerated(uint64(l))
-	return n
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
 }
 
-func (m *UpstreamClusterStatus) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *UpstreamClusterList) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	var l int
-	_ = l
-	return n
+	return dAtA[:n], nil
 }
 
-func sovGenerated(x uint64) (n int) {
-	return (math_bits.Len64(x|1) + 6) / 7
-}
-func sozGenerated(x uint64) (n int) {
-	return sovGenerated(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+func (m *UpstreamClusterList) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
-func (this *ClientConfig) String() string {
-	if this == nil {
-		return "nil"
-	}
-	s := strings.Join([]string{`&ClientConfig{`,
-		`Insecure:` + fmt.Sprintf("%v", this.Insecure) + `,`,
-		`BearerToken:` + valueToStringGenerated(this.BearerToken) + `,`,
-		`KeyData:` + valueToStringGenerated(this.KeyData) + `,`,
-		`CertData:` + valueToStringGenerated(this.CertData) + `,`,
-		`CAData:` + valueToStringGenerated(this.CAData) + `,`,
-		`QPS:` + fmt.Sprintf("%v", this.QPS) + `,`,
-		`Burst:` + fmt.Sprintf("%v", this.Burst) + `,`,
-		`QPSDivisor:` + fmt.Sprintf("%v", this.QPSDivisor) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *DispatchPolicy) String() string {
-	if this == nil {
-		return "nil"
+
+func (m *UpstreamClusterList) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Items) > 0 {
+		for iNdEx := len(m.Items) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Items[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
 	}
-	repeatedStringForRules := "[]DispatchPolicyRule{"
-	for _, f := range this.Rules {
-		repeatedStringForRules += strings.Replace(strings.Replace(f.String(), "DispatchPolicyRule", "DispatchPolicyRule", 1), `&`, ``, 1) + ","
+	{
+		size, err := m.ListMeta.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintGenerated(dAtA, i, uint64(size))
 	}
-	repeatedStringForRules += "}"
-	s := strings.Join([]string{`&DispatchPolicy{`,
-		`Strategy:` + fmt.Sprintf("%v", this.Strategy) + `,`,
-		`UpstreamSubset:` + fmt.Sprintf("%v", this.UpstreamSubset) + `,`,
-		`Rules:` + repeatedStringForRules + `,`,
-		`FlowControlSchemaName:` + fmt.Sprintf("%v", this.FlowControlSchemaName) + `,`,
-		`LogMode:` + fmt.Sprintf("%v", this.LogMode) + `,`,
-		`}`,
-	}, "")
-	return s
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
 }
-func (this *DispatchPolicyRule) String() string {
-	if this == nil {
-		return "nil"
-	}
-	repeatedStringForServiceAccounts := "[]ServiceAccountRef{"
-	for _, f := range this.ServiceAccounts {
-		repeatedStringForServiceAccounts += strings.Replace(strings.Replace(f.String(), "ServiceAccountRef", "ServiceAccountRef", 1), `&`, ``, 1) + ","
+
+func (m *UpstreamClusterServer) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	repeatedStringForServiceAccounts += "}"
-	s := strings.Join([]string{`&DispatchPolicyRule{`,
-		`Verbs:` + fmt.Sprintf("%v", this.Verbs) + `,`,
-		`APIGroups:` + fmt.Sprintf("%v", this.APIGroups) + `,`,
-		`Resources:` + fmt.Sprintf("%v", this.Resources) + `,`,
-		`ResourceNames:` + fmt.Sprintf("%v", this.ResourceNames) + `,`,
-		`Users:` + fmt.Sprintf("%v", this.Users) + `,`,
-		`ServiceAccounts:` + repeatedStringForServiceAccounts + `,`,
-		`UserGroups:` + fmt.Sprintf("%v", this.UserGroups) + `,`,
-		`NonResourceURLs:` + fmt.Sprintf("%v", this.NonResourceURLs) + `,`,
-		`}`,
-	}, "")
-	return s
+	return dAtA[:n], nil
 }
-func (this *ExemptFlowControlSchema) String() string {
-	if this == nil {
-		return "nil"
-	}
-	s := strings.Join([]string{`&ExemptFlowControlSchema{`,
-		`}`,
-	}, "")
-	return s
+
+func (m *UpstreamClusterServer) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
-func (this *FlowControl) String() string {
-	if this == nil {
-		return "nil"
+
+func (m *UpstreamClusterServer) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Weight != nil {
+		i = encodeVarintGenerated(dAtA, i, uint64(*m.Weight))
+		i--
+		dAtA[i] = 0x18
 	}
-	repeatedStringForSchemas := "[]FlowControlSchema{"
-	for _, f := range this.Schemas {
-		repeatedStringForSchemas += strings.Replace(strings.Replace(f.String(), "FlowControlSchema", "FlowControlSchema", 1), `&`, ``, 1) + ","
+	if m.Disabled != nil {
+		i--
+		if *m.Disabled {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x10
 	}
-	repeatedStringForSchemas += "}"
-	s := strings.Join([]string{`&FlowControl{`,
-		`Schemas:` + repeatedStringForSchemas + `,`,
-		`}`,
-	}, "")
-	return s
+	i -= len(m.Endpoint)
+	copy(dAtA[i:], m.Endpoint)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.Endpoint)))
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
 }
-func (this *FlowControlSchema) String() string {
-	if this == nil {
-		return "nil"
+
+func (m *UpstreamClusterSpec) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	s := strings.Join([]string{`&FlowControlSchema{`,
-		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
-		`FlowControlSchemaConfiguration:` + strings.Replace(strings.Replace(this.FlowControlSchemaConfiguration.String(), "FlowControlSchemaConfiguration", "FlowControlSchemaConfiguration", 1), `&`, ``, 1) + `,`,
-		`}`,
-	}, "")
-	return s
+	return dAtA[:n], nil
 }
-func (this *FlowControlSchemaConfiguration) String() string {
-	if this == nil {
-		return "nil"
-	}
-	s := strings.Join([]string{`&FlowControlSchemaConfiguration{`,
-		`Exempt:` + strings.Replace(this.Exempt.String(), "ExemptFlowControlSchema", "ExemptFlowControlSchema", 1) + `,`,
-		`MaxRequestsInflight:` + strings.Replace(this.MaxRequestsInflight.String(), "MaxRequestsInflightFlowControlSchema", "MaxRequestsInflightFlowControlSchema", 1) + `,`,
-		`TokenBucket:` + strings.Replace(this.TokenBucket.String(), "TokenBucketFlowControlSchema", "TokenBucketFlowControlSchema", 1) + `,`,
-		`}`,
-	}, "")
-	return s
+
+func (m *UpstreamClusterSpec) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
-func (this *LoggingConfig) String() string {
-	if this == nil {
-		return "nil"
+
+func (m *UpstreamClusterSpec) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Impersonation != nil {
+		{
+			size, err := m.Impersonation.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintGenerated(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x82
 	}
-	s := strings.Join([]string{`&LoggingConfig{`,
-		`Mode:` + fmt.Sprintf("%v", this.Mode) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *MaxRequestsInflightFlowControlSchema) String() string {
-	if this == nil {
-		return "nil"
+	if m.OutlierDetection != nil {
+		{
+			size, err := m.OutlierDetection.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintGenerated(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x7a
 	}
-	s := strings.Join([]string{`&MaxRequestsInflightFlowControlSchema{`,
-		`Max:` + fmt.Sprintf("%v", this.Max) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *SecretReferecence) String() string {
-	if this == nil {
-		return "nil"
+	if m.HiddenResources != nil {
+		{
+			size, err := m.HiddenResources.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintGenerated(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x72
 	}
-	s := strings.Join([]string{`&SecretReferecence{`,
-		`Namespace:` + fmt.Sprintf("%v", this.Namespace) + `,`,
-		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *SecureServing) String() string {
-	if this == nil {
-		return "nil"
+	if m.HealthCheck != nil {
+		{
+			size, err := m.HealthCheck.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintGenerated(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x6a
 	}
-	s := strings.Join([]string{`&SecureServing{`,
-		`KeyData:` + valueToStringGenerated(this.KeyData) + `,`,
-		`CertData:` + valueToStringGenerated(this.CertData) + `,`,
-		`ClientCAData:` + valueToStringGenerated(this.ClientCAData) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *ServiceAccountRef) String() string {
-	if this == nil {
-		return "nil"
+	if m.APIResources != nil {
+		{
+			size, err := m.APIResources.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintGenerated(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x62
 	}
-	s := strings.Join([]string{`&ServiceAccountRef{`,
-		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
-		`Namespace:` + fmt.Sprintf("%v", this.Namespace) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *TokenBucketFlowControlSchema) String() string {
-	if this == nil {
-		return "nil"
+	if m.Stub != nil {
+		{
+			size, err := m.Stub.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintGenerated(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x5a
 	}
-	s := strings.Join([]string{`&TokenBucketFlowControlSchema{`,
-		`QPS:` + fmt.Sprintf("%v", this.QPS) + `,`,
-		`Burst:` + fmt.Sprintf("%v", this.Burst) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *UpstreamCluster) String() string {
-	if this == nil {
-		return "nil"
+	if m.Retry != nil {
+		{
+			size, err := m.Retry.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintGenerated(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x52
 	}
-	s := strings.Join([]string{`&UpstreamCluster{`,
-		`ObjectMeta:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.ObjectMeta), "ObjectMeta", "v1.ObjectMeta", 1), `&`, ``, 1) + `,`,
-		`Spec:` + strings.Replace(strings.Replace(this.Spec.String(), "UpstreamClusterSpec", "UpstreamClusterSpec", 1), `&`, ``, 1) + `,`,
-		`Status:` + strings.Replace(strings.Replace(this.Status.String(), "UpstreamClusterStatus", "UpstreamClusterStatus", 1), `&`, ``, 1) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *UpstreamClusterList) String() string {
-	if this == nil {
-		return "nil"
+	if m.Shadow != nil {
+		{
+			size, err := m.Shadow.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintGenerated(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x4a
 	}
-	repeatedStringForItems := "[]UpstreamCluster{"
-	for _, f := range this.Items {
-		repeatedStringForItems += strings.Replace(strings.Replace(f.String(), "UpstreamCluster", "UpstreamCluster", 1), `&`, ``, 1) + ","
+	if m.Disabled != nil {
+		i--
+		if *m.Disabled {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x40
 	}
-	repeatedStringForItems += "}"
-	s := strings.Join([]string{`&UpstreamClusterList{`,
-		`ListMeta:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.ListMeta), "ListMeta", "v1.ListMeta", 1), `&`, ``, 1) + `,`,
-		`Items:` + repeatedStringForItems + `,`,
-		`}`,
-	}, "")
-	return s
+	i = encodeVarintGenerated(dAtA, i, uint64(m.MinHealthyEndpoints))
+	i--
+	dAtA[i] = 0x38
+	{
+		size, err := m.Logging.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintGenerated(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x32
+	if len(m.DispatchPolicies) > 0 {
+		for iNdEx := len(m.DispatchPolicies) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.DispatchPolicies[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
+	{
+		size, err := m.FlowControl.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintGenerated(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x22
+	{
+		size, err := m.SecureServing.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintGenerated(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x1a
+	{
+		size, err := m.ClientConfig.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintGenerated(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x12
+	if len(m.Servers) > 0 {
+		for iNdEx := len(m.Servers) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Servers[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintGenerated(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
 }
-func (this *UpstreamClusterServer) String() string {
-	if this == nil {
-		return "nil"
+
+func (m *UpstreamClusterStatus) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	s := strings.Join([]string{`&UpstreamClusterServer{`,
-		`Endpoint:` + fmt.Sprintf("%v", this.Endpoint) + `,`,
-		`Disabled:` + valueToStringGenerated(this.Disabled) + `,`,
-		`}`,
-	}, "")
-	return s
+	return dAtA[:n], nil
 }
-func (this *UpstreamClusterSpec) String() string {
-	if this == nil {
-		return "nil"
+
+func (m *UpstreamClusterStatus) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *UpstreamClusterStatus) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
+func (m *UserAgentFlowControlSchema) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	repeatedStringForServers := "[]UpstreamClusterServer{"
-	for _, f := range this.Servers {
-		repeatedStringForServers += strings.Replace(strings.Replace(f.String(), "UpstreamClusterServer", "UpstreamClusterServer", 1), `&`, ``, 1) + ","
+	return dAtA[:n], nil
+}
+
+func (m *UserAgentFlowControlSchema) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *UserAgentFlowControlSchema) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	i -= len(m.FlowControlSchemaName)
+	copy(dAtA[i:], m.FlowControlSchemaName)
+	i = encodeVarintGenerated(dAtA, i, uint64(len(m.FlowControlSchemaName)))
+	i--
+	dAtA[i] = 0x12
+	if len(m.UserAgentPrefixes) > 0 {
+		for iNdEx := len(m.UserAgentPrefixes) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.UserAgentPrefixes[iNdEx])
+			copy(dAtA[i:], m.UserAgentPrefixes[iNdEx])
+			i = encodeVarintGenerated(dAtA, i, uint64(len(m.UserAgentPrefixes[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+		}
 	}
-	repeatedStringForServers += "}"
-	repeatedStringForDispatchPolicies := "[]DispatchPolicy{"
-	for _, f := range this.DispatchPolicies {
-		repeatedStringForDispatchPolicies += strings.Replace(strings.Replace(f.String(), "DispatchPolicy", "DispatchPolicy", 1), `&`, ``, 1) + ","
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintGenerated(dAtA []byte, offset int, v uint64) int {
+	offset -= sovGenerated(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
 	}
-	repeatedStringForDispatchPolicies += "}"
-	s := strings.Join([]string{`&UpstreamClusterSpec{`,
-		`Servers:` + repeatedStringForServers + `,`,
-		`ClientConfig:` + strings.Replace(strings.Replace(this.ClientConfig.String(), "ClientConfig", "ClientConfig", 1), `&`, ``, 1) + `,`,
-		`SecureServing:` + strings.Replace(strings.Replace(this.SecureServing.String(), "SecureServing", "SecureServing", 1), `&`, ``, 1) + `,`,
-		`FlowControl:` + strings.Replace(strings.Replace(this.FlowControl.String(), "FlowControl", "FlowControl", 1), `&`, ``, 1) + `,`,
-		`DispatchPolicies:` + repeatedStringForDispatchPolicies + `,`,
-		`Logging:` + strings.Replace(strings.Replace(this.Logging.String(), "LoggingConfig", "LoggingConfig", 1), `&`, ``, 1) + `,`,
-		`}`,
-	}, "")
-	return s
+	dAtA[offset] = uint8(v)
+	return base
 }
-func (this *UpstreamClusterStatus) String() string {
-	if this == nil {
-		return "nil"
+func (m *APIResourceConfig) Size() (n int) {
+	if m == nil {
+		return 0
 	}
-	s := strings.Join([]string{`&UpstreamClusterStatus{`,
-		`}`,
-	}, "")
-	return s
+	var l int
+	_ = l
+	if len(m.Rules) > 0 {
+		for _, e := range m.Rules {
+			l = e.Size()
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	return n
 }
-func valueToStringGenerated(v interface{}) string {
-	rv := reflect.ValueOf(v)
-	if rv.IsNil() {
-		return "nil"
+
+func (m *APIResourceRule) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.APIGroups) > 0 {
+		for _, s := range m.APIGroups {
+			l = len(s)
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	if len(m.APIVersions) > 0 {
+		for _, s := range m.APIVersions {
+			l = len(s)
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	if len(m.Resources) > 0 {
+		for _, s := range m.Resources {
+			l = len(s)
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *ClientConfig) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	n += 2
+	if m.BearerToken != nil {
+		l = len(m.BearerToken)
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	if m.KeyData != nil {
+		l = len(m.KeyData)
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	if m.CertData != nil {
+		l = len(m.CertData)
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	if m.CAData != nil {
+		l = len(m.CAData)
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	n += 1 + sovGenerated(uint64(m.QPS))
+	n += 1 + sovGenerated(uint64(m.Burst))
+	n += 1 + sovGenerated(uint64(m.QPSDivisor))
+	n += 1 + sovGenerated(uint64(m.TLSSessionCacheSize))
+	l = len(m.TLSRenegotiation)
+	n += 1 + l + sovGenerated(uint64(l))
+	n += 2
+	n += 2
+	l = len(m.SourceAddress)
+	n += 1 + l + sovGenerated(uint64(l))
+	return n
+}
+
+func (m *DispatchPolicy) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Strategy)
+	n += 1 + l + sovGenerated(uint64(l))
+	if len(m.UpstreamSubset) > 0 {
+		for _, s := range m.UpstreamSubset {
+			l = len(s)
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	if len(m.Rules) > 0 {
+		for _, e := range m.Rules {
+			l = e.Size()
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	l = len(m.FlowControlSchemaName)
+	n += 1 + l + sovGenerated(uint64(l))
+	l = len(m.LogMode)
+	n += 1 + l + sovGenerated(uint64(l))
+	l = len(m.Name)
+	n += 1 + l + sovGenerated(uint64(l))
+	return n
+}
+
+func (m *DispatchPolicyRule) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Verbs) > 0 {
+		for _, s := range m.Verbs {
+			l = len(s)
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	if len(m.APIGroups) > 0 {
+		for _, s := range m.APIGroups {
+			l = len(s)
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	if len(m.Resources) > 0 {
+		for _, s := range m.Resources {
+			l = len(s)
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	if len(m.ResourceNames) > 0 {
+		for _, s := range m.ResourceNames {
+			l = len(s)
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	if len(m.Users) > 0 {
+		for _, s := range m.Users {
+			l = len(s)
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	if len(m.ServiceAccounts) > 0 {
+		for _, e := range m.ServiceAccounts {
+			l = e.Size()
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	if len(m.UserGroups) > 0 {
+		for _, s := range m.UserGroups {
+			l = len(s)
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	if len(m.NonResourceURLs) > 0 {
+		for _, s := range m.NonResourceURLs {
+			l = len(s)
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *ExemptFlowControlSchema) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	n += 1 + sovGenerated(uint64(m.MaxRequestsInflight))
+	return n
+}
+
+func (m *FlowControl) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Schemas) > 0 {
+		for _, e := range m.Schemas {
+			l = e.Size()
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	if len(m.NamespaceSchemas) > 0 {
+		for _, e := range m.NamespaceSchemas {
+			l = e.Size()
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	if m.RejectionResponse != nil {
+		l = m.RejectionResponse.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	if len(m.UserAgentSchemas) > 0 {
+		for _, e := range m.UserAgentSchemas {
+			l = e.Size()
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *FlowControlSchema) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	n += 1 + l + sovGenerated(uint64(l))
+	l = m.FlowControlSchemaConfiguration.Size()
+	n += 1 + l + sovGenerated(uint64(l))
+	n += 1 + sovGenerated(uint64(m.RejectionStatusCode))
+	if m.RejectionResponse != nil {
+		l = m.RejectionResponse.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	return n
+}
+
+func (m *FlowControlSchemaConfiguration) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Exempt != nil {
+		l = m.Exempt.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	if m.MaxRequestsInflight != nil {
+		l = m.MaxRequestsInflight.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	if m.TokenBucket != nil {
+		l = m.TokenBucket.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	if m.MaxRequestsInflightByVerb != nil {
+		l = m.MaxRequestsInflightByVerb.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	if m.Queued != nil {
+		l = m.Queued.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	return n
+}
+
+func (m *HealthCheckPolicy) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	n += 1 + sovGenerated(uint64(m.SuccessRateWindow))
+	n += 1 + sovGenerated(uint64(m.MinSuccessPercent))
+	n += 1 + sovGenerated(uint64(m.IntervalMilliseconds))
+	n += 1 + sovGenerated(uint64(m.TimeoutMilliseconds))
+	l = len(m.Path)
+	n += 1 + l + sovGenerated(uint64(l))
+	n += 2
+	n += 1 + sovGenerated(uint64(m.UnhealthyThreshold))
+	n += 1 + sovGenerated(uint64(m.HealthyThreshold))
+	n += 1 + sovGenerated(uint64(m.MaxBackoffMilliseconds))
+	return n
+}
+
+func (m *HiddenResourceConfig) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Rules) > 0 {
+		for _, e := range m.Rules {
+			l = e.Size()
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *ImpersonationPolicy) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.AllowedUsers) > 0 {
+		for _, s := range m.AllowedUsers {
+			l = len(s)
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	if len(m.DeniedUsers) > 0 {
+		for _, s := range m.DeniedUsers {
+			l = len(s)
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	if len(m.AllowedGroups) > 0 {
+		for _, s := range m.AllowedGroups {
+			l = len(s)
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	if len(m.DeniedGroups) > 0 {
+		for _, s := range m.DeniedGroups {
+			l = len(s)
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *LoggingConfig) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Mode)
+	n += 1 + l + sovGenerated(uint64(l))
+	l = len(m.UserGroups)
+	n += 1 + l + sovGenerated(uint64(l))
+	return n
+}
+
+func (m *MaxRequestsInflightByVerbFlowControlSchema) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	n += 1 + sovGenerated(uint64(m.MaxMutating))
+	n += 1 + sovGenerated(uint64(m.MaxReadOnly))
+	return n
+}
+
+func (m *MaxRequestsInflightFlowControlSchema) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	n += 1 + sovGenerated(uint64(m.Max))
+	return n
+}
+
+func (m *NamespaceFlowControlSchema) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Namespaces) > 0 {
+		for _, s := range m.Namespaces {
+			l = len(s)
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	l = len(m.FlowControlSchemaName)
+	n += 1 + l + sovGenerated(uint64(l))
+	return n
+}
+
+func (m *OutlierDetectionPolicy) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	n += 1 + sovGenerated(uint64(m.ConsecutiveErrors))
+	n += 1 + sovGenerated(uint64(m.IntervalSeconds))
+	n += 1 + sovGenerated(uint64(m.EjectionSeconds))
+	n += 1 + sovGenerated(uint64(m.HalfOpenPercent))
+	return n
+}
+
+func (m *QueuedFlowControlSchema) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	n += 1 + sovGenerated(uint64(m.Concurrency))
+	n += 1 + sovGenerated(uint64(m.QueueLength))
+	n += 1 + sovGenerated(uint64(m.Queues))
+	n += 1 + sovGenerated(uint64(m.HandSize))
+	n += 1 + sovGenerated(uint64(m.MaxWaitSeconds))
+	return n
+}
+
+func (m *RejectionResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Message)
+	n += 1 + l + sovGenerated(uint64(l))
+	if len(m.Headers) > 0 {
+		for k, v := range m.Headers {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovGenerated(uint64(len(k))) + 1 + len(v) + sovGenerated(uint64(len(v)))
+			n += mapEntrySize + 1 + sovGenerated(uint64(mapEntrySize))
+		}
+	}
+	return n
+}
+
+func (m *RetryPolicy) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.StatusReasons) > 0 {
+		for _, s := range m.StatusReasons {
+			l = len(s)
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	if len(m.StatusMessages) > 0 {
+		for _, s := range m.StatusMessages {
+			l = len(s)
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	l = len(m.TooManyRequests)
+	n += 1 + l + sovGenerated(uint64(l))
+	n += 1 + sovGenerated(uint64(m.TooManyRequestsBackoffSeconds))
+	if len(m.StatusCodes) > 0 {
+		for _, e := range m.StatusCodes {
+			n += 1 + sovGenerated(uint64(e))
+		}
+	}
+	n += 2
+	if len(m.Verbs) > 0 {
+		for _, s := range m.Verbs {
+			l = len(s)
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	n += 1 + sovGenerated(uint64(m.MaxRetries))
+	return n
+}
+
+func (m *SecretReferecence) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Namespace)
+	n += 1 + l + sovGenerated(uint64(l))
+	l = len(m.Name)
+	n += 1 + l + sovGenerated(uint64(l))
+	return n
+}
+
+func (m *SecureServing) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.KeyData != nil {
+		l = len(m.KeyData)
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	if m.CertData != nil {
+		l = len(m.CertData)
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	if m.ClientCAData != nil {
+		l = len(m.ClientCAData)
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	l = len(m.ClientAuth)
+	n += 1 + l + sovGenerated(uint64(l))
+	if len(m.APIAudiences) > 0 {
+		for _, s := range m.APIAudiences {
+			l = len(s)
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *ServiceAccountRef) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	n += 1 + l + sovGenerated(uint64(l))
+	l = len(m.Namespace)
+	n += 1 + l + sovGenerated(uint64(l))
+	return n
+}
+
+func (m *ShadowConfig) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Cluster)
+	n += 1 + l + sovGenerated(uint64(l))
+	n += 1 + sovGenerated(uint64(m.Percentage))
+	n += 2
+	return n
+}
+
+func (m *StubConfig) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Message)
+	n += 1 + l + sovGenerated(uint64(l))
+	n += 1 + sovGenerated(uint64(m.RetryAfterSeconds))
+	return n
+}
+
+func (m *TokenBucketFlowControlSchema) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	n += 1 + sovGenerated(uint64(m.QPS))
+	n += 1 + sovGenerated(uint64(m.Burst))
+	n += 1 + sovGenerated(uint64(m.SlowStartSeconds))
+	l = len(m.KeyBy)
+	n += 1 + l + sovGenerated(uint64(l))
+	return n
+}
+
+func (m *UpstreamCluster) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.ObjectMeta.Size()
+	n += 1 + l + sovGenerated(uint64(l))
+	l = m.Spec.Size()
+	n += 1 + l + sovGenerated(uint64(l))
+	l = m.Status.Size()
+	n += 1 + l + sovGenerated(uint64(l))
+	return n
+}
+
+func (m *UpstreamClusterList) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.ListMeta.Size()
+	n += 1 + l + sovGenerated(uint64(l))
+	if len(m.Items) > 0 {
+		for _, e := range m.Items {
+			l = e.Size()
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *UpstreamClusterServer) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Endpoint)
+	n += 1 + l + sovGenerated(uint64(l))
+	if m.Disabled != nil {
+		n += 2
+	}
+	if m.Weight != nil {
+		n += 1 + sovGenerated(uint64(*m.Weight))
+	}
+	return n
+}
+
+func (m *UpstreamClusterSpec) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Servers) > 0 {
+		for _, e := range m.Servers {
+			l = e.Size()
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	l = m.ClientConfig.Size()
+	n += 1 + l + sovGenerated(uint64(l))
+	l = m.SecureServing.Size()
+	n += 1 + l + sovGenerated(uint64(l))
+	l = m.FlowControl.Size()
+	n += 1 + l + sovGenerated(uint64(l))
+	if len(m.DispatchPolicies) > 0 {
+		for _, e := range m.DispatchPolicies {
+			l = e.Size()
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	l = m.Logging.Size()
+	n += 1 + l + sovGenerated(uint64(l))
+	n += 1 + sovGenerated(uint64(m.MinHealthyEndpoints))
+	if m.Disabled != nil {
+		n += 2
+	}
+	if m.Shadow != nil {
+		l = m.Shadow.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	if m.Retry != nil {
+		l = m.Retry.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	if m.Stub != nil {
+		l = m.Stub.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	if m.APIResources != nil {
+		l = m.APIResources.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	if m.HealthCheck != nil {
+		l = m.HealthCheck.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	if m.HiddenResources != nil {
+		l = m.HiddenResources.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	if m.OutlierDetection != nil {
+		l = m.OutlierDetection.Size()
+		n += 1 + l + sovGenerated(uint64(l))
+	}
+	if m.Impersonation != nil {
+		l = m.Impersonation.Size()
+		n += 2 + l + sovGenerated(uint64(l))
+	}
+	return n
+}
+
+func (m *UpstreamClusterStatus) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *UserAgentFlowControlSchema) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.UserAgentPrefixes) > 0 {
+		for _, s := range m.UserAgentPrefixes {
+			l = len(s)
+			n += 1 + l + sovGenerated(uint64(l))
+		}
+	}
+	l = len(m.FlowControlSchemaName)
+	n += 1 + l + sovGenerated(uint64(l))
+	return n
+}
+
+func sovGenerated(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozGenerated(x uint64) (n int) {
+	return sovGenerated(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (this *APIResourceConfig) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForRules := "[]APIResourceRule{"
+	for _, f := range this.Rules {
+		repeatedStringForRules += strings.Replace(strings.Replace(f.String(), "APIResourceRule", "APIResourceRule", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForRules += "}"
+	s := strings.Join([]string{`&APIResourceConfig{`,
+		`Rules:` + repeatedStringForRules + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *APIResourceRule) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&APIResourceRule{`,
+		`APIGroups:` + fmt.Sprintf("%v", this.APIGroups) + `,`,
+		`APIVersions:` + fmt.Sprintf("%v", this.APIVersions) + `,`,
+		`Resources:` + fmt.Sprintf("%v", this.Resources) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ClientConfig) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ClientConfig{`,
+		`Insecure:` + fmt.Sprintf("%v", this.Insecure) + `,`,
+		`BearerToken:` + valueToStringGenerated(this.BearerToken) + `,`,
+		`KeyData:` + valueToStringGenerated(this.KeyData) + `,`,
+		`CertData:` + valueToStringGenerated(this.CertData) + `,`,
+		`CAData:` + valueToStringGenerated(this.CAData) + `,`,
+		`QPS:` + fmt.Sprintf("%v", this.QPS) + `,`,
+		`Burst:` + fmt.Sprintf("%v", this.Burst) + `,`,
+		`QPSDivisor:` + fmt.Sprintf("%v", this.QPSDivisor) + `,`,
+		`TLSSessionCacheSize:` + fmt.Sprintf("%v", this.TLSSessionCacheSize) + `,`,
+		`TLSRenegotiation:` + fmt.Sprintf("%v", this.TLSRenegotiation) + `,`,
+		`H2C:` + fmt.Sprintf("%v", this.H2C) + `,`,
+		`DisableKeepAlives:` + fmt.Sprintf("%v", this.DisableKeepAlives) + `,`,
+		`SourceAddress:` + fmt.Sprintf("%v", this.SourceAddress) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *DispatchPolicy) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForRules := "[]DispatchPolicyRule{"
+	for _, f := range this.Rules {
+		repeatedStringForRules += strings.Replace(strings.Replace(f.String(), "DispatchPolicyRule", "DispatchPolicyRule", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForRules += "}"
+	s := strings.Join([]string{`&DispatchPolicy{`,
+		`Strategy:` + fmt.Sprintf("%v", this.Strategy) + `,`,
+		`UpstreamSubset:` + fmt.Sprintf("%v", this.UpstreamSubset) + `,`,
+		`Rules:` + repeatedStringForRules + `,`,
+		`FlowControlSchemaName:` + fmt.Sprintf("%v", this.FlowControlSchemaName) + `,`,
+		`LogMode:` + fmt.Sprintf("%v", this.LogMode) + `,`,
+		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *DispatchPolicyRule) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForServiceAccounts := "[]ServiceAccountRef{"
+	for _, f := range this.ServiceAccounts {
+		repeatedStringForServiceAccounts += strings.Replace(strings.Replace(f.String(), "ServiceAccountRef", "ServiceAccountRef", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForServiceAccounts += "}"
+	s := strings.Join([]string{`&DispatchPolicyRule{`,
+		`Verbs:` + fmt.Sprintf("%v", this.Verbs) + `,`,
+		`APIGroups:` + fmt.Sprintf("%v", this.APIGroups) + `,`,
+		`Resources:` + fmt.Sprintf("%v", this.Resources) + `,`,
+		`ResourceNames:` + fmt.Sprintf("%v", this.ResourceNames) + `,`,
+		`Users:` + fmt.Sprintf("%v", this.Users) + `,`,
+		`ServiceAccounts:` + repeatedStringForServiceAccounts + `,`,
+		`UserGroups:` + fmt.Sprintf("%v", this.UserGroups) + `,`,
+		`NonResourceURLs:` + fmt.Sprintf("%v", this.NonResourceURLs) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ExemptFlowControlSchema) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ExemptFlowControlSchema{`,
+		`MaxRequestsInflight:` + fmt.Sprintf("%v", this.MaxRequestsInflight) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *FlowControl) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForSchemas := "[]FlowControlSchema{"
+	for _, f := range this.Schemas {
+		repeatedStringForSchemas += strings.Replace(strings.Replace(f.String(), "FlowControlSchema", "FlowControlSchema", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForSchemas += "}"
+	repeatedStringForNamespaceSchemas := "[]NamespaceFlowControlSchema{"
+	for _, f := range this.NamespaceSchemas {
+		repeatedStringForNamespaceSchemas += strings.Replace(strings.Replace(f.String(), "NamespaceFlowControlSchema", "NamespaceFlowControlSchema", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForNamespaceSchemas += "}"
+	repeatedStringForUserAgentSchemas := "[]UserAgentFlowControlSchema{"
+	for _, f := range this.UserAgentSchemas {
+		repeatedStringForUserAgentSchemas += strings.Replace(strings.Replace(f.String(), "UserAgentFlowControlSchema", "UserAgentFlowControlSchema", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForUserAgentSchemas += "}"
+	s := strings.Join([]string{`&FlowControl{`,
+		`Schemas:` + repeatedStringForSchemas + `,`,
+		`NamespaceSchemas:` + repeatedStringForNamespaceSchemas + `,`,
+		`RejectionResponse:` + strings.Replace(this.RejectionResponse.String(), "RejectionResponse", "RejectionResponse", 1) + `,`,
+		`UserAgentSchemas:` + repeatedStringForUserAgentSchemas + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *FlowControlSchema) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&FlowControlSchema{`,
+		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
+		`FlowControlSchemaConfiguration:` + strings.Replace(strings.Replace(this.FlowControlSchemaConfiguration.String(), "FlowControlSchemaConfiguration", "FlowControlSchemaConfiguration", 1), `&`, ``, 1) + `,`,
+		`RejectionStatusCode:` + fmt.Sprintf("%v", this.RejectionStatusCode) + `,`,
+		`RejectionResponse:` + strings.Replace(this.RejectionResponse.String(), "RejectionResponse", "RejectionResponse", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *FlowControlSchemaConfiguration) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&FlowControlSchemaConfiguration{`,
+		`Exempt:` + strings.Replace(this.Exempt.String(), "ExemptFlowControlSchema", "ExemptFlowControlSchema", 1) + `,`,
+		`MaxRequestsInflight:` + strings.Replace(this.MaxRequestsInflight.String(), "MaxRequestsInflightFlowControlSchema", "MaxRequestsInflightFlowControlSchema", 1) + `,`,
+		`TokenBucket:` + strings.Replace(this.TokenBucket.String(), "TokenBucketFlowControlSchema", "TokenBucketFlowControlSchema", 1) + `,`,
+		`MaxRequestsInflightByVerb:` + strings.Replace(this.MaxRequestsInflightByVerb.String(), "MaxRequestsInflightByVerbFlowControlSchema", "MaxRequestsInflightByVerbFlowControlSchema", 1) + `,`,
+		`Queued:` + strings.Replace(this.Queued.String(), "QueuedFlowControlSchema", "QueuedFlowControlSchema", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *HealthCheckPolicy) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&HealthCheckPolicy{`,
+		`SuccessRateWindow:` + fmt.Sprintf("%v", this.SuccessRateWindow) + `,`,
+		`MinSuccessPercent:` + fmt.Sprintf("%v", this.MinSuccessPercent) + `,`,
+		`IntervalMilliseconds:` + fmt.Sprintf("%v", this.IntervalMilliseconds) + `,`,
+		`TimeoutMilliseconds:` + fmt.Sprintf("%v", this.TimeoutMilliseconds) + `,`,
+		`Path:` + fmt.Sprintf("%v", this.Path) + `,`,
+		`FallbackToHealthz:` + fmt.Sprintf("%v", this.FallbackToHealthz) + `,`,
+		`UnhealthyThreshold:` + fmt.Sprintf("%v", this.UnhealthyThreshold) + `,`,
+		`HealthyThreshold:` + fmt.Sprintf("%v", this.HealthyThreshold) + `,`,
+		`MaxBackoffMilliseconds:` + fmt.Sprintf("%v", this.MaxBackoffMilliseconds) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *HiddenResourceConfig) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForRules := "[]DispatchPolicyRule{"
+	for _, f := range this.Rules {
+		repeatedStringForRules += strings.Replace(strings.Replace(f.String(), "DispatchPolicyRule", "DispatchPolicyRule", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForRules += "}"
+	s := strings.Join([]string{`&HiddenResourceConfig{`,
+		`Rules:` + repeatedStringForRules + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ImpersonationPolicy) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ImpersonationPolicy{`,
+		`AllowedUsers:` + fmt.Sprintf("%v", this.AllowedUsers) + `,`,
+		`DeniedUsers:` + fmt.Sprintf("%v", this.DeniedUsers) + `,`,
+		`AllowedGroups:` + fmt.Sprintf("%v", this.AllowedGroups) + `,`,
+		`DeniedGroups:` + fmt.Sprintf("%v", this.DeniedGroups) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *LoggingConfig) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&LoggingConfig{`,
+		`Mode:` + fmt.Sprintf("%v", this.Mode) + `,`,
+		`UserGroups:` + fmt.Sprintf("%v", this.UserGroups) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *MaxRequestsInflightByVerbFlowControlSchema) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&MaxRequestsInflightByVerbFlowControlSchema{`,
+		`MaxMutating:` + fmt.Sprintf("%v", this.MaxMutating) + `,`,
+		`MaxReadOnly:` + fmt.Sprintf("%v", this.MaxReadOnly) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *MaxRequestsInflightFlowControlSchema) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&MaxRequestsInflightFlowControlSchema{`,
+		`Max:` + fmt.Sprintf("%v", this.Max) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *NamespaceFlowControlSchema) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&NamespaceFlowControlSchema{`,
+		`Namespaces:` + fmt.Sprintf("%v", this.Namespaces) + `,`,
+		`FlowControlSchemaName:` + fmt.Sprintf("%v", this.FlowControlSchemaName) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *OutlierDetectionPolicy) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&OutlierDetectionPolicy{`,
+		`ConsecutiveErrors:` + fmt.Sprintf("%v", this.ConsecutiveErrors) + `,`,
+		`IntervalSeconds:` + fmt.Sprintf("%v", this.IntervalSeconds) + `,`,
+		`EjectionSeconds:` + fmt.Sprintf("%v", this.EjectionSeconds) + `,`,
+		`HalfOpenPercent:` + fmt.Sprintf("%v", this.HalfOpenPercent) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *QueuedFlowControlSchema) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&QueuedFlowControlSchema{`,
+		`Concurrency:` + fmt.Sprintf("%v", this.Concurrency) + `,`,
+		`QueueLength:` + fmt.Sprintf("%v", this.QueueLength) + `,`,
+		`Queues:` + fmt.Sprintf("%v", this.Queues) + `,`,
+		`HandSize:` + fmt.Sprintf("%v", this.HandSize) + `,`,
+		`MaxWaitSeconds:` + fmt.Sprintf("%v", this.MaxWaitSeconds) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *RejectionResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	keysForHeaders := make([]string, 0, len(this.Headers))
+	for k := range this.Headers {
+		keysForHeaders = append(keysForHeaders, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForHeaders)
+	mapStringForHeaders := "map[string]string{"
+	for _, k := range keysForHeaders {
+		mapStringForHeaders += fmt.Sprintf("%v: %v,", k, this.Headers[k])
+	}
+	mapStringForHeaders += "}"
+	s := strings.Join([]string{`&RejectionResponse{`,
+		`Message:` + fmt.Sprintf("%v", this.Message) + `,`,
+		`Headers:` + mapStringForHeaders + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *RetryPolicy) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&RetryPolicy{`,
+		`StatusReasons:` + fmt.Sprintf("%v", this.StatusReasons) + `,`,
+		`StatusMessages:` + fmt.Sprintf("%v", this.StatusMessages) + `,`,
+		`TooManyRequests:` + fmt.Sprintf("%v", this.TooManyRequests) + `,`,
+		`TooManyRequestsBackoffSeconds:` + fmt.Sprintf("%v", this.TooManyRequestsBackoffSeconds) + `,`,
+		`StatusCodes:` + fmt.Sprintf("%v", this.StatusCodes) + `,`,
+		`ConnectionErrors:` + fmt.Sprintf("%v", this.ConnectionErrors) + `,`,
+		`Verbs:` + fmt.Sprintf("%v", this.Verbs) + `,`,
+		`MaxRetries:` + fmt.Sprintf("%v", this.MaxRetries) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *SecretReferecence) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&SecretReferecence{`,
+		`Namespace:` + fmt.Sprintf("%v", this.Namespace) + `,`,
+		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *SecureServing) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&SecureServing{`,
+		`KeyData:` + valueToStringGenerated(this.KeyData) + `,`,
+		`CertData:` + valueToStringGenerated(this.CertData) + `,`,
+		`ClientCAData:` + valueToStringGenerated(this.ClientCAData) + `,`,
+		`ClientAuth:` + fmt.Sprintf("%v", this.ClientAuth) + `,`,
+		`APIAudiences:` + fmt.Sprintf("%v", this.APIAudiences) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ServiceAccountRef) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ServiceAccountRef{`,
+		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
+		`Namespace:` + fmt.Sprintf("%v", this.Namespace) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ShadowConfig) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ShadowConfig{`,
+		`Cluster:` + fmt.Sprintf("%v", this.Cluster) + `,`,
+		`Percentage:` + fmt.Sprintf("%v", this.Percentage) + `,`,
+		`CompareResponses:` + fmt.Sprintf("%v", this.CompareResponses) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *StubConfig) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&StubConfig{`,
+		`Message:` + fmt.Sprintf("%v", this.Message) + `,`,
+		`RetryAfterSeconds:` + fmt.Sprintf("%v", this.RetryAfterSeconds) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TokenBucketFlowControlSchema) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TokenBucketFlowControlSchema{`,
+		`QPS:` + fmt.Sprintf("%v", this.QPS) + `,`,
+		`Burst:` + fmt.Sprintf("%v", this.Burst) + `,`,
+		`SlowStartSeconds:` + fmt.Sprintf("%v", this.SlowStartSeconds) + `,`,
+		`KeyBy:` + fmt.Sprintf("%v", this.KeyBy) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *UpstreamCluster) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&UpstreamCluster{`,
+		`ObjectMeta:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.ObjectMeta), "ObjectMeta", "v1.ObjectMeta", 1), `&`, ``, 1) + `,`,
+		`Spec:` + strings.Replace(strings.Replace(this.Spec.String(), "UpstreamClusterSpec", "UpstreamClusterSpec", 1), `&`, ``, 1) + `,`,
+		`Status:` + strings.Replace(strings.Replace(this.Status.String(), "UpstreamClusterStatus", "UpstreamClusterStatus", 1), `&`, ``, 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *UpstreamClusterList) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForItems := "[]UpstreamCluster{"
+	for _, f := range this.Items {
+		repeatedStringForItems += strings.Replace(strings.Replace(f.String(), "UpstreamCluster", "UpstreamCluster", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForItems += "}"
+	s := strings.Join([]string{`&UpstreamClusterList{`,
+		`ListMeta:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.ListMeta), "ListMeta", "v1.ListMeta", 1), `&`, ``, 1) + `,`,
+		`Items:` + repeatedStringForItems + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *UpstreamClusterServer) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&UpstreamClusterServer{`,
+		`Endpoint:` + fmt.Sprintf("%v", this.Endpoint) + `,`,
+		`Disabled:` + valueToStringGenerated(this.Disabled) + `,`,
+		`Weight:` + valueToStringGenerated(this.Weight) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *UpstreamClusterSpec) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForServers := "[]UpstreamClusterServer{"
+	for _, f := range this.Servers {
+		repeatedStringForServers += strings.Replace(strings.Replace(f.String(), "UpstreamClusterServer", "UpstreamClusterServer", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForServers += "}"
+	repeatedStringForDispatchPolicies := "[]DispatchPolicy{"
+	for _, f := range this.DispatchPolicies {
+		repeatedStringForDispatchPolicies += strings.Replace(strings.Replace(f.String(), "DispatchPolicy", "DispatchPolicy", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForDispatchPolicies += "}"
+	s := strings.Join([]string{`&UpstreamClusterSpec{`,
+		`Servers:` + repeatedStringForServers + `,`,
+		`ClientConfig:` + strings.Replace(strings.Replace(this.ClientConfig.String(), "ClientConfig", "ClientConfig", 1), `&`, ``, 1) + `,`,
+		`SecureServing:` + strings.Replace(strings.Replace(this.SecureServing.String(), "SecureServing", "SecureServing", 1), `&`, ``, 1) + `,`,
+		`FlowControl:` + strings.Replace(strings.Replace(this.FlowControl.String(), "FlowControl", "FlowControl", 1), `&`, ``, 1) + `,`,
+		`DispatchPolicies:` + repeatedStringForDispatchPolicies + `,`,
+		`Logging:` + strings.Replace(strings.Replace(this.Logging.String(), "LoggingConfig", "LoggingConfig", 1), `&`, ``, 1) + `,`,
+		`MinHealthyEndpoints:` + fmt.Sprintf("%v", this.MinHealthyEndpoints) + `,`,
+		`Disabled:` + valueToStringGenerated(this.Disabled) + `,`,
+		`Shadow:` + strings.Replace(this.Shadow.String(), "ShadowConfig", "ShadowConfig", 1) + `,`,
+		`Retry:` + strings.Replace(this.Retry.String(), "RetryPolicy", "RetryPolicy", 1) + `,`,
+		`Stub:` + strings.Replace(this.Stub.String(), "StubConfig", "StubConfig", 1) + `,`,
+		`APIResources:` + strings.Replace(this.APIResources.String(), "APIResourceConfig", "APIResourceConfig", 1) + `,`,
+		`HealthCheck:` + strings.Replace(this.HealthCheck.String(), "HealthCheckPolicy", "HealthCheckPolicy", 1) + `,`,
+		`HiddenResources:` + strings.Replace(this.HiddenResources.String(), "HiddenResourceConfig", "HiddenResourceConfig", 1) + `,`,
+		`OutlierDetection:` + strings.Replace(this.OutlierDetection.String(), "OutlierDetectionPolicy", "OutlierDetectionPolicy", 1) + `,`,
+		`Impersonation:` + strings.Replace(this.Impersonation.String(), "ImpersonationPolicy", "ImpersonationPolicy", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *UpstreamClusterStatus) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&UpstreamClusterStatus{`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *UserAgentFlowControlSchema) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&UserAgentFlowControlSchema{`,
+		`UserAgentPrefixes:` + fmt.Sprintf("%v", this.UserAgentPrefixes) + `,`,
+		`FlowControlSchemaName:` + fmt.Sprintf("%v", this.FlowControlSchemaName) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func valueToStringGenerated(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("*%v", pv)
+}
+func (m *APIResourceConfig) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: APIResourceConfig: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: APIResourceConfig: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Rules", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Rules = append(m.Rules, APIResourceRule{})
+			if err := m.Rules[len(m.Rules)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *APIResourceRule) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: APIResourceRule: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: APIResourceRule: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field APIGroups", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.APIGroups = append(m.APIGroups, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field APIVersions", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.APIVersions = append(m.APIVersions, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Resources", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Resources = append(m.Resources, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ClientConfig) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ClientConfig: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ClientConfig: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Insecure", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Insecure = bool(v != 0)
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BearerToken", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.BearerToken = append(m.BearerToken[:0], dAtA[iNdEx:postIndex]...)
+			if m.BearerToken == nil {
+				m.BearerToken = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field KeyData", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.KeyData = append(m.KeyData[:0], dAtA[iNdEx:postIndex]...)
+			if m.KeyData == nil {
+				m.KeyData = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CertData", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.CertData = append(m.CertData[:0], dAtA[iNdEx:postIndex]...)
+			if m.CertData == nil {
+				m.CertData = []byte{}
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CAData", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.CAData = append(m.CAData[:0], dAtA[iNdEx:postIndex]...)
+			if m.CAData == nil {
+				m.CAData = []byte{}
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field QPS", wireType)
+			}
+			m.QPS = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.QPS |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Burst", wireType)
+			}
+			m.Burst = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Burst |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field QPSDivisor", wireType)
+			}
+			m.QPSDivisor = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.QPSDivisor |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TLSSessionCacheSize", wireType)
+			}
+			m.TLSSessionCacheSize = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TLSSessionCacheSize |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TLSRenegotiation", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TLSRenegotiation = TLSRenegotiationPolicy(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field H2C", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.H2C = bool(v != 0)
+		case 12:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DisableKeepAlives", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.DisableKeepAlives = bool(v != 0)
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SourceAddress", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SourceAddress = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DispatchPolicy) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DispatchPolicy: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DispatchPolicy: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Strategy", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Strategy = Strategy(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UpstreamSubset", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.UpstreamSubset = append(m.UpstreamSubset, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Rules", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Rules = append(m.Rules, DispatchPolicyRule{})
+			if err := m.Rules[len(m.Rules)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FlowControlSchemaName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.FlowControlSchemaName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LogMode", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.LogMode = LogMode(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DispatchPolicyRule) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DispatchPolicyRule: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DispatchPolicyRule: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Verbs", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Verbs = append(m.Verbs, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field APIGroups", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.APIGroups = append(m.APIGroups, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Resources", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Resources = append(m.Resources, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ResourceNames", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ResourceNames = append(m.ResourceNames, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Users", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Users = append(m.Users, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ServiceAccounts", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ServiceAccounts = append(m.ServiceAccounts, ServiceAccountRef{})
+			if err := m.ServiceAccounts[len(m.ServiceAccounts)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UserGroups", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.UserGroups = append(m.UserGroups, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NonResourceURLs", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NonResourceURLs = append(m.NonResourceURLs, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ExemptFlowControlSchema) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ExemptFlowControlSchema: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ExemptFlowControlSchema: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxRequestsInflight", wireType)
+			}
+			m.MaxRequestsInflight = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxRequestsInflight |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *FlowControl) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: FlowControl: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: FlowControl: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Schemas", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Schemas = append(m.Schemas, FlowControlSchema{})
+			if err := m.Schemas[len(m.Schemas)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NamespaceSchemas", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NamespaceSchemas = append(m.NamespaceSchemas, NamespaceFlowControlSchema{})
+			if err := m.NamespaceSchemas[len(m.NamespaceSchemas)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RejectionResponse", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.RejectionResponse == nil {
+				m.RejectionResponse = &RejectionResponse{}
+			}
+			if err := m.RejectionResponse.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UserAgentSchemas", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.UserAgentSchemas = append(m.UserAgentSchemas, UserAgentFlowControlSchema{})
+			if err := m.UserAgentSchemas[len(m.UserAgentSchemas)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *FlowControlSchema) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: FlowControlSchema: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: FlowControlSchema: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FlowControlSchemaConfiguration", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.FlowControlSchemaConfiguration.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RejectionStatusCode", wireType)
+			}
+			m.RejectionStatusCode = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RejectionStatusCode |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RejectionResponse", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.RejectionResponse == nil {
+				m.RejectionResponse = &RejectionResponse{}
+			}
+			if err := m.RejectionResponse.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *FlowControlSchemaConfiguration) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: FlowControlSchemaConfiguration: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: FlowControlSchemaConfiguration: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Exempt", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Exempt == nil {
+				m.Exempt = &ExemptFlowControlSchema{}
+			}
+			if err := m.Exempt.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxRequestsInflight", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.MaxRequestsInflight == nil {
+				m.MaxRequestsInflight = &MaxRequestsInflightFlowControlSchema{}
+			}
+			if err := m.MaxRequestsInflight.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TokenBucket", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.TokenBucket == nil {
+				m.TokenBucket = &TokenBucketFlowControlSchema{}
+			}
+			if err := m.TokenBucket.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxRequestsInflightByVerb", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.MaxRequestsInflightByVerb == nil {
+				m.MaxRequestsInflightByVerb = &MaxRequestsInflightByVerbFlowControlSchema{}
+			}
+			if err := m.MaxRequestsInflightByVerb.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Queued", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Queued == nil {
+				m.Queued = &QueuedFlowControlSchema{}
+			}
+			if err := m.Queued.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *HealthCheckPolicy) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: HealthCheckPolicy: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: HealthCheckPolicy: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SuccessRateWindow", wireType)
+			}
+			m.SuccessRateWindow = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SuccessRateWindow |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MinSuccessPercent", wireType)
+			}
+			m.MinSuccessPercent = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MinSuccessPercent |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IntervalMilliseconds", wireType)
+			}
+			m.IntervalMilliseconds = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.IntervalMilliseconds |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TimeoutMilliseconds", wireType)
+			}
+			m.TimeoutMilliseconds = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TimeoutMilliseconds |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Path", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Path = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FallbackToHealthz", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.FallbackToHealthz = bool(v != 0)
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UnhealthyThreshold", wireType)
+			}
+			m.UnhealthyThreshold = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.UnhealthyThreshold |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HealthyThreshold", wireType)
+			}
+			m.HealthyThreshold = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.HealthyThreshold |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxBackoffMilliseconds", wireType)
+			}
+			m.MaxBackoffMilliseconds = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxBackoffMilliseconds |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *HiddenResourceConfig) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: HiddenResourceConfig: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: HiddenResourceConfig: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Rules", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Rules = append(m.Rules, DispatchPolicyRule{})
+			if err := m.Rules[len(m.Rules)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ImpersonationPolicy) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ImpersonationPolicy: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ImpersonationPolicy: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AllowedUsers", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AllowedUsers = append(m.AllowedUsers, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DeniedUsers", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DeniedUsers = append(m.DeniedUsers, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AllowedGroups", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AllowedGroups = append(m.AllowedGroups, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DeniedGroups", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DeniedGroups = append(m.DeniedGroups, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	pv := reflect.Indirect(rv).Interface()
-	return fmt.Sprintf("*%v", pv)
+	return nil
 }
-func (m *ClientConfig) Unmarshal(dAtA []byte) error {
+func (m *LoggingConfig) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2126,17 +6423,17 @@ func (m *ClientConfig) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ClientConfig: wiretype end group for non-group")
+			return fmt.Errorf("proto: LoggingConfig: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ClientConfig: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: LoggingConfig: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Insecure", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Mode", wireType)
 			}
-			var v int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -2146,17 +6443,29 @@ func (m *ClientConfig) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.Insecure = bool(v != 0)
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Mode = LogMode(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field BearerToken", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field UserGroups", wireType)
 			}
-			var byteLen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -2166,31 +6475,79 @@ func (m *ClientConfig) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthGenerated
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthGenerated
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.BearerToken = append(m.BearerToken[:0], dAtA[iNdEx:postIndex]...)
-			if m.BearerToken == nil {
-				m.BearerToken = []byte{}
-			}
+			m.UserGroups = LogMode(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field KeyData", wireType)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
 			}
-			var byteLen int
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *MaxRequestsInflightByVerbFlowControlSchema) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: MaxRequestsInflightByVerbFlowControlSchema: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: MaxRequestsInflightByVerbFlowControlSchema: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxMutating", wireType)
+			}
+			m.MaxMutating = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -2200,31 +6557,154 @@ func (m *ClientConfig) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				m.MaxMutating |= int32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxReadOnly", wireType)
+			}
+			m.MaxReadOnly = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxReadOnly |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthGenerated
 			}
-			postIndex := iNdEx + byteLen
-			if postIndex < 0 {
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *MaxRequestsInflightFlowControlSchema) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: MaxRequestsInflightFlowControlSchema: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: MaxRequestsInflightFlowControlSchema: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Max", wireType)
+			}
+			m.Max = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Max |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthGenerated
 			}
-			if postIndex > l {
+			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.KeyData = append(m.KeyData[:0], dAtA[iNdEx:postIndex]...)
-			if m.KeyData == nil {
-				m.KeyData = []byte{}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *NamespaceFlowControlSchema) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
 			}
-			iNdEx = postIndex
-		case 4:
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: NamespaceFlowControlSchema: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: NamespaceFlowControlSchema: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field CertData", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Namespaces", wireType)
 			}
-			var byteLen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -2234,31 +6714,29 @@ func (m *ClientConfig) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthGenerated
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthGenerated
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.CertData = append(m.CertData[:0], dAtA[iNdEx:postIndex]...)
-			if m.CertData == nil {
-				m.CertData = []byte{}
-			}
+			m.Namespaces = append(m.Namespaces, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
-		case 5:
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field CAData", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field FlowControlSchemaName", wireType)
 			}
-			var byteLen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -2268,31 +6746,79 @@ func (m *ClientConfig) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthGenerated
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthGenerated
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.CAData = append(m.CAData[:0], dAtA[iNdEx:postIndex]...)
-			if m.CAData == nil {
-				m.CAData = []byte{}
-			}
+			m.FlowControlSchemaName = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 6:
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *OutlierDetectionPolicy) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: OutlierDetectionPolicy: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: OutlierDetectionPolicy: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field QPS", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ConsecutiveErrors", wireType)
 			}
-			m.QPS = 0
+			m.ConsecutiveErrors = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -2302,16 +6828,16 @@ func (m *ClientConfig) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.QPS |= int32(b&0x7F) << shift
+				m.ConsecutiveErrors |= int32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 7:
+		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Burst", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field IntervalSeconds", wireType)
 			}
-			m.Burst = 0
+			m.IntervalSeconds = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -2321,16 +6847,16 @@ func (m *ClientConfig) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Burst |= int32(b&0x7F) << shift
+				m.IntervalSeconds |= int32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 8:
+		case 3:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field QPSDivisor", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field EjectionSeconds", wireType)
 			}
-			m.QPSDivisor = 0
+			m.EjectionSeconds = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -2340,7 +6866,26 @@ func (m *ClientConfig) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.QPSDivisor |= int32(b&0x7F) << shift
+				m.EjectionSeconds |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HalfOpenPercent", wireType)
+			}
+			m.HalfOpenPercent = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.HalfOpenPercent |= int32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -2366,7 +6911,7 @@ func (m *ClientConfig) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *DispatchPolicy) Unmarshal(dAtA []byte) error {
+func (m *QueuedFlowControlSchema) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2389,17 +6934,17 @@ func (m *DispatchPolicy) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: DispatchPolicy: wiretype end group for non-group")
+			return fmt.Errorf("proto: QueuedFlowControlSchema: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: DispatchPolicy: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: QueuedFlowControlSchema: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Strategy", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Concurrency", wireType)
 			}
-			var stringLen uint64
+			m.Concurrency = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -2409,29 +6954,16 @@ func (m *DispatchPolicy) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.Concurrency |= int32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthGenerated
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthGenerated
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Strategy = Strategy(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
 		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field UpstreamSubset", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field QueueLength", wireType)
 			}
-			var stringLen uint64
+			m.QueueLength = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -2441,29 +6973,54 @@ func (m *DispatchPolicy) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.QueueLength |= int32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthGenerated
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Queues", wireType)
 			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthGenerated
+			m.Queues = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Queues |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HandSize", wireType)
 			}
-			m.UpstreamSubset = append(m.UpstreamSubset, string(dAtA[iNdEx:postIndex]))
-			iNdEx = postIndex
-		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Rules", wireType)
+			m.HandSize = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.HandSize |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			var msglen int
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxWaitSeconds", wireType)
+			}
+			m.MaxWaitSeconds = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -2473,29 +7030,64 @@ func (m *DispatchPolicy) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.MaxWaitSeconds |= int32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthGenerated
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
 			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthGenerated
 			}
-			if postIndex > l {
+			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Rules = append(m.Rules, DispatchPolicyRule{})
-			if err := m.Rules[len(m.Rules)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RejectionResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
 			}
-			iNdEx = postIndex
-		case 4:
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RejectionResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RejectionResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field FlowControlSchemaName", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -2523,13 +7115,13 @@ func (m *DispatchPolicy) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.FlowControlSchemaName = string(dAtA[iNdEx:postIndex])
+			m.Message = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 5:
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field LogMode", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Headers", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -2539,23 +7131,118 @@ func (m *DispatchPolicy) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthGenerated
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthGenerated
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.LogMode = LogMode(dAtA[iNdEx:postIndex])
+			if m.Headers == nil {
+				m.Headers = make(map[string]string)
+			}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowGenerated
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowGenerated
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowGenerated
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipGenerated(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthGenerated
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Headers[mapkey] = mapvalue
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -2578,7 +7265,7 @@ func (m *DispatchPolicy) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *DispatchPolicyRule) Unmarshal(dAtA []byte) error {
+func (m *RetryPolicy) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2601,15 +7288,15 @@ func (m *DispatchPolicyRule) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: DispatchPolicyRule: wiretype end group for non-group")
+			return fmt.Errorf("proto: RetryPolicy: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: DispatchPolicyRule: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: RetryPolicy: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Verbs", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field StatusReasons", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -2637,11 +7324,11 @@ func (m *DispatchPolicyRule) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Verbs = append(m.Verbs, string(dAtA[iNdEx:postIndex]))
+			m.StatusReasons = append(m.StatusReasons, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field APIGroups", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field StatusMessages", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -2669,11 +7356,11 @@ func (m *DispatchPolicyRule) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.APIGroups = append(m.APIGroups, string(dAtA[iNdEx:postIndex]))
+			m.StatusMessages = append(m.StatusMessages, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
 		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Resources", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field TooManyRequests", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -2701,13 +7388,108 @@ func (m *DispatchPolicyRule) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Resources = append(m.Resources, string(dAtA[iNdEx:postIndex]))
+			m.TooManyRequests = TooManyRequestsPolicy(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 4:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ResourceNames", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TooManyRequestsBackoffSeconds", wireType)
+			}
+			m.TooManyRequestsBackoffSeconds = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TooManyRequestsBackoffSeconds |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType == 0 {
+				var v int32
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowGenerated
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= int32(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.StatusCodes = append(m.StatusCodes, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowGenerated
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthGenerated
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthGenerated
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				var count int
+				for _, integer := range dAtA[iNdEx:postIndex] {
+					if integer < 128 {
+						count++
+					}
+				}
+				elementCount = count
+				if elementCount != 0 && len(m.StatusCodes) == 0 {
+					m.StatusCodes = make([]int32, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v int32
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowGenerated
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= int32(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.StatusCodes = append(m.StatusCodes, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field StatusCodes", wireType)
 			}
-			var stringLen uint64
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ConnectionErrors", wireType)
+			}
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -2717,27 +7499,15 @@ func (m *DispatchPolicyRule) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthGenerated
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthGenerated
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.ResourceNames = append(m.ResourceNames, string(dAtA[iNdEx:postIndex]))
-			iNdEx = postIndex
-		case 5:
+			m.ConnectionErrors = bool(v != 0)
+		case 7:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Users", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Verbs", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -2765,13 +7535,13 @@ func (m *DispatchPolicyRule) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Users = append(m.Users, string(dAtA[iNdEx:postIndex]))
+			m.Verbs = append(m.Verbs, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
-		case 6:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ServiceAccounts", wireType)
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxRetries", wireType)
 			}
-			var msglen int
+			m.MaxRetries = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -2781,29 +7551,64 @@ func (m *DispatchPolicyRule) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.MaxRetries |= int32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthGenerated
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
 			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthGenerated
 			}
-			if postIndex > l {
+			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.ServiceAccounts = append(m.ServiceAccounts, ServiceAccountRef{})
-			if err := m.ServiceAccounts[len(m.ServiceAccounts)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SecretReferecence) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
 			}
-			iNdEx = postIndex
-		case 7:
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SecretReferecence: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SecretReferecence: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field UserGroups", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Namespace", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -2831,11 +7636,11 @@ func (m *DispatchPolicyRule) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.UserGroups = append(m.UserGroups, string(dAtA[iNdEx:postIndex]))
+			m.Namespace = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 8:
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field NonResourceURLs", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -2863,7 +7668,7 @@ func (m *DispatchPolicyRule) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.NonResourceURLs = append(m.NonResourceURLs, string(dAtA[iNdEx:postIndex]))
+			m.Name = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -2886,7 +7691,7 @@ func (m *DispatchPolicyRule) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *ExemptFlowControlSchema) Unmarshal(dAtA []byte) error {
+func (m *SecureServing) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2909,67 +7714,151 @@ func (m *ExemptFlowControlSchema) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ExemptFlowControlSchema: wiretype end group for non-group")
+			return fmt.Errorf("proto: SecureServing: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ExemptFlowControlSchema: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SecureServing: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
-		default:
-			iNdEx = preIndex
-			skippy, err := skipGenerated(dAtA[iNdEx:])
-			if err != nil {
-				return err
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field KeyData", wireType)
 			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
 				return ErrInvalidLengthGenerated
 			}
-			if (iNdEx + skippy) > l {
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *FlowControl) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowGenerated
+			m.KeyData = append(m.KeyData[:0], dAtA[iNdEx:postIndex]...)
+			if m.KeyData == nil {
+				m.KeyData = []byte{}
 			}
-			if iNdEx >= l {
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CertData", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
+			m.CertData = append(m.CertData[:0], dAtA[iNdEx:postIndex]...)
+			if m.CertData == nil {
+				m.CertData = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ClientCAData", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ClientCAData = append(m.ClientCAData[:0], dAtA[iNdEx:postIndex]...)
+			if m.ClientCAData == nil {
+				m.ClientCAData = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ClientAuth", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: FlowControl: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: FlowControl: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ClientAuth = ClientAuthMode(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Schemas", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field APIAudiences", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -2979,25 +7868,23 @@ func (m *FlowControl) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthGenerated
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthGenerated
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Schemas = append(m.Schemas, FlowControlSchema{})
-			if err := m.Schemas[len(m.Schemas)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.APIAudiences = append(m.APIAudiences, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -3020,7 +7907,7 @@ func (m *FlowControl) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *FlowControlSchema) Unmarshal(dAtA []byte) error {
+func (m *ServiceAccountRef) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3043,10 +7930,10 @@ func (m *FlowControlSchema) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: FlowControlSchema: wiretype end group for non-group")
+			return fmt.Errorf("proto: ServiceAccountRef: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: FlowControlSchema: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ServiceAccountRef: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -3083,9 +7970,9 @@ func (m *FlowControlSchema) Unmarshal(dAtA []byte) error {
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field FlowControlSchemaConfiguration", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Namespace", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -3095,24 +7982,23 @@ func (m *FlowControlSchema) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthGenerated
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthGenerated
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.FlowControlSchemaConfiguration.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Namespace = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -3135,7 +8021,7 @@ func (m *FlowControlSchema) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *FlowControlSchemaConfiguration) Unmarshal(dAtA []byte) error {
+func (m *ShadowConfig) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3158,17 +8044,17 @@ func (m *FlowControlSchemaConfiguration) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: FlowControlSchemaConfiguration: wiretype end group for non-group")
+			return fmt.Errorf("proto: ShadowConfig: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: FlowControlSchemaConfiguration: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ShadowConfig: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Exempt", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Cluster", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -3178,33 +8064,29 @@ func (m *FlowControlSchemaConfiguration) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthGenerated
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthGenerated
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Exempt == nil {
-				m.Exempt = &ExemptFlowControlSchema{}
-			}
-			if err := m.Exempt.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Cluster = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field MaxRequestsInflight", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Percentage", wireType)
 			}
-			var msglen int
+			m.Percentage = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -3214,33 +8096,16 @@ func (m *FlowControlSchemaConfiguration) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.Percentage |= int32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthGenerated
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthGenerated
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if m.MaxRequestsInflight == nil {
-				m.MaxRequestsInflight = &MaxRequestsInflightFlowControlSchema{}
-			}
-			if err := m.MaxRequestsInflight.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
 		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TokenBucket", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CompareResponses", wireType)
 			}
-			var msglen int
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -3250,28 +8115,12 @@ func (m *FlowControlSchemaConfiguration) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthGenerated
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthGenerated
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if m.TokenBucket == nil {
-				m.TokenBucket = &TokenBucketFlowControlSchema{}
-			}
-			if err := m.TokenBucket.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
+			m.CompareResponses = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipGenerated(dAtA[iNdEx:])
@@ -3293,7 +8142,7 @@ func (m *FlowControlSchemaConfiguration) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *LoggingConfig) Unmarshal(dAtA []byte) error {
+func (m *StubConfig) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3316,15 +8165,15 @@ func (m *LoggingConfig) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: LoggingConfig: wiretype end group for non-group")
+			return fmt.Errorf("proto: StubConfig: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: LoggingConfig: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: StubConfig: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Mode", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -3352,63 +8201,13 @@ func (m *LoggingConfig) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Mode = LogMode(dAtA[iNdEx:postIndex])
+			m.Message = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipGenerated(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
-				return ErrInvalidLengthGenerated
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *MaxRequestsInflightFlowControlSchema) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowGenerated
-			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
-			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: MaxRequestsInflightFlowControlSchema: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: MaxRequestsInflightFlowControlSchema: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Max", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field RetryAfterSeconds", wireType)
 			}
-			m.Max = 0
+			m.RetryAfterSeconds = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -3418,7 +8217,7 @@ func (m *MaxRequestsInflightFlowControlSchema) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Max |= int32(b&0x7F) << shift
+				m.RetryAfterSeconds |= int32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -3444,7 +8243,7 @@ func (m *MaxRequestsInflightFlowControlSchema) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SecretReferecence) Unmarshal(dAtA []byte) error {
+func (m *TokenBucketFlowControlSchema) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3467,17 +8266,17 @@ func (m *SecretReferecence) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SecretReferecence: wiretype end group for non-group")
+			return fmt.Errorf("proto: TokenBucketFlowControlSchema: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SecretReferecence: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TokenBucketFlowControlSchema: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Namespace", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field QPS", wireType)
 			}
-			var stringLen uint64
+			m.QPS = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -3487,27 +8286,52 @@ func (m *SecretReferecence) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.QPS |= int32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthGenerated
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Burst", wireType)
 			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthGenerated
+			m.Burst = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Burst |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SlowStartSeconds", wireType)
 			}
-			m.Namespace = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 2:
+			m.SlowStartSeconds = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SlowStartSeconds |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field KeyBy", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -3535,7 +8359,7 @@ func (m *SecretReferecence) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Name = string(dAtA[iNdEx:postIndex])
+			m.KeyBy = TokenBucketKey(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -3558,7 +8382,7 @@ func (m *SecretReferecence) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SecureServing) Unmarshal(dAtA []byte) error {
+func (m *UpstreamCluster) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3581,17 +8405,17 @@ func (m *SecureServing) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SecureServing: wiretype end group for non-group")
+			return fmt.Errorf("proto: UpstreamCluster: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SecureServing: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: UpstreamCluster: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field KeyData", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ObjectMeta", wireType)
 			}
-			var byteLen int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -3601,31 +8425,30 @@ func (m *SecureServing) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthGenerated
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthGenerated
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.KeyData = append(m.KeyData[:0], dAtA[iNdEx:postIndex]...)
-			if m.KeyData == nil {
-				m.KeyData = []byte{}
-			}
+			if err := m.ObjectMeta.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field CertData", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Spec", wireType)
 			}
-			var byteLen int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -3635,31 +8458,30 @@ func (m *SecureServing) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthGenerated
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthGenerated
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.CertData = append(m.CertData[:0], dAtA[iNdEx:postIndex]...)
-			if m.CertData == nil {
-				m.CertData = []byte{}
+			if err := m.Spec.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
 			iNdEx = postIndex
 		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ClientCAData", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
 			}
-			var byteLen int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -3669,24 +8491,23 @@ func (m *SecureServing) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthGenerated
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthGenerated
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.ClientCAData = append(m.ClientCAData[:0], dAtA[iNdEx:postIndex]...)
-			if m.ClientCAData == nil {
-				m.ClientCAData = []byte{}
+			if err := m.Status.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
 			iNdEx = postIndex
 		default:
@@ -3710,7 +8531,7 @@ func (m *SecureServing) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *ServiceAccountRef) Unmarshal(dAtA []byte) error {
+func (m *UpstreamClusterList) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3733,17 +8554,17 @@ func (m *ServiceAccountRef) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ServiceAccountRef: wiretype end group for non-group")
+			return fmt.Errorf("proto: UpstreamClusterList: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ServiceAccountRef: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: UpstreamClusterList: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ListMeta", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -3753,29 +8574,30 @@ func (m *ServiceAccountRef) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthGenerated
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthGenerated
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Name = string(dAtA[iNdEx:postIndex])
+			if err := m.ListMeta.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Namespace", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Items", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -3785,23 +8607,25 @@ func (m *ServiceAccountRef) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthGenerated
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthGenerated
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Namespace = string(dAtA[iNdEx:postIndex])
+			m.Items = append(m.Items, UpstreamCluster{})
+			if err := m.Items[len(m.Items)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -3824,7 +8648,7 @@ func (m *ServiceAccountRef) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *TokenBucketFlowControlSchema) Unmarshal(dAtA []byte) error {
+func (m *UpstreamClusterServer) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3847,17 +8671,17 @@ func (m *TokenBucketFlowControlSchema) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: TokenBucketFlowControlSchema: wiretype end group for non-group")
+			return fmt.Errorf("proto: UpstreamClusterServer: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: TokenBucketFlowControlSchema: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: UpstreamClusterServer: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field QPS", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Endpoint", wireType)
 			}
-			m.QPS = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -3867,16 +8691,29 @@ func (m *TokenBucketFlowControlSchema) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.QPS |= int32(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Endpoint = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Burst", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Disabled", wireType)
 			}
-			m.Burst = 0
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -3886,11 +8723,33 @@ func (m *TokenBucketFlowControlSchema) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Burst |= int32(b&0x7F) << shift
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			b := bool(v != 0)
+			m.Disabled = &b
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Weight", wireType)
+			}
+			var v int32
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			m.Weight = &v
 		default:
 			iNdEx = preIndex
 			skippy, err := skipGenerated(dAtA[iNdEx:])
@@ -3912,7 +8771,7 @@ func (m *TokenBucketFlowControlSchema) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *UpstreamCluster) Unmarshal(dAtA []byte) error {
+func (m *UpstreamClusterSpec) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -3935,15 +8794,15 @@ func (m *UpstreamCluster) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: UpstreamCluster: wiretype end group for non-group")
+			return fmt.Errorf("proto: UpstreamClusterSpec: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: UpstreamCluster: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: UpstreamClusterSpec: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ObjectMeta", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Servers", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -3970,13 +8829,14 @@ func (m *UpstreamCluster) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.ObjectMeta.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Servers = append(m.Servers, UpstreamClusterServer{})
+			if err := m.Servers[len(m.Servers)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Spec", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ClientConfig", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -4003,13 +8863,13 @@ func (m *UpstreamCluster) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.Spec.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.ClientConfig.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
 		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field SecureServing", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -4036,63 +8896,46 @@ func (m *UpstreamCluster) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.Status.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.SecureServing.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipGenerated(dAtA[iNdEx:])
-			if err != nil {
-				return err
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FlowControl", wireType)
 			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
-				return ErrInvalidLengthGenerated
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
 			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *UpstreamClusterList) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowGenerated
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
 			}
-			if iNdEx >= l {
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
+			if err := m.FlowControl.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: UpstreamClusterList: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: UpstreamClusterList: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			iNdEx = postIndex
+		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ListMeta", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field DispatchPolicies", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -4119,13 +8962,14 @@ func (m *UpstreamClusterList) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.ListMeta.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.DispatchPolicies = append(m.DispatchPolicies, DispatchPolicy{})
+			if err := m.DispatchPolicies[len(m.DispatchPolicies)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 2:
+		case 6:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Items", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Logging", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -4152,66 +8996,55 @@ func (m *UpstreamClusterList) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Items = append(m.Items, UpstreamCluster{})
-			if err := m.Items[len(m.Items)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.Logging.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipGenerated(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
-				return ErrInvalidLengthGenerated
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MinHealthyEndpoints", wireType)
 			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *UpstreamClusterServer) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowGenerated
+			m.MinHealthyEndpoints = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MinHealthyEndpoints |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Disabled", wireType)
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: UpstreamClusterServer: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: UpstreamClusterServer: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			b := bool(v != 0)
+			m.Disabled = &b
+		case 9:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Endpoint", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Shadow", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -4221,29 +9054,33 @@ func (m *UpstreamClusterServer) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthGenerated
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthGenerated
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Endpoint = string(dAtA[iNdEx:postIndex])
+			if m.Shadow == nil {
+				m.Shadow = &ShadowConfig{}
+			}
+			if err := m.Shadow.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
-		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Disabled", wireType)
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Retry", wireType)
 			}
-			var v int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowGenerated
@@ -4253,66 +9090,31 @@ func (m *UpstreamClusterServer) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			b := bool(v != 0)
-			m.Disabled = &b
-		default:
-			iNdEx = preIndex
-			skippy, err := skipGenerated(dAtA[iNdEx:])
-			if err != nil {
-				return err
+			if msglen < 0 {
+				return ErrInvalidLengthGenerated
 			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
 				return ErrInvalidLengthGenerated
 			}
-			if (iNdEx + skippy) > l {
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *UpstreamClusterSpec) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowGenerated
-			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
+			if m.Retry == nil {
+				m.Retry = &RetryPolicy{}
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
+			if err := m.Retry.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: UpstreamClusterSpec: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: UpstreamClusterSpec: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			iNdEx = postIndex
+		case 11:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Servers", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Stub", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -4339,14 +9141,16 @@ func (m *UpstreamClusterSpec) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Servers = append(m.Servers, UpstreamClusterServer{})
-			if err := m.Servers[len(m.Servers)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if m.Stub == nil {
+				m.Stub = &StubConfig{}
+			}
+			if err := m.Stub.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 2:
+		case 12:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ClientConfig", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field APIResources", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -4373,13 +9177,16 @@ func (m *UpstreamClusterSpec) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.ClientConfig.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if m.APIResources == nil {
+				m.APIResources = &APIResourceConfig{}
+			}
+			if err := m.APIResources.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 3:
+		case 13:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SecureServing", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field HealthCheck", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -4406,13 +9213,16 @@ func (m *UpstreamClusterSpec) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.SecureServing.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if m.HealthCheck == nil {
+				m.HealthCheck = &HealthCheckPolicy{}
+			}
+			if err := m.HealthCheck.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 4:
+		case 14:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field FlowControl", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field HiddenResources", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -4439,13 +9249,16 @@ func (m *UpstreamClusterSpec) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.FlowControl.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if m.HiddenResources == nil {
+				m.HiddenResources = &HiddenResourceConfig{}
+			}
+			if err := m.HiddenResources.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 5:
+		case 15:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DispatchPolicies", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field OutlierDetection", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -4472,14 +9285,16 @@ func (m *UpstreamClusterSpec) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.DispatchPolicies = append(m.DispatchPolicies, DispatchPolicy{})
-			if err := m.DispatchPolicies[len(m.DispatchPolicies)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if m.OutlierDetection == nil {
+				m.OutlierDetection = &OutlierDetectionPolicy{}
+			}
+			if err := m.OutlierDetection.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 6:
+		case 16:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Logging", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Impersonation", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -4506,7 +9321,10 @@ func (m *UpstreamClusterSpec) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.Logging.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if m.Impersonation == nil {
+				m.Impersonation = &ImpersonationPolicy{}
+			}
+			if err := m.Impersonation.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -4581,6 +9399,120 @@ func (m *UpstreamClusterStatus) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *UserAgentFlowControlSchema) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowGenerated
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: UserAgentFlowControlSchema: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: UserAgentFlowControlSchema: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UserAgentPrefixes", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.UserAgentPrefixes = append(m.UserAgentPrefixes, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FlowControlSchemaName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowGenerated
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.FlowControlSchemaName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipGenerated(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthGenerated
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipGenerated(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
@@ -54,6 +54,288 @@ type UpstreamClusterSpec struct {
 	// 3. log mode in dispatchPolicy, it allows you control policy level log switch.
 	//    If it is off, all access logs of requests matching this policy will be disabled.
 	Logging LoggingConfig `json:"logging,omitempty" protobuf:"bytes,6,opt,name=logging"`
+
+	// MinHealthyEndpoints is the minimum number of healthy endpoints required to
+	// serve this cluster. If fewer endpoints are healthy, requests to this cluster
+	// will be rejected with 503 instead of overwhelming the surviving endpoints.
+	// Defaults to 0, which means no limit.
+	// +optional
+	MinHealthyEndpoints int32 `json:"minHealthyEndpoints,omitempty" protobuf:"varint,7,opt,name=minHealthyEndpoints"`
+
+	// Disabled marks the whole cluster down manually, all requests to this cluster will
+	// be rejected with 503. Health checking of endpoints keeps running, so endpoints
+	// status still shows whether the cluster recovers.
+	// +optional
+	Disabled *bool `json:"disabled,omitempty" protobuf:"varint,8,opt,name=disabled"`
+
+	// Shadow mirrors get and list requests to another cluster, e.g. a migration target,
+	// so that it can be verified with real traffic. Shadow responses are discarded and
+	// never affect clients.
+	// +optional
+	Shadow *ShadowConfig `json:"shadow,omitempty" protobuf:"bytes,9,opt,name=shadow"`
+
+	// Retry describes which upstream errors are retried on another endpoint.
+	// +optional
+	Retry *RetryPolicy `json:"retry,omitempty" protobuf:"bytes,10,opt,name=retry"`
+
+	// Stub makes the cluster a placeholder which is not backed by upstream servers yet,
+	// e.g. a hostname registered during onboarding before the real cluster is provisioned.
+	// All requests to a stub cluster are responded with 503 without being proxied, servers
+	// and dispatchPolicies are optional.
+	// +optional
+	Stub *StubConfig `json:"stub,omitempty" protobuf:"bytes,11,opt,name=stub"`
+
+	// APIResources overrides API resources which are proxied to the cluster, e.g. an upstream
+	// which does not serve some API versions. By default, all resource requests are proxied.
+	// +optional
+	APIResources *APIResourceConfig `json:"apiResources,omitempty" protobuf:"bytes,12,opt,name=apiResources"`
+
+	// HealthCheck describes how results of health checks decide whether an endpoint is
+	// healthy. By default, an endpoint becomes unhealthy after 3 consecutive failed health
+	// checks, and becomes healthy after a succeeded one.
+	// +optional
+	HealthCheck *HealthCheckPolicy `json:"healthCheck,omitempty" protobuf:"bytes,13,opt,name=healthCheck"`
+
+	// HiddenResources hides resources from some users more strictly than upstream RBAC,
+	// matched requests are responded with 404 by the gateway as if the resource does not
+	// exist, instead of a 403 from upstream which tells that the resource exists.
+	// +optional
+	HiddenResources *HiddenResourceConfig `json:"hiddenResources,omitempty" protobuf:"bytes,14,opt,name=hiddenResources"`
+
+	// OutlierDetection temporarily ejects endpoints which keep failing proxied requests from
+	// dispatching, independent of health checks.
+	// +optional
+	OutlierDetection *OutlierDetectionPolicy `json:"outlierDetection,omitempty" protobuf:"bytes,15,opt,name=outlierDetection"`
+
+	// Impersonation restricts users and groups which can be impersonated in this cluster, on
+	// top of the impersonate permission of the impersonator, e.g. to forbid impersonating
+	// system:masters.
+	// +optional
+	Impersonation *ImpersonationPolicy `json:"impersonation,omitempty" protobuf:"bytes,16,opt,name=impersonation"`
+}
+
+// ImpersonationPolicy restricts targets of impersonation requests. A user or group is forbidden
+// if it is denied, or allowed ones are set and it is not one of them. Requests impersonating a
+// forbidden target are rejected with 403 even if the impersonator is authorized to impersonate it.
+type ImpersonationPolicy struct {
+	// AllowedUsers are the only users which can be impersonated if it is set. Service accounts
+	// are matched by their user names, e.g. system:serviceaccount:default:builder.
+	// +optional
+	AllowedUsers []string `json:"allowedUsers,omitempty" protobuf:"bytes,1,rep,name=allowedUsers"`
+
+	// DeniedUsers are users which can not be impersonated.
+	// +optional
+	DeniedUsers []string `json:"deniedUsers,omitempty" protobuf:"bytes,2,rep,name=deniedUsers"`
+
+	// AllowedGroups are the only groups which can be impersonated if it is set.
+	// +optional
+	AllowedGroups []string `json:"allowedGroups,omitempty" protobuf:"bytes,3,rep,name=allowedGroups"`
+
+	// DeniedGroups are groups which can not be impersonated.
+	// +optional
+	DeniedGroups []string `json:"deniedGroups,omitempty" protobuf:"bytes,4,rep,name=deniedGroups"`
+}
+
+// OutlierDetectionPolicy describes how endpoints are ejected by passive health signals, i.e.
+// results of proxied requests. An ejected endpoint receives no requests until the ejection
+// ends, then it is half-open and receives a small share of requests to test recovery. It is
+// reinstated after a request succeeds, or ejected again after a request fails. Endpoints are
+// never all ejected, requests are dispatched to ejected endpoints if no other is ready.
+type OutlierDetectionPolicy struct {
+	// ConsecutiveErrors is the number of consecutive 5xx responses or connection errors
+	// for an endpoint to be ejected. Defaults to 5.
+	// +optional
+	ConsecutiveErrors int32 `json:"consecutiveErrors,omitempty" protobuf:"varint,1,opt,name=consecutiveErrors"`
+
+	// IntervalSeconds is the window in which consecutive errors are counted, errors spread
+	// over a longer time do not eject the endpoint. Defaults to 10.
+	// +optional
+	IntervalSeconds int32 `json:"intervalSeconds,omitempty" protobuf:"varint,2,opt,name=intervalSeconds"`
+
+	// EjectionSeconds is how long an endpoint is ejected before it is half-open. Defaults to 30.
+	// +optional
+	EjectionSeconds int32 `json:"ejectionSeconds,omitempty" protobuf:"varint,3,opt,name=ejectionSeconds"`
+
+	// HalfOpenPercent is the percentage of requests a half-open endpoint is considered
+	// for, from 1 to 100. Defaults to 10.
+	// +optional
+	HalfOpenPercent int32 `json:"halfOpenPercent,omitempty" protobuf:"varint,4,opt,name=halfOpenPercent"`
+}
+
+// HealthCheckPolicy describes how results of health checks decide endpoint health
+type HealthCheckPolicy struct {
+	// SuccessRateWindow is the number of recent health checks of an endpoint whose success
+	// rate decides its health, it tolerates occasional failures of upstreams with noisy
+	// readyz. It is at most 100, zero means only the last health check decides.
+	// +optional
+	SuccessRateWindow int32 `json:"successRateWindow,omitempty" protobuf:"varint,1,opt,name=successRateWindow"`
+
+	// MinSuccessPercent is the minimum percentage of succeeded health checks in the window
+	// for an endpoint to be healthy, from 1 to 100. Before the window is full, the rate is
+	// calculated with the health checks so far.
+	// +optional
+	MinSuccessPercent int32 `json:"minSuccessPercent,omitempty" protobuf:"varint,2,opt,name=minSuccessPercent"`
+
+	// IntervalMilliseconds is the interval between health checks of an endpoint. A longer
+	// interval reduces load on busy upstreams, and a shorter one finds failures sooner.
+	// It is at least 100 if it is set, defaults to 5000.
+	// +optional
+	IntervalMilliseconds int32 `json:"intervalMilliseconds,omitempty" protobuf:"varint,3,opt,name=intervalMilliseconds"`
+
+	// TimeoutMilliseconds is the timeout of a health check request, it is at least 100 if
+	// it is set, defaults to 5000.
+	// +optional
+	TimeoutMilliseconds int32 `json:"timeoutMilliseconds,omitempty" protobuf:"varint,4,opt,name=timeoutMilliseconds"`
+
+	// Path is the path requested by health checks, e.g. /readyz, /livez, or a custom probe
+	// path of the load balancer in front of upstream servers. It must start with "/".
+	// Defaults to /healthz, or /readyz if verbose readyz is enabled for the gateway.
+	// +optional
+	Path string `json:"path,omitempty" protobuf:"bytes,5,opt,name=path"`
+
+	// FallbackToHealthz requests /healthz instead if /readyz is not found, e.g. on
+	// apiservers older than v1.16. It only applies to health checks requesting /readyz.
+	// +optional
+	FallbackToHealthz bool `json:"fallbackToHealthz,omitempty" protobuf:"varint,6,opt,name=fallbackToHealthz"`
+
+	// UnhealthyThreshold is the number of consecutive failed health checks for a healthy
+	// endpoint to become unhealthy, it keeps the endpoint from flapping on brief network
+	// blips. Defaults to 3. It can not be set together with successRateWindow.
+	// +optional
+	UnhealthyThreshold int32 `json:"unhealthyThreshold,omitempty" protobuf:"varint,7,opt,name=unhealthyThreshold"`
+
+	// HealthyThreshold is the number of consecutive succeeded health checks for an unhealthy
+	// endpoint to become healthy. Defaults to 1. It can not be set together with successRateWindow.
+	// +optional
+	HealthyThreshold int32 `json:"healthyThreshold,omitempty" protobuf:"varint,8,opt,name=healthyThreshold"`
+
+	// MaxBackoffMilliseconds caps the interval between health checks of an unhealthy endpoint,
+	// which doubles after each health check until the endpoint recovers. A longer cap reduces
+	// load on endpoints down for long, and a shorter one finds their recovery sooner. It is at
+	// least 100 if it is set, defaults to 30000, and the interval is never shortened by it.
+	// +optional
+	MaxBackoffMilliseconds int32 `json:"maxBackoffMilliseconds,omitempty" protobuf:"varint,9,opt,name=maxBackoffMilliseconds"`
+}
+
+// APIResourceConfig describes API resources which are served by the upstream cluster
+type APIResourceConfig struct {
+	// Rules is an allowlist of API resources served by the upstream cluster. Resource requests
+	// matching none of rules are rejected with 404 by the gateway instead of being proxied.
+	// Non-resource requests, e.g. discovery and /healthz, are always proxied.
+	Rules []APIResourceRule `json:"rules" protobuf:"bytes,1,rep,name=rules"`
+}
+
+// APIResourceRule matches resources of API group versions
+type APIResourceRule struct {
+	// APIGroups is a list of API groups, "" is the core group.
+	// - "*" represents all APIGroups.
+	// - use '-' prefix to invert apiGroups matching, e.g. "-apps" means match all apiGroups except "apps"
+	APIGroups []string `json:"apiGroups" protobuf:"bytes,1,rep,name=apiGroups"`
+
+	// APIVersions is a list of API versions in APIGroups.
+	// - "*" represents all APIVersions.
+	// - use '-' prefix to invert apiVersions matching, e.g. "-v1beta1" means match all apiVersions except "v1beta1"
+	APIVersions []string `json:"apiVersions" protobuf:"bytes,2,rep,name=apiVersions"`
+
+	// Resources is a list of resources in APIGroups and APIVersions.
+	// - "*" represents all Resources.
+	// - use "{resource}/{subresource}" to match one resource's subresource
+	// - use "*/{subresource}" to match all resources' subresource, but "{resource}/*" is not allowed.
+	// - use '-' prefix to invert resources matching, e.g. "-deployments" means match all resources except "deployments".
+	Resources []string `json:"resources" protobuf:"bytes,3,rep,name=resources"`
+}
+
+// HiddenResourceConfig describes requests which are responded as if the resource does not exist
+type HiddenResourceConfig struct {
+	// Rules match verbs, resources and users of requests the same as rules of dispatch policies,
+	// requests matching any of them are rejected with 404 before being dispatched.
+	Rules []DispatchPolicyRule `json:"rules" protobuf:"bytes,1,rep,name=rules"`
+}
+
+// StubConfig describes responses of a stub cluster
+type StubConfig struct {
+	// Message is the message of 503 responses, defaults to "cluster(<name>) is being provisioned".
+	// +optional
+	Message string `json:"message,omitempty" protobuf:"bytes,1,opt,name=message"`
+	// RetryAfterSeconds is the value of Retry-After header in 503 responses.
+	// +optional
+	RetryAfterSeconds int32 `json:"retryAfterSeconds,omitempty" protobuf:"varint,2,opt,name=retryAfterSeconds"`
+}
+
+// RetryPolicy describes transient upstream errors which should be retried on another
+// ready endpoint instead of being responded to clients, e.g. "etcdserver: leader changed"
+// or "apiserver is shutting down". Only idempotent requests (get, list and watch) and
+// requests of opted-in verbs whose body can be replayed are retried.
+type RetryPolicy struct {
+	// StatusReasons are reasons of upstream Status responses to retry, e.g. ServiceUnavailable.
+	// +optional
+	StatusReasons []string `json:"statusReasons,omitempty" protobuf:"bytes,1,rep,name=statusReasons"`
+
+	// StatusMessages are substrings of upstream Status messages to retry, e.g. "leader changed".
+	// +optional
+	StatusMessages []string `json:"statusMessages,omitempty" protobuf:"bytes,2,rep,name=statusMessages"`
+
+	// TooManyRequests decides how 429 responses of upstreams are handled, e.g. requests
+	// throttled by API priority and fairness of an upstream. Valid values are:
+	// - PassThrough: responds them to clients;
+	// - Retry: retries them on another ready endpoint, they are responded to clients if
+	//   there are no more endpoints to retry;
+	// - Backoff: delays them by TooManyRequestsBackoffSeconds before responding to clients.
+	// Defaults to PassThrough.
+	// +optional
+	TooManyRequests TooManyRequestsPolicy `json:"tooManyRequests,omitempty" protobuf:"bytes,3,opt,name=tooManyRequests,casttype=TooManyRequestsPolicy"`
+
+	// TooManyRequestsBackoffSeconds is how long 429 responses of upstreams are delayed with
+	// the Backoff policy. Defaults to 1 if it is 0.
+	// +optional
+	TooManyRequestsBackoffSeconds int32 `json:"tooManyRequestsBackoffSeconds,omitempty" protobuf:"varint,4,opt,name=tooManyRequestsBackoffSeconds"`
+
+	// StatusCodes are status codes of upstream error responses to retry no matter what
+	// their bodies are, e.g. 503 responded by a load balancer in front of apiservers.
+	// +optional
+	StatusCodes []int32 `json:"statusCodes,omitempty" protobuf:"varint,5,rep,name=statusCodes"`
+
+	// ConnectionErrors retries requests which fail with connection-level errors, e.g. the
+	// connection to the endpoint is refused or reset.
+	// +optional
+	ConnectionErrors bool `json:"connectionErrors,omitempty" protobuf:"varint,6,opt,name=connectionErrors"`
+
+	// Verbs are verbs of requests to retry in addition to get, list and watch, e.g. create.
+	// Requests which are not idempotent may be applied twice if the upstream fails after
+	// handling them.
+	// +optional
+	Verbs []string `json:"verbs,omitempty" protobuf:"bytes,7,rep,name=verbs"`
+
+	// MaxRetries is the maximum number of times a request is retried on other endpoints.
+	// Defaults to 2 if it is 0.
+	// +optional
+	MaxRetries int32 `json:"maxRetries,omitempty" protobuf:"varint,8,opt,name=maxRetries"`
+}
+
+// TooManyRequestsPolicy describes how 429 responses of upstreams are handled
+type TooManyRequestsPolicy string
+
+const (
+	TooManyRequestsPassThrough TooManyRequestsPolicy = "PassThrough"
+	TooManyRequestsRetry       TooManyRequestsPolicy = "Retry"
+	TooManyRequestsBackoff     TooManyRequestsPolicy = "Backoff"
+)
+
+// ShadowConfig describes how requests are mirrored to a shadow cluster
+type ShadowConfig struct {
+	// Cluster is the name of the shadow UpstreamCluster, it must be proxied by the
+	// same gateway.
+	Cluster string `json:"cluster" protobuf:"bytes,1,opt,name=cluster"`
+
+	// Percentage of get and list requests mirrored to the shadow cluster, from 0 to 100.
+	// Defaults to 100 if it is 0.
+	// +optional
+	Percentage int32 `json:"percentage,omitempty" protobuf:"varint,2,opt,name=percentage"`
+
+	// CompareResponses compares status code and body hash of shadow responses with the
+	// primary ones, and logs the differences.
+	// +optional
+	CompareResponses bool `json:"compareResponses,omitempty" protobuf:"varint,3,opt,name=compareResponses"`
 }
 
 type LogMode string
@@ -72,6 +354,13 @@ type LoggingConfig struct {
 	//   can be override by dispatchPolicy.LogMode
 	// - if unset, the logging is controlled by dispatchPolicy.LogMode
 	Mode LogMode `json:"mode,omitempty" protobuf:"bytes,1,opt,name=mode,casttype=LogMode"`
+	// UserGroups decides whether groups of the authenticated user are logged in access logs
+	// of requests to this cluster, e.g. for compliance reporting on group-based access.
+	// - if set to on, user groups are logged even if the userGroup field is not selected by gateway flags.
+	// - if set to off, user groups are not logged.
+	// - if unset, it follows the access log fields selected by gateway flags.
+	// +optional
+	UserGroups LogMode `json:"userGroups,omitempty" protobuf:"bytes,2,opt,name=userGroups,casttype=LogMode"`
 }
 
 type SecureServing struct {
@@ -84,8 +373,30 @@ type SecureServing struct {
 	// ClientCAData contains PEM-encoded data from a ca file for TLS.
 	// The serialized form of data is a base64 encoded string
 	ClientCAData []byte `json:"clientCAData,omitempty" protobuf:"bytes,3,opt,name=clientCAData"`
+	// ClientAuth decides how client certificates are handled in TLS handshake when ClientCAData is set.
+	// - Request: client certificates are requested but not required, connections without a valid
+	//   certificate can still be authenticated by other methods such as tokens.
+	// - RequireAndVerify: connections without a certificate signed by ClientCAData are rejected
+	//   in TLS handshake.
+	// Defaults to Request if unset.
+	// +optional
+	ClientAuth ClientAuthMode `json:"clientAuth,omitempty" protobuf:"bytes,4,opt,name=clientAuth,casttype=ClientAuthMode"`
+	// APIAudiences are the audiences required of bearer tokens authenticated for this cluster,
+	// a token is accepted only if the upstream token review returns at least one of them, so
+	// that a token issued for another cluster is not accepted. They override the global API
+	// audiences of the gateway for this cluster. Upstream apiservers must support audiences in
+	// token reviews.
+	// +optional
+	APIAudiences []string `json:"apiAudiences,omitempty" protobuf:"bytes,5,rep,name=apiAudiences"`
 }
 
+type ClientAuthMode string
+
+const (
+	ClientAuthRequest          ClientAuthMode = "Request"
+	ClientAuthRequireAndVerify ClientAuthMode = "RequireAndVerify"
+)
+
 type ClientConfig struct {
 	// Server should be accessed without verifying the TLS certificate. For testing only.
 	Insecure bool `json:"insecure,omitempty" protobuf:"varint,1,opt,name=insecure"`
@@ -114,10 +425,94 @@ type ClientConfig struct {
 	// It allows you to set a more precise qps, like 0.01 (qps:1, qpsDivisor:100)
 	// +optional
 	QPSDivisor int32 `json:"qpsDivisor,omitempty" protobuf:"varint,8,opt,name=qpsDivisor"`
+	// TLSSessionCacheSize is the capacity of the TLS client session cache of each
+	// upstream endpoint, sessions are resumed to reduce handshake overhead.
+	// Zero means session resumption is disabled.
+	// +optional
+	TLSSessionCacheSize int32 `json:"tlsSessionCacheSize,omitempty" protobuf:"varint,9,opt,name=tlsSessionCacheSize"`
+	// TLSRenegotiation is the renegotiation policy of TLS connections to upstream
+	// endpoints, valid values are Never, OnceAsClient and FreelyAsClient.
+	// Defaults to Never.
+	// +optional
+	TLSRenegotiation TLSRenegotiationPolicy `json:"tlsRenegotiation,omitempty" protobuf:"bytes,10,opt,name=tlsRenegotiation,casttype=TLSRenegotiationPolicy"`
+	// H2C proxies requests to plaintext (http) upstream endpoints with HTTP/2 prior
+	// knowledge instead of HTTP/1.1. Upgrade requests, e.g. exec and port-forward,
+	// are still proxied with HTTP/1.1. It is only allowed with http scheme.
+	// +optional
+	H2C bool `json:"h2c,omitempty" protobuf:"varint,11,opt,name=h2c"`
+	// DisableKeepAlives closes the upstream connection after each request so that
+	// every request is sent on a new connection. It is a workaround for upstreams or
+	// load balancers which handle keep-alive connections poorly, it should be used
+	// with care because of the cost of dialing and TLS handshakes. It is not allowed
+	// with h2c. Defaults to false.
+	// +optional
+	DisableKeepAlives bool `json:"disableKeepAlives,omitempty" protobuf:"varint,12,opt,name=disableKeepAlives"`
+	// SourceAddress is the local IP address which connections to upstream endpoints
+	// are dialed from, e.g. on multi-homed hosts where upstream firewalls only allow
+	// a given source IP. Connections are dialed from the default interface if it is
+	// empty.
+	// +optional
+	SourceAddress string `json:"sourceAddress,omitempty" protobuf:"bytes,13,opt,name=sourceAddress"`
 }
 
+// TLSRenegotiationPolicy describes the TLS renegotiation supported by upstream connections
+type TLSRenegotiationPolicy string
+
+const (
+	// TLSRenegotiateNever disables renegotiation.
+	TLSRenegotiateNever TLSRenegotiationPolicy = "Never"
+	// TLSRenegotiateOnceAsClient allows a remote server to request renegotiation once per connection.
+	TLSRenegotiateOnceAsClient TLSRenegotiationPolicy = "OnceAsClient"
+	// TLSRenegotiateFreelyAsClient allows a remote server to repeatedly request renegotiation.
+	TLSRenegotiateFreelyAsClient TLSRenegotiationPolicy = "FreelyAsClient"
+)
+
 type FlowControl struct {
 	Schemas []FlowControlSchema `json:"flowControlSchemas,omitempty" protobuf:"bytes,1,rep,name=flowControlSchemas"`
+
+	// NamespaceSchemas overrides the flow control schema of the matched dispatch policy for
+	// requests to the given namespaces, e.g. protecting control plane traffic in kube-system
+	// from being throttled with workloads. Only the first matched one takes effect.
+	// +optional
+	NamespaceSchemas []NamespaceFlowControlSchema `json:"namespaceSchemas,omitempty" protobuf:"bytes,2,rep,name=namespaceSchemas"`
+
+	// RejectionResponse customizes responses to requests rejected by flow control
+	// schemas of this cluster.
+	// +optional
+	RejectionResponse *RejectionResponse `json:"rejectionResponse,omitempty" protobuf:"bytes,3,opt,name=rejectionResponse"`
+
+	// UserAgentSchemas overrides the flow control schema of the matched dispatch policy for
+	// requests from clients with the given User-Agent, e.g. prioritizing kube-controller-manager
+	// and kube-scheduler over kubectl under load. They take precedence over NamespaceSchemas.
+	// Only the first matched one takes effect.
+	// User-Agent is set by clients and is not authenticated, so it should be used to prioritize
+	// trusted traffic only.
+	// +optional
+	UserAgentSchemas []UserAgentFlowControlSchema `json:"userAgentSchemas,omitempty" protobuf:"bytes,4,rep,name=userAgentSchemas"`
+}
+
+// UserAgentFlowControlSchema binds a flow control schema to clients with matched User-Agent
+type UserAgentFlowControlSchema struct {
+	// UserAgentPrefixes are prefixes of User-Agent header of requests this schema applies to,
+	// e.g. "kube-controller-manager/".
+	UserAgentPrefixes []string `json:"userAgentPrefixes" protobuf:"bytes,1,rep,name=userAgentPrefixes"`
+
+	// FlowControlSchemaName indicates to which flow control schema in spec.FlowControl will
+	// take effect on requests from these clients. It is required because User-Agent can not
+	// be trusted to exempt requests from flow control.
+	FlowControlSchemaName string `json:"flowControlSchemaName" protobuf:"bytes,2,opt,name=flowControlSchemaName"`
+}
+
+// NamespaceFlowControlSchema binds a flow control schema to a set of namespaces
+type NamespaceFlowControlSchema struct {
+	// Namespaces of requests this schema applies to
+	Namespaces []string `json:"namespaces" protobuf:"bytes,1,rep,name=namespaces"`
+
+	// FlowControlSchemaName indicates to which flow control schema in spec.FlowControl will
+	// take effect on requests to these namespaces.
+	// If not set, these requests are exempt from flow control.
+	// +optional
+	FlowControlSchemaName string `json:"flowControlSchemaName,omitempty" protobuf:"bytes,2,opt,name=flowControlSchemaName"`
 }
 
 type FlowControlSchema struct {
@@ -125,6 +520,27 @@ type FlowControlSchema struct {
 	Name string `json:"name,omitempty" protobuf:"bytes,1,opt,name=name"`
 	// Schema config
 	FlowControlSchemaConfiguration `json:",inline" protobuf:"bytes,2,opt,name=flowControlSchemaConfiguration"`
+	// RejectionStatusCode is the http status code responded to requests rejected by
+	// this schema, only 429 and 503 are allowed. Defaults to 429.
+	// Retry-After header is always set no matter which code is used.
+	// +optional
+	RejectionStatusCode int32 `json:"rejectionStatusCode,omitempty" protobuf:"varint,3,opt,name=rejectionStatusCode"`
+	// RejectionResponse customizes responses to requests rejected by this schema, it takes
+	// precedence over spec.flowControl.rejectionResponse.
+	// +optional
+	RejectionResponse *RejectionResponse `json:"rejectionResponse,omitempty" protobuf:"bytes,4,opt,name=rejectionResponse"`
+}
+
+// RejectionResponse customizes responses to requests rejected by flow control
+type RejectionResponse struct {
+	// Message replaces the message of Status in response body, it can give clients
+	// actionable guidance, e.g. a link to request quota increases.
+	// +optional
+	Message string `json:"message,omitempty" protobuf:"bytes,1,opt,name=message"`
+	// Headers are additional headers set in responses. Retry-After, Content-Type and
+	// Content-Length can not be set.
+	// +optional
+	Headers map[string]string `json:"headers,omitempty" protobuf:"bytes,2,rep,name=headers"`
 }
 
 // Represents the configuration of flow control schema
@@ -142,6 +558,14 @@ type FlowControlSchemaConfiguration struct {
 	// rate of 'qps'.
 	// +optianal
 	TokenBucket *TokenBucketFlowControlSchema `json:"tokenBucket,omitempty" protobuf:"bytes,3,opt,name=tokenBucket"`
+	// MaxRequestsInflightByVerb represents separate maximum concurrent numbers of
+	// mutating and read-only requests in flight at a given time.
+	// +optianal
+	MaxRequestsInflightByVerb *MaxRequestsInflightByVerbFlowControlSchema `json:"maxRequestsInflightByVerb,omitempty" protobuf:"bytes,4,opt,name=maxRequestsInflightByVerb"`
+	// Queued represents a maximum concurrent number of requests in flight, requests
+	// over it wait in queues instead of being rejected immediately.
+	// +optianal
+	Queued *QueuedFlowControlSchema `json:"queued,omitempty" protobuf:"bytes,5,opt,name=queued"`
 }
 
 // Represents flow control schema type
@@ -152,10 +576,21 @@ const (
 	Exempt              FlowControlSchemaType = "Exempt"
 	MaxRequestsInflight FlowControlSchemaType = "MaxRequestsInflight"
 	TokenBucket         FlowControlSchemaType = "TokenBucket"
+	// MaxRequestsInflightByVerb limits mutating and read-only requests separately
+	MaxRequestsInflightByVerb FlowControlSchemaType = "MaxRequestsInflightByVerb"
+	// Queued queues requests over the concurrency limit like API priority and fairness
+	Queued FlowControlSchemaType = "Queued"
 )
 
 // Represents no limit flow control.
+// Exempt requests are still counted, and they can be capped by an optional
+// high safety ceiling to prevent truly unbounded load.
 type ExemptFlowControlSchema struct {
+	// MaxRequestsInflight is an optional safety ceiling of concurrent requests in flight,
+	// it is supposed to be much higher than normal limits.
+	// Defaults to 0, which means no limit.
+	// +optional
+	MaxRequestsInflight int32 `json:"maxRequestsInflight,omitempty" protobuf:"varint,1,opt,name=maxRequestsInflight"`
 }
 
 // Represents a maximum concurrent number of requests in flight at a given time.
@@ -164,6 +599,42 @@ type MaxRequestsInflightFlowControlSchema struct {
 	Max int32 `json:"max,omitempty" protobuf:"varint,1,opt,name=max"`
 }
 
+// Represents separate maximum concurrent numbers of mutating and read-only requests in
+// flight, like --max-mutating-requests-inflight and --max-requests-inflight of kube-apiserver,
+// so that the write path of upstreams is protected independently. Requests with get, list
+// and watch verbs are read-only, the others are mutating.
+type MaxRequestsInflightByVerbFlowControlSchema struct {
+	// maximum concurrent number of mutating requests
+	MaxMutating int32 `json:"maxMutating,omitempty" protobuf:"varint,1,opt,name=maxMutating"`
+	// maximum concurrent number of read-only requests
+	MaxReadOnly int32 `json:"maxReadOnly,omitempty" protobuf:"varint,2,opt,name=maxReadOnly"`
+}
+
+// Represents a maximum concurrent number of requests in flight, requests over it wait in
+// queues and are admitted as capacity frees, like API priority and fairness of kube-apiserver.
+// Requests are shuffle sharded into queues by user, each user is dealt handSize of the queues
+// and enqueues into the shortest one, and queues take turns to be admitted, so that a user
+// flooding requests does not starve the others. Requests are rejected if the queue is full,
+// or they have waited for maxWaitSeconds.
+type QueuedFlowControlSchema struct {
+	// maximum concurrent number of requests
+	Concurrency int32 `json:"concurrency,omitempty" protobuf:"varint,1,opt,name=concurrency"`
+	// QueueLength is the maximum number of requests waiting in each queue. Defaults to 50.
+	// +optional
+	QueueLength int32 `json:"queueLength,omitempty" protobuf:"varint,2,opt,name=queueLength"`
+	// Queues is the number of queues. Defaults to 64.
+	// +optional
+	Queues int32 `json:"queues,omitempty" protobuf:"varint,3,opt,name=queues"`
+	// HandSize is the number of queues dealt to each user, it is at most queues. Defaults
+	// to 8, or queues if there are fewer.
+	// +optional
+	HandSize int32 `json:"handSize,omitempty" protobuf:"varint,4,opt,name=handSize"`
+	// MaxWaitSeconds is how long a request waits in queue at most before it is rejected.
+	// Defaults to 15.
+	// +optional
+	MaxWaitSeconds int32 `json:"maxWaitSeconds,omitempty" protobuf:"varint,5,opt,name=maxWaitSeconds"`
+}
+
 // Represents token bucket rate limit approach.
 type TokenBucketFlowControlSchema struct {
 	// QPS indicates the maximum QPS to the master from this client.
@@ -173,8 +644,35 @@ type TokenBucketFlowControlSchema struct {
 	// This value must be bigger than QPS if QPS is not 0
 	// +optional
 	Burst int32 `json:"burst,omitempty" protobuf:"varint,2,opt,name=burst"`
+	// SlowStartSeconds is the duration for the rate limiter to ramp up linearly to
+	// the new qps and burst after they are raised, so that the upstream is not hit
+	// by a burst right after a limit is raised. Lowering them takes effect immediately.
+	// Defaults to 0, which means raising takes effect immediately too.
+	// +optional
+	SlowStartSeconds int32 `json:"slowStartSeconds,omitempty" protobuf:"varint,3,opt,name=slowStartSeconds"`
+	// KeyBy makes the rate limiter keep a token bucket of the qps and burst for each key
+	// of requests instead of sharing one bucket among all of them, so that a single client
+	// can not exhaust the limit of others. Buckets of keys idle for a while are dropped.
+	// Defaults to empty, which means all requests share one bucket.
+	// +optional
+	KeyBy TokenBucketKey `json:"keyBy,omitempty" protobuf:"bytes,4,opt,name=keyBy,casttype=TokenBucketKey"`
 }
 
+// TokenBucketKey is the key of requests which share a token bucket
+type TokenBucketKey string
+
+const (
+	// TokenBucketKeyUser keys requests by the name of the authenticated user
+	TokenBucketKeyUser TokenBucketKey = "User"
+	// TokenBucketKeySourceIP keys requests by the client IP
+	TokenBucketKeySourceIP TokenBucketKey = "SourceIP"
+	// TokenBucketKeyGroup keys requests by each group of the authenticated user, a request
+	// takes a token from the bucket of every group the user belongs to and it is rejected if
+	// any of them is exhausted. system:authenticated and system:unauthenticated are ignored as
+	// every user belongs to one of them, users without other groups share one bucket.
+	TokenBucketKeyGroup TokenBucketKey = "Group"
+)
+
 type SecretReferecence struct {
 	// `namespace` is the namespace of the secret.
 	// Required
@@ -190,6 +688,12 @@ type UpstreamClusterServer struct {
 	// Disabled marks the server as permanently unavailable.
 	// +optional
 	Disabled *bool `json:"disabled,omitempty" protobuf:"varint,2,opt,name=disabled"`
+	// Weight is the relative weight of the server in dispatch policies with the
+	// WeightedRandom and LatencyWeightedRandom strategies. Defaults to 1. A server
+	// with weight 0 is drained, no requests are dispatched to it by any strategy,
+	// but it is still health checked.
+	// +optional
+	Weight *int32 `json:"weight,omitempty" protobuf:"varint,3,opt,name=weight"`
 }
 
 type DispatchPolicy struct {
@@ -219,12 +723,33 @@ type DispatchPolicy struct {
 	// - if set to unset, the logging is controlled by spec.Logging.Mode
 	// +optional
 	LogMode LogMode `json:"logMode,omitempty" protobuf:"bytes,5,opt,name=logMode,casttype=LogMode"`
+
+	// Name is an optional name of this policy, it must be unique in a cluster.
+	// It is used as the policy label of proxy request metrics. If not set,
+	// the policy index in the list is used instead, e.g. "#0".
+	// +optional
+	Name string `json:"name,omitempty" protobuf:"bytes,6,opt,name=name"`
 }
 
 type Strategy string
 
 const (
+	// RoundRobin cycles through ready endpoints for each request. The rotation is kept
+	// per cluster and starts over when servers change, endpoints which become unready
+	// are skipped at the next pick.
 	RoundRobin Strategy = "RoundRobin"
+	// WeightedRandom picks a random endpoint for each request in proportion to
+	// the weights of servers. Endpoints are picked per request rather than per
+	// connection, streams of a multiplexed HTTP/2 connection are spread too.
+	WeightedRandom Strategy = "WeightedRandom"
+	// LeastConnections picks the ready endpoint with the fewest in-flight requests, ties are
+	// broken at random. It keeps long-running requests like watches and exec from piling up
+	// on one endpoint.
+	LeastConnections Strategy = "LeastConnections"
+	// LatencyWeightedRandom is the same as WeightedRandom, but the weight of an endpoint is
+	// reduced automatically when its health check latency rises above the lowest one among
+	// ready endpoints, and restored as it recovers. Weights are never reduced below 1/10.
+	LatencyWeightedRandom Strategy = "LatencyWeightedRandom"
 )
 
 // DispatchPolicyRule holds information that describes a policy rule
@@ -16,10 +16,15 @@ package validation
 
 import (
 	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
 	"strings"
 
 	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	certutil "k8s.io/client-go/util/cert"
 	apivalidation "k8s.io/kubernetes/pkg/apis/core/validation"
@@ -30,6 +35,9 @@ import (
 func ValidateUpstreamCluster(cluster *proxyv1alpha1.UpstreamCluster) field.ErrorList {
 	allErrs := apivalidation.ValidateObjectMeta(&cluster.ObjectMeta, false, apimachineryvalidation.NameIsDNSSubdomain, field.NewPath("metadata"))
 	allErrs = append(allErrs, ValidateUpstreamClusterSpec(&cluster.Spec, field.NewPath("spec"))...)
+	if cluster.Spec.Shadow != nil && strings.EqualFold(cluster.Spec.Shadow.Cluster, cluster.Name) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "shadow", "cluster"), cluster.Spec.Shadow.Cluster, "shadow cluster can not be the cluster itself"))
+	}
 	return allErrs
 }
 
@@ -37,9 +45,12 @@ func ValidateUpstreamCluster(cluster *proxyv1alpha1.UpstreamCluster) field.Error
 func ValidateUpstreamClusterSpec(spec *proxyv1alpha1.UpstreamClusterSpec, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
+	// servers and dispatch policies are optional for stub clusters
+	isEmptyStub := spec.Stub != nil && len(spec.Servers) == 0
 	upstreams, scheme, errs := ValidateServers(spec.Servers, fldPath.Child("servers"))
-
-	allErrs = append(allErrs, errs...)
+	if !isEmptyStub {
+		allErrs = append(allErrs, errs...)
+	}
 	allErrs = append(allErrs, ValidateClientConfig(scheme, &spec.ClientConfig, fldPath.Child("clientConfig"))...)
 	allErrs = append(allErrs, ValidateSecureServing(&spec.SecureServing, fldPath.Child("secureServing"))...)
 
@@ -47,11 +58,250 @@ func ValidateUpstreamClusterSpec(spec *proxyv1alpha1.UpstreamClusterSpec, fldPat
 	allErrs = append(allErrs, errs...)
 	allErrs = append(allErrs, ValidateLoggingConfig(spec.Logging, fldPath.Child("logging"))...)
 
-	if len(spec.DispatchPolicies) == 0 {
+	if spec.MinHealthyEndpoints < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("minHealthyEndpoints"), spec.MinHealthyEndpoints, "must be greater than or equal to 0"))
+	} else if int(spec.MinHealthyEndpoints) > len(spec.Servers) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("minHealthyEndpoints"), spec.MinHealthyEndpoints, "must be less than or equal to the number of servers"))
+	}
+
+	if spec.Shadow != nil {
+		allErrs = append(allErrs, ValidateShadowConfig(spec.Shadow, fldPath.Child("shadow"))...)
+	}
+	if spec.Retry != nil {
+		allErrs = append(allErrs, ValidateRetryPolicy(spec.Retry, fldPath.Child("retry"))...)
+	}
+
+	if spec.Stub != nil {
+		allErrs = append(allErrs, ValidateStubConfig(spec.Stub, fldPath.Child("stub"))...)
+	}
+
+	if spec.APIResources != nil {
+		allErrs = append(allErrs, ValidateAPIResourceConfig(spec.APIResources, fldPath.Child("apiResources"))...)
+	}
+
+	if spec.HealthCheck != nil {
+		allErrs = append(allErrs, ValidateHealthCheckPolicy(spec.HealthCheck, fldPath.Child("healthCheck"))...)
+	}
+
+	if spec.OutlierDetection != nil {
+		allErrs = append(allErrs, ValidateOutlierDetectionPolicy(spec.OutlierDetection, fldPath.Child("outlierDetection"))...)
+	}
+
+	if spec.HiddenResources != nil {
+		allErrs = append(allErrs, ValidateHiddenResourceConfig(spec.HiddenResources, fldPath.Child("hiddenResources"))...)
+	}
+
+	if spec.Impersonation != nil {
+		allErrs = append(allErrs, ValidateImpersonationPolicy(spec.Impersonation, fldPath.Child("impersonation"))...)
+	}
+
+	if len(spec.DispatchPolicies) == 0 && spec.Stub == nil {
 		allErrs = append(allErrs, field.Required(fldPath.Child("dispatchPolicies"), "resource must supply at least one dispatch policy"))
 	}
+	policyNames := sets.NewString()
 	for i, policy := range spec.DispatchPolicies {
 		allErrs = append(allErrs, ValidateDispatchPolicy(upstreams, flowControlSchemaNames, policy, fldPath.Child("dispatchPolicies").Index(i))...)
+		if len(policy.Name) == 0 {
+			continue
+		}
+		if policyNames.Has(policy.Name) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("dispatchPolicies").Index(i).Child("name"), policy.Name))
+		}
+		policyNames.Insert(policy.Name)
+	}
+	return allErrs
+}
+
+func ValidateShadowConfig(shadow *proxyv1alpha1.ShadowConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(shadow.Cluster) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("cluster"), "shadow cluster name must be set"))
+	} else {
+		for _, msg := range validation.IsDNS1123Subdomain(shadow.Cluster) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("cluster"), shadow.Cluster, msg))
+		}
+	}
+	if shadow.Percentage < 0 || shadow.Percentage > 100 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("percentage"), shadow.Percentage, "must be between 0 and 100, inclusive"))
+	}
+	return allErrs
+}
+
+// headers which are not allowed to be customized in rejection responses
+var reservedRejectionHeaders = sets.NewString("Retry-After", "Content-Type", "Content-Length")
+
+func ValidateRejectionResponse(response *proxyv1alpha1.RejectionResponse, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for name := range response.Headers {
+		for _, msg := range validation.IsHTTPHeaderName(name) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("headers").Key(name), name, msg))
+		}
+		if reservedRejectionHeaders.Has(http.CanonicalHeaderKey(name)) {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("headers").Key(name), "this header can not be customized"))
+		}
+	}
+	return allErrs
+}
+
+func ValidateRetryPolicy(retry *proxyv1alpha1.RetryPolicy, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, reason := range retry.StatusReasons {
+		if len(reason) == 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("statusReasons").Index(i), reason, "must not be empty"))
+		}
+	}
+	for i, message := range retry.StatusMessages {
+		if len(message) == 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("statusMessages").Index(i), message, "must not be empty"))
+		}
+	}
+	switch retry.TooManyRequests {
+	case "", proxyv1alpha1.TooManyRequestsPassThrough, proxyv1alpha1.TooManyRequestsRetry, proxyv1alpha1.TooManyRequestsBackoff:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("tooManyRequests"), retry.TooManyRequests, []string{
+			string(proxyv1alpha1.TooManyRequestsPassThrough),
+			string(proxyv1alpha1.TooManyRequestsRetry),
+			string(proxyv1alpha1.TooManyRequestsBackoff),
+		}))
+	}
+	if retry.TooManyRequestsBackoffSeconds < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("tooManyRequestsBackoffSeconds"), retry.TooManyRequestsBackoffSeconds, "must be greater than or equal to 0"))
+	}
+	for i, code := range retry.StatusCodes {
+		if code < 400 || code > 599 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("statusCodes").Index(i), code, "must be an error status code between 400 and 599"))
+		}
+	}
+	for i, verb := range retry.Verbs {
+		if len(verb) == 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("verbs").Index(i), verb, "must not be empty"))
+		}
+	}
+	if retry.MaxRetries < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxRetries"), retry.MaxRetries, "must be greater than or equal to 0"))
+	}
+	return allErrs
+}
+
+func ValidateStubConfig(stub *proxyv1alpha1.StubConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if stub.RetryAfterSeconds < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("retryAfterSeconds"), stub.RetryAfterSeconds, "must be greater than or equal to 0"))
+	}
+	return allErrs
+}
+
+// maxHealthCheckSuccessRateWindow bounds the health check results kept for each endpoint
+const maxHealthCheckSuccessRateWindow = 100
+
+// minHealthCheckMilliseconds keeps health checks from flooding upstreams
+const minHealthCheckMilliseconds = 100
+
+func ValidateHealthCheckPolicy(policy *proxyv1alpha1.HealthCheckPolicy, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if policy.SuccessRateWindow < 0 || policy.SuccessRateWindow > maxHealthCheckSuccessRateWindow {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("successRateWindow"), policy.SuccessRateWindow,
+			fmt.Sprintf("must be between 0 and %d", maxHealthCheckSuccessRateWindow)))
+	}
+	if policy.SuccessRateWindow > 0 && (policy.MinSuccessPercent < 1 || policy.MinSuccessPercent > 100) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("minSuccessPercent"), policy.MinSuccessPercent, "must be between 1 and 100"))
+	}
+	if policy.IntervalMilliseconds != 0 && policy.IntervalMilliseconds < minHealthCheckMilliseconds {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("intervalMilliseconds"), policy.IntervalMilliseconds,
+			fmt.Sprintf("must be 0 or at least %d", minHealthCheckMilliseconds)))
+	}
+	if policy.TimeoutMilliseconds != 0 && policy.TimeoutMilliseconds < minHealthCheckMilliseconds {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("timeoutMilliseconds"), policy.TimeoutMilliseconds,
+			fmt.Sprintf("must be 0 or at least %d", minHealthCheckMilliseconds)))
+	}
+	if policy.MaxBackoffMilliseconds != 0 && policy.MaxBackoffMilliseconds < minHealthCheckMilliseconds {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxBackoffMilliseconds"), policy.MaxBackoffMilliseconds,
+			fmt.Sprintf("must be 0 or at least %d", minHealthCheckMilliseconds)))
+	}
+	if len(policy.Path) > 0 && !strings.HasPrefix(policy.Path, "/") {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("path"), policy.Path, "must start with /"))
+	}
+	if policy.UnhealthyThreshold < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("unhealthyThreshold"), policy.UnhealthyThreshold, "must be non-negative"))
+	}
+	if policy.HealthyThreshold < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("healthyThreshold"), policy.HealthyThreshold, "must be non-negative"))
+	}
+	if policy.SuccessRateWindow > 0 && (policy.UnhealthyThreshold > 0 || policy.HealthyThreshold > 0) {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("successRateWindow"), "can not be set together with unhealthyThreshold or healthyThreshold"))
+	}
+	return allErrs
+}
+
+func ValidateOutlierDetectionPolicy(policy *proxyv1alpha1.OutlierDetectionPolicy, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if policy.ConsecutiveErrors < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("consecutiveErrors"), policy.ConsecutiveErrors, "must be non-negative"))
+	}
+	if policy.IntervalSeconds < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("intervalSeconds"), policy.IntervalSeconds, "must be non-negative"))
+	}
+	if policy.EjectionSeconds < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("ejectionSeconds"), policy.EjectionSeconds, "must be non-negative"))
+	}
+	if policy.HalfOpenPercent < 0 || policy.HalfOpenPercent > 100 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("halfOpenPercent"), policy.HalfOpenPercent, "must be between 0 and 100"))
+	}
+	return allErrs
+}
+
+func ValidateImpersonationPolicy(policy *proxyv1alpha1.ImpersonationPolicy, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for _, names := range []struct {
+		field string
+		names []string
+	}{
+		{"allowedUsers", policy.AllowedUsers},
+		{"deniedUsers", policy.DeniedUsers},
+		{"allowedGroups", policy.AllowedGroups},
+		{"deniedGroups", policy.DeniedGroups},
+	} {
+		for i, name := range names.names {
+			if len(name) == 0 {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child(names.field).Index(i), name, "must not be empty"))
+			}
+		}
+	}
+	return allErrs
+}
+
+func ValidateAPIResourceConfig(config *proxyv1alpha1.APIResourceConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(config.Rules) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("rules"), "must supply at least one rule, remove apiResources to proxy all resources"))
+	}
+	for i, rule := range config.Rules {
+		rulePath := fldPath.Child("rules").Index(i)
+		if len(rule.APIGroups) == 0 {
+			allErrs = append(allErrs, field.Required(rulePath.Child("apiGroups"), "rules must supply at least one api group"))
+		}
+		if len(rule.APIVersions) == 0 {
+			allErrs = append(allErrs, field.Required(rulePath.Child("apiVersions"), "rules must supply at least one api version"))
+		}
+		if len(rule.Resources) == 0 {
+			allErrs = append(allErrs, field.Required(rulePath.Child("resources"), "rules must supply at least one resource"))
+		}
+		for j, r := range rule.Resources {
+			if strings.HasSuffix(r, "/*") {
+				allErrs = append(allErrs, field.Required(rulePath.Child("resources").Index(j), "rules must not match all subresources of resource"))
+			}
+		}
+	}
+	return allErrs
+}
+
+func ValidateHiddenResourceConfig(config *proxyv1alpha1.HiddenResourceConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(config.Rules) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("rules"), "must supply at least one rule, remove hiddenResources to hide nothing"))
+	}
+	for i, rule := range config.Rules {
+		allErrs = append(allErrs, ValidateRule(rule, fldPath.Child("rules").Index(i))...)
 	}
 	return allErrs
 }
@@ -71,6 +321,14 @@ func ValidateServers(servers []proxyv1alpha1.UpstreamClusterServer, fldPath *fie
 			allErrs = append(allErrs, field.Invalid(fldPath.Child("servers").Index(i), s, "endpoint must supply http(s) schema"))
 		} else {
 			schemes.Insert(scheme)
+			if u, err := url.Parse(s.Endpoint); err != nil {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("servers").Index(i).Child("endpoint"), s.Endpoint, fmt.Sprintf("invalid endpoint url: %v", err)))
+			} else if len(u.Host) == 0 {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("servers").Index(i).Child("endpoint"), s.Endpoint, "endpoint must supply host"))
+			}
+		}
+		if s.Weight != nil && *s.Weight < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("servers").Index(i).Child("weight"), *s.Weight, "must be greater than or equal to 0"))
 		}
 		upstreams.Insert(s.Endpoint)
 	}
@@ -98,6 +356,29 @@ func ValidateClientConfig(scheme string, clientconfig *proxyv1alpha1.ClientConfi
 	if clientconfig.QPS > 0 && clientconfig.Burst < clientconfig.QPS {
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("burst"), "", "burst must be bigger than qps when qps is not equal to 0"))
 	}
+	if clientconfig.TLSSessionCacheSize < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("tlsSessionCacheSize"), clientconfig.TLSSessionCacheSize, "tlsSessionCacheSize must be bigger than or equal to 0"))
+	}
+	switch clientconfig.TLSRenegotiation {
+	case "", proxyv1alpha1.TLSRenegotiateNever, proxyv1alpha1.TLSRenegotiateOnceAsClient, proxyv1alpha1.TLSRenegotiateFreelyAsClient:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("tlsRenegotiation"), clientconfig.TLSRenegotiation, []string{
+			string(proxyv1alpha1.TLSRenegotiateNever),
+			string(proxyv1alpha1.TLSRenegotiateOnceAsClient),
+			string(proxyv1alpha1.TLSRenegotiateFreelyAsClient),
+		}))
+	}
+
+	if clientconfig.H2C && scheme != "http" {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("h2c"), clientconfig.H2C, "h2c is only allowed with http scheme"))
+	}
+	if clientconfig.H2C && clientconfig.DisableKeepAlives {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("disableKeepAlives"), clientconfig.DisableKeepAlives, "disableKeepAlives is not allowed with h2c"))
+	}
+
+	if len(clientconfig.SourceAddress) > 0 && net.ParseIP(clientconfig.SourceAddress) == nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("sourceAddress"), clientconfig.SourceAddress, "sourceAddress must be a valid IP address"))
+	}
 
 	if scheme == "https" {
 		if !clientconfig.Insecure && len(clientconfig.CAData) == 0 {
@@ -170,6 +451,23 @@ func ValidateSecureServing(serving *proxyv1alpha1.SecureServing, fldPath *field.
 		}
 	}
 
+	for i, audience := range serving.APIAudiences {
+		if len(audience) == 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("apiAudiences").Index(i), audience, "must not be empty"))
+		}
+	}
+
+	switch serving.ClientAuth {
+	case "", proxyv1alpha1.ClientAuthRequest:
+	case proxyv1alpha1.ClientAuthRequireAndVerify:
+		if len(serving.ClientCAData) == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Child("clientCAData"), "clientCAData is required if clientAuth is RequireAndVerify"))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("clientAuth"), serving.ClientAuth,
+			[]string{string(proxyv1alpha1.ClientAuthRequest), string(proxyv1alpha1.ClientAuthRequireAndVerify)}))
+	}
+
 	return allErrs
 }
 
@@ -188,6 +486,50 @@ func ValidateFlowControl(flowcontrol *proxyv1alpha1.FlowControl, fldPath *field.
 			flowControlSchemaNames.Insert(fs.Name)
 		}
 		allErrs = append(allErrs, ValidateFlowControlConfiguration(&fs.FlowControlSchemaConfiguration, flowControlFieldPath.Index(i))...)
+		switch fs.RejectionStatusCode {
+		case 0, http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		default:
+			allErrs = append(allErrs, field.NotSupported(flowControlFieldPath.Index(i).Child("rejectionStatusCode"), fs.RejectionStatusCode, []string{"429", "503"}))
+		}
+		if fs.RejectionResponse != nil {
+			allErrs = append(allErrs, ValidateRejectionResponse(fs.RejectionResponse, flowControlFieldPath.Index(i).Child("rejectionResponse"))...)
+		}
+	}
+
+	if flowcontrol.RejectionResponse != nil {
+		allErrs = append(allErrs, ValidateRejectionResponse(flowcontrol.RejectionResponse, fldPath.Child("rejectionResponse"))...)
+	}
+
+	namespaceSchemasPath := fldPath.Child("namespaceSchemas")
+	for i, ns := range flowcontrol.NamespaceSchemas {
+		if len(ns.Namespaces) == 0 {
+			allErrs = append(allErrs, field.Required(namespaceSchemasPath.Index(i).Child("namespaces"), "must supply at least one namespace"))
+		}
+		for j, namespace := range ns.Namespaces {
+			for _, msg := range validation.IsDNS1123Label(namespace) {
+				allErrs = append(allErrs, field.Invalid(namespaceSchemasPath.Index(i).Child("namespaces").Index(j), namespace, msg))
+			}
+		}
+		if len(ns.FlowControlSchemaName) > 0 && !flowControlSchemaNames.Has(ns.FlowControlSchemaName) {
+			allErrs = append(allErrs, field.Invalid(namespaceSchemasPath.Index(i).Child("flowControlSchemaName"), ns.FlowControlSchemaName, "flowControlSchema name must be present in FlowControlSchemas"))
+		}
+	}
+
+	userAgentSchemasPath := fldPath.Child("userAgentSchemas")
+	for i, ua := range flowcontrol.UserAgentSchemas {
+		if len(ua.UserAgentPrefixes) == 0 {
+			allErrs = append(allErrs, field.Required(userAgentSchemasPath.Index(i).Child("userAgentPrefixes"), "must supply at least one user agent prefix"))
+		}
+		for j, prefix := range ua.UserAgentPrefixes {
+			if len(prefix) == 0 {
+				allErrs = append(allErrs, field.Required(userAgentSchemasPath.Index(i).Child("userAgentPrefixes").Index(j), "user agent prefix can not be empty"))
+			}
+		}
+		if len(ua.FlowControlSchemaName) == 0 {
+			allErrs = append(allErrs, field.Required(userAgentSchemasPath.Index(i).Child("flowControlSchemaName"), "user agent can not be trusted to exempt requests from flow control"))
+		} else if !flowControlSchemaNames.Has(ua.FlowControlSchemaName) {
+			allErrs = append(allErrs, field.Invalid(userAgentSchemasPath.Index(i).Child("flowControlSchemaName"), ua.FlowControlSchemaName, "flowControlSchema name must be present in FlowControlSchemas"))
+		}
 	}
 
 	return flowControlSchemaNames, allErrs
@@ -200,6 +542,11 @@ func ValidateLoggingConfig(logging proxyv1alpha1.LoggingConfig, fldPath *field.P
 	default:
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("mode"), logging.Mode, "valid value: on or off"))
 	}
+	switch logging.UserGroups {
+	case proxyv1alpha1.LogOff, proxyv1alpha1.LogOn, "":
+	default:
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("userGroups"), logging.UserGroups, "valid value: on or off"))
+	}
 	return allErrs
 }
 
@@ -207,11 +554,17 @@ func ValidateDispatchPolicy(upstreams, flowControlSchemaNames sets.String, polic
 	allErrs := field.ErrorList{}
 
 	switch policy.Strategy {
-	case proxyv1alpha1.RoundRobin:
+	case proxyv1alpha1.RoundRobin, proxyv1alpha1.WeightedRandom, proxyv1alpha1.LeastConnections, proxyv1alpha1.LatencyWeightedRandom:
 	default:
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("strategy"), policy.Strategy, ""))
 	}
 
+	if len(policy.Name) > 0 {
+		for _, msg := range validation.IsDNS1123Label(policy.Name) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("name"), policy.Name, msg))
+		}
+	}
+
 	for j, u := range policy.UpstreamSubset {
 		if !upstreams.Has(u) {
 			allErrs = append(allErrs, field.Invalid(fldPath.Child("upstreamSubset").Index(j), u, "upstream subset endpoint must be present in servers"))
@@ -225,6 +578,9 @@ func ValidateDispatchPolicy(upstreams, flowControlSchemaNames sets.String, polic
 	if len(policy.Rules) == 0 {
 		allErrs = append(allErrs, field.Required(fldPath.Child("rules"), "dispatch policy must supply at least one rule"))
 	}
+	for i, rule := range policy.Rules {
+		allErrs = append(allErrs, ValidateRule(rule, fldPath.Child("rules").Index(i))...)
+	}
 
 	switch policy.LogMode {
 	case proxyv1alpha1.LogOff, proxyv1alpha1.LogOn, "":
@@ -274,6 +630,9 @@ func ValidateFlowControlConfiguration(schema *proxyv1alpha1.FlowControlSchemaCon
 
 	if schema.Exempt != nil {
 		numConfig++
+		if schema.Exempt.MaxRequestsInflight < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("exempt").Child("maxRequestsInflight"), schema.Exempt.MaxRequestsInflight, "must be bigger than or equal to 0"))
+		}
 	}
 	if schema.MaxRequestsInflight != nil {
 		if numConfig > 0 {
@@ -293,20 +652,74 @@ func ValidateFlowControlConfiguration(schema *proxyv1alpha1.FlowControlSchemaCon
 			allErrs = append(allErrs, validateTokenBucketFlowControlSchema(schema.TokenBucket, fldPath.Child("tokenBucket"))...)
 		}
 	}
+	if schema.MaxRequestsInflightByVerb != nil {
+		if numConfig > 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("maxRequestsInflightByVerb"), "may not specify more than 1 flow control configuration"))
+		} else {
+			numConfig++
+			if schema.MaxRequestsInflightByVerb.MaxMutating < 0 {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("maxRequestsInflightByVerb").Child("maxMutating"), schema.MaxRequestsInflightByVerb.MaxMutating, "must be bigger than or equal to 0"))
+			}
+			if schema.MaxRequestsInflightByVerb.MaxReadOnly < 0 {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("maxRequestsInflightByVerb").Child("maxReadOnly"), schema.MaxRequestsInflightByVerb.MaxReadOnly, "must be bigger than or equal to 0"))
+			}
+		}
+	}
+	if schema.Queued != nil {
+		if numConfig > 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("queued"), "may not specify more than 1 flow control configuration"))
+		} else {
+			numConfig++
+			allErrs = append(allErrs, validateQueuedFlowControlSchema(schema.Queued, fldPath.Child("queued"))...)
+		}
+	}
 	if numConfig == 0 {
 		allErrs = append(allErrs, field.Required(fldPath, "must specify a flow control type configuration"))
 	}
 	return allErrs
 }
 
+func validateQueuedFlowControlSchema(queued *proxyv1alpha1.QueuedFlowControlSchema, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if queued.Concurrency <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("concurrency"), queued.Concurrency, "must bigger than 0"))
+	}
+	if queued.QueueLength < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("queueLength"), queued.QueueLength, "must be bigger than or equal to 0"))
+	}
+	if queued.Queues < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("queues"), queued.Queues, "must be bigger than or equal to 0"))
+	}
+	if queued.HandSize < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("handSize"), queued.HandSize, "must be bigger than or equal to 0"))
+	}
+	if queued.Queues > 0 && queued.HandSize > queued.Queues {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("handSize"), queued.HandSize, "must not be bigger than queues"))
+	}
+	if queued.MaxWaitSeconds < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxWaitSeconds"), queued.MaxWaitSeconds, "must be bigger than or equal to 0"))
+	}
+	return allErrs
+}
+
 func validateTokenBucketFlowControlSchema(tokenBucket *proxyv1alpha1.TokenBucketFlowControlSchema, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
-	if tokenBucket.QPS == 0 {
+	if tokenBucket.QPS <= 0 {
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("qps"), tokenBucket.QPS, "must bigger than 0"))
 	}
 
 	if tokenBucket.Burst < tokenBucket.QPS {
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("burst"), tokenBucket.Burst, "must bigger than qps"))
 	}
+	if tokenBucket.SlowStartSeconds < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("slowStartSeconds"), tokenBucket.SlowStartSeconds, "must be non-negative"))
+	}
+	switch tokenBucket.KeyBy {
+	case "", proxyv1alpha1.TokenBucketKeyUser, proxyv1alpha1.TokenBucketKeySourceIP, proxyv1alpha1.TokenBucketKeyGroup:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("keyBy"), tokenBucket.KeyBy, []string{
+			string(proxyv1alpha1.TokenBucketKeyUser), string(proxyv1alpha1.TokenBucketKeySourceIP), string(proxyv1alpha1.TokenBucketKeyGroup),
+		}))
+	}
 	return allErrs
 }
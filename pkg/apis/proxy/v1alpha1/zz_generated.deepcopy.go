@@ -25,6 +25,60 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIResourceConfig) DeepCopyInto(out *APIResourceConfig) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]APIResourceRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIResourceConfig.
+func (in *APIResourceConfig) DeepCopy() *APIResourceConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(APIResourceConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIResourceRule) DeepCopyInto(out *APIResourceRule) {
+	*out = *in
+	if in.APIGroups != nil {
+		in, out := &in.APIGroups, &out.APIGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.APIVersions != nil {
+		in, out := &in.APIVersions, &out.APIVersions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIResourceRule.
+func (in *APIResourceRule) DeepCopy() *APIResourceRule {
+	if in == nil {
+		return nil
+	}
+	out := new(APIResourceRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClientConfig) DeepCopyInto(out *ClientConfig) {
 	*out = *in
@@ -171,6 +225,25 @@ func (in *FlowControl) DeepCopyInto(out *FlowControl) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.NamespaceSchemas != nil {
+		in, out := &in.NamespaceSchemas, &out.NamespaceSchemas
+		*out = make([]NamespaceFlowControlSchema, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RejectionResponse != nil {
+		in, out := &in.RejectionResponse, &out.RejectionResponse
+		*out = new(RejectionResponse)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UserAgentSchemas != nil {
+		in, out := &in.UserAgentSchemas, &out.UserAgentSchemas
+		*out = make([]UserAgentFlowControlSchema, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -188,6 +261,11 @@ func (in *FlowControl) DeepCopy() *FlowControl {
 func (in *FlowControlSchema) DeepCopyInto(out *FlowControlSchema) {
 	*out = *in
 	in.FlowControlSchemaConfiguration.DeepCopyInto(&out.FlowControlSchemaConfiguration)
+	if in.RejectionResponse != nil {
+		in, out := &in.RejectionResponse, &out.RejectionResponse
+		*out = new(RejectionResponse)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -219,6 +297,16 @@ func (in *FlowControlSchemaConfiguration) DeepCopyInto(out *FlowControlSchemaCon
 		*out = new(TokenBucketFlowControlSchema)
 		**out = **in
 	}
+	if in.MaxRequestsInflightByVerb != nil {
+		in, out := &in.MaxRequestsInflightByVerb, &out.MaxRequestsInflightByVerb
+		*out = new(MaxRequestsInflightByVerbFlowControlSchema)
+		**out = **in
+	}
+	if in.Queued != nil {
+		in, out := &in.Queued, &out.Queued
+		*out = new(QueuedFlowControlSchema)
+		**out = **in
+	}
 	return
 }
 
@@ -232,6 +320,81 @@ func (in *FlowControlSchemaConfiguration) DeepCopy() *FlowControlSchemaConfigura
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthCheckPolicy) DeepCopyInto(out *HealthCheckPolicy) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthCheckPolicy.
+func (in *HealthCheckPolicy) DeepCopy() *HealthCheckPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthCheckPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HiddenResourceConfig) DeepCopyInto(out *HiddenResourceConfig) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]DispatchPolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HiddenResourceConfig.
+func (in *HiddenResourceConfig) DeepCopy() *HiddenResourceConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(HiddenResourceConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImpersonationPolicy) DeepCopyInto(out *ImpersonationPolicy) {
+	*out = *in
+	if in.AllowedUsers != nil {
+		in, out := &in.AllowedUsers, &out.AllowedUsers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeniedUsers != nil {
+		in, out := &in.DeniedUsers, &out.DeniedUsers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedGroups != nil {
+		in, out := &in.AllowedGroups, &out.AllowedGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeniedGroups != nil {
+		in, out := &in.DeniedGroups, &out.DeniedGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImpersonationPolicy.
+func (in *ImpersonationPolicy) DeepCopy() *ImpersonationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ImpersonationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LoggingConfig) DeepCopyInto(out *LoggingConfig) {
 	*out = *in
@@ -248,6 +411,22 @@ func (in *LoggingConfig) DeepCopy() *LoggingConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaxRequestsInflightByVerbFlowControlSchema) DeepCopyInto(out *MaxRequestsInflightByVerbFlowControlSchema) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaxRequestsInflightByVerbFlowControlSchema.
+func (in *MaxRequestsInflightByVerbFlowControlSchema) DeepCopy() *MaxRequestsInflightByVerbFlowControlSchema {
+	if in == nil {
+		return nil
+	}
+	out := new(MaxRequestsInflightByVerbFlowControlSchema)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MaxRequestsInflightFlowControlSchema) DeepCopyInto(out *MaxRequestsInflightFlowControlSchema) {
 	*out = *in
@@ -264,6 +443,118 @@ func (in *MaxRequestsInflightFlowControlSchema) DeepCopy() *MaxRequestsInflightF
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceFlowControlSchema) DeepCopyInto(out *NamespaceFlowControlSchema) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceFlowControlSchema.
+func (in *NamespaceFlowControlSchema) DeepCopy() *NamespaceFlowControlSchema {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceFlowControlSchema)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OutlierDetectionPolicy) DeepCopyInto(out *OutlierDetectionPolicy) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutlierDetectionPolicy.
+func (in *OutlierDetectionPolicy) DeepCopy() *OutlierDetectionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(OutlierDetectionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueuedFlowControlSchema) DeepCopyInto(out *QueuedFlowControlSchema) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueuedFlowControlSchema.
+func (in *QueuedFlowControlSchema) DeepCopy() *QueuedFlowControlSchema {
+	if in == nil {
+		return nil
+	}
+	out := new(QueuedFlowControlSchema)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RejectionResponse) DeepCopyInto(out *RejectionResponse) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RejectionResponse.
+func (in *RejectionResponse) DeepCopy() *RejectionResponse {
+	if in == nil {
+		return nil
+	}
+	out := new(RejectionResponse)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetryPolicy) DeepCopyInto(out *RetryPolicy) {
+	*out = *in
+	if in.StatusReasons != nil {
+		in, out := &in.StatusReasons, &out.StatusReasons
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StatusMessages != nil {
+		in, out := &in.StatusMessages, &out.StatusMessages
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StatusCodes != nil {
+		in, out := &in.StatusCodes, &out.StatusCodes
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	if in.Verbs != nil {
+		in, out := &in.Verbs, &out.Verbs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryPolicy.
+func (in *RetryPolicy) DeepCopy() *RetryPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RetryPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretReferecence) DeepCopyInto(out *SecretReferecence) {
 	*out = *in
@@ -298,6 +589,11 @@ func (in *SecureServing) DeepCopyInto(out *SecureServing) {
 		*out = make([]byte, len(*in))
 		copy(*out, *in)
 	}
+	if in.APIAudiences != nil {
+		in, out := &in.APIAudiences, &out.APIAudiences
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -327,6 +623,38 @@ func (in *ServiceAccountRef) DeepCopy() *ServiceAccountRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ShadowConfig) DeepCopyInto(out *ShadowConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShadowConfig.
+func (in *ShadowConfig) DeepCopy() *ShadowConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ShadowConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StubConfig) DeepCopyInto(out *StubConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StubConfig.
+func (in *StubConfig) DeepCopy() *StubConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(StubConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TokenBucketFlowControlSchema) DeepCopyInto(out *TokenBucketFlowControlSchema) {
 	*out = *in
@@ -412,6 +740,11 @@ func (in *UpstreamClusterServer) DeepCopyInto(out *UpstreamClusterServer) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Weight != nil {
+		in, out := &in.Weight, &out.Weight
+		*out = new(int32)
+		**out = **in
+	}
 	return
 }
 
@@ -446,6 +779,51 @@ func (in *UpstreamClusterSpec) DeepCopyInto(out *UpstreamClusterSpec) {
 		}
 	}
 	out.Logging = in.Logging
+	if in.Disabled != nil {
+		in, out := &in.Disabled, &out.Disabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Shadow != nil {
+		in, out := &in.Shadow, &out.Shadow
+		*out = new(ShadowConfig)
+		**out = **in
+	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Stub != nil {
+		in, out := &in.Stub, &out.Stub
+		*out = new(StubConfig)
+		**out = **in
+	}
+	if in.APIResources != nil {
+		in, out := &in.APIResources, &out.APIResources
+		*out = new(APIResourceConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(HealthCheckPolicy)
+		**out = **in
+	}
+	if in.HiddenResources != nil {
+		in, out := &in.HiddenResources, &out.HiddenResources
+		*out = new(HiddenResourceConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OutlierDetection != nil {
+		in, out := &in.OutlierDetection, &out.OutlierDetection
+		*out = new(OutlierDetectionPolicy)
+		**out = **in
+	}
+	if in.Impersonation != nil {
+		in, out := &in.Impersonation, &out.Impersonation
+		*out = new(ImpersonationPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -474,3 +852,24 @@ func (in *UpstreamClusterStatus) DeepCopy() *UpstreamClusterStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserAgentFlowControlSchema) DeepCopyInto(out *UserAgentFlowControlSchema) {
+	*out = *in
+	if in.UserAgentPrefixes != nil {
+		in, out := &in.UserAgentPrefixes, &out.UserAgentPrefixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserAgentFlowControlSchema.
+func (in *UserAgentFlowControlSchema) DeepCopy() *UserAgentFlowControlSchema {
+	if in == nil {
+		return nil
+	}
+	out := new(UserAgentFlowControlSchema)
+	in.DeepCopyInto(out)
+	return out
+}
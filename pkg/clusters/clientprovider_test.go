@@ -22,7 +22,7 @@ import (
 
 func createTestNotReadyClusterInfo() *ClusterInfo {
 	cfg := newTestUpstreamClusterConfig()
-	cfg.Name = "testing.notReadyCluster"
+	cfg.Name = "testing.not-ready-cluster"
 	ret, _ := CreateClusterInfo(cfg, nil)
 	return ret
 }
@@ -42,7 +42,7 @@ func Test_manager_ClientFor(t *testing.T) {
 	// add not ready cluster
 	manager.Add(createTestNotReadyClusterInfo())
 
-	cluster, _, err := manager.ClientFor("testing.notReadyCluster")
+	cluster, _, err := manager.ClientFor("testing.not-ready-cluster")
 	if err == nil {
 		t.Error("ClientFor() want error, but got nil")
 	}
@@ -19,6 +19,9 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
+	"math/rand"
+	"net/http"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -27,7 +30,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/zoumo/goset"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
-	"k8s.io/apimachinery/pkg/util/wait"
+	utilcache "k8s.io/apimachinery/pkg/util/cache"
 	"k8s.io/apiserver/pkg/authorization/authorizer"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -36,11 +39,43 @@ import (
 	"k8s.io/klog"
 
 	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
+	"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1/validation"
 	"github.com/kubewharf/kubegateway/pkg/clusters/features"
 	gatewayflowcontrol "github.com/kubewharf/kubegateway/pkg/flowcontrol"
+	"github.com/kubewharf/kubegateway/pkg/gateway/metrics"
 	"github.com/kubewharf/kubegateway/pkg/transport"
 )
 
+// ClusterInfoConfig holds the gateway wide settings a ClusterInfo is created with
+type ClusterInfoConfig struct {
+	// MaxEndpoints is the maximum number of endpoints of the cluster, endpoints beyond the maximum
+	// are rejected when syncing the cluster. It guards against a misconfiguration registering
+	// thousands of endpoints and exhausting health check resources. Zero means no limit.
+	MaxEndpoints int32
+	// DispatchPoliciesWarningThreshold is the number of dispatch policies of the cluster above which
+	// warnings are logged. Policies are evaluated one by one for each request, too many of them
+	// degrade dispatch latency. Zero disables warnings.
+	DispatchPoliciesWarningThreshold int32
+	// MaxDispatchPolicies is the maximum number of dispatch policies of the cluster, syncing the
+	// cluster with more policies than the maximum fails. Zero means no limit.
+	MaxDispatchPolicies int32
+	// ReconcileEphemeralEndpoints removes ephemeral endpoints of the cluster once servers in its
+	// UpstreamCluster spec change, so that the spec takes over after an incident.
+	ReconcileEphemeralEndpoints bool
+}
+
+// checkDispatchPolicies returns an error if the number of dispatch policies exceeds the limit
+func (c *ClusterInfo) checkDispatchPolicies(policies int) error {
+	if max := int(c.config.MaxDispatchPolicies); max > 0 && policies > max {
+		return fmt.Errorf("cluster %q has %d dispatch policies, more than the limit %d", c.Cluster, policies, max)
+	}
+	if threshold := int(c.config.DispatchPoliciesWarningThreshold); threshold > 0 && policies > threshold {
+		klog.Warningf("[cluster info] cluster=%q has %d dispatch policies, more than %d, they are evaluated one by one for each request and may degrade dispatch latency",
+			c.Cluster, policies, threshold)
+	}
+	return nil
+}
+
 var (
 	ErrNoReadyEndpoints    = errors.New("no ready endpoints")
 	ErrNoRouterRuleMatches = errors.New("no router rule matches this request")
@@ -49,60 +84,249 @@ var (
 // EndpointPicker knows
 type EndpointPicker interface {
 	FlowControl() gatewayflowcontrol.FlowControl
+	FlowControlRejectionStatusCode() int32
+	// FlowControlRejectionResponse returns the customized response for requests rejected
+	// by flow control, it returns nil if it is not customized.
+	FlowControlRejectionResponse() *proxyv1alpha1.RejectionResponse
 	Pop() (*EndpointInfo, error)
+	// PopExcluding is the same as Pop but skips the excluded endpoints, it is used
+	// to pick another endpoint when retrying a request.
+	PopExcluding(excluded ...string) (*EndpointInfo, error)
+	// PopPreferred is the same as Pop but picks the preferred endpoint if it is ready and
+	// allowed by the matched dispatch policy.
+	PopPreferred(preferred string) (*EndpointInfo, error)
 	EnableLog() bool
+	// PolicyName returns the name of matched dispatch policy
+	PolicyName() string
+	// HasMinHealthyEndpoints returns the number of ready endpoints, the minimum number of
+	// healthy endpoints required by the cluster, and whether the requirement is satisfied.
+	// Endpoints are counted in the same version of the cluster which the request is
+	// dispatched with.
+	HasMinHealthyEndpoints() (int, int, bool)
+	// RetryPolicy returns the retry policy of the cluster in the same version which the
+	// request is dispatched with, it returns nil if retry is not configured.
+	RetryPolicy() *proxyv1alpha1.RetryPolicy
 }
 
 // endpointPickStrategy implement EndpointPicker interface
 type endpointPickStrategy struct {
 	cluster     *ClusterInfo
+	snapshot    *clusterSnapshot
+	policyName  string
 	strategy    proxyv1alpha1.Strategy
 	flowControl gatewayflowcontrol.FlowControl
-	upstreams   []string
-	enableLog   bool
+	// status code responded when flowControl rejects the request
+	rejectionStatusCode int32
+	rejectionResponse   *proxyv1alpha1.RejectionResponse
+	upstreams           []string
+	enableLog           bool
 }
 
 func (s *endpointPickStrategy) Pop() (*EndpointInfo, error) {
+	return s.PopExcluding()
+}
+
+func (s *endpointPickStrategy) PopExcluding(excluded ...string) (*EndpointInfo, error) {
+	selection := newEndpointSelectionLog(s.cluster.Cluster)
 	if len(s.upstreams) == 0 {
+		selection.chosen("", "no upstreams")
 		return nil, ErrNoReadyEndpoints
 	}
 	readyEndpoints := []*EndpointInfo{}
+	// ejectedEndpoints are ready but ejected by outlier detection
+	ejectedEndpoints := []*EndpointInfo{}
 	unreadyReason := []string{}
+	now := time.Now()
 	for _, ep := range s.upstreams {
-		info, ok := s.cluster.Endpoints.Load(ep)
-		if ok {
-			if info.IsReady() {
+		if containsString(excluded, ep) {
+			selection.candidate(ep, "excluded")
+			continue
+		}
+		info, ok := s.snapshot.endpoints.Load(ep)
+		if ok && s.snapshot.weight(ep) == 0 {
+			// drained endpoints are still health checked but never picked
+			unreadyReason = append(unreadyReason, fmt.Sprintf("endpoint=%q is drained by weight 0.", ep))
+			selection.candidate(ep, "drained by weight 0")
+		} else if ok {
+			if info.IsReady() && !info.admitsRequest(now) {
+				ejectedEndpoints = append(ejectedEndpoints, info)
+				selection.candidate(ep, "ejected by outlier detection")
+			} else if info.IsReady() {
 				readyEndpoints = append(readyEndpoints, info)
+				selection.candidate(ep, "ready")
 			} else {
 				unreadyReason = append(unreadyReason, info.UnreadyReason())
+				selection.candidate(ep, info.UnreadyReason())
 			}
+		} else {
+			selection.candidate(ep, "not found")
 		}
 	}
+	if len(readyEndpoints) == 0 && len(ejectedEndpoints) > 0 {
+		// endpoints are never all ejected, requests would fail anyway otherwise
+		readyEndpoints = ejectedEndpoints
+	}
 	if len(readyEndpoints) == 0 {
+		selection.chosen("", "no ready endpoints")
 		return nil, errors.WithMessage(ErrNoReadyEndpoints, strings.Join(unreadyReason, " "))
 	}
 
 	if len(readyEndpoints) == 1 {
+		selection.chosen(readyEndpoints[0].Endpoint, "only one ready endpoint")
 		return readyEndpoints[0], nil
 	}
 
-	// TODO: apply strategy
+	switch s.strategy {
+	case proxyv1alpha1.WeightedRandom:
+		return s.pickWeightedRandom(readyEndpoints, selection), nil
+	case proxyv1alpha1.LeastConnections:
+		return pickLeastConnections(readyEndpoints, selection), nil
+	case proxyv1alpha1.LatencyWeightedRandom:
+		return s.pickLatencyWeightedRandom(readyEndpoints, selection), nil
+	}
+
 	key := fmt.Sprintf("%v", readyEndpoints)
 	var i uint64
-	lb, _ := s.cluster.loadbalancer.LoadOrStore(key, &i)
+	lb, _ := s.snapshot.loadbalancer.LoadOrStore(key, &i)
 	index := atomic.AddUint64(lb.(*uint64), 1)
 	index = index % uint64(len(readyEndpoints))
+	selection.chosen(readyEndpoints[index].Endpoint, fmt.Sprintf("round robin index %d of %d ready endpoints", index, len(readyEndpoints)))
 	return readyEndpoints[index], nil
 }
 
+// pickWeightedRandom picks one of ready endpoints at random in proportion to their weights.
+// It draws on every call, i.e. every request, so that requests are spread by weights even if
+// they are multiplexed on one long-lived connection.
+func (s *endpointPickStrategy) pickWeightedRandom(readyEndpoints []*EndpointInfo, selection *endpointSelectionLog) *EndpointInfo {
+	var total int64
+	for _, info := range readyEndpoints {
+		total += int64(s.snapshot.weight(info.Endpoint))
+	}
+	n := rand.Int63n(total)
+	for _, info := range readyEndpoints {
+		n -= int64(s.snapshot.weight(info.Endpoint))
+		if n < 0 {
+			selection.chosen(info.Endpoint, fmt.Sprintf("weighted random with weight %d of total %d", s.snapshot.weight(info.Endpoint), total))
+			return info
+		}
+	}
+	// unreachable
+	return readyEndpoints[len(readyEndpoints)-1]
+}
+
+// minLatencyWeightFactor is the lowest factor that the weight of an endpoint is reduced to by
+// its health check latency, so that a slow endpoint still gets a little traffic to recover from
+const minLatencyWeightFactor = 0.1
+
+// latencyWeightFactor returns the factor applied to the weight of an endpoint whose health check
+// latency is latency, fastest is the lowest health check latency among ready endpoints. Endpoints
+// without health check latency are not reduced.
+func latencyWeightFactor(latency, fastest time.Duration) float64 {
+	if latency <= 0 || fastest <= 0 || latency <= fastest {
+		return 1
+	}
+	factor := float64(fastest) / float64(latency)
+	if factor < minLatencyWeightFactor {
+		factor = minLatencyWeightFactor
+	}
+	return factor
+}
+
+// pickLatencyWeightedRandom picks one of ready endpoints at random in proportion to their weights
+// reduced by health check latency. Only latency measured by health checks is used, so nothing is
+// tracked in the path of requests.
+func (s *endpointPickStrategy) pickLatencyWeightedRandom(readyEndpoints []*EndpointInfo, selection *endpointSelectionLog) *EndpointInfo {
+	var fastest time.Duration
+	for _, info := range readyEndpoints {
+		if latency := info.HealthCheckLatency(); latency > 0 && (fastest == 0 || latency < fastest) {
+			fastest = latency
+		}
+	}
+	weights := make([]float64, len(readyEndpoints))
+	var total float64
+	for i, info := range readyEndpoints {
+		weights[i] = float64(s.snapshot.weight(info.Endpoint)) * latencyWeightFactor(info.HealthCheckLatency(), fastest)
+		total += weights[i]
+	}
+	n := rand.Float64() * total
+	for i, info := range readyEndpoints {
+		n -= weights[i]
+		if n < 0 {
+			selection.chosen(info.Endpoint, fmt.Sprintf("latency weighted random with weight %.2f of total %.2f, health check latency %v", weights[i], total, info.HealthCheckLatency()))
+			return info
+		}
+	}
+	// rounding errors
+	return readyEndpoints[len(readyEndpoints)-1]
+}
+
+// pickLeastConnections picks the ready endpoint with the fewest in-flight requests, one of the
+// endpoints with the same fewest requests is picked at random so that a burst of requests is not
+// dispatched to the first one.
+func pickLeastConnections(readyEndpoints []*EndpointInfo, selection *endpointSelectionLog) *EndpointInfo {
+	var chosen *EndpointInfo
+	var least int64
+	ties := 0
+	for _, info := range readyEndpoints {
+		inflight := info.Inflight()
+		switch {
+		case chosen == nil || inflight < least:
+			chosen, least, ties = info, inflight, 1
+		case inflight == least:
+			// reservoir sampling among ties
+			ties++
+			if rand.Intn(ties) == 0 {
+				chosen = info
+			}
+		}
+	}
+	selection.chosen(chosen.Endpoint, fmt.Sprintf("least connections with %d in-flight requests", least))
+	return chosen
+}
+
+func (s *endpointPickStrategy) PopPreferred(preferred string) (*EndpointInfo, error) {
+	if len(preferred) > 0 && containsString(s.upstreams, preferred) {
+		if info, ok := s.snapshot.endpoints.Load(preferred); ok && info.IsReady() && info.admitsRequest(time.Now()) {
+			selection := newEndpointSelectionLog(s.cluster.Cluster)
+			selection.candidate(preferred, "ready")
+			selection.chosen(preferred, "preferred endpoint")
+			return info, nil
+		}
+	}
+	return s.PopExcluding()
+}
+
 func (s *endpointPickStrategy) EnableLog() bool {
 	return s.enableLog
 }
 
+func (s *endpointPickStrategy) HasMinHealthyEndpoints() (int, int, bool) {
+	return s.snapshot.hasMinHealthyEndpoints()
+}
+
+func (s *endpointPickStrategy) RetryPolicy() *proxyv1alpha1.RetryPolicy {
+	return s.snapshot.retry
+}
+
+func (s *endpointPickStrategy) PolicyName() string {
+	return s.policyName
+}
+
 func (s *endpointPickStrategy) FlowControl() gatewayflowcontrol.FlowControl {
 	return s.flowControl
 }
 
+func (s *endpointPickStrategy) FlowControlRejectionResponse() *proxyv1alpha1.RejectionResponse {
+	return s.rejectionResponse
+}
+
+func (s *endpointPickStrategy) FlowControlRejectionStatusCode() int32 {
+	if s.rejectionStatusCode == 0 {
+		return http.StatusTooManyRequests
+	}
+	return s.rejectionStatusCode
+}
+
 // ClusterInfo is a wrapper to a UpstreamCluster with additional information
 type ClusterInfo struct {
 	// server Cluster
@@ -116,23 +340,40 @@ type ClusterInfo struct {
 	cancel context.CancelFunc
 
 	defaultFlowControl gatewayflowcontrol.FlowControl
-	flowcontrol        *gatewayflowcontrol.FlowControls
-	loadbalancer       sync.Map
-
-	// upstream endpoint client rest config, the host must be replaced when using it
-	restConfig *rest.Config
-	// current synced flow controler spec
-	currentFlowControlSpec atomic.Value
-	// current synced tls config for secure seving
-	currentSecureServingTLSConfig atomic.Value
-	// current dispatch policies
-	currentDispatchPolicies atomic.Value
-	// current logging config
-	currentLoggingConfig atomic.Value
-	featuregate          featuregate.MutableFeatureGate
-
-	healthCheckIntervalSeconds time.Duration
-	endpointHeathCheck         EndpointHealthCheck
+	// flowControlLock guards syncing flow control from the spec and overrides
+	flowControlLock sync.Mutex
+	// flow control spec of UpstreamCluster, overrides are not applied
+	sourceFlowControlSpec proxyv1alpha1.FlowControl
+	// temporary flow control schema overrides set at runtime, keyed by schema name
+	flowControlOverrides map[string]proxyv1alpha1.FlowControlSchema
+
+	// snapshot stores *clusterSnapshot, it holds everything synced from the spec of the
+	// cluster, e.g. endpoints, flow controls, feature gate and policies
+	snapshot atomic.Value
+	// snapshotLock serializes updates of snapshot, it must be acquired before flowControlLock
+	// and endpointsLock
+	snapshotLock sync.Mutex
+	// endpointsLock guards syncing endpoints from the spec and ephemeral endpoints
+	endpointsLock sync.Mutex
+	// servers in spec of UpstreamCluster, ephemeral endpoints are not included
+	sourceServers []proxyv1alpha1.UpstreamClusterServer
+	// endpoints added at runtime, see AddEphemeralEndpoint
+	ephemeralEndpoints []EphemeralEndpoint
+	// isolationLock serializes updates of isolations
+	isolationLock sync.Mutex
+	// isolations stores map[string][]string of user name to the isolated endpoint subset,
+	// it is replaced as a whole on update so that it can be read without locking
+	isolations atomic.Value
+	// pauseLock serializes pausing and resuming admission of requests
+	pauseLock sync.Mutex
+	// pause stores *pauseState if admission of new requests is paused, or nil
+	pause atomic.Value
+	// listEndpoints caches endpoints which serve lists by the resourceVersion of list
+	listEndpoints *utilcache.LRUExpireCache
+	// config is set on creation and never changes afterwards
+	config ClusterInfoConfig
+
+	endpointHeathCheck EndpointHealthCheck
 }
 
 type secureServingConfig struct {
@@ -147,23 +388,38 @@ func NewEmptyClusterInfo(clusterName string, config *rest.Config, healthCheck En
 	clusterName = strings.ToLower(clusterName)
 	ctx, cancel := context.WithCancel(context.Background())
 	info := &ClusterInfo{
-		ctx:                        ctx,
-		cancel:                     cancel,
-		Cluster:                    clusterName,
-		restConfig:                 config,
-		Endpoints:                  &EndpointInfoMap{data: sync.Map{}},
-		healthCheckIntervalSeconds: 5 * time.Second,
-		defaultFlowControl:         gatewayflowcontrol.DefaultFlowControl,
-		flowcontrol:                gatewayflowcontrol.NewFlowControls(),
-		loadbalancer:               sync.Map{},
-		endpointHeathCheck:         healthCheck,
-		featuregate:                features.DefaultMutableFeatureGate.DeepCopy(),
+		ctx:       ctx,
+		cancel:    cancel,
+		Cluster:   clusterName,
+		Endpoints: &EndpointInfoMap{data: sync.Map{}},
+		// default flow control counts requests of each cluster separately
+		defaultFlowControl:   gatewayflowcontrol.NewFlowControl(gatewayflowcontrol.DefaultFlowControlSchema),
+		flowControlOverrides: map[string]proxyv1alpha1.FlowControlSchema{},
+		endpointHeathCheck:   healthCheck,
+		listEndpoints:        utilcache.NewLRUExpireCache(maxListEndpoints),
 	}
+	snapshot := newClusterSnapshot()
+	// upstream endpoint client rest config, the host must be replaced when using it
+	snapshot.restConfig = config
+	info.snapshot.Store(snapshot)
 	return info
 }
 
 // CreateClusterInfo try every endpoint to find a ready endpoint, and then init rest config
 func CreateClusterInfo(cluster *proxyv1alpha1.UpstreamCluster, healthCheck EndpointHealthCheck) (*ClusterInfo, error) {
+	return CreateClusterInfoWithConfig(cluster, healthCheck, ClusterInfoConfig{})
+}
+
+// CreateClusterInfoWithConfig is like CreateClusterInfo, the cluster is synced with the given config
+func CreateClusterInfoWithConfig(cluster *proxyv1alpha1.UpstreamCluster, healthCheck EndpointHealthCheck, config ClusterInfoConfig) (*ClusterInfo, error) {
+	// an invalid cluster is rejected before anything is built, instead of failing at request time.
+	// It is validated after defaulting like the API server does.
+	defaulted := cluster.DeepCopy()
+	proxyv1alpha1.SetObjectDefaults_UpstreamCluster(defaulted)
+	if errs := validation.ValidateUpstreamCluster(defaulted); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid config of cluster %q: %v", cluster.Name, errs.ToAggregate())
+	}
+
 	restconfig, err := buildClusterRESTConfig(cluster)
 	if err != nil {
 		return nil, err
@@ -171,6 +427,13 @@ func CreateClusterInfo(cluster *proxyv1alpha1.UpstreamCluster, healthCheck Endpo
 
 	klog.Infof("create valid rest config for cluster: %v", cluster.Name)
 	info := NewEmptyClusterInfo(cluster.Name, restconfig, healthCheck)
+	info.config = config
+	// client config which endpoints are created with, the snapshot is not published yet
+	snapshot := info.loadSnapshot()
+	snapshot.sourceAddress = cluster.Spec.ClientConfig.SourceAddress
+	snapshot.upstreamTLSSettings = newUpstreamTLSSettings(cluster.Spec.ClientConfig)
+	snapshot.h2c = cluster.Spec.ClientConfig.H2C
+	snapshot.disableKeepAlives = cluster.Spec.ClientConfig.DisableKeepAlives
 	err = info.Sync(cluster)
 	if err != nil {
 		return nil, err
@@ -191,7 +454,12 @@ func (c *ClusterInfo) LoadTLSConfig() (*tls.Config, bool) {
 		return nil, false
 	}
 
+	clientAuth := tls.RequestClientCert
+	if cfg.secureServing.ClientAuth == proxyv1alpha1.ClientAuthRequireAndVerify {
+		clientAuth = tls.RequireAndVerifyClientCert
+	}
 	return &tls.Config{
+		ClientAuth:   clientAuth,
 		ClientCAs:    cfg.clientCA,
 		Certificates: cfg.certs,
 	}, true
@@ -209,69 +477,29 @@ func (c *ClusterInfo) LoadVerifyOptions() (x509.VerifyOptions, bool) {
 	return *cfg.verifyOptions, true
 }
 
-func (c *ClusterInfo) loadSecureServingConfig() (secureServingConfig, bool) {
-	empty := secureServingConfig{
-		secureServing: &proxyv1alpha1.SecureServing{},
-	}
-	uncastObj := c.currentSecureServingTLSConfig.Load()
-	if uncastObj == nil {
-		return empty, false
-	}
-	spec, ok := uncastObj.(*secureServingConfig)
-	if !ok {
-		return empty, false
-	}
-	if spec == nil {
-		return empty, false
-	}
-	return *spec, true
+// APIAudiences returns the audiences required of bearer tokens for this cluster, it is
+// empty if the global API audiences of the gateway are used.
+func (c *ClusterInfo) APIAudiences() []string {
+	cfg, _ := c.loadSecureServingConfig()
+	return cfg.secureServing.APIAudiences
 }
 
-func (c *ClusterInfo) loadFlowControlSpec() (proxyv1alpha1.FlowControl, bool) {
-	empty := proxyv1alpha1.FlowControl{}
-	uncastObj := c.currentFlowControlSpec.Load()
-	if uncastObj == nil {
-		return empty, false
-	}
-	spec, ok := uncastObj.(*proxyv1alpha1.FlowControl)
-	if !ok {
-		return empty, false
-	}
-	if spec == nil {
-		return empty, false
-	}
-	return *spec, true
-}
-
-func (c *ClusterInfo) loadDispatchPolicies() []proxyv1alpha1.DispatchPolicy {
-	uncastObj := c.currentDispatchPolicies.Load()
-	if uncastObj == nil {
-		return nil
-	}
-
-	polices, ok := uncastObj.([]proxyv1alpha1.DispatchPolicy)
-	if !ok {
-		return nil
-	}
-	return polices
+func (c *ClusterInfo) loadSecureServingConfig() (secureServingConfig, bool) {
+	return c.loadSnapshot().loadSecureServingConfig()
 }
 
-func (c *ClusterInfo) loadLoggingConfig() proxyv1alpha1.LoggingConfig {
-	empty := proxyv1alpha1.LoggingConfig{}
-	uncastObj := c.currentLoggingConfig.Load()
-	if uncastObj == nil {
-		return empty
+func (s *clusterSnapshot) loadSecureServingConfig() (secureServingConfig, bool) {
+	if s.secureServing == nil {
+		return secureServingConfig{
+			secureServing: &proxyv1alpha1.SecureServing{},
+		}, false
 	}
-	cfg, ok := uncastObj.(proxyv1alpha1.LoggingConfig)
-	if !ok {
-		return empty
-	}
-	return cfg
+	return *s.secureServing, true
 }
 
 // Sync will only be triggered by upstream event handler, it is single thread.
 // so there is no need to add a lock
-// TODO: how to deal with clientConfig changes
+// TODO: how to deal with clientConfig changes other than source address
 func (c *ClusterInfo) Sync(cluster *proxyv1alpha1.UpstreamCluster) error {
 	if c.Cluster != strings.ToLower(cluster.Name) {
 		klog.V(3).Infof("[cluster info] skip syncing cluster because input cluster name is mismatching, %v != %v", c.Cluster, cluster.Name)
@@ -280,36 +508,114 @@ func (c *ClusterInfo) Sync(cluster *proxyv1alpha1.UpstreamCluster) error {
 
 	klog.V(5).Infof("[cluster info] syncing cluster info, name=%q", c.Cluster)
 
-	// update flow control
-	c.syncFlowControlLocked(cluster.Spec.FlowControl)
-
-	// update secure serving
-	if err := c.syncSecureServingConfigLocked(cluster.Spec.SecureServing); err != nil {
+	// a cluster with invalid config keeps running the last good config as a whole, so anything
+	// which can fail must be done before changing the cluster
+	featuregate, err := c.syncedFeatureGate(cluster.Annotations)
+	if err != nil {
+		// we should never get here because there is validating admission
 		return err
 	}
-
-	// add or update endpoints
-	if err := c.syncEndpoints(cluster.Spec.Servers); err != nil {
+	if err := c.checkDispatchPolicies(len(cluster.Spec.DispatchPolicies)); err != nil {
+		return err
+	}
+	restConfig, err := c.syncedRESTConfig(cluster)
+	if err != nil {
 		return err
 	}
 
-	if err := c.syncFeatureGate(cluster.Annotations); err != nil {
-		// we should never get here because there is validating admission
+	// the state which dispatching depends on is published as a whole, in-flight requests keep
+	// using the previous snapshot and never see a partially synced cluster
+	err = c.updateSnapshot(func(next *clusterSnapshot) error {
+		if restConfig != next.restConfig {
+			// endpoints are recreated with the new config, so that they dial from the new address
+			klog.Infof("[cluster info] cluster=%q source address is changed to %q, recreate endpoints", c.Cluster, cluster.Spec.ClientConfig.SourceAddress)
+			next.restConfig = restConfig
+			next.sourceAddress = cluster.Spec.ClientConfig.SourceAddress
+			next.endpoints = &EndpointInfoMap{}
+		}
+
+		// update secure serving
+		if err := c.syncSecureServingConfigLocked(next, cluster.Spec.SecureServing); err != nil {
+			return err
+		}
+
+		// add or update endpoints, ephemeral endpoints are kept
+		if err := c.syncSourceEndpointsLocked(next, cluster.Spec.Servers); err != nil {
+			return err
+		}
+
+		// update flow control, it never fails and flow controls shared with the current
+		// snapshot are resized after next snapshot is published
+		c.flowControlLock.Lock()
+		c.sourceFlowControlSpec = cluster.Spec.FlowControl
+		c.syncFlowControlLocked(next, c.applyFlowControlOverridesLocked())
+		c.flowControlLock.Unlock()
+
+		// set dispatch policies, the match cache is kept if policies are not changed
+		if !apiequality.Semantic.DeepEqual(next.policies.policies, cluster.Spec.DispatchPolicies) {
+			next.policies = newPolicyMatcher(cluster.Spec.DispatchPolicies)
+		}
+		next.logging = cluster.Spec.Logging
+		next.minHealthyEndpoints = cluster.Spec.MinHealthyEndpoints
+		next.featureGate = featuregate
+
+		disabled := cluster.Spec.Disabled != nil && *cluster.Spec.Disabled
+		if disabled != next.disabled {
+			klog.Infof("[cluster info] cluster=%q disabled changed to %v", c.Cluster, disabled)
+		}
+		next.disabled = disabled
+		next.shadow = cluster.Spec.Shadow.DeepCopy()
+		next.retry = cluster.Spec.Retry.DeepCopy()
+		next.stub = cluster.Spec.Stub.DeepCopy()
+		next.apiResources = cluster.Spec.APIResources.DeepCopy()
+		next.hiddenResources = cluster.Spec.HiddenResources.DeepCopy()
+		next.healthCheck = cluster.Spec.HealthCheck.DeepCopy()
+		next.outlierDetection = cluster.Spec.OutlierDetection.DeepCopy()
+		next.impersonation = cluster.Spec.Impersonation.DeepCopy()
+		return nil
+	})
+	if err != nil {
 		return err
 	}
 
-	// set dispatch policies
-	c.currentDispatchPolicies.Store(cluster.Spec.DispatchPolicies)
-	c.currentLoggingConfig.Store(cluster.Spec.Logging)
+	metrics.RecordDispatchPolicies(c.Cluster, len(cluster.Spec.DispatchPolicies))
 
 	return nil
 }
 
+// syncedRESTConfig returns the rest config with the dialer of the source address in cluster
+// without changing the current one. Other changes of client config are not applied.
+func (c *ClusterInfo) syncedRESTConfig(cluster *proxyv1alpha1.UpstreamCluster) (*rest.Config, error) {
+	current := c.loadSnapshot()
+	if cluster.Spec.ClientConfig.SourceAddress == current.sourceAddress {
+		return current.restConfig, nil
+	}
+	built, err := buildClusterRESTConfig(cluster)
+	if err != nil {
+		return nil, err
+	}
+	config := rest.CopyConfig(current.restConfig)
+	config.Dial = built.Dial
+	return config, nil
+}
+
+// syncEndpoints syncs endpoints with servers and publishes them
 func (c *ClusterInfo) syncEndpoints(servers []proxyv1alpha1.UpstreamClusterServer) error {
+	return c.updateSnapshot(func(next *clusterSnapshot) error {
+		return c.syncEndpointsLocked(next, servers)
+	})
+}
+
+// syncEndpointsLocked adds, updates and deletes endpoints in next snapshot, deleted endpoints
+// are stopped after next snapshot is published.
+func (c *ClusterInfo) syncEndpointsLocked(next *clusterSnapshot, servers []proxyv1alpha1.UpstreamClusterServer) error {
 	// update endpoints
-	currentEPs := goset.NewSetFromStrings(c.AllEndpoints())
+	currentEPs := goset.NewSetFromStrings(next.endpoints.Names())
 	wantedEPs := goset.NewSet()
 
+	if max := int(c.config.MaxEndpoints); max > 0 && len(servers) > max {
+		servers = c.capServers(servers, currentEPs, max)
+	}
 	for _, e := range servers {
 		wantedEPs.Add(e.Endpoint) //nolint
 	}
@@ -319,33 +625,30 @@ func (c *ClusterInfo) syncEndpoints(servers []proxyv1alpha1.UpstreamClusterServe
 
 	if added.Len() > 0 || deleted.Len() > 0 {
 		// servers changed, reset loadbalancer
-		c.loadbalancer = sync.Map{}
+		next.loadbalancer = &sync.Map{}
 	}
 
 	deleted.Range(func(index int, elem interface{}) bool {
-		ep := elem.(string)
-		info, loaded := c.Endpoints.LoadAndDelete(ep)
-		if !loaded {
-			return true
-		}
-		klog.Infof("[cluster info] endpoint=%q is deleted from cluster %q", info.Endpoint, c.Cluster)
-		if info.cancel != nil {
-			info.cancel()
-		}
+		next.endpoints.LoadAndDelete(elem.(string))
 		return true
 	})
 
 	var syncErr error
 
 	disabled := goset.NewSet()
+	weights := map[string]int32{}
 	for _, server := range servers {
 		if server.Disabled != nil && *server.Disabled {
 			disabled.Add(server.Endpoint) //nolint
 		}
+		if server.Weight != nil {
+			weights[server.Endpoint] = *server.Weight
+		}
 	}
+	next.weights = weights
 	wantedEPs.Range(func(index int, elem interface{}) bool {
 		ep := elem.(string)
-		syncErr = c.addOrUpdateEndpoint(ep, disabled.Contains(ep))
+		syncErr = c.addOrUpdateEndpointLocked(next, ep, disabled.Contains(ep))
 		// stop loop if add or update error
 		return syncErr == nil
 	})
@@ -353,14 +656,40 @@ func (c *ClusterInfo) syncEndpoints(servers []proxyv1alpha1.UpstreamClusterServe
 	return syncErr
 }
 
-func (c *ClusterInfo) syncFlowControlLocked(newObj proxyv1alpha1.FlowControl) {
-	oldObj, _ := c.loadFlowControlSpec()
+// capServers returns at most max servers, existing endpoints are kept in preference to new ones,
+// and the rest are rejected with logs.
+func (c *ClusterInfo) capServers(servers []proxyv1alpha1.UpstreamClusterServer, currentEPs goset.Set, max int) []proxyv1alpha1.UpstreamClusterServer {
+	kept := []proxyv1alpha1.UpstreamClusterServer{}
+	keptEPs := goset.NewSet()
+	keep := func(existing bool) {
+		for _, server := range servers {
+			if len(kept) >= max {
+				return
+			}
+			if currentEPs.Contains(server.Endpoint) == existing && !keptEPs.Contains(server.Endpoint) {
+				kept = append(kept, server)
+				keptEPs.Add(server.Endpoint) //nolint
+			}
+		}
+	}
+	keep(true)
+	keep(false)
+	for _, server := range servers {
+		if !keptEPs.Contains(server.Endpoint) {
+			klog.Errorf("[cluster info] cluster=%q reject endpoint=%q, the number of endpoints exceeds the maximum %d", c.Cluster, server.Endpoint, max)
+		}
+	}
+	return kept
+}
+
+func (c *ClusterInfo) syncFlowControlLocked(next *clusterSnapshot, newObj proxyv1alpha1.FlowControl) {
+	oldObj := next.flowControlSpec
 	if apiequality.Semantic.DeepEqual(oldObj, newObj) {
 		return
 	}
 
 	defer func() {
-		c.currentFlowControlSpec.Store(&newObj)
+		next.flowControlSpec = newObj
 	}()
 
 	oldMap := map[string]proxyv1alpha1.FlowControlSchema{}
@@ -378,26 +707,54 @@ func (c *ClusterInfo) syncFlowControlLocked(newObj proxyv1alpha1.FlowControl) {
 		oldSchema := oldMap[newSchema.Name]
 		oldType := gatewayflowcontrol.GuessFlowControlSchemaType(oldSchema)
 		newType := gatewayflowcontrol.GuessFlowControlSchemaType(newSchema)
-		fc, ok := c.flowcontrol.Load(newSchema.Name)
-		if !ok || oldType != newType {
-			// flow control is not created or type changed
+		fc, ok := next.flowControls.Load(newSchema.Name)
+		if !ok || oldType != newType || queuesChanged(oldSchema, newSchema) || tokenBucketKeyChanged(oldSchema, newSchema) {
+			// flow control is not created, type changed, queues of Queued changed, or key of TokenBucket changed
 			newFC := gatewayflowcontrol.NewFlowControl(newSchema)
-			c.flowcontrol.Store(newSchema.Name, newFC)
+			name := newSchema.Name
+			next.onPublished(func() {
+				metrics.ForgetFlowControlKeys(c.Cluster, name)
+				gatewayflowcontrol.SetKeysObserver(newFC, func(keys int) {
+					metrics.RecordFlowControlKeys(c.Cluster, name, keys)
+				})
+			})
+			next.flowControls.Store(newSchema.Name, newFC)
 			klog.Infof("[cluster info] cluster=%q ensure flowcontrol schema %v", c.Cluster, newFC.String())
 			continue
 		}
-		if ok {
+		// fc is shared with the current snapshot, it is resized only if next snapshot is published
+		newSchema := newSchema
+		next.onPublished(func() {
 			switch newType {
+			case proxyv1alpha1.Exempt:
+				var max int32
+				if newSchema.Exempt != nil {
+					max = newSchema.Exempt.MaxRequestsInflight
+				}
+				if fc.Resize(uint32(max), 0) {
+					klog.Infof("[cluster info] cluster=%q resize flowcontrol schema=%q", c.Cluster, fc.String())
+				}
 			case proxyv1alpha1.MaxRequestsInflight:
 				if fc.Resize(uint32(newSchema.MaxRequestsInflight.Max), 0) {
 					klog.Infof("[cluster info] cluster=%q resize flowcontrol schema=%q", c.Cluster, fc.String())
 				}
 			case proxyv1alpha1.TokenBucket:
+				gatewayflowcontrol.SetSlowStart(fc, time.Duration(newSchema.TokenBucket.SlowStartSeconds)*time.Second)
 				if fc.Resize(uint32(newSchema.TokenBucket.QPS), uint32(newSchema.TokenBucket.Burst)) {
 					klog.Infof("[cluster info] cluster=%q resize flowcontrol schema=%q", c.Cluster, fc.String())
 				}
+			case proxyv1alpha1.MaxRequestsInflightByVerb:
+				byVerb := newSchema.MaxRequestsInflightByVerb
+				if fc.Resize(uint32(byVerb.MaxReadOnly), uint32(byVerb.MaxMutating)) {
+					klog.Infof("[cluster info] cluster=%q resize flowcontrol schema=%q", c.Cluster, fc.String())
+				}
+			case proxyv1alpha1.Queued:
+				gatewayflowcontrol.SetMaxWait(fc, time.Duration(newSchema.Queued.MaxWaitSeconds)*time.Second)
+				if fc.Resize(uint32(newSchema.Queued.Concurrency), uint32(newSchema.Queued.QueueLength)) {
+					klog.Infof("[cluster info] cluster=%q resize flowcontrol schema=%q", c.Cluster, fc.String())
+				}
 			}
-		}
+		})
 	}
 
 	deleted := oldset.Diff(newset)
@@ -405,13 +762,34 @@ func (c *ClusterInfo) syncFlowControlLocked(newObj proxyv1alpha1.FlowControl) {
 	deleted.Range(func(_ int, elem interface{}) bool {
 		name := elem.(string)
 		klog.Infof("[cluster info] cluster=%q delete flowcontrol schema=%q", c.Cluster, name)
-		c.flowcontrol.Delete(name)
+		next.flowControls.Delete(name)
+		next.onPublished(func() {
+			metrics.ForgetFlowControlKeys(c.Cluster, name)
+		})
 		return true
 	})
 }
 
-func (c *ClusterInfo) syncSecureServingConfigLocked(newSecureServing proxyv1alpha1.SecureServing) error {
-	oldCfg, _ := c.loadSecureServingConfig()
+// queuesChanged returns true if the queues of a Queued flow control schema are changed, they
+// can not be resized in place
+func queuesChanged(oldSchema, newSchema proxyv1alpha1.FlowControlSchema) bool {
+	if oldSchema.Queued == nil || newSchema.Queued == nil {
+		return false
+	}
+	return oldSchema.Queued.Queues != newSchema.Queued.Queues || oldSchema.Queued.HandSize != newSchema.Queued.HandSize
+}
+
+// tokenBucketKeyChanged returns true if the key of a TokenBucket flow control schema is changed,
+// a keyed bucket and a shared one can not be converted to each other in place
+func tokenBucketKeyChanged(oldSchema, newSchema proxyv1alpha1.FlowControlSchema) bool {
+	if oldSchema.TokenBucket == nil || newSchema.TokenBucket == nil {
+		return false
+	}
+	return oldSchema.TokenBucket.KeyBy != newSchema.TokenBucket.KeyBy
+}
+
+func (c *ClusterInfo) syncSecureServingConfigLocked(next *clusterSnapshot, newSecureServing proxyv1alpha1.SecureServing) error {
+	oldCfg, _ := next.loadSecureServingConfig()
 	if apiequality.Semantic.DeepEqual(oldCfg.secureServing, newSecureServing) {
 		return nil
 	}
@@ -469,12 +847,127 @@ func (c *ClusterInfo) syncSecureServingConfigLocked(newSecureServing proxyv1alph
 		}
 	}
 
-	c.currentSecureServingTLSConfig.Store(&newCfg)
+	next.secureServing = &newCfg
 	return nil
 }
 
 func (c *ClusterInfo) AllEndpoints() []string {
-	return c.Endpoints.Names()
+	return c.loadSnapshot().endpoints.Names()
+}
+
+// IsDisabled returns true if the cluster is marked down manually
+func (c *ClusterInfo) IsDisabled() bool {
+	return c.loadSnapshot().disabled
+}
+
+// ShadowConfig returns the shadow config of this cluster, the bool is false if shadow is not configured
+func (c *ClusterInfo) ShadowConfig() (proxyv1alpha1.ShadowConfig, bool) {
+	shadow := c.loadSnapshot().shadow
+	if shadow == nil {
+		return proxyv1alpha1.ShadowConfig{}, false
+	}
+	return *shadow, true
+}
+
+// RetryPolicy returns the retry policy of this cluster, it returns nil if retry is not configured
+func (c *ClusterInfo) RetryPolicy() *proxyv1alpha1.RetryPolicy {
+	return c.loadSnapshot().retry
+}
+
+// HealthCheckPolicy returns the health check policy of this cluster, it returns nil if it is
+// not configured
+func (c *ClusterInfo) HealthCheckPolicy() *proxyv1alpha1.HealthCheckPolicy {
+	return c.loadSnapshot().healthCheck
+}
+
+// OutlierDetectionPolicy returns the outlier detection policy of this cluster, it returns nil
+// if it is not configured
+func (c *ClusterInfo) OutlierDetectionPolicy() *proxyv1alpha1.OutlierDetectionPolicy {
+	return c.loadSnapshot().outlierDetection
+}
+
+// StubConfig returns the stub config of this cluster, it returns nil if the cluster is not a stub
+func (c *ClusterInfo) StubConfig() *proxyv1alpha1.StubConfig {
+	return c.loadSnapshot().stub
+}
+
+// ServesAPIResource returns whether the resource request is proxied to this cluster by its api
+// resource config. Non-resource requests and all requests of clusters without the config are proxied.
+func (c *ClusterInfo) ServesAPIResource(requestAttributes authorizer.Attributes) bool {
+	config := c.loadSnapshot().apiResources
+	if config == nil || !requestAttributes.IsResourceRequest() {
+		return true
+	}
+	combinedResource := requestAttributes.GetResource()
+	if len(requestAttributes.GetSubresource()) > 0 {
+		combinedResource = requestAttributes.GetResource() + "/" + requestAttributes.GetSubresource()
+	}
+	for _, rule := range config.Rules {
+		if proxyv1alpha1.APIGroupMatches(rule.APIGroups, requestAttributes.GetAPIGroup()) &&
+			proxyv1alpha1.APIVersionMatches(rule.APIVersions, requestAttributes.GetAPIVersion()) &&
+			proxyv1alpha1.ResourceMatches(rule.Resources, combinedResource, requestAttributes.GetSubresource()) {
+			return true
+		}
+	}
+	return false
+}
+
+// HidesResource returns whether the request matches hidden resource rules of this cluster and
+// should be responded as if the resource does not exist.
+func (c *ClusterInfo) HidesResource(requestAttributes authorizer.Attributes) bool {
+	config := c.loadSnapshot().hiddenResources
+	if config == nil {
+		return false
+	}
+	for i := range config.Rules {
+		if RuleMatches(requestAttributes, &config.Rules[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsImpersonatingUser returns whether the user can be impersonated in this cluster according
+// to its impersonation policy, the impersonator must be authorized to impersonate it as well.
+func (c *ClusterInfo) AllowsImpersonatingUser(name string) bool {
+	policy := c.loadSnapshot().impersonation
+	if policy == nil {
+		return true
+	}
+	return impersonationTargetAllowed(policy.AllowedUsers, policy.DeniedUsers, name)
+}
+
+// AllowsImpersonatingGroup returns whether the group can be impersonated in this cluster according
+// to its impersonation policy, the impersonator must be authorized to impersonate it as well.
+func (c *ClusterInfo) AllowsImpersonatingGroup(name string) bool {
+	policy := c.loadSnapshot().impersonation
+	if policy == nil {
+		return true
+	}
+	return impersonationTargetAllowed(policy.AllowedGroups, policy.DeniedGroups, name)
+}
+
+func impersonationTargetAllowed(allowed, denied []string, name string) bool {
+	for _, d := range denied {
+		if d == name {
+			return false
+		}
+	}
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, a := range allowed {
+		if a == name {
+			return true
+		}
+	}
+	return false
+}
+
+// UserGroupsLogMode returns whether user groups are logged in access logs of this cluster,
+// an empty mode means it is not configured.
+func (c *ClusterInfo) UserGroupsLogMode() proxyv1alpha1.LogMode {
+	return c.loadSnapshot().logging.UserGroups
 }
 
 func (c *ClusterInfo) Stop() {
@@ -486,73 +979,288 @@ func (c *ClusterInfo) Stop() {
 
 // MatchAttributes matches a requestAttributes from reqeust and return a flowcontrol and endpointPicker
 func (c *ClusterInfo) MatchAttributes(requestAttributes authorizer.Attributes) (EndpointPicker, error) {
-	policies := c.loadDispatchPolicies()
-	logging := c.loadLoggingConfig()
-	policy := MatchPolicies(requestAttributes, policies)
-	if policy == nil {
+	return c.MatchRequest(requestAttributes, "")
+}
+
+// MatchRequest is the same as MatchAttributes, and additionally matches the User-Agent of
+// request with spec.flowControl.userAgentSchemas.
+func (c *ClusterInfo) MatchRequest(requestAttributes authorizer.Attributes, userAgent string) (EndpointPicker, error) {
+	// everything is read from the same snapshot even if the cluster is syncing
+	snapshot := c.loadSnapshot()
+	policies := snapshot.policies.policies
+	logging := snapshot.logging
+	index := snapshot.policies.match(requestAttributes)
+	if index < 0 {
 		return nil, ErrNoRouterRuleMatches
 	}
+	policy := &policies[index]
 
+	flowControlSchemaName := policy.FlowControlSchemaName
+	if name, ok := snapshot.matchUserAgentFlowControlSchema(userAgent); ok {
+		flowControlSchemaName = name
+	} else if name, ok := snapshot.matchNamespaceFlowControlSchema(requestAttributes.GetNamespace()); ok {
+		flowControlSchemaName = name
+	}
+
+	rejectionStatusCode, rejectionResponse := snapshot.getFlowSchemaRejection(flowControlSchemaName)
 	result := &endpointPickStrategy{
-		cluster:     c,
-		strategy:    policy.Strategy,
-		flowControl: c.getFlowSchema(policy.FlowControlSchemaName),
-		enableLog:   isLogEnabled(logging.Mode, policy.LogMode),
+		cluster:             c,
+		snapshot:            snapshot,
+		policyName:          dispatchPolicyName(policy, index),
+		strategy:            policy.Strategy,
+		flowControl:         c.getFlowSchemaForVerb(snapshot, flowControlSchemaName, requestAttributes.GetVerb()),
+		rejectionStatusCode: rejectionStatusCode,
+		rejectionResponse:   rejectionResponse,
+		enableLog:           isLogEnabled(logging.Mode, policy.LogMode),
 	}
 
 	if len(policy.UpstreamSubset) != 0 {
 		result.upstreams = policy.UpstreamSubset
 	} else {
-		result.upstreams = c.AllEndpoints()
+		result.upstreams = snapshot.endpoints.Names()
 	}
+	result.upstreams = c.isolateUpstreams(requestAttributes.GetUser(), result.upstreams)
 
 	return result, nil
 }
 
 func (c *ClusterInfo) PickOne() (*EndpointInfo, error) {
+	snapshot := c.loadSnapshot()
 	s := &endpointPickStrategy{
 		cluster:   c,
-		upstreams: c.AllEndpoints(),
+		snapshot:  snapshot,
+		upstreams: snapshot.endpoints.Names(),
 	}
 	return s.Pop()
 }
 
-func (c *ClusterInfo) getFlowSchema(name string) gatewayflowcontrol.FlowControl {
+// applyFlowControlOverridesLocked returns the source flow control spec with overrides applied.
+// Overrides whose schema is deleted or changes type in the source spec are dropped.
+func (c *ClusterInfo) applyFlowControlOverridesLocked() proxyv1alpha1.FlowControl {
+	spec := *c.sourceFlowControlSpec.DeepCopy()
+	applied := map[string]bool{}
+	for i, schema := range spec.Schemas {
+		override, ok := c.flowControlOverrides[schema.Name]
+		if !ok {
+			continue
+		}
+		if gatewayflowcontrol.GuessFlowControlSchemaType(schema) != gatewayflowcontrol.GuessFlowControlSchemaType(override) {
+			continue
+		}
+		spec.Schemas[i].FlowControlSchemaConfiguration = *override.FlowControlSchemaConfiguration.DeepCopy()
+		if tokenBucket := spec.Schemas[i].TokenBucket; tokenBucket != nil {
+			if tokenBucket.SlowStartSeconds == 0 {
+				// limits raised by overrides during incidents ramp up as configured in spec
+				tokenBucket.SlowStartSeconds = schema.TokenBucket.SlowStartSeconds
+			}
+			if tokenBucket.KeyBy == "" {
+				// overrides change the limits of each key, requests are keyed as configured in spec
+				tokenBucket.KeyBy = schema.TokenBucket.KeyBy
+			}
+		}
+		applied[schema.Name] = true
+	}
+	for name := range c.flowControlOverrides {
+		if !applied[name] {
+			klog.Warningf("[cluster info] cluster=%q drop flowcontrol override schema=%q, it is deleted or changes type in spec", c.Cluster, name)
+			delete(c.flowControlOverrides, name)
+		}
+	}
+	return spec
+}
+
+// SetFlowControlOverride temporarily overrides parameters of a flow control schema in spec,
+// the type of schema can not be changed. The override takes effect until it is deleted, or
+// the schema is deleted or changes type in spec.
+func (c *ClusterInfo) SetFlowControlOverride(override proxyv1alpha1.FlowControlSchema) error {
+	return c.updateSnapshot(func(next *clusterSnapshot) error {
+		c.flowControlLock.Lock()
+		defer c.flowControlLock.Unlock()
+
+		var source *proxyv1alpha1.FlowControlSchema
+		for i := range c.sourceFlowControlSpec.Schemas {
+			if c.sourceFlowControlSpec.Schemas[i].Name == override.Name {
+				source = &c.sourceFlowControlSpec.Schemas[i]
+				break
+			}
+		}
+		if source == nil {
+			return fmt.Errorf("flow control schema %q is not found in cluster %q", override.Name, c.Cluster)
+		}
+		sourceType := gatewayflowcontrol.GuessFlowControlSchemaType(*source)
+		if overrideType := gatewayflowcontrol.GuessFlowControlSchemaType(override); overrideType != sourceType {
+			return fmt.Errorf("flow control schema %q is %v, it can not be overridden by %v", override.Name, sourceType, overrideType)
+		}
+
+		c.flowControlOverrides[override.Name] = override
+		c.syncFlowControlLocked(next, c.applyFlowControlOverridesLocked())
+		return nil
+	})
+}
+
+// DeleteFlowControlOverride deletes the override of flow control schema, the schema in spec
+// takes effect again. It returns false if the schema is not overridden.
+func (c *ClusterInfo) DeleteFlowControlOverride(name string) bool {
+	deleted := false
+	//nolint:errcheck
+	c.updateSnapshot(func(next *clusterSnapshot) error {
+		c.flowControlLock.Lock()
+		defer c.flowControlLock.Unlock()
+
+		if _, ok := c.flowControlOverrides[name]; !ok {
+			return nil
+		}
+		delete(c.flowControlOverrides, name)
+		c.syncFlowControlLocked(next, c.applyFlowControlOverridesLocked())
+		deleted = true
+		return nil
+	})
+	return deleted
+}
+
+// FlowControlOverrides returns all flow control schema overrides sorted by name
+func (c *ClusterInfo) FlowControlOverrides() []proxyv1alpha1.FlowControlSchema {
+	c.flowControlLock.Lock()
+	defer c.flowControlLock.Unlock()
+
+	ret := []proxyv1alpha1.FlowControlSchema{}
+	for _, override := range c.flowControlOverrides {
+		ret = append(ret, override)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Name < ret[j].Name
+	})
+	return ret
+}
+
+// matchNamespaceFlowControlSchema returns the flow control schema name bound to the namespace
+// by spec.flowControl.namespaceSchemas, an empty name means the request is exempt.
+func (s *clusterSnapshot) matchNamespaceFlowControlSchema(namespace string) (string, bool) {
+	if len(namespace) == 0 {
+		return "", false
+	}
+	for _, ns := range s.flowControlSpec.NamespaceSchemas {
+		if containsString(ns.Namespaces, namespace) {
+			return ns.FlowControlSchemaName, true
+		}
+	}
+	return "", false
+}
+
+// matchUserAgentFlowControlSchema returns the flow control schema name bound to the User-Agent
+// by spec.flowControl.userAgentSchemas.
+func (s *clusterSnapshot) matchUserAgentFlowControlSchema(userAgent string) (string, bool) {
+	if len(userAgent) == 0 {
+		return "", false
+	}
+	for _, ua := range s.flowControlSpec.UserAgentSchemas {
+		for _, prefix := range ua.UserAgentPrefixes {
+			if strings.HasPrefix(userAgent, prefix) {
+				return ua.FlowControlSchemaName, true
+			}
+		}
+	}
+	return "", false
+}
+
+func (c *ClusterInfo) getFlowSchema(snapshot *clusterSnapshot, name string) gatewayflowcontrol.FlowControl {
 	if len(name) == 0 {
 		return c.defaultFlowControl
 	}
-	load, ok := c.flowcontrol.Load(name)
+	load, ok := snapshot.flowControls.Load(name)
 	if !ok {
 		return c.defaultFlowControl
 	}
 	return load
 }
 
-func (c *ClusterInfo) addOrUpdateEndpoint(endpoint string, disabled bool) error {
-	info, ok := c.Endpoints.Load(endpoint)
+// getFlowSchemaForVerb is the same as getFlowSchema, but returns the flow control of the verb
+// if the schema limits requests by verb.
+func (c *ClusterInfo) getFlowSchemaForVerb(snapshot *clusterSnapshot, name, verb string) gatewayflowcontrol.FlowControl {
+	fc := c.getFlowSchema(snapshot, name)
+	if byVerb, ok := fc.(gatewayflowcontrol.VerbFlowControl); ok {
+		return byVerb.ForVerb(verb)
+	}
+	return fc
+}
+
+// FlowControlStatus returns the live status of all flow controls of this cluster,
+// the default flow control is the first one and the others are sorted by name.
+func (c *ClusterInfo) FlowControlStatus() []gatewayflowcontrol.Status {
+	ret := []gatewayflowcontrol.Status{}
+	snapshot := c.loadSnapshot()
+	c.flowControlLock.Lock()
+	snapshot.flowControls.Range(func(name string, fl gatewayflowcontrol.FlowControl) bool {
+		status := fl.Status()
+		_, status.Overridden = c.flowControlOverrides[name]
+		ret = append(ret, status)
+		return true
+	})
+	c.flowControlLock.Unlock()
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Name < ret[j].Name
+	})
+	return append([]gatewayflowcontrol.Status{c.defaultFlowControl.Status()}, ret...)
+}
+
+// getFlowSchemaRejection returns the rejection status code and customized response of
+// the flow control schema, the response of schema takes precedence over the cluster one.
+func (s *clusterSnapshot) getFlowSchemaRejection(name string) (int32, *proxyv1alpha1.RejectionResponse) {
+	spec := s.flowControlSpec
+	if len(name) == 0 {
+		return 0, spec.RejectionResponse
+	}
+	for _, schema := range spec.Schemas {
+		if schema.Name != name {
+			continue
+		}
+		if schema.RejectionResponse != nil {
+			return schema.RejectionStatusCode, schema.RejectionResponse
+		}
+		return schema.RejectionStatusCode, spec.RejectionResponse
+	}
+	return 0, spec.RejectionResponse
+}
+
+func (c *ClusterInfo) addOrUpdateEndpointLocked(next *clusterSnapshot, endpoint string, disabled bool) error {
+	info, ok := next.endpoints.Load(endpoint)
 	if ok {
 		info.SetDisabled(disabled)
 		return nil
 	}
 
-	http2configCopy := *c.restConfig
-	http2configCopy.WrapTransport = transport.NewDynamicImpersonatingRoundTripper
-	http2configCopy.Host = endpoint
-	ts, err := rest.TransportFor(&http2configCopy)
-	if err != nil {
-		klog.Errorf("failed to create http2 transport for <cluster:%s,endpoint:%s>, err: %v", c.Cluster, endpoint, err)
-		return err
+	http2configCopy := *next.restConfig
+	http2configCopy.WrapTransport = next.upstreamTLSSettings.wrapTransport(transport.NewDynamicImpersonatingRoundTripper)
+	if next.disableKeepAlives {
+		http2configCopy.WrapTransport = disableKeepAlivesWrapper(http2configCopy.WrapTransport)
 	}
+	http2configCopy.Host = endpoint
 
 	// since http2 doesn't support websocket, we need to disable http2 when using websocket
 	upgradeConfigCopy := http2configCopy
 	upgradeConfigCopy.NextProtos = []string{"http/1.1"}
+	if err := setUpstreamTransport(&upgradeConfigCopy); err != nil {
+		klog.Errorf("failed to create http/1.1 transport for <cluster:%s,endpoint:%s>, err: %v", c.Cluster, endpoint, err)
+		return err
+	}
 	ts2, err := rest.TransportFor(&upgradeConfigCopy)
 	if err != nil {
 		klog.Errorf("failed to create http/1.1 transport for <cluster:%s,endpoint:%s>, err: %v", c.Cluster, endpoint, err)
 		return err
 	}
 
+	if next.h2c && strings.HasPrefix(endpoint, "http://") {
+		http2configCopy.Transport = newH2CTransport(next.restConfig.Dial)
+	} else if err := setUpstreamTransport(&http2configCopy); err != nil {
+		klog.Errorf("failed to create http2 transport for <cluster:%s,endpoint:%s>, err: %v", c.Cluster, endpoint, err)
+		return err
+	}
+	ts, err := rest.TransportFor(&http2configCopy)
+	if err != nil {
+		klog.Errorf("failed to create http2 transport for <cluster:%s,endpoint:%s>, err: %v", c.Cluster, endpoint, err)
+		return err
+	}
+
 	client, err := kubernetes.NewForConfig(&http2configCopy)
 	if err != nil {
 		klog.Errorf("failed to create clientset for <cluster:%s,endpoint:%s>, err: %v", c.Cluster, endpoint, err)
@@ -567,34 +1275,36 @@ func (c *ClusterInfo) addOrUpdateEndpoint(endpoint string, disabled bool) error
 
 	ctx, cancel := context.WithCancel(c.Context())
 	info = &EndpointInfo{
-		ctx:                   ctx,
-		cancel:                cancel,
-		Cluster:               c.Cluster,
-		Endpoint:              endpoint,
-		status:                initStatus,
-		proxyConfig:           &http2configCopy,
-		ProxyTransport:        ts,
-		proxyUpgradeConfig:    &upgradeConfigCopy,
-		PorxyUpgradeTransport: ts2,
-		clientset:             client,
+		ctx:                    ctx,
+		cancel:                 cancel,
+		Cluster:                c.Cluster,
+		Endpoint:               endpoint,
+		status:                 initStatus,
+		proxyConfig:            &http2configCopy,
+		proxyUpgradeConfig:     &upgradeConfigCopy,
+		PorxyUpgradeTransport:  ts2,
+		clientset:              client,
+		healthCheckPolicy:      c.HealthCheckPolicy,
+		outlierDetectionPolicy: c.OutlierDetectionPolicy,
 	}
+	info.ProxyTransport = &outlierDetectionTransport{endpoint: info, transport: ts}
 
 	klog.Infof("[cluster info] new endpoint added, cluster=%q, endpoint=%q", c.Cluster, info.Endpoint)
-	c.Endpoints.Store(endpoint, info)
+	next.endpoints.Store(endpoint, info)
 
 	if c.endpointHeathCheck != nil {
 		go func() {
 			klog.V(2).Infof("[endpoint info] start health checking for cluster=%q, endpoint=%q", c.Cluster, info.Endpoint)
 			defer klog.V(2).Infof("[endpoint info] stop health checking for cluster=%q, endpoint=%q", c.Cluster, info.Endpoint)
-			//nolint
-			wait.PollImmediateUntil(
-				c.healthCheckIntervalSeconds,
-				func() (done bool, err error) {
-					done = c.endpointHeathCheck(info)
+			// the interval is read before each wait, so that a new health check policy takes
+			// effect without restarting health checks, it backs off while the endpoint is unhealthy
+			for !c.endpointHeathCheck(info) {
+				select {
+				case <-info.ctx.Done():
 					return
-				},
-				info.ctx.Done(),
-			)
+				case <-time.After(info.nextHealthCheckInterval(c.HealthCheckPolicy())):
+				}
+			}
 		}()
 	}
 
@@ -602,19 +1312,29 @@ func (c *ClusterInfo) addOrUpdateEndpoint(endpoint string, disabled bool) error
 }
 
 func (c *ClusterInfo) FeatureEnabled(key featuregate.Feature) bool {
-	return c.featuregate.Enabled(key)
+	return c.featureGate().Enabled(key)
 }
 
-func (c *ClusterInfo) syncFeatureGate(annotations map[string]string) error {
-	featuregate := annotations[features.FeatureGateAnnotationKey]
-	if len(featuregate) == 0 {
-		if !features.IsDefault(c.featuregate) {
+func (c *ClusterInfo) featureGate() featuregate.MutableFeatureGate {
+	return c.loadSnapshot().featureGate
+}
+
+// syncedFeatureGate returns the feature gate set by annotations without changing the current one
+func (c *ClusterInfo) syncedFeatureGate(annotations map[string]string) (featuregate.MutableFeatureGate, error) {
+	current := c.featureGate()
+	value := annotations[features.FeatureGateAnnotationKey]
+	if len(value) == 0 {
+		if !features.IsDefault(current) {
 			// reset featuregate
-			c.featuregate = features.DefaultMutableFeatureGate.DeepCopy()
+			return features.DefaultMutableFeatureGate.DeepCopy(), nil
 		}
-		return nil
+		return current, nil
+	}
+	gate := current.DeepCopy()
+	if err := gate.Set(value); err != nil {
+		return nil, err
 	}
-	return c.featuregate.Set(featuregate)
+	return gate, nil
 }
 
 // upstream policy    enabled
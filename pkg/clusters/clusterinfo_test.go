@@ -15,13 +15,25 @@
 package clusters
 
 import (
+	"crypto/tls"
 	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/pkg/errors"
 	"github.com/zoumo/golib/cert"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
 
 	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
 	"github.com/kubewharf/kubegateway/pkg/flowcontrol"
@@ -88,7 +100,11 @@ func newTestUpstreamClusterConfig() *proxyv1alpha1.UpstreamCluster {
 }
 
 func createTestClusterInfo() *ClusterInfo {
-	ret, _ := CreateClusterInfo(newTestUpstreamClusterConfig(), alwaysReadyHealthCheck)
+	return createTestClusterInfoWithConfig(ClusterInfoConfig{})
+}
+
+func createTestClusterInfoWithConfig(config ClusterInfoConfig) *ClusterInfo {
+	ret, _ := CreateClusterInfoWithConfig(newTestUpstreamClusterConfig(), alwaysReadyHealthCheck, config)
 	return ret
 }
 
@@ -158,6 +174,43 @@ func TestClusterInfo_syncEndpoints(t *testing.T) {
 	}
 }
 
+func TestClusterInfo_syncEndpoints_maxEndpoints(t *testing.T) {
+	// https://127.0.0.1:443 exists in the test cluster
+	clusterInfo := createTestClusterInfoWithConfig(ClusterInfoConfig{MaxEndpoints: 2})
+	defer clusterInfo.Stop()
+	err := clusterInfo.syncEndpoints([]proxyv1alpha1.UpstreamClusterServer{
+		{Endpoint: "https://127.0.0.2:443"},
+		{Endpoint: "https://127.0.0.3:443"},
+		{Endpoint: "https://127.0.0.1:443"},
+	})
+	if err != nil {
+		t.Fatalf("ClusterInfo.syncEndpoints() error = %v", err)
+	}
+	want := sets.NewString("https://127.0.0.1:443", "https://127.0.0.2:443")
+	if got := sets.NewString(clusterInfo.AllEndpoints()...); !got.Equal(want) {
+		t.Errorf("ClusterInfo.syncEndpoints() = %v, want %v", got.List(), want.List())
+	}
+}
+
+func TestClusterInfo_Sync_maxDispatchPolicies(t *testing.T) {
+	clusterInfo := createTestClusterInfoWithConfig(ClusterInfoConfig{DispatchPoliciesWarningThreshold: 1, MaxDispatchPolicies: 2})
+	defer clusterInfo.Stop()
+
+	cluster := newTestUpstreamClusterConfig()
+	policy := cluster.Spec.DispatchPolicies[0]
+	cluster.Spec.DispatchPolicies = []proxyv1alpha1.DispatchPolicy{policy, policy}
+	if err := clusterInfo.Sync(cluster); err != nil {
+		t.Errorf("ClusterInfo.Sync() with policies above warning threshold error = %v", err)
+	}
+	cluster.Spec.DispatchPolicies = append(cluster.Spec.DispatchPolicies, policy)
+	if err := clusterInfo.Sync(cluster); err == nil {
+		t.Errorf("ClusterInfo.Sync() with policies above limit error = nil, want error")
+	}
+	if got := len(clusterInfo.loadSnapshot().policies.policies); got != 2 {
+		t.Errorf("ClusterInfo.Sync() with policies above limit applies %d policies, want last good 2", got)
+	}
+}
+
 func TestClusterInfo_syncSecureServingConfigLocked(t *testing.T) {
 	type args struct {
 		clusterInfo   *ClusterInfo
@@ -253,7 +306,9 @@ func TestClusterInfo_syncSecureServingConfigLocked(t *testing.T) {
 	for i := range tests {
 		tt := tests[i]
 		t.Run(tt.name, func(t *testing.T) {
-			err := tt.args.clusterInfo.syncSecureServingConfigLocked(tt.args.secureServing)
+			err := tt.args.clusterInfo.updateSnapshot(func(next *clusterSnapshot) error {
+				return tt.args.clusterInfo.syncSecureServingConfigLocked(next, tt.args.secureServing)
+			})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("ClusterInfo.syncSecureServingConfigLocked() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -307,6 +362,46 @@ func TestClusterInfo_syncFlowControlLocked(t *testing.T) {
 			},
 		},
 	}
+	queued10 := proxyv1alpha1.FlowControlSchema{
+		Name: "queued",
+		FlowControlSchemaConfiguration: proxyv1alpha1.FlowControlSchemaConfiguration{
+			Queued: &proxyv1alpha1.QueuedFlowControlSchema{
+				Concurrency: 10,
+				QueueLength: 10,
+			},
+		},
+	}
+	queued20 := proxyv1alpha1.FlowControlSchema{
+		Name: "queued",
+		FlowControlSchemaConfiguration: proxyv1alpha1.FlowControlSchemaConfiguration{
+			Queued: &proxyv1alpha1.QueuedFlowControlSchema{
+				Concurrency:    20,
+				QueueLength:    20,
+				MaxWaitSeconds: 30,
+			},
+		},
+	}
+	queued20MoreQueues := proxyv1alpha1.FlowControlSchema{
+		Name: "queued",
+		FlowControlSchemaConfiguration: proxyv1alpha1.FlowControlSchemaConfiguration{
+			Queued: &proxyv1alpha1.QueuedFlowControlSchema{
+				Concurrency: 20,
+				QueueLength: 20,
+				Queues:      128,
+				HandSize:    4,
+			},
+		},
+	}
+	tokenBucket20ByUser := proxyv1alpha1.FlowControlSchema{
+		Name: "tokenbucket",
+		FlowControlSchemaConfiguration: proxyv1alpha1.FlowControlSchemaConfiguration{
+			TokenBucket: &proxyv1alpha1.TokenBucketFlowControlSchema{
+				QPS:   20,
+				Burst: 40,
+				KeyBy: proxyv1alpha1.TokenBucketKeyUser,
+			},
+		},
+	}
 	type args struct {
 		clusterInfo *ClusterInfo
 		oldObj      proxyv1alpha1.FlowControl
@@ -330,15 +425,15 @@ func TestClusterInfo_syncFlowControlLocked(t *testing.T) {
 				},
 			},
 			check: func(info *ClusterInfo) error {
-				_, ok := info.flowcontrol.Load("exempt")
+				_, ok := info.loadSnapshot().flowControls.Load("exempt")
 				if !ok {
 					return fmt.Errorf("missing exempt flowcontrol")
 				}
-				_, ok = info.flowcontrol.Load("max-inflight")
+				_, ok = info.loadSnapshot().flowControls.Load("max-inflight")
 				if !ok {
 					return fmt.Errorf("missing max-inflight flowcontrol")
 				}
-				_, ok = info.flowcontrol.Load("tokenbucket")
+				_, ok = info.loadSnapshot().flowControls.Load("tokenbucket")
 				if !ok {
 					return fmt.Errorf("missing tokenbucket flowcontrol")
 				}
@@ -358,7 +453,7 @@ func TestClusterInfo_syncFlowControlLocked(t *testing.T) {
 				},
 			},
 			check: func(info *ClusterInfo) error {
-				if info.flowcontrol.Len() > 0 {
+				if info.loadSnapshot().flowControls.Len() > 0 {
 					return fmt.Errorf("flow controls are not deleted")
 				}
 				return nil
@@ -382,13 +477,13 @@ func TestClusterInfo_syncFlowControlLocked(t *testing.T) {
 				},
 			},
 			check: func(info *ClusterInfo) error {
-				fl, _ := info.flowcontrol.Load(maxInflight10.Name)
+				fl, _ := info.loadSnapshot().flowControls.Load(maxInflight10.Name)
 				got := fl.String()
 				want := flowcontrol.NewFlowControl(maxInflight20).String()
 				if got != want {
 					return fmt.Errorf("max-inflight is not resized, got=%v, want=%v", got, want)
 				}
-				fl, _ = info.flowcontrol.Load(tokenBucket10.Name)
+				fl, _ = info.loadSnapshot().flowControls.Load(tokenBucket10.Name)
 				got = fl.String()
 				want = flowcontrol.NewFlowControl(tokenBucket20).String()
 				if got != want {
@@ -397,12 +492,92 @@ func TestClusterInfo_syncFlowControlLocked(t *testing.T) {
 				return nil
 			},
 		},
+		{
+			name: "resize queued",
+			args: args{
+				clusterInfo: createTestClusterInfo(),
+				oldObj: proxyv1alpha1.FlowControl{
+					Schemas: []proxyv1alpha1.FlowControlSchema{
+						queued10,
+					},
+				},
+				newObj: proxyv1alpha1.FlowControl{
+					Schemas: []proxyv1alpha1.FlowControlSchema{
+						queued20,
+					},
+				},
+			},
+			check: func(info *ClusterInfo) error {
+				fl, _ := info.loadSnapshot().flowControls.Load(queued10.Name)
+				got := fl.String()
+				want := flowcontrol.NewFlowControl(queued20).String()
+				if got != want {
+					return fmt.Errorf("queued is not resized, got=%v, want=%v", got, want)
+				}
+				return nil
+			},
+		},
+		{
+			name: "recreate queued if queues are changed",
+			args: args{
+				clusterInfo: createTestClusterInfo(),
+				oldObj: proxyv1alpha1.FlowControl{
+					Schemas: []proxyv1alpha1.FlowControlSchema{
+						queued20,
+					},
+				},
+				newObj: proxyv1alpha1.FlowControl{
+					Schemas: []proxyv1alpha1.FlowControlSchema{
+						queued20MoreQueues,
+					},
+				},
+			},
+			check: func(info *ClusterInfo) error {
+				fl, _ := info.loadSnapshot().flowControls.Load(queued20.Name)
+				got := fl.String()
+				want := flowcontrol.NewFlowControl(queued20MoreQueues).String()
+				if got != want {
+					return fmt.Errorf("queued is not recreated, got=%v, want=%v", got, want)
+				}
+				return nil
+			},
+		},
+		{
+			name: "recreate token bucket if key is changed",
+			args: args{
+				clusterInfo: createTestClusterInfo(),
+				oldObj: proxyv1alpha1.FlowControl{
+					Schemas: []proxyv1alpha1.FlowControlSchema{
+						tokenBucket20,
+					},
+				},
+				newObj: proxyv1alpha1.FlowControl{
+					Schemas: []proxyv1alpha1.FlowControlSchema{
+						tokenBucket20ByUser,
+					},
+				},
+			},
+			check: func(info *ClusterInfo) error {
+				fl, _ := info.loadSnapshot().flowControls.Load(tokenBucket20.Name)
+				got := fl.String()
+				want := flowcontrol.NewFlowControl(tokenBucket20ByUser).String()
+				if got != want {
+					return fmt.Errorf("token bucket is not recreated, got=%v, want=%v", got, want)
+				}
+				return nil
+			},
+		},
 	}
 	for i := range tests {
 		tt := tests[i]
 		t.Run(tt.name, func(t *testing.T) {
-			tt.args.clusterInfo.syncFlowControlLocked(tt.args.oldObj)
-			tt.args.clusterInfo.syncFlowControlLocked(tt.args.newObj)
+			for _, obj := range []proxyv1alpha1.FlowControl{tt.args.oldObj, tt.args.newObj} {
+				//nolint:errcheck
+				tt.args.clusterInfo.updateSnapshot(func(next *clusterSnapshot) error {
+					tt.args.clusterInfo.syncFlowControlLocked(next, obj)
+					return nil
+				})
+			}
 			if tt.check != nil {
 				if err := tt.check(tt.args.clusterInfo); err != nil {
 					t.Errorf("ClusterInfo.syncFlowControlLocked() error = %v", err)
@@ -432,6 +607,1066 @@ func TestClusterInfo_sync(t *testing.T) {
 	}
 }
 
+func TestCreateClusterInfo_invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(cluster *proxyv1alpha1.UpstreamCluster)
+	}{
+		{
+			"endpoint without scheme",
+			func(cluster *proxyv1alpha1.UpstreamCluster) {
+				cluster.Spec.Servers[0].Endpoint = "127.0.0.1:443"
+			},
+		},
+		{
+			"endpoint without host",
+			func(cluster *proxyv1alpha1.UpstreamCluster) {
+				cluster.Spec.Servers[0].Endpoint = "https://"
+			},
+		},
+		{
+			"empty name",
+			func(cluster *proxyv1alpha1.UpstreamCluster) {
+				cluster.Name = ""
+			},
+		},
+		{
+			"invalid log mode",
+			func(cluster *proxyv1alpha1.UpstreamCluster) {
+				cluster.Spec.DispatchPolicies[0].LogMode = "verbose"
+			},
+		},
+		{
+			"multiple flow control configurations",
+			func(cluster *proxyv1alpha1.UpstreamCluster) {
+				cluster.Spec.FlowControl.Schemas = []proxyv1alpha1.FlowControlSchema{
+					{
+						Name: "a",
+						FlowControlSchemaConfiguration: proxyv1alpha1.FlowControlSchemaConfiguration{
+							Exempt:      &proxyv1alpha1.ExemptFlowControlSchema{},
+							TokenBucket: &proxyv1alpha1.TokenBucketFlowControlSchema{QPS: 1, Burst: 1},
+						},
+					},
+				}
+			},
+		},
+		{
+			"empty verbs",
+			func(cluster *proxyv1alpha1.UpstreamCluster) {
+				cluster.Spec.DispatchPolicies[0].Rules[0].Verbs = nil
+			},
+		},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := newTestUpstreamClusterConfig()
+			tt.mutate(cluster)
+			info, err := CreateClusterInfo(cluster, alwaysReadyHealthCheck)
+			if err == nil {
+				info.Stop()
+				t.Fatalf("CreateClusterInfo() want error, got nil")
+			}
+		})
+	}
+}
+
+func TestClusterInfo_LoadTLSConfig_clientAuth(t *testing.T) {
+	tests := []struct {
+		clientAuth proxyv1alpha1.ClientAuthMode
+		want       tls.ClientAuthType
+	}{
+		{"", tls.RequestClientCert},
+		{proxyv1alpha1.ClientAuthRequest, tls.RequestClientCert},
+		{proxyv1alpha1.ClientAuthRequireAndVerify, tls.RequireAndVerifyClientCert},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(string(tt.clientAuth), func(t *testing.T) {
+			cluster := newTestUpstreamClusterConfig()
+			cluster.Spec.SecureServing.ClientAuth = tt.clientAuth
+			clusterInfo, err := CreateClusterInfo(cluster, func(*EndpointInfo) bool { return true })
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer clusterInfo.Stop()
+
+			tlsConfig, ok := clusterInfo.LoadTLSConfig()
+			if !ok {
+				t.Fatalf("LoadTLSConfig() returns false")
+			}
+			if tlsConfig.ClientAuth != tt.want {
+				t.Errorf("ClientAuth = %v, want %v", tlsConfig.ClientAuth, tt.want)
+			}
+		})
+	}
+}
+
+func TestClusterInfo_addOrUpdateEndpoint_h2c(t *testing.T) {
+	tests := []struct {
+		name      string
+		h2c       bool
+		wantProto int
+	}{
+		{"http/1.1 by default", false, 1},
+		{"h2c enabled", true, 2},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(h2c.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("X-Proto-Major", fmt.Sprint(req.ProtoMajor))
+			}), &http2.Server{}))
+			defer server.Close()
+
+			cluster := newTestUpstreamClusterConfig()
+			cluster.Spec.Servers = []proxyv1alpha1.UpstreamClusterServer{{Endpoint: server.URL}}
+			cluster.Spec.ClientConfig.H2C = tt.h2c
+			clusterInfo, err := CreateClusterInfo(cluster, func(*EndpointInfo) bool { return true })
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer clusterInfo.Stop()
+
+			info, ok := clusterInfo.Endpoints.Load(server.URL)
+			if !ok {
+				t.Fatalf("endpoint %q is not added", server.URL)
+			}
+			req, _ := http.NewRequest(http.MethodGet, server.URL+"/api", nil)
+			resp, err := info.ProxyTransport.RoundTrip(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if got := resp.Header.Get("X-Proto-Major"); got != fmt.Sprint(tt.wantProto) {
+				t.Errorf("upstream request proto major = %v, want %v", got, tt.wantProto)
+			}
+		})
+	}
+}
+
+func TestClusterInfo_Sync_sourceAddress(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		host, _, _ := net.SplitHostPort(req.RemoteAddr)
+		w.Header().Set("X-Remote-IP", host)
+	}))
+	defer server.Close()
+
+	cluster := newTestUpstreamClusterConfig()
+	cluster.Spec.Servers = []proxyv1alpha1.UpstreamClusterServer{{Endpoint: server.URL}}
+	clusterInfo, err := CreateClusterInfo(cluster, func(*EndpointInfo) bool { return true })
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clusterInfo.Stop()
+
+	tests := []struct {
+		name          string
+		sourceAddress string
+		wantSourceIP  string
+	}{
+		{"bound to source address", "127.0.0.2", "127.0.0.2"},
+		{"source address changed", "127.0.0.3", "127.0.0.3"},
+		{"source address unchanged", "127.0.0.3", "127.0.0.3"},
+		{"default interface", "", "127.0.0.1"},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := cluster.DeepCopy()
+			cluster.Spec.ClientConfig.SourceAddress = tt.sourceAddress
+			if err := clusterInfo.Sync(cluster); err != nil {
+				t.Fatalf("Sync() error = %v", err)
+			}
+
+			info, ok := clusterInfo.Endpoints.Load(server.URL)
+			if !ok {
+				t.Fatalf("endpoint %q is not found", server.URL)
+			}
+			if loaded, _ := clusterInfo.loadSnapshot().endpoints.Load(server.URL); loaded != info {
+				t.Errorf("Endpoints is not in line with the published snapshot")
+			}
+			req, _ := http.NewRequest(http.MethodGet, server.URL+"/api", nil)
+			resp, err := info.ProxyTransport.RoundTrip(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if got := resp.Header.Get("X-Remote-IP"); got != tt.wantSourceIP {
+				t.Errorf("upstream request source IP = %v, want %v", got, tt.wantSourceIP)
+			}
+		})
+	}
+}
+
+func TestClusterInfo_StubConfig(t *testing.T) {
+	cluster := newTestUpstreamClusterConfig()
+	cluster.Spec.Servers = nil
+	cluster.Spec.DispatchPolicies = nil
+	cluster.Spec.Stub = &proxyv1alpha1.StubConfig{Message: "provisioning"}
+	clusterInfo, err := CreateClusterInfo(cluster, func(*EndpointInfo) bool { return true })
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clusterInfo.Stop()
+	if stub := clusterInfo.StubConfig(); stub == nil || stub.Message != "provisioning" {
+		t.Errorf("StubConfig() = %v, want message %q", stub, "provisioning")
+	}
+
+	// the cluster is provisioned
+	cluster = newTestUpstreamClusterConfig()
+	if err := clusterInfo.Sync(cluster); err != nil {
+		t.Fatal(err)
+	}
+	if stub := clusterInfo.StubConfig(); stub != nil {
+		t.Errorf("StubConfig() = %v after provisioned, want nil", stub)
+	}
+	if got := clusterInfo.AllEndpoints(); len(got) != 1 {
+		t.Errorf("AllEndpoints() = %v after provisioned, want 1 endpoint", got)
+	}
+}
+
+func TestClusterInfo_ServesAPIResource(t *testing.T) {
+	apiResources := &proxyv1alpha1.APIResourceConfig{
+		Rules: []proxyv1alpha1.APIResourceRule{
+			{APIGroups: []string{""}, APIVersions: []string{"v1"}, Resources: []string{"*"}},
+			{APIGroups: []string{"apps"}, APIVersions: []string{"v1"}, Resources: []string{"deployments", "deployments/scale"}},
+		},
+	}
+	resource := func(group, version, resource, subresource string) authorizer.AttributesRecord {
+		return authorizer.AttributesRecord{
+			User:            &user.DefaultInfo{Name: "test"},
+			Verb:            "get",
+			APIGroup:        group,
+			APIVersion:      version,
+			Resource:        resource,
+			Subresource:     subresource,
+			ResourceRequest: true,
+		}
+	}
+	tests := []struct {
+		name         string
+		apiResources *proxyv1alpha1.APIResourceConfig
+		attrs        authorizer.AttributesRecord
+		want         bool
+	}{
+		{"no config", nil, resource("batch", "v1beta1", "cronjobs", ""), true},
+		{"non-resource request", apiResources, authorizer.AttributesRecord{Verb: "get", Path: "/apis/batch/v1beta1"}, true},
+		{"core resource", apiResources, resource("", "v1", "pods", ""), true},
+		{"core subresource", apiResources, resource("", "v1", "pods", "log"), true},
+		{"allowed resource", apiResources, resource("apps", "v1", "deployments", ""), true},
+		{"allowed subresource", apiResources, resource("apps", "v1", "deployments", "scale"), true},
+		{"subresource not allowed", apiResources, resource("apps", "v1", "deployments", "status"), false},
+		{"version not allowed", apiResources, resource("apps", "v1beta1", "deployments", ""), false},
+		{"group not allowed", apiResources, resource("batch", "v1beta1", "cronjobs", ""), false},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := newTestUpstreamClusterConfig()
+			cluster.Spec.APIResources = tt.apiResources
+			clusterInfo, err := CreateClusterInfo(cluster, func(*EndpointInfo) bool { return true })
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer clusterInfo.Stop()
+			if got := clusterInfo.ServesAPIResource(tt.attrs); got != tt.want {
+				t.Errorf("ClusterInfo.ServesAPIResource() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClusterInfo_HidesResource(t *testing.T) {
+	hiddenResources := &proxyv1alpha1.HiddenResourceConfig{
+		Rules: []proxyv1alpha1.DispatchPolicyRule{
+			{Verbs: []string{"get", "list"}, APIGroups: []string{""}, Resources: []string{"secrets"}, Users: []string{"-admin"}},
+		},
+	}
+	request := func(userName, verb, resource string) authorizer.AttributesRecord {
+		return authorizer.AttributesRecord{
+			User:            &user.DefaultInfo{Name: userName},
+			Verb:            verb,
+			APIVersion:      "v1",
+			Resource:        resource,
+			ResourceRequest: true,
+		}
+	}
+	tests := []struct {
+		name            string
+		hiddenResources *proxyv1alpha1.HiddenResourceConfig
+		attrs           authorizer.AttributesRecord
+		want            bool
+	}{
+		{"no config", nil, request("test", "get", "secrets"), false},
+		{"hidden resource", hiddenResources, request("test", "get", "secrets"), true},
+		{"verb not matched", hiddenResources, request("test", "delete", "secrets"), false},
+		{"resource not matched", hiddenResources, request("test", "get", "configmaps"), false},
+		{"user not matched", hiddenResources, request("admin", "list", "secrets"), false},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := newTestUpstreamClusterConfig()
+			cluster.Spec.HiddenResources = tt.hiddenResources
+			clusterInfo, err := CreateClusterInfo(cluster, func(*EndpointInfo) bool { return true })
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer clusterInfo.Stop()
+			if got := clusterInfo.HidesResource(tt.attrs); got != tt.want {
+				t.Errorf("ClusterInfo.HidesResource() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClusterInfo_AllowsImpersonating(t *testing.T) {
+	denied := &proxyv1alpha1.ImpersonationPolicy{
+		DeniedUsers:  []string{"admin"},
+		DeniedGroups: []string{"system:masters"},
+	}
+	allowed := &proxyv1alpha1.ImpersonationPolicy{
+		AllowedUsers:  []string{"dev", "admin"},
+		DeniedUsers:   []string{"admin"},
+		AllowedGroups: []string{"developers"},
+	}
+	tests := []struct {
+		name      string
+		policy    *proxyv1alpha1.ImpersonationPolicy
+		user      string
+		group     string
+		wantUser  bool
+		wantGroup bool
+	}{
+		{"no policy", nil, "admin", "system:masters", true, true},
+		{"denied", denied, "admin", "system:masters", false, false},
+		{"not denied", denied, "dev", "developers", true, true},
+		{"allowed", allowed, "dev", "developers", true, true},
+		{"not allowed", allowed, "ops", "system:masters", false, false},
+		{"allowed but denied", allowed, "admin", "developers", false, true},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := newTestUpstreamClusterConfig()
+			cluster.Spec.Impersonation = tt.policy
+			clusterInfo, err := CreateClusterInfo(cluster, func(*EndpointInfo) bool { return true })
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer clusterInfo.Stop()
+			if got := clusterInfo.AllowsImpersonatingUser(tt.user); got != tt.wantUser {
+				t.Errorf("ClusterInfo.AllowsImpersonatingUser() = %v, want %v", got, tt.wantUser)
+			}
+			if got := clusterInfo.AllowsImpersonatingGroup(tt.group); got != tt.wantGroup {
+				t.Errorf("ClusterInfo.AllowsImpersonatingGroup() = %v, want %v", got, tt.wantGroup)
+			}
+		})
+	}
+}
+
+func TestClusterInfo_APIAudiences(t *testing.T) {
+	tests := []struct {
+		name      string
+		audiences []string
+		update    []string
+		want      []string
+	}{
+		{"not set", nil, nil, nil},
+		{"set", []string{"cluster-a"}, []string{"cluster-a"}, []string{"cluster-a"}},
+		{"updated", []string{"cluster-a"}, []string{"cluster-a", "cluster-b"}, []string{"cluster-a", "cluster-b"}},
+		{"removed", []string{"cluster-a"}, nil, nil},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := newTestUpstreamClusterConfig()
+			cluster.Spec.SecureServing.APIAudiences = tt.audiences
+			clusterInfo, err := CreateClusterInfo(cluster, func(*EndpointInfo) bool { return true })
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer clusterInfo.Stop()
+
+			cluster = cluster.DeepCopy()
+			cluster.Spec.SecureServing.APIAudiences = tt.update
+			if err := clusterInfo.Sync(cluster); err != nil {
+				t.Fatal(err)
+			}
+			if got := clusterInfo.APIAudiences(); !apiequality.Semantic.DeepEqual(got, tt.want) {
+				t.Errorf("ClusterInfo.APIAudiences() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClusterInfo_HasMinHealthyEndpoints(t *testing.T) {
+	tests := []struct {
+		name                string
+		minHealthyEndpoints int32
+		healthy             []bool
+		wantReady           int
+		want                bool
+	}{
+		{"no limit", 0, []bool{false, false, false}, 0, true},
+		{"enough healthy endpoints", 2, []bool{true, true, false}, 2, true},
+		{"not enough healthy endpoints", 2, []bool{true, false, false}, 1, false},
+		{"all healthy endpoints required", 3, []bool{true, true, false}, 2, false},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := newTestUpstreamClusterConfig()
+			cluster.Spec.Servers = nil
+			for i := range tt.healthy {
+				cluster.Spec.Servers = append(cluster.Spec.Servers, proxyv1alpha1.UpstreamClusterServer{
+					Endpoint: fmt.Sprintf("https://127.0.0.%d:443", i+1),
+				})
+			}
+			cluster.Spec.MinHealthyEndpoints = tt.minHealthyEndpoints
+
+			// skip health checking, endpoints status is set below
+			clusterInfo, err := CreateClusterInfo(cluster, func(*EndpointInfo) bool { return true })
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer clusterInfo.Stop()
+			for i, healthy := range tt.healthy {
+				info, _ := clusterInfo.Endpoints.Load(fmt.Sprintf("https://127.0.0.%d:443", i+1))
+				info.UpdateStatus(healthy, "", "")
+			}
+
+			picker, err := clusterInfo.MatchAttributes(authorizer.AttributesRecord{
+				User:            &user.DefaultInfo{Name: "test"},
+				Verb:            "get",
+				Resource:        "pods",
+				ResourceRequest: true,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			ready, min, got := picker.HasMinHealthyEndpoints()
+			if ready != tt.wantReady || min != int(tt.minHealthyEndpoints) || got != tt.want {
+				t.Errorf("HasMinHealthyEndpoints() = (%v, %v, %v), want (%v, %v, %v)", ready, min, got, tt.wantReady, tt.minHealthyEndpoints, tt.want)
+			}
+
+			// endpoints are counted in the snapshot of the request even if the cluster is synced
+			synced := cluster.DeepCopy()
+			synced.Spec.Servers = synced.Spec.Servers[:1]
+			synced.Spec.MinHealthyEndpoints = 0
+			if err := clusterInfo.Sync(synced); err != nil {
+				t.Fatal(err)
+			}
+			if ready, min, got := picker.HasMinHealthyEndpoints(); ready != tt.wantReady || min != int(tt.minHealthyEndpoints) || got != tt.want {
+				t.Errorf("HasMinHealthyEndpoints() after Sync() = (%v, %v, %v), want (%v, %v, %v)", ready, min, got, tt.wantReady, tt.minHealthyEndpoints, tt.want)
+			}
+		})
+	}
+}
+
+func TestClusterInfo_MatchAttributes_namespaceSchemas(t *testing.T) {
+	cluster := newTestUpstreamClusterConfig()
+	cluster.Spec.FlowControl = proxyv1alpha1.FlowControl{
+		Schemas: []proxyv1alpha1.FlowControlSchema{
+			{
+				Name: "workloads",
+				FlowControlSchemaConfiguration: proxyv1alpha1.FlowControlSchemaConfiguration{
+					MaxRequestsInflight: &proxyv1alpha1.MaxRequestsInflightFlowControlSchema{Max: 10},
+				},
+			},
+			{
+				Name: "monitoring",
+				FlowControlSchemaConfiguration: proxyv1alpha1.FlowControlSchemaConfiguration{
+					MaxRequestsInflight: &proxyv1alpha1.MaxRequestsInflightFlowControlSchema{Max: 100},
+				},
+			},
+		},
+		NamespaceSchemas: []proxyv1alpha1.NamespaceFlowControlSchema{
+			{Namespaces: []string{"kube-system", "kube-public"}},
+			{Namespaces: []string{"monitoring"}, FlowControlSchemaName: "monitoring"},
+		},
+	}
+	cluster.Spec.DispatchPolicies[0].FlowControlSchemaName = "workloads"
+
+	clusterInfo, err := CreateClusterInfo(cluster, func(*EndpointInfo) bool { return true })
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clusterInfo.Stop()
+
+	tests := []struct {
+		namespace string
+		want      string
+	}{
+		{"default", "workloads"},
+		{"", "workloads"},
+		{"kube-system", flowcontrol.DefaultFlowControlSchema.Name},
+		{"kube-public", flowcontrol.DefaultFlowControlSchema.Name},
+		{"monitoring", "monitoring"},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.namespace, func(t *testing.T) {
+			picker, err := clusterInfo.MatchAttributes(authorizer.AttributesRecord{
+				User:            &user.DefaultInfo{Name: "test"},
+				Verb:            "list",
+				Namespace:       tt.namespace,
+				Resource:        "pods",
+				ResourceRequest: true,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := picker.FlowControl().Name(); got != tt.want {
+				t.Errorf("FlowControl().Name() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClusterInfo_MatchRequest_userAgentSchemas(t *testing.T) {
+	cluster := newTestUpstreamClusterConfig()
+	cluster.Spec.FlowControl = proxyv1alpha1.FlowControl{
+		Schemas: []proxyv1alpha1.FlowControlSchema{
+			{
+				Name: "workloads",
+				FlowControlSchemaConfiguration: proxyv1alpha1.FlowControlSchemaConfiguration{
+					MaxRequestsInflight: &proxyv1alpha1.MaxRequestsInflightFlowControlSchema{Max: 10},
+				},
+			},
+			{
+				Name: "control-plane",
+				FlowControlSchemaConfiguration: proxyv1alpha1.FlowControlSchemaConfiguration{
+					MaxRequestsInflight: &proxyv1alpha1.MaxRequestsInflightFlowControlSchema{Max: 1000},
+				},
+			},
+		},
+		NamespaceSchemas: []proxyv1alpha1.NamespaceFlowControlSchema{
+			{Namespaces: []string{"kube-system"}},
+		},
+		UserAgentSchemas: []proxyv1alpha1.UserAgentFlowControlSchema{
+			{UserAgentPrefixes: []string{"kube-controller-manager/", "kube-scheduler/"}, FlowControlSchemaName: "control-plane"},
+		},
+	}
+	cluster.Spec.DispatchPolicies[0].FlowControlSchemaName = "workloads"
+
+	clusterInfo, err := CreateClusterInfo(cluster, func(*EndpointInfo) bool { return true })
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clusterInfo.Stop()
+
+	tests := []struct {
+		name      string
+		userAgent string
+		namespace string
+		want      string
+	}{
+		{"kubectl", "kubectl/v1.18.10 (linux/amd64) kubernetes/abcdef", "default", "workloads"},
+		{"no user agent", "", "default", "workloads"},
+		{"controller manager", "kube-controller-manager/v1.18.10 (linux/amd64) kubernetes/abcdef/leader-election", "default", "control-plane"},
+		{"scheduler", "kube-scheduler/v1.18.10 (linux/amd64) kubernetes/abcdef", "default", "control-plane"},
+		{"user agent takes precedence over namespace", "kube-scheduler/v1.18.10", "kube-system", "control-plane"},
+		{"namespace", "kubectl/v1.18.10", "kube-system", flowcontrol.DefaultFlowControlSchema.Name},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			picker, err := clusterInfo.MatchRequest(authorizer.AttributesRecord{
+				User:            &user.DefaultInfo{Name: "test"},
+				Verb:            "list",
+				Namespace:       tt.namespace,
+				Resource:        "pods",
+				ResourceRequest: true,
+			}, tt.userAgent)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := picker.FlowControl().Name(); got != tt.want {
+				t.Errorf("FlowControl().Name() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClusterInfo_MatchRequest_weightedRandom(t *testing.T) {
+	weight := func(w int32) *int32 { return &w }
+	tests := []struct {
+		name    string
+		weights []*int32
+		// unready is the index of the endpoint which is not ready, -1 means all are ready
+		unready int
+	}{
+		{"default weights", []*int32{nil, nil, nil}, -1},
+		{"weighted", []*int32{weight(1), weight(3), weight(6)}, -1},
+		{"unset weight is 1", []*int32{nil, weight(2), weight(7)}, -1},
+		{"weights of ready endpoints are renormalized", []*int32{weight(1), weight(3), weight(6)}, 2},
+		{"zero weight drains the endpoint", []*int32{weight(0), weight(1), nil}, -1},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := newTestUpstreamClusterConfig()
+			cluster.Spec.Servers = nil
+			// want are the weights in effect, unset weights are 1
+			want := make([]int32, len(tt.weights))
+			var total int32
+			for i, w := range tt.weights {
+				cluster.Spec.Servers = append(cluster.Spec.Servers, proxyv1alpha1.UpstreamClusterServer{
+					Endpoint: fmt.Sprintf("https://127.0.0.%d:443", i+1),
+					Weight:   w,
+				})
+				want[i] = 1
+				if w != nil {
+					want[i] = *w
+				}
+				if i != tt.unready {
+					total += want[i]
+				}
+			}
+			cluster.Spec.DispatchPolicies[0].Strategy = proxyv1alpha1.WeightedRandom
+
+			clusterInfo, err := CreateClusterInfo(cluster, func(*EndpointInfo) bool { return true })
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer clusterInfo.Stop()
+			for i, server := range cluster.Spec.Servers {
+				info, _ := clusterInfo.Endpoints.Load(server.Endpoint)
+				info.UpdateStatus(i != tt.unready, "", "")
+			}
+
+			// every request matches and picks on its own, like streams of one HTTP/2 connection
+			const requests = 20000
+			counts := map[string]int{}
+			for i := 0; i < requests; i++ {
+				picker, err := clusterInfo.MatchRequest(authorizer.AttributesRecord{
+					User:            &user.DefaultInfo{Name: "test"},
+					Verb:            "get",
+					Namespace:       "default",
+					Resource:        "pods",
+					ResourceRequest: true,
+				}, "")
+				if err != nil {
+					t.Fatal(err)
+				}
+				info, err := picker.Pop()
+				if err != nil {
+					t.Fatal(err)
+				}
+				counts[info.Endpoint]++
+			}
+
+			for i, server := range cluster.Spec.Servers {
+				wantRatio := float64(want[i]) / float64(total)
+				if i == tt.unready {
+					wantRatio = 0
+				}
+				got := float64(counts[server.Endpoint]) / requests
+				if math.Abs(got-wantRatio) > 0.02 {
+					t.Errorf("endpoint %v is picked %.3f of requests, want %.3f", server.Endpoint, got, wantRatio)
+				}
+			}
+		})
+	}
+}
+
+func TestClusterInfo_MatchRequest_drainedByZeroWeight(t *testing.T) {
+	zero := int32(0)
+	drained := "https://127.0.0.1:443"
+	tests := []struct {
+		name     string
+		strategy proxyv1alpha1.Strategy
+		servers  []proxyv1alpha1.UpstreamClusterServer
+		wantErr  bool
+	}{
+		{
+			name:     "weighted random",
+			strategy: proxyv1alpha1.WeightedRandom,
+			servers:  []proxyv1alpha1.UpstreamClusterServer{{Endpoint: drained, Weight: &zero}, {Endpoint: "https://127.0.0.2:443"}},
+		},
+		{
+			name:     "round robin",
+			strategy: proxyv1alpha1.RoundRobin,
+			servers:  []proxyv1alpha1.UpstreamClusterServer{{Endpoint: drained, Weight: &zero}, {Endpoint: "https://127.0.0.2:443"}},
+		},
+		{
+			name:     "all endpoints are drained",
+			strategy: proxyv1alpha1.WeightedRandom,
+			servers:  []proxyv1alpha1.UpstreamClusterServer{{Endpoint: drained, Weight: &zero}},
+			wantErr:  true,
+		},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := newTestUpstreamClusterConfig()
+			cluster.Spec.Servers = tt.servers
+			cluster.Spec.DispatchPolicies[0].Strategy = tt.strategy
+
+			checked := make(chan string, 10)
+			clusterInfo, err := CreateClusterInfo(cluster, func(e *EndpointInfo) bool {
+				e.UpdateStatus(true, "", "")
+				select {
+				case checked <- e.Endpoint:
+				default:
+				}
+				return true
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer clusterInfo.Stop()
+
+			// the drained endpoint is still health checked
+			healthChecked := map[string]bool{}
+			timeout := time.After(10 * time.Second)
+			for len(healthChecked) < len(tt.servers) {
+				select {
+				case endpoint := <-checked:
+					healthChecked[endpoint] = true
+				case <-timeout:
+					t.Fatalf("health checked endpoints = %v, want all of %d", healthChecked, len(tt.servers))
+				}
+			}
+			if info, _ := clusterInfo.Endpoints.Load(drained); !info.IsReady() {
+				t.Errorf("drained endpoint is not ready after health checking")
+			}
+
+			for i := 0; i < 100; i++ {
+				picker, err := clusterInfo.MatchRequest(authorizer.AttributesRecord{
+					User:            &user.DefaultInfo{Name: "test"},
+					Verb:            "get",
+					Resource:        "pods",
+					ResourceRequest: true,
+				}, "")
+				if err != nil {
+					t.Fatal(err)
+				}
+				info, err := picker.Pop()
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("Pop() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if tt.wantErr {
+					if errors.Cause(err) != ErrNoReadyEndpoints {
+						t.Errorf("Pop() error = %v, want %v", err, ErrNoReadyEndpoints)
+					}
+					return
+				}
+				if info.Endpoint == drained {
+					t.Fatalf("Pop() = %v, want the drained endpoint never picked", info.Endpoint)
+				}
+			}
+		})
+	}
+}
+
+func TestClusterInfo_MatchRequest_roundRobin(t *testing.T) {
+	cluster := newTestUpstreamClusterConfig()
+	cluster.Spec.Servers = []proxyv1alpha1.UpstreamClusterServer{
+		{Endpoint: "https://127.0.0.1:443"},
+		{Endpoint: "https://127.0.0.2:443"},
+		{Endpoint: "https://127.0.0.3:443"},
+	}
+	cluster.Spec.DispatchPolicies[0].Strategy = proxyv1alpha1.RoundRobin
+	clusterInfo, err := CreateClusterInfo(cluster, func(*EndpointInfo) bool { return true })
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clusterInfo.Stop()
+	for _, server := range cluster.Spec.Servers {
+		info, _ := clusterInfo.Endpoints.Load(server.Endpoint)
+		info.UpdateStatus(true, "", "")
+	}
+
+	pick := func(requests int) map[string]int {
+		var lock sync.Mutex
+		var wg sync.WaitGroup
+		counts := map[string]int{}
+		for i := 0; i < requests; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				picker, err := clusterInfo.MatchRequest(authorizer.AttributesRecord{
+					User:            &user.DefaultInfo{Name: "test"},
+					Verb:            "get",
+					Namespace:       "default",
+					Resource:        "pods",
+					ResourceRequest: true,
+				}, "")
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				info, err := picker.Pop()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				lock.Lock()
+				counts[info.Endpoint]++
+				lock.Unlock()
+			}()
+		}
+		wg.Wait()
+		return counts
+	}
+
+	// concurrent picks are spread evenly
+	want := map[string]int{"https://127.0.0.1:443": 100, "https://127.0.0.2:443": 100, "https://127.0.0.3:443": 100}
+	if got := pick(300); !apiequality.Semantic.DeepEqual(got, want) {
+		t.Errorf("picked endpoints = %v, want %v", got, want)
+	}
+
+	// unready endpoints are skipped
+	info, _ := clusterInfo.Endpoints.Load("https://127.0.0.2:443")
+	info.UpdateStatus(false, "Timeout", "")
+	want = map[string]int{"https://127.0.0.1:443": 100, "https://127.0.0.3:443": 100}
+	if got := pick(200); !apiequality.Semantic.DeepEqual(got, want) {
+		t.Errorf("picked endpoints after one becomes unready = %v, want %v", got, want)
+	}
+}
+
+func TestClusterInfo_MatchRequest_latencyWeightedRandom(t *testing.T) {
+	tests := []struct {
+		name      string
+		latencies []time.Duration
+		want      []float64
+	}{
+		{"no latency", []time.Duration{0, 0}, []float64{0.5, 0.5}},
+		{"same latency", []time.Duration{10 * time.Millisecond, 10 * time.Millisecond}, []float64{0.5, 0.5}},
+		{"slower endpoint is reduced", []time.Duration{10 * time.Millisecond, 40 * time.Millisecond}, []float64{0.8, 0.2}},
+		{"reduced weight is bounded", []time.Duration{10 * time.Millisecond, time.Second}, []float64{1 / 1.1, 0.1 / 1.1}},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := newTestUpstreamClusterConfig()
+			cluster.Spec.Servers = nil
+			for i := range tt.latencies {
+				cluster.Spec.Servers = append(cluster.Spec.Servers, proxyv1alpha1.UpstreamClusterServer{
+					Endpoint: fmt.Sprintf("https://127.0.0.%d:443", i+1),
+				})
+			}
+			cluster.Spec.DispatchPolicies[0].Strategy = proxyv1alpha1.LatencyWeightedRandom
+			clusterInfo, err := CreateClusterInfo(cluster, func(*EndpointInfo) bool { return true })
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer clusterInfo.Stop()
+			for i, server := range cluster.Spec.Servers {
+				info, _ := clusterInfo.Endpoints.Load(server.Endpoint)
+				info.UpdateStatus(true, "", "")
+				info.RecordHealthCheckLatency(tt.latencies[i])
+			}
+
+			const requests = 20000
+			counts := map[string]int{}
+			for i := 0; i < requests; i++ {
+				picker, err := clusterInfo.MatchRequest(authorizer.AttributesRecord{
+					User:            &user.DefaultInfo{Name: "test"},
+					Verb:            "get",
+					Namespace:       "default",
+					Resource:        "pods",
+					ResourceRequest: true,
+				}, "")
+				if err != nil {
+					t.Fatal(err)
+				}
+				info, err := picker.Pop()
+				if err != nil {
+					t.Fatal(err)
+				}
+				counts[info.Endpoint]++
+			}
+			for i, server := range cluster.Spec.Servers {
+				got := float64(counts[server.Endpoint]) / requests
+				if math.Abs(got-tt.want[i]) > 0.02 {
+					t.Errorf("endpoint %v is picked %.3f of requests, want %.3f", server.Endpoint, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestClusterInfo_MatchRequest_leastConnections(t *testing.T) {
+	tests := []struct {
+		name     string
+		inflight []int
+		unready  int
+		want     []string
+	}{
+		{"fewest in-flight requests", []int{3, 1, 2}, -1, []string{"https://127.0.0.2:443"}},
+		{"unready endpoints are skipped", []int{3, 1, 2}, 1, []string{"https://127.0.0.3:443"}},
+		{"ties are broken at random", []int{1, 1, 2}, -1, []string{"https://127.0.0.1:443", "https://127.0.0.2:443"}},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := newTestUpstreamClusterConfig()
+			cluster.Spec.Servers = nil
+			for i := range tt.inflight {
+				cluster.Spec.Servers = append(cluster.Spec.Servers, proxyv1alpha1.UpstreamClusterServer{
+					Endpoint: fmt.Sprintf("https://127.0.0.%d:443", i+1),
+				})
+			}
+			cluster.Spec.DispatchPolicies[0].Strategy = proxyv1alpha1.LeastConnections
+			clusterInfo, err := CreateClusterInfo(cluster, func(*EndpointInfo) bool { return true })
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer clusterInfo.Stop()
+			for i, server := range cluster.Spec.Servers {
+				info, _ := clusterInfo.Endpoints.Load(server.Endpoint)
+				info.UpdateStatus(i != tt.unready, "", "")
+				for j := 0; j < tt.inflight[i]; j++ {
+					info.TrackRequest()
+				}
+			}
+
+			picked := sets.NewString()
+			for i := 0; i < 100; i++ {
+				picker, err := clusterInfo.MatchRequest(authorizer.AttributesRecord{
+					User:            &user.DefaultInfo{Name: "test"},
+					Verb:            "watch",
+					Namespace:       "default",
+					Resource:        "pods",
+					ResourceRequest: true,
+				}, "")
+				if err != nil {
+					t.Fatal(err)
+				}
+				info, err := picker.Pop()
+				if err != nil {
+					t.Fatal(err)
+				}
+				picked.Insert(info.Endpoint)
+			}
+			if want := sets.NewString(tt.want...); !picked.Equal(want) {
+				t.Errorf("picked endpoints = %v, want %v", picked.List(), want.List())
+			}
+		})
+	}
+}
+
+func TestClusterInfo_SetFlowControlOverride(t *testing.T) {
+	cluster := newTestUpstreamClusterConfig()
+	cluster.Spec.FlowControl = proxyv1alpha1.FlowControl{
+		Schemas: []proxyv1alpha1.FlowControlSchema{
+			{
+				Name: "limited",
+				FlowControlSchemaConfiguration: proxyv1alpha1.FlowControlSchemaConfiguration{
+					MaxRequestsInflight: &proxyv1alpha1.MaxRequestsInflightFlowControlSchema{Max: 100},
+				},
+			},
+		},
+	}
+	clusterInfo, err := CreateClusterInfo(cluster, func(*EndpointInfo) bool { return true })
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clusterInfo.Stop()
+
+	limitedMax := func() (uint32, bool) {
+		for _, status := range clusterInfo.FlowControlStatus() {
+			if status.Name == "limited" {
+				return status.Max, status.Overridden
+			}
+		}
+		return 0, false
+	}
+	maxInflight := func(max int32) proxyv1alpha1.FlowControlSchemaConfiguration {
+		return proxyv1alpha1.FlowControlSchemaConfiguration{
+			MaxRequestsInflight: &proxyv1alpha1.MaxRequestsInflightFlowControlSchema{Max: max},
+		}
+	}
+
+	// schema type can not be changed
+	if err := clusterInfo.SetFlowControlOverride(proxyv1alpha1.FlowControlSchema{
+		Name:                           "limited",
+		FlowControlSchemaConfiguration: proxyv1alpha1.FlowControlSchemaConfiguration{Exempt: &proxyv1alpha1.ExemptFlowControlSchema{}},
+	}); err == nil {
+		t.Errorf("SetFlowControlOverride() with another type should fail")
+	}
+	if err := clusterInfo.SetFlowControlOverride(proxyv1alpha1.FlowControlSchema{Name: "unknown", FlowControlSchemaConfiguration: maxInflight(1)}); err == nil {
+		t.Errorf("SetFlowControlOverride() with unknown schema should fail")
+	}
+
+	if err := clusterInfo.SetFlowControlOverride(proxyv1alpha1.FlowControlSchema{Name: "limited", FlowControlSchemaConfiguration: maxInflight(10)}); err != nil {
+		t.Fatalf("SetFlowControlOverride() error = %v", err)
+	}
+	if max, overridden := limitedMax(); max != 10 || !overridden {
+		t.Errorf("after override, max = %v, overridden = %v, want 10, true", max, overridden)
+	}
+
+	// syncing the same spec keeps the override
+	if err := clusterInfo.Sync(cluster); err != nil {
+		t.Fatal(err)
+	}
+	if max, overridden := limitedMax(); max != 10 || !overridden {
+		t.Errorf("after sync, max = %v, overridden = %v, want 10, true", max, overridden)
+	}
+
+	if !clusterInfo.DeleteFlowControlOverride("limited") {
+		t.Errorf("DeleteFlowControlOverride() = false, want true")
+	}
+	if max, overridden := limitedMax(); max != 100 || overridden {
+		t.Errorf("after deleting override, max = %v, overridden = %v, want 100, false", max, overridden)
+	}
+
+	// override is dropped once the schema changes type in spec
+	if err := clusterInfo.SetFlowControlOverride(proxyv1alpha1.FlowControlSchema{Name: "limited", FlowControlSchemaConfiguration: maxInflight(10)}); err != nil {
+		t.Fatalf("SetFlowControlOverride() error = %v", err)
+	}
+	cluster.Spec.FlowControl.Schemas[0].FlowControlSchemaConfiguration = proxyv1alpha1.FlowControlSchemaConfiguration{
+		TokenBucket: &proxyv1alpha1.TokenBucketFlowControlSchema{QPS: 10, Burst: 10},
+	}
+	if err := clusterInfo.Sync(cluster); err != nil {
+		t.Fatal(err)
+	}
+	if got := clusterInfo.FlowControlOverrides(); len(got) != 0 {
+		t.Errorf("FlowControlOverrides() = %v, want empty", got)
+	}
+}
+
+func TestClusterInfo_getFlowSchemaRejection(t *testing.T) {
+	clusterResponse := &proxyv1alpha1.RejectionResponse{Message: "cluster"}
+	schemaResponse := &proxyv1alpha1.RejectionResponse{Message: "schema", Headers: map[string]string{"X-Quota-Link": "https://quota"}}
+	clusterInfo := NewEmptyClusterInfo("test", nil, nil)
+	//nolint:errcheck
+	clusterInfo.updateSnapshot(func(next *clusterSnapshot) error {
+		next.flowControlSpec = proxyv1alpha1.FlowControl{
+			Schemas: []proxyv1alpha1.FlowControlSchema{
+				{Name: "custom", RejectionStatusCode: 503, RejectionResponse: schemaResponse},
+				{Name: "default"},
+			},
+			RejectionResponse: clusterResponse,
+		}
+		return nil
+	})
+	snapshot := clusterInfo.loadSnapshot()
+
+	tests := []struct {
+		schema       string
+		wantCode     int32
+		wantResponse *proxyv1alpha1.RejectionResponse
+	}{
+		{"custom", 503, schemaResponse},
+		{"default", 0, clusterResponse},
+		{"", 0, clusterResponse},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.schema, func(t *testing.T) {
+			code, response := snapshot.getFlowSchemaRejection(tt.schema)
+			if code != tt.wantCode || response != tt.wantResponse {
+				t.Errorf("getFlowSchemaRejection() = %v, %v, want %v, %v", code, response, tt.wantCode, tt.wantResponse)
+			}
+		})
+	}
+}
+
 func Test_isLogEnabled(t *testing.T) {
 	tests := []struct {
 		name     string
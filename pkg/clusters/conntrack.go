@@ -0,0 +1,59 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusters
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"github.com/kubewharf/kubegateway/pkg/gateway/metrics"
+)
+
+type dialFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// trackConnections wraps the dial func to track open upstream connections of the cluster.
+//
+// Transports of endpoints of a cluster share a dialer, so endpoint is resolved from the
+// dialed address.
+func trackConnections(cluster, scheme string, dial dialFunc) dialFunc {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		conn, err := dial(ctx, network, address)
+		if err != nil {
+			return nil, err
+		}
+		endpoint := scheme + "://" + address
+		metrics.RecordUpstreamConnectionOpened(cluster, endpoint)
+		return &trackedConn{
+			Conn: conn,
+			onClose: func() {
+				metrics.RecordUpstreamConnectionClosed(cluster, endpoint)
+			},
+		}, nil
+	}
+}
+
+// trackedConn calls onClose only once after the connection is closed
+type trackedConn struct {
+	net.Conn
+	once    sync.Once
+	onClose func()
+}
+
+func (c *trackedConn) Close() error {
+	err := c.Conn.Close()
+	c.once.Do(c.onClose)
+	return err
+}
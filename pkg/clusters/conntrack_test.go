@@ -0,0 +1,84 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusters
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/kubewharf/kubegateway/pkg/gateway/metrics"
+)
+
+func upstreamConnections(t *testing.T, registry *prometheus.Registry, cluster, endpoint string) float64 {
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	for _, mf := range families {
+		if mf.GetName() != "kubegateway_upstream_connections" {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			labels := map[string]string{}
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			if labels["cluster"] == cluster && labels["endpoint"] == endpoint {
+				return m.GetGauge().GetValue()
+			}
+		}
+	}
+	return 0
+}
+
+func Test_trackConnections(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	if err := metrics.RegisterTo(registry); err != nil {
+		t.Fatalf("RegisterTo() error = %v", err)
+	}
+
+	dial := trackConnections("conntrack.cluster", "https", func(ctx context.Context, network, address string) (net.Conn, error) {
+		client, _ := net.Pipe()
+		return client, nil
+	})
+
+	var conns []net.Conn
+	defer func() {
+		// the gauge outlives the test, it starts from zero when the test is run again
+		for _, conn := range conns {
+			conn.Close()
+		}
+	}()
+	for i := 0; i < 3; i++ {
+		conn, err := dial(context.TODO(), "tcp", "127.0.0.1:443")
+		if err != nil {
+			t.Fatalf("dial error = %v", err)
+		}
+		conns = append(conns, conn)
+	}
+	if got := upstreamConnections(t, registry, "conntrack.cluster", "https://127.0.0.1:443"); got != 3 {
+		t.Errorf("upstream connections = %v, want 3", got)
+	}
+
+	// closing a connection twice only decreases the gauge once
+	conns[0].Close()
+	conns[0].Close()
+	if got := upstreamConnections(t, registry, "conntrack.cluster", "https://127.0.0.1:443"); got != 2 {
+		t.Errorf("upstream connections = %v, want 2", got)
+	}
+}
@@ -19,11 +19,13 @@ import (
 	"fmt"
 	"net/http"
 	"sync"
+	"sync/atomic"
 
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog"
 
+	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
 	"github.com/kubewharf/kubegateway/pkg/gateway/metrics"
 )
 
@@ -54,7 +56,32 @@ type EndpointInfo struct {
 
 	clientset kubernetes.Interface
 
-	status endpointStatus
+	// statusLock guards status, it is written by health checks and sync while requests read it
+	statusLock sync.RWMutex
+	status     endpointStatus
+	// healthCheckPolicy returns the health check policy of the cluster
+	healthCheckPolicy func() *proxyv1alpha1.HealthCheckPolicy
+	// healthChecks holds results of recent health checks
+	healthChecks healthCheckWindow
+	// consecutiveHealthChecks counts consecutive health check results since the last transition
+	consecutiveHealthChecks consecutiveHealthChecks
+	// healthCheckBackoff is the interval in nanoseconds between health checks while the endpoint is unhealthy
+	healthCheckBackoff int64
+	// healthCheckLatency is the moving average in nanoseconds of latency of succeeded health checks
+	healthCheckLatency int64
+
+	// inflight is the number of in-flight requests dispatched to the endpoint
+	inflight int64
+
+	// outlierDetectionPolicy returns the outlier detection policy of the cluster
+	outlierDetectionPolicy func() *proxyv1alpha1.OutlierDetectionPolicy
+	// outlier ejects the endpoint after consecutive failures of proxied requests
+	outlier outlierDetector
+
+	// healthHistoryLock guards healthHistory
+	healthHistoryLock sync.Mutex
+	// healthHistory holds the last maxHealthTransitions status transitions, oldest first
+	healthHistory []HealthTransition
 }
 
 func (e *EndpointInfo) Context() context.Context {
@@ -66,42 +93,81 @@ func (e *EndpointInfo) Clientset() kubernetes.Interface {
 }
 
 func (e *EndpointInfo) SetDisabled(disabled bool) {
+	e.statusLock.Lock()
+	defer e.statusLock.Unlock()
 	if e.status.Disabled != disabled {
+		from := e.status
 		e.status.Disabled = disabled
-		e.recordStatusChange()
+		e.recordStatusChangeLocked(from)
 	}
 }
 
+// IsDisabled returns true if the endpoint is disabled in spec, no requests are routed to it
+func (e *EndpointInfo) IsDisabled() bool {
+	return e.loadStatus().Disabled
+}
+
 func (e *EndpointInfo) UpdateStatus(healthy bool, reason, message string) {
 	if !healthy {
 		metrics.RecordUnhealthyUpstream(e.Cluster, e.Endpoint, reason)
 	}
+	e.statusLock.Lock()
+	defer e.statusLock.Unlock()
 	if e.status.Healthy != healthy {
 		// healthy changed
+		from := e.status
 		e.status.Healthy = healthy
 		e.status.Reason = reason
 		e.status.Message = message
-		e.recordStatusChange()
+		e.recordStatusChangeLocked(from)
 	}
 }
 
-func (e *EndpointInfo) recordStatusChange() {
+// loadStatus returns a copy of the status, so that its fields are read consistently
+func (e *EndpointInfo) loadStatus() endpointStatus {
+	e.statusLock.RLock()
+	defer e.statusLock.RUnlock()
+	return e.status
+}
+
+// recordStatusChangeLocked records a status change in the health history, transitions are
+// recorded in the order of status changes as the status lock is held.
+func (e *EndpointInfo) recordStatusChangeLocked(from endpointStatus) {
 	klog.V(1).Infof(
 		"[endpoint info] endpoint status changed, cluster=%q, endpoint=%q, disabled=%v, healthy=%v, reason=%q, message=%q",
 		e.Cluster, e.Endpoint, e.status.Disabled, e.status.Healthy, e.status.Reason, e.status.Message,
 	)
+	e.recordHealthTransition(from.state(), e.status)
+}
+
+// TrackRequest counts a request dispatched to the endpoint as in-flight until the returned
+// done func is called, done is idempotent so that it can be deferred and called early.
+func (e *EndpointInfo) TrackRequest() (done func()) {
+	atomic.AddInt64(&e.inflight, 1)
+	var finished int32
+	return func() {
+		if atomic.CompareAndSwapInt32(&finished, 0, 1) {
+			atomic.AddInt64(&e.inflight, -1)
+		}
+	}
+}
+
+// Inflight returns the number of in-flight requests dispatched to the endpoint
+func (e *EndpointInfo) Inflight() int64 {
+	return atomic.LoadInt64(&e.inflight)
 }
 
 func (e *EndpointInfo) IsReady() bool {
-	return e.status.IsReady()
+	return e.loadStatus().IsReady()
 }
 
 func (e *EndpointInfo) UnreadyReason() string {
+	status := e.loadStatus()
 	message := ""
-	if e.status.Disabled {
+	if status.Disabled {
 		message = fmt.Sprintf("endpoint=%q is disabled.", e.Endpoint)
-	} else if !e.status.Healthy {
-		message = fmt.Sprintf("endpoint=%q is unhealthy, reason=%q, message=%q.", e.Endpoint, e.status.Reason, e.status.Message)
+	} else if !status.Healthy {
+		message = fmt.Sprintf("endpoint=%q is unhealthy, reason=%q, message=%q.", e.Endpoint, status.Reason, status.Message)
 	}
 	return message
 }
@@ -71,3 +71,28 @@ func TestEndpointInfo_ReadyAndReason(t *testing.T) {
 		})
 	}
 }
+
+func TestEndpointInfo_TrackRequest(t *testing.T) {
+	e := &EndpointInfo{}
+	first := e.TrackRequest()
+	second := e.TrackRequest()
+	if got := e.Inflight(); got != 2 {
+		t.Errorf("EndpointInfo.Inflight() = %v, want 2", got)
+	}
+	first()
+	first()
+	if got := e.Inflight(); got != 1 {
+		t.Errorf("EndpointInfo.Inflight() after calling done twice = %v, want 1", got)
+	}
+
+	func() {
+		defer func() {
+			_ = recover()
+		}()
+		defer second()
+		panic("proxy panics")
+	}()
+	if got := e.Inflight(); got != 0 {
+		t.Errorf("EndpointInfo.Inflight() after panic = %v, want 0", got)
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusters
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/klog"
+
+	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
+)
+
+// EphemeralEndpoint is an endpoint added to a running cluster at runtime instead of the
+// UpstreamCluster spec, e.g. an extra apiserver for emergency capacity during incidents.
+type EphemeralEndpoint struct {
+	Endpoint string    `json:"endpoint"`
+	AddedBy  string    `json:"addedBy,omitempty"`
+	AddedAt  time.Time `json:"addedAt"`
+}
+
+// syncSourceEndpointsLocked syncs endpoints in next snapshot with servers in spec and ephemeral endpoints.
+func (c *ClusterInfo) syncSourceEndpointsLocked(next *clusterSnapshot, servers []proxyv1alpha1.UpstreamClusterServer) error {
+	c.endpointsLock.Lock()
+	defer c.endpointsLock.Unlock()
+	if len(c.ephemeralEndpoints) > 0 && c.config.ReconcileEphemeralEndpoints &&
+		!apiequality.Semantic.DeepEqual(c.sourceServers, servers) {
+		for _, e := range c.ephemeralEndpoints {
+			klog.Infof("[ephemeral endpoint] cluster=%q servers in spec changed, remove ephemeral endpoint=%q", c.Cluster, e.Endpoint)
+		}
+		c.ephemeralEndpoints = nil
+	}
+	c.sourceServers = append([]proxyv1alpha1.UpstreamClusterServer(nil), servers...)
+	return c.syncEndpointsLocked(next, c.serversWithEphemeralLocked())
+}
+
+// serversWithEphemeralLocked returns servers in spec followed by ephemeral endpoints, ephemeral
+// endpoints which are added to spec are no longer ephemeral.
+func (c *ClusterInfo) serversWithEphemeralLocked() []proxyv1alpha1.UpstreamClusterServer {
+	servers := append([]proxyv1alpha1.UpstreamClusterServer(nil), c.sourceServers...)
+	var ephemeral []EphemeralEndpoint
+	for _, e := range c.ephemeralEndpoints {
+		if c.isSourceServerLocked(e.Endpoint) {
+			klog.Infof("[ephemeral endpoint] cluster=%q endpoint=%q is added to spec, it is no longer ephemeral", c.Cluster, e.Endpoint)
+			continue
+		}
+		ephemeral = append(ephemeral, e)
+		servers = append(servers, proxyv1alpha1.UpstreamClusterServer{Endpoint: e.Endpoint})
+	}
+	c.ephemeralEndpoints = ephemeral
+	return servers
+}
+
+func (c *ClusterInfo) isSourceServerLocked(endpoint string) bool {
+	for _, server := range c.sourceServers {
+		if server.Endpoint == endpoint {
+			return true
+		}
+	}
+	return false
+}
+
+// AddEphemeralEndpoint adds an endpoint to this cluster at runtime, it is health checked and
+// dispatched to like endpoints in spec, except by dispatch policies with an upstream subset.
+// Ephemeral endpoints live in memory only and are lost after restarting.
+func (c *ClusterInfo) AddEphemeralEndpoint(endpoint, addedBy string) (EphemeralEndpoint, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || len(u.Host) == 0 {
+		return EphemeralEndpoint{}, fmt.Errorf("invalid endpoint %q, it must be like https://host:port", endpoint)
+	}
+
+	var added EphemeralEndpoint
+	err = c.updateSnapshot(func(next *clusterSnapshot) error {
+		c.endpointsLock.Lock()
+		defer c.endpointsLock.Unlock()
+		if len(c.sourceServers) > 0 {
+			if source, err := url.Parse(c.sourceServers[0].Endpoint); err == nil && source.Scheme != u.Scheme {
+				return fmt.Errorf("endpoint %q must use the same scheme %q as servers in spec", endpoint, source.Scheme)
+			}
+		}
+		if c.isSourceServerLocked(endpoint) {
+			return fmt.Errorf("endpoint %q is already in spec of cluster %q", endpoint, c.Cluster)
+		}
+		for _, e := range c.ephemeralEndpoints {
+			if e.Endpoint == endpoint {
+				return fmt.Errorf("ephemeral endpoint %q already exists in cluster %q", endpoint, c.Cluster)
+			}
+		}
+
+		added = EphemeralEndpoint{Endpoint: endpoint, AddedBy: addedBy, AddedAt: time.Now()}
+		c.ephemeralEndpoints = append(c.ephemeralEndpoints, added)
+		klog.Infof("[ephemeral endpoint] cluster=%q endpoint=%q is added by user=%q", c.Cluster, endpoint, addedBy)
+		return c.syncEndpointsLocked(next, c.serversWithEphemeralLocked())
+	})
+	return added, err
+}
+
+// DeleteEphemeralEndpoint removes an ephemeral endpoint, it returns false if the endpoint is not
+// an ephemeral endpoint of this cluster.
+func (c *ClusterInfo) DeleteEphemeralEndpoint(endpoint, deletedBy string) (bool, error) {
+	deleted := false
+	err := c.updateSnapshot(func(next *clusterSnapshot) error {
+		c.endpointsLock.Lock()
+		defer c.endpointsLock.Unlock()
+		var ephemeral []EphemeralEndpoint
+		for _, e := range c.ephemeralEndpoints {
+			if e.Endpoint != endpoint {
+				ephemeral = append(ephemeral, e)
+			}
+		}
+		if len(ephemeral) == len(c.ephemeralEndpoints) {
+			return nil
+		}
+		c.ephemeralEndpoints = ephemeral
+		deleted = true
+		klog.Infof("[ephemeral endpoint] cluster=%q endpoint=%q is removed by user=%q", c.Cluster, endpoint, deletedBy)
+		return c.syncEndpointsLocked(next, c.serversWithEphemeralLocked())
+	})
+	return deleted, err
+}
+
+// EphemeralEndpoints returns ephemeral endpoints of this cluster sorted by endpoint
+func (c *ClusterInfo) EphemeralEndpoints() []EphemeralEndpoint {
+	c.endpointsLock.Lock()
+	defer c.endpointsLock.Unlock()
+	ret := append([]EphemeralEndpoint{}, c.ephemeralEndpoints...)
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Endpoint < ret[j].Endpoint
+	})
+	return ret
+}
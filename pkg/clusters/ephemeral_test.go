@@ -0,0 +1,107 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusters
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
+)
+
+func TestClusterInfo_EphemeralEndpoints(t *testing.T) {
+	tests := []struct {
+		name string
+		// reconcile ephemeral endpoints when servers in spec change
+		reconcile bool
+		// servers in spec synced after the ephemeral endpoint is added
+		servers       []string
+		wantEndpoints []string
+		wantEphemeral int
+	}{
+		{
+			name:          "kept when spec is not changed",
+			servers:       []string{"https://127.0.0.1:443"},
+			wantEndpoints: []string{"https://127.0.0.1:443", "https://127.0.0.2:443"},
+			wantEphemeral: 1,
+		},
+		{
+			name:          "kept when spec changes",
+			servers:       []string{"https://127.0.0.1:443", "https://127.0.0.3:443"},
+			wantEndpoints: []string{"https://127.0.0.1:443", "https://127.0.0.2:443", "https://127.0.0.3:443"},
+			wantEphemeral: 1,
+		},
+		{
+			name:          "reconciled when spec changes",
+			reconcile:     true,
+			servers:       []string{"https://127.0.0.1:443", "https://127.0.0.3:443"},
+			wantEndpoints: []string{"https://127.0.0.1:443", "https://127.0.0.3:443"},
+			wantEphemeral: 0,
+		},
+		{
+			name:          "added to spec",
+			servers:       []string{"https://127.0.0.1:443", "https://127.0.0.2:443"},
+			wantEndpoints: []string{"https://127.0.0.1:443", "https://127.0.0.2:443"},
+			wantEphemeral: 0,
+		},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := newTestUpstreamClusterConfig()
+			clusterInfo, err := CreateClusterInfoWithConfig(cluster, func(*EndpointInfo) bool { return true },
+				ClusterInfoConfig{ReconcileEphemeralEndpoints: tt.reconcile})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer clusterInfo.Stop()
+
+			if _, err := clusterInfo.AddEphemeralEndpoint("http://127.0.0.2:80", "admin"); err == nil {
+				t.Errorf("AddEphemeralEndpoint() with a different scheme should fail")
+			}
+			if _, err := clusterInfo.AddEphemeralEndpoint("https://127.0.0.1:443", "admin"); err == nil {
+				t.Errorf("AddEphemeralEndpoint() with an endpoint in spec should fail")
+			}
+			if _, err := clusterInfo.AddEphemeralEndpoint("https://127.0.0.2:443", "admin"); err != nil {
+				t.Fatalf("AddEphemeralEndpoint() error = %v", err)
+			}
+			if _, ok := clusterInfo.Endpoints.Load("https://127.0.0.2:443"); !ok {
+				t.Fatalf("ephemeral endpoint is not added")
+			}
+
+			cluster.Spec.Servers = nil
+			for _, server := range tt.servers {
+				cluster.Spec.Servers = append(cluster.Spec.Servers, proxyv1alpha1.UpstreamClusterServer{Endpoint: server})
+			}
+			if err := clusterInfo.Sync(cluster); err != nil {
+				t.Fatal(err)
+			}
+			got := clusterInfo.AllEndpoints()
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.wantEndpoints) {
+				t.Errorf("AllEndpoints() = %v, want %v", got, tt.wantEndpoints)
+			}
+			if got := clusterInfo.EphemeralEndpoints(); len(got) != tt.wantEphemeral {
+				t.Errorf("EphemeralEndpoints() = %v, want %d endpoints", got, tt.wantEphemeral)
+			}
+
+			deleted, err := clusterInfo.DeleteEphemeralEndpoint("https://127.0.0.2:443", "admin")
+			if err != nil || deleted != (tt.wantEphemeral > 0) {
+				t.Errorf("DeleteEphemeralEndpoint() = %v, %v, want %v", deleted, err, tt.wantEphemeral > 0)
+			}
+		})
+	}
+}
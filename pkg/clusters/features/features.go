@@ -27,6 +27,9 @@ const (
 
 	// Deny all reqeusts and make cluster temporary down
 	DenyAllRequests featuregate.Feature = "DenyAllRequests"
+
+	// Do not send GOAWAY probabilistically to HTTP/2 clients of this cluster
+	DisableProbabilisticGoaway featuregate.Feature = "DisableProbabilisticGoaway"
 )
 
 var (
@@ -48,8 +51,9 @@ var (
 	// defaultFeatureGates consists of all known feature keys.
 	// To add a new feature, define a key for it above and add it here.
 	defaultFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
-		CloseConnectionWhenIdle: {Default: false, PreRelease: featuregate.Alpha},
-		DenyAllRequests:         {Default: false, PreRelease: featuregate.Alpha},
+		CloseConnectionWhenIdle:    {Default: false, PreRelease: featuregate.Alpha},
+		DenyAllRequests:            {Default: false, PreRelease: featuregate.Alpha},
+		DisableProbabilisticGoaway: {Default: false, PreRelease: featuregate.Alpha},
 	}
 
 	defaultKnownFeatures []string
@@ -0,0 +1,238 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusters
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog"
+
+	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
+)
+
+const (
+	defaultHealthCheckInterval = 5 * time.Second
+	defaultHealthCheckTimeout  = 5 * time.Second
+	defaultUnhealthyThreshold  = 3
+	defaultHealthyThreshold    = 1
+	// defaultMaxHealthCheckBackoff caps the interval between health checks of an unhealthy
+	// endpoint if it is not configured, unless the configured interval is longer
+	defaultMaxHealthCheckBackoff = 30 * time.Second
+	// healthCheckBackoffJitter spreads health checks of endpoints sharing a backend
+	healthCheckBackoffJitter = 0.2
+	// healthCheckLatencySmoothing is the weight of the latest sample in the moving average
+	// of health check latency
+	healthCheckLatencySmoothing = 0.3
+)
+
+// healthCheckWindow holds results of recent health checks of an endpoint
+type healthCheckWindow struct {
+	lock sync.Mutex
+	// results of health checks, oldest first
+	results []bool
+}
+
+// record appends a result to the window of size, and returns the number of succeeded health
+// checks and all health checks in the window.
+func (w *healthCheckWindow) record(succeeded bool, size int) (int, int) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.results = append(w.results, succeeded)
+	if len(w.results) > size {
+		// the window may be shrunk by a new policy
+		w.results = append(w.results[:0], w.results[len(w.results)-size:]...)
+	}
+	successes := 0
+	for _, r := range w.results {
+		if r {
+			successes++
+		}
+	}
+	return successes, len(w.results)
+}
+
+// consecutiveHealthChecks counts consecutive succeeded and failed health checks of an endpoint
+type consecutiveHealthChecks struct {
+	lock      sync.Mutex
+	successes int
+	failures  int
+}
+
+// record counts a result, and returns the number of consecutive succeeded and failed health checks
+func (c *consecutiveHealthChecks) record(succeeded bool) (int, int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if succeeded {
+		c.successes++
+		c.failures = 0
+	} else {
+		c.failures++
+		c.successes = 0
+	}
+	return c.successes, c.failures
+}
+
+func (c *consecutiveHealthChecks) reset() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.successes, c.failures = 0, 0
+}
+
+// RecordHealthCheck records the result of a health check and updates the status by the health
+// check policy of the cluster. Without a success rate window, the status changes after the
+// number of consecutive health checks with the opposite result reaches the threshold.
+func (e *EndpointInfo) RecordHealthCheck(succeeded bool, reason, message string) {
+	policy := e.loadHealthCheckPolicy()
+	if policy == nil || policy.SuccessRateWindow == 0 {
+		e.recordConsecutiveHealthCheck(policy, succeeded, reason, message)
+		return
+	}
+
+	successes, total := e.healthChecks.record(succeeded, int(policy.SuccessRateWindow))
+	healthy := successes*100 >= int(policy.MinSuccessPercent)*total
+	if healthy != succeeded {
+		// the endpoint stays healthy after a failure or unhealthy after a success, the reason
+		// and message of an unhealthy endpoint are of the failure making it unhealthy
+		klog.V(2).Infof("[endpoint info] cluster=%q endpoint=%q health check succeeded=%v, reason=%q, %d of last %d health checks succeeded, healthy=%v",
+			e.Cluster, e.Endpoint, succeeded, reason, successes, total, healthy)
+	}
+	e.UpdateStatus(healthy, reason, message)
+}
+
+func (e *EndpointInfo) recordConsecutiveHealthCheck(policy *proxyv1alpha1.HealthCheckPolicy, succeeded bool, reason, message string) {
+	unhealthyThreshold, healthyThreshold := healthCheckThresholds(policy)
+	successes, failures := e.consecutiveHealthChecks.record(succeeded)
+
+	wasHealthy := e.loadStatus().Healthy
+	healthy := wasHealthy
+	switch {
+	case succeeded && !healthy && successes >= healthyThreshold:
+		healthy = true
+	case !succeeded && healthy && failures >= unhealthyThreshold:
+		healthy = false
+	}
+	if healthy != succeeded {
+		klog.V(2).Infof("[endpoint info] cluster=%q endpoint=%q health check succeeded=%v, reason=%q, %d consecutive successes and %d consecutive failures, healthy=%v",
+			e.Cluster, e.Endpoint, succeeded, reason, successes, failures, healthy)
+	}
+	if healthy != wasHealthy {
+		// counting starts over for the next transition
+		e.consecutiveHealthChecks.reset()
+	}
+	e.UpdateStatus(healthy, reason, message)
+}
+
+// healthCheckThresholds returns the number of consecutive health checks for an endpoint to become
+// unhealthy and healthy
+func healthCheckThresholds(policy *proxyv1alpha1.HealthCheckPolicy) (int, int) {
+	unhealthy, healthy := defaultUnhealthyThreshold, defaultHealthyThreshold
+	if policy != nil && policy.UnhealthyThreshold > 0 {
+		unhealthy = int(policy.UnhealthyThreshold)
+	}
+	if policy != nil && policy.HealthyThreshold > 0 {
+		healthy = int(policy.HealthyThreshold)
+	}
+	return unhealthy, healthy
+}
+
+// HealthCheckTimeout returns the timeout of health check requests to the endpoint
+func (e *EndpointInfo) HealthCheckTimeout() time.Duration {
+	if policy := e.loadHealthCheckPolicy(); policy != nil && policy.TimeoutMilliseconds > 0 {
+		return time.Duration(policy.TimeoutMilliseconds) * time.Millisecond
+	}
+	return defaultHealthCheckTimeout
+}
+
+// HealthCheckPath returns the path of health check requests to the endpoint and whether
+// /healthz is requested instead if /readyz is not found. The path is empty if it is not
+// configured, the health checker decides the default one.
+func (e *EndpointInfo) HealthCheckPath() (string, bool) {
+	policy := e.loadHealthCheckPolicy()
+	if policy == nil {
+		return "", false
+	}
+	return policy.Path, policy.FallbackToHealthz
+}
+
+func (e *EndpointInfo) loadHealthCheckPolicy() *proxyv1alpha1.HealthCheckPolicy {
+	if e.healthCheckPolicy == nil {
+		return nil
+	}
+	return e.healthCheckPolicy()
+}
+
+// nextHealthCheckInterval returns how long to wait before the next health check of the endpoint.
+// The interval of the policy is used while the endpoint is healthy, it backs off exponentially
+// with jitter while the endpoint is unhealthy, and is reset once the endpoint recovers.
+func (e *EndpointInfo) nextHealthCheckInterval(policy *proxyv1alpha1.HealthCheckPolicy) time.Duration {
+	interval := healthCheckInterval(policy)
+	if e.loadStatus().Healthy {
+		atomic.StoreInt64(&e.healthCheckBackoff, 0)
+		return interval
+	}
+	backoff := time.Duration(atomic.LoadInt64(&e.healthCheckBackoff)) * 2
+	if backoff < interval {
+		backoff = interval
+	}
+	limit := defaultMaxHealthCheckBackoff
+	if policy != nil && policy.MaxBackoffMilliseconds > 0 {
+		limit = time.Duration(policy.MaxBackoffMilliseconds) * time.Millisecond
+	}
+	if interval > limit {
+		limit = interval
+	}
+	if backoff > limit {
+		backoff = limit
+	}
+	atomic.StoreInt64(&e.healthCheckBackoff, int64(backoff))
+	return wait.Jitter(backoff, healthCheckBackoffJitter)
+}
+
+// HealthCheckBackoff returns the current interval between health checks of an unhealthy endpoint
+// before jitter, it is zero if the endpoint is not backing off.
+func (e *EndpointInfo) HealthCheckBackoff() time.Duration {
+	return time.Duration(atomic.LoadInt64(&e.healthCheckBackoff))
+}
+
+// healthCheckInterval returns the interval between health checks of the policy
+func healthCheckInterval(policy *proxyv1alpha1.HealthCheckPolicy) time.Duration {
+	if policy != nil && policy.IntervalMilliseconds > 0 {
+		return time.Duration(policy.IntervalMilliseconds) * time.Millisecond
+	}
+	return defaultHealthCheckInterval
+}
+
+// RecordHealthCheckLatency records the round-trip time of a succeeded health check into the
+// exponentially weighted moving average of health check latency.
+func (e *EndpointInfo) RecordHealthCheckLatency(latency time.Duration) {
+	if latency <= 0 {
+		return
+	}
+	// only the health checker of the endpoint writes it
+	average := time.Duration(atomic.LoadInt64(&e.healthCheckLatency))
+	if average > 0 {
+		latency = time.Duration(healthCheckLatencySmoothing*float64(latency) + (1-healthCheckLatencySmoothing)*float64(average))
+	}
+	atomic.StoreInt64(&e.healthCheckLatency, int64(latency))
+}
+
+// HealthCheckLatency returns the moving average of health check latency, it is zero if no
+// health check has succeeded.
+func (e *EndpointInfo) HealthCheckLatency() time.Duration {
+	return time.Duration(atomic.LoadInt64(&e.healthCheckLatency))
+}
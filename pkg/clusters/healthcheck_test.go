@@ -0,0 +1,313 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusters
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
+)
+
+func TestEndpointInfo_RecordHealthCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     *proxyv1alpha1.HealthCheckPolicy
+		results    []bool
+		want       []bool
+		wantReason string
+	}{
+		{
+			"no policy",
+			nil,
+			[]bool{true, false, false, false, true},
+			[]bool{true, true, true, false, true},
+			"",
+		},
+		{
+			"no window",
+			&proxyv1alpha1.HealthCheckPolicy{},
+			[]bool{true, false, false, false, true},
+			[]bool{true, true, true, false, true},
+			"",
+		},
+		{
+			"alternating results do not reach unhealthy threshold",
+			&proxyv1alpha1.HealthCheckPolicy{UnhealthyThreshold: 2},
+			[]bool{true, false, true, false, true, false},
+			[]bool{true, true, true, true, true, true},
+			"",
+		},
+		{
+			"consecutive failures reach unhealthy threshold",
+			&proxyv1alpha1.HealthCheckPolicy{UnhealthyThreshold: 2},
+			[]bool{true, false, true, false, false},
+			[]bool{true, true, true, true, false},
+			"Failure",
+		},
+		{
+			"alternating results do not reach healthy threshold",
+			&proxyv1alpha1.HealthCheckPolicy{UnhealthyThreshold: 1, HealthyThreshold: 2},
+			[]bool{true, true, false, true, false, true},
+			[]bool{false, true, false, false, false, false},
+			"Failure",
+		},
+		{
+			"counters are reset on transition",
+			&proxyv1alpha1.HealthCheckPolicy{UnhealthyThreshold: 2, HealthyThreshold: 2},
+			[]bool{true, true, false, false, true, false, false, true, true},
+			[]bool{false, true, true, false, false, false, false, false, true},
+			"",
+		},
+		{
+			"failures are tolerated",
+			&proxyv1alpha1.HealthCheckPolicy{SuccessRateWindow: 5, MinSuccessPercent: 60},
+			[]bool{true, true, false, true, false},
+			[]bool{true, true, true, true, true},
+			"",
+		},
+		{
+			"success rate is too low",
+			&proxyv1alpha1.HealthCheckPolicy{SuccessRateWindow: 5, MinSuccessPercent: 60},
+			[]bool{true, false, false, true},
+			[]bool{true, false, false, false},
+			"Failure",
+		},
+		{
+			"old results slide out of the window",
+			&proxyv1alpha1.HealthCheckPolicy{SuccessRateWindow: 3, MinSuccessPercent: 60},
+			[]bool{false, false, true, true},
+			[]bool{false, false, false, true},
+			"",
+		},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			e := &EndpointInfo{
+				Cluster:  "test",
+				Endpoint: "https://127.0.0.1:443",
+				healthCheckPolicy: func() *proxyv1alpha1.HealthCheckPolicy {
+					return tt.policy
+				},
+			}
+			got := []bool{}
+			for _, succeeded := range tt.results {
+				reason := ""
+				if !succeeded {
+					reason = "Failure"
+				}
+				e.RecordHealthCheck(succeeded, reason, "")
+				got = append(got, e.IsReady())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RecordHealthCheck() ready = %v, want %v", got, tt.want)
+			}
+			if e.IsReady() {
+				return
+			}
+			if e.status.Reason != tt.wantReason {
+				t.Errorf("RecordHealthCheck() reason = %q, want %q", e.status.Reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestEndpointInfo_HealthCheckTimeout(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy *proxyv1alpha1.HealthCheckPolicy
+		want   time.Duration
+	}{
+		{"no policy", nil, 5 * time.Second},
+		{"not set", &proxyv1alpha1.HealthCheckPolicy{}, 5 * time.Second},
+		{"sub-second", &proxyv1alpha1.HealthCheckPolicy{TimeoutMilliseconds: 500}, 500 * time.Millisecond},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			e := &EndpointInfo{
+				healthCheckPolicy: func() *proxyv1alpha1.HealthCheckPolicy {
+					return tt.policy
+				},
+			}
+			if got := e.HealthCheckTimeout(); got != tt.want {
+				t.Errorf("EndpointInfo.HealthCheckTimeout() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_healthCheckInterval(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy *proxyv1alpha1.HealthCheckPolicy
+		want   time.Duration
+	}{
+		{"no policy", nil, 5 * time.Second},
+		{"not set", &proxyv1alpha1.HealthCheckPolicy{}, 5 * time.Second},
+		{"backoff", &proxyv1alpha1.HealthCheckPolicy{IntervalMilliseconds: 30000}, 30 * time.Second},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			if got := healthCheckInterval(tt.policy); got != tt.want {
+				t.Errorf("healthCheckInterval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEndpointInfo_HealthCheckPath(t *testing.T) {
+	tests := []struct {
+		name         string
+		policy       *proxyv1alpha1.HealthCheckPolicy
+		wantPath     string
+		wantFallback bool
+	}{
+		{"no policy", nil, "", false},
+		{"not set", &proxyv1alpha1.HealthCheckPolicy{}, "", false},
+		{"livez", &proxyv1alpha1.HealthCheckPolicy{Path: "/livez"}, "/livez", false},
+		{"readyz with fallback", &proxyv1alpha1.HealthCheckPolicy{Path: "/readyz", FallbackToHealthz: true}, "/readyz", true},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			e := &EndpointInfo{
+				healthCheckPolicy: func() *proxyv1alpha1.HealthCheckPolicy {
+					return tt.policy
+				},
+			}
+			path, fallback := e.HealthCheckPath()
+			if path != tt.wantPath || fallback != tt.wantFallback {
+				t.Errorf("EndpointInfo.HealthCheckPath() = (%v, %v), want (%v, %v)", path, fallback, tt.wantPath, tt.wantFallback)
+			}
+		})
+	}
+}
+
+func TestEndpointInfo_nextHealthCheckInterval(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  *proxyv1alpha1.HealthCheckPolicy
+		healthy []bool
+		want    []time.Duration
+	}{
+		{
+			"healthy",
+			nil,
+			[]bool{true, true},
+			[]time.Duration{5 * time.Second, 5 * time.Second},
+		},
+		{
+			"backs off until cap and resets once recovered",
+			nil,
+			[]bool{false, false, false, false, false, true, false},
+			[]time.Duration{5 * time.Second, 10 * time.Second, 20 * time.Second, 30 * time.Second, 30 * time.Second, 5 * time.Second, 5 * time.Second},
+		},
+		{
+			"interval longer than cap",
+			&proxyv1alpha1.HealthCheckPolicy{IntervalMilliseconds: 60000},
+			[]bool{false, false},
+			[]time.Duration{time.Minute, time.Minute},
+		},
+		{
+			"configured cap",
+			&proxyv1alpha1.HealthCheckPolicy{MaxBackoffMilliseconds: 120000},
+			[]bool{false, false, false, false, false, false},
+			[]time.Duration{5 * time.Second, 10 * time.Second, 20 * time.Second, 40 * time.Second, 80 * time.Second, 120 * time.Second},
+		},
+		{
+			"configured cap shorter than interval",
+			&proxyv1alpha1.HealthCheckPolicy{IntervalMilliseconds: 10000, MaxBackoffMilliseconds: 5000},
+			[]bool{false, false},
+			[]time.Duration{10 * time.Second, 10 * time.Second},
+		},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			e := &EndpointInfo{}
+			for j, healthy := range tt.healthy {
+				e.status.Healthy = healthy
+				got := e.nextHealthCheckInterval(tt.policy)
+				want := tt.want[j]
+				if healthy {
+					if got != want {
+						t.Errorf("nextHealthCheckInterval() #%d = %v, want %v", j, got, want)
+					}
+					if e.HealthCheckBackoff() != 0 {
+						t.Errorf("HealthCheckBackoff() #%d = %v, want 0", j, e.HealthCheckBackoff())
+					}
+					continue
+				}
+				if e.HealthCheckBackoff() != want {
+					t.Errorf("HealthCheckBackoff() #%d = %v, want %v", j, e.HealthCheckBackoff(), want)
+				}
+				if limit := time.Duration(float64(want) * (1 + healthCheckBackoffJitter)); got < want || got > limit {
+					t.Errorf("nextHealthCheckInterval() #%d = %v, want between %v and %v", j, got, want, limit)
+				}
+			}
+		})
+	}
+}
+
+func TestEndpointInfo_RecordHealthCheckLatency(t *testing.T) {
+	tests := []struct {
+		name    string
+		samples []time.Duration
+		want    time.Duration
+	}{
+		{"no samples", nil, 0},
+		{"first sample", []time.Duration{100 * time.Millisecond}, 100 * time.Millisecond},
+		{"moving average", []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}, 130 * time.Millisecond},
+		{"non-positive samples are ignored", []time.Duration{100 * time.Millisecond, 0}, 100 * time.Millisecond},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			e := &EndpointInfo{}
+			for _, sample := range tt.samples {
+				e.RecordHealthCheckLatency(sample)
+			}
+			if got := e.HealthCheckLatency(); got != tt.want {
+				t.Errorf("EndpointInfo.HealthCheckLatency() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_latencyWeightFactor(t *testing.T) {
+	tests := []struct {
+		name    string
+		latency time.Duration
+		fastest time.Duration
+		want    float64
+	}{
+		{"no latency", 0, 10 * time.Millisecond, 1},
+		{"fastest", 10 * time.Millisecond, 10 * time.Millisecond, 1},
+		{"slower", 40 * time.Millisecond, 10 * time.Millisecond, 0.25},
+		{"much slower", time.Second, 10 * time.Millisecond, minLatencyWeightFactor},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			if got := latencyWeightFactor(tt.latency, tt.fastest); got != tt.want {
+				t.Errorf("latencyWeightFactor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
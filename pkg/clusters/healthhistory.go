@@ -0,0 +1,144 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusters
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxHealthTransitions is the number of recent status transitions kept for each endpoint
+const maxHealthTransitions = 32
+
+const (
+	endpointStateHealthy   = "Healthy"
+	endpointStateUnhealthy = "Unhealthy"
+	endpointStateDisabled  = "Disabled"
+	// endpointStateEjected and endpointStateHalfOpen are states of healthy endpoints ejected
+	// by outlier detection
+	endpointStateEjected  = "Ejected"
+	endpointStateHalfOpen = "HalfOpen"
+)
+
+// HealthTransition is a status transition of an endpoint, e.g. from Healthy to Unhealthy,
+// it is kept in memory for post-incident analysis after logs rotate.
+type HealthTransition struct {
+	Time metav1.Time `json:"time"`
+	From string      `json:"from"`
+	To   string      `json:"to"`
+	// Reason and Message are the health check result of an Unhealthy endpoint, or why an
+	// endpoint is Ejected
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+func (s endpointStatus) state() string {
+	switch {
+	case s.Disabled:
+		return endpointStateDisabled
+	case s.Healthy:
+		return endpointStateHealthy
+	default:
+		return endpointStateUnhealthy
+	}
+}
+
+// recordHealthTransition appends a status transition to the history. Changes which keep the
+// state, e.g. health of a disabled endpoint changes, are not transitions.
+func (e *EndpointInfo) recordHealthTransition(from string, to endpointStatus) {
+	if from == to.state() {
+		return
+	}
+	transition := HealthTransition{
+		Time: metav1.Now(),
+		From: from,
+		To:   to.state(),
+	}
+	if transition.To == endpointStateUnhealthy {
+		transition.Reason = to.Reason
+		transition.Message = to.Message
+	}
+	e.appendHealthTransition(transition)
+}
+
+// appendHealthTransition appends a transition to the history, the oldest one is dropped if
+// there are more than maxHealthTransitions.
+func (e *EndpointInfo) appendHealthTransition(transition HealthTransition) {
+	e.healthHistoryLock.Lock()
+	defer e.healthHistoryLock.Unlock()
+	if len(e.healthHistory) >= maxHealthTransitions {
+		copy(e.healthHistory, e.healthHistory[1:])
+		e.healthHistory = e.healthHistory[:maxHealthTransitions-1]
+	}
+	e.healthHistory = append(e.healthHistory, transition)
+}
+
+// HealthHistory returns recent status transitions of the endpoint, oldest first
+func (e *EndpointInfo) HealthHistory() []HealthTransition {
+	e.healthHistoryLock.Lock()
+	defer e.healthHistoryLock.Unlock()
+	return append([]HealthTransition{}, e.healthHistory...)
+}
+
+// EndpointHealth is a snapshot of the status of an endpoint
+type EndpointHealth struct {
+	Endpoint string `json:"endpoint"`
+	Ready    bool   `json:"ready"`
+	State    string `json:"state"`
+	// LastTransitionTime is the time of the last status transition, it is nil if the status has never changed
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+	// LastFailureReason and LastFailureMessage are of the last health check failure making the endpoint unhealthy
+	LastFailureReason  string `json:"lastFailureReason,omitempty"`
+	LastFailureMessage string `json:"lastFailureMessage,omitempty"`
+	// HealthCheckLatency is the moving average of latency of succeeded health checks
+	HealthCheckLatency string `json:"healthCheckLatency,omitempty"`
+}
+
+// Health returns the current status of the endpoint, the last failure is looked up in the
+// health history if the endpoint is not unhealthy now.
+func (e *EndpointInfo) Health() EndpointHealth {
+	status := e.loadStatus()
+	ret := EndpointHealth{
+		Endpoint: e.Endpoint,
+		Ready:    status.IsReady(),
+		State:    status.state(),
+	}
+	if ret.State == endpointStateHealthy {
+		// a healthy endpoint may be ejected by outlier detection
+		ret.State = e.outlierState().healthState()
+	}
+	if !status.Healthy {
+		ret.LastFailureReason = status.Reason
+		ret.LastFailureMessage = status.Message
+	}
+
+	if latency := e.HealthCheckLatency(); latency > 0 {
+		ret.HealthCheckLatency = latency.String()
+	}
+
+	history := e.HealthHistory()
+	if len(history) > 0 {
+		ret.LastTransitionTime = &history[len(history)-1].Time
+	}
+	if status.Healthy {
+		for i := len(history) - 1; i >= 0; i-- {
+			if history[i].To == endpointStateUnhealthy {
+				ret.LastFailureReason = history[i].Reason
+				ret.LastFailureMessage = history[i].Message
+				break
+			}
+		}
+	}
+	return ret
+}
@@ -0,0 +1,142 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusters
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEndpointInfo_HealthHistory(t *testing.T) {
+	type change struct {
+		disabled *bool
+		healthy  bool
+		reason   string
+	}
+	disable := func(disabled bool) change { return change{disabled: &disabled} }
+	health := func(healthy bool, reason string) change { return change{healthy: healthy, reason: reason} }
+
+	tests := []struct {
+		name    string
+		changes []change
+		want    [][2]string
+		reasons []string
+	}{
+		{
+			"no changes",
+			nil,
+			[][2]string{},
+			[]string{},
+		},
+		{
+			"healthy and unhealthy",
+			[]change{health(true, ""), health(false, "Timeout"), health(false, "Failure"), health(true, "")},
+			[][2]string{{"Unhealthy", "Healthy"}, {"Healthy", "Unhealthy"}, {"Unhealthy", "Healthy"}},
+			[]string{"", "Timeout", ""},
+		},
+		{
+			"disabled",
+			[]change{health(true, ""), disable(true), health(false, "Timeout"), disable(false)},
+			[][2]string{{"Unhealthy", "Healthy"}, {"Healthy", "Disabled"}, {"Disabled", "Unhealthy"}},
+			[]string{"", "", "Timeout"},
+		},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			e := &EndpointInfo{Cluster: "test", Endpoint: "https://127.0.0.1:443"}
+			for _, c := range tt.changes {
+				if c.disabled != nil {
+					e.SetDisabled(*c.disabled)
+					continue
+				}
+				e.UpdateStatus(c.healthy, c.reason, "")
+			}
+			got := [][2]string{}
+			reasons := []string{}
+			for _, transition := range e.HealthHistory() {
+				got = append(got, [2]string{transition.From, transition.To})
+				reasons = append(reasons, transition.Reason)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("HealthHistory() transitions = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(reasons, tt.reasons) {
+				t.Errorf("HealthHistory() reasons = %v, want %v", reasons, tt.reasons)
+			}
+		})
+	}
+}
+
+func TestEndpointInfo_HealthHistory_bounded(t *testing.T) {
+	e := &EndpointInfo{Cluster: "test", Endpoint: "https://127.0.0.1:443"}
+	for i := 0; i < maxHealthTransitions+5; i++ {
+		e.UpdateStatus(i%2 == 0, "", "")
+	}
+	history := e.HealthHistory()
+	if len(history) != maxHealthTransitions {
+		t.Fatalf("len(HealthHistory()) = %v, want %v", len(history), maxHealthTransitions)
+	}
+	// the last change is to healthy
+	if last := history[len(history)-1]; last.To != endpointStateHealthy {
+		t.Errorf("the last transition is to %v, want %v", last.To, endpointStateHealthy)
+	}
+}
+
+func TestEndpointInfo_Health(t *testing.T) {
+	tests := []struct {
+		name        string
+		changes     []bool
+		disabled    bool
+		wantReady   bool
+		wantState   string
+		wantReason  string
+		wantChanged bool
+	}{
+		{"initial", nil, false, false, "Unhealthy", "", false},
+		{"healthy", []bool{true}, false, true, "Healthy", "", true},
+		{"unhealthy", []bool{true, false}, false, false, "Unhealthy", "Failure", true},
+		{"recovered", []bool{true, false, true}, false, true, "Healthy", "Failure", true},
+		{"disabled", []bool{true}, true, false, "Disabled", "", true},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			e := &EndpointInfo{Cluster: "test", Endpoint: "https://127.0.0.1:443"}
+			for _, healthy := range tt.changes {
+				reason := ""
+				if !healthy {
+					reason = "Failure"
+				}
+				e.UpdateStatus(healthy, reason, "")
+			}
+			e.SetDisabled(tt.disabled)
+
+			got := e.Health()
+			if got.Endpoint != e.Endpoint {
+				t.Errorf("Health() endpoint = %v, want %v", got.Endpoint, e.Endpoint)
+			}
+			if got.Ready != tt.wantReady || got.State != tt.wantState {
+				t.Errorf("Health() ready = %v, state = %v, want %v, %v", got.Ready, got.State, tt.wantReady, tt.wantState)
+			}
+			if got.LastFailureReason != tt.wantReason {
+				t.Errorf("Health() lastFailureReason = %v, want %v", got.LastFailureReason, tt.wantReason)
+			}
+			if changed := got.LastTransitionTime != nil; changed != tt.wantChanged {
+				t.Errorf("Health() lastTransitionTime = %v, want changed %v", got.LastTransitionTime, tt.wantChanged)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusters
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/apiserver/pkg/authentication/user"
+)
+
+// Isolation dispatches all requests of a user to a dedicated endpoint subset, it is used
+// to quarantine traffic of a noisy tenant during incidents.
+type Isolation struct {
+	User      string   `json:"user"`
+	Endpoints []string `json:"endpoints"`
+}
+
+func (c *ClusterInfo) loadIsolations() map[string][]string {
+	isolations, _ := c.isolations.Load().(map[string][]string)
+	return isolations
+}
+
+// SetIsolation isolates requests of the user onto the given endpoints of this cluster.
+// Requests of the user are dispatched to these endpoints only, and requests of other users
+// avoid them as long as other endpoints are available. Isolations live in memory only.
+func (c *ClusterInfo) SetIsolation(isolation Isolation) error {
+	if len(isolation.User) == 0 {
+		return fmt.Errorf("user must be specified")
+	}
+	if len(isolation.Endpoints) == 0 {
+		return fmt.Errorf("endpoints must be specified")
+	}
+	for _, endpoint := range isolation.Endpoints {
+		if _, ok := c.Endpoints.Load(endpoint); !ok {
+			return fmt.Errorf("endpoint %q is not found in cluster %q", endpoint, c.Cluster)
+		}
+	}
+
+	c.isolationLock.Lock()
+	defer c.isolationLock.Unlock()
+	isolations := map[string][]string{}
+	for u, endpoints := range c.loadIsolations() {
+		isolations[u] = endpoints
+	}
+	isolations[isolation.User] = append([]string(nil), isolation.Endpoints...)
+	c.isolations.Store(isolations)
+	return nil
+}
+
+// DeleteIsolation removes the isolation of the user, it returns false if the user is not isolated.
+func (c *ClusterInfo) DeleteIsolation(userName string) bool {
+	c.isolationLock.Lock()
+	defer c.isolationLock.Unlock()
+	current := c.loadIsolations()
+	if _, ok := current[userName]; !ok {
+		return false
+	}
+	isolations := map[string][]string{}
+	for u, endpoints := range current {
+		if u != userName {
+			isolations[u] = endpoints
+		}
+	}
+	c.isolations.Store(isolations)
+	return true
+}
+
+// Isolations returns all isolations sorted by user name
+func (c *ClusterInfo) Isolations() []Isolation {
+	ret := []Isolation{}
+	for u, endpoints := range c.loadIsolations() {
+		ret = append(ret, Isolation{User: u, Endpoints: endpoints})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].User < ret[j].User
+	})
+	return ret
+}
+
+// isolateUpstreams returns the isolated endpoints if the user is isolated, otherwise it returns
+// upstreams without endpoints isolated for other users. Upstreams are returned as they are if
+// all of them are isolated, isolation must not make a cluster unavailable for other users.
+func (c *ClusterInfo) isolateUpstreams(u user.Info, upstreams []string) []string {
+	isolations := c.loadIsolations()
+	if len(isolations) == 0 {
+		return upstreams
+	}
+	if u != nil {
+		if endpoints, ok := isolations[u.GetName()]; ok {
+			return endpoints
+		}
+	}
+	var ret []string
+	for _, upstream := range upstreams {
+		isolated := false
+		for _, endpoints := range isolations {
+			if containsString(endpoints, upstream) {
+				isolated = true
+				break
+			}
+		}
+		if !isolated {
+			ret = append(ret, upstream)
+		}
+	}
+	if len(ret) == 0 {
+		return upstreams
+	}
+	return ret
+}
@@ -0,0 +1,92 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusters
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+
+	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
+)
+
+func TestClusterInfo_isolation(t *testing.T) {
+	cluster := newTestUpstreamClusterConfig()
+	cluster.Spec.Servers = []proxyv1alpha1.UpstreamClusterServer{
+		{Endpoint: "https://127.0.0.1:443"},
+		{Endpoint: "https://127.0.0.2:443"},
+		{Endpoint: "https://127.0.0.3:443"},
+	}
+	clusterInfo, err := CreateClusterInfo(cluster, func(*EndpointInfo) bool { return true })
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clusterInfo.Stop()
+
+	if err := clusterInfo.SetIsolation(Isolation{User: "noisy", Endpoints: []string{"https://127.0.0.4:443"}}); err == nil {
+		t.Errorf("SetIsolation() with unknown endpoint should fail")
+	}
+	if err := clusterInfo.SetIsolation(Isolation{User: "noisy", Endpoints: []string{"https://127.0.0.3:443"}}); err != nil {
+		t.Fatalf("SetIsolation() error = %v", err)
+	}
+
+	upstreams := func(userName string) []string {
+		picker, err := clusterInfo.MatchAttributes(authorizer.AttributesRecord{
+			User:            &user.DefaultInfo{Name: userName},
+			Verb:            "list",
+			Resource:        "pods",
+			ResourceRequest: true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ret := append([]string(nil), picker.(*endpointPickStrategy).upstreams...)
+		sort.Strings(ret)
+		return ret
+	}
+
+	tests := []struct {
+		name string
+		user string
+		want []string
+	}{
+		{"isolated user", "noisy", []string{"https://127.0.0.3:443"}},
+		{"other users avoid isolated endpoints", "quiet", []string{"https://127.0.0.1:443", "https://127.0.0.2:443"}},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			if got := upstreams(tt.user); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("upstreams = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := clusterInfo.Isolations(); len(got) != 1 || got[0].User != "noisy" {
+		t.Errorf("Isolations() = %v, want isolation of noisy", got)
+	}
+	if !clusterInfo.DeleteIsolation("noisy") {
+		t.Errorf("DeleteIsolation() = false, want true")
+	}
+	if clusterInfo.DeleteIsolation("noisy") {
+		t.Errorf("DeleteIsolation() again = true, want false")
+	}
+	if got := upstreams("noisy"); len(got) != 3 {
+		t.Errorf("upstreams after deleting isolation = %v, want all endpoints", got)
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusters
+
+import (
+	"time"
+
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+)
+
+const (
+	// maxListEndpoints is the maximum number of lists whose endpoints are cached in a cluster
+	maxListEndpoints = 4096
+	// listEndpointTTL is how long the endpoint serving a list is remembered, watches usually
+	// follow lists immediately
+	listEndpointTTL = time.Minute
+)
+
+// listKey identifies a list of resources at a resourceVersion
+type listKey struct {
+	group           string
+	resource        string
+	namespace       string
+	resourceVersion string
+}
+
+func newListKey(requestInfo *genericapirequest.RequestInfo, resourceVersion string) listKey {
+	return listKey{
+		group:           requestInfo.APIGroup,
+		resource:        requestInfo.Resource,
+		namespace:       requestInfo.Namespace,
+		resourceVersion: resourceVersion,
+	}
+}
+
+// RecordListEndpoint remembers the endpoint which responds the list at resourceVersion, the cache
+// of the endpoint is at least as fresh as resourceVersion.
+func (c *ClusterInfo) RecordListEndpoint(requestInfo *genericapirequest.RequestInfo, resourceVersion, endpoint string) {
+	if len(resourceVersion) == 0 || len(endpoint) == 0 {
+		return
+	}
+	c.listEndpoints.Add(newListKey(requestInfo, resourceVersion), endpoint, listEndpointTTL)
+}
+
+// ListEndpoint returns the endpoint which responds the list of the resource at resourceVersion
+// recently, a watch starting from resourceVersion is expected to be dispatched to it so that it
+// does not land on a lagging endpoint and fail with "too old resource version".
+func (c *ClusterInfo) ListEndpoint(requestInfo *genericapirequest.RequestInfo, resourceVersion string) (string, bool) {
+	if len(resourceVersion) == 0 {
+		return "", false
+	}
+	endpoint, ok := c.listEndpoints.Get(newListKey(requestInfo, resourceVersion))
+	if !ok {
+		return "", false
+	}
+	return endpoint.(string), true
+}
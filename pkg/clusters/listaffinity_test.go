@@ -0,0 +1,83 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusters
+
+import (
+	"fmt"
+	"testing"
+
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+
+	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
+)
+
+func TestClusterInfo_listAffinity(t *testing.T) {
+	cluster := newTestUpstreamClusterConfig()
+	cluster.Spec.Servers = nil
+	for i := 1; i <= 3; i++ {
+		cluster.Spec.Servers = append(cluster.Spec.Servers, proxyv1alpha1.UpstreamClusterServer{Endpoint: fmt.Sprintf("https://127.0.0.%d:443", i)})
+	}
+	clusterInfo, err := CreateClusterInfo(cluster, func(*EndpointInfo) bool { return true })
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clusterInfo.Stop()
+	for i := 1; i <= 2; i++ {
+		info, _ := clusterInfo.Endpoints.Load(fmt.Sprintf("https://127.0.0.%d:443", i))
+		info.UpdateStatus(true, "", "")
+	}
+
+	pods := &genericapirequest.RequestInfo{IsResourceRequest: true, Verb: "list", Resource: "pods", Namespace: "default"}
+	clusterInfo.RecordListEndpoint(pods, "100", "https://127.0.0.2:443")
+	clusterInfo.RecordListEndpoint(pods, "200", "https://127.0.0.3:443")
+
+	tests := []struct {
+		name            string
+		requestInfo     *genericapirequest.RequestInfo
+		resourceVersion string
+		wantPreferred   string
+		want            []string
+	}{
+		{"watch from list", pods, "100", "https://127.0.0.2:443", []string{"https://127.0.0.2:443"}},
+		{"unknown resourceVersion", pods, "150", "", []string{"https://127.0.0.1:443", "https://127.0.0.2:443"}},
+		{"another namespace", &genericapirequest.RequestInfo{Resource: "pods", Namespace: "kube-system"}, "100", "", []string{"https://127.0.0.1:443", "https://127.0.0.2:443"}},
+		{"list endpoint is not ready", pods, "200", "https://127.0.0.3:443", []string{"https://127.0.0.1:443", "https://127.0.0.2:443"}},
+	}
+	attrs := authorizer.AttributesRecord{User: &user.DefaultInfo{Name: "test"}, Verb: "watch", Resource: "pods", ResourceRequest: true}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			preferred, _ := clusterInfo.ListEndpoint(tt.requestInfo, tt.resourceVersion)
+			if preferred != tt.wantPreferred {
+				t.Errorf("ClusterInfo.ListEndpoint() = %v, want %v", preferred, tt.wantPreferred)
+			}
+			picker, err := clusterInfo.MatchAttributes(attrs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for j := 0; j < 4; j++ {
+				endpoint, err := picker.PopPreferred(preferred)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !containsString(tt.want, endpoint.Endpoint) {
+					t.Errorf("PopPreferred() = %v, want one of %v", endpoint.Endpoint, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -15,6 +15,7 @@
 package clusters
 
 import (
+	"sort"
 	"strings"
 	"sync"
 
@@ -31,6 +32,8 @@ type EndpointHealthCheck func(*EndpointInfo) (done bool)
 type Manager interface {
 	Add(*ClusterInfo)
 	Get(name string) (*ClusterInfo, bool)
+	// List returns all clusters sorted by name
+	List() []*ClusterInfo
 	Delete(name string)
 	DeleteAll()
 
@@ -58,11 +61,26 @@ func (m *manager) Get(name string) (*ClusterInfo, bool) {
 	return v.(*ClusterInfo), true
 }
 
+func (m *manager) List() []*ClusterInfo {
+	ret := []*ClusterInfo{}
+	m.clusters.Range(func(key, value interface{}) bool {
+		ret = append(ret, value.(*ClusterInfo))
+		return true
+	})
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Cluster < ret[j].Cluster
+	})
+	return ret
+}
+
 func (m *manager) Add(cluster *ClusterInfo) {
 	if cluster == nil {
 		return
 	}
-	cluster.Cluster = strings.ToLower(cluster.Cluster)
+	if name := strings.ToLower(cluster.Cluster); name != cluster.Cluster {
+		// names are lowercased on creation, the name is not rewritten while health checks read it
+		cluster.Cluster = name
+	}
 	klog.V(1).Infof("[cluster manager] new cluster info is added, cluster=%q", cluster.Cluster)
 	m.clusters.Store(cluster.Cluster, cluster)
 }
@@ -15,18 +15,36 @@
 package clusters
 
 import (
+	"strconv"
+
 	"k8s.io/apiserver/pkg/authorization/authorizer"
 
 	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
 )
 
 func MatchPolicies(requestAttributes authorizer.Attributes, policies []proxyv1alpha1.DispatchPolicy) *proxyv1alpha1.DispatchPolicy {
+	if i := matchPolicyIndex(requestAttributes, policies); i >= 0 {
+		return &policies[i]
+	}
+	return nil
+}
+
+// matchPolicyIndex returns the index of the first matched policy, or -1 if nothing matches
+func matchPolicyIndex(requestAttributes authorizer.Attributes, policies []proxyv1alpha1.DispatchPolicy) int {
 	for i := range policies {
 		if PolicyMatches(requestAttributes, &policies[i]) {
-			return &policies[i]
+			return i
 		}
 	}
-	return nil
+	return -1
+}
+
+// dispatchPolicyName returns the policy name, or its index in policies if the name is not set
+func dispatchPolicyName(policy *proxyv1alpha1.DispatchPolicy, index int) string {
+	if len(policy.Name) > 0 {
+		return policy.Name
+	}
+	return "#" + strconv.Itoa(index)
 }
 
 func PolicyMatches(requestAttributes authorizer.Attributes, policy *proxyv1alpha1.DispatchPolicy) bool {
@@ -39,22 +57,33 @@ func PolicyMatches(requestAttributes authorizer.Attributes, policy *proxyv1alpha
 }
 
 func RuleMatches(requestAttributes authorizer.Attributes, rule *proxyv1alpha1.DispatchPolicyRule) bool {
-	basicMatch := proxyv1alpha1.VerbMatches(rule.Verbs, requestAttributes.GetVerb()) &&
-		proxyv1alpha1.UserOrServiceAccountMatches(rule.Users, rule.ServiceAccounts, requestAttributes.GetUser().GetName()) &&
-		proxyv1alpha1.UserGroupMatches(rule.UserGroups, requestAttributes.GetUser().GetGroups())
-
-	if !basicMatch {
-		return false
+	if requestAttributes.IsResourceRequest() {
+		return ruleMatchesResourceShape(requestAttributes, rule) && ruleMatchesRequester(requestAttributes, rule)
 	}
+	return proxyv1alpha1.VerbMatches(rule.Verbs, requestAttributes.GetVerb()) &&
+		ruleMatchesUser(requestAttributes, rule) &&
+		proxyv1alpha1.NonResourceURLMatches(rule.NonResourceURLs, requestAttributes.GetPath())
+}
 
-	if requestAttributes.IsResourceRequest() {
-		combinedResource := requestAttributes.GetResource()
-		if len(requestAttributes.GetSubresource()) > 0 {
-			combinedResource = requestAttributes.GetResource() + "/" + requestAttributes.GetSubresource()
-		}
-		return proxyv1alpha1.APIGroupMatches(rule.APIGroups, requestAttributes.GetAPIGroup()) &&
-			proxyv1alpha1.ResourceMatches(rule.Resources, combinedResource, requestAttributes.GetSubresource()) &&
-			proxyv1alpha1.ResourceNameMatches(rule.ResourceNames, requestAttributes.GetName())
+// ruleMatchesResourceShape matches the parts of rule which only depend on the shape of a resource
+// request, i.e. verb, api group and resource, results of them can be cached by request shape.
+func ruleMatchesResourceShape(requestAttributes authorizer.Attributes, rule *proxyv1alpha1.DispatchPolicyRule) bool {
+	combinedResource := requestAttributes.GetResource()
+	if len(requestAttributes.GetSubresource()) > 0 {
+		combinedResource = requestAttributes.GetResource() + "/" + requestAttributes.GetSubresource()
 	}
-	return proxyv1alpha1.NonResourceURLMatches(rule.NonResourceURLs, requestAttributes.GetPath())
+	return proxyv1alpha1.VerbMatches(rule.Verbs, requestAttributes.GetVerb()) &&
+		proxyv1alpha1.APIGroupMatches(rule.APIGroups, requestAttributes.GetAPIGroup()) &&
+		proxyv1alpha1.ResourceMatches(rule.Resources, combinedResource, requestAttributes.GetSubresource())
+}
+
+// ruleMatchesRequester matches the parts of rule which vary between requests of the same shape
+func ruleMatchesRequester(requestAttributes authorizer.Attributes, rule *proxyv1alpha1.DispatchPolicyRule) bool {
+	return ruleMatchesUser(requestAttributes, rule) &&
+		proxyv1alpha1.ResourceNameMatches(rule.ResourceNames, requestAttributes.GetName())
+}
+
+func ruleMatchesUser(requestAttributes authorizer.Attributes, rule *proxyv1alpha1.DispatchPolicyRule) bool {
+	return proxyv1alpha1.UserOrServiceAccountMatches(rule.Users, rule.ServiceAccounts, requestAttributes.GetUser().GetName()) &&
+		proxyv1alpha1.UserGroupMatches(rule.UserGroups, requestAttributes.GetUser().GetGroups())
 }
@@ -141,3 +141,45 @@ func TestRuleMatches(t *testing.T) {
 		})
 	}
 }
+
+func Test_dispatchPolicyName(t *testing.T) {
+	policies := []proxyv1alpha1.DispatchPolicy{
+		{
+			Rules: []proxyv1alpha1.DispatchPolicyRule{
+				{Verbs: []string{"list"}, APIGroups: []string{"*"}, Resources: []string{"*"}},
+			},
+		},
+		{
+			Name: "default",
+			Rules: []proxyv1alpha1.DispatchPolicyRule{
+				{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}},
+			},
+		},
+	}
+	tests := []struct {
+		name string
+		verb string
+		want string
+	}{
+		{"unnamed policy uses index", "list", "#0"},
+		{"named policy", "get", "default"},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			attrs := authorizer.AttributesRecord{
+				Verb:            tt.verb,
+				Resource:        "pods",
+				ResourceRequest: true,
+				User:            &user.DefaultInfo{Name: "test"},
+			}
+			index := matchPolicyIndex(attrs, policies)
+			if index < 0 {
+				t.Fatalf("matchPolicyIndex() = %v, want a matched policy", index)
+			}
+			if got := dispatchPolicyName(&policies[index], index); got != tt.want {
+				t.Errorf("dispatchPolicyName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
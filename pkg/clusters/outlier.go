@@ -0,0 +1,227 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusters
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+
+	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
+	"github.com/kubewharf/kubegateway/pkg/gateway/metrics"
+)
+
+const (
+	defaultOutlierConsecutiveErrors = 5
+	defaultOutlierInterval          = 10 * time.Second
+	defaultOutlierEjection          = 30 * time.Second
+	defaultOutlierHalfOpenPercent   = 10
+
+	// outlierReasonConsecutiveErrors is the reason of ejections in the health history
+	outlierReasonConsecutiveErrors = "ConsecutiveErrors"
+)
+
+const (
+	outlierResultEjected    = "ejected"
+	outlierResultHalfOpen   = "half_open"
+	outlierResultReinstated = "reinstated"
+)
+
+type outlierState int
+
+const (
+	// outlierStateActive endpoints receive requests as usual
+	outlierStateActive outlierState = iota
+	// outlierStateEjected endpoints receive no requests until the ejection ends
+	outlierStateEjected
+	// outlierStateHalfOpen endpoints receive a share of requests to test recovery
+	outlierStateHalfOpen
+)
+
+// healthState returns the state shown in the health history and status of a healthy endpoint
+func (s outlierState) healthState() string {
+	switch s {
+	case outlierStateEjected:
+		return endpointStateEjected
+	case outlierStateHalfOpen:
+		return endpointStateHalfOpen
+	default:
+		return endpointStateHealthy
+	}
+}
+
+// outlierDetector tracks results of requests proxied to an endpoint and ejects it after
+// consecutive errors
+type outlierDetector struct {
+	lock  sync.Mutex
+	state outlierState
+	// errors is the number of consecutive errors since streakStart
+	errors      int
+	streakStart time.Time
+	// ejectedUntil is when the ejection ends and the endpoint becomes half-open
+	ejectedUntil time.Time
+}
+
+// outlierDetectionThresholds returns the number of consecutive errors to eject an endpoint, the window
+// they are counted in and how long the endpoint is ejected
+func outlierDetectionThresholds(policy *proxyv1alpha1.OutlierDetectionPolicy) (int, time.Duration, time.Duration) {
+	errors, interval, ejection := defaultOutlierConsecutiveErrors, defaultOutlierInterval, defaultOutlierEjection
+	if policy.ConsecutiveErrors > 0 {
+		errors = int(policy.ConsecutiveErrors)
+	}
+	if policy.IntervalSeconds > 0 {
+		interval = time.Duration(policy.IntervalSeconds) * time.Second
+	}
+	if policy.EjectionSeconds > 0 {
+		ejection = time.Duration(policy.EjectionSeconds) * time.Second
+	}
+	return errors, interval, ejection
+}
+
+func (e *EndpointInfo) loadOutlierDetectionPolicy() *proxyv1alpha1.OutlierDetectionPolicy {
+	if e.outlierDetectionPolicy == nil {
+		return nil
+	}
+	return e.outlierDetectionPolicy()
+}
+
+// recordProxyResult records the result of a request proxied to the endpoint, failure is the
+// error or status code of a failed request and it is empty if the request succeeded.
+func (e *EndpointInfo) recordProxyResult(now time.Time, failure string) {
+	policy := e.loadOutlierDetectionPolicy()
+	if policy == nil {
+		return
+	}
+	threshold, interval, ejection := outlierDetectionThresholds(policy)
+	failed := len(failure) > 0
+
+	d := &e.outlier
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	switch d.state {
+	case outlierStateEjected:
+		// results of requests dispatched before the ejection
+		return
+	case outlierStateHalfOpen:
+		if failed {
+			e.ejectLocked(now, ejection, fmt.Sprintf("request failed while half-open: %s", failure))
+		} else {
+			d.errors = 0
+			e.transitOutlierLocked(outlierStateActive, outlierResultReinstated, "", "")
+		}
+		return
+	}
+
+	if !failed {
+		d.errors = 0
+		return
+	}
+	if d.errors == 0 || now.Sub(d.streakStart) > interval {
+		// errors spread over a longer time than the interval do not count as consecutive
+		d.errors, d.streakStart = 0, now
+	}
+	d.errors++
+	if d.errors >= threshold {
+		e.ejectLocked(now, ejection, fmt.Sprintf("%d consecutive errors within %v, last error: %s", d.errors, interval, failure))
+	}
+}
+
+func (e *EndpointInfo) ejectLocked(now time.Time, ejection time.Duration, message string) {
+	e.outlier.errors = 0
+	e.outlier.ejectedUntil = now.Add(ejection)
+	e.transitOutlierLocked(outlierStateEjected, outlierResultEjected, outlierReasonConsecutiveErrors, message)
+}
+
+func (e *EndpointInfo) transitOutlierLocked(to outlierState, result, reason, message string) {
+	from := e.outlier.state
+	e.outlier.state = to
+	klog.V(1).Infof("[endpoint info] outlier detection state changed, cluster=%q, endpoint=%q, from=%q, to=%q, message=%q",
+		e.Cluster, e.Endpoint, from.healthState(), to.healthState(), message)
+	metrics.RecordOutlierTransition(e.Cluster, e.Endpoint, result)
+	e.appendHealthTransition(HealthTransition{
+		Time:    metav1.Now(),
+		From:    from.healthState(),
+		To:      to.healthState(),
+		Reason:  reason,
+		Message: message,
+	})
+}
+
+// admitsRequest returns true if requests can be dispatched to the endpoint by outlier detection.
+// An ejected endpoint becomes half-open once the ejection ends, and a half-open endpoint is
+// considered for a share of requests.
+func (e *EndpointInfo) admitsRequest(now time.Time) bool {
+	policy := e.loadOutlierDetectionPolicy()
+	if policy == nil {
+		return true
+	}
+
+	d := &e.outlier
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	switch d.state {
+	case outlierStateActive:
+		return true
+	case outlierStateEjected:
+		if now.Before(d.ejectedUntil) {
+			return false
+		}
+		e.transitOutlierLocked(outlierStateHalfOpen, outlierResultHalfOpen, "", "")
+	}
+	percent := defaultOutlierHalfOpenPercent
+	if policy.HalfOpenPercent > 0 {
+		percent = int(policy.HalfOpenPercent)
+	}
+	return rand.Intn(100) < percent
+}
+
+// outlierState returns the current outlier detection state of the endpoint
+func (e *EndpointInfo) outlierState() outlierState {
+	e.outlier.lock.Lock()
+	defer e.outlier.lock.Unlock()
+	return e.outlier.state
+}
+
+// outlierDetectionTransport records results of requests proxied to the endpoint for outlier
+// detection, 5xx responses and connection errors are failures. Requests canceled by clients
+// are not counted.
+type outlierDetectionTransport struct {
+	endpoint  *EndpointInfo
+	transport http.RoundTripper
+}
+
+func (t *outlierDetectionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.transport.RoundTrip(req)
+	switch {
+	case err != nil:
+		if req.Context().Err() == nil {
+			t.endpoint.recordProxyResult(time.Now(), err.Error())
+		}
+	case resp.StatusCode >= http.StatusInternalServerError:
+		t.endpoint.recordProxyResult(time.Now(), fmt.Sprintf("status code %d", resp.StatusCode))
+	default:
+		t.endpoint.recordProxyResult(time.Now(), "")
+	}
+	return resp, err
+}
+
+func (t *outlierDetectionTransport) WrappedRoundTripper() http.RoundTripper {
+	return t.transport
+}
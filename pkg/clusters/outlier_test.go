@@ -0,0 +1,143 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusters
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+
+	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
+)
+
+// outlierResult is the result of a proxied request after seconds, it fails if failure is not empty
+type outlierResult struct {
+	seconds int
+	failure string
+}
+
+func TestEndpointInfo_recordProxyResult(t *testing.T) {
+	policy := &proxyv1alpha1.OutlierDetectionPolicy{ConsecutiveErrors: 3, IntervalSeconds: 10, EjectionSeconds: 30, HalfOpenPercent: 100}
+	tests := []struct {
+		name    string
+		policy  *proxyv1alpha1.OutlierDetectionPolicy
+		results []outlierResult
+		// at is the seconds when the state and admission are checked
+		at         int
+		want       outlierState
+		wantAdmits bool
+	}{
+		{"no policy", nil, []outlierResult{{0, "503"}, {1, "503"}, {2, "503"}}, 3, outlierStateActive, true},
+		{"consecutive errors eject", policy, []outlierResult{{0, "503"}, {1, "503"}, {2, "503"}}, 3, outlierStateEjected, false},
+		{"success resets errors", policy, []outlierResult{{0, "503"}, {1, "503"}, {2, ""}, {3, "503"}}, 4, outlierStateActive, true},
+		{"errors out of interval do not eject", policy, []outlierResult{{0, "503"}, {5, "503"}, {11, "503"}}, 12, outlierStateActive, true},
+		{"default thresholds", &proxyv1alpha1.OutlierDetectionPolicy{}, []outlierResult{{0, "503"}, {1, "503"}, {2, "503"}, {3, "503"}, {4, "503"}}, 5, outlierStateEjected, false},
+		{"half-open after ejection", policy, []outlierResult{{0, "503"}, {1, "503"}, {2, "503"}}, 32, outlierStateHalfOpen, true},
+		{"results while ejected are ignored", policy, []outlierResult{{0, "503"}, {1, "503"}, {2, "503"}, {3, ""}}, 4, outlierStateEjected, false},
+		{"half-open endpoint is reinstated after a success", policy, []outlierResult{{0, "503"}, {1, "503"}, {2, "503"}, {33, ""}}, 33, outlierStateActive, true},
+		{"half-open endpoint is ejected again after a failure", policy, []outlierResult{{0, "503"}, {1, "503"}, {2, "503"}, {33, "503"}}, 34, outlierStateEjected, false},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			e := &EndpointInfo{
+				Cluster:  "test",
+				Endpoint: "https://127.0.0.1:443",
+				outlierDetectionPolicy: func() *proxyv1alpha1.OutlierDetectionPolicy {
+					return tt.policy
+				},
+			}
+			start := time.Now()
+			for _, r := range tt.results {
+				now := start.Add(time.Duration(r.seconds) * time.Second)
+				if e.outlierState() == outlierStateEjected {
+					// half-open is entered when the endpoint is picked
+					e.admitsRequest(now)
+				}
+				e.recordProxyResult(now, r.failure)
+			}
+			if got := e.admitsRequest(start.Add(time.Duration(tt.at) * time.Second)); got != tt.wantAdmits {
+				t.Errorf("admitsRequest() = %v, want %v", got, tt.wantAdmits)
+			}
+			if got := e.outlierState(); got != tt.want {
+				t.Errorf("outlierState() = %v, want %v", got.healthState(), tt.want.healthState())
+			}
+		})
+	}
+}
+
+func TestClusterInfo_MatchRequest_outlierDetection(t *testing.T) {
+	tests := []struct {
+		name    string
+		ejected []int
+		want    []string
+	}{
+		{"no ejected endpoints", nil, []string{"https://127.0.0.1:443", "https://127.0.0.2:443", "https://127.0.0.3:443"}},
+		{"ejected endpoints are skipped", []int{0, 2}, []string{"https://127.0.0.2:443"}},
+		{"endpoints are never all ejected", []int{0, 1, 2}, []string{"https://127.0.0.1:443", "https://127.0.0.2:443", "https://127.0.0.3:443"}},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := newTestUpstreamClusterConfig()
+			cluster.Spec.Servers = nil
+			for i := 0; i < 3; i++ {
+				cluster.Spec.Servers = append(cluster.Spec.Servers, proxyv1alpha1.UpstreamClusterServer{
+					Endpoint: fmt.Sprintf("https://127.0.0.%d:443", i+1),
+				})
+			}
+			cluster.Spec.OutlierDetection = &proxyv1alpha1.OutlierDetectionPolicy{ConsecutiveErrors: 1}
+			clusterInfo, err := CreateClusterInfo(cluster, func(*EndpointInfo) bool { return true })
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer clusterInfo.Stop()
+			for _, server := range cluster.Spec.Servers {
+				info, _ := clusterInfo.Endpoints.Load(server.Endpoint)
+				info.UpdateStatus(true, "", "")
+			}
+			for _, i := range tt.ejected {
+				info, _ := clusterInfo.Endpoints.Load(cluster.Spec.Servers[i].Endpoint)
+				info.recordProxyResult(time.Now(), "status code 503")
+			}
+
+			picked := sets.NewString()
+			for i := 0; i < 100; i++ {
+				picker, err := clusterInfo.MatchRequest(authorizer.AttributesRecord{
+					User:            &user.DefaultInfo{Name: "test"},
+					Verb:            "list",
+					Namespace:       "default",
+					Resource:        "pods",
+					ResourceRequest: true,
+				}, "")
+				if err != nil {
+					t.Fatal(err)
+				}
+				info, err := picker.Pop()
+				if err != nil {
+					t.Fatal(err)
+				}
+				picked.Insert(info.Endpoint)
+			}
+			if want := sets.NewString(tt.want...); !picked.Equal(want) {
+				t.Errorf("picked endpoints = %v, want %v", picked.List(), want.List())
+			}
+		})
+	}
+}
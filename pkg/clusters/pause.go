@@ -0,0 +1,138 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusters
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ErrAdmissionPaused means a request is not admitted because admission of the cluster is paused
+var ErrAdmissionPaused = fmt.Errorf("admission of new requests is paused")
+
+// Pause describes a paused cluster. New requests wait for resuming for at most MaxWait before
+// they are rejected, requests admitted before pausing, including watches, are not affected.
+type Pause struct {
+	Since metav1.Time `json:"since"`
+	// MaxWait is how long new requests wait for resuming, zero rejects them immediately
+	MaxWait metav1.Duration `json:"maxWait"`
+	// Until is when the cluster resumes automatically, it is nil if the cluster is paused until resumed
+	Until *metav1.Time `json:"until,omitempty"`
+}
+
+type pauseState struct {
+	Pause
+	// resumed is closed when the cluster is resumed
+	resumed chan struct{}
+	timer   *time.Timer
+}
+
+func (c *ClusterInfo) loadPause() *pauseState {
+	state, _ := c.pause.Load().(*pauseState)
+	return state
+}
+
+// PauseAdmission pauses admitting new requests to this cluster while keeping existing connections
+// and watches, e.g. quiescing a cluster during an upstream leader handoff. New requests wait for
+// at most maxWait and are rejected if the cluster is not resumed in time. If duration is positive,
+// the cluster resumes automatically after it. Pausing a paused cluster updates maxWait and duration.
+func (c *ClusterInfo) PauseAdmission(maxWait, duration time.Duration) (Pause, error) {
+	if maxWait < 0 || duration < 0 {
+		return Pause{}, fmt.Errorf("maxWait and duration can not be negative")
+	}
+
+	c.pauseLock.Lock()
+	defer c.pauseLock.Unlock()
+
+	now := time.Now()
+	state := &pauseState{
+		Pause:   Pause{Since: metav1.NewTime(now), MaxWait: metav1.Duration{Duration: maxWait}},
+		resumed: make(chan struct{}),
+	}
+	if current := c.loadPause(); current != nil {
+		// requests waiting for the current pause keep waiting
+		state.Since = current.Since
+		state.resumed = current.resumed
+		if current.timer != nil {
+			current.timer.Stop()
+		}
+	}
+	if duration > 0 {
+		until := metav1.NewTime(now.Add(duration))
+		state.Until = &until
+		state.timer = time.AfterFunc(duration, func() {
+			c.resumeAdmission(state.resumed)
+		})
+	}
+	c.pause.Store(state)
+	return state.Pause, nil
+}
+
+// ResumeAdmission resumes admitting requests to this cluster, it returns false if the cluster is not paused.
+func (c *ClusterInfo) ResumeAdmission() bool {
+	return c.resumeAdmission(nil)
+}
+
+// resumeAdmission resumes the cluster if it is paused by the pause whose resumed channel is given,
+// or by any pause if resumed is nil.
+func (c *ClusterInfo) resumeAdmission(resumed chan struct{}) bool {
+	c.pauseLock.Lock()
+	defer c.pauseLock.Unlock()
+	current := c.loadPause()
+	if current == nil || (resumed != nil && current.resumed != resumed) {
+		return false
+	}
+	if current.timer != nil {
+		current.timer.Stop()
+	}
+	close(current.resumed)
+	c.pause.Store((*pauseState)(nil))
+	return true
+}
+
+// AdmissionPause returns the pause of this cluster, it returns false if the cluster is not paused.
+func (c *ClusterInfo) AdmissionPause() (Pause, bool) {
+	state := c.loadPause()
+	if state == nil {
+		return Pause{}, false
+	}
+	return state.Pause, true
+}
+
+// WaitForAdmission returns immediately if the cluster is not paused, otherwise it waits until
+// the cluster is resumed. It returns ErrAdmissionPaused if the cluster is not resumed in MaxWait
+// of the pause, or the error of ctx if ctx is done before that.
+func (c *ClusterInfo) WaitForAdmission(ctx context.Context) error {
+	state := c.loadPause()
+	if state == nil {
+		return nil
+	}
+	if state.MaxWait.Duration <= 0 {
+		return ErrAdmissionPaused
+	}
+	timer := time.NewTimer(state.MaxWait.Duration)
+	defer timer.Stop()
+	select {
+	case <-state.resumed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return ErrAdmissionPaused
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusters
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestClusterInfo_WaitForAdmission(t *testing.T) {
+	tests := []struct {
+		name     string
+		paused   bool
+		maxWait  time.Duration
+		duration time.Duration
+		resume   bool
+		wantErr  error
+	}{
+		{"not paused", false, 0, 0, false, nil},
+		{"rejected immediately", true, 0, 0, false, ErrAdmissionPaused},
+		{"rejected after max wait", true, 50 * time.Millisecond, 0, false, ErrAdmissionPaused},
+		{"admitted after resuming", true, 5 * time.Second, 0, true, nil},
+		{"admitted after resuming automatically", true, 5 * time.Second, 50 * time.Millisecond, false, nil},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			clusterInfo, err := CreateClusterInfo(newTestUpstreamClusterConfig(), alwaysReadyHealthCheck)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer clusterInfo.Stop()
+			if tt.paused {
+				if _, err := clusterInfo.PauseAdmission(tt.maxWait, tt.duration); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.resume {
+				time.AfterFunc(50*time.Millisecond, func() { clusterInfo.ResumeAdmission() })
+			}
+			if err := clusterInfo.WaitForAdmission(context.Background()); err != tt.wantErr {
+				t.Errorf("ClusterInfo.WaitForAdmission() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClusterInfo_PauseAdmission_repause(t *testing.T) {
+	clusterInfo, err := CreateClusterInfo(newTestUpstreamClusterConfig(), alwaysReadyHealthCheck)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clusterInfo.Stop()
+
+	first, _ := clusterInfo.PauseAdmission(time.Second, 50*time.Millisecond)
+	// pausing again cancels the automatic resuming of the first pause
+	second, _ := clusterInfo.PauseAdmission(time.Second, 0)
+	if !second.Since.Equal(&first.Since) || second.Until != nil {
+		t.Errorf("PauseAdmission() = %+v, want since %v and no until", second, first.Since)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if _, ok := clusterInfo.AdmissionPause(); !ok {
+		t.Errorf("cluster is resumed by the timer of a replaced pause")
+	}
+	if !clusterInfo.ResumeAdmission() || clusterInfo.ResumeAdmission() {
+		t.Errorf("ResumeAdmission() should succeed once")
+	}
+}
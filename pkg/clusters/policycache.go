@@ -0,0 +1,112 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusters
+
+import (
+	"sync"
+
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+
+	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
+)
+
+// maxPolicyMatchCacheEntries bounds the number of request shapes cached for a cluster,
+// request shapes are not cached any more after the cache is full.
+const maxPolicyMatchCacheEntries = 4096
+
+// policyMatchKey is the shape of a resource request
+type policyMatchKey struct {
+	verb        string
+	apiGroup    string
+	resource    string
+	subresource string
+	namespace   string
+}
+
+// candidateRules are rules of a policy which match a request shape
+type candidateRules struct {
+	policy int
+	rules  []int
+}
+
+// policyMatcher matches requests against dispatch policies. For resource requests, it caches
+// rules matching the request shape, so that only users and resource names are matched against
+// these rules for repeated request shapes. It is created again when policies change, which
+// invalidates the cache.
+type policyMatcher struct {
+	policies []proxyv1alpha1.DispatchPolicy
+
+	lock       sync.RWMutex
+	candidates map[policyMatchKey][]candidateRules
+}
+
+func newPolicyMatcher(policies []proxyv1alpha1.DispatchPolicy) *policyMatcher {
+	return &policyMatcher{
+		policies:   policies,
+		candidates: map[policyMatchKey][]candidateRules{},
+	}
+}
+
+// match returns the index of the first matched policy, or -1 if nothing matches
+func (m *policyMatcher) match(requestAttributes authorizer.Attributes) int {
+	if !requestAttributes.IsResourceRequest() {
+		// paths of non-resource requests are unbounded, they are not cached
+		return matchPolicyIndex(requestAttributes, m.policies)
+	}
+
+	key := policyMatchKey{
+		verb:        requestAttributes.GetVerb(),
+		apiGroup:    requestAttributes.GetAPIGroup(),
+		resource:    requestAttributes.GetResource(),
+		subresource: requestAttributes.GetSubresource(),
+		namespace:   requestAttributes.GetNamespace(),
+	}
+	m.lock.RLock()
+	candidates, ok := m.candidates[key]
+	m.lock.RUnlock()
+	if !ok {
+		candidates = m.matchResourceShape(requestAttributes)
+		m.lock.Lock()
+		if len(m.candidates) < maxPolicyMatchCacheEntries {
+			m.candidates[key] = candidates
+		}
+		m.lock.Unlock()
+	}
+
+	for _, c := range candidates {
+		for _, r := range c.rules {
+			if ruleMatchesRequester(requestAttributes, &m.policies[c.policy].Rules[r]) {
+				return c.policy
+			}
+		}
+	}
+	return -1
+}
+
+func (m *policyMatcher) matchResourceShape(requestAttributes authorizer.Attributes) []candidateRules {
+	var ret []candidateRules
+	for i := range m.policies {
+		var rules []int
+		for j := range m.policies[i].Rules {
+			if ruleMatchesResourceShape(requestAttributes, &m.policies[i].Rules[j]) {
+				rules = append(rules, j)
+			}
+		}
+		if len(rules) > 0 {
+			ret = append(ret, candidateRules{policy: i, rules: rules})
+		}
+	}
+	return ret
+}
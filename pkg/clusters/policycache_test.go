@@ -0,0 +1,117 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusters
+
+import (
+	"fmt"
+	"testing"
+
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+
+	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
+)
+
+// newBenchmarkPolicies returns a realistic policy set: a few policies for special users,
+// resource names and resources in front of catch-all policies.
+func newBenchmarkPolicies() []proxyv1alpha1.DispatchPolicy {
+	policies := []proxyv1alpha1.DispatchPolicy{}
+	for i := 0; i < 5; i++ {
+		policies = append(policies, proxyv1alpha1.DispatchPolicy{
+			Rules: []proxyv1alpha1.DispatchPolicyRule{
+				{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}, Users: []string{fmt.Sprintf("user-%d", i)}},
+				{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}, UserGroups: []string{fmt.Sprintf("group-%d", i)}},
+			},
+		})
+	}
+	policies = append(policies,
+		proxyv1alpha1.DispatchPolicy{
+			Rules: []proxyv1alpha1.DispatchPolicyRule{
+				{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"configmaps"}, ResourceNames: []string{"leader"}},
+			},
+		},
+		proxyv1alpha1.DispatchPolicy{
+			Rules: []proxyv1alpha1.DispatchPolicyRule{
+				{Verbs: []string{"list", "watch"}, APIGroups: []string{""}, Resources: []string{"pods", "nodes"}},
+				{Verbs: []string{"list", "watch"}, APIGroups: []string{"apps"}, Resources: []string{"deployments", "replicasets"}},
+			},
+		},
+		proxyv1alpha1.DispatchPolicy{
+			Rules: []proxyv1alpha1.DispatchPolicyRule{
+				{Verbs: []string{"create", "update", "patch", "delete"}, APIGroups: []string{"*"}, Resources: []string{"*"}},
+			},
+		},
+		proxyv1alpha1.DispatchPolicy{
+			Rules: []proxyv1alpha1.DispatchPolicyRule{
+				{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}},
+				{Verbs: []string{"*"}, NonResourceURLs: []string{"*"}},
+			},
+		},
+	)
+	return policies
+}
+
+func TestPolicyMatcher_match(t *testing.T) {
+	policies := newBenchmarkPolicies()
+	matcher := newPolicyMatcher(policies)
+	tests := []authorizer.AttributesRecord{
+		{Verb: "list", Resource: "pods", Namespace: "default", ResourceRequest: true, User: &user.DefaultInfo{Name: "user-3"}},
+		{Verb: "list", Resource: "pods", Namespace: "default", ResourceRequest: true, User: &user.DefaultInfo{Name: "test"}},
+		{Verb: "list", Resource: "pods", Namespace: "default", ResourceRequest: true, User: &user.DefaultInfo{Name: "test", Groups: []string{"group-1"}}},
+		{Verb: "get", Resource: "configmaps", Name: "leader", Namespace: "kube-system", ResourceRequest: true, User: &user.DefaultInfo{Name: "test"}},
+		{Verb: "get", Resource: "configmaps", Name: "other", Namespace: "kube-system", ResourceRequest: true, User: &user.DefaultInfo{Name: "test"}},
+		{Verb: "get", Resource: "pods", Subresource: "log", Namespace: "default", ResourceRequest: true, User: &user.DefaultInfo{Name: "test"}},
+		{Verb: "patch", APIGroup: "apps", Resource: "deployments", Namespace: "default", ResourceRequest: true, User: &user.DefaultInfo{Name: "test"}},
+		{Verb: "get", Path: "/healthz", User: &user.DefaultInfo{Name: "test"}},
+	}
+	for i := range tests {
+		attrs := tests[i]
+		t.Run(fmt.Sprintf("%s %s/%s %s", attrs.Verb, attrs.Resource, attrs.Name, attrs.User.GetName()), func(t *testing.T) {
+			want := matchPolicyIndex(attrs, policies)
+			// the second match hits the cache
+			for j := 0; j < 2; j++ {
+				if got := matcher.match(attrs); got != want {
+					t.Errorf("match() = %v, want %v", got, want)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkPolicyMatch(b *testing.B) {
+	policies := newBenchmarkPolicies()
+	attrs := authorizer.AttributesRecord{
+		Verb:            "list",
+		APIGroup:        "apps",
+		Resource:        "replicasets",
+		Namespace:       "default",
+		ResourceRequest: true,
+		User:            &user.DefaultInfo{Name: "system:serviceaccount:kube-system:replicaset-controller", Groups: []string{"system:serviceaccounts"}},
+	}
+
+	b.Run("uncached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			matchPolicyIndex(attrs, policies)
+		}
+	})
+	b.Run("cached", func(b *testing.B) {
+		matcher := newPolicyMatcher(policies)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			matcher.match(attrs)
+		}
+	})
+}
@@ -0,0 +1,60 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusters
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/klog"
+)
+
+// endpointSelectionLogLevel is the verbosity of endpoint selection logs. Selection is
+// logged for every request, so the level is higher than other proxy logs. It can be
+// enabled for selection logs only with --vmodule=selection_log=6
+const endpointSelectionLogLevel klog.Level = 6
+
+// endpointSelectionLog records the candidates of an endpoint selection and the final
+// choice. A nil *endpointSelectionLog is valid and logs nothing.
+type endpointSelectionLog struct {
+	cluster    string
+	candidates []string
+}
+
+// newEndpointSelectionLog returns nil if selection logs are not enabled
+func newEndpointSelectionLog(cluster string) *endpointSelectionLog {
+	if !klog.V(endpointSelectionLogLevel) {
+		return nil
+	}
+	return &endpointSelectionLog{cluster: cluster}
+}
+
+// candidate records the state of a candidate endpoint
+func (l *endpointSelectionLog) candidate(endpoint, state string) {
+	if l == nil {
+		return
+	}
+	l.candidates = append(l.candidates, fmt.Sprintf("%s(%s)", endpoint, state))
+}
+
+// chosen logs the candidates and the chosen endpoint with the reason,
+// endpoint is empty if no endpoint is chosen.
+func (l *endpointSelectionLog) chosen(endpoint, reason string) {
+	if l == nil {
+		return
+	}
+	klog.Infof("[endpoint selection] cluster=%q candidates=[%s] chosen=%q reason=%q",
+		l.cluster, strings.Join(l.candidates, ", "), endpoint, reason)
+}
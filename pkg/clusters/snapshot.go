@@ -0,0 +1,185 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusters
+
+import (
+	"sync"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/component-base/featuregate"
+	"k8s.io/klog"
+
+	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
+	"github.com/kubewharf/kubegateway/pkg/clusters/features"
+	gatewayflowcontrol "github.com/kubewharf/kubegateway/pkg/flowcontrol"
+)
+
+// clusterSnapshot is the state of a cluster which dispatching depends on. A snapshot is never
+// modified after it is published, syncing builds a new one and swaps it in atomically, so that
+// a request reads endpoints, flow controls, feature gate and policies of the same version of
+// the cluster even if the cluster is syncing.
+type clusterSnapshot struct {
+	policies *policyMatcher
+	logging  proxyv1alpha1.LoggingConfig
+	// flow control spec with overrides applied
+	flowControlSpec proxyv1alpha1.FlowControl
+	flowControls    *gatewayflowcontrol.FlowControls
+	endpoints       *EndpointInfoMap
+	secureServing   *secureServingConfig
+	// loadbalancer stores round robin counters, it is reset when endpoints change
+	loadbalancer *sync.Map
+	// weights of endpoints used by weighted strategies, endpoints not in it weigh 1 and
+	// endpoints weighing 0 are drained
+	weights map[string]int32
+	// minimum number of healthy endpoints required to serve this cluster
+	minHealthyEndpoints int32
+	// restConfig is the config of upstream clients which endpoints are created with
+	restConfig *rest.Config
+	// sourceAddress is the local address which restConfig dials from
+	sourceAddress string
+	// upstream client tls settings which can not be set in rest config
+	upstreamTLSSettings upstreamTLSSettings
+	// h2c proxies requests to plaintext endpoints with HTTP/2 prior knowledge
+	h2c bool
+	// disableKeepAlives closes upstream connections after each request
+	disableKeepAlives bool
+	// feature gate set by annotations, it is never modified after it is published
+	featureGate featuregate.MutableFeatureGate
+	// cluster is marked down manually
+	disabled bool
+	// the following configs are nil if they are not configured
+	shadow           *proxyv1alpha1.ShadowConfig
+	retry            *proxyv1alpha1.RetryPolicy
+	stub             *proxyv1alpha1.StubConfig
+	apiResources     *proxyv1alpha1.APIResourceConfig
+	hiddenResources  *proxyv1alpha1.HiddenResourceConfig
+	healthCheck      *proxyv1alpha1.HealthCheckPolicy
+	outlierDetection *proxyv1alpha1.OutlierDetectionPolicy
+	impersonation    *proxyv1alpha1.ImpersonationPolicy
+	// published are changes to state shared with the current snapshot, e.g. resizing a flow
+	// control, they are run only after the snapshot is published
+	published []func()
+}
+
+func newClusterSnapshot() *clusterSnapshot {
+	return &clusterSnapshot{
+		policies:     newPolicyMatcher(nil),
+		flowControls: gatewayflowcontrol.NewFlowControls(),
+		endpoints:    &EndpointInfoMap{},
+		loadbalancer: &sync.Map{},
+		featureGate:  features.DefaultMutableFeatureGate.DeepCopy(),
+	}
+}
+
+// clone returns a shallow copy of the snapshot, registries are copied so that they can be
+// modified without affecting the published snapshot
+func (s *clusterSnapshot) clone() *clusterSnapshot {
+	ret := *s
+	ret.published = nil
+	ret.flowControls = gatewayflowcontrol.NewFlowControls()
+	s.flowControls.Range(func(name string, fl gatewayflowcontrol.FlowControl) bool {
+		ret.flowControls.Store(name, fl)
+		return true
+	})
+	ret.endpoints = &EndpointInfoMap{}
+	s.endpoints.Range(func(name string, info *EndpointInfo) bool {
+		ret.endpoints.Store(name, info)
+		return true
+	})
+	return &ret
+}
+
+// weight returns the weight of endpoint used by the WeightedRandom strategy
+func (s *clusterSnapshot) weight(endpoint string) int32 {
+	if w, ok := s.weights[endpoint]; ok {
+		return w
+	}
+	return 1
+}
+
+// onPublished defers fn until the snapshot is published, it is used to change state which is
+// shared with the current snapshot.
+func (s *clusterSnapshot) onPublished(fn func()) {
+	s.published = append(s.published, fn)
+}
+
+// hasMinHealthyEndpoints returns the number of ready endpoints, the minimum number of
+// healthy endpoints required, and whether the requirement is satisfied.
+func (s *clusterSnapshot) hasMinHealthyEndpoints() (int, int, bool) {
+	min := int(s.minHealthyEndpoints)
+	ready := 0
+	s.endpoints.Range(func(name string, info *EndpointInfo) bool {
+		if info.IsReady() {
+			ready++
+		}
+		return true
+	})
+	return ready, min, ready >= min
+}
+
+func (c *ClusterInfo) loadSnapshot() *clusterSnapshot {
+	return c.snapshot.Load().(*clusterSnapshot)
+}
+
+// updateSnapshot applies update to a copy of the current snapshot and publishes it. Nothing is
+// published if update fails, and actions deferred by onPublished are dropped. Endpoints which
+// are not in the resulting snapshot are stopped after publishing, i.e. removed endpoints on success and endpoints created by update on failure.
+func (c *ClusterInfo) updateSnapshot(update func(next *clusterSnapshot) error) error {
+	c.snapshotLock.Lock()
+	defer c.snapshotLock.Unlock()
+
+	current := c.loadSnapshot()
+	next := current.clone()
+	if err := update(next); err != nil {
+		stopRemovedEndpoints(next, current)
+		return err
+	}
+	c.snapshot.Store(next)
+	for _, fn := range next.published {
+		fn()
+	}
+	next.published = nil
+
+	// keep Endpoints in line with the published snapshot
+	next.endpoints.Range(func(name string, info *EndpointInfo) bool {
+		c.Endpoints.Store(name, info)
+		return true
+	})
+	for _, info := range stopRemovedEndpoints(current, next) {
+		if _, ok := next.endpoints.Load(info.Endpoint); ok {
+			// the endpoint is recreated
+			continue
+		}
+		c.Endpoints.LoadAndDelete(info.Endpoint)
+		klog.Infof("[cluster info] endpoint=%q is deleted from cluster %q", info.Endpoint, c.Cluster)
+	}
+	return nil
+}
+
+// stopRemovedEndpoints cancels endpoints which are in from but not in to, and returns them.
+func stopRemovedEndpoints(from, to *clusterSnapshot) []*EndpointInfo {
+	var removed []*EndpointInfo
+	from.endpoints.Range(func(name string, info *EndpointInfo) bool {
+		if ep, ok := to.endpoints.Load(name); ok && ep == info {
+			return true
+		}
+		if info.cancel != nil {
+			info.cancel()
+		}
+		removed = append(removed, info)
+		return true
+	})
+	return removed
+}
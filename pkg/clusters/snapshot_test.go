@@ -0,0 +1,324 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusters
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+
+	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
+	"github.com/kubewharf/kubegateway/pkg/clusters/features"
+)
+
+const snapshotTestMax = 1000
+
+// snapshotTestVersion is a version of cluster whose endpoints, flow control schema, dispatch
+// policy, retry policy and serving cert all refer to the version name, so that a torn state is detectable
+type snapshotTestVersion struct {
+	name string
+	// limits are the limits of flow control which can be observed in this version
+	limits    []int32
+	endpoints []string
+	cert      []byte
+	cluster   *proxyv1alpha1.UpstreamCluster
+}
+
+func newSnapshotTestVersion(name string, endpoints ...string) snapshotTestVersion {
+	key, crt, _ := createCAandCert()
+	pair, _ := tls.X509KeyPair(crt, key)
+
+	cluster := newTestUpstreamClusterConfig()
+	cluster.Spec.Servers = nil
+	for _, e := range endpoints {
+		cluster.Spec.Servers = append(cluster.Spec.Servers, proxyv1alpha1.UpstreamClusterServer{Endpoint: e})
+	}
+	cluster.Spec.SecureServing = proxyv1alpha1.SecureServing{KeyData: key, CertData: crt}
+	cluster.Spec.FlowControl = proxyv1alpha1.FlowControl{
+		Schemas: []proxyv1alpha1.FlowControlSchema{
+			{
+				Name: name,
+				FlowControlSchemaConfiguration: proxyv1alpha1.FlowControlSchemaConfiguration{
+					MaxRequestsInflight: &proxyv1alpha1.MaxRequestsInflightFlowControlSchema{Max: snapshotTestMax},
+				},
+			},
+		},
+	}
+	cluster.Spec.DispatchPolicies[0].UpstreamSubset = endpoints
+	cluster.Spec.DispatchPolicies[0].FlowControlSchemaName = name
+	cluster.Spec.Retry = &proxyv1alpha1.RetryPolicy{StatusMessages: []string{name}}
+	return snapshotTestVersion{name: name, limits: []int32{snapshotTestMax}, endpoints: endpoints, cert: pair.Certificate[0], cluster: cluster}
+}
+
+func (v snapshotTestVersion) hasEndpoint(endpoint string) bool {
+	return containsString(v.endpoints, endpoint)
+}
+
+// checkSnapshot returns an error if state in the snapshot does not belong to the same version
+func checkSnapshot(s *clusterSnapshot, versions map[string]snapshotTestVersion) error {
+	if len(s.flowControlSpec.Schemas) != 1 {
+		return fmt.Errorf("flow control schemas = %v, want exactly one", s.flowControlSpec.Schemas)
+	}
+	v := versions[s.flowControlSpec.Schemas[0].Name]
+	fc, ok := s.flowControls.Load(v.name)
+	if !ok || s.flowControls.Len() != 1 {
+		return fmt.Errorf("version %s: flow controls do not match the spec", v.name)
+	}
+	if max := fc.Status().Max; !containsLimit(v.limits, max) {
+		return fmt.Errorf("version %s: flow control max = %d, want one of %v", v.name, max, v.limits)
+	}
+	if got := s.policies.policies[0].FlowControlSchemaName; got != v.name {
+		return fmt.Errorf("version %s: dispatch policy refers to flow control schema %s", v.name, got)
+	}
+	names := s.endpoints.Names()
+	if len(names) != len(v.endpoints) {
+		return fmt.Errorf("version %s: endpoints = %v, want %v", v.name, names, v.endpoints)
+	}
+	for _, name := range names {
+		if !v.hasEndpoint(name) {
+			return fmt.Errorf("version %s: unexpected endpoint %s", v.name, name)
+		}
+	}
+	if s.secureServing == nil || len(s.secureServing.certs) != 1 || !bytes.Equal(s.secureServing.certs[0].Certificate[0], v.cert) {
+		return fmt.Errorf("version %s: serving cert does not match", v.name)
+	}
+	return checkRetryPolicy(v, s.retry)
+}
+
+func checkRetryPolicy(v snapshotTestVersion, retry *proxyv1alpha1.RetryPolicy) error {
+	if retry == nil || len(retry.StatusMessages) != 1 || retry.StatusMessages[0] != v.name {
+		return fmt.Errorf("version %s: retry policy %v does not match", v.name, retry)
+	}
+	return nil
+}
+
+func containsLimit(limits []int32, limit uint32) bool {
+	for _, l := range limits {
+		if uint32(l) == limit {
+			return true
+		}
+	}
+	return false
+}
+
+// checkFlowControlLimit returns an error if the limit of flow control in the snapshot is not the
+// one in its spec
+func checkFlowControlLimit(s *clusterSnapshot) error {
+	schema := s.flowControlSpec.Schemas[0]
+	fc, ok := s.flowControls.Load(schema.Name)
+	if !ok {
+		return fmt.Errorf("flow control %s is not found", schema.Name)
+	}
+	if got, want := fc.Status().Max, uint32(schema.MaxRequestsInflight.Max); got != want {
+		return fmt.Errorf("flow control %s: max = %d, want %d", schema.Name, got, want)
+	}
+	return nil
+}
+
+// checkPicker returns an error if the picker dispatches the request with endpoints, flow control
+// and retry policy of different versions
+func checkPicker(picker EndpointPicker, versions map[string]snapshotTestVersion) error {
+	v, ok := versions[picker.FlowControl().Name()]
+	if !ok {
+		return fmt.Errorf("unexpected flow control %s", picker.FlowControl().Name())
+	}
+	if err := checkRetryPolicy(v, picker.RetryPolicy()); err != nil {
+		return err
+	}
+	for _, upstream := range picker.(*endpointPickStrategy).upstreams {
+		if !v.hasEndpoint(upstream) {
+			return fmt.Errorf("version %s: upstream %s of another version is picked", v.name, upstream)
+		}
+	}
+	endpoint, err := picker.Pop()
+	if err != nil {
+		// endpoints are not ready before health checking, but they must be found
+		if errors.Cause(err) != ErrNoReadyEndpoints {
+			return fmt.Errorf("version %s: %v", v.name, err)
+		}
+		return nil
+	}
+	if !v.hasEndpoint(endpoint.Endpoint) {
+		return fmt.Errorf("version %s: endpoint %s of another version is popped", v.name, endpoint.Endpoint)
+	}
+	return nil
+}
+
+func TestClusterInfo_snapshotIsConsistentDuringSync(t *testing.T) {
+	a := newSnapshotTestVersion("a", "https://127.0.0.1:443", "https://127.0.0.2:443")
+	b := newSnapshotTestVersion("b", "https://127.0.0.3:443", "https://127.0.0.4:443", "https://127.0.0.5:443")
+	// flow control of a is resized in place, a request may see either limit of a
+	resized := a.cluster.DeepCopy()
+	resized.Spec.FlowControl.Schemas[0].MaxRequestsInflight.Max = snapshotTestMax / 2
+	a.limits = append(a.limits, snapshotTestMax/2)
+	// a failed sync must never change the limit
+	invalid := a.cluster.DeepCopy()
+	invalid.Spec.FlowControl.Schemas[0].MaxRequestsInflight.Max = 1
+	invalid.Spec.SecureServing.CertData = []byte("invalid")
+	versions := map[string]snapshotTestVersion{a.name: a, b.name: b}
+
+	clusterInfo, err := CreateClusterInfo(a.cluster, alwaysReadyHealthCheck)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clusterInfo.Stop()
+
+	const (
+		syncs   = 200
+		readers = 8
+	)
+	attrs := authorizer.AttributesRecord{
+		User:            &user.DefaultInfo{Name: "test"},
+		Verb:            "list",
+		Resource:        "pods",
+		ResourceRequest: true,
+	}
+
+	stopCh := make(chan struct{})
+	errCh := make(chan error, readers)
+	var wg sync.WaitGroup
+	for i := 0; i < readers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stopCh:
+					return
+				default:
+				}
+				if err := checkSnapshot(clusterInfo.loadSnapshot(), versions); err != nil {
+					errCh <- err
+					return
+				}
+				if clusterInfo.FeatureEnabled(features.DenyAllRequests) {
+					errCh <- fmt.Errorf("feature %s is enabled", features.DenyAllRequests)
+					return
+				}
+				picker, err := clusterInfo.MatchAttributes(attrs)
+				if err != nil {
+					errCh <- err
+					return
+				}
+				if err := checkPicker(picker, versions); err != nil {
+					errCh <- err
+					return
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < syncs; i++ {
+		var err error
+		switch i % 4 {
+		case 0:
+			err = clusterInfo.Sync(b.cluster)
+		case 1:
+			err = clusterInfo.Sync(a.cluster)
+		case 2:
+			err = clusterInfo.Sync(resized)
+		case 3:
+			if clusterInfo.Sync(invalid) == nil {
+				t.Fatalf("Sync() error = nil, want error")
+			}
+		}
+		if err != nil {
+			t.Fatalf("Sync() error = %v", err)
+		}
+		if err := checkFlowControlLimit(clusterInfo.loadSnapshot()); err != nil {
+			t.Fatalf("Sync() does not apply flow control: %v", err)
+		}
+		if i%20 == 0 {
+			// let endpoints become ready sometimes
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	close(stopCh)
+	wg.Wait()
+
+	select {
+	case err := <-errCh:
+		t.Fatalf("request sees a torn state of cluster: %v", err)
+	default:
+	}
+}
+
+func TestClusterInfo_syncKeepsLastGoodConfig(t *testing.T) {
+	a := newSnapshotTestVersion("a", "https://127.0.0.1:443")
+	b := newSnapshotTestVersion("b", "https://127.0.0.2:443")
+	versions := map[string]snapshotTestVersion{a.name: a}
+
+	invalidFeatureGate := b.cluster.DeepCopy()
+	invalidFeatureGate.Annotations = map[string]string{features.FeatureGateAnnotationKey: "DenyAllRequests=true,UnknownFeature=true"}
+	invalidSecureServing := b.cluster.DeepCopy()
+	invalidSecureServing.Spec.SecureServing.CertData = []byte("invalid")
+	// the same flow control schema with another limit must not be resized by a failed sync
+	resizedWithInvalidSecureServing := a.cluster.DeepCopy()
+	resizedWithInvalidSecureServing.Spec.FlowControl.Schemas[0].MaxRequestsInflight.Max = 1
+	resizedWithInvalidSecureServing.Spec.SecureServing.CertData = []byte("invalid")
+
+	tests := []struct {
+		name    string
+		cluster *proxyv1alpha1.UpstreamCluster
+	}{
+		{"invalid feature gate", invalidFeatureGate},
+		{"invalid secure serving", invalidSecureServing},
+		{"resized flow control with invalid secure serving", resizedWithInvalidSecureServing},
+	}
+
+	clusterInfo, err := CreateClusterInfo(a.cluster, alwaysReadyHealthCheck)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clusterInfo.Stop()
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			if err := clusterInfo.Sync(tt.cluster); err == nil {
+				t.Fatalf("Sync() error = nil, want error")
+			}
+			if err := checkSnapshot(clusterInfo.loadSnapshot(), versions); err != nil {
+				t.Errorf("Sync() publishes a partially applied config: %v", err)
+			}
+			if err := checkFlowControlLimit(clusterInfo.loadSnapshot()); err != nil {
+				t.Errorf("Sync() changes flow control: %v", err)
+			}
+			if source := clusterInfo.sourceFlowControlSpec.Schemas[0]; source.Name != a.name || source.MaxRequestsInflight.Max != snapshotTestMax {
+				t.Errorf("Sync() changes source flow control spec to %v", clusterInfo.sourceFlowControlSpec)
+			}
+			if clusterInfo.FeatureEnabled(features.DenyAllRequests) {
+				t.Errorf("Sync() changes feature gate")
+			}
+			if !containsString(clusterInfo.AllEndpoints(), "https://127.0.0.1:443") || len(clusterInfo.AllEndpoints()) != 1 {
+				t.Errorf("Sync() changes endpoints to %v", clusterInfo.AllEndpoints())
+			}
+		})
+	}
+
+	if err := clusterInfo.Sync(b.cluster); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if err := checkSnapshot(clusterInfo.loadSnapshot(), map[string]snapshotTestVersion{b.name: b}); err != nil {
+		t.Errorf("Sync() does not apply valid config: %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusters
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/klog"
+
+	"github.com/kubewharf/kubegateway/pkg/gateway/metrics"
+)
+
+// reasons of upstream certificate verification failures
+const (
+	TLSVerificationUnknownAuthority = "UnknownAuthority"
+	TLSVerificationHostnameMismatch = "HostnameMismatch"
+	TLSVerificationExpired          = "Expired"
+	TLSVerificationNotYetValid      = "NotYetValid"
+	TLSVerificationInvalid          = "Invalid"
+)
+
+// TLSVerificationFailure describes why the certificate of an upstream endpoint fails
+// to be verified.
+type TLSVerificationFailure struct {
+	Reason string
+	// Detail tells what is wrong and the certificate presented by upstream
+	Detail string
+}
+
+// DiagnoseTLSVerificationError returns the failure if err is caused by failing to verify
+// the certificate of an upstream endpoint.
+func DiagnoseTLSVerificationError(err error) (TLSVerificationFailure, bool) {
+	if err == nil {
+		return TLSVerificationFailure{}, false
+	}
+	var (
+		unknownAuthorityErr x509.UnknownAuthorityError
+		hostnameErr         x509.HostnameError
+		invalidErr          x509.CertificateInvalidError
+	)
+	switch {
+	case errors.As(err, &unknownAuthorityErr):
+		return TLSVerificationFailure{
+			Reason: TLSVerificationUnknownAuthority,
+			Detail: fmt.Sprintf("certificate is not signed by caData in spec.clientConfig, %s", describeCertificate(unknownAuthorityErr.Cert)),
+		}, true
+	case errors.As(err, &hostnameErr):
+		return TLSVerificationFailure{
+			Reason: TLSVerificationHostnameMismatch,
+			Detail: fmt.Sprintf("certificate is not valid for server name %q, %s", hostnameErr.Host, describeCertificate(hostnameErr.Certificate)),
+		}, true
+	case errors.As(err, &invalidErr):
+		failure := TLSVerificationFailure{
+			Reason: TLSVerificationInvalid,
+			Detail: fmt.Sprintf("%v, %s", invalidErr, describeCertificate(invalidErr.Cert)),
+		}
+		if invalidErr.Reason == x509.Expired && invalidErr.Cert != nil {
+			now := time.Now()
+			if now.Before(invalidErr.Cert.NotBefore) {
+				failure.Reason = TLSVerificationNotYetValid
+				failure.Detail = fmt.Sprintf("certificate is not valid until %s, check the clock of gateway, %s",
+					invalidErr.Cert.NotBefore.UTC().Format(time.RFC3339), describeCertificate(invalidErr.Cert))
+			} else {
+				failure.Reason = TLSVerificationExpired
+				failure.Detail = fmt.Sprintf("certificate expired at %s, %s",
+					invalidErr.Cert.NotAfter.UTC().Format(time.RFC3339), describeCertificate(invalidErr.Cert))
+			}
+		}
+		return failure, true
+	}
+	return TLSVerificationFailure{}, false
+}
+
+// describeCertificate returns fields of the certificate which are useful to debug
+// verification failures.
+func describeCertificate(cert *x509.Certificate) string {
+	if cert == nil {
+		return "certificate=<unknown>"
+	}
+	var sans []string
+	sans = append(sans, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	return fmt.Sprintf("certificate subject=%q issuer=%q sans=[%s] notBefore=%s notAfter=%s serial=%s",
+		cert.Subject.String(),
+		cert.Issuer.String(),
+		strings.Join(sans, ","),
+		cert.NotBefore.UTC().Format(time.RFC3339),
+		cert.NotAfter.UTC().Format(time.RFC3339),
+		cert.SerialNumber.Text(16),
+	)
+}
+
+// RecordTLSVerificationError logs diagnostics and records metrics if err is caused by failing
+// to verify the certificate of the upstream endpoint, source is one of proxy and health_check.
+// It returns false if err is not a verification failure.
+func RecordTLSVerificationError(cluster, endpoint, source string, err error) (TLSVerificationFailure, bool) {
+	failure, ok := DiagnoseTLSVerificationError(err)
+	if !ok {
+		return failure, false
+	}
+	klog.Errorf("[upstream tls] failed to verify certificate of upstream, cluster=%q endpoint=%q source=%q reason=%q: %s",
+		cluster, endpoint, source, failure.Reason, failure.Detail)
+	metrics.RecordUpstreamTLSVerificationFailure(cluster, endpoint, source, failure.Reason)
+	return failure, true
+}
@@ -0,0 +1,112 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusters
+
+import (
+	"crypto/x509"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiagnoseTLSVerificationError(t *testing.T) {
+	cert := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		DNSNames:     []string{"kubernetes.default"},
+		NotBefore:    time.Now().Add(-2 * time.Hour),
+		NotAfter:     time.Now().Add(-time.Hour),
+	}
+	future := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		NotBefore:    time.Now().Add(time.Hour),
+		NotAfter:     time.Now().Add(2 * time.Hour),
+	}
+	tests := []struct {
+		name       string
+		err        error
+		wantReason string
+		wantDetail string
+		wantOK     bool
+	}{
+		{"nil", nil, "", "", false},
+		{"not a tls error", errors.New("connection refused"), "", "", false},
+		{
+			name:       "unknown authority",
+			err:        &url.Error{Op: "Get", URL: "https://127.0.0.1", Err: x509.UnknownAuthorityError{Cert: cert}},
+			wantReason: TLSVerificationUnknownAuthority,
+			wantDetail: "sans=[kubernetes.default]",
+			wantOK:     true,
+		},
+		{
+			name:       "hostname mismatch",
+			err:        &url.Error{Op: "Get", URL: "https://127.0.0.1", Err: x509.HostnameError{Certificate: cert, Host: "testing.cluster"}},
+			wantReason: TLSVerificationHostnameMismatch,
+			wantDetail: `server name "testing.cluster"`,
+			wantOK:     true,
+		},
+		{
+			name:       "expired",
+			err:        x509.CertificateInvalidError{Cert: cert, Reason: x509.Expired},
+			wantReason: TLSVerificationExpired,
+			wantDetail: "certificate expired at",
+			wantOK:     true,
+		},
+		{
+			name:       "not yet valid",
+			err:        x509.CertificateInvalidError{Cert: future, Reason: x509.Expired},
+			wantReason: TLSVerificationNotYetValid,
+			wantDetail: "check the clock",
+			wantOK:     true,
+		},
+		{
+			name:       "invalid",
+			err:        x509.CertificateInvalidError{Cert: cert, Reason: x509.NotAuthorizedToSign},
+			wantReason: TLSVerificationInvalid,
+			wantOK:     true,
+		},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := DiagnoseTLSVerificationError(tt.err)
+			if ok != tt.wantOK {
+				t.Fatalf("DiagnoseTLSVerificationError() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if got.Reason != tt.wantReason {
+				t.Errorf("DiagnoseTLSVerificationError() reason = %v, want %v", got.Reason, tt.wantReason)
+			}
+			if !strings.Contains(got.Detail, tt.wantDetail) {
+				t.Errorf("DiagnoseTLSVerificationError() detail = %v, want it contains %v", got.Detail, tt.wantDetail)
+			}
+		})
+	}
+}
+
+func TestDiagnoseTLSVerificationError_handshake(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}))
+	defer server.Close()
+
+	// the default client does not trust the test server certificate
+	_, err := http.Get(server.URL)
+	failure, ok := DiagnoseTLSVerificationError(err)
+	if !ok || failure.Reason != TLSVerificationUnknownAuthority {
+		t.Errorf("DiagnoseTLSVerificationError(%v) = %v, %v, want reason %v", err, failure, ok, TLSVerificationUnknownAuthority)
+	}
+}
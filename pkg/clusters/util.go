@@ -15,17 +15,26 @@
 package clusters
 
 import (
+	"context"
+	"crypto/tls"
 	"fmt"
 	"net"
+	"net/http"
 	"net/url"
 	"time"
 
+	"golang.org/x/net/http2"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/util/flowcontrol"
 
 	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
 )
 
+// upstreamIdleConnsPerHost is the same as the one of transports built by client-go
+const upstreamIdleConnsPerHost = 25
+
 func buildClusterRESTConfig(cluster *proxyv1alpha1.UpstreamCluster) (*rest.Config, error) {
 	httpScheme := "https"
 	if len(cluster.Spec.Servers) > 0 {
@@ -39,6 +48,14 @@ func buildClusterRESTConfig(cluster *proxyv1alpha1.UpstreamCluster) (*rest.Confi
 	}
 
 	cfg := newRESTConfig()
+	if address := cluster.Spec.ClientConfig.SourceAddress; len(address) > 0 {
+		ip := net.ParseIP(address)
+		if ip == nil {
+			return nil, fmt.Errorf("spec.clientConfig.sourceAddress: invalid IP address %q", address)
+		}
+		cfg.Dial = newUpstreamDialer(ip).DialContext
+	}
+	cfg.Dial = trackConnections(cluster.Name, httpScheme, cfg.Dial)
 	cfg.BearerToken = string(cluster.Spec.ClientConfig.BearerToken)
 
 	if cluster.Spec.ClientConfig.QPS > 0 {
@@ -59,6 +76,104 @@ func buildClusterRESTConfig(cluster *proxyv1alpha1.UpstreamCluster) (*rest.Confi
 	return cfg, nil
 }
 
+// upstreamTLSSettings holds client TLS settings of upstream connections
+// which are not supported by rest.Config.
+type upstreamTLSSettings struct {
+	// sessionCache is nil if session resumption is disabled
+	sessionCache  tls.ClientSessionCache
+	renegotiation tls.RenegotiationSupport
+}
+
+func newUpstreamTLSSettings(clientConfig proxyv1alpha1.ClientConfig) upstreamTLSSettings {
+	settings := upstreamTLSSettings{
+		renegotiation: tls.RenegotiateNever,
+	}
+	if clientConfig.TLSSessionCacheSize > 0 {
+		// the session cache is shared by endpoints of a cluster because they have
+		// the same tls server name
+		settings.sessionCache = tls.NewLRUClientSessionCache(int(clientConfig.TLSSessionCacheSize))
+	}
+	switch clientConfig.TLSRenegotiation {
+	case proxyv1alpha1.TLSRenegotiateOnceAsClient:
+		settings.renegotiation = tls.RenegotiateOnceAsClient
+	case proxyv1alpha1.TLSRenegotiateFreelyAsClient:
+		settings.renegotiation = tls.RenegotiateFreelyAsClient
+	}
+	return settings
+}
+
+// wrapTransport returns a rest.Config WrapTransport func which applies the settings to the
+// underlying *http.Transport before wrapping it with the next wrapper.
+func (s upstreamTLSSettings) wrapTransport(next func(http.RoundTripper) http.RoundTripper) func(http.RoundTripper) http.RoundTripper {
+	return func(rt http.RoundTripper) http.RoundTripper {
+		// the transport may be already in use if it is cached, only write it when changed
+		if t, ok := rt.(*http.Transport); ok && t.TLSClientConfig != nil &&
+			(t.TLSClientConfig.ClientSessionCache != s.sessionCache || t.TLSClientConfig.Renegotiation != s.renegotiation) {
+			t.TLSClientConfig.ClientSessionCache = s.sessionCache
+			t.TLSClientConfig.Renegotiation = s.renegotiation
+		}
+		return next(rt)
+	}
+}
+
+// disableKeepAlivesWrapper returns a rest.Config WrapTransport func which closes upstream
+// connections after each request. Requests are marked to close instead of changing the
+// *http.Transport, so that it works with any transport, e.g. h2c.
+func disableKeepAlivesWrapper(next func(http.RoundTripper) http.RoundTripper) func(http.RoundTripper) http.RoundTripper {
+	return func(rt http.RoundTripper) http.RoundTripper {
+		return &closeConnectionRoundTripper{delegate: next(rt)}
+	}
+}
+
+type closeConnectionRoundTripper struct {
+	delegate http.RoundTripper
+}
+
+func (rt *closeConnectionRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	// upgraded connections are not reused anyway
+	if !req.Close && !httpstream.IsUpgradeRequest(req) {
+		req = utilnet.CloneRequest(req)
+		req.Close = true
+	}
+	return rt.delegate.RoundTrip(req)
+}
+
+func (rt *closeConnectionRoundTripper) WrappedRoundTripper() http.RoundTripper {
+	return rt.delegate
+}
+
+// newH2CTransport returns a transport which speaks HTTP/2 with prior knowledge over
+// plaintext connections created by dial.
+// setUpstreamTransport sets the transport of config which is built like client-go does but is
+// not cached, the tls options are moved into the transport. client-go caches transports by the
+// address of the dial func, which is the same for all closures, so a cached transport may dial
+// from the source address of another config.
+func setUpstreamTransport(config *rest.Config) error {
+	tlsConfig, err := rest.TLSConfigFor(config)
+	if err != nil {
+		return err
+	}
+	config.Transport = utilnet.SetTransportDefaults(&http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		TLSHandshakeTimeout: 10 * time.Second,
+		TLSClientConfig:     tlsConfig,
+		MaxIdleConnsPerHost: upstreamIdleConnsPerHost,
+		DialContext:         config.Dial,
+		DisableCompression:  config.DisableCompression,
+	})
+	config.TLSClientConfig = rest.TLSClientConfig{}
+	return nil
+}
+
+func newH2CTransport(dial func(ctx context.Context, network, address string) (net.Conn, error)) http.RoundTripper {
+	return &http2.Transport{
+		AllowHTTP: true,
+		DialTLS: func(network, addr string, _ *tls.Config) (net.Conn, error) {
+			return dial(context.Background(), network, addr)
+		},
+	}
+}
+
 func calQPS(qps int32, qpsDivisor int32) float32 {
 	ret := float32(qps)
 	if qpsDivisor > 1 {
@@ -71,12 +186,31 @@ func newRESTConfig() *rest.Config {
 	cfg := &rest.Config{
 		Timeout:     5 * time.Second,
 		RateLimiter: flowcontrol.NewFakeAlwaysRateLimiter(),
-		Dial: (&net.Dialer{
-			Timeout:   5 * time.Second,
-			KeepAlive: 30 * time.Second,
-		}).DialContext,
+		Dial:        newUpstreamDialer(nil).DialContext,
 	}
 
 	rest.AddUserAgent(cfg, "kube-gateway")
 	return cfg
 }
+
+// newUpstreamDialer returns the dialer of upstream connections, connections are dialed from
+// sourceIP if it is not nil.
+func newUpstreamDialer(sourceIP net.IP) *net.Dialer {
+	dialer := &net.Dialer{
+		Timeout:   5 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	if sourceIP != nil {
+		dialer.LocalAddr = &net.TCPAddr{IP: sourceIP}
+	}
+	return dialer
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
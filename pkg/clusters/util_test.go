@@ -14,7 +14,15 @@
 
 package clusters
 
-import "testing"
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"testing"
+
+	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
+)
 
 func Test_calQPS(t *testing.T) {
 	type args struct {
@@ -67,3 +75,175 @@ func Test_calQPS(t *testing.T) {
 		})
 	}
 }
+
+func Test_upstreamTLSSettings_wrapTransport(t *testing.T) {
+	tests := []struct {
+		name              string
+		clientConfig      proxyv1alpha1.ClientConfig
+		wantSessionCache  bool
+		wantRenegotiation tls.RenegotiationSupport
+	}{
+		{
+			"default",
+			proxyv1alpha1.ClientConfig{},
+			false,
+			tls.RenegotiateNever,
+		},
+		{
+			"session cache",
+			proxyv1alpha1.ClientConfig{TLSSessionCacheSize: 10},
+			true,
+			tls.RenegotiateNever,
+		},
+		{
+			"renegotiate once",
+			proxyv1alpha1.ClientConfig{TLSRenegotiation: proxyv1alpha1.TLSRenegotiateOnceAsClient},
+			false,
+			tls.RenegotiateOnceAsClient,
+		},
+		{
+			"renegotiate freely",
+			proxyv1alpha1.ClientConfig{TLSRenegotiation: proxyv1alpha1.TLSRenegotiateFreelyAsClient},
+			false,
+			tls.RenegotiateFreelyAsClient,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := &http.Transport{TLSClientConfig: &tls.Config{}}
+			wrapped := false
+			newUpstreamTLSSettings(tt.clientConfig).wrapTransport(func(rt http.RoundTripper) http.RoundTripper {
+				wrapped = true
+				return rt
+			})(rt)
+
+			if !wrapped {
+				t.Errorf("wrapTransport() should call next wrapper")
+			}
+			if got := rt.TLSClientConfig.ClientSessionCache != nil; got != tt.wantSessionCache {
+				t.Errorf("wrapTransport() session cache = %v, want %v", got, tt.wantSessionCache)
+			}
+			if rt.TLSClientConfig.Renegotiation != tt.wantRenegotiation {
+				t.Errorf("wrapTransport() renegotiation = %v, want %v", rt.TLSClientConfig.Renegotiation, tt.wantRenegotiation)
+			}
+		})
+	}
+}
+
+func Test_closeConnectionRoundTripper(t *testing.T) {
+	tests := []struct {
+		name      string
+		header    http.Header
+		wantClose bool
+	}{
+		{
+			"normal request",
+			http.Header{},
+			true,
+		},
+		{
+			"upgrade request",
+			http.Header{"Connection": []string{"Upgrade"}, "Upgrade": []string{"SPDY/3.1"}},
+			false,
+		},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			var got *http.Request
+			rt := disableKeepAlivesWrapper(func(rt http.RoundTripper) http.RoundTripper {
+				return rt
+			})(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				got = req
+				return &http.Response{StatusCode: http.StatusOK}, nil
+			}))
+
+			req, _ := http.NewRequest(http.MethodGet, "https://127.0.0.1:6443/api", nil)
+			req.Header = tt.header
+			if _, err := rt.RoundTrip(req); err != nil {
+				t.Fatalf("RoundTrip() error = %v", err)
+			}
+			if got.Close != tt.wantClose {
+				t.Errorf("RoundTrip() request close = %v, want %v", got.Close, tt.wantClose)
+			}
+			if req.Close {
+				t.Errorf("RoundTrip() should not modify the original request")
+			}
+		})
+	}
+}
+
+func Test_buildClusterRESTConfig_sourceAddress(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	tests := []struct {
+		name          string
+		sourceAddress string
+		wantSourceIP  string
+		wantErr       bool
+	}{
+		{
+			"default interface",
+			"",
+			"127.0.0.1",
+			false,
+		},
+		{
+			"bound to source address",
+			"127.0.0.2",
+			"127.0.0.2",
+			false,
+		},
+		{
+			"invalid source address",
+			"localhost",
+			"",
+			true,
+		},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := &proxyv1alpha1.UpstreamCluster{
+				Spec: proxyv1alpha1.UpstreamClusterSpec{
+					Servers:      []proxyv1alpha1.UpstreamClusterServer{{Endpoint: "http://" + listener.Addr().String()}},
+					ClientConfig: proxyv1alpha1.ClientConfig{SourceAddress: tt.sourceAddress},
+				},
+			}
+			cfg, err := buildClusterRESTConfig(cluster)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("buildClusterRESTConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			conn, err := cfg.Dial(context.TODO(), "tcp", listener.Addr().String())
+			if err != nil {
+				t.Fatalf("Dial() error = %v", err)
+			}
+			defer conn.Close()
+			if got := conn.LocalAddr().(*net.TCPAddr).IP.String(); got != tt.wantSourceIP {
+				t.Errorf("Dial() source IP = %v, want %v", got, tt.wantSourceIP)
+			}
+		})
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -17,9 +17,10 @@ package flowcontrol
 import (
 	"fmt"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/zoumo/golib/lock/maxinflight"
-	"k8s.io/client-go/util/flowcontrol"
 
 	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
 )
@@ -50,6 +51,13 @@ func (f *FlowControls) Delete(name string) {
 	f.data.Delete(name)
 }
 
+// Range calls fn for each flow control, it stops if fn returns false.
+func (f *FlowControls) Range(fn func(name string, fl FlowControl) bool) {
+	f.data.Range(func(key, value interface{}) bool {
+		return fn(key.(string), value.(FlowControl))
+	})
+}
+
 func (f *FlowControls) Len() int {
 	length := 0
 	f.data.Range(func(key, value interface{}) bool {
@@ -69,15 +77,80 @@ type FlowControl interface {
 	Resize(n uint32, burst uint32) bool
 	// String returns human readable string.
 	String() string
+	// Name returns the flow control schema name
+	Name() string
+	// Type returns the flow control schema type
+	Type() proxyv1alpha1.FlowControlSchemaType
+	// Status returns a snapshot of the live state
+	Status() Status
+}
+
+// Status is a snapshot of the live state of a flow control
+type Status struct {
+	Name string                              `json:"name"`
+	Type proxyv1alpha1.FlowControlSchemaType `json:"type"`
+	// Max is the capacity of MaxRequestsInflight, or the safety ceiling of Exempt
+	Max   uint32 `json:"max,omitempty"`
+	QPS   uint32 `json:"qps,omitempty"`
+	Burst uint32 `json:"burst,omitempty"`
+	// AvailableTokens is the number of requests which can be accepted immediately,
+	// it is not set if there is no limit or each key has its own limit.
+	AvailableTokens *float64 `json:"availableTokens,omitempty"`
+	// Inflight is the number of accepted requests which are not released
+	Inflight int64 `json:"inflight"`
+	// Rejected is the number of rejected requests since the flow control is created
+	Rejected uint64 `json:"rejected"`
+	// Queued is the number of requests waiting in queues of Queued
+	Queued int64 `json:"queued,omitempty"`
+	// KeyBy is the key of requests which share a token bucket of keyed TokenBucket
+	KeyBy proxyv1alpha1.TokenBucketKey `json:"keyBy,omitempty"`
+	// Keys is the number of keys which have a token bucket of keyed TokenBucket
+	Keys int `json:"keys,omitempty"`
+	// Overridden is true if parameters of the schema are overridden at runtime
+	Overridden bool `json:"overridden,omitempty"`
+	// Mutating is the status of mutating requests of MaxRequestsInflightByVerb, the other
+	// fields are of read-only requests
+	Mutating *Status `json:"mutating,omitempty"`
+}
+
+// counter counts inflight and rejected requests of a flow control
+type counter struct {
+	inflight int64
+	rejected uint64
+}
+
+func (c *counter) acquired(ok bool) bool {
+	if ok {
+		atomic.AddInt64(&c.inflight, 1)
+	} else {
+		atomic.AddUint64(&c.rejected, 1)
+	}
+	return ok
+}
+
+func (c *counter) released() {
+	atomic.AddInt64(&c.inflight, -1)
+}
+
+func (c *counter) status(name string, typ proxyv1alpha1.FlowControlSchemaType) Status {
+	return Status{
+		Name:     name,
+		Type:     typ,
+		Inflight: atomic.LoadInt64(&c.inflight),
+		Rejected: atomic.LoadUint64(&c.rejected),
+	}
 }
 
 var (
-	DefaultFlowControl = NewFlowControl(proxyv1alpha1.FlowControlSchema{
+	// DefaultFlowControlSchema is used by dispatch policies which do not reference a flow control schema
+	DefaultFlowControlSchema = proxyv1alpha1.FlowControlSchema{
 		Name: "system-default",
 		FlowControlSchemaConfiguration: proxyv1alpha1.FlowControlSchemaConfiguration{
 			Exempt: &proxyv1alpha1.ExemptFlowControlSchema{},
 		},
-	})
+	}
+
+	DefaultFlowControl = NewFlowControl(DefaultFlowControlSchema)
 )
 
 func GuessFlowControlSchemaType(config proxyv1alpha1.FlowControlSchema) proxyv1alpha1.FlowControlSchemaType {
@@ -88,6 +161,10 @@ func GuessFlowControlSchemaType(config proxyv1alpha1.FlowControlSchema) proxyv1a
 		return proxyv1alpha1.MaxRequestsInflight
 	case config.TokenBucket != nil:
 		return proxyv1alpha1.TokenBucket
+	case config.MaxRequestsInflightByVerb != nil:
+		return proxyv1alpha1.MaxRequestsInflightByVerb
+	case config.Queued != nil:
+		return proxyv1alpha1.Queued
 	}
 	return proxyv1alpha1.Exempt
 }
@@ -103,69 +180,190 @@ func NewFlowControl(schema proxyv1alpha1.FlowControlSchema) FlowControl {
 			typ:         typ,
 			max:         uint32(schema.MaxRequestsInflight.Max),
 		}
+	case proxyv1alpha1.MaxRequestsInflightByVerb:
+		return newVerbFlowControl(name, uint32(schema.MaxRequestsInflightByVerb.MaxReadOnly), uint32(schema.MaxRequestsInflightByVerb.MaxMutating))
+	case proxyv1alpha1.Queued:
+		return newQueuedFlowControl(name, schema.Queued)
 	case proxyv1alpha1.TokenBucket:
+		if schema.TokenBucket.KeyBy != "" {
+			return newKeyedTokenBucket(name, schema.TokenBucket)
+		}
+		rateLimiter := newTokenBucket(float64(schema.TokenBucket.QPS), float64(schema.TokenBucket.Burst))
+		rateLimiter.SetSlowStart(time.Duration(schema.TokenBucket.SlowStartSeconds) * time.Second)
 		return &resizeableTokenBucket{
-			rateLimiter: flowcontrol.NewTokenBucketRateLimiter(float32(schema.TokenBucket.QPS), int(schema.TokenBucket.Burst)),
+			rateLimiter: rateLimiter,
 			name:        name,
 			typ:         typ,
-			qps:         uint32(schema.TokenBucket.QPS),
-			burst:       uint32(schema.TokenBucket.Burst),
 		}
 	}
-	return &flowControl{
-		TokenBucket: maxinflight.InfinityTokenBucket,
-		name:        name,
-		typ:         typ,
+	ret := &exemptFlowControl{
+		name: name,
+	}
+	if schema.Exempt != nil {
+		ret.max = uint32(schema.Exempt.MaxRequestsInflight)
 	}
+	return ret
 }
 
 type flowControl struct {
 	maxinflight.TokenBucket
+	counter
 	name string
 	typ  proxyv1alpha1.FlowControlSchemaType
 	max  uint32
 }
 
+func (f *flowControl) TryAcquire() bool {
+	return f.acquired(f.TokenBucket.TryAcquire())
+}
+
+func (f *flowControl) Release() {
+	f.TokenBucket.Release()
+	f.released()
+}
+
+func (f *flowControl) Status() Status {
+	status := f.status(f.name, f.typ)
+	status.Max = atomic.LoadUint32(&f.max)
+	available := float64(int64(status.Max) - status.Inflight)
+	if available < 0 {
+		available = 0
+	}
+	status.AvailableTokens = &available
+	return status
+}
+
 func (f *flowControl) String() string {
 	return fmt.Sprintf("name=%v,type=%v,size=%v", f.name, f.typ, f.max)
 }
 
+func (f *flowControl) Name() string {
+	return f.name
+}
+
+func (f *flowControl) Type() proxyv1alpha1.FlowControlSchemaType {
+	return f.typ
+}
+
 func (f *flowControl) Resize(n uint32, burst uint32) bool {
 	resized := false
-	if f.max != n {
+	if atomic.LoadUint32(&f.max) != n {
 		f.TokenBucket.Resize(n)
-		f.max = n
+		atomic.StoreUint32(&f.max, n)
 		resized = true
 	}
 	return resized
 }
 
 type resizeableTokenBucket struct {
-	rateLimiter flowcontrol.RateLimiter
+	counter
+	rateLimiter *tokenBucket
 	name        string
 	typ         proxyv1alpha1.FlowControlSchemaType
-	qps         uint32
-	burst       uint32
 }
 
 func (f *resizeableTokenBucket) TryAcquire() bool {
-	return f.rateLimiter.TryAccept()
+	return f.acquired(f.rateLimiter.TryAccept())
 }
 
 func (f *resizeableTokenBucket) String() string {
-	return fmt.Sprintf("name=%v,type=%v,qps=%v,burst=%v", f.name, f.typ, f.qps, f.burst)
+	qps, burst := f.rateLimiter.Size()
+	return fmt.Sprintf("name=%v,type=%v,qps=%v,burst=%v", f.name, f.typ, qps, burst)
+}
+
+func (f *resizeableTokenBucket) Status() Status {
+	status := f.status(f.name, f.typ)
+	qps, burst := f.rateLimiter.Size()
+	status.QPS, status.Burst = uint32(qps), uint32(burst)
+	available := f.rateLimiter.Available()
+	status.AvailableTokens = &available
+	return status
+}
+
+func (f *resizeableTokenBucket) Name() string {
+	return f.name
+}
+
+func (f *resizeableTokenBucket) Type() proxyv1alpha1.FlowControlSchemaType {
+	return f.typ
 }
 
 func (f *resizeableTokenBucket) Resize(n uint32, burst uint32) bool {
-	resized := false
-	if f.qps != n || f.burst != burst {
-		f.rateLimiter = flowcontrol.NewTokenBucketRateLimiter(float32(n), int(burst))
-		f.qps = n
-		f.burst = burst
-		resized = true
+	return f.rateLimiter.Resize(float64(n), float64(burst))
+}
+
+// SetSlowStart sets the duration for a TokenBucket flow control to ramp up after its qps or burst
+// is raised by the next Resize, it is a no-op for flow controls of other types.
+func SetSlowStart(fc FlowControl, d time.Duration) {
+	switch f := fc.(type) {
+	case *resizeableTokenBucket:
+		f.rateLimiter.SetSlowStart(d)
+	case *keyedTokenBucket:
+		f.setSlowStart(d)
 	}
-	return resized
 }
 
 func (f *resizeableTokenBucket) Release() {
+	f.released()
+}
+
+// exemptFlowControl does not limit requests unless the safety ceiling max is set,
+// but it always counts requests in flight.
+type exemptFlowControl struct {
+	name     string
+	max      uint32
+	inflight uint32
+	rejected uint64
+}
+
+func (f *exemptFlowControl) TryAcquire() bool {
+	for {
+		max := atomic.LoadUint32(&f.max)
+		inflight := atomic.LoadUint32(&f.inflight)
+		if max > 0 && inflight >= max {
+			atomic.AddUint64(&f.rejected, 1)
+			return false
+		}
+		if atomic.CompareAndSwapUint32(&f.inflight, inflight, inflight+1) {
+			return true
+		}
+	}
+}
+
+func (f *exemptFlowControl) Release() {
+	atomic.AddUint32(&f.inflight, ^uint32(0))
+}
+
+func (f *exemptFlowControl) Resize(n uint32, burst uint32) bool {
+	return atomic.SwapUint32(&f.max, n) != n
+}
+
+func (f *exemptFlowControl) String() string {
+	return fmt.Sprintf("name=%v,type=%v,size=%v", f.name, proxyv1alpha1.Exempt, atomic.LoadUint32(&f.max))
+}
+
+func (f *exemptFlowControl) Status() Status {
+	status := Status{
+		Name:     f.name,
+		Type:     proxyv1alpha1.Exempt,
+		Max:      atomic.LoadUint32(&f.max),
+		Inflight: int64(atomic.LoadUint32(&f.inflight)),
+		Rejected: atomic.LoadUint64(&f.rejected),
+	}
+	if status.Max > 0 {
+		available := float64(int64(status.Max) - status.Inflight)
+		if available < 0 {
+			available = 0
+		}
+		status.AvailableTokens = &available
+	}
+	return status
+}
+
+func (f *exemptFlowControl) Name() string {
+	return f.name
+}
+
+func (f *exemptFlowControl) Type() proxyv1alpha1.FlowControlSchemaType {
+	return proxyv1alpha1.Exempt
 }
@@ -0,0 +1,114 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowcontrol
+
+import (
+	"testing"
+
+	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
+)
+
+func TestExemptFlowControl(t *testing.T) {
+	tests := []struct {
+		name        string
+		max         int32
+		acquire     int
+		wantAllowed int
+	}{
+		{"no ceiling", 0, 100, 100},
+		{"capped by ceiling", 10, 100, 10},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			fc := NewFlowControl(proxyv1alpha1.FlowControlSchema{
+				Name: "exempt",
+				FlowControlSchemaConfiguration: proxyv1alpha1.FlowControlSchemaConfiguration{
+					Exempt: &proxyv1alpha1.ExemptFlowControlSchema{MaxRequestsInflight: tt.max},
+				},
+			})
+			if fc.Type() != proxyv1alpha1.Exempt {
+				t.Fatalf("Type() = %v, want %v", fc.Type(), proxyv1alpha1.Exempt)
+			}
+			allowed := 0
+			for j := 0; j < tt.acquire; j++ {
+				if fc.TryAcquire() {
+					allowed++
+				}
+			}
+			if allowed != tt.wantAllowed {
+				t.Errorf("allowed = %v, want %v", allowed, tt.wantAllowed)
+			}
+			// released tokens can be acquired again
+			fc.Release()
+			if !fc.TryAcquire() {
+				t.Errorf("TryAcquire() after Release() = false, want true")
+			}
+		})
+	}
+}
+
+func TestFlowControlStatus(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        proxyv1alpha1.FlowControlSchemaConfiguration
+		acquire       int
+		wantInflight  int64
+		wantRejected  uint64
+		wantAvailable float64
+	}{
+		{
+			name:          "max requests inflight",
+			config:        proxyv1alpha1.FlowControlSchemaConfiguration{MaxRequestsInflight: &proxyv1alpha1.MaxRequestsInflightFlowControlSchema{Max: 3}},
+			acquire:       5,
+			wantInflight:  3,
+			wantRejected:  2,
+			wantAvailable: 0,
+		},
+		{
+			name:          "token bucket",
+			config:        proxyv1alpha1.FlowControlSchemaConfiguration{TokenBucket: &proxyv1alpha1.TokenBucketFlowControlSchema{QPS: 1, Burst: 4}},
+			acquire:       2,
+			wantInflight:  2,
+			wantRejected:  0,
+			wantAvailable: 2,
+		},
+		{
+			name:          "queued",
+			config:        proxyv1alpha1.FlowControlSchemaConfiguration{Queued: &proxyv1alpha1.QueuedFlowControlSchema{Concurrency: 3}},
+			acquire:       5,
+			wantInflight:  3,
+			wantRejected:  2,
+			wantAvailable: 0,
+		},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			fc := NewFlowControl(proxyv1alpha1.FlowControlSchema{Name: "test", FlowControlSchemaConfiguration: tt.config})
+			for j := 0; j < tt.acquire; j++ {
+				fc.TryAcquire()
+			}
+			status := fc.Status()
+			if status.Inflight != tt.wantInflight || status.Rejected != tt.wantRejected {
+				t.Errorf("Status() inflight = %v, rejected = %v, want %v, %v", status.Inflight, status.Rejected, tt.wantInflight, tt.wantRejected)
+			}
+			// token bucket may be refilled a little while testing
+			if status.AvailableTokens == nil || *status.AvailableTokens < tt.wantAvailable || *status.AvailableTokens > tt.wantAvailable+0.5 {
+				t.Errorf("Status() availableTokens = %v, want %v", status.AvailableTokens, tt.wantAvailable)
+			}
+		})
+	}
+}
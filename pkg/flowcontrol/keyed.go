@@ -0,0 +1,239 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowcontrol
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/authentication/user"
+
+	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
+)
+
+const (
+	// maxTokenBucketKeys is the maximum number of keys which have a token bucket in a keyed
+	// TokenBucket flow control, the least recently used one is dropped when it is exceeded
+	maxTokenBucketKeys = 10000
+	// tokenBucketKeyIdleTTL is how long the token bucket of an idle key is kept
+	tokenBucketKeyIdleTTL = 10 * time.Minute
+)
+
+// Flow identifies where a request comes from, flow controls may limit flows separately
+type Flow struct {
+	// User is the name of the authenticated user
+	User string
+	// Groups are the groups of the authenticated user
+	Groups []string
+	// SourceIP is the client IP
+	SourceIP string
+}
+
+// keyedTokenBucket is a TokenBucket flow control which keeps a token bucket of the same qps and
+// burst for each key of flows. Buckets are kept in a bounded LRU and dropped once they are idle
+// for tokenBucketKeyIdleTTL, a dropped key gets a full bucket when it comes back.
+type keyedTokenBucket struct {
+	counter
+	name  string
+	keyBy proxyv1alpha1.TokenBucketKey
+	now   func() time.Time
+
+	lock      sync.Mutex
+	qps       float64
+	burst     float64
+	slowStart time.Duration
+	// lru holds *keyedBucket, the most recently used one is at front
+	lru     *list.List
+	buckets map[string]*list.Element
+	// onKeys is called with the number of keys once it changes
+	onKeys func(keys int)
+}
+
+type keyedBucket struct {
+	key      string
+	bucket   *tokenBucket
+	lastUsed time.Time
+}
+
+func newKeyedTokenBucket(name string, schema *proxyv1alpha1.TokenBucketFlowControlSchema) *keyedTokenBucket {
+	return &keyedTokenBucket{
+		name:      name,
+		keyBy:     schema.KeyBy,
+		now:       time.Now,
+		qps:       float64(schema.QPS),
+		burst:     float64(schema.Burst),
+		slowStart: time.Duration(schema.SlowStartSeconds) * time.Second,
+		lru:       list.New(),
+		buckets:   map[string]*list.Element{},
+	}
+}
+
+// TryAcquire takes a token from the bucket shared by requests without flow
+func (f *keyedTokenBucket) TryAcquire() bool {
+	return f.acquired(f.bucket("").TryAccept())
+}
+
+// AcquireFlow takes a token from the bucket of the flow
+func (f *keyedTokenBucket) AcquireFlow(flow Flow) bool {
+	switch f.keyBy {
+	case proxyv1alpha1.TokenBucketKeySourceIP:
+		return f.acquired(f.bucket(flow.SourceIP).TryAccept())
+	case proxyv1alpha1.TokenBucketKeyGroup:
+		return f.acquired(f.acquireGroups(flow.Groups))
+	}
+	return f.acquired(f.bucket(flow.User).TryAccept())
+}
+
+// acquireGroups takes a token from the bucket of each group, tokens already taken are given
+// back if the bucket of any group is exhausted.
+func (f *keyedTokenBucket) acquireGroups(groups []string) bool {
+	keys := groupKeys(groups)
+	buckets := make([]*tokenBucket, 0, len(keys))
+	for _, key := range keys {
+		buckets = append(buckets, f.bucket(key))
+	}
+	for i, bucket := range buckets {
+		if bucket.TryAccept() {
+			continue
+		}
+		for _, taken := range buckets[:i] {
+			taken.Return()
+		}
+		return false
+	}
+	return true
+}
+
+// groupKeys returns the distinct groups which have a bucket. system:authenticated and
+// system:unauthenticated are dropped since every user belongs to one of them, users
+// without other groups share the bucket of empty key.
+func groupKeys(groups []string) []string {
+	keys := sets.NewString(groups...)
+	keys.Delete(user.AllAuthenticated, user.AllUnauthenticated)
+	if keys.Len() == 0 {
+		return []string{""}
+	}
+	return keys.List()
+}
+
+// bucket returns the token bucket of the key, it is created if the key has none
+func (f *keyedTokenBucket) bucket(key string) *tokenBucket {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	now := f.now()
+	if element, ok := f.buckets[key]; ok {
+		entry := element.Value.(*keyedBucket)
+		entry.lastUsed = now
+		f.lru.MoveToFront(element)
+		return entry.bucket
+	}
+
+	keys := f.lru.Len()
+	bucket := newTokenBucket(f.qps, f.burst)
+	bucket.now = f.now
+	bucket.last = now
+	bucket.SetSlowStart(f.slowStart)
+	f.buckets[key] = f.lru.PushFront(&keyedBucket{key: key, bucket: bucket, lastUsed: now})
+	f.evictLocked(now)
+	f.keysChangedLocked(keys)
+	return bucket
+}
+
+// evictLocked drops the least recently used buckets once they are idle or there are too many keys
+func (f *keyedTokenBucket) evictLocked(now time.Time) {
+	for back := f.lru.Back(); back != nil; back = f.lru.Back() {
+		entry := back.Value.(*keyedBucket)
+		if f.lru.Len() <= maxTokenBucketKeys && now.Sub(entry.lastUsed) < tokenBucketKeyIdleTTL {
+			break
+		}
+		f.lru.Remove(back)
+		delete(f.buckets, entry.key)
+	}
+}
+
+// keysChangedLocked notifies the observer if the number of keys is changed from before
+func (f *keyedTokenBucket) keysChangedLocked(before int) {
+	if f.onKeys != nil && f.lru.Len() != before {
+		f.onKeys(f.lru.Len())
+	}
+}
+
+func (f *keyedTokenBucket) Release() {
+	f.released()
+}
+
+// Resize changes qps and burst of the bucket of each key
+func (f *keyedTokenBucket) Resize(n uint32, burst uint32) bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.qps == float64(n) && f.burst == float64(burst) {
+		return false
+	}
+	f.qps, f.burst = float64(n), float64(burst)
+	for element := f.lru.Front(); element != nil; element = element.Next() {
+		element.Value.(*keyedBucket).bucket.Resize(f.qps, f.burst)
+	}
+	return true
+}
+
+func (f *keyedTokenBucket) setSlowStart(d time.Duration) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.slowStart = d
+	for element := f.lru.Front(); element != nil; element = element.Next() {
+		element.Value.(*keyedBucket).bucket.SetSlowStart(d)
+	}
+}
+
+func (f *keyedTokenBucket) String() string {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return fmt.Sprintf("name=%v,type=%v,qps=%v,burst=%v,keyBy=%v", f.name, proxyv1alpha1.TokenBucket, f.qps, f.burst, f.keyBy)
+}
+
+func (f *keyedTokenBucket) Name() string {
+	return f.name
+}
+
+func (f *keyedTokenBucket) Type() proxyv1alpha1.FlowControlSchemaType {
+	return proxyv1alpha1.TokenBucket
+}
+
+func (f *keyedTokenBucket) Status() Status {
+	status := f.status(f.name, proxyv1alpha1.TokenBucket)
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	keys := f.lru.Len()
+	f.evictLocked(f.now())
+	f.keysChangedLocked(keys)
+	status.QPS, status.Burst = uint32(f.qps), uint32(f.burst)
+	status.KeyBy = f.keyBy
+	status.Keys = f.lru.Len()
+	return status
+}
+
+// SetKeysObserver sets fn to be called with the number of keys which have a token bucket now and
+// once it changes, it is a no-op for flow controls which are not keyed.
+func SetKeysObserver(fc FlowControl, fn func(keys int)) {
+	if f, ok := fc.(*keyedTokenBucket); ok {
+		f.lock.Lock()
+		defer f.lock.Unlock()
+		f.onKeys = fn
+		fn(f.lru.Len())
+	}
+}
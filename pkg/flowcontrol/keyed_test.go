@@ -0,0 +1,146 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowcontrol
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
+)
+
+func TestKeyedTokenBucket_Acquire(t *testing.T) {
+	tests := []struct {
+		name  string
+		keyBy proxyv1alpha1.TokenBucketKey
+		first Flow
+		other Flow
+		// want is the result of acquiring for other after first exhausts its bucket
+		want bool
+	}{
+		{"other user", proxyv1alpha1.TokenBucketKeyUser, Flow{User: "a", SourceIP: "10.0.0.1"}, Flow{User: "b", SourceIP: "10.0.0.1"}, true},
+		{"same user", proxyv1alpha1.TokenBucketKeyUser, Flow{User: "a", SourceIP: "10.0.0.1"}, Flow{User: "a", SourceIP: "10.0.0.2"}, false},
+		{"other source ip", proxyv1alpha1.TokenBucketKeySourceIP, Flow{User: "a", SourceIP: "10.0.0.1"}, Flow{User: "a", SourceIP: "10.0.0.2"}, true},
+		{"same source ip", proxyv1alpha1.TokenBucketKeySourceIP, Flow{User: "a", SourceIP: "10.0.0.1"}, Flow{User: "b", SourceIP: "10.0.0.1"}, false},
+		{"other group", proxyv1alpha1.TokenBucketKeyGroup, Flow{User: "a", Groups: []string{"x", "system:authenticated"}}, Flow{User: "a", Groups: []string{"y", "system:authenticated"}}, true},
+		{"shared group", proxyv1alpha1.TokenBucketKeyGroup, Flow{User: "a", Groups: []string{"x"}}, Flow{User: "b", Groups: []string{"y", "x"}}, false},
+		{"no groups", proxyv1alpha1.TokenBucketKeyGroup, Flow{User: "a", Groups: []string{"system:authenticated"}}, Flow{User: "b"}, false},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Now()
+			fc := NewFlowControl(proxyv1alpha1.FlowControlSchema{
+				Name: "test",
+				FlowControlSchemaConfiguration: proxyv1alpha1.FlowControlSchemaConfiguration{
+					TokenBucket: &proxyv1alpha1.TokenBucketFlowControlSchema{QPS: 1, Burst: 2, KeyBy: tt.keyBy},
+				},
+			})
+			fc.(*keyedTokenBucket).now = func() time.Time { return now }
+
+			for i := 0; i < 2; i++ {
+				if !Acquire(context.Background(), fc, tt.first) {
+					t.Fatalf("Acquire() = false, want true within burst")
+				}
+			}
+			if Acquire(context.Background(), fc, tt.first) {
+				t.Fatalf("Acquire() = true, want false over burst")
+			}
+			if got := Acquire(context.Background(), fc, tt.other); got != tt.want {
+				t.Errorf("Acquire() of the other flow = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeyedTokenBucket_acquireGroups(t *testing.T) {
+	now := time.Now()
+	f := newKeyedTokenBucket("test", &proxyv1alpha1.TokenBucketFlowControlSchema{QPS: 1, Burst: 1, KeyBy: proxyv1alpha1.TokenBucketKeyGroup})
+	f.now = func() time.Time { return now }
+
+	if !f.AcquireFlow(Flow{User: "a", Groups: []string{"y"}}) {
+		t.Fatal("AcquireFlow() = false, want true within burst")
+	}
+	if f.AcquireFlow(Flow{User: "b", Groups: []string{"x", "y"}}) {
+		t.Fatal("AcquireFlow() = true, want false if the bucket of a group is exhausted")
+	}
+	if !f.AcquireFlow(Flow{User: "c", Groups: []string{"x"}}) {
+		t.Error("AcquireFlow() = false, want true after the token of a rejected request is given back")
+	}
+	if got := f.Status().Keys; got != 2 {
+		t.Errorf("Status().Keys = %v, want 2", got)
+	}
+}
+
+func TestKeyedTokenBucket_evict(t *testing.T) {
+	now := time.Now()
+	f := newKeyedTokenBucket("test", &proxyv1alpha1.TokenBucketFlowControlSchema{QPS: 1, Burst: 1, KeyBy: proxyv1alpha1.TokenBucketKeyUser})
+	f.now = func() time.Time { return now }
+	var observed int
+	SetKeysObserver(f, func(keys int) {
+		observed = keys
+	})
+
+	f.AcquireFlow(Flow{User: "idle"})
+	now = now.Add(tokenBucketKeyIdleTTL / 2)
+	f.AcquireFlow(Flow{User: "active"})
+	if observed != 2 {
+		t.Errorf("observed keys = %v, want 2", observed)
+	}
+
+	now = now.Add(tokenBucketKeyIdleTTL / 2)
+	if got := f.Status().Keys; got != 1 {
+		t.Errorf("Status().Keys = %v, want 1 after the idle key is evicted", got)
+	}
+	if observed != 1 {
+		t.Errorf("observed keys = %v, want 1", observed)
+	}
+	if !f.AcquireFlow(Flow{User: "idle"}) {
+		t.Error("AcquireFlow() = false, want true with a new bucket after the idle key is evicted")
+	}
+
+	for i := 0; i < maxTokenBucketKeys; i++ {
+		f.AcquireFlow(Flow{User: strconv.Itoa(i)})
+	}
+	if observed != maxTokenBucketKeys {
+		t.Errorf("observed keys = %v, want %v", observed, maxTokenBucketKeys)
+	}
+}
+
+func TestKeyedTokenBucket_Resize(t *testing.T) {
+	now := time.Now()
+	f := newKeyedTokenBucket("test", &proxyv1alpha1.TokenBucketFlowControlSchema{QPS: 1, Burst: 1, KeyBy: proxyv1alpha1.TokenBucketKeyUser})
+	f.now = func() time.Time { return now }
+	f.AcquireFlow(Flow{User: "a"})
+
+	if !f.Resize(10, 10) {
+		t.Fatal("Resize() = false, want true")
+	}
+	if f.Resize(10, 10) {
+		t.Error("Resize() = true, want false if the size is not changed")
+	}
+	now = now.Add(time.Second)
+	for i := 0; i < 10; i++ {
+		if !f.AcquireFlow(Flow{User: "a"}) {
+			t.Fatalf("AcquireFlow() = false at %d, want true after the bucket of an existing key is resized", i)
+		}
+	}
+	status := f.Status()
+	if status.QPS != 10 || status.Burst != 10 || status.KeyBy != proxyv1alpha1.TokenBucketKeyUser {
+		t.Errorf("Status() = %+v, want qps 10, burst 10 keyed by user", status)
+	}
+}
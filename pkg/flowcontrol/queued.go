@@ -0,0 +1,274 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowcontrol
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
+)
+
+const (
+	defaultQueueLength = 50
+	defaultQueues      = 64
+	defaultHandSize    = 8
+	// defaultMaxWait is how long a request waits in queue at most before it is rejected
+	defaultMaxWait = 15 * time.Second
+)
+
+// queuedRequest is a request waiting in a queue, admitted is closed once it is admitted
+type queuedRequest struct {
+	admitted chan struct{}
+}
+
+// queuedFlowControl limits requests in flight like flowControl, but requests over the limit
+// wait in queues and are admitted as capacity frees. Flows are shuffle sharded into queues
+// and queues are admitted in round robin, so that a flooding flow only fills its own queues.
+type queuedFlowControl struct {
+	counter
+	name string
+
+	lock        sync.Mutex
+	concurrency int
+	queueLength int
+	handSize    int
+	maxWait     time.Duration
+	executing   int
+	queues      [][]*queuedRequest
+	// waiting is the number of requests in all queues
+	waiting int
+	// next is the index of the queue admitted next
+	next int
+}
+
+func newQueuedFlowControl(name string, schema *proxyv1alpha1.QueuedFlowControlSchema) *queuedFlowControl {
+	queues, handSize := defaultQueues, defaultHandSize
+	if schema.Queues > 0 {
+		queues = int(schema.Queues)
+	}
+	if schema.HandSize > 0 {
+		handSize = int(schema.HandSize)
+	}
+	if handSize > queues {
+		handSize = queues
+	}
+	f := &queuedFlowControl{
+		name:     name,
+		handSize: handSize,
+		queues:   make([][]*queuedRequest, queues),
+	}
+	f.setMaxWait(time.Duration(schema.MaxWaitSeconds) * time.Second)
+	f.Resize(uint32(schema.Concurrency), uint32(schema.QueueLength))
+	return f
+}
+
+// TryAcquire admits the request only if there is free capacity and no request is waiting
+func (f *queuedFlowControl) TryAcquire() bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.acquired(f.tryAdmitLocked())
+}
+
+func (f *queuedFlowControl) tryAdmitLocked() bool {
+	if f.waiting == 0 && f.executing < f.concurrency {
+		f.executing++
+		return true
+	}
+	return false
+}
+
+// Acquire admits the request of flow, it waits in a queue if there is no free capacity. It returns
+// false if the queue is full, or the request is not admitted before ctx is done or maxWait.
+func (f *queuedFlowControl) Acquire(ctx context.Context, flow string) bool {
+	f.lock.Lock()
+	if f.tryAdmitLocked() {
+		f.lock.Unlock()
+		return f.acquired(true)
+	}
+	queue := f.shortestQueueLocked(flow)
+	if len(f.queues[queue]) >= f.queueLength {
+		f.lock.Unlock()
+		return f.acquired(false)
+	}
+	req := &queuedRequest{admitted: make(chan struct{})}
+	f.queues[queue] = append(f.queues[queue], req)
+	f.waiting++
+	maxWait := f.maxWait
+	f.lock.Unlock()
+
+	timer := time.NewTimer(maxWait)
+	defer timer.Stop()
+	select {
+	case <-req.admitted:
+		return f.acquired(true)
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	select {
+	case <-req.admitted:
+		// admitted right before giving up
+		return f.acquired(true)
+	default:
+	}
+	f.removeLocked(queue, req)
+	return f.acquired(false)
+}
+
+// shortestQueueLocked deals handSize queues to the flow and returns the shortest one of them
+func (f *queuedFlowControl) shortestQueueLocked(flow string) int {
+	h := fnv.New64a()
+	h.Write([]byte(flow)) //nolint:errcheck
+	hash := h.Sum64()
+
+	dealt := make([]bool, len(f.queues))
+	shortest := -1
+	for i := 0; i < f.handSize; i++ {
+		remaining := uint64(len(f.queues) - i)
+		card := int(hash % remaining)
+		hash /= remaining
+		// card is the index among queues which are not dealt yet
+		queue := 0
+		for ; ; queue++ {
+			if dealt[queue] {
+				continue
+			}
+			if card == 0 {
+				break
+			}
+			card--
+		}
+		dealt[queue] = true
+		if shortest < 0 || len(f.queues[queue]) < len(f.queues[shortest]) {
+			shortest = queue
+		}
+	}
+	return shortest
+}
+
+func (f *queuedFlowControl) removeLocked(queue int, req *queuedRequest) {
+	for i, r := range f.queues[queue] {
+		if r == req {
+			f.queues[queue] = append(f.queues[queue][:i], f.queues[queue][i+1:]...)
+			f.waiting--
+			return
+		}
+	}
+}
+
+// admitLocked admits waiting requests while there is free capacity, queues take turns
+func (f *queuedFlowControl) admitLocked() {
+	for f.waiting > 0 && f.executing < f.concurrency {
+		for len(f.queues[f.next]) == 0 {
+			f.next = (f.next + 1) % len(f.queues)
+		}
+		req := f.queues[f.next][0]
+		f.queues[f.next] = f.queues[f.next][1:]
+		f.next = (f.next + 1) % len(f.queues)
+		f.waiting--
+		f.executing++
+		close(req.admitted)
+	}
+}
+
+func (f *queuedFlowControl) Release() {
+	f.lock.Lock()
+	f.executing--
+	f.admitLocked()
+	f.lock.Unlock()
+	f.released()
+}
+
+// Resize changes the concurrency to n and the queue length to burst, waiting requests are
+// admitted if the concurrency is raised.
+func (f *queuedFlowControl) Resize(n uint32, burst uint32) bool {
+	queueLength := int(burst)
+	if queueLength == 0 {
+		queueLength = defaultQueueLength
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	resized := f.concurrency != int(n) || f.queueLength != queueLength
+	f.concurrency, f.queueLength = int(n), queueLength
+	f.admitLocked()
+	return resized
+}
+
+// SetMaxWait sets how long requests wait in queue at most for a Queued flow control, a zero d
+// means the default, it is a no-op for flow controls of other types.
+func SetMaxWait(fc FlowControl, d time.Duration) {
+	if f, ok := fc.(*queuedFlowControl); ok {
+		f.setMaxWait(d)
+	}
+}
+
+// setMaxWait changes how long requests wait in queue at most, it applies to requests enqueued
+// afterwards. A zero d means defaultMaxWait.
+func (f *queuedFlowControl) setMaxWait(d time.Duration) {
+	if d <= 0 {
+		d = defaultMaxWait
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.maxWait = d
+}
+
+func (f *queuedFlowControl) String() string {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return fmt.Sprintf("name=%v,type=%v,concurrency=%v,queueLength=%v,queues=%v,handSize=%v,maxWait=%v",
+		f.name, proxyv1alpha1.Queued, f.concurrency, f.queueLength, len(f.queues), f.handSize, f.maxWait)
+}
+
+func (f *queuedFlowControl) Name() string {
+	return f.name
+}
+
+func (f *queuedFlowControl) Type() proxyv1alpha1.FlowControlSchemaType {
+	return proxyv1alpha1.Queued
+}
+
+func (f *queuedFlowControl) Status() Status {
+	status := f.status(f.name, proxyv1alpha1.Queued)
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	status.Max = uint32(f.concurrency)
+	status.Queued = int64(f.waiting)
+	available := float64(f.concurrency - f.executing)
+	if available < 0 || f.waiting > 0 {
+		available = 0
+	}
+	status.AvailableTokens = &available
+	return status
+}
+
+// Acquire acquires the flow control for a request of flow. Requests wait in queues of Queued
+// flow controls, keyed TokenBucket flow controls take a token from the bucket of the flow, and
+// the others admit or reject requests immediately.
+func Acquire(ctx context.Context, fc FlowControl, flow Flow) bool {
+	switch f := fc.(type) {
+	case *queuedFlowControl:
+		return f.Acquire(ctx, flow.User)
+	case *keyedTokenBucket:
+		return f.AcquireFlow(flow)
+	}
+	return fc.TryAcquire()
+}
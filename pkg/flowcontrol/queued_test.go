@@ -0,0 +1,197 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowcontrol
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
+)
+
+// waitQueued waits until n requests are waiting in queues of f
+func waitQueued(t *testing.T, f *queuedFlowControl, n int64) {
+	deadline := time.Now().Add(5 * time.Second)
+	for f.Status().Queued != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("queued requests = %v, want %v", f.Status().Queued, n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestQueuedFlowControl_Acquire(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema proxyv1alpha1.QueuedFlowControlSchema
+		// waiting is the number of requests of the same flow enqueued after concurrency is used up
+		waiting      int
+		wantQueued   int64
+		wantRejected uint64
+	}{
+		{"queued", proxyv1alpha1.QueuedFlowControlSchema{Concurrency: 1, QueueLength: 2, Queues: 1}, 2, 2, 0},
+		{"queue is full", proxyv1alpha1.QueuedFlowControlSchema{Concurrency: 1, QueueLength: 2, Queues: 1}, 3, 2, 1},
+		{"queue of the flow is full while others are empty", proxyv1alpha1.QueuedFlowControlSchema{Concurrency: 1, QueueLength: 2, Queues: 4, HandSize: 1}, 3, 2, 1},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			f := newQueuedFlowControl("test", &tt.schema)
+			ctx := context.Background()
+			if !f.Acquire(ctx, "user") {
+				t.Fatal("Acquire() = false, want true with free capacity")
+			}
+
+			results := make(chan bool, tt.waiting)
+			for j := 0; j < tt.waiting; j++ {
+				go func() {
+					results <- f.Acquire(ctx, "user")
+				}()
+			}
+			waitQueued(t, f, tt.wantQueued)
+			for j := uint64(0); j < tt.wantRejected; j++ {
+				if <-results {
+					t.Fatal("Acquire() = true, want false for requests over queue length")
+				}
+			}
+			if got := f.Status().Rejected; got != tt.wantRejected {
+				t.Errorf("rejected = %v, want %v", got, tt.wantRejected)
+			}
+
+			// queued requests are admitted one by one as capacity frees
+			for j := int64(0); j < tt.wantQueued; j++ {
+				f.Release()
+				if !<-results {
+					t.Fatal("Acquire() = false, want true after capacity frees")
+				}
+			}
+			f.Release()
+			if status := f.Status(); status.Inflight != 0 || status.Queued != 0 {
+				t.Errorf("inflight = %v, queued = %v, want 0", status.Inflight, status.Queued)
+			}
+		})
+	}
+}
+
+func TestQueuedFlowControl_Acquire_canceled(t *testing.T) {
+	f := newQueuedFlowControl("test", &proxyv1alpha1.QueuedFlowControlSchema{Concurrency: 1})
+	if !f.TryAcquire() {
+		t.Fatal("TryAcquire() = false, want true with free capacity")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan bool)
+	go func() {
+		result <- f.Acquire(ctx, "user")
+	}()
+	waitQueued(t, f, 1)
+	if f.TryAcquire() {
+		t.Error("TryAcquire() = true, want false while requests are waiting")
+	}
+	cancel()
+	if <-result {
+		t.Error("Acquire() = true, want false after the request is canceled")
+	}
+	if got := f.Status().Queued; got != 0 {
+		t.Errorf("queued = %v, want 0", got)
+	}
+}
+
+func TestQueuedFlowControl_Acquire_maxWait(t *testing.T) {
+	f := newQueuedFlowControl("test", &proxyv1alpha1.QueuedFlowControlSchema{Concurrency: 1})
+	if f.maxWait != defaultMaxWait {
+		t.Errorf("maxWait = %v, want %v by default", f.maxWait, defaultMaxWait)
+	}
+	SetMaxWait(f, 50*time.Millisecond)
+	if !f.TryAcquire() {
+		t.Fatal("TryAcquire() = false, want true with free capacity")
+	}
+	start := time.Now()
+	if f.Acquire(context.Background(), "user") {
+		t.Error("Acquire() = true, want false after waiting for maxWait")
+	}
+	if waited := time.Since(start); waited < 50*time.Millisecond || waited > 5*time.Second {
+		t.Errorf("waited %v, want about 50ms", waited)
+	}
+	if got := f.Status().Queued; got != 0 {
+		t.Errorf("queued = %v, want 0", got)
+	}
+	SetMaxWait(f, 0)
+	if f.maxWait != defaultMaxWait {
+		t.Errorf("maxWait = %v, want %v after it is reset", f.maxWait, defaultMaxWait)
+	}
+}
+
+func TestQueuedFlowControl_fairness(t *testing.T) {
+	// flows are dealt one of two queues, queues are admitted in turn
+	f := newQueuedFlowControl("test", &proxyv1alpha1.QueuedFlowControlSchema{Concurrency: 1, QueueLength: 10, Queues: 2, HandSize: 1})
+	quiet := "quiet"
+	for j := 0; f.shortestQueueLocked(quiet) == f.shortestQueueLocked("busy"); j++ {
+		quiet = fmt.Sprintf("quiet-%d", j)
+	}
+	ctx := context.Background()
+	if !f.Acquire(ctx, "busy") {
+		t.Fatal("Acquire() = false, want true with free capacity")
+	}
+
+	admitted := make(chan string, 10)
+	acquire := func(flow string) {
+		if f.Acquire(ctx, flow) {
+			admitted <- flow
+		}
+	}
+	for j := 0; j < 5; j++ {
+		go acquire("busy")
+	}
+	waitQueued(t, f, 5)
+	go acquire(quiet)
+	waitQueued(t, f, 6)
+
+	// the quiet flow is admitted within 2 releases even if the busy flow enqueued first
+	var got []string
+	for j := 0; j < 2; j++ {
+		f.Release()
+		got = append(got, <-admitted)
+	}
+	if got[0] != quiet && got[1] != quiet {
+		t.Errorf("admitted flows = %v, want quiet in the first 2", got)
+	}
+}
+
+func TestQueuedFlowControl_Resize(t *testing.T) {
+	f := newQueuedFlowControl("test", &proxyv1alpha1.QueuedFlowControlSchema{Concurrency: 1})
+	ctx := context.Background()
+	f.Acquire(ctx, "user")
+	results := make(chan bool, 2)
+	for j := 0; j < 2; j++ {
+		go func(j int) {
+			results <- f.Acquire(ctx, fmt.Sprintf("user-%d", j))
+		}(j)
+	}
+	waitQueued(t, f, 2)
+	if !f.Resize(3, 0) {
+		t.Error("Resize() = false, want true")
+	}
+	// waiting requests are admitted once concurrency is raised
+	for j := 0; j < 2; j++ {
+		if !<-results {
+			t.Error("Acquire() = false, want true after concurrency is raised")
+		}
+	}
+	if f.Resize(3, 0) {
+		t.Error("Resize() = true, want false without changes")
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowcontrol
+
+import (
+	"sync"
+	"time"
+)
+
+// tokenBucket is a resizable token bucket rate limiter. Unlike the rate limiter in client-go,
+// it exposes available tokens and keeps them when it is resized.
+type tokenBucket struct {
+	lock   sync.Mutex
+	qps    float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+
+	// slowStart is the duration to ramp up to a raised qps and burst
+	slowStart time.Duration
+	// rampStart is the time when qps or burst is raised, rampQPS and rampBurst are the
+	// size when the ramp starts
+	rampStart time.Time
+	rampQPS   float64
+	rampBurst float64
+}
+
+// newTokenBucket returns a token bucket which is full at the beginning
+func newTokenBucket(qps, burst float64) *tokenBucket {
+	return &tokenBucket{
+		qps:    qps,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+		now:    time.Now,
+	}
+}
+
+// sizeLocked returns qps and burst in effect at the time, they ramp up linearly from the size
+// when the ramp starts during slow start.
+func (b *tokenBucket) sizeLocked(at time.Time) (float64, float64) {
+	if b.rampStart.IsZero() {
+		return b.qps, b.burst
+	}
+	progress := float64(at.Sub(b.rampStart)) / float64(b.slowStart)
+	if progress >= 1 {
+		return b.qps, b.burst
+	}
+	if progress < 0 {
+		progress = 0
+	}
+	return b.rampQPS + (b.qps-b.rampQPS)*progress, b.rampBurst + (b.burst-b.rampBurst)*progress
+}
+
+// advanceLocked refills tokens according to the elapsed time
+func (b *tokenBucket) advanceLocked() {
+	now := b.now()
+	lastQPS, _ := b.sizeLocked(b.last)
+	qps, burst := b.sizeLocked(now)
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		// qps changes linearly during slow start
+		b.tokens += elapsed * (lastQPS + qps) / 2
+	}
+	if b.tokens > burst {
+		b.tokens = burst
+	}
+	b.last = now
+	if !b.rampStart.IsZero() && now.Sub(b.rampStart) >= b.slowStart {
+		b.rampStart = time.Time{}
+	}
+}
+
+// TryAccept takes a token if it is available
+func (b *tokenBucket) TryAccept() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.advanceLocked()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// Return gives back a token taken by TryAccept
+func (b *tokenBucket) Return() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.advanceLocked()
+	_, burst := b.sizeLocked(b.last)
+	if b.tokens++; b.tokens > burst {
+		b.tokens = burst
+	}
+}
+
+// Available returns the number of available tokens
+func (b *tokenBucket) Available() float64 {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.advanceLocked()
+	return b.tokens
+}
+
+// Size returns qps and burst of the bucket
+func (b *tokenBucket) Size() (float64, float64) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.qps, b.burst
+}
+
+// Resize changes qps and burst, tokens taken before are kept. If slow start is set and qps or
+// burst is raised, the bucket ramps up to the new size from the size in effect now. It returns
+// true if the size is changed.
+func (b *tokenBucket) Resize(qps, burst float64) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.qps == qps && b.burst == burst {
+		return false
+	}
+	b.advanceLocked()
+	currentQPS, currentBurst := b.sizeLocked(b.last)
+	b.rampStart = time.Time{}
+	if b.slowStart > 0 && (qps > currentQPS || burst > currentBurst) {
+		b.rampStart, b.rampQPS, b.rampBurst = b.last, minFloat(currentQPS, qps), minFloat(currentBurst, burst)
+	}
+	b.qps = qps
+	b.burst = burst
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	return true
+}
+
+// SetSlowStart sets the duration to ramp up after qps or burst is raised, zero disables it.
+// It takes effect at the next resize.
+func (b *tokenBucket) SetSlowStart(d time.Duration) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.slowStart = d
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
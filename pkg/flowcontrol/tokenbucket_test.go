@@ -0,0 +1,58 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowcontrol
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTokenBucket_slowStart(t *testing.T) {
+	tests := []struct {
+		name      string
+		slowStart time.Duration
+		qps       float64
+		burst     float64
+		elapsed   time.Duration
+		want      float64
+	}{
+		{"raised without slow start", 0, 100, 100, time.Second, 100},
+		{"raised during slow start", 10 * time.Second, 100, 100, time.Second, 14.5},
+		{"burst is capped during slow start", 10 * time.Second, 100, 100, 5 * time.Second, 55},
+		{"raised after slow start", 10 * time.Second, 100, 100, 20 * time.Second, 100},
+		{"lowered during slow start", 10 * time.Second, 5, 5, time.Second, 5},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Now()
+			b := newTokenBucket(10, 10)
+			b.now = func() time.Time { return now }
+			b.last = now
+			b.tokens = 0
+			b.SetSlowStart(tt.slowStart)
+
+			b.Resize(tt.qps, tt.burst)
+			now = now.Add(tt.elapsed)
+			if got := b.Available(); math.Abs(got-tt.want) > 0.01 {
+				t.Errorf("tokenBucket.Available() = %v, want %v", got, tt.want)
+			}
+			if qps, burst := b.Size(); qps != tt.qps || burst != tt.burst {
+				t.Errorf("tokenBucket.Size() = %v, %v, want %v, %v", qps, burst, tt.qps, tt.burst)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowcontrol
+
+import (
+	"fmt"
+
+	"github.com/zoumo/golib/lock/maxinflight"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
+)
+
+// readOnlyVerbs are verbs of requests which are not mutating, the same as kube-apiserver
+// classifies requests for --max-requests-inflight.
+var readOnlyVerbs = sets.NewString("get", "list", "watch")
+
+// IsMutatingVerb returns true if requests with the verb are mutating
+func IsMutatingVerb(verb string) bool {
+	return !readOnlyVerbs.Has(verb)
+}
+
+// VerbFlowControl is a flow control which limits requests separately by verb
+type VerbFlowControl interface {
+	FlowControl
+	// ForVerb returns the flow control which requests with the verb acquire
+	ForVerb(verb string) FlowControl
+}
+
+// verbFlowControl limits mutating and read-only requests in flight separately. Requests
+// acquire the flow control returned by ForVerb, the read-only one is acquired if it is
+// acquired directly.
+type verbFlowControl struct {
+	name     string
+	readOnly *flowControl
+	mutating *flowControl
+}
+
+var _ VerbFlowControl = &verbFlowControl{}
+
+func newVerbFlowControl(name string, maxReadOnly, maxMutating uint32) *verbFlowControl {
+	newFlowControl := func(max uint32) *flowControl {
+		return &flowControl{
+			TokenBucket: maxinflight.New(max),
+			name:        name,
+			typ:         proxyv1alpha1.MaxRequestsInflightByVerb,
+			max:         max,
+		}
+	}
+	return &verbFlowControl{
+		name:     name,
+		readOnly: newFlowControl(maxReadOnly),
+		mutating: newFlowControl(maxMutating),
+	}
+}
+
+func (f *verbFlowControl) ForVerb(verb string) FlowControl {
+	if IsMutatingVerb(verb) {
+		return f.mutating
+	}
+	return f.readOnly
+}
+
+func (f *verbFlowControl) TryAcquire() bool {
+	return f.readOnly.TryAcquire()
+}
+
+func (f *verbFlowControl) Release() {
+	f.readOnly.Release()
+}
+
+// Resize resizes the read-only limit to n and the mutating limit to burst
+func (f *verbFlowControl) Resize(n uint32, burst uint32) bool {
+	readOnlyResized := f.readOnly.Resize(n, 0)
+	mutatingResized := f.mutating.Resize(burst, 0)
+	return readOnlyResized || mutatingResized
+}
+
+func (f *verbFlowControl) String() string {
+	return fmt.Sprintf("name=%v,type=%v,readOnly=%v,mutating=%v", f.name, proxyv1alpha1.MaxRequestsInflightByVerb, f.readOnly.Status().Max, f.mutating.Status().Max)
+}
+
+func (f *verbFlowControl) Name() string {
+	return f.name
+}
+
+func (f *verbFlowControl) Type() proxyv1alpha1.FlowControlSchemaType {
+	return proxyv1alpha1.MaxRequestsInflightByVerb
+}
+
+func (f *verbFlowControl) Status() Status {
+	status := f.readOnly.Status()
+	mutating := f.mutating.Status()
+	status.Mutating = &mutating
+	return status
+}
@@ -0,0 +1,76 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flowcontrol
+
+import (
+	"testing"
+
+	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
+)
+
+func TestVerbFlowControl(t *testing.T) {
+	tests := []struct {
+		name                 string
+		verbs                []string
+		wantReadOnly         int64
+		wantMutating         int64
+		wantReadOnlyRejected uint64
+		wantMutatingRejected uint64
+		resizeReadOnly       uint32
+		resizeMutating       uint32
+		wantResized          bool
+	}{
+		{"read-only requests", []string{"get", "list", "watch", "get"}, 3, 0, 1, 0, 3, 1, false},
+		{"mutating requests", []string{"create", "update", "patch", "delete", "post"}, 0, 1, 0, 4, 3, 2, true},
+		{"mixed requests", []string{"create", "get", "deletecollection", "list"}, 2, 1, 0, 1, 3, 1, false},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			fc := NewFlowControl(proxyv1alpha1.FlowControlSchema{
+				Name: "by-verb",
+				FlowControlSchemaConfiguration: proxyv1alpha1.FlowControlSchemaConfiguration{
+					MaxRequestsInflightByVerb: &proxyv1alpha1.MaxRequestsInflightByVerbFlowControlSchema{MaxReadOnly: 3, MaxMutating: 1},
+				},
+			})
+			if fc.Type() != proxyv1alpha1.MaxRequestsInflightByVerb {
+				t.Fatalf("Type() = %v, want %v", fc.Type(), proxyv1alpha1.MaxRequestsInflightByVerb)
+			}
+			byVerb, ok := fc.(VerbFlowControl)
+			if !ok {
+				t.Fatalf("flow control does not limit requests by verb")
+			}
+			for _, verb := range tt.verbs {
+				byVerb.ForVerb(verb).TryAcquire()
+			}
+
+			status := fc.Status()
+			if status.Inflight != tt.wantReadOnly || status.Rejected != tt.wantReadOnlyRejected {
+				t.Errorf("read-only inflight = %v, rejected = %v, want %v, %v", status.Inflight, status.Rejected, tt.wantReadOnly, tt.wantReadOnlyRejected)
+			}
+			if status.Mutating == nil || status.Mutating.Inflight != tt.wantMutating || status.Mutating.Rejected != tt.wantMutatingRejected {
+				t.Errorf("mutating status = %+v, want inflight = %v, rejected = %v", status.Mutating, tt.wantMutating, tt.wantMutatingRejected)
+			}
+
+			if got := fc.Resize(tt.resizeReadOnly, tt.resizeMutating); got != tt.wantResized {
+				t.Errorf("Resize() = %v, want %v", got, tt.wantResized)
+			}
+			status = fc.Status()
+			if status.Max != tt.resizeReadOnly || status.Mutating.Max != tt.resizeMutating {
+				t.Errorf("max after resizing = %v, %v, want %v, %v", status.Max, status.Mutating.Max, tt.resizeReadOnly, tt.resizeMutating)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientcert
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	x509request "k8s.io/apiserver/pkg/authentication/request/x509"
+	"k8s.io/klog"
+)
+
+// allowlistCheckInterval is how often the allowlist file is checked for changes
+const allowlistCheckInterval = 10 * time.Second
+
+// SerialAllowlist is a list of serial numbers of client certificates which are currently valid,
+// it is an alternative to CRL and OCSP in environments that manage revocation by an allowlist.
+// The list is loaded from a file which contains a hex serial number per line, colons in serial
+// numbers, blank lines and lines starting with '#' are ignored. The file is reloaded when it is
+// changed, and the last loaded list is kept if it fails to be reloaded.
+type SerialAllowlist struct {
+	path          string
+	checkInterval time.Duration
+
+	lock      sync.RWMutex
+	serials   sets.String
+	modTime   time.Time
+	size      int64
+	lastCheck time.Time
+}
+
+// NewSerialAllowlist loads the allowlist from file
+func NewSerialAllowlist(path string) (*SerialAllowlist, error) {
+	l := &SerialAllowlist{
+		path:          path,
+		checkInterval: allowlistCheckInterval,
+	}
+	stat, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := l.loadLocked(stat); err != nil {
+		return nil, err
+	}
+	l.lastCheck = time.Now()
+	return l, nil
+}
+
+// Allowed returns true if the serial number is in the allowlist
+func (l *SerialAllowlist) Allowed(serial *big.Int) bool {
+	if serial == nil {
+		return false
+	}
+	l.reloadIfChanged()
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	return l.serials.Has(serial.Text(16))
+}
+
+// UserConversion returns a UserConversion which rejects certificate chains whose leaf
+// certificate is not in the allowlist, and converts the others by delegate.
+func (l *SerialAllowlist) UserConversion(delegate x509request.UserConversion) x509request.UserConversion {
+	return x509request.UserConversionFunc(func(chain []*x509.Certificate) (*authenticator.Response, bool, error) {
+		if len(chain) == 0 {
+			return nil, false, nil
+		}
+		if !l.Allowed(chain[0].SerialNumber) {
+			return nil, false, fmt.Errorf("client certificate with serial number %s is not in the allowlist", chain[0].SerialNumber.Text(16))
+		}
+		return delegate.User(chain)
+	})
+}
+
+func (l *SerialAllowlist) reloadIfChanged() {
+	l.lock.RLock()
+	due := time.Since(l.lastCheck) >= l.checkInterval
+	l.lock.RUnlock()
+	if !due {
+		return
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if time.Since(l.lastCheck) < l.checkInterval {
+		// reloaded by others
+		return
+	}
+	l.lastCheck = time.Now()
+	stat, err := os.Stat(l.path)
+	if err != nil {
+		klog.Errorf("[client cert allowlist] failed to stat %q, keep the last loaded allowlist: %v", l.path, err)
+		return
+	}
+	if stat.ModTime().Equal(l.modTime) && stat.Size() == l.size {
+		return
+	}
+	if err := l.loadLocked(stat); err != nil {
+		klog.Errorf("[client cert allowlist] failed to reload %q, keep the last loaded allowlist: %v", l.path, err)
+		return
+	}
+	klog.Infof("[client cert allowlist] reloaded %d serial numbers from %q", l.serials.Len(), l.path)
+}
+
+func (l *SerialAllowlist) loadLocked(stat os.FileInfo) error {
+	data, err := ioutil.ReadFile(l.path)
+	if err != nil {
+		return err
+	}
+	serials, err := parseSerials(data)
+	if err != nil {
+		return fmt.Errorf("invalid allowlist %q: %v", l.path, err)
+	}
+	l.serials = serials
+	l.modTime = stat.ModTime()
+	l.size = stat.Size()
+	return nil
+}
+
+// parseSerials parses hex serial numbers and returns them in normalized hex
+func parseSerials(data []byte) (sets.String, error) {
+	serials := sets.NewString()
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if len(text) == 0 || strings.HasPrefix(text, "#") {
+			continue
+		}
+		serial, ok := new(big.Int).SetString(strings.ReplaceAll(text, ":", ""), 16)
+		if !ok {
+			return nil, fmt.Errorf("line %d: %q is not a hex serial number", line, text)
+		}
+		serials.Insert(serial.Text(16))
+	}
+	return serials, scanner.Err()
+}
@@ -0,0 +1,95 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientcert
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	x509request "k8s.io/apiserver/pkg/authentication/request/x509"
+)
+
+func TestSerialAllowlist(t *testing.T) {
+	dir, err := ioutil.TempDir("", "allowlist")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "serials")
+	if err := ioutil.WriteFile(path, []byte("# allowed serials\n0A:1B\n\nff\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	allowlist, err := NewSerialAllowlist(path)
+	if err != nil {
+		t.Fatalf("NewSerialAllowlist() error = %v", err)
+	}
+	conversion := allowlist.UserConversion(x509request.CommonNameUserConversion)
+	chain := func(serial int64) []*x509.Certificate {
+		return []*x509.Certificate{{SerialNumber: big.NewInt(serial), Subject: pkix.Name{CommonName: "test"}}}
+	}
+
+	tests := []struct {
+		name   string
+		serial int64
+		wantOK bool
+	}{
+		{"serial with colons", 0x0a1b, true},
+		{"lower case serial", 0xff, true},
+		{"absent serial", 0x10, false},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			resp, ok, err := conversion.User(chain(tt.serial))
+			if ok != tt.wantOK || (err != nil) == tt.wantOK {
+				t.Fatalf("User() ok = %v, err = %v, want ok %v", ok, err, tt.wantOK)
+			}
+			if ok && resp.User.GetName() != "test" {
+				t.Errorf("User() name = %v, want test", resp.User.GetName())
+			}
+		})
+	}
+
+	// the allowlist is reloaded after it changes
+	allowlist.checkInterval = 0
+	if err := ioutil.WriteFile(path, []byte("10\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, future, future); err != nil {
+		t.Fatal(err)
+	}
+	if !allowlist.Allowed(big.NewInt(0x10)) || allowlist.Allowed(big.NewInt(0xff)) {
+		t.Errorf("allowlist is not reloaded")
+	}
+
+	// the last loaded allowlist is kept if the file becomes invalid
+	if err := ioutil.WriteFile(path, []byte("not-a-serial\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, future.Add(time.Minute), future.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if !allowlist.Allowed(big.NewInt(0x10)) {
+		t.Errorf("last loaded allowlist is not kept")
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+
+	"github.com/kubewharf/kubegateway/pkg/gateway/metrics"
+)
+
+// tokenCache caches token review results of a cluster for success or failure TTL. The number
+// of cached results is bounded by maxEntries, the least recently used one is evicted when the
+// cache is full, so that a burst of distinct tokens can not grow the cache without limit.
+// Errors are not cached.
+type tokenCache struct {
+	host       string
+	review     authenticator.Token
+	successTTL time.Duration
+	failureTTL time.Duration
+	// maxEntries is the maximum number of cached results, zero means no limit
+	maxEntries int
+
+	lock sync.Mutex
+	// lru holds *tokenCacheEntry, the most recently used one is at front
+	lru     *list.List
+	entries map[string]*list.Element
+}
+
+type tokenCacheEntry struct {
+	key      string
+	response *authenticator.Response
+	ok       bool
+	expireAt time.Time
+}
+
+func newTokenCache(host string, review authenticator.Token, successTTL, failureTTL time.Duration, maxEntries int) *tokenCache {
+	return &tokenCache{
+		host:       host,
+		review:     review,
+		successTTL: successTTL,
+		failureTTL: failureTTL,
+		maxEntries: maxEntries,
+		lru:        list.New(),
+		entries:    map[string]*list.Element{},
+	}
+}
+
+func (c *tokenCache) AuthenticateToken(ctx context.Context, token string) (*authenticator.Response, bool, error) {
+	auds, _ := authenticator.AudiencesFrom(ctx)
+	key := tokenReviewKey(auds, token)
+	if entry, ok := c.get(key); ok {
+		return entry.response, entry.ok, nil
+	}
+
+	response, ok, err := c.review.AuthenticateToken(ctx, token)
+	if err != nil {
+		return nil, false, err
+	}
+	ttl := c.failureTTL
+	if ok {
+		ttl = c.successTTL
+	}
+	if ttl > 0 {
+		c.set(&tokenCacheEntry{key: key, response: response, ok: ok, expireAt: time.Now().Add(ttl)})
+	}
+	return response, ok, nil
+}
+
+func (c *tokenCache) get(key string) (*tokenCacheEntry, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*tokenCacheEntry)
+	if time.Now().After(entry.expireAt) {
+		c.removeLocked(element)
+		metrics.RecordTokenCacheEntries(c.host, c.lru.Len())
+		return nil, false
+	}
+	c.lru.MoveToFront(element)
+	return entry, true
+}
+
+func (c *tokenCache) set(entry *tokenCacheEntry) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if element, ok := c.entries[entry.key]; ok {
+		element.Value = entry
+		c.lru.MoveToFront(element)
+	} else {
+		c.entries[entry.key] = c.lru.PushFront(entry)
+	}
+
+	// the least recently used entries are evicted once they expire or the cache is full,
+	// entries which are never used again do not stay until they are looked up
+	now := time.Now()
+	for back := c.lru.Back(); back != nil; back = c.lru.Back() {
+		full := c.maxEntries > 0 && c.lru.Len() > c.maxEntries
+		if !full && !now.After(back.Value.(*tokenCacheEntry).expireAt) {
+			break
+		}
+		c.removeLocked(back)
+	}
+	metrics.RecordTokenCacheEntries(c.host, c.lru.Len())
+}
+
+func (c *tokenCache) removeLocked(element *list.Element) {
+	c.lru.Remove(element)
+	delete(c.entries, element.Value.(*tokenCacheEntry).key)
+}
@@ -16,33 +16,50 @@ package webhook
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
+	"golang.org/x/sync/singleflight"
 	"k8s.io/apiserver/pkg/authentication/authenticator"
-	tokencache "k8s.io/apiserver/pkg/authentication/token/cache"
 	webhooktoken "k8s.io/apiserver/plugin/pkg/authenticator/token/webhook"
 
 	"github.com/kubewharf/kubegateway/pkg/clusters"
 	"github.com/kubewharf/kubegateway/pkg/gateway/endpoints/request"
+	"github.com/kubewharf/kubegateway/pkg/gateway/metrics"
 )
 
+// tokenReviewTimeout bounds a token review including the time waiting for an inflight slot
+const tokenReviewTimeout = 2 * time.Second
+
 type multiClusterTokenReviewAuthenticator struct {
 	tokenSuccessCacheTTL time.Duration
 	tokenFailureCacheTTL time.Duration
-	implicitAuds         authenticator.Audiences
+	// maxCacheEntries is the maximum number of cached token review results of each cluster,
+	// zero means no limit
+	maxCacheEntries int
+	implicitAuds    authenticator.Audiences
+	// maxInflightReviews is the maximum number of inflight token reviews to each cluster,
+	// zero means no limit
+	maxInflightReviews int
 
 	clientProvider clusters.ClientProvider
 	caches         sync.Map
+	// reviewers stores *tokenReviewer split by host
+	reviewers sync.Map
 }
 
-func NewMultiClusterTokenReviewAuthenticator(clientProvider clusters.ClientProvider, tokenSuccessCacheTTL, tokenFailureCacheTTL time.Duration, implicitAuds authenticator.Audiences) authenticator.Token {
+func NewMultiClusterTokenReviewAuthenticator(clientProvider clusters.ClientProvider, tokenSuccessCacheTTL, tokenFailureCacheTTL time.Duration, implicitAuds authenticator.Audiences, maxInflightReviews, maxCacheEntries int) authenticator.Token {
 	return &multiClusterTokenReviewAuthenticator{
 		tokenSuccessCacheTTL: tokenSuccessCacheTTL,
 		tokenFailureCacheTTL: tokenFailureCacheTTL,
+		maxCacheEntries:      maxCacheEntries,
+		maxInflightReviews:   maxInflightReviews,
 		clientProvider:       clientProvider,
 		caches:               sync.Map{},
+		reviewers:            sync.Map{},
 		implicitAuds:         implicitAuds,
 	}
 }
@@ -59,28 +76,54 @@ func (a *multiClusterTokenReviewAuthenticator) AuthenticateToken(ctx context.Con
 		return nil, false, err
 	}
 
+	// split reviewer by host
+	reviewer, loaded := a.reviewers.Load(host)
+	if !loaded {
+		reviewer, loaded = a.reviewers.LoadOrStore(host, a.newTokenReviewer(host))
+		if !loaded {
+			go func() {
+				<-cluster.Context().Done()
+				a.reviewers.Delete(host)
+			}()
+		}
+	}
+
 	var tokenAuth authenticator.Token
 	if a.tokenFailureCacheTTL == 0 && a.tokenSuccessCacheTTL == 0 {
 		// if token cache ttl is 0, call upstream cluster directly
-		tokenAuth = a.authenticateTokenForHost(host)
+		tokenAuth = reviewer.(authenticator.Token)
 	} else {
 		// split cache by host
 		cache, loaded := a.caches.Load(host)
 		if !loaded {
-			// use token cache, if no cache is hit, authenticateToken() will be called
-			// tokencache use a new context inheriting from context.Background() without all value of req.Context.
-			cache, loaded = a.caches.LoadOrStore(host, tokencache.New(a.authenticateTokenForHost(host), false, a.tokenSuccessCacheTTL, a.tokenFailureCacheTTL))
+			// use token cache, if no cache is hit, the reviewer will be called, it reviews token
+			// with a new context inheriting from context.Background() without all value of req.Context.
+			cache, loaded = a.caches.LoadOrStore(host, newTokenCache(host, reviewer.(authenticator.Token), a.tokenSuccessCacheTTL, a.tokenFailureCacheTTL, a.maxCacheEntries))
 			// destry cache when cluster stopped
 			if !loaded {
 				go func() {
 					<-cluster.Context().Done()
 					a.caches.Delete(host)
+					metrics.ForgetTokenCacheEntries(host)
 				}()
 			}
 		}
 		tokenAuth = cache.(authenticator.Token)
 	}
-	return tokenAuth.AuthenticateToken(ctx, token)
+
+	auds := cluster.APIAudiences()
+	if len(auds) == 0 {
+		return tokenAuth.AuthenticateToken(ctx, token)
+	}
+	// audiences of the cluster override the global ones, the token review only succeeds if
+	// the token is valid for at least one of them
+	resp, ok, err := tokenAuth.AuthenticateToken(authenticator.WithAudiences(ctx, auds), token)
+	if !ok || err != nil {
+		return resp, ok, err
+	}
+	// the audiences are checked already, they must not be checked against the global ones
+	// again, and the cached response must not be modified
+	return &authenticator.Response{User: resp.User}, true, nil
 }
 
 // authenticate token by webhook.
@@ -92,8 +135,91 @@ func (a *multiClusterTokenReviewAuthenticator) authenticateTokenForHost(host str
 		}
 		// err is always nil, can be ignored
 		tokenauth, _ := webhooktoken.NewFromInterface(client.AuthenticationV1().TokenReviews(), a.implicitAuds)
-		newCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+		return tokenauth.AuthenticateToken(ctx, token)
+	})
+}
+
+// tokenReviewer reviews tokens for a host. Concurrent reviews of the same token share one
+// upstream TokenReview, and the number of inflight TokenReviews to the host is limited so
+// that a burst of distinct tokens does not flood the upstream.
+type tokenReviewer struct {
+	host   string
+	review authenticator.Token
+	group  singleflight.Group
+	// inflight is a semaphore of inflight reviews, it is nil if there is no limit
+	inflight chan struct{}
+}
+
+func (a *multiClusterTokenReviewAuthenticator) newTokenReviewer(host string) *tokenReviewer {
+	r := &tokenReviewer{
+		host:   host,
+		review: a.authenticateTokenForHost(host),
+	}
+	if a.maxInflightReviews > 0 {
+		r.inflight = make(chan struct{}, a.maxInflightReviews)
+	}
+	return r
+}
+
+type tokenReviewResult struct {
+	response *authenticator.Response
+	ok       bool
+}
+
+func (r *tokenReviewer) AuthenticateToken(ctx context.Context, token string) (*authenticator.Response, bool, error) {
+	auds, _ := authenticator.AudiencesFrom(ctx)
+	key := tokenReviewKey(auds, token)
+	// the shared review must not be canceled by the request which starts it, only audiences
+	// are inherited like the token cache does
+	ch := r.group.DoChan(key, func() (interface{}, error) {
+		reviewCtx, cancel := context.WithTimeout(context.Background(), tokenReviewTimeout)
 		defer cancel()
-		return tokenauth.AuthenticateToken(newCtx, token)
+		if auds != nil {
+			reviewCtx = authenticator.WithAudiences(reviewCtx, auds)
+		}
+		if err := r.acquire(reviewCtx); err != nil {
+			return nil, err
+		}
+		defer r.release()
+		response, ok, err := r.review.AuthenticateToken(reviewCtx, token)
+		return &tokenReviewResult{response: response, ok: ok}, err
 	})
+
+	select {
+	case result := <-ch:
+		if result.Err != nil {
+			return nil, false, result.Err
+		}
+		ret := result.Val.(*tokenReviewResult)
+		return ret.response, ret.ok, nil
+	case <-ctx.Done():
+		return nil, false, ctx.Err()
+	}
+}
+
+func (r *tokenReviewer) acquire(ctx context.Context) error {
+	if r.inflight == nil {
+		return nil
+	}
+	select {
+	case r.inflight <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("too many inflight token reviews to cluster %q, limit is %d", r.host, cap(r.inflight))
+	}
+}
+
+func (r *tokenReviewer) release() {
+	if r.inflight != nil {
+		<-r.inflight
+	}
+}
+
+// tokenReviewKey hashes the token so that it is not kept in memory in plain text
+func tokenReviewKey(auds authenticator.Audiences, token string) string {
+	h := sha256.New()
+	h.Write([]byte(strings.Join(auds, ","))) //nolint:errcheck
+	h.Write([]byte{0})                       //nolint:errcheck
+	h.Write([]byte(token))                   //nolint:errcheck
+	return string(h.Sum(nil))
 }
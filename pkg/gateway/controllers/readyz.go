@@ -0,0 +1,50 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// failedReadyzChecks returns names of failed checks in the response of a failed verbose
+// readyz request. The response is in plain text, client-go keeps it in the causes of the
+// error, e.g.
+//
+//	[+]ping ok
+//	[-]etcd failed: reason withheld
+//	readyz check failed
+func failedReadyzChecks(err error) []string {
+	status, ok := err.(errors.APIStatus)
+	if !ok || status.Status().Details == nil {
+		return nil
+	}
+	var failed []string
+	for _, cause := range status.Status().Details.Causes {
+		for _, line := range strings.Split(cause.Message, "\n") {
+			line = strings.TrimSpace(line)
+			if !strings.HasPrefix(line, "[-]") {
+				continue
+			}
+			name := strings.TrimPrefix(line, "[-]")
+			if i := strings.IndexByte(name, ' '); i >= 0 {
+				name = name[:i]
+			}
+			failed = append(failed, name)
+		}
+	}
+	return failed
+}
@@ -0,0 +1,101 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"k8s.io/klog"
+
+	"github.com/kubewharf/kubegateway/pkg/gateway/metrics"
+)
+
+// reloadFailures tracks consecutive failures of applying UpstreamCluster config. A cluster
+// failed to apply its config keeps running the last good one, failures beyond threshold
+// are escalated since the gateway is probably drifting from the desired config.
+type reloadFailures struct {
+	lock sync.Mutex
+	// threshold is the number of consecutive failures to escalate, zero disables escalation
+	threshold int
+	failures  map[string]int
+}
+
+func newReloadFailures() *reloadFailures {
+	return &reloadFailures{failures: map[string]int{}}
+}
+
+// SetConfigFailureThreshold sets the number of consecutive failures of applying an UpstreamCluster
+// config after which the failures are escalated, zero disables escalation.
+func (m *UpstreamClusterController) SetConfigFailureThreshold(threshold int) {
+	m.reloadFailures.lock.Lock()
+	defer m.reloadFailures.lock.Unlock()
+	m.reloadFailures.threshold = threshold
+}
+
+// recordFailure records a failure of applying config of cluster
+func (r *reloadFailures) recordFailure(cluster string, generation int64, err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.failures[cluster]++
+	failures := r.failures[cluster]
+	escalated := r.threshold > 0 && failures >= r.threshold
+	metrics.RecordUpstreamClusterConfigFailures(cluster, failures, escalated)
+	if escalated {
+		klog.Errorf("[config drift] cluster=%q failed to apply generation %d of UpstreamCluster config %d times in a row, "+
+			"it keeps running the last good config until the config is fixed, err: %v", cluster, generation, failures, err)
+	}
+}
+
+// reset forgets failures of cluster after its config is applied or it is deleted
+func (r *reloadFailures) reset(cluster string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if failures, ok := r.failures[cluster]; ok && r.threshold > 0 && failures >= r.threshold {
+		klog.Infof("[config drift] cluster=%q recovers after %d consecutive failures of applying UpstreamCluster config", cluster, failures)
+	}
+	delete(r.failures, cluster)
+	metrics.ForgetUpstreamClusterConfigFailures(cluster)
+}
+
+// escalated returns the sorted clusters whose consecutive failures reach threshold
+func (r *reloadFailures) escalated() []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	var ret []string
+	if r.threshold <= 0 {
+		return ret
+	}
+	for cluster, failures := range r.failures {
+		if failures >= r.threshold {
+			ret = append(ret, cluster)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// CheckConfigFailures is a readiness check which fails if any cluster fails to apply its
+// UpstreamCluster config more times in a row than the threshold.
+func (m *UpstreamClusterController) CheckConfigFailures(_ *http.Request) error {
+	clusters := m.reloadFailures.escalated()
+	if len(clusters) > 0 {
+		return fmt.Errorf("failed to apply UpstreamCluster config of clusters: %s", strings.Join(clusters, ","))
+	}
+	return nil
+}
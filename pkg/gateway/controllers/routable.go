@@ -0,0 +1,68 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"fmt"
+
+	"k8s.io/klog"
+)
+
+// EmptyUpstreamsPolicy decides how startup is handled if no requests can be routed to
+// any upstream endpoint, e.g. a botched config empties the server list.
+type EmptyUpstreamsPolicy string
+
+const (
+	// EmptyUpstreamsIgnore starts the proxy silently
+	EmptyUpstreamsIgnore EmptyUpstreamsPolicy = "Ignore"
+	// EmptyUpstreamsWarn starts the proxy with a warning log
+	EmptyUpstreamsWarn EmptyUpstreamsPolicy = "Warn"
+	// EmptyUpstreamsFail fails startup
+	EmptyUpstreamsFail EmptyUpstreamsPolicy = "Fail"
+)
+
+// CheckRoutableUpstreams counts clusters which have at least one enabled endpoint after all
+// UpstreamClusters in informer cache are created, and handles the result by policy. It returns
+// an error only if the policy is EmptyUpstreamsFail and there are no routable clusters.
+func (m *UpstreamClusterController) CheckRoutableUpstreams(stopCh <-chan struct{}, policy EmptyUpstreamsPolicy) error {
+	if policy == EmptyUpstreamsIgnore {
+		return nil
+	}
+	infos := m.waitForClusters(stopCh, "routable-upstreams")
+	routableClusters, routableEndpoints := 0, 0
+	for _, info := range infos {
+		endpoints := 0
+		for _, endpoint := range info.AllEndpoints() {
+			if e, ok := info.Endpoints.Load(endpoint); ok && !e.IsDisabled() {
+				endpoints++
+			}
+		}
+		if endpoints > 0 && !info.IsDisabled() {
+			routableClusters++
+			routableEndpoints += endpoints
+		}
+	}
+	if routableClusters > 0 {
+		klog.Infof("[routable-upstreams] %d of %d upstream clusters with %d endpoints are routable", routableClusters, len(infos), routableEndpoints)
+		return nil
+	}
+
+	err := fmt.Errorf("none of %d upstream clusters has enabled endpoints, all proxied requests will be rejected", len(infos))
+	if policy == EmptyUpstreamsFail {
+		return err
+	}
+	klog.Warningf("[routable-upstreams] %v", err)
+	return nil
+}
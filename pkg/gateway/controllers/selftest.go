@@ -0,0 +1,170 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+
+	"github.com/kubewharf/kubegateway/pkg/clusters"
+)
+
+// SelfTestPolicy decides whether the startup self-test of upstream endpoints runs and
+// how its result is handled
+type SelfTestPolicy string
+
+const (
+	// SelfTestDisabled skips the self-test
+	SelfTestDisabled SelfTestPolicy = "Disabled"
+	// SelfTestLog logs the result of the self-test only
+	SelfTestLog SelfTestPolicy = "Log"
+	// SelfTestFailIfNoneReachable fails startup if there are endpoints but none of them passes
+	SelfTestFailIfNoneReachable SelfTestPolicy = "FailIfNoneReachable"
+)
+
+const (
+	// clusterCreationTimeout bounds how long startup checks wait for clusters in
+	// informer cache to be created
+	clusterCreationTimeout = 30 * time.Second
+	selfTestRequestTimeout = 5 * time.Second
+)
+
+// SelfTestResult is the result of the self-test of an upstream endpoint
+type SelfTestResult struct {
+	Cluster  string
+	Endpoint string
+	// Err is nil if the endpoint passes the self-test
+	Err error
+}
+
+// SelfTest tests connectivity and authentication of every upstream endpoint once, after
+// all UpstreamClusters in informer cache are created. It logs a summary and returns the
+// results sorted by cluster and endpoint.
+func (m *UpstreamClusterController) SelfTest(stopCh <-chan struct{}) []SelfTestResult {
+	infos := m.waitForClusters(stopCh, "self-test")
+
+	var results []SelfTestResult
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	for _, info := range infos {
+		for _, endpoint := range info.AllEndpoints() {
+			e, ok := info.Endpoints.Load(endpoint)
+			if !ok {
+				continue
+			}
+			wg.Add(1)
+			go func(e *clusters.EndpointInfo) {
+				defer wg.Done()
+				err := m.selfTestEndpoint(e)
+				lock.Lock()
+				results = append(results, SelfTestResult{Cluster: e.Cluster, Endpoint: e.Endpoint, Err: err})
+				lock.Unlock()
+			}(e)
+		}
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Cluster != results[j].Cluster {
+			return results[i].Cluster < results[j].Cluster
+		}
+		return results[i].Endpoint < results[j].Endpoint
+	})
+	logSelfTestResults(results)
+	return results
+}
+
+// waitForClusters returns clusters after all UpstreamClusters in informer cache are created,
+// clusters failed to be created in clusterCreationTimeout are skipped.
+func (m *UpstreamClusterController) waitForClusters(stopCh <-chan struct{}, logPrefix string) []*clusters.ClusterInfo {
+	if !cache.WaitForCacheSync(stopCh, m.synced) {
+		return nil
+	}
+	upstreams, err := m.lister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("[%s] failed to list upstream clusters: %v", logPrefix, err)
+		return nil
+	}
+
+	var infos []*clusters.ClusterInfo
+	for _, upstream := range upstreams {
+		var info *clusters.ClusterInfo
+		err := wait.PollImmediateUntil(100*time.Millisecond, func() (bool, error) {
+			var ok bool
+			info, ok = m.Get(upstream.Name)
+			return ok, nil
+		}, timeoutCh(stopCh, clusterCreationTimeout))
+		if err != nil {
+			klog.Errorf("[%s] cluster=%q is not created in %v, skip it", logPrefix, upstream.Name, clusterCreationTimeout)
+			continue
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// selfTestEndpoint reuses the health check to test connectivity, and then requests discovery
+// API which is not open to anonymous users to test the credentials of client config.
+func (m *UpstreamClusterController) selfTestEndpoint(e *clusters.EndpointInfo) error {
+	m.healthCheck(e)
+	if !e.IsReady() {
+		return fmt.Errorf("health check failed: %s", e.UnreadyReason())
+	}
+	err := e.Clientset().CoreV1().RESTClient().Get().AbsPath("/api").Timeout(selfTestRequestTimeout).Do(context.TODO()).Error()
+	switch {
+	case err == nil, errors.IsForbidden(err):
+		// forbidden means the client is authenticated
+		return nil
+	case errors.IsUnauthorized(err):
+		return fmt.Errorf("authentication failed, check credentials in client config: %v", err)
+	default:
+		return err
+	}
+}
+
+func logSelfTestResults(results []SelfTestResult) {
+	passed := 0
+	for _, r := range results {
+		if r.Err != nil {
+			klog.Errorf("[self-test] FAIL cluster=%q endpoint=%q: %v", r.Cluster, r.Endpoint, r.Err)
+			continue
+		}
+		passed++
+		klog.Infof("[self-test] PASS cluster=%q endpoint=%q", r.Cluster, r.Endpoint)
+	}
+	klog.Infof("[self-test] %d of %d upstream endpoints passed", passed, len(results))
+}
+
+// timeoutCh returns a channel which is closed after timeout or when stopCh is closed
+func timeoutCh(stopCh <-chan struct{}, timeout time.Duration) <-chan struct{} {
+	ch := make(chan struct{})
+	go func() {
+		defer close(ch)
+		select {
+		case <-stopCh:
+		case <-time.After(timeout):
+		}
+	}()
+	return ch
+}
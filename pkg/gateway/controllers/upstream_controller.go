@@ -22,39 +22,74 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	requestx509 "k8s.io/apiserver/pkg/authentication/request/x509"
 	"k8s.io/apiserver/pkg/server/dynamiccertificates"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog"
 
 	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
+	"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1/validation"
 	proxyinformers "github.com/kubewharf/kubegateway/pkg/client/informers/proxy/v1alpha1"
 	scheme "github.com/kubewharf/kubegateway/pkg/client/kubernetes/scheme"
 	proxylisters "github.com/kubewharf/kubegateway/pkg/client/listers/proxy/v1alpha1"
 	"github.com/kubewharf/kubegateway/pkg/clusters"
+	"github.com/kubewharf/kubegateway/pkg/gateway/metrics"
 	gatewaynet "github.com/kubewharf/kubegateway/pkg/gateway/net"
 	"github.com/kubewharf/kubegateway/pkg/syncqueue"
 )
 
+const (
+	// hostname sources and results of cluster matching recorded in tls handshakes
+	hostnameSourceSNI      = "sni"
+	hostnameSourceLocalIP  = "local_ip"
+	hostnameSourceNone     = "none"
+	hostnameResultMatched  = "matched"
+	hostnameResultNoMatch  = "no_match"
+	hostnameResultError    = "error"
+	hostnameResultRejected = "rejected"
+)
+
+// errMissingSNI fails handshakes without SNI if SNI is required
+var errMissingSNI = fmt.Errorf("tls handshake without server name indication is rejected")
+
 var _ dynamiccertificates.DynamicClientConfigProvider = &UpstreamClusterController{}
 var _ requestx509.SNIVerifyOptionsProvider = &UpstreamClusterController{}
 
+// UpstreamClusterControllerConfig holds the settings an UpstreamClusterController is created with
+type UpstreamClusterControllerConfig struct {
+	// Cluster is the config every cluster is created with
+	Cluster clusters.ClusterInfoConfig
+	// HealthCheckVerboseReadyz health checks upstream endpoints with /readyz?verbose instead of
+	// /healthz, so that the readyz checks failing an endpoint, e.g. etcd or informer-sync, are
+	// logged and reported in its status.
+	HealthCheckVerboseReadyz bool
+}
+
 type UpstreamClusterController struct {
+	config UpstreamClusterControllerConfig
 	queue  *syncqueue.SyncQueue
 	lister proxylisters.UpstreamClusterLister
 	synced cache.InformerSynced
+	// requireSNI rejects tls handshakes without SNI instead of resolving hostname from the local IP
+	requireSNI bool
+	// reloadFailures tracks consecutive failures of applying UpstreamCluster config
+	reloadFailures *reloadFailures
 
 	clusters.Manager
 }
 
-func NewUpstreamClusterController(upstreamclusterinformer proxyinformers.UpstreamClusterInformer) *UpstreamClusterController {
+func NewUpstreamClusterController(upstreamclusterinformer proxyinformers.UpstreamClusterInformer, config UpstreamClusterControllerConfig) *UpstreamClusterController {
 	m := &UpstreamClusterController{
-		lister:  upstreamclusterinformer.Lister(),
-		synced:  upstreamclusterinformer.Informer().HasSynced,
-		Manager: clusters.NewManager(),
+		config:         config,
+		lister:         upstreamclusterinformer.Lister(),
+		synced:         upstreamclusterinformer.Informer().HasSynced,
+		reloadFailures: newReloadFailures(),
+		Manager:        clusters.NewManager(),
 	}
 	m.queue = syncqueue.NewPassthroughSyncQueue(proxyv1alpha1.SchemeGroupVersion.WithKind("UpstreamCluster"), m.syncUpstreamCluster)
 
@@ -62,6 +97,13 @@ func NewUpstreamClusterController(upstreamclusterinformer proxyinformers.Upstrea
 	return m
 }
 
+// SetRequireSNI sets whether tls handshakes without SNI are rejected. By default, hostname of
+// these handshakes is resolved from the local IP which clients connect to, it allows clients
+// to bypass name-based routing by connecting to an IP directly.
+func (m *UpstreamClusterController) SetRequireSNI(require bool) {
+	m.requireSNI = require
+}
+
 func (m *UpstreamClusterController) Run(stopCh <-chan struct{}) {
 	klog.Info("starting upstream cluster controller")
 	if !cache.WaitForCacheSync(stopCh, m.synced) {
@@ -85,33 +127,55 @@ func (m *UpstreamClusterController) syncUpstreamCluster(obj interface{}) (syncqu
 	clusterName := cluster.Name
 	if errors.IsNotFound(err) {
 		// clean cluster
+		start := time.Now()
 		m.Delete(clusterName)
+		metrics.RecordUpstreamClusterSync(clusterName, "delete", nil, time.Since(start))
+		metrics.ForgetUpstreamClusterConfig(clusterName)
+		m.reloadFailures.reset(clusterName)
 		return syncqueue.Result{}, nil
 	}
 	if err != nil {
 		return syncqueue.Result{}, err
 	}
 
+	// invalid config is never applied, the cluster keeps running the last good config
+	if errs := validation.ValidateUpstreamCluster(cluster); len(errs) > 0 {
+		err := errs.ToAggregate()
+		klog.Errorf("invalid config of cluster: %v, err: %v", cluster.Name, err)
+		m.reloadFailures.recordFailure(clusterName, cluster.Generation, err)
+		return syncqueue.Result{}, nil
+	}
+
 	info, ok := m.Get(clusterName)
 
 	if !ok {
 		// bootstrap
-		clusterInfo, err := clusters.CreateClusterInfo(cluster, GatewayHealthCheck)
+		start := time.Now()
+		clusterInfo, err := clusters.CreateClusterInfoWithConfig(cluster, m.healthCheck, m.config.Cluster)
+		metrics.RecordUpstreamClusterSync(clusterName, "create", err, time.Since(start))
 		if err != nil {
 			klog.Errorf("failed to create cluster: %v, err: %v", cluster.Name, err)
+			m.reloadFailures.recordFailure(clusterName, cluster.Generation, err)
 			return syncqueue.Result{RequeueAfter: 5 * time.Second, MaxRequeueTimes: 3}, nil
 		}
 
 		m.Add(clusterInfo)
+		metrics.RecordUpstreamClusterConfigApplied(clusterName, cluster.Generation, time.Now())
+		m.reloadFailures.reset(clusterName)
 		return syncqueue.Result{}, err
 	}
 
 	// sync
+	start := time.Now()
 	err = info.Sync(cluster)
+	metrics.RecordUpstreamClusterSync(clusterName, "update", err, time.Since(start))
 	if err != nil {
 		klog.Errorf("failed to sync cluster: %v, err: %v", cluster.Name, err)
+		m.reloadFailures.recordFailure(clusterName, cluster.Generation, err)
 		return syncqueue.Result{RequeueAfter: 5 * time.Second, MaxRequeueTimes: 3}, nil
 	}
+	metrics.RecordUpstreamClusterConfigApplied(clusterName, cluster.Generation, time.Now())
+	m.reloadFailures.reset(clusterName)
 
 	return syncqueue.Result{}, nil
 }
@@ -126,13 +190,21 @@ func (m *UpstreamClusterController) WrapGetConfigForClient(getConfigFunc dynamic
 		// if the client set SNI information, just use our "normal" SNI flow
 		// Get request host name from SNI information or inspect the requested IP
 		hostname := clientHello.ServerName
+		source := hostnameSourceSNI
+		if len(hostname) == 0 && m.requireSNI {
+			klog.V(3).Infof("reject tls handshake without SNI from %v", clientHello.Conn.RemoteAddr())
+			metrics.RecordTLSHostnameResolution(hostnameSourceNone, hostnameResultRejected)
+			return nil, errMissingSNI
+		}
 		if len(hostname) == 0 {
 			// if the client didn't set SNI, then we need to inspect the requested IP so that we can choose
 			// a certificate from our list if we specifically handle that IP.  This can happen when an IP is specifically mapped by name.
 			var err error
+			source = hostnameSourceLocalIP
 			hostname, _, err = net.SplitHostPort(clientHello.Conn.LocalAddr().String())
 			if err != nil {
 				klog.Errorf("faild to get hostname from clientHello's conn: %v", err)
+				metrics.RecordTLSHostnameResolution(source, hostnameResultError)
 				return baseTLSConfig, nil
 			}
 		}
@@ -141,8 +213,10 @@ func (m *UpstreamClusterController) WrapGetConfigForClient(getConfigFunc dynamic
 
 		cluster, ok := m.Get(hostname)
 		if !ok {
+			metrics.RecordTLSHostnameResolution(source, hostnameResultNoMatch)
 			return baseTLSConfig, nil
 		}
+		metrics.RecordTLSHostnameResolution(source, hostnameResultMatched)
 
 		tlsConfig, ok := cluster.LoadTLSConfig()
 		if !ok {
@@ -152,9 +226,10 @@ func (m *UpstreamClusterController) WrapGetConfigForClient(getConfigFunc dynamic
 		tlsConfigCopy := baseTLSConfig.Clone()
 
 		if tlsConfig.ClientCAs != nil {
-			// Populate PeerCertificates in requests, but don't reject connections without certificates
-			// This allows certificates to be validated by authenticators, while still allowing other auth types
-			tlsConfigCopy.ClientAuth = tls.RequestClientCert
+			// By default, populate PeerCertificates in requests, but don't reject connections without certificates
+			// This allows certificates to be validated by authenticators, while still allowing other auth types.
+			// Clusters which mandate mTLS require and verify client certificates in handshake.
+			tlsConfigCopy.ClientAuth = tlsConfig.ClientAuth
 			tlsConfigCopy.ClientCAs = tlsConfig.ClientCAs
 		}
 		if len(tlsConfig.Certificates) > 0 {
@@ -178,14 +253,67 @@ func (m *UpstreamClusterController) SNIVerifyOptions(host string) (x509.VerifyOp
 	return cluster.LoadVerifyOptions()
 }
 
+const (
+	// healthCheckReasonAuthenticationFailed means the upstream responds 401 to health checks,
+	// the credentials of client config are invalid or expired
+	healthCheckReasonAuthenticationFailed = "AuthenticationFailed"
+	// healthCheckReasonAuthorizationFailed means the upstream responds 403 to health checks,
+	// the user of client config is not allowed to get health check path
+	healthCheckReasonAuthorizationFailed = "AuthorizationFailed"
+)
+
+// healthCheckAuthFailure returns the reason and message of a 401 or 403 health check error
+func healthCheckAuthFailure(err error) (string, string) {
+	if errors.IsUnauthorized(err) {
+		return healthCheckReasonAuthenticationFailed, fmt.Sprintf("credentials of client config are rejected, check the bearer token or client certificate: %v", err)
+	}
+	return healthCheckReasonAuthorizationFailed, fmt.Sprintf("user of client config is not allowed to get health check path, check its RBAC permissions: %v", err)
+}
+
+// requestHealthCheck requests the health check path of endpoint, /readyz is requested with
+// verbose if verboseReadyz is true.
+func requestHealthCheck(e *clusters.EndpointInfo, path string, verboseReadyz bool) rest.Result {
+	request := e.Clientset().CoreV1().RESTClient().
+		Get().AbsPath(path).Timeout(e.HealthCheckTimeout())
+	if path == "/readyz" && verboseReadyz {
+		request = request.Param("verbose", "")
+	}
+	return request.Do(context.TODO())
+}
+
 // health check endpoint periodically
 func GatewayHealthCheck(e *clusters.EndpointInfo) (done bool) {
+	return gatewayHealthCheck(e, false)
+}
+
+// healthCheck is GatewayHealthCheck with verbose readyz set in the config of controller
+func (m *UpstreamClusterController) healthCheck(e *clusters.EndpointInfo) (done bool) {
+	return gatewayHealthCheck(e, m.config.HealthCheckVerboseReadyz)
+}
+
+func gatewayHealthCheck(e *clusters.EndpointInfo, verboseReadyz bool) (done bool) {
 	done = false
 
-	// TODO: use readyz if all kubernetes master version is greater than v1.16
-	result := e.Clientset().CoreV1().RESTClient().
-		Get().AbsPath("/healthz").Timeout(5 * time.Second).Do(context.TODO())
+	path, fallbackToHealthz := e.HealthCheckPath()
+	if len(path) == 0 {
+		path = "/healthz"
+		if verboseReadyz {
+			path = "/readyz"
+		}
+	}
+	start := time.Now()
+	result := requestHealthCheck(e, path, verboseReadyz)
 	err := result.Error()
+	if path == "/readyz" && fallbackToHealthz && errors.IsNotFound(err) {
+		// readyz is served by apiservers since v1.16
+		klog.V(2).Infof("upstream health check falls back to /healthz, cluster=%q endpoint=%q", e.Cluster, e.Endpoint)
+		path = "/healthz"
+		start = time.Now()
+		result = requestHealthCheck(e, path, verboseReadyz)
+		err = result.Error()
+	}
+	latency := time.Since(start)
+	verbose := path == "/readyz" && verboseReadyz
 
 	var reason, message string
 	statusCode := 0
@@ -194,6 +322,20 @@ func GatewayHealthCheck(e *clusters.EndpointInfo) (done bool) {
 		if os.IsTimeout(err) {
 			reason = "Timeout"
 			message = err.Error()
+		} else if errors.IsUnauthorized(err) || errors.IsForbidden(err) {
+			// the upstream is probably up, but it rejects credentials of gateway
+			reason, message = healthCheckAuthFailure(err)
+			klog.Errorf("upstream health check failed with auth error, credentials in spec.clientConfig are probably wrong or lack permissions, "+
+				"it is not an upstream outage, cluster=%q endpoint=%q reason=%q message=%q", e.Cluster, e.Endpoint, reason, message)
+			e.RecordHealthCheck(false, reason, message)
+			return done
+		} else if failure, ok := clusters.RecordTLSVerificationError(e.Cluster, e.Endpoint, "health_check", err); ok {
+			// the diagnostics are logged, it is probably a misconfiguration of spec.clientConfig
+			e.RecordHealthCheck(false, "TLSVerificationFailed", failure.Reason+": "+failure.Detail)
+			return done
+		} else if failed := failedReadyzChecks(err); verbose && len(failed) > 0 {
+			reason = "NotReady"
+			message = fmt.Sprintf("request %s/readyz, failed checks: %s", e.Endpoint, strings.Join(failed, ", "))
 		} else {
 			switch status := err.(type) {
 			case errors.APIStatus:
@@ -207,13 +349,14 @@ func GatewayHealthCheck(e *clusters.EndpointInfo) (done bool) {
 	} else {
 		result.StatusCode(&statusCode)
 		if statusCode == http.StatusOK {
-			e.UpdateStatus(true, "", "")
+			e.RecordHealthCheckLatency(latency)
+			e.RecordHealthCheck(true, "", "")
 			return done
 		}
 		reason = "NotReady"
-		message = fmt.Sprintf("request %s/healthz, got response code is %v", e.Endpoint, statusCode)
+		message = fmt.Sprintf("request %s%s, got response code is %v", e.Endpoint, path, statusCode)
 	}
 	klog.Errorf("upstream health check failed, cluster=%q endpoint=%q reason=%q message=%q", e.Cluster, e.Endpoint, reason, message)
-	e.UpdateStatus(false, reason, message)
+	e.RecordHealthCheck(false, reason, message)
 	return done
 }
@@ -69,7 +69,11 @@ func (o *ControlPlaneOptions) Complete() error {
 			return err
 		}
 	}
-	if o.SecureServing != nil {
+	if o.SecureServing != nil && !o.SecureServing.SelfSignedCerts {
+		if err := o.SecureServing.requireServingCert(); err != nil {
+			return err
+		}
+	} else if o.SecureServing != nil {
 		if err := o.SecureServing.MaybeDefaultWithSelfSignedCerts(o.ServerRun.AdvertiseAddress.String(), []string{"kubernetes.default.svc", "kubernetes.default", "kubernetes"}, []net.IP{}); err != nil {
 			return fmt.Errorf("error creating self-signed certificates: %v", err)
 		}
@@ -17,6 +17,7 @@ package options
 import (
 	"fmt"
 	"net"
+	"path"
 	"strconv"
 
 	"github.com/libp2p/go-reuseport"
@@ -27,6 +28,7 @@ import (
 	"k8s.io/apiserver/pkg/server"
 	genericoptions "k8s.io/apiserver/pkg/server/options"
 	"k8s.io/client-go/rest"
+	certutil "k8s.io/client-go/util/cert"
 )
 
 // secure serving options with reuse port and loop back
@@ -36,6 +38,9 @@ type SecureServingOptions struct {
 	ReusePort           bool
 	OtherPorts          []int
 	LoopbackClientToken string
+	// SelfSignedCerts generates self-signed serving certs if none is configured, otherwise
+	// startup fails without serving certs
+	SelfSignedCerts bool
 }
 
 func NewSecureServingOptions() *SecureServingOptions {
@@ -49,6 +54,7 @@ func NewSecureServingOptions() *SecureServingOptions {
 
 	return &SecureServingOptions{
 		SecureServingOptionsWithLoopback: sso.WithLoopback(),
+		SelfSignedCerts:                  true,
 	}
 }
 
@@ -86,6 +92,38 @@ func (s *SecureServingOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.IntSliceVar(&s.OtherPorts, "other-secure-ports", s.OtherPorts, "A list of ports which to serve HTTPS with authentication and authorization. The same with --secure-ports")
 	fs.BoolVar(&s.ReusePort, "enable-reuse-port", s.ReusePort, "enable reuse port on secure serving port")
 	fs.StringVar(&s.LoopbackClientToken, "loopback-client-token", s.LoopbackClientToken, "privileged loopback client token used for reuse port mode")
+	fs.BoolVar(&s.SelfSignedCerts, "enable-self-signed-certs", s.SelfSignedCerts, ""+
+		"If true, self-signed serving certs are generated when neither --tls-cert-file and --tls-private-key-file nor "+
+		"existing certs in --cert-dir are provided. If false, startup fails without serving certs instead, so that a "+
+		"misconfigured deployment never serves an untrusted cert.")
+}
+
+// requireServingCert returns an error if no serving cert is provided, it is used instead of
+// MaybeDefaultWithSelfSignedCerts when self-signed certs are disabled. Like the latter, certs
+// in cert directory are used if cert and key files are not specified.
+func (s *SecureServingOptions) requireServingCert() error {
+	if s == nil || s.SecureServingOptions == nil || (s.BindPort == 0 && s.Listener == nil) {
+		return nil
+	}
+	keyCert := &s.ServerCert.CertKey
+	if len(keyCert.CertFile) != 0 || len(keyCert.KeyFile) != 0 {
+		return nil
+	}
+	if len(s.ServerCert.CertDirectory) > 0 && len(s.ServerCert.PairName) > 0 {
+		certFile := path.Join(s.ServerCert.CertDirectory, s.ServerCert.PairName+".crt")
+		keyFile := path.Join(s.ServerCert.CertDirectory, s.ServerCert.PairName+".key")
+		canRead, err := certutil.CanReadCertAndKey(certFile, keyFile)
+		if err != nil {
+			return err
+		}
+		if canRead {
+			keyCert.CertFile = certFile
+			keyCert.KeyFile = keyFile
+			return nil
+		}
+	}
+	return fmt.Errorf("no serving cert is provided and self-signed certs are disabled by --enable-self-signed-certs=false, " +
+		"set --tls-cert-file and --tls-private-key-file, or provide certs in --cert-dir")
 }
 
 // ApplyTo fills up serving information in the server configuration.
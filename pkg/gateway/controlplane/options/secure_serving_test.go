@@ -15,9 +15,13 @@
 package options
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	genericoptions "k8s.io/apiserver/pkg/server/options"
+	certutil "k8s.io/client-go/util/cert"
 )
 
 func TestSecureServingOptions_Validate(t *testing.T) {
@@ -73,3 +77,50 @@ func TestSecureServingOptions_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestSecureServingOptions_requireServingCert(t *testing.T) {
+	certDir, err := ioutil.TempDir("", "certs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(certDir)
+	cert, key, err := certutil.GenerateSelfSignedCertKey("localhost", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(certDir, "apiserver.crt"), cert, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(certDir, "apiserver.key"), key, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		certFile     string
+		certDir      string
+		wantErr      bool
+		wantCertFile string
+	}{
+		{"no certs", "", "", true, ""},
+		{"cert files", "/etc/kube-gateway/tls.crt", "", false, "/etc/kube-gateway/tls.crt"},
+		{"certs in cert dir", "", certDir, false, filepath.Join(certDir, "apiserver.crt")},
+		{"no certs in cert dir", "", filepath.Join(certDir, "empty"), true, ""},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSecureServingOptions()
+			s.SelfSignedCerts = false
+			s.ServerCert.CertKey.CertFile = tt.certFile
+			s.ServerCert.CertDirectory = tt.certDir
+			err := s.requireServingCert()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("requireServingCert() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := s.ServerCert.CertKey.CertFile; got != tt.wantCertFile {
+				t.Errorf("requireServingCert() cert file = %v, want %v", got, tt.wantCertFile)
+			}
+		})
+	}
+}
@@ -21,7 +21,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/apiserver/pkg/server/routes"
 	serverstorage "k8s.io/apiserver/pkg/server/storage"
+	"k8s.io/component-base/logs"
 	"k8s.io/kubernetes/pkg/master"
 
 	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
@@ -81,6 +83,12 @@ func (c *CompletedConfig) New(delegationTarget genericapiserver.DelegationTarget
 		}
 	}
 
+	if !c.GenericConfig.EnableProfiling {
+		// log level endpoint is installed along with profiling by generic apiserver,
+		// but we always want to adjust log level at runtime without restart.
+		routes.DebugFlags{}.Install(s.Handler.NonGoRestfulMux, "v", routes.StringFlagPutHandler(logs.GlogSetter))
+	}
+
 	// Install Legacy APIs
 	// we only need namespace, secret, serviceaccount and rbac in control plane registry
 	legacyRESTStorageProviders := []server.LegecyRESTStorageProvider{
@@ -0,0 +1,76 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debug
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/kubewharf/kubegateway/pkg/clusters"
+)
+
+// ClusterHealthPath is served by the proxy server, requests are authorized as non-resource requests
+const ClusterHealthPath = "/apis/proxy.kubegateway/clusters"
+
+type ClusterHealth struct {
+	Cluster string `json:"cluster"`
+	// ReadyEndpoints is the number of endpoints which requests can be routed to
+	ReadyEndpoints int                       `json:"readyEndpoints"`
+	Endpoints      []clusters.EndpointHealth `json:"endpoints"`
+}
+
+// ClusterHealthHandler returns the handler which reports the current status of upstream
+// clusters and their endpoints for dashboards and alerting:
+//
+//	GET /apis/proxy.kubegateway/clusters[?cluster=<name>]
+func ClusterHealthHandler(clusterManager clusters.Manager) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			http.Error(w, "only GET is allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		infos := clusterManager.List()
+		if clusterName := req.URL.Query().Get("cluster"); len(clusterName) > 0 {
+			info, ok := clusterManager.Get(clusterName)
+			if !ok {
+				http.Error(w, "cluster not found", http.StatusNotFound)
+				return
+			}
+			infos = []*clusters.ClusterInfo{info}
+		}
+
+		ret := []ClusterHealth{}
+		for _, info := range infos {
+			health := ClusterHealth{Cluster: info.Cluster, Endpoints: []clusters.EndpointHealth{}}
+			info.Endpoints.Range(func(name string, e *clusters.EndpointInfo) bool {
+				endpoint := e.Health()
+				if endpoint.Ready {
+					health.ReadyEndpoints++
+				}
+				health.Endpoints = append(health.Endpoints, endpoint)
+				return true
+			})
+			sort.Slice(health.Endpoints, func(i, j int) bool {
+				return health.Endpoints[i].Endpoint < health.Endpoints[j].Endpoint
+			})
+			ret = append(ret, health)
+		}
+		sort.Slice(ret, func(i, j int) bool {
+			return ret[i].Cluster < ret[j].Cluster
+		})
+		writeJSON(w, ret)
+	})
+}
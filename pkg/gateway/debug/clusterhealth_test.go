@@ -0,0 +1,87 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debug
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
+	"github.com/kubewharf/kubegateway/pkg/clusters"
+)
+
+func TestClusterHealthHandler(t *testing.T) {
+	cluster, err := clusters.CreateClusterInfo(newTestUpstreamCluster(proxyv1alpha1.UpstreamClusterSpec{
+		Servers: []proxyv1alpha1.UpstreamClusterServer{
+			{Endpoint: "https://127.0.0.1:443"},
+			{Endpoint: "https://127.0.0.2:443"},
+		},
+	}), func(*clusters.EndpointInfo) bool { return true })
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cluster.Stop()
+	e, _ := cluster.Endpoints.Load("https://127.0.0.1:443")
+	e.UpdateStatus(true, "", "")
+	e, _ = cluster.Endpoints.Load("https://127.0.0.2:443")
+	e.UpdateStatus(true, "", "")
+	e.UpdateStatus(false, "Timeout", "health check timed out")
+	manager := clusters.NewManager()
+	manager.Add(cluster)
+	handler := ClusterHealthHandler(manager)
+
+	tests := []struct {
+		name         string
+		method       string
+		query        string
+		wantCode     int
+		wantClusters int
+	}{
+		{"only GET is allowed", http.MethodPost, "", http.StatusMethodNotAllowed, 0},
+		{"all", http.MethodGet, "", http.StatusOK, 1},
+		{"cluster", http.MethodGet, "?cluster=a.cluster", http.StatusOK, 1},
+		{"cluster not found", http.MethodGet, "?cluster=b.cluster", http.StatusNotFound, 0},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(tt.method, ClusterHealthPath+tt.query, nil))
+			if w.Code != tt.wantCode {
+				t.Fatalf("status code = %v, want %v, body: %s", w.Code, tt.wantCode, w.Body.String())
+			}
+			if w.Code != http.StatusOK {
+				return
+			}
+			var got []ClusterHealth
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != tt.wantClusters {
+				t.Fatalf("clusters = %v, want %v", len(got), tt.wantClusters)
+			}
+			health := got[0]
+			if health.ReadyEndpoints != 1 || len(health.Endpoints) != 2 {
+				t.Fatalf("ready endpoints = %v, endpoints = %v, want 1, 2", health.ReadyEndpoints, len(health.Endpoints))
+			}
+			unhealthy := health.Endpoints[1]
+			if unhealthy.Ready || unhealthy.LastFailureReason != "Timeout" || unhealthy.LastTransitionTime == nil {
+				t.Errorf("unexpected endpoint health %#v", unhealthy)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debug
+
+import (
+	"net/http"
+
+	"k8s.io/apiserver/pkg/server/mux"
+
+	"github.com/kubewharf/kubegateway/pkg/clusters"
+)
+
+const EphemeralEndpointsPath = "/debug/endpoints/ephemeral"
+
+type ClusterEphemeralEndpoints struct {
+	Cluster   string                       `json:"cluster"`
+	Endpoints []clusters.EphemeralEndpoint `json:"endpoints"`
+}
+
+// InstallEphemeralEndpointsHandler registers the handler which adds endpoints to a running
+// cluster, e.g. an extra apiserver spun up for emergency capacity during incidents:
+//
+//	GET    /debug/endpoints/ephemeral[?cluster=<name>]                 lists ephemeral endpoints
+//	POST   /debug/endpoints/ephemeral?cluster=<name>&endpoint=<url>    adds the endpoint
+//	DELETE /debug/endpoints/ephemeral?cluster=<name>&endpoint=<url>    removes the endpoint
+//
+// Ephemeral endpoints live in memory only and are lost after restarting.
+func InstallEphemeralEndpointsHandler(c *mux.PathRecorderMux, clusterManager clusters.Manager) {
+	c.UnlistedHandle(EphemeralEndpointsPath, EphemeralEndpointsHandler(clusterManager))
+}
+
+func EphemeralEndpointsHandler(clusterManager clusters.Manager) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		clusterName := req.URL.Query().Get("cluster")
+		endpoint := req.URL.Query().Get("endpoint")
+		var cluster *clusters.ClusterInfo
+		if len(clusterName) > 0 {
+			info, ok := clusterManager.Get(clusterName)
+			if !ok {
+				http.Error(w, "cluster not found", http.StatusNotFound)
+				return
+			}
+			cluster = info
+		}
+
+		switch req.Method {
+		case http.MethodGet:
+			infos := clusterManager.List()
+			if cluster != nil {
+				infos = []*clusters.ClusterInfo{cluster}
+			}
+			ret := []ClusterEphemeralEndpoints{}
+			for _, info := range infos {
+				endpoints := info.EphemeralEndpoints()
+				if len(endpoints) == 0 && cluster == nil {
+					continue
+				}
+				ret = append(ret, ClusterEphemeralEndpoints{Cluster: info.Cluster, Endpoints: endpoints})
+			}
+			writeJSON(w, ret)
+		case http.MethodPost:
+			if cluster == nil || len(endpoint) == 0 {
+				http.Error(w, "cluster and endpoint must be specified", http.StatusBadRequest)
+				return
+			}
+			added, err := cluster.AddEphemeralEndpoint(endpoint, userName(req))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			writeJSON(w, added)
+		case http.MethodDelete:
+			if cluster == nil || len(endpoint) == 0 {
+				http.Error(w, "cluster and endpoint must be specified", http.StatusBadRequest)
+				return
+			}
+			deleted, err := cluster.DeleteEphemeralEndpoint(endpoint, userName(req))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if !deleted {
+				http.Error(w, "endpoint is not an ephemeral endpoint", http.StatusNotFound)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		default:
+			http.Error(w, "only GET, POST and DELETE are allowed", http.StatusMethodNotAllowed)
+		}
+	})
+}
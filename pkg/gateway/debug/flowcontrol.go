@@ -0,0 +1,69 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debug
+
+import (
+	"net/http"
+
+	"k8s.io/apiserver/pkg/server/mux"
+
+	"github.com/kubewharf/kubegateway/pkg/clusters"
+	"github.com/kubewharf/kubegateway/pkg/flowcontrol"
+)
+
+const FlowControlPath = "/debug/flowcontrol"
+
+type ClusterFlowControlStatus struct {
+	Cluster      string               `json:"cluster"`
+	FlowControls []flowcontrol.Status `json:"flowControls"`
+}
+
+// InstallFlowControlHandler registers the handler which dumps live flow control status
+// of all proxied clusters, use ?cluster=<name> to select one cluster.
+func InstallFlowControlHandler(c *mux.PathRecorderMux, clusterManager clusters.Manager) {
+	c.UnlistedHandle(FlowControlPath, FlowControlHandler(clusterManager))
+}
+
+// FlowControlHandler returns the live status of the same flow control objects the dispatcher consults
+func FlowControlHandler(clusterManager clusters.Manager) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			http.Error(w, "only GET is allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var infos []*clusters.ClusterInfo
+		if name := req.URL.Query().Get("cluster"); len(name) > 0 {
+			info, ok := clusterManager.Get(name)
+			if !ok {
+				http.Error(w, "cluster not found", http.StatusNotFound)
+				return
+			}
+			infos = append(infos, info)
+		} else {
+			infos = clusterManager.List()
+		}
+
+		ret := []ClusterFlowControlStatus{}
+		for _, info := range infos {
+			ret = append(ret, ClusterFlowControlStatus{
+				Cluster:      info.Cluster,
+				FlowControls: info.FlowControlStatus(),
+			})
+		}
+
+		writeJSON(w, ret)
+	})
+}
@@ -0,0 +1,71 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debug
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"k8s.io/client-go/rest"
+
+	"github.com/kubewharf/kubegateway/pkg/clusters"
+)
+
+func TestFlowControlHandler(t *testing.T) {
+	manager := clusters.NewManager()
+	manager.Add(clusters.NewEmptyClusterInfo("b.cluster", &rest.Config{}, nil))
+	manager.Add(clusters.NewEmptyClusterInfo("a.cluster", &rest.Config{}, nil))
+
+	tests := []struct {
+		name         string
+		query        string
+		wantCode     int
+		wantClusters []string
+	}{
+		{"all clusters", "", http.StatusOK, []string{"a.cluster", "b.cluster"}},
+		{"one cluster", "?cluster=b.cluster", http.StatusOK, []string{"b.cluster"}},
+		{"cluster not found", "?cluster=c.cluster", http.StatusNotFound, nil},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			FlowControlHandler(manager).ServeHTTP(w, httptest.NewRequest(http.MethodGet, FlowControlPath+tt.query, nil))
+			if w.Code != tt.wantCode {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantCode)
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			var got []ClusterFlowControlStatus
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(got) != len(tt.wantClusters) {
+				t.Fatalf("got %v clusters, want %v", len(got), len(tt.wantClusters))
+			}
+			for j := range got {
+				if got[j].Cluster != tt.wantClusters[j] {
+					t.Errorf("cluster[%d] = %v, want %v", j, got[j].Cluster, tt.wantClusters[j])
+				}
+				if len(got[j].FlowControls) != 1 || got[j].FlowControls[0].Name != "system-default" {
+					t.Errorf("flowControls = %+v, want the default flow control only", got[j].FlowControls)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debug
+
+import (
+	"net/http"
+	"sort"
+
+	"k8s.io/apiserver/pkg/server/mux"
+
+	"github.com/kubewharf/kubegateway/pkg/clusters"
+)
+
+const HealthHistoryPath = "/debug/endpoints/health"
+
+type EndpointHealthHistory struct {
+	Cluster     string                      `json:"cluster"`
+	Endpoint    string                      `json:"endpoint"`
+	Transitions []clusters.HealthTransition `json:"transitions"`
+	// HealthCheckBackoff is the current interval between health checks of an unhealthy endpoint
+	HealthCheckBackoff string `json:"healthCheckBackoff,omitempty"`
+}
+
+// InstallHealthHistoryHandler registers the handler which exports recent status transitions
+// and health check backoff of endpoints for post-incident analysis:
+//
+//	GET /debug/endpoints/health[?cluster=<name>[&endpoint=<url>]]
+//
+// Transitions live in memory only and are lost after restarting.
+func InstallHealthHistoryHandler(c *mux.PathRecorderMux, clusterManager clusters.Manager) {
+	c.UnlistedHandle(HealthHistoryPath, HealthHistoryHandler(clusterManager))
+}
+
+func HealthHistoryHandler(clusterManager clusters.Manager) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			http.Error(w, "only GET is allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		clusterName := req.URL.Query().Get("cluster")
+		endpoint := req.URL.Query().Get("endpoint")
+		if len(endpoint) > 0 && len(clusterName) == 0 {
+			http.Error(w, "cluster must be specified with endpoint", http.StatusBadRequest)
+			return
+		}
+
+		infos := clusterManager.List()
+		if len(clusterName) > 0 {
+			info, ok := clusterManager.Get(clusterName)
+			if !ok {
+				http.Error(w, "cluster not found", http.StatusNotFound)
+				return
+			}
+			infos = []*clusters.ClusterInfo{info}
+		}
+
+		ret := []EndpointHealthHistory{}
+		for _, info := range infos {
+			info.Endpoints.Range(func(name string, e *clusters.EndpointInfo) bool {
+				if len(endpoint) == 0 || name == endpoint {
+					history := EndpointHealthHistory{Cluster: info.Cluster, Endpoint: name, Transitions: e.HealthHistory()}
+					if backoff := e.HealthCheckBackoff(); backoff > 0 {
+						history.HealthCheckBackoff = backoff.String()
+					}
+					ret = append(ret, history)
+				}
+				return true
+			})
+		}
+		if len(endpoint) > 0 && len(ret) == 0 {
+			http.Error(w, "endpoint not found", http.StatusNotFound)
+			return
+		}
+		sort.Slice(ret, func(i, j int) bool {
+			if ret[i].Cluster != ret[j].Cluster {
+				return ret[i].Cluster < ret[j].Cluster
+			}
+			return ret[i].Endpoint < ret[j].Endpoint
+		})
+		writeJSON(w, ret)
+	})
+}
@@ -0,0 +1,85 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debug
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
+	"github.com/kubewharf/kubegateway/pkg/clusters"
+)
+
+func TestHealthHistoryHandler(t *testing.T) {
+	cluster, err := clusters.CreateClusterInfo(newTestUpstreamCluster(proxyv1alpha1.UpstreamClusterSpec{
+		Servers: []proxyv1alpha1.UpstreamClusterServer{
+			{Endpoint: "https://127.0.0.1:443"},
+			{Endpoint: "https://127.0.0.2:443"},
+		},
+	}), func(*clusters.EndpointInfo) bool { return true })
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cluster.Stop()
+	e, _ := cluster.Endpoints.Load("https://127.0.0.1:443")
+	e.UpdateStatus(true, "", "")
+	e.UpdateStatus(false, "Timeout", "")
+	manager := clusters.NewManager()
+	manager.Add(cluster)
+	handler := HealthHistoryHandler(manager)
+
+	tests := []struct {
+		name            string
+		method          string
+		query           string
+		wantCode        int
+		wantEndpoints   int
+		wantTransitions int
+	}{
+		{"only GET is allowed", http.MethodPost, "", http.StatusMethodNotAllowed, 0, 0},
+		{"all", http.MethodGet, "", http.StatusOK, 2, 2},
+		{"cluster", http.MethodGet, "?cluster=a.cluster", http.StatusOK, 2, 2},
+		{"endpoint", http.MethodGet, "?cluster=a.cluster&endpoint=https://127.0.0.1:443", http.StatusOK, 1, 2},
+		{"cluster not found", http.MethodGet, "?cluster=b.cluster", http.StatusNotFound, 0, 0},
+		{"endpoint not found", http.MethodGet, "?cluster=a.cluster&endpoint=https://127.0.0.3:443", http.StatusNotFound, 0, 0},
+		{"endpoint without cluster", http.MethodGet, "?endpoint=https://127.0.0.1:443", http.StatusBadRequest, 0, 0},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(tt.method, HealthHistoryPath+tt.query, nil))
+			if w.Code != tt.wantCode {
+				t.Fatalf("status code = %v, want %v, body: %s", w.Code, tt.wantCode, w.Body.String())
+			}
+			if w.Code != http.StatusOK {
+				return
+			}
+			var got []EndpointHealthHistory
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			transitions := 0
+			for _, h := range got {
+				transitions += len(h.Transitions)
+			}
+			if len(got) != tt.wantEndpoints || transitions != tt.wantTransitions {
+				t.Errorf("got %v endpoints and %v transitions, want %v and %v", len(got), transitions, tt.wantEndpoints, tt.wantTransitions)
+			}
+		})
+	}
+}
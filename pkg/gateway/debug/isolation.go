@@ -0,0 +1,108 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debug
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"k8s.io/apiserver/pkg/server/mux"
+	"k8s.io/klog"
+
+	"github.com/kubewharf/kubegateway/pkg/clusters"
+)
+
+const IsolationsPath = "/debug/isolations"
+
+type ClusterIsolations struct {
+	Cluster    string               `json:"cluster"`
+	Isolations []clusters.Isolation `json:"isolations"`
+}
+
+// InstallIsolationsHandler registers the handler which isolates requests of a user onto a
+// dedicated endpoint subset of a cluster, e.g. quarantining a noisy tenant during incidents:
+//
+//	GET    /debug/isolations[?cluster=<name>]          lists isolations
+//	PUT    /debug/isolations?cluster=<name>            isolates the user in body
+//	DELETE /debug/isolations?cluster=<name>&user=<name> removes the isolation
+//
+// Isolations live in memory only and are lost after restarting.
+func InstallIsolationsHandler(c *mux.PathRecorderMux, clusterManager clusters.Manager) {
+	c.UnlistedHandle(IsolationsPath, IsolationsHandler(clusterManager))
+}
+
+func IsolationsHandler(clusterManager clusters.Manager) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		clusterName := req.URL.Query().Get("cluster")
+		var cluster *clusters.ClusterInfo
+		if len(clusterName) > 0 {
+			info, ok := clusterManager.Get(clusterName)
+			if !ok {
+				http.Error(w, "cluster not found", http.StatusNotFound)
+				return
+			}
+			cluster = info
+		}
+
+		switch req.Method {
+		case http.MethodGet:
+			infos := clusterManager.List()
+			if cluster != nil {
+				infos = []*clusters.ClusterInfo{cluster}
+			}
+			ret := []ClusterIsolations{}
+			for _, info := range infos {
+				isolations := info.Isolations()
+				if len(isolations) == 0 && cluster == nil {
+					continue
+				}
+				ret = append(ret, ClusterIsolations{Cluster: info.Cluster, Isolations: isolations})
+			}
+			writeJSON(w, ret)
+		case http.MethodPut:
+			if cluster == nil {
+				http.Error(w, "cluster must be specified", http.StatusBadRequest)
+				return
+			}
+			isolation := clusters.Isolation{}
+			if err := json.NewDecoder(io.LimitReader(req.Body, maxOverrideBodyBytes)).Decode(&isolation); err != nil {
+				http.Error(w, "failed to decode isolation: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			if err := cluster.SetIsolation(isolation); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			klog.Infof("[isolation] user=%q cluster=%q isolates requests of user=%q onto endpoints %v",
+				userName(req), cluster.Cluster, isolation.User, isolation.Endpoints)
+			writeJSON(w, isolation)
+		case http.MethodDelete:
+			user := req.URL.Query().Get("user")
+			if cluster == nil || len(user) == 0 {
+				http.Error(w, "cluster and user must be specified", http.StatusBadRequest)
+				return
+			}
+			if !cluster.DeleteIsolation(user) {
+				http.Error(w, "user is not isolated", http.StatusNotFound)
+				return
+			}
+			klog.Infof("[isolation] user=%q cluster=%q removes isolation of user=%q", userName(req), cluster.Cluster, user)
+			w.WriteHeader(http.StatusOK)
+		default:
+			http.Error(w, "only GET, PUT and DELETE are allowed", http.StatusMethodNotAllowed)
+		}
+	})
+}
@@ -0,0 +1,67 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debug
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
+	"github.com/kubewharf/kubegateway/pkg/clusters"
+)
+
+func TestIsolationsHandler(t *testing.T) {
+	cluster, err := clusters.CreateClusterInfo(newTestUpstreamCluster(proxyv1alpha1.UpstreamClusterSpec{
+		Servers: []proxyv1alpha1.UpstreamClusterServer{{Endpoint: "https://127.0.0.1:443"}, {Endpoint: "https://127.0.0.2:443"}},
+	}), func(*clusters.EndpointInfo) bool { return true })
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cluster.Stop()
+	manager := clusters.NewManager()
+	manager.Add(cluster)
+	handler := IsolationsHandler(manager)
+
+	tests := []struct {
+		name           string
+		method         string
+		query          string
+		body           string
+		wantCode       int
+		wantIsolations int
+	}{
+		{"cluster is required", http.MethodPut, "", `{"user":"noisy","endpoints":["https://127.0.0.2:443"]}`, http.StatusBadRequest, 0},
+		{"unknown endpoint", http.MethodPut, "?cluster=a.cluster", `{"user":"noisy","endpoints":["https://127.0.0.3:443"]}`, http.StatusBadRequest, 0},
+		{"isolate", http.MethodPut, "?cluster=a.cluster", `{"user":"noisy","endpoints":["https://127.0.0.2:443"]}`, http.StatusOK, 1},
+		{"list", http.MethodGet, "", "", http.StatusOK, 1},
+		{"delete", http.MethodDelete, "?cluster=a.cluster&user=noisy", "", http.StatusOK, 0},
+		{"delete again", http.MethodDelete, "?cluster=a.cluster&user=noisy", "", http.StatusNotFound, 0},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(tt.method, IsolationsPath+tt.query, strings.NewReader(tt.body)))
+			if w.Code != tt.wantCode {
+				t.Errorf("status code = %v, want %v, body: %s", w.Code, tt.wantCode, w.Body.String())
+			}
+			if got := len(cluster.Isolations()); got != tt.wantIsolations {
+				t.Errorf("got %v isolations, want %v", got, tt.wantIsolations)
+			}
+		})
+	}
+}
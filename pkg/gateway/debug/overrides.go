@@ -0,0 +1,148 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debug
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/server/mux"
+	"k8s.io/klog"
+
+	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
+	"github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1/validation"
+	"github.com/kubewharf/kubegateway/pkg/clusters"
+)
+
+const (
+	FlowControlOverridesPath = "/debug/flowcontrol/overrides"
+
+	// maxOverrideBodyBytes limits the size of override request body
+	maxOverrideBodyBytes = 64 * 1024
+)
+
+type ClusterFlowControlOverrides struct {
+	Cluster   string                            `json:"cluster"`
+	Overrides []proxyv1alpha1.FlowControlSchema `json:"overrides"`
+}
+
+// InstallFlowControlOverridesHandler registers the handler which adjusts flow control schema
+// parameters of a cluster at runtime, e.g. tightening rate limits during incidents:
+//
+//	GET    /debug/flowcontrol/overrides[?cluster=<name>]        lists overrides
+//	PUT    /debug/flowcontrol/overrides?cluster=<name>          overrides the schema in body
+//	DELETE /debug/flowcontrol/overrides?cluster=<name>&schema=<name> deletes the override
+//
+// Overrides are temporary, they live in memory only and are lost after restarting.
+func InstallFlowControlOverridesHandler(c *mux.PathRecorderMux, clusterManager clusters.Manager) {
+	c.UnlistedHandle(FlowControlOverridesPath, FlowControlOverridesHandler(clusterManager))
+}
+
+func FlowControlOverridesHandler(clusterManager clusters.Manager) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		clusterName := req.URL.Query().Get("cluster")
+		var cluster *clusters.ClusterInfo
+		if len(clusterName) > 0 {
+			info, ok := clusterManager.Get(clusterName)
+			if !ok {
+				http.Error(w, "cluster not found", http.StatusNotFound)
+				return
+			}
+			cluster = info
+		}
+
+		switch req.Method {
+		case http.MethodGet:
+			infos := clusterManager.List()
+			if cluster != nil {
+				infos = []*clusters.ClusterInfo{cluster}
+			}
+			ret := []ClusterFlowControlOverrides{}
+			for _, info := range infos {
+				overrides := info.FlowControlOverrides()
+				if len(overrides) == 0 && cluster == nil {
+					continue
+				}
+				ret = append(ret, ClusterFlowControlOverrides{Cluster: info.Cluster, Overrides: overrides})
+			}
+			writeJSON(w, ret)
+		case http.MethodPut:
+			if cluster == nil {
+				http.Error(w, "cluster must be specified", http.StatusBadRequest)
+				return
+			}
+			override, err := decodeFlowControlOverride(req.Body)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if err := cluster.SetFlowControlOverride(override); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			klog.Infof("[flowcontrol override] user=%q cluster=%q overrides flowcontrol schema=%q with %+v",
+				userName(req), cluster.Cluster, override.Name, override.FlowControlSchemaConfiguration)
+			writeJSON(w, override)
+		case http.MethodDelete:
+			schema := req.URL.Query().Get("schema")
+			if cluster == nil || len(schema) == 0 {
+				http.Error(w, "cluster and schema must be specified", http.StatusBadRequest)
+				return
+			}
+			if !cluster.DeleteFlowControlOverride(schema) {
+				http.Error(w, "flow control schema is not overridden", http.StatusNotFound)
+				return
+			}
+			klog.Infof("[flowcontrol override] user=%q cluster=%q deletes override of flowcontrol schema=%q", userName(req), cluster.Cluster, schema)
+			w.WriteHeader(http.StatusOK)
+		default:
+			http.Error(w, "only GET, PUT and DELETE are allowed", http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+func decodeFlowControlOverride(body io.Reader) (proxyv1alpha1.FlowControlSchema, error) {
+	override := proxyv1alpha1.FlowControlSchema{}
+	if err := json.NewDecoder(io.LimitReader(body, maxOverrideBodyBytes)).Decode(&override); err != nil {
+		return override, fmt.Errorf("failed to decode flow control schema: %v", err)
+	}
+	if len(override.Name) == 0 {
+		return override, fmt.Errorf("flow control schema name must be specified")
+	}
+	if errs := validation.ValidateFlowControlConfiguration(&override.FlowControlSchemaConfiguration, field.NewPath("")); len(errs) > 0 {
+		return override, errs.ToAggregate()
+	}
+	return override, nil
+}
+
+func userName(req *http.Request) string {
+	if user, ok := genericapirequest.UserFrom(req.Context()); ok {
+		return user.GetName()
+	}
+	return ""
+}
+
+func writeJSON(w http.ResponseWriter, obj interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(obj); err != nil {
+		klog.Errorf("failed to write response: %v", err)
+	}
+}
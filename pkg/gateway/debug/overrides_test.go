@@ -0,0 +1,101 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debug
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
+	"github.com/kubewharf/kubegateway/pkg/clusters"
+)
+
+// newTestUpstreamCluster returns a valid UpstreamCluster named a.cluster with the given spec,
+// client config and dispatch policies are filled if they are empty
+func newTestUpstreamCluster(spec proxyv1alpha1.UpstreamClusterSpec) *proxyv1alpha1.UpstreamCluster {
+	if len(spec.ClientConfig.BearerToken) == 0 {
+		spec.ClientConfig = proxyv1alpha1.ClientConfig{Insecure: true, BearerToken: []byte("token")}
+	}
+	if len(spec.DispatchPolicies) == 0 {
+		spec.DispatchPolicies = []proxyv1alpha1.DispatchPolicy{
+			{
+				Rules: []proxyv1alpha1.DispatchPolicyRule{
+					{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}, NonResourceURLs: []string{"*"}},
+				},
+			},
+		}
+	}
+	return &proxyv1alpha1.UpstreamCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "a.cluster"},
+		Spec:       spec,
+	}
+}
+
+func TestFlowControlOverridesHandler(t *testing.T) {
+	cluster, err := clusters.CreateClusterInfo(newTestUpstreamCluster(proxyv1alpha1.UpstreamClusterSpec{
+		Servers: []proxyv1alpha1.UpstreamClusterServer{{Endpoint: "https://127.0.0.1:443"}},
+		FlowControl: proxyv1alpha1.FlowControl{
+			Schemas: []proxyv1alpha1.FlowControlSchema{
+				{
+					Name: "limited",
+					FlowControlSchemaConfiguration: proxyv1alpha1.FlowControlSchemaConfiguration{
+						TokenBucket: &proxyv1alpha1.TokenBucketFlowControlSchema{QPS: 100, Burst: 200},
+					},
+				},
+			},
+		},
+	}), func(*clusters.EndpointInfo) bool { return true })
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cluster.Stop()
+	manager := clusters.NewManager()
+	manager.Add(cluster)
+	handler := FlowControlOverridesHandler(manager)
+
+	tests := []struct {
+		name          string
+		method        string
+		query         string
+		body          string
+		wantCode      int
+		wantOverrides int
+	}{
+		{"cluster is required", http.MethodPut, "", `{"name":"limited","tokenBucket":{"qps":1,"burst":1}}`, http.StatusBadRequest, 0},
+		{"invalid schema", http.MethodPut, "?cluster=a.cluster", `{"name":"limited","tokenBucket":{"qps":-1,"burst":1}}`, http.StatusBadRequest, 0},
+		{"type can not be changed", http.MethodPut, "?cluster=a.cluster", `{"name":"limited","exempt":{}}`, http.StatusBadRequest, 0},
+		{"override", http.MethodPut, "?cluster=a.cluster", `{"name":"limited","tokenBucket":{"qps":1,"burst":1}}`, http.StatusOK, 1},
+		{"list", http.MethodGet, "", "", http.StatusOK, 1},
+		{"delete", http.MethodDelete, "?cluster=a.cluster&schema=limited", "", http.StatusOK, 0},
+		{"delete again", http.MethodDelete, "?cluster=a.cluster&schema=limited", "", http.StatusNotFound, 0},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(tt.method, FlowControlOverridesPath+tt.query, strings.NewReader(tt.body)))
+			if w.Code != tt.wantCode {
+				t.Errorf("status code = %v, want %v, body: %s", w.Code, tt.wantCode, w.Body.String())
+			}
+			if got := len(cluster.FlowControlOverrides()); got != tt.wantOverrides {
+				t.Errorf("got %v overrides, want %v", got, tt.wantOverrides)
+			}
+		})
+	}
+}
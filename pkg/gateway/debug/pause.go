@@ -0,0 +1,126 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debug
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"k8s.io/apiserver/pkg/server/mux"
+	"k8s.io/klog"
+
+	"github.com/kubewharf/kubegateway/pkg/clusters"
+)
+
+const PausesPath = "/debug/pauses"
+
+type ClusterPause struct {
+	Cluster string         `json:"cluster"`
+	Pause   clusters.Pause `json:"pause"`
+}
+
+// InstallPausesHandler registers the handler which pauses admitting new requests to a cluster while
+// keeping existing connections and watches, e.g. quiescing a cluster during an upstream leader handoff:
+//
+//	GET    /debug/pauses[?cluster=<name>]                         lists paused clusters
+//	PUT    /debug/pauses?cluster=<name>[&maxWait=5s][&duration=1m] pauses the cluster
+//	DELETE /debug/pauses?cluster=<name>                           resumes the cluster
+//
+// New requests to a paused cluster wait for at most maxWait, which defaults to 0, and are rejected
+// with 503 if the cluster is not resumed in time. The cluster resumes automatically after duration
+// if it is set. Pauses live in memory only and are lost after restarting.
+func InstallPausesHandler(c *mux.PathRecorderMux, clusterManager clusters.Manager) {
+	c.UnlistedHandle(PausesPath, PausesHandler(clusterManager))
+}
+
+func PausesHandler(clusterManager clusters.Manager) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		clusterName := req.URL.Query().Get("cluster")
+		var cluster *clusters.ClusterInfo
+		if len(clusterName) > 0 {
+			info, ok := clusterManager.Get(clusterName)
+			if !ok {
+				http.Error(w, "cluster not found", http.StatusNotFound)
+				return
+			}
+			cluster = info
+		}
+
+		switch req.Method {
+		case http.MethodGet:
+			infos := clusterManager.List()
+			if cluster != nil {
+				infos = []*clusters.ClusterInfo{cluster}
+			}
+			ret := []ClusterPause{}
+			for _, info := range infos {
+				if pause, ok := info.AdmissionPause(); ok {
+					ret = append(ret, ClusterPause{Cluster: info.Cluster, Pause: pause})
+				}
+			}
+			writeJSON(w, ret)
+		case http.MethodPut:
+			if cluster == nil {
+				http.Error(w, "cluster must be specified", http.StatusBadRequest)
+				return
+			}
+			maxWait, err := durationQuery(req, "maxWait")
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			duration, err := durationQuery(req, "duration")
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			pause, err := cluster.PauseAdmission(maxWait, duration)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			klog.Infof("[pause] user=%q cluster=%q pauses admission of new requests, maxWait=%v duration=%v",
+				userName(req), cluster.Cluster, maxWait, duration)
+			writeJSON(w, ClusterPause{Cluster: cluster.Cluster, Pause: pause})
+		case http.MethodDelete:
+			if cluster == nil {
+				http.Error(w, "cluster must be specified", http.StatusBadRequest)
+				return
+			}
+			if !cluster.ResumeAdmission() {
+				http.Error(w, "cluster is not paused", http.StatusNotFound)
+				return
+			}
+			klog.Infof("[pause] user=%q cluster=%q resumes admission of new requests", userName(req), cluster.Cluster)
+			w.WriteHeader(http.StatusOK)
+		default:
+			http.Error(w, "only GET, PUT and DELETE are allowed", http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+// durationQuery parses the duration in query, it returns 0 if the query is not set
+func durationQuery(req *http.Request, key string) (time.Duration, error) {
+	value := req.URL.Query().Get(key)
+	if len(value) == 0 {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %v", key, err)
+	}
+	return d, nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debug
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
+	"github.com/kubewharf/kubegateway/pkg/clusters"
+)
+
+func TestPausesHandler(t *testing.T) {
+	cluster, err := clusters.CreateClusterInfo(newTestUpstreamCluster(proxyv1alpha1.UpstreamClusterSpec{
+		Servers: []proxyv1alpha1.UpstreamClusterServer{{Endpoint: "https://127.0.0.1:443"}},
+	}), func(*clusters.EndpointInfo) bool { return true })
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cluster.Stop()
+	manager := clusters.NewManager()
+	manager.Add(cluster)
+	handler := PausesHandler(manager)
+
+	tests := []struct {
+		name       string
+		method     string
+		query      string
+		wantCode   int
+		wantPaused bool
+	}{
+		{"cluster is required", http.MethodPut, "", http.StatusBadRequest, false},
+		{"invalid maxWait", http.MethodPut, "?cluster=a.cluster&maxWait=abc", http.StatusBadRequest, false},
+		{"negative duration", http.MethodPut, "?cluster=a.cluster&duration=-1s", http.StatusBadRequest, false},
+		{"pause", http.MethodPut, "?cluster=a.cluster&maxWait=5s", http.StatusOK, true},
+		{"list", http.MethodGet, "", http.StatusOK, true},
+		{"resume", http.MethodDelete, "?cluster=a.cluster", http.StatusOK, false},
+		{"resume again", http.MethodDelete, "?cluster=a.cluster", http.StatusNotFound, false},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(tt.method, PausesPath+tt.query, nil))
+			if w.Code != tt.wantCode {
+				t.Errorf("status code = %v, want %v, body: %s", w.Code, tt.wantCode, w.Body.String())
+			}
+			if _, got := cluster.AdmissionPause(); got != tt.wantPaused {
+				t.Errorf("paused = %v, want %v", got, tt.wantPaused)
+			}
+		})
+	}
+}
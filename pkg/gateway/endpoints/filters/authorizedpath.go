@@ -0,0 +1,81 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filters
+
+import (
+	"errors"
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/klog"
+)
+
+// WithAuthorizedPath serves requests for path with pathHandler instead of dispatching them
+// to the upstream cluster. Requests are authorized as non-resource requests of the path with
+// the verb of the http method, e.g. "get", so it must be put after authentication and
+// impersonation. Other requests are passed to handler.
+func WithAuthorizedPath(handler http.Handler, path string, pathHandler http.Handler, a authorizer.Authorizer, s runtime.NegotiatedSerializer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != path {
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		ctx := req.Context()
+		requestor, exists := genericapirequest.UserFrom(ctx)
+		if !exists {
+			responsewriters.InternalError(w, req, errors.New("no user found for request"))
+			return
+		}
+		attributes := &authorizer.AttributesRecord{
+			User:            requestor,
+			Verb:            nonResourceVerb(req.Method),
+			Path:            req.URL.Path,
+			ResourceRequest: false,
+		}
+		if a == nil {
+			responsewriters.Forbidden(ctx, attributes, w, req, "no authorizer is configured", s)
+			return
+		}
+		decision, reason, err := a.Authorize(ctx, attributes)
+		if err != nil || decision != authorizer.DecisionAllow {
+			klog.V(4).Infof("Forbidden: %#v, Reason: %s, Error: %v", req.RequestURI, reason, err)
+			responsewriters.Forbidden(ctx, attributes, w, req, reason, s)
+			return
+		}
+		pathHandler.ServeHTTP(w, req)
+	})
+}
+
+// nonResourceVerb returns the verb of non-resource requests in the same way as kube-apiserver
+func nonResourceVerb(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead:
+		return "get"
+	case http.MethodPost:
+		return "post"
+	case http.MethodPut:
+		return "put"
+	case http.MethodPatch:
+		return "patch"
+	case http.MethodDelete:
+		return "delete"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filters
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	serializer "k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+func TestWithAuthorizedPath(t *testing.T) {
+	const path = "/apis/proxy.kubegateway/clusters"
+	tests := []struct {
+		name     string
+		path     string
+		user     user.Info
+		wantCode int
+		wantBody string
+	}{
+		{"other paths are passed", "/api/v1/pods", &user.DefaultInfo{Name: "deny-me"}, http.StatusOK, "upstream"},
+		{"authorized", path, &user.DefaultInfo{Name: "system:admin"}, http.StatusOK, "gateway"},
+		{"denied", path, &user.DefaultInfo{Name: "deny-me"}, http.StatusForbidden, ""},
+		{"authorizer error", path, &user.DefaultInfo{Name: "tester"}, http.StatusForbidden, ""},
+		{"no user", path, nil, http.StatusInternalServerError, ""},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			handler := WithAuthorizedPath(
+				http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) { _, _ = w.Write([]byte("upstream")) }),
+				path,
+				http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) { _, _ = w.Write([]byte("gateway")) }),
+				impersonateAuthorizer{},
+				serializer.NewCodecFactory(runtime.NewScheme()),
+			)
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.user != nil {
+				req = req.WithContext(request.WithUser(req.Context(), tt.user))
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			if w.Code != tt.wantCode {
+				t.Errorf("WithAuthorizedPath() code = %v, want %v", w.Code, tt.wantCode)
+			}
+			if len(tt.wantBody) > 0 && w.Body.String() != tt.wantBody {
+				t.Errorf("WithAuthorizedPath() body = %v, want %v", w.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filters
+
+import (
+	"net"
+	"net/http"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/util/runtime"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+
+	"github.com/kubewharf/kubegateway/pkg/gateway/endpoints/request"
+)
+
+const statusReasonConnectionRateLimited = "too_many_requests_per_connection"
+
+// connectionInflight counts in-flight requests of each downstream connection
+type connectionInflight struct {
+	lock     sync.Mutex
+	inflight map[string]int
+}
+
+func (c *connectionInflight) tryAcquire(conn string, limit int) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.inflight[conn] >= limit {
+		return false
+	}
+	c.inflight[conn]++
+	return true
+}
+
+func (c *connectionInflight) release(conn string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.inflight[conn]--
+	if c.inflight[conn] <= 0 {
+		// the entry is removed once the connection is idle, so that closed
+		// connections never leak
+		delete(c.inflight, conn)
+	}
+}
+
+// WithMaxInflightPerConnection limits the number of in-flight requests of each downstream
+// connection, requests over the limit are rejected with 429. It works the same for HTTP/1.1
+// and HTTP/2, so a client multiplexing lots of streams on one connection can not monopolize
+// the gateway. Long-running requests are not limited. Limit <= 0 disables it.
+func WithMaxInflightPerConnection(handler http.Handler, limit int, longRunningFunc genericapirequest.LongRunningRequestCheck) http.Handler {
+	if limit <= 0 {
+		return handler
+	}
+	conns := &connectionInflight{inflight: map[string]int{}}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requestInfo, ok := genericapirequest.RequestInfoFrom(req.Context())
+		if ok && longRunningFunc != nil && longRunningFunc(req, requestInfo) {
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		conn := connectionKey(req)
+		if !conns.tryAcquire(conn, limit) {
+			runtime.Must(request.SetProxyTerminated(req.Context(), statusReasonConnectionRateLimited))
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "Too many requests on this connection, please try again later.", http.StatusTooManyRequests)
+			return
+		}
+		defer conns.release(conn)
+		handler.ServeHTTP(w, req)
+	})
+}
+
+// connectionKey identifies the downstream connection of the request. The remote address
+// is unique among connections accepted by the same local address.
+func connectionKey(req *http.Request) string {
+	if addr, ok := req.Context().Value(http.LocalAddrContextKey).(net.Addr); ok {
+		return addr.String() + "/" + req.RemoteAddr
+	}
+	return req.RemoteAddr
+}
@@ -0,0 +1,75 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filters
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+
+	"github.com/kubewharf/kubegateway/pkg/gateway/endpoints/request"
+)
+
+func TestWithMaxInflightPerConnection(t *testing.T) {
+	block := make(chan struct{})
+	started := make(chan struct{})
+	handler := WithMaxInflightPerConnection(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Query().Get("block") == "true" {
+			started <- struct{}{}
+			<-block
+		}
+	}), 2, func(req *http.Request, requestInfo *genericapirequest.RequestInfo) bool {
+		return requestInfo.Verb == "watch"
+	})
+
+	serve := func(remoteAddr, verb, query string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/pods"+query, nil)
+		req.RemoteAddr = remoteAddr
+		ctx := genericapirequest.WithRequestInfo(req.Context(), &genericapirequest.RequestInfo{Verb: verb})
+		ctx = request.WithProxyInfo(ctx, request.NewProxyInfo())
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req.WithContext(ctx))
+		return w.Code
+	}
+
+	// occupy the connection with blocked requests
+	for i := 0; i < 2; i++ {
+		go serve("10.0.0.1:10000", "list", "?block=true")
+		<-started
+	}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		verb       string
+		want       int
+	}{
+		{"over the limit", "10.0.0.1:10000", "list", http.StatusTooManyRequests},
+		{"long-running requests are not limited", "10.0.0.1:10000", "watch", http.StatusOK},
+		{"another connection of the same client", "10.0.0.1:10001", "list", http.StatusOK},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			if got := serve(tt.remoteAddr, tt.verb, ""); got != tt.want {
+				t.Errorf("status code = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	close(block)
+}
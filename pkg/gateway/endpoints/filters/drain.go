@@ -0,0 +1,135 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filters
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"k8s.io/klog"
+)
+
+// RequestDrainer tracks all in-flight requests, including long-running ones such as
+// watches, and is able to force them to close during shutdown.
+type RequestDrainer struct {
+	lock     sync.Mutex
+	inflight int64
+	// drainedCh is closed when there is no in-flight request after Drain is called
+	drainedCh chan struct{}
+	forceCh   chan struct{}
+	forced    bool
+}
+
+func NewRequestDrainer() *RequestDrainer {
+	return &RequestDrainer{
+		forceCh: make(chan struct{}),
+	}
+}
+
+// WithRequestDrainer registers every request into the drainer and cancels the request
+// context once the drainer forces in-flight requests to close.
+func WithRequestDrainer(handler http.Handler, drainer *RequestDrainer) http.Handler {
+	if drainer == nil {
+		return handler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !drainer.add() {
+			// the drainer has already given up waiting, reject new requests
+			w.Header().Set("Connection", "close")
+			http.Error(w, "the gateway is shutting down", http.StatusServiceUnavailable)
+			return
+		}
+		defer drainer.done()
+
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		go func() {
+			select {
+			case <-drainer.forceCh:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+
+		handler.ServeHTTP(w, req.WithContext(ctx))
+	})
+}
+
+func (d *RequestDrainer) add() bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if d.forced {
+		return false
+	}
+	d.inflight++
+	return true
+}
+
+func (d *RequestDrainer) done() {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.inflight--
+	if d.inflight == 0 && d.drainedCh != nil {
+		close(d.drainedCh)
+		d.drainedCh = nil
+	}
+}
+
+// Inflight returns the number of in-flight requests.
+func (d *RequestDrainer) Inflight() int64 {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.inflight
+}
+
+// Drain waits for in-flight requests to finish within the given timeout. Requests still
+// in flight after the timeout are forced to close by cancelling their contexts, and Drain
+// returns after all of them exit.
+// It returns true if all requests finished within the timeout.
+func (d *RequestDrainer) Drain(timeout time.Duration) bool {
+	d.lock.Lock()
+	if d.inflight == 0 {
+		d.lock.Unlock()
+		return true
+	}
+	if d.drainedCh == nil {
+		d.drainedCh = make(chan struct{})
+	}
+	drained := d.drainedCh
+	d.lock.Unlock()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-drained:
+		return true
+	case <-timer.C:
+	}
+
+	d.lock.Lock()
+	if !d.forced {
+		d.forced = true
+		close(d.forceCh)
+	}
+	inflight := d.inflight
+	d.lock.Unlock()
+
+	klog.Warningf("[graceful-termination] %v in-flight proxied requests are not finished after %v, force closing them", inflight, timeout)
+	<-drained
+	return false
+}
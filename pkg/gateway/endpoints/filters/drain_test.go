@@ -0,0 +1,77 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filters
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRequestDrainer(t *testing.T) {
+	tests := []struct {
+		name        string
+		handlerTime time.Duration
+		timeout     time.Duration
+		wantDrained bool
+	}{
+		{
+			name:        "requests finish within timeout",
+			handlerTime: 10 * time.Millisecond,
+			timeout:     5 * time.Second,
+			wantDrained: true,
+		},
+		{
+			name:        "long-running requests are forced to close",
+			handlerTime: time.Hour,
+			timeout:     50 * time.Millisecond,
+			wantDrained: false,
+		},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			drainer := NewRequestDrainer()
+			started := make(chan struct{})
+			handler := WithRequestDrainer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				close(started)
+				select {
+				case <-time.After(tt.handlerTime):
+				case <-req.Context().Done():
+				}
+			}), drainer)
+
+			go handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/pods?watch=true", nil))
+			<-started
+
+			if got := drainer.Drain(tt.timeout); got != tt.wantDrained {
+				t.Errorf("Drain() = %v, want %v", got, tt.wantDrained)
+			}
+			if got := drainer.Inflight(); got != 0 {
+				t.Errorf("Inflight() = %v after Drain, want 0", got)
+			}
+
+			if !tt.wantDrained {
+				// new requests are rejected after drainer gives up waiting
+				w := httptest.NewRecorder()
+				handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/pods", nil))
+				if w.Code != http.StatusServiceUnavailable {
+					t.Errorf("status code after forced drain = %v, want %v", w.Code, http.StatusServiceUnavailable)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filters
+
+import (
+	"math/rand"
+	"net/http"
+	"sync"
+
+	"github.com/kubewharf/kubegateway/pkg/clusters"
+	"github.com/kubewharf/kubegateway/pkg/clusters/features"
+	"github.com/kubewharf/kubegateway/pkg/gateway/endpoints/request"
+)
+
+var (
+	// randPool used to get a rand.Rand and generate a random number thread-safely
+	randPool = &sync.Pool{
+		New: func() interface{} {
+			return rand.New(rand.NewSource(rand.Int63()))
+		},
+	}
+)
+
+// WithProbabilisticGoaway is a cluster-aware version of genericfilters.WithProbabilisticGoaway.
+// It sends GOAWAY probabilistically according to the given chance for HTTP2 requests, unless
+// the requested cluster enables the DisableProbabilisticGoaway feature, so that clients with
+// long-lived watches on this cluster are not forced to reconnect.
+// It must be placed after WithExtraRequestInfo.
+func WithProbabilisticGoaway(handler http.Handler, chance float64, clusterManager clusters.Manager) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Proto == "HTTP/2.0" && nextGoawayChance() < chance && goawayEnabled(req, clusterManager) {
+			// Send a GOAWAY and tear down the TCP connection when idle.
+			w.Header().Set("Connection", "close")
+		}
+		handler.ServeHTTP(w, req)
+	})
+}
+
+func goawayEnabled(req *http.Request, clusterManager clusters.Manager) bool {
+	info, ok := request.ExtraReqeustInfoFrom(req.Context())
+	if !ok {
+		return true
+	}
+	cluster, ok := clusterManager.Get(info.Hostname)
+	if !ok {
+		return true
+	}
+	return !cluster.FeatureEnabled(features.DisableProbabilisticGoaway)
+}
+
+func nextGoawayChance() float64 {
+	rnd := randPool.Get().(*rand.Rand)
+	ret := rnd.Float64()
+	randPool.Put(rnd)
+	return ret
+}
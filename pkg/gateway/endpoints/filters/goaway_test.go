@@ -0,0 +1,89 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filters
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
+	"github.com/kubewharf/kubegateway/pkg/clusters"
+	"github.com/kubewharf/kubegateway/pkg/clusters/features"
+	"github.com/kubewharf/kubegateway/pkg/gateway/endpoints/request"
+)
+
+func TestWithProbabilisticGoaway(t *testing.T) {
+	manager := clusters.NewManager()
+	for name, annotations := range map[string]map[string]string{
+		"enabled.cluster":  nil,
+		"disabled.cluster": {features.FeatureGateAnnotationKey: string(features.DisableProbabilisticGoaway) + "=true"},
+	} {
+		cluster, err := clusters.CreateClusterInfo(&proxyv1alpha1.UpstreamCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: annotations},
+			Spec: proxyv1alpha1.UpstreamClusterSpec{
+				Servers:      []proxyv1alpha1.UpstreamClusterServer{{Endpoint: "https://127.0.0.1:443"}},
+				ClientConfig: proxyv1alpha1.ClientConfig{Insecure: true, BearerToken: []byte("token")},
+				DispatchPolicies: []proxyv1alpha1.DispatchPolicy{
+					{Rules: []proxyv1alpha1.DispatchPolicyRule{{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}, NonResourceURLs: []string{"*"}}}},
+				},
+			},
+		}, func(*clusters.EndpointInfo) bool { return true })
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer cluster.Stop()
+		manager.Add(cluster)
+	}
+
+	const requests = 1000
+	tests := []struct {
+		name    string
+		cluster string
+		proto   string
+		chance  float64
+		// the number of requests responded with Connection: close is in [min, max]
+		min, max int
+	}{
+		{"never", "enabled.cluster", "HTTP/2.0", 0, 0, 0},
+		{"always", "enabled.cluster", "HTTP/2.0", 1, requests, requests},
+		{"half", "enabled.cluster", "HTTP/2.0", 0.5, requests * 4 / 10, requests * 6 / 10},
+		{"unknown cluster", "unknown.cluster", "HTTP/2.0", 1, requests, requests},
+		{"http/1.1", "enabled.cluster", "HTTP/1.1", 1, 0, 0},
+		{"disabled by cluster", "disabled.cluster", "HTTP/2.0", 1, 0, 0},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			handler := WithProbabilisticGoaway(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}), tt.chance, manager)
+			closed := 0
+			for i := 0; i < requests; i++ {
+				req := httptest.NewRequest(http.MethodGet, "/api/v1/pods", nil)
+				req.Proto = tt.proto
+				req = req.WithContext(request.WithExtraReqeustInfo(req.Context(), &request.ExtraRequestInfo{Hostname: tt.cluster}))
+				w := httptest.NewRecorder()
+				handler.ServeHTTP(w, req)
+				if w.Header().Get("Connection") == "close" {
+					closed++
+				}
+			}
+			if closed < tt.min || closed > tt.max {
+				t.Errorf("%d of %d requests are responded with Connection: close, want [%d, %d]", closed, requests, tt.min, tt.max)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filters
+
+import (
+	"net/http"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/util/flowcontrol"
+
+	"github.com/kubewharf/kubegateway/pkg/gateway/endpoints/request"
+	"github.com/kubewharf/kubegateway/pkg/gateway/metrics"
+)
+
+const statusReasonImpersonationRateLimited = "impersonation_rate_limited"
+
+// impersonationLimiter holds a token bucket for each impersonator of each cluster. Only users
+// authorized to impersonate have buckets, so the number of them is bounded.
+type impersonationLimiter struct {
+	qps   float32
+	burst int
+
+	lock     sync.Mutex
+	limiters map[string]flowcontrol.RateLimiter
+}
+
+func (l *impersonationLimiter) tryAccept(cluster, impersonator string) bool {
+	key := cluster + "/" + impersonator
+	l.lock.Lock()
+	limiter, ok := l.limiters[key]
+	if !ok {
+		limiter = flowcontrol.NewTokenBucketRateLimiter(l.qps, l.burst)
+		l.limiters[key] = limiter
+	}
+	l.lock.Unlock()
+	return limiter.TryAccept()
+}
+
+// WithImpersonationLimit counts authorized impersonation requests by cluster and impersonator,
+// and rejects them with 429 if an impersonator exceeds qps and burst in a cluster. It must be
+// put after impersonation is authorized and the impersonator is recorded. qps <= 0 disables
+// limiting, requests are still counted.
+func WithImpersonationLimit(handler http.Handler, qps float32, burst int) http.Handler {
+	var limiter *impersonationLimiter
+	if qps > 0 {
+		limiter = &impersonationLimiter{
+			qps:      qps,
+			burst:    burst,
+			limiters: map[string]flowcontrol.RateLimiter{},
+		}
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		info, ok := request.ExtraReqeustInfoFrom(req.Context())
+		if !ok || !info.IsImpersonateRequest || info.Impersonator == nil {
+			handler.ServeHTTP(w, req)
+			return
+		}
+
+		impersonator := info.Impersonator.GetName()
+		metrics.RecordImpersonation(info.Hostname, impersonator)
+		if limiter != nil && !limiter.tryAccept(info.Hostname, impersonator) {
+			runtime.Must(request.SetProxyTerminated(req.Context(), statusReasonImpersonationRateLimited))
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "Too many impersonation requests, please try again later.", http.StatusTooManyRequests)
+			return
+		}
+		handler.ServeHTTP(w, req)
+	})
+}
@@ -0,0 +1,70 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filters
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"k8s.io/apiserver/pkg/authentication/user"
+
+	"github.com/kubewharf/kubegateway/pkg/gateway/endpoints/request"
+)
+
+func TestWithImpersonationLimit(t *testing.T) {
+	handler := WithImpersonationLimit(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}), 0.001, 2)
+
+	serve := func(cluster, impersonator string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/pods", nil)
+		info := &request.ExtraRequestInfo{Hostname: cluster}
+		if len(impersonator) > 0 {
+			info.IsImpersonateRequest = true
+			info.Impersonator = &user.DefaultInfo{Name: impersonator}
+		}
+		ctx := request.WithExtraReqeustInfo(req.Context(), info)
+		ctx = request.WithProxyInfo(ctx, request.NewProxyInfo())
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req.WithContext(ctx))
+		return w.Code
+	}
+
+	// use up the burst
+	for i := 0; i < 2; i++ {
+		if got := serve("a.cluster", "admin"); got != http.StatusOK {
+			t.Fatalf("status code = %v, want %v", got, http.StatusOK)
+		}
+	}
+
+	tests := []struct {
+		name         string
+		cluster      string
+		impersonator string
+		want         int
+	}{
+		{"over the limit", "a.cluster", "admin", http.StatusTooManyRequests},
+		{"another impersonator", "a.cluster", "operator", http.StatusOK},
+		{"another cluster", "b.cluster", "admin", http.StatusOK},
+		{"not impersonating", "a.cluster", "", http.StatusOK},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			if got := serve(tt.cluster, tt.impersonator); got != tt.want {
+				t.Errorf("status code = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filters
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apiserver/pkg/authentication/serviceaccount"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/klog"
+
+	"github.com/kubewharf/kubegateway/pkg/clusters"
+	"github.com/kubewharf/kubegateway/pkg/gateway/endpoints/request"
+)
+
+const statusReasonImpersonationForbidden = "impersonation_forbidden"
+
+// WithImpersonationPolicy rejects requests with 403 if they impersonate users or groups which are
+// forbidden by the impersonation policy of the requested cluster, no matter whether the impersonator
+// is authorized to impersonate them. It must be put after WithExtraRequestInfo and authentication,
+// and before WithNoLoggingImpersonation which removes impersonation headers.
+func WithImpersonationPolicy(handler http.Handler, clusterManager clusters.Manager, s runtime.NegotiatedSerializer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if len(req.Header.Get(authenticationv1.ImpersonateUserHeader)) == 0 {
+			handler.ServeHTTP(w, req)
+			return
+		}
+		ctx := req.Context()
+		info, ok := request.ExtraReqeustInfoFrom(ctx)
+		if !ok {
+			handler.ServeHTTP(w, req)
+			return
+		}
+		cluster, ok := clusterManager.Get(info.Hostname)
+		if !ok {
+			handler.ServeHTTP(w, req)
+			return
+		}
+		impersonationRequests, err := buildImpersonationRequests(req.Header)
+		if err != nil {
+			// invalid impersonation headers are responded by WithNoLoggingImpersonation
+			handler.ServeHTTP(w, req)
+			return
+		}
+		requestor, exists := genericapirequest.UserFrom(ctx)
+		if !exists {
+			responsewriters.InternalError(w, req, errors.New("no user found for request"))
+			return
+		}
+
+		for _, impersonationRequest := range impersonationRequests {
+			attributes := &authorizer.AttributesRecord{
+				User:            requestor,
+				Verb:            impersonateVerb,
+				Namespace:       impersonationRequest.Namespace,
+				Name:            impersonationRequest.Name,
+				ResourceRequest: true,
+			}
+			allowed := true
+			switch impersonationRequest.Kind {
+			case "ServiceAccount":
+				attributes.Resource = resourceServiceAccounts
+				allowed = cluster.AllowsImpersonatingUser(serviceaccount.MakeUsername(impersonationRequest.Namespace, impersonationRequest.Name))
+			case "User":
+				attributes.Resource = resourceUsers
+				allowed = cluster.AllowsImpersonatingUser(impersonationRequest.Name)
+			case "Group":
+				attributes.Resource = resourceGroups
+				allowed = cluster.AllowsImpersonatingGroup(impersonationRequest.Name)
+			}
+			if allowed {
+				continue
+			}
+			reason := fmt.Sprintf("impersonating %s %q is forbidden by the impersonation policy of cluster %q", attributes.Resource, impersonationRequest.Name, info.Hostname)
+			klog.V(2).Infof("[impersonation] user=%q uri=%q: %s", requestor.GetName(), req.RequestURI, reason)
+			utilruntime.Must(request.SetProxyTerminated(ctx, statusReasonImpersonationForbidden))
+			responsewriters.Forbidden(ctx, attributes, w, req, reason, s)
+			return
+		}
+		handler.ServeHTTP(w, req)
+	})
+}
@@ -0,0 +1,91 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filters
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apiserver/pkg/authentication/user"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+
+	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
+	"github.com/kubewharf/kubegateway/pkg/clusters"
+	"github.com/kubewharf/kubegateway/pkg/gateway/endpoints/request"
+)
+
+func TestWithImpersonationPolicy(t *testing.T) {
+	cluster, err := clusters.CreateClusterInfo(&proxyv1alpha1.UpstreamCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "a.cluster"},
+		Spec: proxyv1alpha1.UpstreamClusterSpec{
+			Servers:      []proxyv1alpha1.UpstreamClusterServer{{Endpoint: "https://127.0.0.1:443"}},
+			ClientConfig: proxyv1alpha1.ClientConfig{Insecure: true, BearerToken: []byte("token")},
+			DispatchPolicies: []proxyv1alpha1.DispatchPolicy{
+				{Rules: []proxyv1alpha1.DispatchPolicyRule{{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}, NonResourceURLs: []string{"*"}}}},
+			},
+			Impersonation: &proxyv1alpha1.ImpersonationPolicy{
+				DeniedUsers:  []string{"system:serviceaccount:kube-system:admin"},
+				DeniedGroups: []string{"system:masters"},
+			},
+		},
+	}, func(*clusters.EndpointInfo) bool { return true })
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cluster.Stop()
+	manager := clusters.NewManager()
+	manager.Add(cluster)
+
+	handler := WithImpersonationPolicy(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}), manager, serializer.NewCodecFactory(runtime.NewScheme()))
+
+	tests := []struct {
+		name    string
+		cluster string
+		user    string
+		groups  []string
+		want    int
+	}{
+		{"not impersonating", "a.cluster", "", nil, http.StatusOK},
+		{"allowed", "a.cluster", "dev", []string{"developers"}, http.StatusOK},
+		{"denied group", "a.cluster", "dev", []string{"developers", "system:masters"}, http.StatusForbidden},
+		{"denied service account", "a.cluster", "system:serviceaccount:kube-system:admin", nil, http.StatusForbidden},
+		{"another cluster", "b.cluster", "dev", []string{"system:masters"}, http.StatusOK},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/pods", nil)
+			if len(tt.user) > 0 {
+				req.Header.Set(authenticationv1.ImpersonateUserHeader, tt.user)
+			}
+			for _, group := range tt.groups {
+				req.Header.Add(authenticationv1.ImpersonateGroupHeader, group)
+			}
+			ctx := request.WithExtraReqeustInfo(req.Context(), &request.ExtraRequestInfo{Hostname: tt.cluster})
+			ctx = request.WithProxyInfo(ctx, request.NewProxyInfo())
+			ctx = genericapirequest.WithUser(ctx, &user.DefaultInfo{Name: "impersonator"})
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req.WithContext(ctx))
+			if w.Code != tt.want {
+				t.Errorf("status code = %v, want %v, body: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
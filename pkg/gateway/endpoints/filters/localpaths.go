@@ -0,0 +1,57 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filters
+
+import (
+	"net/http"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// GatewayServablePaths are the well-known paths which can be served by the gateway itself
+// instead of being dispatched to the upstream cluster.
+var GatewayServablePaths = sets.NewString("/", "/version", "/healthz", "/livez", "/readyz")
+
+// WithGatewayServedPaths serves requests for the given well-known paths with gatewayHandler,
+// e.g. /version responds the version of gateway, and passes other requests to handler.
+// Sub paths of health check paths are served by gatewayHandler too, such as /healthz/ping.
+func WithGatewayServedPaths(handler http.Handler, gatewayHandler http.Handler, paths []string) http.Handler {
+	if len(paths) == 0 {
+		return handler
+	}
+	served := sets.NewString(paths...)
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if isGatewayServedPath(served, req.URL.Path) {
+			gatewayHandler.ServeHTTP(w, req)
+			return
+		}
+		handler.ServeHTTP(w, req)
+	})
+}
+
+func isGatewayServedPath(served sets.String, path string) bool {
+	if served.Has(path) {
+		return true
+	}
+	if len(path) <= 1 {
+		return false
+	}
+	if i := strings.Index(path[1:], "/"); i >= 0 {
+		root := path[:i+1]
+		return root != "/version" && served.Has(root)
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filters
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWithGatewayServedPaths(t *testing.T) {
+	tests := []struct {
+		name       string
+		paths      []string
+		path       string
+		wantServed bool
+	}{
+		{
+			name:       "dispatched by default",
+			paths:      nil,
+			path:       "/version",
+			wantServed: false,
+		},
+		{
+			name:       "version served by gateway",
+			paths:      []string{"/version"},
+			path:       "/version",
+			wantServed: true,
+		},
+		{
+			name:       "sub path of healthz served by gateway",
+			paths:      []string{"/healthz"},
+			path:       "/healthz/ping",
+			wantServed: true,
+		},
+		{
+			name:       "root does not match other paths",
+			paths:      []string{"/"},
+			path:       "/api",
+			wantServed: false,
+		},
+		{
+			name:       "root served by gateway",
+			paths:      []string{"/"},
+			path:       "/",
+			wantServed: true,
+		},
+		{
+			name:       "sub path of version is dispatched",
+			paths:      []string{"/version"},
+			path:       "/version/foo",
+			wantServed: false,
+		},
+		{
+			name:       "resource requests are dispatched",
+			paths:      []string{"/", "/version", "/healthz"},
+			path:       "/api/v1/pods",
+			wantServed: false,
+		},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			served := false
+			gatewayHandler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				served = true
+			})
+			dispatcher := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {})
+			handler := WithGatewayServedPaths(dispatcher, gatewayHandler, tt.paths)
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if served != tt.wantServed {
+				t.Errorf("served by gateway = %v, want %v", served, tt.wantServed)
+			}
+		})
+	}
+}
@@ -15,6 +15,7 @@
 package filters
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -29,6 +30,10 @@ func WithExtraRequestInfo(handler http.Handler, resolver request.ExtraRequestInf
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		ctx := req.Context()
 		info, err := resolver.NewExtraRequestInfo(req)
+		if errors.Is(err, request.ErrHostnameMismatch) {
+			http.Error(w, err.Error(), http.StatusMisdirectedRequest)
+			return
+		}
 		if err != nil {
 			responsewriters.InternalError(w, req, fmt.Errorf("failed to create ExtraRequestInfo: %v", err))
 			return
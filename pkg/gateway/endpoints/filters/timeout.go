@@ -0,0 +1,64 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filters
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	genericfilters "k8s.io/apiserver/pkg/server/filters"
+	"k8s.io/klog"
+
+	"github.com/kubewharf/kubegateway/pkg/gateway/endpoints/request"
+	"github.com/kubewharf/kubegateway/pkg/gateway/metrics"
+)
+
+// WithMaxRequestDuration responds 504 to non long-running requests which do not complete within
+// maxDuration, no matter what timeout is requested by the client or configured for the cluster.
+// The deadline is set to the request context, so that proxy requests to upstream are canceled
+// as well. maxDuration <= 0 disables it.
+func WithMaxRequestDuration(handler http.Handler, longRunningFunc genericapirequest.LongRunningRequestCheck, maxDuration time.Duration) http.Handler {
+	if maxDuration <= 0 || longRunningFunc == nil {
+		return handler
+	}
+	return genericfilters.WithTimeout(handler, func(req *http.Request) (*http.Request, <-chan time.Time, func(), *apierrors.StatusError) {
+		ctx := req.Context()
+		requestInfo, ok := genericapirequest.RequestInfoFrom(ctx)
+		if !ok {
+			// if this happens, the handler chain isn't setup correctly because there is no request info
+			return req, time.After(maxDuration), func() {}, apierrors.NewInternalError(fmt.Errorf("no request info found for request during timeout"))
+		}
+		if longRunningFunc(req, requestInfo) {
+			return req, nil, nil, nil
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, maxDuration)
+		req = req.WithContext(ctx)
+		postTimeoutFn := func() {
+			cancel()
+			var cluster string
+			if info, ok := request.ExtraReqeustInfoFrom(ctx); ok {
+				cluster = info.Hostname
+			}
+			klog.Errorf("[proxy timeout] method=%q host=%q uri=%q exceeds max request duration %v", req.Method, cluster, req.RequestURI, maxDuration)
+			metrics.RecordMaxRequestDurationExceeded(cluster, requestInfo)
+		}
+		return req, time.After(maxDuration), postTimeoutFn, apierrors.NewTimeoutError(fmt.Sprintf("request did not complete within %s", maxDuration), 0)
+	})
+}
@@ -0,0 +1,72 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filters
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+)
+
+func TestWithMaxRequestDuration(t *testing.T) {
+	longRunning := func(req *http.Request, info *genericapirequest.RequestInfo) bool {
+		return info.Verb == "watch"
+	}
+	tests := []struct {
+		name         string
+		verb         string
+		maxDuration  time.Duration
+		handlerTime  time.Duration
+		wantCode     int
+		wantDeadline bool
+	}{
+		{"complete within max duration", "get", time.Second, 0, http.StatusOK, true},
+		{"exceed max duration", "get", 50 * time.Millisecond, time.Hour, http.StatusGatewayTimeout, true},
+		{"long-running requests are not limited", "watch", 50 * time.Millisecond, 100 * time.Millisecond, http.StatusOK, false},
+		{"disabled", "get", 0, 100 * time.Millisecond, http.StatusOK, false},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			handler := WithMaxRequestDuration(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				if _, ok := req.Context().Deadline(); ok != tt.wantDeadline {
+					t.Errorf("request context has deadline %v, want %v", ok, tt.wantDeadline)
+				}
+				select {
+				case <-time.After(tt.handlerTime):
+				case <-req.Context().Done():
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}), longRunning, tt.maxDuration)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/pods", nil)
+			req = req.WithContext(genericapirequest.WithRequestInfo(req.Context(), &genericapirequest.RequestInfo{
+				IsResourceRequest: true,
+				Verb:              tt.verb,
+				APIVersion:        "v1",
+				Resource:          "pods",
+			}))
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			if w.Code != tt.wantCode {
+				t.Errorf("WithMaxRequestDuration() code = %v, want %v", w.Code, tt.wantCode)
+			}
+		})
+	}
+}
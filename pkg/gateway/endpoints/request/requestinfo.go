@@ -16,12 +16,16 @@ package request
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net"
 	"net/http"
+	"strings"
 
 	authenticationv1 "k8s.io/api/authentication/v1"
 	"k8s.io/apiserver/pkg/authentication/user"
 
-	"github.com/kubewharf/kubegateway/pkg/gateway/net"
+	gatewaynet "github.com/kubewharf/kubegateway/pkg/gateway/net"
 )
 
 type key int
@@ -38,24 +42,67 @@ type ExtraRequestInfoResolver interface {
 	NewExtraRequestInfo(req *http.Request) (*ExtraRequestInfo, error)
 }
 
-type ExtraRequestInfoFactory struct{}
+// HostnameMismatchPolicy defines which hostname is used to choose the cluster when
+// TLS SNI and Host header indicate different hostnames in an HTTP/1.x request.
+type HostnameMismatchPolicy string
+
+const (
+	// HostnameMismatchPolicyTrustHost uses hostname in Host header
+	HostnameMismatchPolicyTrustHost HostnameMismatchPolicy = "Host"
+	// HostnameMismatchPolicyTrustSNI uses hostname in TLS SNI
+	HostnameMismatchPolicyTrustSNI HostnameMismatchPolicy = "SNI"
+	// HostnameMismatchPolicyReject rejects the request
+	HostnameMismatchPolicyReject HostnameMismatchPolicy = "Reject"
+)
+
+// ErrHostnameMismatch is returned if SNI and Host header mismatch and the request is rejected
+var ErrHostnameMismatch = errors.New("tls server name and host header mismatch")
+
+type ExtraRequestInfoFactory struct {
+	// HostnameMismatchPolicy only applies to HTTP/1.x requests, because HTTP/2 clients
+	// may reuse a connection for different hosts. Defaults to HostnameMismatchPolicyTrustHost.
+	HostnameMismatchPolicy HostnameMismatchPolicy
+	// TrustedProxies are proxies whose forwarding headers are trusted to resolve the client IP.
+	// If it is empty, the client IP is always the remote address.
+	TrustedProxies gatewaynet.TrustedProxies
+}
 
 func (f *ExtraRequestInfoFactory) NewExtraRequestInfo(req *http.Request) (*ExtraRequestInfo, error) {
 	isImpersonate := len(req.Header.Get(authenticationv1.ImpersonateUserHeader)) > 0
-	hostname := net.HostWithoutPort(req.Host)
+	hostname, err := f.resolveHostname(req)
+	if err != nil {
+		return nil, err
+	}
 
 	return &ExtraRequestInfo{
 		Scheme:               req.URL.Scheme,
 		Hostname:             hostname,
 		IsImpersonateRequest: isImpersonate,
+		ClientIP:             gatewaynet.ClientIP(req, f.TrustedProxies),
 	}, nil
 }
 
+func (f *ExtraRequestInfoFactory) resolveHostname(req *http.Request) (string, error) {
+	hostname := gatewaynet.HostWithoutPort(req.Host)
+	if req.ProtoMajor != 1 || req.TLS == nil || len(req.TLS.ServerName) == 0 || strings.EqualFold(req.TLS.ServerName, hostname) {
+		return hostname, nil
+	}
+	switch f.HostnameMismatchPolicy {
+	case HostnameMismatchPolicyTrustSNI:
+		return strings.ToLower(req.TLS.ServerName), nil
+	case HostnameMismatchPolicyReject:
+		return "", fmt.Errorf("%w: server name %q, host %q", ErrHostnameMismatch, req.TLS.ServerName, hostname)
+	}
+	return hostname, nil
+}
+
 type ExtraRequestInfo struct {
 	Scheme               string
 	Hostname             string // hostname without port
 	IsImpersonateRequest bool
 	Impersonator         user.Info
+	// ClientIP is the real client IP resolved from the trusted proxy chain, it may be nil
+	ClientIP net.IP
 }
 
 // WithExtraReqeustInfo returns a copy of parent in which the ExtraRequestInfo value is set
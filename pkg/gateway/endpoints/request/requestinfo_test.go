@@ -0,0 +1,65 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package request
+
+import (
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestExtraRequestInfoFactory_NewExtraRequestInfo(t *testing.T) {
+	tests := []struct {
+		name         string
+		policy       HostnameMismatchPolicy
+		protoMajor   int
+		serverName   string
+		host         string
+		wantHostname string
+		wantErr      bool
+	}{
+		{"no tls server name", HostnameMismatchPolicyReject, 1, "", "a.cluster:443", "a.cluster", false},
+		{"matched", HostnameMismatchPolicyReject, 1, "A.cluster", "a.cluster:443", "a.cluster", false},
+		{"default trusts host", "", 1, "b.cluster", "a.cluster:443", "a.cluster", false},
+		{"trust host", HostnameMismatchPolicyTrustHost, 1, "b.cluster", "a.cluster:443", "a.cluster", false},
+		{"trust sni", HostnameMismatchPolicyTrustSNI, 1, "b.cluster", "a.cluster:443", "b.cluster", false},
+		{"reject", HostnameMismatchPolicyReject, 1, "b.cluster", "a.cluster:443", "", true},
+		{"http2 is not checked", HostnameMismatchPolicyReject, 2, "b.cluster", "a.cluster:443", "a.cluster", false},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, "https://"+tt.host+"/api", nil)
+			req.ProtoMajor = tt.protoMajor
+			req.TLS = &tls.ConnectionState{ServerName: tt.serverName}
+
+			f := &ExtraRequestInfoFactory{HostnameMismatchPolicy: tt.policy}
+			got, err := f.NewExtraRequestInfo(req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewExtraRequestInfo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !errors.Is(err, ErrHostnameMismatch) {
+					t.Errorf("NewExtraRequestInfo() error = %v, want ErrHostnameMismatch", err)
+				}
+				return
+			}
+			if got.Hostname != tt.wantHostname {
+				t.Errorf("NewExtraRequestInfo() hostname = %v, want %v", got.Hostname, tt.wantHostname)
+			}
+		})
+	}
+}
@@ -81,14 +81,14 @@ var (
 			Help:           "Counter of proxied apiserver requests, it is recorded when this proxied request ends",
 			StabilityLevel: compbasemetrics.ALPHA,
 		},
-		[]string{"pid", "serverName", "endpoint", "verb", "resource", "code"},
+		[]string{"pid", "serverName", "endpoint", "policy", "verb", "resource", "code"},
 	)
 	proxyRequestLatencies = compbasemetrics.NewHistogramVec(
 		&compbasemetrics.HistogramOpts{
 			Namespace: namespace,
 			Subsystem: subsystem,
 			Name:      "apiserver_request_duration_seconds",
-			Help:      "Response latency distribution in seconds for each serverName, endpoint, verb, resource.",
+			Help:      "Response latency distribution in seconds for each serverName, endpoint, policy, verb, resource.",
 			// This metric is used for verifying api call latencies SLO,
 			// as well as tracking regressions in this aspects.
 			// Thus we customize buckets significantly, to empower both usecases.
@@ -96,7 +96,7 @@ var (
 				1.25, 1.5, 1.75, 2.0, 2.5, 3.0, 3.5, 4.0, 4.5, 5, 6, 7, 8, 9, 10, 15, 20, 25, 30, 40, 50, 60, 120, 180, 240, 300},
 			StabilityLevel: compbasemetrics.ALPHA,
 		},
-		[]string{"pid", "serverName", "endpoint", "verb", "resource"},
+		[]string{"pid", "serverName", "endpoint", "policy", "verb", "resource"},
 	)
 	proxyResponseSizes = compbasemetrics.NewHistogramVec(
 		&compbasemetrics.HistogramOpts{
@@ -110,6 +110,18 @@ var (
 		},
 		[]string{"pid", "serverName", "endpoint", "verb", "resource"},
 	)
+	// responseSizes is measured as responses are streamed to clients, for all verbs including watch
+	responseSizes = compbasemetrics.NewHistogramVec(
+		&compbasemetrics.HistogramOpts{
+			Namespace: namespace,
+			Name:      "response_size_bytes",
+			Help:      "Size distribution in bytes of proxied responses written to clients, partitioned by cluster and verb.",
+			// Use buckets ranging from 1000 bytes (1KB) to 10^9 bytes (1GB).
+			Buckets:        prometheus.ExponentialBuckets(1000, 10.0, 7),
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"cluster", "verb"},
+	)
 	proxyUpstreamUnhealthy = compbasemetrics.NewCounterVec(
 		&compbasemetrics.CounterOpts{
 			Namespace:      namespace,
@@ -141,25 +153,318 @@ var (
 		},
 		[]string{"pid", "serverName", "endpoint", "resource"},
 	)
+	// proxyInflightRequests is a number of proxied requests being served, splitted by verb category.
+	proxyInflightRequests = compbasemetrics.NewGaugeVec(
+		&compbasemetrics.GaugeOpts{
+			Namespace:      namespace,
+			Subsystem:      subsystem,
+			Name:           "inflight_requests",
+			Help:           "Number of proxied requests being served for each serverName and verb category (get, list, watch, create, update, patch, delete or other)",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"pid", "serverName", "verb"},
+	)
+	// proxyTLSHostnameResolutions counts how the hostname used to choose a cluster
+	// is resolved during TLS handshakes, by SNI or by falling back to local IP.
+	proxyTLSHostnameResolutions = compbasemetrics.NewCounterVec(
+		&compbasemetrics.CounterOpts{
+			Namespace:      namespace,
+			Subsystem:      subsystem,
+			Name:           "tls_hostname_resolutions_total",
+			Help:           "Counter of TLS handshakes split by hostname source (sni, local_ip, none) and cluster matching result (matched, no_match, error, rejected)",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"pid", "source", "result"},
+	)
+
+	// proxyWatchBookmarks counts bookmark events in proxied watch streams.
+	proxyWatchBookmarks = compbasemetrics.NewCounterVec(
+		&compbasemetrics.CounterOpts{
+			Namespace:      namespace,
+			Subsystem:      subsystem,
+			Name:           "apiserver_watch_bookmarks_total",
+			Help:           "Counter of bookmark events passed through proxied watch streams",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"pid", "serverName", "resource"},
+	)
+
+	proxyShadowRequests = compbasemetrics.NewCounterVec(
+		&compbasemetrics.CounterOpts{
+			Namespace:      namespace,
+			Subsystem:      subsystem,
+			Name:           "shadow_requests_total",
+			Help:           "Counter of requests mirrored to shadow clusters, partitioned by result",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"pid", "serverName", "shadowCluster", "result"},
+	)
+
+	proxyFlowControlRequests = compbasemetrics.NewCounterVec(
+		&compbasemetrics.CounterOpts{
+			Namespace:      namespace,
+			Subsystem:      subsystem,
+			Name:           "flowcontrol_requests_total",
+			Help:           "Counter of requests which go through flow control, including exempt ones, partitioned by result",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"pid", "serverName", "schema", "type", "result"},
+	)
+
+	proxyRetries = compbasemetrics.NewCounterVec(
+		&compbasemetrics.CounterOpts{
+			Namespace:      namespace,
+			Subsystem:      subsystem,
+			Name:           "retries_total",
+			Help:           "Counter of proxy requests retried on another endpoint, partitioned by the failed endpoint and the reason",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"pid", "serverName", "endpoint", "reason"},
+	)
+
+	proxyDownstreamDisconnects = compbasemetrics.NewCounterVec(
+		&compbasemetrics.CounterOpts{
+			Namespace:      namespace,
+			Subsystem:      subsystem,
+			Name:           "downstream_disconnects_total",
+			Help:           "Counter of non long-running proxy requests whose client disconnected before the response completed",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"pid", "serverName", "verb"},
+	)
+
+	proxyMaxRequestDurationExceeded = compbasemetrics.NewCounterVec(
+		&compbasemetrics.CounterOpts{
+			Namespace:      namespace,
+			Subsystem:      subsystem,
+			Name:           "max_request_duration_exceeded_total",
+			Help:           "Counter of non long-running requests responded 504 because they do not complete within the max request duration",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"pid", "serverName", "verb"},
+	)
+
+	proxyUpstreamWatchAborts = compbasemetrics.NewCounterVec(
+		&compbasemetrics.CounterOpts{
+			Namespace:      namespace,
+			Subsystem:      subsystem,
+			Name:           "upstream_watch_aborts_total",
+			Help:           "Counter of watch streams aborted by upstream before the client disconnected, result is how the stream is terminated to the client",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"pid", "serverName", "resource", "result"},
+	)
+
+	proxyWatchDurations = compbasemetrics.NewHistogramVec(
+		&compbasemetrics.HistogramOpts{
+			Namespace:      namespace,
+			Subsystem:      subsystem,
+			Name:           "watch_duration_seconds",
+			Help:           "Duration distribution in seconds of proxied watch streams from the start of proxying to the end of the response",
+			Buckets:        []float64{1, 5, 10, 30, 60, 120, 300, 600, 900, 1800, 3600, 7200, 21600, 86400},
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"pid", "serverName", "resource"},
+	)
+
+	proxyWatchMaxDurationExceeded = compbasemetrics.NewCounterVec(
+		&compbasemetrics.CounterOpts{
+			Namespace:      namespace,
+			Subsystem:      subsystem,
+			Name:           "watch_max_duration_exceeded_total",
+			Help:           "Counter of watch streams closed by the gateway because they last for the max watch duration",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"pid", "serverName", "resource"},
+	)
+
+	upstreamClusterSyncLatencies = compbasemetrics.NewHistogramVec(
+		&compbasemetrics.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "upstream_cluster_sync_duration_seconds",
+			Help:      "Latency distribution in seconds of applying UpstreamCluster changes, including parsing, validating and syncing endpoints, flow control and TLS config.",
+			// Use buckets ranging from 1ms to about 16s.
+			Buckets:        prometheus.ExponentialBuckets(0.001, 2, 15),
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"pid", "serverName", "operation", "result"},
+	)
+
+	upstreamClusterConfigAppliedTimestamp = compbasemetrics.NewGaugeVec(
+		&compbasemetrics.GaugeOpts{
+			Namespace:      namespace,
+			Subsystem:      subsystem,
+			Name:           "upstream_cluster_config_applied_timestamp_seconds",
+			Help:           "Unix timestamp in seconds when the current UpstreamCluster config was applied successfully",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"pid", "serverName"},
+	)
+
+	upstreamClusterConfigGeneration = compbasemetrics.NewGaugeVec(
+		&compbasemetrics.GaugeOpts{
+			Namespace:      namespace,
+			Subsystem:      subsystem,
+			Name:           "upstream_cluster_config_generation",
+			Help:           "Generation of the current UpstreamCluster config which is applied successfully",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"pid", "serverName"},
+	)
+
+	upstreamClusterDispatchPolicies = compbasemetrics.NewGaugeVec(
+		&compbasemetrics.GaugeOpts{
+			Namespace:      namespace,
+			Subsystem:      subsystem,
+			Name:           "upstream_cluster_dispatch_policies",
+			Help:           "Number of dispatch policies of UpstreamCluster, they are evaluated one by one for each request",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"pid", "serverName"},
+	)
+
+	upstreamClusterConfigFailures = compbasemetrics.NewGaugeVec(
+		&compbasemetrics.GaugeOpts{
+			Namespace:      namespace,
+			Subsystem:      subsystem,
+			Name:           "upstream_cluster_config_consecutive_failures",
+			Help:           "Number of consecutive failures of applying UpstreamCluster config, the cluster keeps running the last good config meanwhile",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"pid", "serverName"},
+	)
+
+	upstreamClusterConfigDrift = compbasemetrics.NewGaugeVec(
+		&compbasemetrics.GaugeOpts{
+			Namespace:      namespace,
+			Subsystem:      subsystem,
+			Name:           "upstream_cluster_config_drift",
+			Help:           "1 if consecutive failures of applying UpstreamCluster config reach the escalation threshold, 0 otherwise",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"pid", "serverName"},
+	)
+
+	upstreamTLSVerificationFailures = compbasemetrics.NewCounterVec(
+		&compbasemetrics.CounterOpts{
+			Namespace:      namespace,
+			Subsystem:      subsystem,
+			Name:           "upstream_tls_verification_failures_total",
+			Help:           "Counter of TLS handshakes with upstream endpoints failed to verify the server certificate, partitioned by the source (proxy or health_check) and the reason",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"pid", "serverName", "endpoint", "source", "reason"},
+	)
+
+	upstreamConnections = compbasemetrics.NewGaugeVec(
+		&compbasemetrics.GaugeOpts{
+			Namespace:      namespace,
+			Name:           "upstream_connections",
+			Help:           "Gauge of currently open connections to upstream endpoints",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"cluster", "endpoint"},
+	)
+
+	// proxyTokenCacheEntries is the number of token review results cached for each upstream cluster
+	proxyTokenCacheEntries = compbasemetrics.NewGaugeVec(
+		&compbasemetrics.GaugeOpts{
+			Namespace:      namespace,
+			Subsystem:      subsystem,
+			Name:           "token_cache_entries",
+			Help:           "Number of token review results currently cached for each serverName",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"pid", "serverName"},
+	)
+
+	// proxyFlowControlKeys is the number of keys which have a token bucket in keyed TokenBucket flow control schemas
+	proxyFlowControlKeys = compbasemetrics.NewGaugeVec(
+		&compbasemetrics.GaugeOpts{
+			Namespace:      namespace,
+			Subsystem:      subsystem,
+			Name:           "flowcontrol_keys",
+			Help:           "Number of keys which currently have a token bucket in TokenBucket flow control schemas keyed by user or source IP",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"pid", "serverName", "schema"},
+	)
+
+	impersonationRequests = compbasemetrics.NewCounterVec(
+		&compbasemetrics.CounterOpts{
+			Namespace:      namespace,
+			Name:           "impersonation_total",
+			Help:           "Counter of authorized impersonation requests, partitioned by cluster and the impersonating user",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"cluster", "impersonator"},
+	)
+
+	// sourceIPConnectionsRejected counts downstream connections closed because their source IP
+	// exceeds the connection limit.
+	sourceIPConnectionsRejected = compbasemetrics.NewCounterVec(
+		&compbasemetrics.CounterOpts{
+			Namespace:      namespace,
+			Subsystem:      subsystem,
+			Name:           "source_ip_connections_rejected_total",
+			Help:           "Counter of downstream connections rejected because their source IP exceeds the connection limit",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"pid"},
+	)
+
+	// proxyOutlierTransitions counts endpoints ejected by outlier detection and reinstated.
+	proxyOutlierTransitions = compbasemetrics.NewCounterVec(
+		&compbasemetrics.CounterOpts{
+			Namespace:      namespace,
+			Subsystem:      subsystem,
+			Name:           "upstream_outlier_transitions_total",
+			Help:           "Counter of outlier detection state transitions of upstream endpoints, partitioned by the new state: ejected, half_open or reinstated",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"pid", "serverName", "endpoint", "state"},
+	)
 
 	localMetrics = []compbasemetrics.Registerable{
 		proxyReceiveRequestCounter,
 		proxyRequestCounter,
 		proxyRequestLatencies,
 		proxyResponseSizes,
+		responseSizes,
 		proxyUpstreamUnhealthy,
 		proxyRequestTerminationsTotal,
 		proxyRegisteredWatchers,
+		proxyInflightRequests,
+		proxyTLSHostnameResolutions,
+		proxyWatchBookmarks,
+		upstreamConnections,
+		proxyShadowRequests,
+		proxyFlowControlRequests,
+		proxyRetries,
+		proxyDownstreamDisconnects,
+		proxyMaxRequestDurationExceeded,
+		proxyUpstreamWatchAborts,
+		proxyWatchDurations,
+		proxyWatchMaxDurationExceeded,
+		upstreamClusterSyncLatencies,
+		upstreamClusterConfigAppliedTimestamp,
+		upstreamClusterConfigGeneration,
+		upstreamClusterDispatchPolicies,
+		upstreamClusterConfigFailures,
+		upstreamClusterConfigDrift,
+		upstreamTLSVerificationFailures,
+		impersonationRequests,
+		proxyTokenCacheEntries,
+		proxyFlowControlKeys,
+		sourceIPConnectionsRejected,
+		proxyOutlierTransitions,
 	}
 )
 
 var registerMetrics sync.Once
 
-func init() {
-	Register()
-}
-
-// Register all metrics.
+// Register all metrics into the global registry.
 func Register() {
 	registerMetrics.Do(func() {
 		for _, metric := range localMetrics {
@@ -168,6 +473,31 @@ func Register() {
 	})
 }
 
+// RegisterTo registers all metrics into the given registerer, so that embedders can gather
+// gateway metrics from their own registry. Metrics are registered into the global registry
+// if registerer is nil.
+func RegisterTo(registerer prometheus.Registerer) error {
+	if registerer == nil {
+		Register()
+		return nil
+	}
+	// metrics are created when they are registered into a kube registry, a registry of their
+	// own is used so that they are not exposed by the global one
+	kubeRegistry := compbasemetrics.NewKubeRegistry()
+	for _, metric := range localMetrics {
+		if err := kubeRegistry.Register(metric); err != nil {
+			return err
+		}
+		if err := registerer.Register(metric); err != nil {
+			if _, ok := err.(prometheus.AlreadyRegisteredError); ok {
+				continue
+			}
+			return err
+		}
+	}
+	return nil
+}
+
 // RecordUnhealthyUpstream records that the upstream endpoint is unhealthy.
 func RecordUnhealthyUpstream(serverName string, endpoint string, reason string) {
 	proxyUpstreamUnhealthy.WithLabelValues(proxyPid, serverName, endpoint, reason).Inc()
@@ -191,7 +521,8 @@ func RecordProxyRequestReceived(req *http.Request, serverName string, requestInf
 
 // MonitorProxyRequest handles standard transformations for client and the reported verb and then invokes Monitor to record
 // a request. verb must be uppercase to be backwards compatible with existing monitoring tooling.
-func MonitorProxyRequest(req *http.Request, serverName, endpoint string, requestInfo *request.RequestInfo, contentType string, httpCode, respSize int, elapsed time.Duration) {
+// policy is the name of matched dispatch policy.
+func MonitorProxyRequest(req *http.Request, serverName, endpoint, policy string, requestInfo *request.RequestInfo, contentType string, httpCode, respSize int, elapsed time.Duration) {
 	if requestInfo == nil {
 		requestInfo = &request.RequestInfo{Verb: req.Method, Path: req.URL.Path}
 	}
@@ -206,8 +537,9 @@ func MonitorProxyRequest(req *http.Request, serverName, endpoint string, request
 			resource += "/" + requestInfo.Subresource
 		}
 	}
-	proxyRequestCounter.WithLabelValues(proxyPid, serverName, endpoint, verb, resource, codeToString(httpCode)).Inc()
-	proxyRequestLatencies.WithLabelValues(proxyPid, serverName, endpoint, verb, resource).Observe(elapsedSeconds)
+	proxyRequestCounter.WithLabelValues(proxyPid, serverName, endpoint, policy, verb, resource, codeToString(httpCode)).Inc()
+	proxyRequestLatencies.WithLabelValues(proxyPid, serverName, endpoint, policy, verb, resource).Observe(elapsedSeconds)
+	responseSizes.WithLabelValues(serverName, verb).Observe(float64(respSize))
 	// We are only interested in response sizes of read requests.
 	// nolint:goconst
 	if requestInfo.IsResourceRequest && (verb == "GET" || verb == "LIST") {
@@ -242,6 +574,29 @@ func RecordProxyRequestTermination(req *http.Request, code int, reason string) {
 	proxyRequestTerminationsTotal.WithLabelValues(proxyPid, serverName, cleanVerb(verb, req), requestInfo.Path, codeToString(code), reason, resource).Inc()
 }
 
+// inflightVerbCategories are verb categories of inflight requests, other verbs are reported as other
+var inflightVerbCategories = utilsets.NewString("get", "list", "watch", "create", "update", "patch", "delete")
+
+// InflightVerbCategory returns the verb category of the request reported in the inflight gauge
+func InflightVerbCategory(req *http.Request, requestInfo *request.RequestInfo) string {
+	verb := strings.ToLower(cleanVerb(canonicalVerb(requestInfo, CleanScope(requestInfo)), req))
+	if verb == "deletecollection" {
+		verb = "delete"
+	}
+	if !inflightVerbCategories.Has(verb) {
+		return strings.ToLower(OtherRequestMethod)
+	}
+	return verb
+}
+
+func RecordInflightRequestStarted(serverName, verbCategory string) {
+	proxyInflightRequests.WithLabelValues(proxyPid, serverName, verbCategory).Inc()
+}
+
+func RecordInflightRequestFinished(serverName, verbCategory string) {
+	proxyInflightRequests.WithLabelValues(proxyPid, serverName, verbCategory).Dec()
+}
+
 func RecordWatcherRegistered(serverName, endpoint, resource string) {
 	proxyRegisteredWatchers.WithLabelValues(proxyPid, serverName, endpoint, resource).Inc()
 }
@@ -250,6 +605,166 @@ func RecordWatcherUnregistered(serverName, endpoint, resource string) {
 	proxyRegisteredWatchers.WithLabelValues(proxyPid, serverName, endpoint, resource).Dec()
 }
 
+// RecordTLSHostnameResolution records how the hostname of a TLS handshake is resolved
+// and whether a proxied cluster matches it.
+func RecordTLSHostnameResolution(source, result string) {
+	proxyTLSHostnameResolutions.WithLabelValues(proxyPid, source, result).Inc()
+}
+
+// RecordSourceIPConnectionRejected records a downstream connection rejected by the connection
+// limit of its source IP.
+func RecordSourceIPConnectionRejected() {
+	sourceIPConnectionsRejected.WithLabelValues(proxyPid).Inc()
+}
+
+// RecordOutlierTransition records that outlier detection moves the upstream endpoint to state.
+func RecordOutlierTransition(serverName, endpoint, state string) {
+	proxyOutlierTransitions.WithLabelValues(proxyPid, serverName, endpoint, state).Inc()
+}
+
+// RecordWatchBookmarks records the number of bookmark events in a watch stream.
+func RecordWatchBookmarks(serverName, resource string, count int) {
+	proxyWatchBookmarks.WithLabelValues(proxyPid, serverName, resource).Add(float64(count))
+}
+
+// RecordUpstreamConnectionOpened records that a connection to the upstream endpoint is opened.
+func RecordUpstreamConnectionOpened(cluster, endpoint string) {
+	upstreamConnections.WithLabelValues(cluster, endpoint).Inc()
+}
+
+// RecordUpstreamConnectionClosed records that a connection to the upstream endpoint is closed.
+func RecordUpstreamConnectionClosed(cluster, endpoint string) {
+	upstreamConnections.WithLabelValues(cluster, endpoint).Dec()
+}
+
+// RecordShadowRequest records the result of a request mirrored to the shadow cluster.
+func RecordShadowRequest(serverName, shadowCluster, result string) {
+	proxyShadowRequests.WithLabelValues(proxyPid, serverName, shadowCluster, result).Inc()
+}
+
+// RecordFlowControlRequest records whether the request is accepted by the flow control schema.
+func RecordFlowControlRequest(serverName, schema, schemaType string, accepted bool) {
+	result := "accepted"
+	if !accepted {
+		result = "rejected"
+	}
+	proxyFlowControlRequests.WithLabelValues(proxyPid, serverName, schema, schemaType, result).Inc()
+}
+
+// RecordRetry records that a request failed on the endpoint is retried on another endpoint.
+func RecordRetry(serverName, endpoint, reason string) {
+	proxyRetries.WithLabelValues(proxyPid, serverName, endpoint, reason).Inc()
+}
+
+// RecordDownstreamDisconnect records that the client disconnected before the response completed.
+func RecordDownstreamDisconnect(serverName string, requestInfo *request.RequestInfo) {
+	verb := canonicalVerb(requestInfo, CleanScope(requestInfo))
+	proxyDownstreamDisconnects.WithLabelValues(proxyPid, serverName, verb).Inc()
+}
+
+// RecordMaxRequestDurationExceeded records that the request does not complete within the max request duration.
+func RecordMaxRequestDurationExceeded(serverName string, requestInfo *request.RequestInfo) {
+	verb := canonicalVerb(requestInfo, CleanScope(requestInfo))
+	proxyMaxRequestDurationExceeded.WithLabelValues(proxyPid, serverName, verb).Inc()
+}
+
+// RecordUpstreamWatchAbort records that an upstream watch stream is aborted before the client
+// disconnected, result is one of error_event, close and abort.
+func RecordUpstreamWatchAbort(serverName, resource, result string) {
+	proxyUpstreamWatchAborts.WithLabelValues(proxyPid, serverName, resource, result).Inc()
+}
+
+// RecordWatchDuration records how long a watch stream is proxied to the client
+func RecordWatchDuration(serverName, resource string, duration time.Duration) {
+	proxyWatchDurations.WithLabelValues(proxyPid, serverName, resource).Observe(duration.Seconds())
+}
+
+// RecordWatchMaxDurationExceeded records that a watch stream is closed by the gateway because it
+// lasts for the max watch duration.
+func RecordWatchMaxDurationExceeded(serverName, resource string) {
+	proxyWatchMaxDurationExceeded.WithLabelValues(proxyPid, serverName, resource).Inc()
+}
+
+// RecordUpstreamTLSVerificationFailure records that the certificate of the upstream endpoint failed
+// to be verified, source is one of proxy and health_check.
+func RecordUpstreamTLSVerificationFailure(serverName, endpoint, source, reason string) {
+	upstreamTLSVerificationFailures.WithLabelValues(proxyPid, serverName, endpoint, source, reason).Inc()
+}
+
+// RecordImpersonation records an authorized impersonation request of the impersonator.
+func RecordImpersonation(cluster, impersonator string) {
+	impersonationRequests.WithLabelValues(cluster, impersonator).Inc()
+}
+
+// RecordTokenCacheEntries records the number of token review results cached for the cluster
+func RecordTokenCacheEntries(serverName string, entries int) {
+	proxyTokenCacheEntries.WithLabelValues(proxyPid, serverName).Set(float64(entries))
+}
+
+// ForgetTokenCacheEntries deletes the token cache metric of a stopped cluster
+func ForgetTokenCacheEntries(serverName string) {
+	proxyTokenCacheEntries.Delete(map[string]string{"pid": proxyPid, "serverName": serverName})
+}
+
+// RecordFlowControlKeys records the number of keys which have a token bucket in the keyed flow control schema
+func RecordFlowControlKeys(serverName, schema string, keys int) {
+	proxyFlowControlKeys.WithLabelValues(proxyPid, serverName, schema).Set(float64(keys))
+}
+
+// ForgetFlowControlKeys deletes the keys metric of a deleted or recreated flow control schema
+func ForgetFlowControlKeys(serverName, schema string) {
+	proxyFlowControlKeys.Delete(map[string]string{"pid": proxyPid, "serverName": serverName, "schema": schema})
+}
+
+// RecordUpstreamClusterSync records the latency of applying an UpstreamCluster change, operation
+// is one of create, update and delete.
+func RecordUpstreamClusterSync(serverName, operation string, err error, elapsed time.Duration) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	upstreamClusterSyncLatencies.WithLabelValues(proxyPid, serverName, operation, result).Observe(elapsed.Seconds())
+}
+
+// RecordUpstreamClusterConfigApplied records when and which generation of UpstreamCluster config
+// is applied, a gateway running stale config can be found by comparing them with other gateways.
+func RecordUpstreamClusterConfigApplied(serverName string, generation int64, appliedAt time.Time) {
+	upstreamClusterConfigAppliedTimestamp.WithLabelValues(proxyPid, serverName).Set(float64(appliedAt.Unix()))
+	upstreamClusterConfigGeneration.WithLabelValues(proxyPid, serverName).Set(float64(generation))
+}
+
+// ForgetUpstreamClusterConfig deletes config metrics of a deleted UpstreamCluster
+func ForgetUpstreamClusterConfig(serverName string) {
+	labels := map[string]string{"pid": proxyPid, "serverName": serverName}
+	upstreamClusterConfigAppliedTimestamp.Delete(labels)
+	upstreamClusterConfigGeneration.Delete(labels)
+	upstreamClusterDispatchPolicies.Delete(labels)
+}
+
+// RecordDispatchPolicies records the number of dispatch policies of UpstreamCluster
+func RecordDispatchPolicies(serverName string, policies int) {
+	upstreamClusterDispatchPolicies.WithLabelValues(proxyPid, serverName).Set(float64(policies))
+}
+
+// RecordUpstreamClusterConfigFailures records consecutive failures of applying UpstreamCluster config
+// and whether they are escalated as config drift.
+func RecordUpstreamClusterConfigFailures(serverName string, failures int, drift bool) {
+	upstreamClusterConfigFailures.WithLabelValues(proxyPid, serverName).Set(float64(failures))
+	value := 0.0
+	if drift {
+		value = 1
+	}
+	upstreamClusterConfigDrift.WithLabelValues(proxyPid, serverName).Set(value)
+}
+
+// ForgetUpstreamClusterConfigFailures deletes failure metrics of an UpstreamCluster after its config
+// is applied or it is deleted
+func ForgetUpstreamClusterConfigFailures(serverName string) {
+	labels := map[string]string{"pid": proxyPid, "serverName": serverName}
+	upstreamClusterConfigFailures.Delete(labels)
+	upstreamClusterConfigDrift.Delete(labels)
+}
+
 // CleanScope returns the scope of the request.
 func CleanScope(requestInfo *request.RequestInfo) string {
 	if requestInfo.Name != "" || requestInfo.Verb == "create" {
@@ -0,0 +1,88 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	metricsregistry "github.com/kubewharf/kubegateway/pkg/gateway/metrics/registry"
+)
+
+func TestRegisterTo(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	if err := RegisterTo(registry); err != nil {
+		t.Fatalf("RegisterTo() error = %v", err)
+	}
+	// registering twice is allowed
+	if err := RegisterTo(registry); err != nil {
+		t.Fatalf("RegisterTo() error = %v", err)
+	}
+
+	RecordTLSHostnameResolution("sni", "matched")
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	found := false
+	for _, mf := range families {
+		if mf.GetName() == "kubegateway_proxy_tls_hostname_resolutions_total" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("metric kubegateway_proxy_tls_hostname_resolutions_total is not gathered from custom registerer")
+	}
+
+	// metrics are not registered into the global registry if a registerer is given
+	families, err = metricsregistry.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	for _, mf := range families {
+		if strings.HasPrefix(mf.GetName(), namespace+"_") {
+			t.Errorf("metric %s is registered into the global registry", mf.GetName())
+		}
+	}
+}
+
+func TestInflightVerbCategory(t *testing.T) {
+	tests := []struct {
+		name        string
+		url         string
+		requestInfo *request.RequestInfo
+		want        string
+	}{
+		{"get", "/api/v1/namespaces/default/pods/a", &request.RequestInfo{IsResourceRequest: true, Verb: "get", Resource: "pods", Name: "a"}, "get"},
+		{"list", "/api/v1/pods", &request.RequestInfo{IsResourceRequest: true, Verb: "list", Resource: "pods"}, "list"},
+		{"watch", "/api/v1/pods?watch=true", &request.RequestInfo{IsResourceRequest: true, Verb: "watch", Resource: "pods"}, "watch"},
+		{"deletecollection", "/api/v1/pods", &request.RequestInfo{IsResourceRequest: true, Verb: "deletecollection", Resource: "pods"}, "delete"},
+		{"proxy", "/api/v1/nodes/a/proxy", &request.RequestInfo{IsResourceRequest: true, Verb: "proxy", Resource: "nodes", Name: "a"}, "other"},
+		{"non-resource", "/healthz", &request.RequestInfo{Verb: "get", Path: "/healthz"}, "get"},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InflightVerbCategory(httptest.NewRequest("GET", tt.url, nil), tt.requestInfo); got != tt.want {
+				t.Errorf("InflightVerbCategory() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
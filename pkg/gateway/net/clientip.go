@@ -0,0 +1,139 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package net
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// TrustedProxies is a list of CIDRs of proxies, e.g. L7 load balancers, whose
+// X-Forwarded-For and Forwarded headers are trusted.
+type TrustedProxies []*net.IPNet
+
+// ParseTrustedProxies parses CIDRs of trusted proxies, single IPs are also accepted.
+func ParseTrustedProxies(cidrs []string) (TrustedProxies, error) {
+	var ret TrustedProxies
+	for _, cidr := range cidrs {
+		cidr = strings.TrimSpace(cidr)
+		if len(cidr) == 0 {
+			continue
+		}
+		if !strings.Contains(cidr, "/") {
+			ip := net.ParseIP(cidr)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid IP address %q", cidr)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			ret = append(ret, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, ipNet)
+	}
+	return ret, nil
+}
+
+func (t TrustedProxies) contains(ip net.IP) bool {
+	for _, ipNet := range t {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP returns the real client IP of the request.
+//
+// The forwarding headers are only taken into account if the direct peer is a trusted proxy.
+// In that case, the proxy chain in X-Forwarded-For, or in Forwarded if X-Forwarded-For is
+// not present, is walked from right to left, and the first address which is not a trusted
+// proxy is the client IP. Addresses on the left of it may be spoofed by the client, so they
+// are never used. It returns nil if the remote address can not be parsed.
+func ClientIP(req *http.Request, trusted TrustedProxies) net.IP {
+	ip := remoteIP(req.RemoteAddr)
+	if ip == nil || len(trusted) == 0 || !trusted.contains(ip) {
+		return ip
+	}
+
+	chain := forwardedForChain(req.Header)
+	for i := len(chain) - 1; i >= 0; i-- {
+		hop := parseForwardedIP(chain[i])
+		if hop == nil {
+			// the chain is broken, the rest of it can not be trusted
+			return ip
+		}
+		ip = hop
+		if !trusted.contains(hop) {
+			return hop
+		}
+	}
+	// all hops are trusted proxies, use the leftmost one
+	return ip
+}
+
+func remoteIP(remoteAddr string) net.IP {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	return net.ParseIP(host)
+}
+
+// forwardedForChain returns the list of forwarded addresses from the client to the last proxy.
+func forwardedForChain(header http.Header) []string {
+	var chain []string
+	if values := header.Values("X-Forwarded-For"); len(values) > 0 {
+		for _, value := range values {
+			for _, hop := range strings.Split(value, ",") {
+				chain = append(chain, strings.TrimSpace(hop))
+			}
+		}
+		return chain
+	}
+	// RFC 7239, e.g. Forwarded: for=192.0.2.43, for="[2001:db8:cafe::17]:4711";proto=https
+	for _, value := range header.Values("Forwarded") {
+		for _, element := range strings.Split(value, ",") {
+			forValue := ""
+			for _, pair := range strings.Split(element, ";") {
+				kv := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+				if len(kv) == 2 && strings.EqualFold(kv[0], "for") {
+					forValue = strings.Trim(kv[1], `"`)
+				}
+			}
+			chain = append(chain, forValue)
+		}
+	}
+	return chain
+}
+
+// parseForwardedIP parses an IP address with optional port or brackets.
+func parseForwardedIP(hop string) net.IP {
+	if ip := net.ParseIP(hop); ip != nil {
+		return ip
+	}
+	if host, _, err := net.SplitHostPort(hop); err == nil {
+		return net.ParseIP(host)
+	}
+	return net.ParseIP(strings.TrimSuffix(strings.TrimPrefix(hop, "["), "]"))
+}
@@ -0,0 +1,101 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package net
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	trusted, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.1", "fd00::/8"})
+	if err != nil {
+		t.Fatalf("ParseTrustedProxies() error = %v", err)
+	}
+	tests := []struct {
+		name       string
+		remoteAddr string
+		header     http.Header
+		trusted    TrustedProxies
+		want       string
+	}{
+		{
+			name:       "no trusted proxies ignores headers",
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"X-Forwarded-For": {"1.1.1.1"}},
+			want:       "10.0.0.1",
+		},
+		{
+			name:       "untrusted peer can not spoof",
+			remoteAddr: "2.2.2.2:1234",
+			header:     http.Header{"X-Forwarded-For": {"1.1.1.1"}},
+			trusted:    trusted,
+			want:       "2.2.2.2",
+		},
+		{
+			name:       "trusted peer without headers",
+			remoteAddr: "10.0.0.1:1234",
+			trusted:    trusted,
+			want:       "10.0.0.1",
+		},
+		{
+			name:       "rightmost untrusted hop is the client",
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"X-Forwarded-For": {"6.6.6.6, 1.1.1.1", "192.168.1.1"}},
+			trusted:    trusted,
+			want:       "1.1.1.1",
+		},
+		{
+			name:       "all hops trusted uses leftmost",
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"X-Forwarded-For": {"10.1.1.1, 10.2.2.2"}},
+			trusted:    trusted,
+			want:       "10.1.1.1",
+		},
+		{
+			name:       "broken chain stops at last valid hop",
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"X-Forwarded-For": {"1.1.1.1, unknown, 10.2.2.2"}},
+			trusted:    trusted,
+			want:       "10.2.2.2",
+		},
+		{
+			name:       "forwarded header",
+			remoteAddr: "[fd00::1]:1234",
+			header:     http.Header{"Forwarded": {`for=6.6.6.6, for="[2001:db8:cafe::17]:4711";proto=https`}},
+			trusted:    trusted,
+			want:       "2001:db8:cafe::17",
+		},
+		{
+			name:       "x-forwarded-for takes precedence over forwarded",
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"X-Forwarded-For": {"1.1.1.1"}, "Forwarded": {"for=6.6.6.6"}},
+			trusted:    trusted,
+			want:       "1.1.1.1",
+		},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			req := &http.Request{RemoteAddr: tt.remoteAddr, Header: tt.header}
+			if req.Header == nil {
+				req.Header = http.Header{}
+			}
+			if got := ClientIP(req, tt.trusted); got.String() != tt.want {
+				t.Errorf("ClientIP() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
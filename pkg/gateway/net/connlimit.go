@@ -0,0 +1,166 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package net
+
+import (
+	"errors"
+	"net"
+	"sync"
+
+	"k8s.io/klog"
+)
+
+var errTooManyConnections = errors.New("too many connections from the source IP")
+
+// sourceIPConnections counts open connections of each source IP
+type sourceIPConnections struct {
+	limit int
+
+	lock  sync.Mutex
+	conns map[string]int
+}
+
+func (c *sourceIPConnections) tryAcquire(ip string) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.conns[ip] >= c.limit {
+		return false
+	}
+	c.conns[ip]++
+	return true
+}
+
+func (c *sourceIPConnections) release(ip string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.conns[ip]--
+	if c.conns[ip] <= 0 {
+		delete(c.conns, ip)
+	}
+}
+
+// connLimitListener closes new connections from a source IP which already has limit
+// open connections.
+type connLimitListener struct {
+	net.Listener
+	conns  *sourceIPConnections
+	exempt []*net.IPNet
+	// onReject is called after a connection is rejected
+	onReject func()
+}
+
+// LimitConnectionsPerSourceIP wraps the listener to limit the number of concurrent connections
+// from each source IP, connections over the limit are closed before TLS handshakes. Source IPs
+// in exempt CIDRs are not limited, e.g. trusted proxies whose connections are shared by lots of
+// clients. The source IP is the remote address of the connection, which is the real client IP
+// if the listener speaks PROXY protocol. onReject is called after a connection is rejected, it
+// can be nil. limit <= 0 disables it.
+func LimitConnectionsPerSourceIP(l net.Listener, limit int, exempt []*net.IPNet, onReject func()) net.Listener {
+	if limit <= 0 {
+		return l
+	}
+	return &connLimitListener{
+		Listener: l,
+		conns:    &sourceIPConnections{limit: limit, conns: map[string]int{}},
+		exempt:   exempt,
+		onReject: onReject,
+	}
+}
+
+func (l *connLimitListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	// the remote address is not resolved here, because it may block on reading the PROXY
+	// protocol header, and the accept loop must not be blocked by a slow client
+	return &connLimitConn{Conn: conn, listener: l}, nil
+}
+
+func (l *connLimitListener) isExempt(ip net.IP) bool {
+	return TrustedProxies(l.exempt).contains(ip)
+}
+
+// connLimitConn acquires a connection of its source IP before the first read or write, it is
+// closed if the source IP is at its limit.
+type connLimitConn struct {
+	net.Conn
+	listener *connLimitListener
+
+	acquireOnce sync.Once
+	// ip is the source IP counted for this connection, it is empty if the connection is not counted
+	ip  string
+	err error
+
+	closeOnce sync.Once
+}
+
+func (c *connLimitConn) acquire() {
+	ip := remoteIP(c.Conn.RemoteAddr().String())
+	if ip == nil || c.listener.isExempt(ip) {
+		return
+	}
+	if !c.listener.conns.tryAcquire(ip.String()) {
+		klog.V(2).Infof("[connection limit] reject connection from %v, it exceeds %d connections per source IP", c.Conn.RemoteAddr(), c.listener.conns.limit)
+		if c.listener.onReject != nil {
+			c.listener.onReject()
+		}
+		c.err = errTooManyConnections
+		c.Conn.Close() //nolint
+		return
+	}
+	c.ip = ip.String()
+}
+
+func (c *connLimitConn) Read(b []byte) (int, error) {
+	c.acquireOnce.Do(c.acquire)
+	if c.err != nil {
+		return 0, c.err
+	}
+	return c.Conn.Read(b)
+}
+
+func (c *connLimitConn) Write(b []byte) (int, error) {
+	c.acquireOnce.Do(c.acquire)
+	if c.err != nil {
+		return 0, c.err
+	}
+	return c.Conn.Write(b)
+}
+
+func (c *connLimitConn) Close() error {
+	c.closeOnce.Do(func() {
+		// wait for acquire in progress, or prevent it from counting a closed connection
+		c.acquireOnce.Do(func() {})
+		if len(c.ip) > 0 {
+			c.listener.conns.release(c.ip)
+		}
+	})
+	return c.Conn.Close()
+}
+
+// TCPConn returns the underlying TCP connection, so that tcp keep-alive is still set by
+// the generic server.
+func (c *connLimitConn) TCPConn() (*net.TCPConn, bool) {
+	switch conn := c.Conn.(type) {
+	case *net.TCPConn:
+		return conn, true
+	case interface {
+		TCPConn() (*net.TCPConn, bool)
+	}:
+		return conn.TCPConn()
+	}
+	return nil, false
+}
@@ -0,0 +1,93 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package net
+
+import (
+	"net"
+	"testing"
+)
+
+func TestLimitConnectionsPerSourceIP(t *testing.T) {
+	loopback, _ := ParseTrustedProxies([]string{"127.0.0.1"})
+	tests := []struct {
+		name     string
+		limit    int
+		exempt   []*net.IPNet
+		conns    int
+		rejected int
+	}{
+		{"disabled", 0, nil, 3, 0},
+		{"under limit", 3, nil, 3, 0},
+		{"over limit", 2, nil, 3, 1},
+		{"exempt", 1, loopback, 3, 0},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			rejected := 0
+			l = LimitConnectionsPerSourceIP(l, tt.limit, tt.exempt, func() { rejected++ })
+			defer l.Close()
+
+			var accepted []net.Conn
+			for i := 0; i < tt.conns; i++ {
+				client, err := net.Dial("tcp", l.Addr().String())
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer client.Close()
+				if _, err := client.Write([]byte("x")); err != nil {
+					t.Fatal(err)
+				}
+				conn, err := l.Accept()
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer conn.Close()
+				if _, err := conn.Read(make([]byte, 1)); err == nil {
+					accepted = append(accepted, conn)
+				}
+			}
+			if got := tt.conns - len(accepted); got != tt.rejected || rejected != tt.rejected {
+				t.Fatalf("rejected connections = %v, onReject called %v times, want %v", got, rejected, tt.rejected)
+			}
+			if tt.rejected == 0 {
+				return
+			}
+
+			// closing a connection frees up room for a new one
+			accepted[0].Close()
+			client, err := net.Dial("tcp", l.Addr().String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer client.Close()
+			if _, err := client.Write([]byte("x")); err != nil {
+				t.Fatal(err)
+			}
+			conn, err := l.Accept()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer conn.Close()
+			if _, err := conn.Read(make([]byte, 1)); err != nil {
+				t.Errorf("connection after another one is closed is rejected: %v", err)
+			}
+		})
+	}
+}
@@ -30,6 +30,7 @@ import (
 	"k8s.io/apiserver/pkg/authentication/request/x509"
 
 	"github.com/kubewharf/kubegateway/pkg/clusters"
+	"github.com/kubewharf/kubegateway/pkg/gateway/authentication/clientcert"
 	"github.com/kubewharf/kubegateway/pkg/gateway/authentication/token/webhook"
 )
 
@@ -40,18 +41,25 @@ type ClientCertAuthenticationConfig struct {
 	CAContentProvider authenticatorfactory.CAContentProvider
 	// SNIVerifyOptionsPorvider provides dynamic verifyOptions for each sni hostname
 	SNIVerifyOptionsPorvider x509.SNIVerifyOptionsProvider
+	// SerialAllowlist is optional, if it is set, client certificates must be in the allowlist
+	// in addition to chaining to CA
+	SerialAllowlist *clientcert.SerialAllowlist
 }
 
 func (c *ClientCertAuthenticationConfig) New() authenticator.Request {
 	if c == nil {
 		return nil
 	}
+	var userConversion x509.UserConversion = x509.CommonNameUserConversion
+	if c.SerialAllowlist != nil {
+		userConversion = c.SerialAllowlist.UserConversion(userConversion)
+	}
 	if c.CAContentProvider != nil && c.SNIVerifyOptionsPorvider != nil {
-		return x509.NewSNIDynamic(c.SNIVerifyOptionsPorvider.SNIVerifyOptions, c.CAContentProvider.VerifyOptions, x509.CommonNameUserConversion)
+		return x509.NewSNIDynamic(c.SNIVerifyOptionsPorvider.SNIVerifyOptions, c.CAContentProvider.VerifyOptions, userConversion)
 	} else if c.CAContentProvider != nil && c.SNIVerifyOptionsPorvider == nil {
-		return x509.NewDynamic(c.CAContentProvider.VerifyOptions, x509.CommonNameUserConversion)
+		return x509.NewDynamic(c.CAContentProvider.VerifyOptions, userConversion)
 	} else if c.CAContentProvider == nil && c.SNIVerifyOptionsPorvider != nil {
-		return x509.NewSNIDynamic(c.SNIVerifyOptionsPorvider.SNIVerifyOptions, nil, x509.CommonNameUserConversion)
+		return x509.NewSNIDynamic(c.SNIVerifyOptionsPorvider.SNIVerifyOptions, nil, userConversion)
 	}
 	return nil
 }
@@ -73,6 +81,12 @@ type AuthenricatorConfig struct {
 	TokenSuccessCacheTTL time.Duration
 	TokenFailureCacheTTL time.Duration
 	APIAudiences         authenticator.Audiences
+	// TokenCacheMaxEntries is the maximum number of cached token review results of each
+	// upstream cluster, zero means no limit.
+	TokenCacheMaxEntries int
+	// TokenReviewMaxInflight is the maximum number of inflight token reviews to each
+	// upstream cluster, zero means no limit.
+	TokenReviewMaxInflight int
 
 	TokenRequest *TokenAuthenticationConfig
 
@@ -105,7 +119,7 @@ func (c AuthenricatorConfig) New() (authenticator.Request, *spec.SecurityDefinit
 	if c.TokenRequest != nil {
 		var tokenAuth authenticator.Token
 		if c.TokenRequest.ClusterClientProvider != nil {
-			tokenAuth = webhook.NewMultiClusterTokenReviewAuthenticator(c.TokenRequest.ClusterClientProvider, c.TokenSuccessCacheTTL, c.TokenFailureCacheTTL, c.APIAudiences)
+			tokenAuth = webhook.NewMultiClusterTokenReviewAuthenticator(c.TokenRequest.ClusterClientProvider, c.TokenSuccessCacheTTL, c.TokenFailureCacheTTL, c.APIAudiences, c.TokenReviewMaxInflight, c.TokenCacheMaxEntries)
 		}
 		if tokenAuth != nil {
 			authenticators = append(authenticators, bearertoken.New(tokenAuth), websocket.NewProtocolAuthenticator(tokenAuth))
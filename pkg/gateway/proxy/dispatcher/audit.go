@@ -0,0 +1,94 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dispatcher
+
+import (
+	"context"
+
+	"k8s.io/apiserver/pkg/audit"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+
+	gatewayflowcontrol "github.com/kubewharf/kubegateway/pkg/flowcontrol"
+)
+
+// FlowControlAuditPolicy defines which flow control decisions are recorded in audit
+// events as annotations.
+type FlowControlAuditPolicy string
+
+const (
+	// FlowControlAuditNone records no decisions.
+	FlowControlAuditNone FlowControlAuditPolicy = "None"
+	// FlowControlAuditRejected records decisions of requests rejected by flow control.
+	FlowControlAuditRejected FlowControlAuditPolicy = "Rejected"
+	// FlowControlAuditAll records decisions of all requests going through flow control.
+	FlowControlAuditAll FlowControlAuditPolicy = "All"
+)
+
+const (
+	// audit annotation keys of flow control decisions
+	flowControlSchemaAnnotationKey   = "flowcontrol.kubegateway.io/schema"
+	flowControlDecisionAnnotationKey = "flowcontrol.kubegateway.io/decision"
+
+	flowControlDecisionAdmitted = "admitted"
+	flowControlDecisionRejected = "rejected"
+
+	// audit annotation keys of routing decisions
+	routingClusterAnnotationKey           = "routing.kubegateway.io/cluster"
+	routingPolicyAnnotationKey            = "routing.kubegateway.io/policy"
+	routingEndpointAnnotationKey          = "routing.kubegateway.io/endpoint"
+	routingFlowControlSchemaAnnotationKey = "routing.kubegateway.io/flowcontrol-schema"
+)
+
+// auditFlowControlDecision annotates the audit event of request with the flow control
+// schema and whether the request is admitted, according to the policy.
+func auditFlowControlDecision(ctx context.Context, policy FlowControlAuditPolicy, fl gatewayflowcontrol.FlowControl, acquired bool) {
+	switch {
+	case policy == FlowControlAuditAll:
+	case policy == FlowControlAuditRejected && !acquired:
+	default:
+		return
+	}
+	ae := genericapirequest.AuditEventFrom(ctx)
+	if ae == nil {
+		return
+	}
+	decision := flowControlDecisionAdmitted
+	if !acquired {
+		decision = flowControlDecisionRejected
+	}
+	audit.LogAnnotation(ae, flowControlSchemaAnnotationKey, fl.Name())
+	audit.LogAnnotation(ae, flowControlDecisionAnnotationKey, decision)
+}
+
+// auditRoutingDecision annotates the audit event of request with how it is routed: the upstream
+// cluster, the matched dispatch policy, the endpoint it is finally proxied to and the applied
+// flow control schema. Empty values are not recorded, e.g. no endpoint is picked for requests
+// rejected by flow control.
+func auditRoutingDecision(ctx context.Context, cluster, policy, endpoint, flowControlSchema string) {
+	ae := genericapirequest.AuditEventFrom(ctx)
+	if ae == nil {
+		return
+	}
+	for _, annotation := range []struct{ key, value string }{
+		{routingClusterAnnotationKey, cluster},
+		{routingPolicyAnnotationKey, policy},
+		{routingEndpointAnnotationKey, endpoint},
+		{routingFlowControlSchemaAnnotationKey, flowControlSchema},
+	} {
+		if len(annotation.value) > 0 {
+			audit.LogAnnotation(ae, annotation.key, annotation.value)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dispatcher
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	auditinternal "k8s.io/apiserver/pkg/apis/audit"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+
+	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
+	gatewayflowcontrol "github.com/kubewharf/kubegateway/pkg/flowcontrol"
+)
+
+func Test_auditFlowControlDecision(t *testing.T) {
+	fl := gatewayflowcontrol.NewFlowControl(proxyv1alpha1.FlowControlSchema{
+		Name: "limited",
+		FlowControlSchemaConfiguration: proxyv1alpha1.FlowControlSchemaConfiguration{
+			MaxRequestsInflight: &proxyv1alpha1.MaxRequestsInflightFlowControlSchema{Max: 1},
+		},
+	})
+	admitted := map[string]string{
+		flowControlSchemaAnnotationKey:   "limited",
+		flowControlDecisionAnnotationKey: flowControlDecisionAdmitted,
+	}
+	rejected := map[string]string{
+		flowControlSchemaAnnotationKey:   "limited",
+		flowControlDecisionAnnotationKey: flowControlDecisionRejected,
+	}
+	tests := []struct {
+		name     string
+		policy   FlowControlAuditPolicy
+		acquired bool
+		want     map[string]string
+	}{
+		{"none admitted", FlowControlAuditNone, true, nil},
+		{"none rejected", FlowControlAuditNone, false, nil},
+		{"rejected admitted", FlowControlAuditRejected, true, nil},
+		{"rejected rejected", FlowControlAuditRejected, false, rejected},
+		{"all admitted", FlowControlAuditAll, true, admitted},
+		{"all rejected", FlowControlAuditAll, false, rejected},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			ae := &auditinternal.Event{Level: auditinternal.LevelMetadata}
+			ctx := genericapirequest.WithAuditEvent(context.Background(), ae)
+			auditFlowControlDecision(ctx, tt.policy, fl, tt.acquired)
+			if !reflect.DeepEqual(ae.Annotations, tt.want) {
+				t.Errorf("auditFlowControlDecision() annotations = %v, want %v", ae.Annotations, tt.want)
+			}
+		})
+	}
+
+	// requests without audit event are ignored
+	auditFlowControlDecision(context.Background(), FlowControlAuditAll, fl, true)
+}
+
+func Test_auditRoutingDecision(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint string
+		want     map[string]string
+	}{
+		{
+			"proxied",
+			"https://127.0.0.1:6443",
+			map[string]string{
+				routingClusterAnnotationKey:           "test",
+				routingPolicyAnnotationKey:            "default",
+				routingEndpointAnnotationKey:          "https://127.0.0.1:6443",
+				routingFlowControlSchemaAnnotationKey: "limited",
+			},
+		},
+		{
+			"no endpoint picked",
+			"",
+			map[string]string{
+				routingClusterAnnotationKey:           "test",
+				routingPolicyAnnotationKey:            "default",
+				routingFlowControlSchemaAnnotationKey: "limited",
+			},
+		},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			ae := &auditinternal.Event{Level: auditinternal.LevelMetadata}
+			ctx := genericapirequest.WithAuditEvent(context.Background(), ae)
+			auditRoutingDecision(ctx, "test", "default", tt.endpoint, "limited")
+			if !reflect.DeepEqual(ae.Annotations, tt.want) {
+				t.Errorf("auditRoutingDecision() annotations = %v, want %v", ae.Annotations, tt.want)
+			}
+		})
+	}
+
+	// requests without audit event are ignored
+	auditRoutingDecision(context.Background(), "test", "default", "https://127.0.0.1:6443", "limited")
+}
@@ -0,0 +1,61 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dispatcher
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// bufferRequestBody reads the request body into memory if it is not larger than limit,
+// and sets req.GetBody so that the body can be replayed when the request is retried.
+// If the body is too large, req.GetBody is left nil and the body is still fully readable,
+// retrying this request must be disabled in this case.
+func bufferRequestBody(req *http.Request, limit int64) error {
+	if req.Body == nil || req.Body == http.NoBody || req.GetBody != nil {
+		return nil
+	}
+	if req.ContentLength > limit {
+		return nil
+	}
+
+	body := req.Body
+	buf, err := ioutil.ReadAll(io.LimitReader(body, limit+1))
+	if err != nil {
+		return err
+	}
+	if int64(len(buf)) > limit {
+		// the length is unknown and exceeds the limit, restore the read bytes
+		req.Body = &readCloser{
+			Reader: io.MultiReader(bytes.NewReader(buf), body),
+			Closer: body,
+		}
+		return nil
+	}
+
+	body.Close()
+	req.Body = ioutil.NopCloser(bytes.NewReader(buf))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(buf)), nil
+	}
+	return nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
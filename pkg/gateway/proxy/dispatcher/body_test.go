@@ -0,0 +1,67 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dispatcher
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func Test_bufferRequestBody(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		contentLength int64
+		limit         int64
+		wantGetBody   bool
+	}{
+		{"small body", "hello", 5, 10, true},
+		{"body equals limit", "helloworld", 10, 10, true},
+		{"large body", "hello world", 11, 10, false},
+		{"small body with unknown length", "hello", -1, 10, true},
+		{"large body with unknown length", "hello world", -1, 10, false},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodPost, "https://127.0.0.1/api/v1/pods", ioutil.NopCloser(strings.NewReader(tt.body)))
+			req.ContentLength = tt.contentLength
+
+			if err := bufferRequestBody(req, tt.limit); err != nil {
+				t.Fatalf("bufferRequestBody() error = %v", err)
+			}
+			if (req.GetBody != nil) != tt.wantGetBody {
+				t.Fatalf("bufferRequestBody() GetBody = %v, want %v", req.GetBody != nil, tt.wantGetBody)
+			}
+			got, _ := ioutil.ReadAll(req.Body)
+			if string(got) != tt.body {
+				t.Errorf("bufferRequestBody() body = %q, want %q", got, tt.body)
+			}
+			if req.GetBody == nil {
+				return
+			}
+			// replay twice
+			for i := 0; i < 2; i++ {
+				body, _ := req.GetBody()
+				got, _ := ioutil.ReadAll(body)
+				if string(got) != tt.body {
+					t.Errorf("bufferRequestBody() replayed body = %q, want %q", got, tt.body)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dispatcher
+
+import (
+	"bytes"
+)
+
+var (
+	// bookmarkPatterns are the encoded type field of bookmark watch events
+	bookmarkPatterns = [][]byte{
+		// json: {"type":"BOOKMARK","object":{...}}
+		[]byte(`"type":"BOOKMARK"`),
+		// protobuf: field 1 (type) of WatchEvent with 8 bytes string value
+		[]byte("\x0a\x08BOOKMARK"),
+	}
+)
+
+// bookmarkDetector counts bookmark events in a watch stream without buffering it.
+// The stream may be split at any point, so the last bytes of previous write are
+// kept to detect patterns across writes.
+type bookmarkDetector struct {
+	// tails[i] is the last len(bookmarkPatterns[i])-1 bytes written
+	tails [][]byte
+}
+
+func newBookmarkDetector() *bookmarkDetector {
+	d := &bookmarkDetector{
+		tails: make([][]byte, len(bookmarkPatterns)),
+	}
+	for i, pattern := range bookmarkPatterns {
+		d.tails[i] = make([]byte, 0, 2*len(pattern))
+	}
+	return d
+}
+
+// Detect returns the number of bookmark events found in b
+func (d *bookmarkDetector) Detect(b []byte) int {
+	count := 0
+	for i, pattern := range bookmarkPatterns {
+		count += bytes.Count(b, pattern)
+
+		n := len(pattern) - 1
+		tail := d.tails[i]
+		if len(tail) > 0 {
+			// tail is shorter than pattern, so joint only matches patterns across writes
+			head := b
+			if len(head) > n {
+				head = head[:n]
+			}
+			count += bytes.Count(append(tail, head...), pattern)
+		}
+
+		// keep the last n bytes of tail + b
+		if len(b) >= n {
+			tail = append(tail[:0], b[len(b)-n:]...)
+		} else {
+			tail = append(tail, b...)
+			if len(tail) > n {
+				tail = append(tail[:0], tail[len(tail)-n:]...)
+			}
+		}
+		if len(tail) > n {
+			tail = append(tail[:0], tail[len(tail)-n:]...)
+		}
+		d.tails[i] = tail
+	}
+	return count
+}
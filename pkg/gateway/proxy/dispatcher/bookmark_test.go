@@ -0,0 +1,60 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dispatcher
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_bookmarkDetector_Detect(t *testing.T) {
+	jsonStream := `{"type":"ADDED","object":{"kind":"Pod"}}` + "\n" +
+		`{"type":"BOOKMARK","object":{"kind":"Pod","metadata":{"resourceVersion":"12"}}}` + "\n" +
+		`{"type":"MODIFIED","object":{"kind":"Pod","metadata":{"name":"BOOKMARK"}}}` + "\n" +
+		`{"type":"BOOKMARK","object":{"kind":"Pod","metadata":{"resourceVersion":"13"}}}` + "\n"
+	protobufStream := "\x00\x00\x00\x20k8s\x00\x0a\x08BOOKMARK\x12\x10" + "\x00\x00\x00\x20k8s\x00\x0a\x05ADDED\x12\x10BOOKMARK"
+
+	tests := []struct {
+		name      string
+		stream    string
+		chunkSize int
+		want      int
+	}{
+		{"json in one write", jsonStream, len(jsonStream), 2},
+		{"json split into bytes", jsonStream, 1, 2},
+		{"json split into chunks", jsonStream, 7, 2},
+		{"protobuf in one write", protobufStream, len(protobufStream), 1},
+		{"protobuf split into bytes", protobufStream, 1, 1},
+		{"protobuf split into chunks", protobufStream, 5, 1},
+		{"no bookmarks", strings.Repeat(`{"type":"ADDED"}`, 10), 3, 0},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			d := newBookmarkDetector()
+			got := 0
+			for start := 0; start < len(tt.stream); start += tt.chunkSize {
+				end := start + tt.chunkSize
+				if end > len(tt.stream) {
+					end = len(tt.stream)
+				}
+				got += d.Detect([]byte(tt.stream[start:end]))
+			}
+			if got != tt.want {
+				t.Errorf("bookmarkDetector.Detect() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
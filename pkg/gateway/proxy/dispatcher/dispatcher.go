@@ -16,13 +16,16 @@ package dispatcher
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/gobeam/stringy"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apimachinery/pkg/util/httpstream"
@@ -35,9 +38,12 @@ import (
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/klog"
 
+	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
 	"github.com/kubewharf/kubegateway/pkg/clusters"
 	"github.com/kubewharf/kubegateway/pkg/clusters/features"
+	gatewayflowcontrol "github.com/kubewharf/kubegateway/pkg/flowcontrol"
 	"github.com/kubewharf/kubegateway/pkg/gateway/endpoints/request"
+	"github.com/kubewharf/kubegateway/pkg/gateway/metrics"
 	"github.com/kubewharf/kubegateway/pkg/gateway/net"
 )
 
@@ -47,15 +53,54 @@ var (
 
 type dispatcher struct {
 	clusters.Manager
-	codecs          serializer.CodecFactory
-	enableAccessLog bool
+	codecs                 serializer.CodecFactory
+	enableAccessLog        bool
+	accessLogFields        AccessLogFields
+	accessLogFormat        AccessLogFormat
+	malformedRequestPolicy MalformedRequestPolicy
+	longRunningFunc        genericapirequest.LongRunningRequestCheck
+	// request bodies up to this size are buffered to be replayed on retry
+	maxReplayableBodyBytes int64
+	flowControlAuditPolicy FlowControlAuditPolicy
+	// listAffinity dispatches watches to the endpoint which responds the list they start from
+	listAffinity     bool
+	watchAbortPolicy WatchAbortPolicy
+	// watches are closed cleanly once they last for maxWatchDuration, zero means no limit
+	maxWatchDuration time.Duration
 }
 
-func NewDispatcher(clusterManager clusters.Manager, enableAccessLog bool) http.Handler {
+// DispatcherConfig configures how a dispatcher proxies requests to upstream clusters
+// nolint:revive
+type DispatcherConfig struct {
+	EnableAccessLog        bool
+	AccessLogFields        AccessLogFields
+	AccessLogFormat        AccessLogFormat
+	MalformedRequestPolicy MalformedRequestPolicy
+	LongRunningFunc        genericapirequest.LongRunningRequestCheck
+	// request bodies up to this size are buffered to be replayed on retry
+	MaxReplayableBodyBytes int64
+	FlowControlAuditPolicy FlowControlAuditPolicy
+	// ListAffinity dispatches watches to the endpoint which responds the list they start from
+	ListAffinity     bool
+	WatchAbortPolicy WatchAbortPolicy
+	// watches are closed cleanly once they last for MaxWatchDuration, zero means no limit
+	MaxWatchDuration time.Duration
+}
+
+func NewDispatcher(clusterManager clusters.Manager, config DispatcherConfig) http.Handler {
 	return &dispatcher{
-		Manager:         clusterManager,
-		codecs:          scheme.Codecs,
-		enableAccessLog: enableAccessLog,
+		Manager:                clusterManager,
+		codecs:                 scheme.Codecs,
+		enableAccessLog:        config.EnableAccessLog,
+		accessLogFields:        config.AccessLogFields,
+		accessLogFormat:        config.AccessLogFormat,
+		malformedRequestPolicy: config.MalformedRequestPolicy,
+		longRunningFunc:        config.LongRunningFunc,
+		maxReplayableBodyBytes: config.MaxReplayableBodyBytes,
+		flowControlAuditPolicy: config.FlowControlAuditPolicy,
+		listAffinity:           config.ListAffinity,
+		watchAbortPolicy:       config.WatchAbortPolicy,
+		maxWatchDuration:       config.MaxWatchDuration,
 	}
 }
 
@@ -93,35 +138,110 @@ func (d *dispatcher) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	if cluster.IsDisabled() {
+		d.responseError(errors.NewServiceUnavailable(fmt.Sprintf("cluster(%s) is disabled", extraInfo.Hostname)), w, req, statusReasonClusterDisabled)
+		return
+	}
+
+	if stub := cluster.StubConfig(); stub != nil {
+		d.responseError(newStubClusterError(extraInfo.Hostname, stub), w, req, statusReasonClusterProvisioning)
+		return
+	}
+
+	if err := cluster.WaitForAdmission(ctx); err != nil {
+		// requests admitted before pausing are not affected, clients are expected to retry shortly
+		statusErr := errors.NewServiceUnavailable(fmt.Sprintf("cluster(%s) is paused: %v", extraInfo.Hostname, err))
+		statusErr.ErrStatus.Details = &metav1.StatusDetails{RetryAfterSeconds: 1}
+		d.responseError(statusErr, w, req, statusReasonClusterPaused)
+		return
+	}
+
 	requestAttributes, err := filters.GetAuthorizerAttributes(ctx)
 	if err != nil {
 		d.responseError(errors.NewInternalError(err), w, req, statusReasonInvalidRequestContext)
 		return
 	}
-	endpointPicker, err := cluster.MatchAttributes(requestAttributes)
+	if isMalformedRequestInfo(requestInfo) {
+		if d.malformedRequestPolicy == MalformedRequestPolicyReject {
+			d.responseError(errors.NewBadRequest(fmt.Sprintf("malformed request path %q", requestInfo.Path)), w, req, statusReasonMalformedRequest)
+			return
+		}
+		requestAttributes = nonResourceAttributes(requestAttributes, req.Method)
+	}
+	if !cluster.ServesAPIResource(requestAttributes) {
+		// reject early instead of getting an upstream error for resources the cluster does not serve
+		d.responseError(newResourceNotServedError(extraInfo.Hostname, requestInfo), w, req, statusReasonResourceNotServed)
+		return
+	}
+	if cluster.HidesResource(requestAttributes) {
+		d.responseError(newResourceHiddenError(), w, req, statusReasonResourceHidden)
+		return
+	}
+	endpointPicker, err := cluster.MatchRequest(requestAttributes, req.UserAgent())
 	if err != nil {
 		d.responseError(errors.NewInternalError(err), w, req, normalizeErrToReason(err))
 		return
 	}
+	if ready, min, ok := endpointPicker.HasMinHealthyEndpoints(); !ok {
+		d.responseError(errors.NewServiceUnavailable(fmt.Sprintf("cluster(%s) has %d healthy endpoints, less than the required %d", extraInfo.Hostname, ready, min)), w, req, statusReasonNotEnoughHealthyEndpoints)
+		return
+	}
 
 	flowcontrol := endpointPicker.FlowControl()
-	if !flowcontrol.TryAcquire() {
+	// routedEndpoint is the endpoint the request is finally proxied to, it changes on retries
+	var routedEndpoint string
+	defer func() {
+		auditRoutingDecision(ctx, extraInfo.Hostname, endpointPicker.PolicyName(), routedEndpoint, flowcontrol.Name())
+	}()
+	flow := gatewayflowcontrol.Flow{User: user.GetName(), Groups: user.GetGroups()}
+	if extraInfo.ClientIP != nil {
+		flow.SourceIP = extraInfo.ClientIP.String()
+	}
+	acquired := gatewayflowcontrol.Acquire(ctx, flowcontrol, flow)
+	metrics.RecordFlowControlRequest(extraInfo.Hostname, flowcontrol.Name(), string(flowcontrol.Type()), acquired)
+	auditFlowControlDecision(ctx, d.flowControlAuditPolicy, flowcontrol, acquired)
+	if !acquired {
 		//TODO: exempt master request and long running request
 		// add metrics
-		d.responseError(errors.NewTooManyRequests(fmt.Sprintf("too many requests for cluster(%s), limited by flowControl(%v)", extraInfo.Hostname, flowcontrol.String()), retryAfter), w, req, statusReasonRateLimited)
+		message := fmt.Sprintf("too many requests for cluster(%s), limited by flowControl(%v)", extraInfo.Hostname, flowcontrol.String())
+		if response := endpointPicker.FlowControlRejectionResponse(); response != nil {
+			if len(response.Message) > 0 {
+				message = response.Message
+			}
+			for name, value := range response.Headers {
+				w.Header().Set(name, value)
+			}
+		}
+		d.responseError(newFlowControlRejectedError(endpointPicker.FlowControlRejectionStatusCode(), message), w, req, statusReasonRateLimited)
 		return
 	}
 	defer flowcontrol.Release()
 
-	endpoint, err := endpointPicker.Pop()
+	inflightVerb := metrics.InflightVerbCategory(req, requestInfo)
+	metrics.RecordInflightRequestStarted(extraInfo.Hostname, inflightVerb)
+	defer metrics.RecordInflightRequestFinished(extraInfo.Hostname, inflightVerb)
+
+	endpoint, err := endpointPicker.PopPreferred(d.preferredEndpoint(cluster, req, requestInfo))
 	if err != nil {
 		d.responseError(errors.NewServiceUnavailable(err.Error()), w, req, statusReasonNoReadyEndpoints)
 		return
 	}
+	routedEndpoint = endpoint.Endpoint
+	// count the request as in-flight on the endpoint it is dispatched to, it is deferred so that
+	// the counter does not leak even if proxying panics
+	requestDone := endpoint.TrackRequest()
+	defer func() {
+		requestDone()
+	}()
 
 	transport := endpoint.ProxyTransport
 	if httpstream.IsUpgradeRequest(req) {
 		transport = endpoint.PorxyUpgradeTransport
+	} else if d.maxReplayableBodyBytes > 0 {
+		if err := bufferRequestBody(req, d.maxReplayableBodyBytes); err != nil {
+			d.responseError(errors.NewBadRequest(fmt.Sprintf("failed to read request body: %v", err)), w, req, statusReasonInvalidRequestBody)
+			return
+		}
 	}
 
 	ep, err := url.Parse(endpoint.Endpoint)
@@ -142,37 +262,148 @@ func (d *dispatcher) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	location.Path = req.URL.Path
 	location.RawQuery = req.URL.Query().Encode()
 
-	newReq, cancel := newRequestForProxy(location, req, extraInfo.Hostname)
-	// close this request if endpoint is stoped
-	go func() {
-		select {
-		case <-newReq.Context().Done():
-			// this context comes from incoming server requests, and then we use
-			// it as proxy client context to control cancellation
-			//
-			// For incoming server requests, the context is canceled when the
-			// client's connection closes, the request is canceled (with HTTP/2),
-			// or when the ServeHTTP method returns.
-		case <-endpoint.Context().Done():
-			// when endpoint stopping, we should cancel the context to close proxy request
-			cancel()
-		}
-	}()
+	longRunning := d.longRunningFunc != nil && d.longRunningFunc(req, requestInfo)
+	newReq, cancel := newRequestForProxy(location, req, longRunning)
+	// close this request if the endpoint it is routed to is stopped
+	reroute := cancelWhenEndpointStopped(newReq.Context(), cancel, endpoint.Context())
 
 	logging := d.enableAccessLog && endpointPicker.EnableLog()
-	delegate := decorateResponseWriter(req, w, logging, requestInfo, extraInfo.Hostname, endpoint.Endpoint, user, extraInfo.Impersonator)
+	logFields := d.accessLogFields.withUserGroup(cluster.UserGroupsLogMode())
+	delegate := decorateResponseWriter(req, w, logging, logFields, d.accessLogFormat, requestInfo, extraInfo.Hostname, endpoint.Endpoint, endpointPicker.PolicyName(), user, extraInfo.Impersonator)
 	delegate.MonitorBeforeProxy()
 	defer delegate.MonitorAfterProxy()
 
+	if d.listAffinity && requestInfo.IsResourceRequest && requestInfo.Verb == "list" {
+		delegate.listHead = &listHead{}
+		defer func() {
+			if delegate.status != http.StatusOK {
+				return
+			}
+			if rv, ok := delegate.listHead.ResourceVersion(w.Header().Get("Content-Encoding")); ok {
+				cluster.RecordListEndpoint(requestInfo, rv, delegate.endpoint)
+			}
+		}()
+	}
+
+	if primaryCh := d.startShadow(req, cluster, requestInfo, user); primaryCh != nil {
+		delegate.bodyHash = sha256.New()
+		defer func() {
+			primaryCh <- delegate.digest()
+		}()
+	}
+
+	if retry := endpointPicker.RetryPolicy(); retry != nil && transport == endpoint.ProxyTransport && isRetriableRequest(req, requestInfo, retry) {
+		transport = &retryTransport{
+			cluster:   extraInfo.Hostname,
+			policy:    retry,
+			picker:    endpointPicker,
+			decoder:   d.codecs.UniversalDeserializer(),
+			transport: transport,
+			endpoint:  endpoint.Endpoint,
+			onRetry: func(next *clusters.EndpointInfo) {
+				runtime.Must(request.SetProxyForwarded(req.Context(), next.Endpoint))
+				delegate.switchEndpoint(next.Endpoint)
+				routedEndpoint = next.Endpoint
+				requestDone()
+				requestDone = next.TrackRequest()
+				reroute(next.Context())
+			},
+		}
+	}
+
+	var watchTransport *watchAbortTransport
+	if requestInfo.IsResourceRequest && requestInfo.Verb == "watch" && transport != endpoint.PorxyUpgradeTransport {
+		watchTransport = &watchAbortTransport{
+			cluster:   extraInfo.Hostname,
+			resource:  requestInfo.Resource,
+			policy:    d.watchAbortPolicy,
+			codecs:    d.codecs,
+			clientCtx: ctx,
+			transport: transport,
+		}
+		transport = watchTransport
+		if d.maxWatchDuration > 0 {
+			// cancel the upstream watch once it lasts for the max duration, the response is closed
+			// cleanly and the client reconnects, so that watches are rebalanced among gateways
+			timer := time.AfterFunc(jitterWatchDuration(d.maxWatchDuration), func() {
+				watchTransport.expire()
+				cancel()
+			})
+			defer timer.Stop()
+		}
+	}
+
 	rw := responsewriter.WrapForHTTP1Or2(delegate)
 
 	proxyHandler := NewUpgradeAwareHandler(location, transport, false, false, d)
+	proxyStart := time.Now()
 	proxyHandler.ServeHTTP(rw, newReq)
+	if watchTransport != nil && delegate.status == http.StatusOK {
+		metrics.RecordWatchDuration(extraInfo.Hostname, requestInfo.Resource, time.Since(proxyStart))
+	}
+
+	// the context of server request is canceled when the client's connection closes or the
+	// request is canceled with HTTP/2, before the handler returns. Long-running requests
+	// always end in this way, so they are ignored.
+	if !longRunning && ctx.Err() == context.Canceled {
+		metrics.RecordDownstreamDisconnect(extraInfo.Hostname, requestInfo)
+	}
+}
+
+// cancelWhenEndpointStopped calls cancel once endpointCtx is done, i.e. the endpoint which the
+// request is routed to is stopped. It returns a func to watch the context of another endpoint
+// instead when the request is retried on it. Watching stops once ctx is done, the context comes
+// from the incoming server request, it is canceled when the client's connection closes, the
+// request is canceled (with HTTP/2), or when the ServeHTTP method returns.
+func cancelWhenEndpointStopped(ctx context.Context, cancel context.CancelFunc, endpointCtx context.Context) func(context.Context) {
+	rerouted := make(chan context.Context)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case endpointCtx = <-rerouted:
+			case <-endpointCtx.Done():
+				// the request may be rerouted right before the previous endpoint is stopped
+				select {
+				case endpointCtx = <-rerouted:
+					continue
+				default:
+				}
+				cancel()
+				return
+			}
+		}
+	}()
+	return func(next context.Context) {
+		select {
+		case rerouted <- next:
+		case <-ctx.Done():
+		}
+	}
+}
+
+// preferredEndpoint returns the endpoint which responds the list that a watch starts from, the cache of
+// the endpoint is at least as fresh as the resourceVersion of watch, and watching a lagging endpoint
+// fails with "too old resource version". It returns empty string for other requests.
+func (d *dispatcher) preferredEndpoint(cluster *clusters.ClusterInfo, req *http.Request, requestInfo *genericapirequest.RequestInfo) string {
+	if !d.listAffinity || !requestInfo.IsResourceRequest || requestInfo.Verb != "watch" {
+		return ""
+	}
+	rv := req.URL.Query().Get("resourceVersion")
+	if len(rv) == 0 || rv == "0" {
+		// watches from any resourceVersion can be served by any endpoint
+		return ""
+	}
+	endpoint, _ := cluster.ListEndpoint(requestInfo, rv)
+	return endpoint
 }
 
 func (d *dispatcher) responseError(err *errors.StatusError, w http.ResponseWriter, req *http.Request, reason string) {
 	gv := schema.GroupVersion{Group: "", Version: "v1"}
-	if errors.IsTooManyRequests(err) {
+	if details := err.Status().Details; details != nil && details.RetryAfterSeconds > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(details.RetryAfterSeconds)))
+	} else if errors.IsTooManyRequests(err) {
 		w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
 	} else if errors.IsServiceUnavailable(err) {
 		w.Header().Set("Retry-After", strconv.Itoa(retryAfter*30))
@@ -194,10 +425,76 @@ func (d *dispatcher) responseError(err *errors.StatusError, w http.ResponseWrite
 	responsewriters.ErrorNegotiated(err, d.codecs, gv, w, req)
 }
 
-// newRequestForProxy returns a shallow copy of the original request with a context that may include a timeout for discovery requests
-func newRequestForProxy(location *url.URL, req *http.Request, _ string) (*http.Request, context.CancelFunc) {
+// newFlowControlRejectedError returns an error with the given status code for requests
+// rejected by flow control, Retry-After is always set no matter which code is used.
+func newFlowControlRejectedError(code int32, message string) *errors.StatusError {
+	if code != http.StatusServiceUnavailable {
+		return errors.NewTooManyRequests(message, retryAfter)
+	}
+	return &errors.StatusError{ErrStatus: metav1.Status{
+		Status:  metav1.StatusFailure,
+		Code:    http.StatusServiceUnavailable,
+		Reason:  metav1.StatusReasonServiceUnavailable,
+		Message: message,
+		Details: &metav1.StatusDetails{
+			RetryAfterSeconds: int32(retryAfter),
+		},
+	}}
+}
+
+// newStubClusterError returns the 503 error responded for all requests to a stub cluster
+func newStubClusterError(cluster string, stub *proxyv1alpha1.StubConfig) *errors.StatusError {
+	message := stub.Message
+	if len(message) == 0 {
+		message = fmt.Sprintf("cluster(%s) is being provisioned", cluster)
+	}
+	err := errors.NewServiceUnavailable(message)
+	if stub.RetryAfterSeconds > 0 {
+		err.ErrStatus.Details = &metav1.StatusDetails{RetryAfterSeconds: stub.RetryAfterSeconds}
+	}
+	return err
+}
+
+// newResourceNotServedError returns a 404 error like the one responded by kube-apiserver for an unknown resource
+func newResourceNotServedError(cluster string, requestInfo *genericapirequest.RequestInfo) *errors.StatusError {
+	err := errors.NewNotFound(schema.GroupResource{Group: requestInfo.APIGroup, Resource: requestInfo.Resource}, requestInfo.Name)
+	gv := schema.GroupVersion{Group: requestInfo.APIGroup, Version: requestInfo.APIVersion}
+	err.ErrStatus.Message = fmt.Sprintf("the server could not find the requested resource, %s of %s is not served by cluster(%s)", requestInfo.Resource, gv.String(), cluster)
+	return err
+}
+
+// newResourceHiddenError returns the same 404 error as kube-apiserver responds for a request to
+// an unknown resource, it must not tell that the resource is hidden by the gateway.
+func newResourceHiddenError() *errors.StatusError {
+	return &errors.StatusError{ErrStatus: metav1.Status{
+		Status:  metav1.StatusFailure,
+		Code:    http.StatusNotFound,
+		Reason:  metav1.StatusReasonNotFound,
+		Message: "the server could not find the requested resource",
+	}}
+}
+
+// newRequestForProxy returns a shallow copy of the original request with a context that may include
+// a timeout for non long-running requests.
+//
+// The deadline is taken from the client's timeout query parameter or from the incoming context,
+// so that the proxy request is aborted once the client has given up. If the deadline only comes
+// from the context, it is also propagated to upstream as the timeout query parameter, and the
+// upstream can stop working on it as well.
+func newRequestForProxy(location *url.URL, req *http.Request, longRunning bool) (*http.Request, context.CancelFunc) {
 	ctx := req.Context()
-	newCtx, cancel := context.WithCancel(ctx)
+	timeout, hasTimeout := time.Duration(0), false
+	if !longRunning {
+		timeout, hasTimeout = requestTimeout(location, ctx)
+	}
+
+	var newCtx context.Context
+	var cancel context.CancelFunc
+	if hasTimeout {
+		newCtx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		newCtx, cancel = context.WithCancel(ctx)
+	}
 
 	// WithContext creates a shallow clone of the request with the same context.
 	newReq := req.WithContext(newCtx)
@@ -207,8 +504,41 @@ func newRequestForProxy(location *url.URL, req *http.Request, _ string) (*http.R
 	return newReq, cancel
 }
 
+// requestTimeout returns the timeout of request, the timeout query parameter of
+// location will be set if the timeout only comes from deadline of ctx.
+func requestTimeout(location *url.URL, ctx context.Context) (time.Duration, bool) {
+	query := location.Query()
+	if timeoutStr := query.Get("timeout"); len(timeoutStr) > 0 {
+		timeout, err := time.ParseDuration(timeoutStr)
+		if err != nil || timeout <= 0 {
+			// let upstream reject the invalid timeout
+			return 0, false
+		}
+		return timeout, true
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return 0, false
+	}
+	timeout := time.Until(deadline).Truncate(time.Millisecond)
+	if timeout <= 0 {
+		return 0, false
+	}
+	query.Set("timeout", timeout.String())
+	location.RawQuery = query.Encode()
+	return timeout, true
+}
+
 // implements k8s.io/apimachinery/pkg/util/proxy.ErrorResponder interface
 func (d *dispatcher) Error(w http.ResponseWriter, req *http.Request, err error) {
+	if extraInfo, ok := request.ExtraReqeustInfoFrom(req.Context()); ok {
+		var endpoint string
+		if proxyInfo, ok := request.ExtraProxyInfoFrom(req.Context()); ok {
+			endpoint = proxyInfo.Endpoint
+		}
+		clusters.RecordTLSVerificationError(extraInfo.Hostname, endpoint, "proxy", err)
+	}
 	status := errorToProxyStatus(err)
 	reason := statusReasonUpgradeAwareHandlerError
 	if status.Code == http.StatusBadGateway {
@@ -0,0 +1,236 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dispatcher
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/kubernetes/scheme"
+
+	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
+	"github.com/kubewharf/kubegateway/pkg/gateway/endpoints/request"
+)
+
+func Test_newRequestForProxy(t *testing.T) {
+	tests := []struct {
+		name         string
+		rawQuery     string
+		ctxTimeout   time.Duration
+		longRunning  bool
+		wantDeadline bool
+		wantTimeout  string
+	}{
+		{
+			name:         "no timeout",
+			rawQuery:     "",
+			wantDeadline: false,
+			wantTimeout:  "",
+		},
+		{
+			name:         "timeout from query",
+			rawQuery:     "timeout=10s",
+			wantDeadline: true,
+			wantTimeout:  "10s",
+		},
+		{
+			name:         "invalid timeout from query",
+			rawQuery:     "timeout=abc",
+			wantDeadline: false,
+			wantTimeout:  "abc",
+		},
+		{
+			name:         "timeout from context",
+			ctxTimeout:   time.Minute,
+			wantDeadline: true,
+			wantTimeout:  "59.",
+		},
+		{
+			name:         "long running request",
+			rawQuery:     "timeout=10s&watch=true",
+			longRunning:  true,
+			wantDeadline: false,
+			wantTimeout:  "10s",
+		},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.ctxTimeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.ctxTimeout)
+				defer cancel()
+			}
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://127.0.0.1/api/v1/pods?"+tt.rawQuery, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			location := &url.URL{Scheme: "https", Host: "upstream", Path: req.URL.Path, RawQuery: req.URL.Query().Encode()}
+
+			newReq, cancel := newRequestForProxy(location, req, tt.longRunning)
+			defer cancel()
+
+			if _, ok := newReq.Context().Deadline(); ok != tt.wantDeadline {
+				t.Errorf("newRequestForProxy() deadline = %v, want %v", ok, tt.wantDeadline)
+			}
+			got := newReq.URL.Query().Get("timeout")
+			if (len(tt.wantTimeout) == 0 && len(got) > 0) || !strings.HasPrefix(got, tt.wantTimeout) {
+				t.Errorf("newRequestForProxy() timeout = %q, want prefix %q", got, tt.wantTimeout)
+			}
+		})
+	}
+}
+
+func Test_dispatcher_responseFlowControlRejectedError(t *testing.T) {
+	tests := []struct {
+		name           string
+		code           int32
+		wantCode       int
+		wantRetryAfter string
+	}{
+		{"default", 0, http.StatusTooManyRequests, "1"},
+		{"too many requests", http.StatusTooManyRequests, http.StatusTooManyRequests, "1"},
+		{"service unavailable", http.StatusServiceUnavailable, http.StatusServiceUnavailable, "1"},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			d := &dispatcher{codecs: scheme.Codecs}
+			req := httptest.NewRequest(http.MethodGet, "https://127.0.0.1/api/v1/pods", nil)
+			req = req.WithContext(request.WithProxyInfo(req.Context(), request.NewProxyInfo()))
+			w := httptest.NewRecorder()
+
+			d.responseError(newFlowControlRejectedError(tt.code, "rejected"), w, req, statusReasonRateLimited)
+
+			if w.Code != tt.wantCode {
+				t.Errorf("responseError() code = %v, want %v", w.Code, tt.wantCode)
+			}
+			if got := w.Header().Get("Retry-After"); got != tt.wantRetryAfter {
+				t.Errorf("responseError() Retry-After = %v, want %v", got, tt.wantRetryAfter)
+			}
+		})
+	}
+}
+
+func Test_dispatcher_responseStubClusterError(t *testing.T) {
+	tests := []struct {
+		name           string
+		stub           *proxyv1alpha1.StubConfig
+		wantMessage    string
+		wantRetryAfter string
+	}{
+		{"default", &proxyv1alpha1.StubConfig{}, "cluster(test.cluster) is being provisioned", "30"},
+		{"custom", &proxyv1alpha1.StubConfig{Message: "coming soon", RetryAfterSeconds: 60}, "coming soon", "60"},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			d := &dispatcher{codecs: scheme.Codecs}
+			req := httptest.NewRequest(http.MethodGet, "https://127.0.0.1/api/v1/pods", nil)
+			req = req.WithContext(request.WithProxyInfo(req.Context(), request.NewProxyInfo()))
+			w := httptest.NewRecorder()
+
+			d.responseError(newStubClusterError("test.cluster", tt.stub), w, req, statusReasonClusterProvisioning)
+
+			if w.Code != http.StatusServiceUnavailable {
+				t.Errorf("responseError() code = %v, want %v", w.Code, http.StatusServiceUnavailable)
+			}
+			if !strings.Contains(w.Body.String(), tt.wantMessage) {
+				t.Errorf("responseError() body = %v, want message %q", w.Body.String(), tt.wantMessage)
+			}
+			if got := w.Header().Get("Retry-After"); got != tt.wantRetryAfter {
+				t.Errorf("responseError() Retry-After = %v, want %v", got, tt.wantRetryAfter)
+			}
+		})
+	}
+}
+
+func Test_dispatcher_responseResourceHiddenError(t *testing.T) {
+	d := &dispatcher{codecs: scheme.Codecs}
+	req := httptest.NewRequest(http.MethodGet, "https://127.0.0.1/api/v1/secrets", nil)
+	req = req.WithContext(request.WithProxyInfo(req.Context(), request.NewProxyInfo()))
+	w := httptest.NewRecorder()
+
+	d.responseError(newResourceHiddenError(), w, req, statusReasonResourceHidden)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("responseError() code = %v, want %v", w.Code, http.StatusNotFound)
+	}
+	// the response must be the same as the one of an unknown resource
+	if !strings.Contains(w.Body.String(), `"message":"the server could not find the requested resource"`) {
+		t.Errorf("responseError() body = %v, want message of unknown resource", w.Body.String())
+	}
+	if strings.Contains(w.Body.String(), "secrets") {
+		t.Errorf("responseError() body = %v, should not contain the hidden resource", w.Body.String())
+	}
+}
+
+func Test_cancelWhenEndpointStopped(t *testing.T) {
+	canceled := func(ctx context.Context) bool {
+		select {
+		case <-ctx.Done():
+			return true
+		case <-time.After(100 * time.Millisecond):
+			return false
+		}
+	}
+	tests := []struct {
+		name string
+		// reroute moves the request to the second endpoint before endpoints are stopped
+		reroute    bool
+		stopFirst  bool
+		stopSecond bool
+		want       bool
+	}{
+		{"endpoint is stopped", false, true, false, true},
+		{"endpoint is not stopped", false, false, false, false},
+		{"previous endpoint is stopped after rerouting", true, true, false, false},
+		{"rerouted endpoint is stopped", true, false, true, true},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			first, stopFirst := context.WithCancel(context.Background())
+			defer stopFirst()
+			second, stopSecond := context.WithCancel(context.Background())
+			defer stopSecond()
+
+			reroute := cancelWhenEndpointStopped(ctx, cancel, first)
+			if tt.reroute {
+				reroute(second)
+			}
+			if tt.stopFirst {
+				stopFirst()
+			}
+			if tt.stopSecond {
+				stopSecond()
+			}
+			if got := canceled(ctx); got != tt.want {
+				t.Errorf("request canceled = %v, want %v", got, tt.want)
+			}
+			// rerouting never blocks after the request is done
+			cancel()
+			reroute(second)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dispatcher
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// envoyAccessLogWriter is where access logs in envoy format are written to,
+// envoy writes access logs to /dev/stdout by default.
+var envoyAccessLogWriter io.Writer = os.Stdout
+
+// envoyTimeFormat is the format of %START_TIME% in envoy default access log
+const envoyTimeFormat = "2006-01-02T15:04:05.000Z"
+
+// logEnvoy writes access log in the layout of envoy's default format:
+//
+//	[%START_TIME%] "%REQ(:METHOD)% %REQ(X-ENVOY-ORIGINAL-PATH?:PATH)% %PROTOCOL%"
+//	%RESPONSE_CODE% %RESPONSE_FLAGS% %BYTES_RECEIVED% %BYTES_SENT% %DURATION%
+//	%RESP(X-ENVOY-UPSTREAM-SERVICE-TIME)% "%REQ(X-FORWARDED-FOR)%" "%REQ(USER-AGENT)%"
+//	"%REQ(X-REQUEST-ID)%" "%REQ(:AUTHORITY)%" "%UPSTREAM_HOST%"
+//
+// Response flags and upstream service time are not tracked by gateway, they are
+// always "-" like envoy does for unavailable values.
+func (rw *responseWriterDelegator) logEnvoy(latency time.Duration) {
+	var received int64
+	if rw.req.ContentLength > 0 {
+		received = rw.req.ContentLength
+	}
+	line := fmt.Sprintf("[%s] \"%s %s %s\" %d - %d %d %d - \"%s\" \"%s\" \"%s\" \"%s\" \"%s\"\n",
+		rw.startTime.UTC().Format(envoyTimeFormat),
+		rw.req.Method,
+		rw.req.RequestURI,
+		rw.req.Proto,
+		rw.status,
+		received,
+		rw.written,
+		latency.Milliseconds(),
+		envoyHeaderValue(rw.req.Header.Get("X-Forwarded-For")),
+		envoyHeaderValue(rw.req.UserAgent()),
+		envoyHeaderValue(rw.req.Header.Get("X-Request-Id")),
+		envoyHeaderValue(rw.req.Host),
+		envoyUpstreamHost(rw.endpoint),
+	)
+	io.WriteString(envoyAccessLogWriter, line) //nolint:errcheck
+}
+
+// envoyHeaderValue returns "-" for an empty value as envoy does, double quotes
+// are escaped to keep the line parseable.
+func envoyHeaderValue(value string) string {
+	if len(value) == 0 {
+		return "-"
+	}
+	return strings.ReplaceAll(value, `"`, `\"`)
+}
+
+// envoyUpstreamHost returns host:port of the upstream endpoint
+func envoyUpstreamHost(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil || len(u.Host) == 0 {
+		return envoyHeaderValue(endpoint)
+	}
+	return u.Host
+}
@@ -0,0 +1,77 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dispatcher
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResponseWriterDelegator_logEnvoy(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   http.Header
+		endpoint string
+		want     string
+	}{
+		{
+			name:     "missing headers",
+			endpoint: "https://10.0.0.1:6443",
+			want:     `[2022-03-04T05:06:07.089Z] "POST /api/v1/namespaces/default/pods HTTP/1.1" 201 - 2 5 1500 - "-" "-" "-" "a.cluster" "10.0.0.1:6443"` + "\n",
+		},
+		{
+			name: "with headers",
+			header: http.Header{
+				"X-Forwarded-For": []string{"10.0.0.2, 10.0.0.3"},
+				"User-Agent":      []string{`kubectl "v1.18"`},
+				"X-Request-Id":    []string{"abc"},
+			},
+			endpoint: "https://10.0.0.1:6443",
+			want:     `[2022-03-04T05:06:07.089Z] "POST /api/v1/namespaces/default/pods HTTP/1.1" 201 - 2 5 1500 - "10.0.0.2, 10.0.0.3" "kubectl \"v1.18\"" "abc" "a.cluster" "10.0.0.1:6443"` + "\n",
+		},
+	}
+	defer func(w io.Writer) {
+		envoyAccessLogWriter = w
+	}(envoyAccessLogWriter)
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			envoyAccessLogWriter = &buf
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/namespaces/default/pods", strings.NewReader("{}"))
+			req.Host = "a.cluster"
+			for k, v := range tt.header {
+				req.Header[k] = v
+			}
+			rw := &responseWriterDelegator{
+				req:       req,
+				startTime: time.Date(2022, 3, 4, 5, 6, 7, 89e6, time.UTC),
+				status:    http.StatusCreated,
+				written:   5,
+				endpoint:  tt.endpoint,
+			}
+			rw.logEnvoy(1500 * time.Millisecond)
+			if got := buf.String(); got != tt.want {
+				t.Errorf("logEnvoy() =\n%v\nwant\n%v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dispatcher
+
+import (
+	"net/http"
+	"net/textproto"
+	"strings"
+)
+
+// hopByHopHeaders are headers meaningful only for a single transport-level connection,
+// they must not be forwarded by proxies, see RFC 7230 section 6.1.
+var hopByHopHeaders = []string{
+	"Connection",
+	"Keep-Alive",
+	"Proxy-Connection",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+// removeHopByHopHeaders removes hop-by-hop headers of a non-upgrade request before it is
+// forwarded to upstream, including headers listed in Connection and all Proxy-* headers.
+// "Te: trailers" is kept as it is end-to-end. Upgrade requests are proxied as they are,
+// their Connection and Upgrade headers are required to switch protocols.
+func removeHopByHopHeaders(header http.Header) {
+	for _, value := range header["Connection"] {
+		for _, name := range strings.Split(value, ",") {
+			if name = textproto.TrimString(name); len(name) > 0 {
+				header.Del(name)
+			}
+		}
+	}
+
+	trailers := false
+	for _, value := range header["Te"] {
+		for _, coding := range strings.Split(value, ",") {
+			if strings.EqualFold(textproto.TrimString(coding), "trailers") {
+				trailers = true
+			}
+		}
+	}
+
+	for _, name := range hopByHopHeaders {
+		header.Del(name)
+	}
+	for name := range header {
+		if strings.HasPrefix(name, "Proxy-") {
+			delete(header, name)
+		}
+	}
+
+	if trailers {
+		header.Set("Te", "trailers")
+	}
+}
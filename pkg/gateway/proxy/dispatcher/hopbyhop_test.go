@@ -0,0 +1,129 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dispatcher
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func Test_removeHopByHopHeaders(t *testing.T) {
+	tests := []struct {
+		name   string
+		header http.Header
+		want   http.Header
+	}{
+		{
+			name: "end-to-end headers are kept",
+			header: http.Header{
+				"Accept":        []string{"application/json"},
+				"Authorization": []string{"Bearer token"},
+			},
+			want: http.Header{
+				"Accept":        []string{"application/json"},
+				"Authorization": []string{"Bearer token"},
+			},
+		},
+		{
+			name: "hop-by-hop headers are removed",
+			header: http.Header{
+				"Accept":            []string{"application/json"},
+				"Connection":        []string{"keep-alive"},
+				"Keep-Alive":        []string{"timeout=5"},
+				"Te":                []string{"gzip"},
+				"Trailer":           []string{"Expires"},
+				"Transfer-Encoding": []string{"chunked"},
+				"Upgrade":           []string{"SPDY/3.1"},
+			},
+			want: http.Header{
+				"Accept": []string{"application/json"},
+			},
+		},
+		{
+			name: "headers listed in connection are removed",
+			header: http.Header{
+				"Accept":     []string{"application/json"},
+				"Connection": []string{"X-Foo, x-bar", "X-Baz"},
+				"X-Foo":      []string{"foo"},
+				"X-Bar":      []string{"bar"},
+				"X-Baz":      []string{"baz"},
+			},
+			want: http.Header{
+				"Accept": []string{"application/json"},
+			},
+		},
+		{
+			name: "proxy headers are removed",
+			header: http.Header{
+				"Accept":              []string{"application/json"},
+				"Proxy-Authorization": []string{"Basic Zm9vOmJhcg=="},
+				"Proxy-Connection":    []string{"keep-alive"},
+				"Proxy-Foo":           []string{"foo"},
+			},
+			want: http.Header{
+				"Accept": []string{"application/json"},
+			},
+		},
+		{
+			name: "te trailers is kept",
+			header: http.Header{
+				"Te": []string{"gzip, Trailers"},
+			},
+			want: http.Header{
+				"Te": []string{"trailers"},
+			},
+		},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			removeHopByHopHeaders(tt.header)
+			if !reflect.DeepEqual(tt.header, tt.want) {
+				t.Errorf("removeHopByHopHeaders() = %v, want %v", tt.header, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpgradeAwareHandler_hopByHopHeaders(t *testing.T) {
+	var got http.Header
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		got = req.Header.Clone()
+	}))
+	defer upstream.Close()
+
+	location, _ := url.Parse(upstream.URL + "/api")
+	handler := NewUpgradeAwareHandler(location, http.DefaultTransport, false, false, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api", nil)
+	req.Header.Set("Connection", "X-Foo")
+	req.Header.Set("X-Foo", "foo")
+	req.Header.Set("Upgrade", "SPDY/3.1")
+	req.Header.Set("Proxy-Foo", "foo")
+	req.Header.Set("X-Bar", "bar")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	for _, name := range []string{"X-Foo", "Upgrade", "Proxy-Foo"} {
+		if _, ok := got[name]; ok {
+			t.Errorf("header %q is forwarded to upstream", name)
+		}
+	}
+	if got.Get("X-Bar") != "bar" {
+		t.Errorf("header X-Bar is not forwarded to upstream")
+	}
+}
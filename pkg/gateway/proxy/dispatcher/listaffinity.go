@@ -0,0 +1,163 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dispatcher
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+
+	"github.com/gogo/protobuf/proto"
+)
+
+const (
+	// listHeadBytes is the size of the head of list responses which is kept to find the resourceVersion,
+	// list metadata is encoded before items
+	listHeadBytes = 1024
+	// maxDecompressedListHeadBytes limits the size of decompressed head of gzipped list responses
+	maxDecompressedListHeadBytes = 4 * listHeadBytes
+)
+
+var (
+	// protobufMagic is the prefix of kubernetes protobuf encoded objects
+	protobufMagic = []byte{0x6b, 0x38, 0x73, 0x00}
+
+	jsonResourceVersionPrefix = []byte(`"resourceVersion":"`)
+	jsonItemsPrefix           = []byte(`"items":`)
+)
+
+// listHead keeps the head of a list response without buffering the whole response
+type listHead struct {
+	head []byte
+}
+
+func (l *listHead) Write(b []byte) {
+	if n := listHeadBytes - len(l.head); n > 0 {
+		if len(b) > n {
+			b = b[:n]
+		}
+		l.head = append(l.head, b...)
+	}
+}
+
+// ResourceVersion returns the resourceVersion in metadata of the list response, contentEncoding is the
+// Content-Encoding header of the response.
+func (l *listHead) ResourceVersion(contentEncoding string) (string, bool) {
+	head := l.head
+	if contentEncoding == "gzip" {
+		head = gunzipHead(head)
+	}
+	if bytes.HasPrefix(head, protobufMagic) {
+		return protobufListResourceVersion(head[len(protobufMagic):])
+	}
+	return jsonListResourceVersion(head)
+}
+
+// gunzipHead decompresses the head of a gzip stream as much as possible
+func gunzipHead(head []byte) []byte {
+	r, err := gzip.NewReader(bytes.NewReader(head))
+	if err != nil {
+		return nil
+	}
+	buf := make([]byte, maxDecompressedListHeadBytes)
+	n, _ := io.ReadFull(r, buf)
+	return buf[:n]
+}
+
+// jsonListResourceVersion finds metadata.resourceVersion of a json encoded list, the resourceVersion
+// must be found before items so that resourceVersion of an item is never taken.
+func jsonListResourceVersion(head []byte) (string, bool) {
+	start := bytes.Index(head, jsonResourceVersionPrefix)
+	if start < 0 {
+		return "", false
+	}
+	if items := bytes.Index(head, jsonItemsPrefix); items >= 0 && items < start {
+		return "", false
+	}
+	value := head[start+len(jsonResourceVersionPrefix):]
+	end := bytes.IndexByte(value, '"')
+	if end <= 0 {
+		return "", false
+	}
+	return string(value[:end]), true
+}
+
+// protobufListResourceVersion finds metadata.resourceVersion of a protobuf encoded list, data is
+// a runtime.Unknown whose raw is the list, and metadata is field 1 of lists.
+func protobufListResourceVersion(data []byte) (string, bool) {
+	// the list may be truncated
+	raw, _, ok := protobufField(data, 2)
+	if !ok {
+		return "", false
+	}
+	metadata, complete, ok := protobufField(raw, 1)
+	if !ok || !complete {
+		return "", false
+	}
+	rv, complete, ok := protobufField(metadata, 2)
+	if !ok || !complete || len(rv) == 0 {
+		return "", false
+	}
+	return string(rv), true
+}
+
+// protobufField returns the value of the first length-delimited field with the number in data,
+// complete is false if the value is truncated.
+func protobufField(data []byte, number uint64) (value []byte, complete bool, ok bool) {
+	for len(data) > 0 {
+		key, n := proto.DecodeVarint(data)
+		if n == 0 {
+			return nil, false, false
+		}
+		data = data[n:]
+		switch key & 0x7 {
+		case proto.WireVarint:
+			_, n := proto.DecodeVarint(data)
+			if n == 0 {
+				return nil, false, false
+			}
+			data = data[n:]
+		case proto.WireFixed64:
+			if len(data) < 8 {
+				return nil, false, false
+			}
+			data = data[8:]
+		case proto.WireFixed32:
+			if len(data) < 4 {
+				return nil, false, false
+			}
+			data = data[4:]
+		case proto.WireBytes:
+			length, n := proto.DecodeVarint(data)
+			if n == 0 {
+				return nil, false, false
+			}
+			data = data[n:]
+			if key>>3 == number {
+				if uint64(len(data)) < length {
+					return data, false, true
+				}
+				return data[:length], true, true
+			}
+			if uint64(len(data)) < length {
+				return nil, false, false
+			}
+			data = data[length:]
+		default:
+			return nil, false, false
+		}
+	}
+	return nil, false, false
+}
@@ -0,0 +1,99 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dispatcher
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer/json"
+	"k8s.io/apimachinery/pkg/runtime/serializer/protobuf"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+)
+
+func encodePodList(t *testing.T, encoder runtime.Encoder, resourceVersion string, pods int) []byte {
+	list := &corev1.PodList{
+		TypeMeta: metav1.TypeMeta{Kind: "PodList", APIVersion: "v1"},
+		ListMeta: metav1.ListMeta{ResourceVersion: resourceVersion},
+	}
+	for i := 0; i < pods; i++ {
+		list.Items = append(list.Items, corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("pod-%d", i), ResourceVersion: "1"}})
+	}
+	buf := &bytes.Buffer{}
+	if err := encoder.Encode(list, buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func gzipBytes(t *testing.T, data []byte) []byte {
+	buf := &bytes.Buffer{}
+	w := gzip.NewWriter(buf)
+	if _, err := w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func Test_listHead_ResourceVersion(t *testing.T) {
+	jsonEncoder := json.NewSerializer(json.DefaultMetaFactory, clientgoscheme.Scheme, clientgoscheme.Scheme, false)
+	protobufEncoder := protobuf.NewSerializer(clientgoscheme.Scheme, clientgoscheme.Scheme)
+	jsonList := encodePodList(t, jsonEncoder, "123", 100)
+	protobufList := encodePodList(t, protobufEncoder, "456", 100)
+
+	tests := []struct {
+		name            string
+		body            []byte
+		contentEncoding string
+		want            string
+		wantOK          bool
+	}{
+		{"json", jsonList, "", "123", true},
+		{"protobuf", protobufList, "", "456", true},
+		{"gzipped json", gzipBytes(t, jsonList), "gzip", "123", true},
+		{"gzipped protobuf", gzipBytes(t, protobufList), "gzip", "456", true},
+		{"json without list resourceVersion", encodePodList(t, jsonEncoder, "", 1), "", "", false},
+		{"protobuf without list resourceVersion", encodePodList(t, protobufEncoder, "", 1), "", "", false},
+		{"status", []byte(`{"kind":"Status","apiVersion":"v1","metadata":{},"status":"Failure"}`), "", "", false},
+		{"truncated", jsonList[:20], "", "", false},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			l := &listHead{}
+			// responses are written in chunks
+			for body := tt.body; len(body) > 0; {
+				n := 100
+				if n > len(body) {
+					n = len(body)
+				}
+				l.Write(body[:n])
+				body = body[n:]
+			}
+			got, ok := l.ResourceVersion(tt.contentEncoding)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("listHead.ResourceVersion() = (%v, %v), want (%v, %v)", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
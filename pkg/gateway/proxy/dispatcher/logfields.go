@@ -0,0 +1,114 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dispatcher
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
+)
+
+// AccessLogFormat is the layout of access log
+type AccessLogFormat string
+
+const (
+	// AccessLogFormatDefault logs the selected fields in key=value pairs through klog
+	AccessLogFormatDefault AccessLogFormat = "default"
+	// AccessLogFormatEnvoy logs in the layout of Envoy's default access log format to
+	// stdout, so that existing parsers for Envoy access logs work without modification.
+	// Selected fields are ignored in this format.
+	AccessLogFormatEnvoy AccessLogFormat = "envoy"
+)
+
+// fields that can be selected in access log
+const (
+	AccessLogFieldVerb         = "verb"
+	AccessLogFieldCluster      = "cluster"
+	AccessLogFieldEndpoint     = "endpoint"
+	AccessLogFieldURI          = "uri"
+	AccessLogFieldResource     = "resource"
+	AccessLogFieldNamespace    = "namespace"
+	AccessLogFieldLatency      = "latency"
+	AccessLogFieldStatus       = "status"
+	AccessLogFieldUser         = "user"
+	AccessLogFieldUserGroup    = "userGroup"
+	AccessLogFieldUserAgent    = "userAgent"
+	AccessLogFieldImpersonator = "impersonator"
+	AccessLogFieldSourceIP     = "sourceIP"
+	// AccessLogFieldResponseSize is the number of bytes written to the client
+	AccessLogFieldResponseSize = "responseSize"
+)
+
+var (
+	// allAccessLogFields is all known fields in the order they are logged
+	allAccessLogFields = []string{
+		AccessLogFieldVerb,
+		AccessLogFieldCluster,
+		AccessLogFieldEndpoint,
+		AccessLogFieldURI,
+		AccessLogFieldResource,
+		AccessLogFieldNamespace,
+		AccessLogFieldLatency,
+		AccessLogFieldStatus,
+		AccessLogFieldUser,
+		AccessLogFieldUserGroup,
+		AccessLogFieldUserAgent,
+		AccessLogFieldImpersonator,
+		AccessLogFieldSourceIP,
+		AccessLogFieldResponseSize,
+	}
+
+	// DefaultAccessLogFields is the fields logged if no field is included
+	DefaultAccessLogFields = sets.NewString(allAccessLogFields...).Delete(AccessLogFieldResource, AccessLogFieldNamespace, AccessLogFieldResponseSize).List()
+)
+
+// AllAccessLogFields returns all known access log fields
+func AllAccessLogFields() []string {
+	return append([]string{}, allAccessLogFields...)
+}
+
+// AccessLogFields is a set of fields that appear in access log
+type AccessLogFields struct {
+	sets.String
+}
+
+// NewAccessLogFields returns the included fields without the excluded fields,
+// all default fields are included if include is empty.
+func NewAccessLogFields(include, exclude []string) (AccessLogFields, error) {
+	known := sets.NewString(allAccessLogFields...)
+	for _, field := range append(append([]string{}, include...), exclude...) {
+		if !known.Has(field) {
+			return AccessLogFields{}, fmt.Errorf("unknown access log field %q, known fields are %v", field, allAccessLogFields)
+		}
+	}
+	if len(include) == 0 {
+		include = DefaultAccessLogFields
+	}
+	return AccessLogFields{sets.NewString(include...).Delete(exclude...)}, nil
+}
+
+// withUserGroup returns the fields with userGroup included or excluded according to
+// the log mode of cluster, the fields are not copied if they are not changed.
+func (f AccessLogFields) withUserGroup(mode proxyv1alpha1.LogMode) AccessLogFields {
+	switch {
+	case mode == proxyv1alpha1.LogOn && !f.Has(AccessLogFieldUserGroup):
+		return AccessLogFields{f.Union(sets.NewString(AccessLogFieldUserGroup))}
+	case mode == proxyv1alpha1.LogOff && f.Has(AccessLogFieldUserGroup):
+		return AccessLogFields{f.Difference(sets.NewString(AccessLogFieldUserGroup))}
+	}
+	return f
+}
@@ -0,0 +1,106 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dispatcher
+
+import (
+	"reflect"
+	"testing"
+
+	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
+)
+
+func TestNewAccessLogFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		include []string
+		exclude []string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "default",
+			want: []string{"cluster", "endpoint", "impersonator", "latency", "sourceIP", "status", "uri", "user", "userAgent", "userGroup", "verb"},
+		},
+		{
+			name:    "exclude from default",
+			exclude: []string{"userAgent", "userGroup", "impersonator", "uri"},
+			want:    []string{"cluster", "endpoint", "latency", "sourceIP", "status", "user", "verb"},
+		},
+		{
+			name:    "include",
+			include: []string{"cluster", "verb", "resource", "namespace", "status"},
+			want:    []string{"cluster", "namespace", "resource", "status", "verb"},
+		},
+		{
+			name:    "include response size",
+			include: []string{"cluster", "verb", "status", "responseSize"},
+			want:    []string{"cluster", "responseSize", "status", "verb"},
+		},
+		{
+			name:    "include and exclude",
+			include: []string{"cluster", "verb", "status"},
+			exclude: []string{"status"},
+			want:    []string{"cluster", "verb"},
+		},
+		{
+			name:    "unknown field",
+			include: []string{"cluster", "unknown"},
+			wantErr: true,
+		},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewAccessLogFields(tt.include, tt.exclude)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewAccessLogFields() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got.List(), tt.want) {
+				t.Errorf("NewAccessLogFields() = %v, want %v", got.List(), tt.want)
+			}
+		})
+	}
+}
+
+func TestAccessLogFields_withUserGroup(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields []string
+		mode   proxyv1alpha1.LogMode
+		want   []string
+	}{
+		{"unset follows fields", []string{"user", "userGroup"}, "", []string{"user", "userGroup"}},
+		{"on adds userGroup", []string{"user"}, proxyv1alpha1.LogOn, []string{"user", "userGroup"}},
+		{"off removes userGroup", []string{"user", "userGroup"}, proxyv1alpha1.LogOff, []string{"user"}},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			fields, err := NewAccessLogFields(tt.fields, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := fields.withUserGroup(tt.mode).List(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("withUserGroup() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(fields.List(), tt.fields) {
+				t.Errorf("withUserGroup() changes the original fields to %v", fields.List())
+			}
+		})
+	}
+}
@@ -16,6 +16,8 @@ package dispatcher
 
 import (
 	"fmt"
+	"hash"
+	"net"
 	"net/http"
 	"strings"
 	"time"
@@ -44,8 +46,11 @@ type responseWriterDelegator struct {
 	captureErrorOutput bool
 
 	logging      bool
+	logFields    AccessLogFields
+	logFormat    AccessLogFormat
 	host         string
 	endpoint     string
+	policy       string
 	user         user.Info
 	impersonator user.Info
 
@@ -54,27 +59,42 @@ type responseWriterDelegator struct {
 	w           http.ResponseWriter
 
 	written int64
+	// bookmarks is not nil for watch requests
+	bookmarks *bookmarkDetector
+	// bodyHash is not nil if the response needs to be compared with shadow response
+	bodyHash hash.Hash
+	// listHead is not nil if the resourceVersion of list response is tracked
+	listHead *listHead
 }
 
 func decorateResponseWriter(
 	req *http.Request,
 	w http.ResponseWriter,
 	logging bool,
+	logFields AccessLogFields,
+	logFormat AccessLogFormat,
 	requestInfo *request.RequestInfo,
-	host, endpoint string,
+	host, endpoint, policy string,
 	user, impersonator user.Info,
 ) *responseWriterDelegator {
-	return &responseWriterDelegator{
+	rw := &responseWriterDelegator{
 		startTime:    time.Now(),
 		req:          req,
 		w:            w,
 		logging:      logging,
+		logFields:    logFields,
+		logFormat:    logFormat,
 		requestInfo:  requestInfo,
 		host:         host,
 		endpoint:     endpoint,
+		policy:       policy,
 		user:         user,
 		impersonator: impersonator,
 	}
+	if rw.isWatch() {
+		rw.bookmarks = newBookmarkDetector()
+	}
+	return rw
 }
 
 func (rw *responseWriterDelegator) Unwrap() http.ResponseWriter {
@@ -102,9 +122,25 @@ func (rw *responseWriterDelegator) Write(b []byte) (int, error) {
 	}
 	n, err := rw.w.Write(b)
 	rw.written += int64(n)
+	if rw.bodyHash != nil {
+		rw.bodyHash.Write(b[:n])
+	}
+	if rw.listHead != nil {
+		rw.listHead.Write(b[:n])
+	}
+	if rw.bookmarks != nil {
+		if count := rw.bookmarks.Detect(b[:n]); count > 0 {
+			metrics.RecordWatchBookmarks(rw.host, rw.requestInfo.Resource, count)
+		}
+	}
 	return n, err
 }
 
+// digest returns the status code and body hash of the response, bodyHash must be set
+func (rw *responseWriterDelegator) digest() responseDigest {
+	return responseDigest{code: rw.status, hash: hashString(rw.bodyHash)}
+}
+
 func (rw *responseWriterDelegator) Status() int {
 	return rw.status
 }
@@ -129,6 +165,16 @@ func (rw *responseWriterDelegator) MonitorBeforeProxy() {
 	// TODO: add a metrics before request forwarded
 }
 
+// switchEndpoint is called when the request is retried on another endpoint before
+// any response is written.
+func (rw *responseWriterDelegator) switchEndpoint(endpoint string) {
+	if rw.isWatch() {
+		metrics.RecordWatcherUnregistered(rw.host, rw.endpoint, rw.requestInfo.Resource)
+		metrics.RecordWatcherRegistered(rw.host, endpoint, rw.requestInfo.Resource)
+	}
+	rw.endpoint = endpoint
+}
+
 func (rw *responseWriterDelegator) MonitorAfterProxy() {
 	if rw.isWatch() {
 		metrics.RecordWatcherUnregistered(rw.host, rw.endpoint, rw.requestInfo.Resource)
@@ -143,6 +189,7 @@ func (rw *responseWriterDelegator) MonitorAfterProxy() {
 		rw.req,
 		rw.host,
 		rw.endpoint,
+		rw.policy,
 		rw.requestInfo,
 		rw.Header().Get("Content-Type"),
 		rw.Status(),
@@ -162,39 +209,58 @@ func (rw *responseWriterDelegator) Log() {
 	if !logging {
 		return
 	}
-	sourceIPs := utilnet.SourceIPs(rw.req)
-	verb := strings.ToUpper(rw.requestInfo.Verb)
-	if rw.impersonator != nil {
-		klog.Infof("verb=%q host=%q endpoint=%q URI=%q latency=%v resp=%v user=%q userGroup=%v userAgent=%q impersonator=%q impersonatorGroup=%v srcIP=%v: %v",
-			verb,
-			rw.host,
-			rw.endpoint,
-			rw.req.RequestURI,
-			latency,
-			rw.status,
-			rw.user.GetName(),
-			rw.user.GetGroups(),
-			rw.req.UserAgent(),
-			rw.impersonator.GetName(),
-			rw.impersonator.GetGroups(),
-			sourceIPs,
-			rw.addedInfo,
-		)
-	} else {
-		klog.Infof("verb=%q host=%q endpoint=%q URI=%q latency=%v resp=%v user=%q userGroup=%v userAgent=%q srcIP=%v: %v",
-			verb,
-			rw.host,
-			rw.endpoint,
-			rw.req.RequestURI,
-			latency,
-			rw.status,
-			rw.user.GetName(),
-			rw.user.GetGroups(),
-			rw.req.UserAgent(),
-			sourceIPs,
-			rw.addedInfo,
-		)
+	if rw.logFormat == AccessLogFormatEnvoy {
+		rw.logEnvoy(latency)
+		return
+	}
+	var b strings.Builder
+	for _, field := range allAccessLogFields {
+		if !rw.logFields.Has(field) {
+			continue
+		}
+		switch field {
+		case AccessLogFieldVerb:
+			fmt.Fprintf(&b, "verb=%q ", strings.ToUpper(rw.requestInfo.Verb))
+		case AccessLogFieldCluster:
+			fmt.Fprintf(&b, "host=%q ", rw.host)
+		case AccessLogFieldEndpoint:
+			fmt.Fprintf(&b, "endpoint=%q ", rw.endpoint)
+		case AccessLogFieldURI:
+			fmt.Fprintf(&b, "URI=%q ", rw.req.RequestURI)
+		case AccessLogFieldResource:
+			fmt.Fprintf(&b, "resource=%q ", rw.requestInfo.Resource)
+		case AccessLogFieldNamespace:
+			fmt.Fprintf(&b, "namespace=%q ", rw.requestInfo.Namespace)
+		case AccessLogFieldLatency:
+			fmt.Fprintf(&b, "latency=%v ", latency)
+		case AccessLogFieldStatus:
+			fmt.Fprintf(&b, "resp=%v ", rw.status)
+		case AccessLogFieldUser:
+			fmt.Fprintf(&b, "user=%q ", rw.user.GetName())
+		case AccessLogFieldUserGroup:
+			fmt.Fprintf(&b, "userGroup=%v ", rw.user.GetGroups())
+		case AccessLogFieldUserAgent:
+			fmt.Fprintf(&b, "userAgent=%q ", rw.req.UserAgent())
+		case AccessLogFieldImpersonator:
+			if rw.impersonator != nil {
+				fmt.Fprintf(&b, "impersonator=%q impersonatorGroup=%v ", rw.impersonator.GetName(), rw.impersonator.GetGroups())
+			}
+		case AccessLogFieldSourceIP:
+			fmt.Fprintf(&b, "srcIP=%v ", rw.sourceIPs())
+		case AccessLogFieldResponseSize:
+			fmt.Fprintf(&b, "respSize=%d ", rw.written)
+		}
+	}
+	klog.Infof("%s: %v", strings.TrimSuffix(b.String(), " "), rw.addedInfo)
+}
+
+// sourceIPs returns the client IP resolved from trusted proxies, it keeps
+// the same format as utilnet.SourceIPs.
+func (rw *responseWriterDelegator) sourceIPs() []net.IP {
+	if info, ok := gatewayrequest.ExtraReqeustInfoFrom(rw.req.Context()); ok && info.ClientIP != nil {
+		return []net.IP{info.ClientIP}
 	}
+	return utilnet.SourceIPs(rw.req)
 }
 
 func (rw *responseWriterDelegator) recordStatus(status int) {
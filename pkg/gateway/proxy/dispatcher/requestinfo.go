@@ -0,0 +1,63 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dispatcher
+
+import (
+	"strings"
+
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+)
+
+// MalformedRequestPolicy defines how dispatcher handles requests whose
+// RequestInfo can not be parsed cleanly.
+type MalformedRequestPolicy string
+
+const (
+	// MalformedRequestPolicyReject rejects malformed requests with 400 BadRequest.
+	MalformedRequestPolicyReject MalformedRequestPolicy = "Reject"
+	// MalformedRequestPolicyNonResourceURL treats malformed requests as non-resource
+	// requests, so they can only be matched by nonResourceURLs rules.
+	MalformedRequestPolicyNonResourceURL MalformedRequestPolicy = "NonResourceURL"
+)
+
+// isMalformedRequestInfo returns true if the request path contains empty or
+// dot segments, or it is parsed as a resource request without resource or verb.
+func isMalformedRequestInfo(info *genericapirequest.RequestInfo) bool {
+	trimmed := strings.Trim(info.Path, "/")
+	if len(trimmed) > 0 {
+		for _, segment := range strings.Split(trimmed, "/") {
+			switch segment {
+			case "", ".", "..":
+				return true
+			}
+		}
+	}
+	if info.IsResourceRequest {
+		return len(info.Resource) == 0 || len(info.Verb) == 0
+	}
+	return false
+}
+
+// nonResourceAttributes converts attributes into non-resource attributes which
+// only match nonResourceURLs rules, the verb is reset to the lowercased http method.
+func nonResourceAttributes(attrs authorizer.Attributes, method string) authorizer.Attributes {
+	return authorizer.AttributesRecord{
+		User:            attrs.GetUser(),
+		Verb:            strings.ToLower(method),
+		ResourceRequest: false,
+		Path:            attrs.GetPath(),
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dispatcher
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
+	"github.com/kubewharf/kubegateway/pkg/clusters"
+	"github.com/kubewharf/kubegateway/pkg/gateway/endpoints/request"
+)
+
+var testRequestInfoFactory = &genericapirequest.RequestInfoFactory{
+	APIPrefixes:          sets.NewString("api", "apis"),
+	GrouplessAPIPrefixes: sets.NewString("api"),
+}
+
+func TestIsMalformedRequestInfo(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   bool
+	}{
+		{"root", http.MethodGet, "/", false},
+		{"healthz", http.MethodGet, "/healthz", false},
+		{"discovery", http.MethodGet, "/apis/apps/v1", false},
+		{"resource", http.MethodGet, "/api/v1/namespaces/default/pods", false},
+		{"trailing slash", http.MethodGet, "/api/v1/pods/", false},
+		{"empty segment", http.MethodGet, "/api/v1//pods", true},
+		{"dot segment", http.MethodGet, "/api/v1/./pods", true},
+		{"dot dot segment", http.MethodGet, "/healthz/../api/v1/pods", true},
+		{"empty resource", http.MethodGet, "/api/v1/namespaces/default/", false},
+		{"unknown method", "FOO", "/api/v1/pods", true},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, "https://127.0.0.1"+tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			info, err := testRequestInfoFactory.NewRequestInfo(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := isMalformedRequestInfo(info); got != tt.want {
+				t.Errorf("isMalformedRequestInfo() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNonResourceAttributes(t *testing.T) {
+	wildcardResourceRule := proxyv1alpha1.DispatchPolicyRule{
+		Verbs:     []string{"*"},
+		APIGroups: []string{"*"},
+		Resources: []string{"*"},
+	}
+	nonResourceURLRule := proxyv1alpha1.DispatchPolicyRule{
+		Verbs:           []string{"get"},
+		NonResourceURLs: []string{"/api/*"},
+	}
+
+	attrs := authorizer.AttributesRecord{
+		User:            &user.DefaultInfo{Name: "test"},
+		Verb:            "list",
+		APIVersion:      "v1",
+		Resource:        "pods",
+		ResourceRequest: true,
+		Path:            "/api/v1//pods",
+	}
+	if !clusters.RuleMatches(attrs, &wildcardResourceRule) {
+		t.Fatalf("resource attributes should match wildcard resource rule")
+	}
+
+	converted := nonResourceAttributes(attrs, http.MethodGet)
+	if clusters.RuleMatches(converted, &wildcardResourceRule) {
+		t.Errorf("non-resource attributes should not match wildcard resource rule")
+	}
+	if !clusters.RuleMatches(converted, &nonResourceURLRule) {
+		t.Errorf("non-resource attributes should match nonResourceURLs rule")
+	}
+}
+
+func Test_dispatcher_malformedRequestPolicy(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("X-Proxied-Path", req.URL.Path)
+	}))
+	defer upstream.Close()
+
+	wildcardResourceRule := proxyv1alpha1.DispatchPolicyRule{
+		Verbs:     []string{"*"},
+		APIGroups: []string{"*"},
+		Resources: []string{"*"},
+	}
+	nonResourceURLRule := proxyv1alpha1.DispatchPolicyRule{
+		Verbs:           []string{"get"},
+		NonResourceURLs: []string{"/api/*"},
+	}
+	manager := clusters.NewManager()
+	for name, rules := range map[string][]proxyv1alpha1.DispatchPolicyRule{
+		"resource.cluster":    {wildcardResourceRule},
+		"nonresource.cluster": {wildcardResourceRule, nonResourceURLRule},
+	} {
+		cluster, err := clusters.CreateClusterInfo(&proxyv1alpha1.UpstreamCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: proxyv1alpha1.UpstreamClusterSpec{
+				Servers:          []proxyv1alpha1.UpstreamClusterServer{{Endpoint: upstream.URL}},
+				DispatchPolicies: []proxyv1alpha1.DispatchPolicy{{Rules: rules}},
+			},
+		}, func(*clusters.EndpointInfo) bool { return true })
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer cluster.Stop()
+		info, _ := cluster.Endpoints.Load(upstream.URL)
+		info.UpdateStatus(true, "", "")
+		manager.Add(cluster)
+	}
+
+	tests := []struct {
+		name        string
+		policy      MalformedRequestPolicy
+		cluster     string
+		path        string
+		wantCode    int
+		wantProxied bool
+	}{
+		{"well-formed request", MalformedRequestPolicyReject, "resource.cluster", "/api/v1/pods", http.StatusOK, true},
+		{"reject", MalformedRequestPolicyReject, "nonresource.cluster", "/api/v1//pods", http.StatusBadRequest, false},
+		{"matched by nonResourceURLs rule", MalformedRequestPolicyNonResourceURL, "nonresource.cluster", "/api/v1//pods", http.StatusOK, true},
+		{"not matched by resource rule", MalformedRequestPolicyNonResourceURL, "resource.cluster", "/api/v1//pods", http.StatusInternalServerError, false},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			d := &dispatcher{Manager: manager, codecs: scheme.Codecs, malformedRequestPolicy: tt.policy}
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			info, err := testRequestInfoFactory.NewRequestInfo(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := genericapirequest.WithRequestInfo(req.Context(), info)
+			ctx = genericapirequest.WithUser(ctx, &user.DefaultInfo{Name: "test"})
+			ctx = request.WithExtraReqeustInfo(ctx, &request.ExtraRequestInfo{Hostname: tt.cluster})
+			ctx = request.WithProxyInfo(ctx, request.NewProxyInfo())
+			w := httptest.NewRecorder()
+			d.ServeHTTP(w, req.WithContext(ctx))
+
+			if w.Code != tt.wantCode {
+				t.Errorf("status code = %v, want %v, body: %s", w.Code, tt.wantCode, w.Body.String())
+			}
+			if proxied := w.Header().Get("X-Proxied-Path") == tt.path; proxied != tt.wantProxied {
+				t.Errorf("request is proxied = %v, want %v", proxied, tt.wantProxied)
+			}
+		})
+	}
+}
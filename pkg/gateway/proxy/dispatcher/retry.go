@@ -0,0 +1,233 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dispatcher
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/klog"
+
+	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
+	"github.com/kubewharf/kubegateway/pkg/clusters"
+	"github.com/kubewharf/kubegateway/pkg/gateway/metrics"
+)
+
+const (
+	// defaultMaxRetries is the maximum number of times a request is retried on other endpoints
+	// if it is not specified by the retry policy
+	defaultMaxRetries = 2
+	// maxStatusBodyBytes is the maximum size of upstream error response read to decode
+	// the Status, larger responses are never retried.
+	maxStatusBodyBytes = 64 * 1024
+	// defaultTooManyRequestsBackoff is how long 429 responses are delayed with the Backoff
+	// policy if it is not specified
+	defaultTooManyRequestsBackoff = time.Second
+
+	// retryReasonConnectionError is the retry reason of connection-level errors
+	retryReasonConnectionError = "ConnectionError"
+)
+
+// isRetriableRequest returns true if the request is idempotent or its verb is opted in by
+// the retry policy, and its body can be replayed. Requests whose body can not be replayed
+// are never retried, because the body may have been streamed to the failed endpoint.
+func isRetriableRequest(req *http.Request, requestInfo *genericapirequest.RequestInfo, policy *proxyv1alpha1.RetryPolicy) bool {
+	switch requestInfo.Verb {
+	case "get", "list", "watch":
+	default:
+		if !containsString(policy.Verbs, requestInfo.Verb) {
+			return false
+		}
+	}
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// retryTransport retries requests on another ready endpoint if the upstream responds
+// an error which matches the retry policy, or the connection to it fails.
+type retryTransport struct {
+	cluster string
+	policy  *proxyv1alpha1.RetryPolicy
+	picker  clusters.EndpointPicker
+	decoder runtime.Decoder
+	// transport of the first chosen endpoint
+	transport http.RoundTripper
+	endpoint  string
+	// onRetry is called when the request is retried on another endpoint
+	onRetry func(next *clusters.EndpointInfo)
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport, endpoint := t.transport, t.endpoint
+	tried := []string{endpoint}
+	for {
+		resp, err := transport.RoundTrip(req)
+		// giveUp returns the upstream response or error to the client
+		giveUp := func() (*http.Response, error) {
+			if err != nil {
+				return resp, err
+			}
+			return t.respond(req, resp, endpoint), nil
+		}
+		if len(tried) > t.maxRetries() {
+			return giveUp()
+		}
+		var reason string
+		if err != nil {
+			if !t.policy.ConnectionErrors || req.Context().Err() != nil {
+				// the request is canceled by the client or timed out
+				return resp, err
+			}
+			reason = retryReasonConnectionError
+		} else {
+			var ok bool
+			if reason, ok = t.retriableStatus(resp); !ok {
+				return t.respond(req, resp, endpoint), nil
+			}
+		}
+		next, pickErr := t.picker.PopExcluding(tried...)
+		if pickErr != nil {
+			// no more endpoints to retry, respond the upstream error
+			return giveUp()
+		}
+		ep, parseErr := url.Parse(next.Endpoint)
+		if parseErr != nil {
+			return giveUp()
+		}
+		retryReq := req.Clone(req.Context())
+		if req.GetBody != nil {
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return giveUp()
+			}
+			retryReq.Body = body
+		}
+		retryReq.URL.Scheme = ep.Scheme
+		retryReq.URL.Host = ep.Host
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		klog.V(3).Infof("[proxy retry] cluster=%q method=%q uri=%q endpoint=%q reason=%q, retrying on endpoint %q",
+			t.cluster, req.Method, req.RequestURI, endpoint, reason, next.Endpoint)
+		metrics.RecordRetry(t.cluster, endpoint, reason)
+		if t.onRetry != nil {
+			t.onRetry(next)
+		}
+
+		req, transport, endpoint = retryReq, next.ProxyTransport, next.Endpoint
+		tried = append(tried, endpoint)
+	}
+}
+
+// maxRetries returns the maximum number of times a request is retried on other endpoints.
+func (t *retryTransport) maxRetries() int {
+	if t.policy.MaxRetries > 0 {
+		return int(t.policy.MaxRetries)
+	}
+	return defaultMaxRetries
+}
+
+// respond returns the upstream response which is responded to the client. 429 responses are
+// delayed with the Backoff policy, so that clients do not hammer throttled upstreams.
+func (t *retryTransport) respond(req *http.Request, resp *http.Response, endpoint string) *http.Response {
+	if resp.StatusCode != http.StatusTooManyRequests || t.policy.TooManyRequests != proxyv1alpha1.TooManyRequestsBackoff {
+		return resp
+	}
+	backoff := time.Duration(t.policy.TooManyRequestsBackoffSeconds) * time.Second
+	if backoff == 0 {
+		backoff = defaultTooManyRequestsBackoff
+	}
+	klog.V(3).Infof("[proxy retry] cluster=%q method=%q uri=%q endpoint=%q responds 429, backoff %v before responding",
+		t.cluster, req.Method, req.RequestURI, endpoint, backoff)
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-req.Context().Done():
+	}
+	return resp
+}
+
+// retriableStatus decodes the Status from an upstream error response and returns its
+// reason if it matches the retry policy. The response body is restored after reading.
+func (t *retryTransport) retriableStatus(resp *http.Response) (string, bool) {
+	if resp.StatusCode == http.StatusTooManyRequests && t.policy.TooManyRequests == proxyv1alpha1.TooManyRequestsRetry {
+		// the response may not be a Status, e.g. it is from a load balancer
+		return string(metav1.StatusReasonTooManyRequests), true
+	}
+	for _, code := range t.policy.StatusCodes {
+		if resp.StatusCode == int(code) {
+			// the response may not be a Status, e.g. it is from a load balancer
+			return strconv.Itoa(resp.StatusCode), true
+		}
+	}
+	if resp.StatusCode < http.StatusBadRequest || resp.Body == nil {
+		return "", false
+	}
+	body := resp.Body
+	buf, err := ioutil.ReadAll(io.LimitReader(body, maxStatusBodyBytes+1))
+	resp.Body = &readCloser{
+		Reader: io.MultiReader(bytes.NewReader(buf), body),
+		Closer: body,
+	}
+	if err != nil || len(buf) > maxStatusBodyBytes {
+		return "", false
+	}
+
+	obj, err := runtime.Decode(t.decoder, buf)
+	if err != nil {
+		return "", false
+	}
+	status, ok := obj.(*metav1.Status)
+	if !ok {
+		return "", false
+	}
+	if !matchRetryPolicy(t.policy, status) {
+		return "", false
+	}
+	return string(status.Reason), true
+}
+
+func matchRetryPolicy(policy *proxyv1alpha1.RetryPolicy, status *metav1.Status) bool {
+	for _, reason := range policy.StatusReasons {
+		if string(status.Reason) == reason {
+			return true
+		}
+	}
+	for _, message := range policy.StatusMessages {
+		if strings.Contains(status.Message, message) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
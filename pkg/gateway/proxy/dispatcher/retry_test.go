@@ -0,0 +1,292 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dispatcher
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/endpoints/handlers/responsewriters"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	proxyv1alpha1 "github.com/kubewharf/kubegateway/pkg/apis/proxy/v1alpha1"
+	"github.com/kubewharf/kubegateway/pkg/clusters"
+)
+
+type fakeEndpointPicker struct {
+	clusters.EndpointPicker
+	endpoints []*clusters.EndpointInfo
+}
+
+func (f *fakeEndpointPicker) PopExcluding(excluded ...string) (*clusters.EndpointInfo, error) {
+	for _, ep := range f.endpoints {
+		found := false
+		for _, e := range excluded {
+			if e == ep.Endpoint {
+				found = true
+			}
+		}
+		if !found {
+			return ep, nil
+		}
+	}
+	return nil, clusters.ErrNoReadyEndpoints
+}
+
+func newStatusServer(err error, hits *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		*hits++
+		if err != nil {
+			responsewriters.ErrorNegotiated(err, scheme.Codecs, schema.GroupVersion{Version: "v1"}, w, req)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+	}))
+}
+
+func Test_retryTransport(t *testing.T) {
+	shuttingDown := errors.NewServiceUnavailable("apiserver is shutting down")
+	leaderChanged := errors.NewInternalError(fmt.Errorf("etcdserver: leader changed"))
+	notFound := errors.NewNotFound(schema.GroupResource{Resource: "pods"}, "test")
+
+	policy := &proxyv1alpha1.RetryPolicy{
+		StatusReasons:  []string{string(metav1.StatusReasonServiceUnavailable)},
+		StatusMessages: []string{"leader changed"},
+	}
+	tests := []struct {
+		name          string
+		errs          []error
+		wantCode      int
+		wantHits      []int
+		wantRetriedOn []int
+	}{
+		{"reason matches", []error{shuttingDown, nil}, http.StatusOK, []int{1, 1}, []int{1}},
+		{"message matches", []error{leaderChanged, nil}, http.StatusOK, []int{1, 1}, []int{1}},
+		{"not retriable", []error{notFound, nil}, http.StatusNotFound, []int{1, 0}, nil},
+		{"no more endpoints", []error{shuttingDown, shuttingDown}, http.StatusServiceUnavailable, []int{1, 1}, []int{1}},
+		{"retries are bounded", []error{shuttingDown, leaderChanged, shuttingDown, nil}, http.StatusServiceUnavailable, []int{1, 1, 1, 0}, []int{1, 2}},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			hits := make([]int, len(tt.errs))
+			picker := &fakeEndpointPicker{}
+			index := map[string]int{}
+			for j, err := range tt.errs {
+				server := newStatusServer(err, &hits[j])
+				defer server.Close()
+				picker.endpoints = append(picker.endpoints, &clusters.EndpointInfo{Endpoint: server.URL, ProxyTransport: http.DefaultTransport})
+				index[server.URL] = j
+			}
+
+			var retriedOn []int
+			transport := &retryTransport{
+				cluster:   "test",
+				policy:    policy,
+				picker:    picker,
+				decoder:   scheme.Codecs.UniversalDeserializer(),
+				transport: http.DefaultTransport,
+				endpoint:  picker.endpoints[0].Endpoint,
+				onRetry: func(next *clusters.EndpointInfo) {
+					retriedOn = append(retriedOn, index[next.Endpoint])
+				},
+			}
+			req, _ := http.NewRequest(http.MethodGet, picker.endpoints[0].Endpoint+"/api/v1/pods", nil)
+			resp, err := transport.RoundTrip(req)
+			if err != nil {
+				t.Fatalf("RoundTrip() error = %v", err)
+			}
+			defer resp.Body.Close()
+			// the response body must be readable after the Status is decoded
+			if body, err := ioutil.ReadAll(resp.Body); err != nil || len(body) == 0 {
+				t.Errorf("failed to read response body, body = %q, err = %v", body, err)
+			}
+			if resp.StatusCode != tt.wantCode {
+				t.Errorf("status code = %v, want %v", resp.StatusCode, tt.wantCode)
+			}
+			if !reflect.DeepEqual(hits, tt.wantHits) {
+				t.Errorf("endpoint hits = %v, want %v", hits, tt.wantHits)
+			}
+			if !reflect.DeepEqual(retriedOn, tt.wantRetriedOn) {
+				t.Errorf("retried on = %v, want %v", retriedOn, tt.wantRetriedOn)
+			}
+		})
+	}
+}
+
+func Test_retryTransport_policy(t *testing.T) {
+	// 0 is an endpoint which refuses connections, others respond the status code
+	tests := []struct {
+		name     string
+		policy   proxyv1alpha1.RetryPolicy
+		codes    []int
+		wantCode int
+		wantErr  bool
+		wantHits []int
+	}{
+		{"status code matches", proxyv1alpha1.RetryPolicy{StatusCodes: []int32{http.StatusBadGateway}}, []int{http.StatusBadGateway, http.StatusOK}, http.StatusOK, false, []int{1, 1}},
+		{"status code does not match", proxyv1alpha1.RetryPolicy{StatusCodes: []int32{http.StatusBadGateway}}, []int{http.StatusGatewayTimeout, http.StatusOK}, http.StatusGatewayTimeout, false, []int{1, 0}},
+		{"connection error", proxyv1alpha1.RetryPolicy{ConnectionErrors: true}, []int{0, http.StatusOK}, http.StatusOK, false, []int{0, 1}},
+		{"connection error is not retried", proxyv1alpha1.RetryPolicy{}, []int{0, http.StatusOK}, 0, true, []int{0, 0}},
+		{"connection error without more endpoints", proxyv1alpha1.RetryPolicy{ConnectionErrors: true}, []int{0, 0}, 0, true, []int{0, 0}},
+		{"max retries", proxyv1alpha1.RetryPolicy{ConnectionErrors: true, MaxRetries: 1}, []int{0, 0, http.StatusOK}, 0, true, []int{0, 0, 0}},
+		{"default max retries", proxyv1alpha1.RetryPolicy{ConnectionErrors: true}, []int{0, 0, 0, http.StatusOK}, 0, true, []int{0, 0, 0, 0}},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			hits := make([]int, len(tt.codes))
+			picker := &fakeEndpointPicker{}
+			for j, code := range tt.codes {
+				j, code := j, code
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+					hits[j]++
+					w.WriteHeader(code)
+				}))
+				if code == 0 {
+					server.Close()
+				} else {
+					defer server.Close()
+				}
+				picker.endpoints = append(picker.endpoints, &clusters.EndpointInfo{Endpoint: server.URL, ProxyTransport: http.DefaultTransport})
+			}
+
+			transport := &retryTransport{
+				cluster:   "test",
+				policy:    &tt.policy,
+				picker:    picker,
+				decoder:   scheme.Codecs.UniversalDeserializer(),
+				transport: http.DefaultTransport,
+				endpoint:  picker.endpoints[0].Endpoint,
+			}
+			req, _ := http.NewRequest(http.MethodGet, picker.endpoints[0].Endpoint+"/api/v1/pods", nil)
+			resp, err := transport.RoundTrip(req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RoundTrip() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				resp.Body.Close()
+				if resp.StatusCode != tt.wantCode {
+					t.Errorf("status code = %v, want %v", resp.StatusCode, tt.wantCode)
+				}
+			}
+			if !reflect.DeepEqual(hits, tt.wantHits) {
+				t.Errorf("endpoint hits = %v, want %v", hits, tt.wantHits)
+			}
+		})
+	}
+}
+
+func Test_isRetriableRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		verb    string
+		body    bool
+		getBody bool
+		verbs   []string
+		want    bool
+	}{
+		{"get", "get", false, false, nil, true},
+		{"watch", "watch", false, false, nil, true},
+		{"create", "create", true, true, nil, false},
+		{"create opted in", "create", true, true, []string{"create"}, true},
+		{"opted in body can not be replayed", "create", true, false, []string{"create"}, false},
+		{"body can not be replayed", "list", true, false, nil, false},
+		{"body can be replayed", "list", true, true, nil, true},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/pods", nil)
+			if tt.body {
+				req.Body = ioutil.NopCloser(strings.NewReader("{}"))
+			}
+			if tt.getBody {
+				req.GetBody = func() (io.ReadCloser, error) { return ioutil.NopCloser(strings.NewReader("{}")), nil }
+			}
+			if got := isRetriableRequest(req, &genericapirequest.RequestInfo{Verb: tt.verb}, &proxyv1alpha1.RetryPolicy{Verbs: tt.verbs}); got != tt.want {
+				t.Errorf("isRetriableRequest() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_retryTransport_tooManyRequests(t *testing.T) {
+	tooManyRequests := errors.NewTooManyRequests("too many requests", 1)
+
+	tests := []struct {
+		name        string
+		policy      proxyv1alpha1.TooManyRequestsPolicy
+		errs        []error
+		wantCode    int
+		wantHits    []int
+		wantBackoff bool
+	}{
+		{"pass through", "", []error{tooManyRequests, nil}, http.StatusTooManyRequests, []int{1, 0}, false},
+		{"retry", proxyv1alpha1.TooManyRequestsRetry, []error{tooManyRequests, nil}, http.StatusOK, []int{1, 1}, false},
+		{"retry without more endpoints", proxyv1alpha1.TooManyRequestsRetry, []error{tooManyRequests}, http.StatusTooManyRequests, []int{1}, false},
+		{"backoff", proxyv1alpha1.TooManyRequestsBackoff, []error{tooManyRequests, nil}, http.StatusTooManyRequests, []int{1, 0}, true},
+		{"no backoff for other errors", proxyv1alpha1.TooManyRequestsBackoff, []error{errors.NewServiceUnavailable("unavailable")}, http.StatusServiceUnavailable, []int{1}, false},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			hits := make([]int, len(tt.errs))
+			picker := &fakeEndpointPicker{}
+			for j, err := range tt.errs {
+				server := newStatusServer(err, &hits[j])
+				defer server.Close()
+				picker.endpoints = append(picker.endpoints, &clusters.EndpointInfo{Endpoint: server.URL, ProxyTransport: http.DefaultTransport})
+			}
+
+			transport := &retryTransport{
+				cluster:   "test",
+				policy:    &proxyv1alpha1.RetryPolicy{TooManyRequests: tt.policy},
+				picker:    picker,
+				decoder:   scheme.Codecs.UniversalDeserializer(),
+				transport: http.DefaultTransport,
+				endpoint:  picker.endpoints[0].Endpoint,
+			}
+			req, _ := http.NewRequest(http.MethodGet, picker.endpoints[0].Endpoint+"/api/v1/pods", nil)
+			start := time.Now()
+			resp, err := transport.RoundTrip(req)
+			if err != nil {
+				t.Fatalf("RoundTrip() error = %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.wantCode {
+				t.Errorf("status code = %v, want %v", resp.StatusCode, tt.wantCode)
+			}
+			if !reflect.DeepEqual(hits, tt.wantHits) {
+				t.Errorf("endpoint hits = %v, want %v", hits, tt.wantHits)
+			}
+			if backoff := time.Since(start) >= defaultTooManyRequestsBackoff; backoff != tt.wantBackoff {
+				t.Errorf("backoff = %v, want %v", backoff, tt.wantBackoff)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dispatcher
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"time"
+
+	"k8s.io/apiserver/pkg/authentication/user"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/klog"
+
+	"github.com/kubewharf/kubegateway/pkg/clusters"
+	"github.com/kubewharf/kubegateway/pkg/gateway/metrics"
+)
+
+const (
+	shadowRequestTimeout = 30 * time.Second
+
+	shadowResultSkipped    = "skipped"
+	shadowResultError      = "error"
+	shadowResultCompleted  = "completed"
+	shadowResultMatched    = "matched"
+	shadowResultMismatched = "mismatched"
+)
+
+// responseDigest is the status code and body hash of a response
+type responseDigest struct {
+	code int
+	hash string
+}
+
+// shouldShadow returns true if the request can be mirrored, only get and list
+// requests are mirrored because they have no side effect.
+func shouldShadow(requestInfo *genericapirequest.RequestInfo, percentage int32) bool {
+	if !requestInfo.IsResourceRequest || (requestInfo.Verb != "get" && requestInfo.Verb != "list") {
+		return false
+	}
+	if percentage == 0 || percentage >= 100 {
+		return true
+	}
+	return rand.Int31n(100) < percentage
+}
+
+// startShadow mirrors the request to the shadow cluster of the given cluster asynchronously.
+// If responses need to be compared, it returns a channel which the primary response digest
+// must be sent to after the primary request finished, otherwise it returns nil.
+func (d *dispatcher) startShadow(req *http.Request, cluster *clusters.ClusterInfo, requestInfo *genericapirequest.RequestInfo, u user.Info) chan<- responseDigest {
+	config, ok := cluster.ShadowConfig()
+	if !ok || !shouldShadow(requestInfo, config.Percentage) {
+		return nil
+	}
+
+	shadowCluster, ok := d.Get(config.Cluster)
+	if !ok || shadowCluster.IsDisabled() {
+		metrics.RecordShadowRequest(cluster.Cluster, config.Cluster, shadowResultSkipped)
+		return nil
+	}
+	endpoint, err := shadowCluster.PickOne()
+	if err != nil {
+		metrics.RecordShadowRequest(cluster.Cluster, config.Cluster, shadowResultSkipped)
+		return nil
+	}
+	ep, err := url.Parse(endpoint.Endpoint)
+	if err != nil {
+		metrics.RecordShadowRequest(cluster.Cluster, config.Cluster, shadowResultSkipped)
+		return nil
+	}
+
+	// shadow request must not be canceled with the primary request, but it is still
+	// sent as the same user by the impersonating transport
+	ctx, cancel := context.WithTimeout(genericapirequest.WithUser(context.Background(), u), shadowRequestTimeout)
+	shadowReq := req.Clone(ctx)
+	shadowReq.URL = &url.URL{
+		Scheme:   ep.Scheme,
+		Host:     ep.Host,
+		Path:     req.URL.Path,
+		RawQuery: req.URL.RawQuery,
+	}
+	shadowReq.Host = ep.Host
+	shadowReq.RequestURI = ""
+	shadowReq.Body = nil
+	shadowReq.GetBody = nil
+	shadowReq.ContentLength = 0
+
+	var primaryCh chan responseDigest
+	if config.CompareResponses {
+		primaryCh = make(chan responseDigest, 1)
+	}
+
+	go func() {
+		defer cancel()
+		shadow, err := doShadowRequest(endpoint.ProxyTransport, shadowReq)
+		if err != nil {
+			klog.V(3).Infof("[shadow] failed to mirror request to cluster=%q endpoint=%q uri=%q: %v", config.Cluster, endpoint.Endpoint, req.RequestURI, err)
+			metrics.RecordShadowRequest(cluster.Cluster, config.Cluster, shadowResultError)
+			return
+		}
+		if primaryCh == nil {
+			metrics.RecordShadowRequest(cluster.Cluster, config.Cluster, shadowResultCompleted)
+			return
+		}
+		primary := <-primaryCh
+		if primary == shadow {
+			klog.V(4).Infof("[shadow] response matched, cluster=%q shadow=%q verb=%q uri=%q resp=%v", cluster.Cluster, config.Cluster, requestInfo.Verb, req.RequestURI, primary.code)
+			metrics.RecordShadowRequest(cluster.Cluster, config.Cluster, shadowResultMatched)
+			return
+		}
+		klog.Warningf("[shadow] response mismatched, cluster=%q shadow=%q endpoint=%q verb=%q uri=%q resp=%v shadowResp=%v hash=%s shadowHash=%s",
+			cluster.Cluster, config.Cluster, endpoint.Endpoint, requestInfo.Verb, req.RequestURI, primary.code, shadow.code, primary.hash, shadow.hash)
+		metrics.RecordShadowRequest(cluster.Cluster, config.Cluster, shadowResultMismatched)
+	}()
+
+	return primaryCh
+}
+
+func doShadowRequest(rt http.RoundTripper, req *http.Request) (responseDigest, error) {
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		return responseDigest{}, err
+	}
+	defer resp.Body.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, resp.Body); err != nil {
+		return responseDigest{}, err
+	}
+	return responseDigest{code: resp.StatusCode, hash: hashString(h)}, nil
+}
+
+func hashString(h hash.Hash) string {
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,74 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dispatcher
+
+import (
+	"crypto/sha256"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+)
+
+func Test_shouldShadow(t *testing.T) {
+	tests := []struct {
+		name        string
+		requestInfo *genericapirequest.RequestInfo
+		percentage  int32
+		want        bool
+	}{
+		{"get", &genericapirequest.RequestInfo{IsResourceRequest: true, Verb: "get"}, 0, true},
+		{"list", &genericapirequest.RequestInfo{IsResourceRequest: true, Verb: "list"}, 100, true},
+		{"watch", &genericapirequest.RequestInfo{IsResourceRequest: true, Verb: "watch"}, 100, false},
+		{"create", &genericapirequest.RequestInfo{IsResourceRequest: true, Verb: "create"}, 100, false},
+		{"non resource", &genericapirequest.RequestInfo{IsResourceRequest: false, Verb: "get"}, 100, false},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shouldShadow(tt.requestInfo, tt.percentage); got != tt.want {
+				t.Errorf("shouldShadow() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_doShadowRequest(t *testing.T) {
+	body := `{"kind":"PodList","items":[]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/api/v1/pods", nil)
+	got, err := doShadowRequest(http.DefaultTransport, req)
+	if err != nil {
+		t.Fatalf("doShadowRequest() error = %v", err)
+	}
+
+	// the primary response digest is computed by the delegator from written bytes
+	delegate := &responseWriterDelegator{
+		w:           httptest.NewRecorder(),
+		requestInfo: &genericapirequest.RequestInfo{},
+		bodyHash:    sha256.New(),
+	}
+	delegate.Write([]byte(body[:10]))
+	delegate.Write([]byte(body[10:]))
+	if want := delegate.digest(); got != want {
+		t.Errorf("doShadowRequest() = %+v, want %+v", got, want)
+	}
+}
@@ -21,14 +21,22 @@ import (
 )
 
 var (
-	statusReasonNoReadyEndpoints         = "no_ready_endpoints"
-	statusReasonClusterNotBeingProxied   = "cluster_not_being_proxied"
-	statusReasonInvalidRequestContext    = "invalid_request_context"
-	statusReasonCircuitBreaker           = "circuit_breaker"
-	statusReasonRateLimited              = "rate_limited"
-	statusReasonInvalidEndpoint          = "invalid_endpoint"
-	statusReasonUpgradeAwareHandlerError = "upgrade_aware_handler_error"
-	statusReasonReverseProxyError        = "reverse_proxy_error"
+	statusReasonNoReadyEndpoints          = "no_ready_endpoints"
+	statusReasonClusterNotBeingProxied    = "cluster_not_being_proxied"
+	statusReasonInvalidRequestContext     = "invalid_request_context"
+	statusReasonCircuitBreaker            = "circuit_breaker"
+	statusReasonRateLimited               = "rate_limited"
+	statusReasonInvalidEndpoint           = "invalid_endpoint"
+	statusReasonUpgradeAwareHandlerError  = "upgrade_aware_handler_error"
+	statusReasonReverseProxyError         = "reverse_proxy_error"
+	statusReasonMalformedRequest          = "malformed_request"
+	statusReasonNotEnoughHealthyEndpoints = "not_enough_healthy_endpoints"
+	statusReasonClusterDisabled           = "cluster_disabled"
+	statusReasonInvalidRequestBody        = "invalid_request_body"
+	statusReasonClusterProvisioning       = "cluster_provisioning"
+	statusReasonResourceNotServed         = "resource_not_served"
+	statusReasonClusterPaused             = "cluster_paused"
+	statusReasonResourceHidden            = "resource_hidden"
 )
 
 func captureErrorReason(reason string) bool {
@@ -87,6 +87,7 @@ func (h *UpgradeAwareHandler) ServeHTTP(w http.ResponseWriter, req *http.Request
 	// WithContext creates a shallow clone of the request with the same context.
 	newReq := req.WithContext(req.Context())
 	newReq.Header = utilnet.CloneHeader(req.Header)
+	removeHopByHopHeaders(newReq.Header)
 	if !h.UseRequestLocation {
 		newReq.URL = &loc
 	}
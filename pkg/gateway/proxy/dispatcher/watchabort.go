@@ -0,0 +1,259 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dispatcher
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/rand"
+	"mime"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/runtime/serializer/streaming"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/klog"
+
+	"github.com/kubewharf/kubegateway/pkg/gateway/metrics"
+)
+
+// WatchAbortPolicy defines how dispatcher terminates the response of a watch
+// whose upstream stream is aborted before the client disconnects.
+type WatchAbortPolicy string
+
+const (
+	// WatchAbortPolicyErrorEvent ends the response with an ERROR watch event which
+	// carries a Status, client-go reflectors relist after receiving it.
+	WatchAbortPolicyErrorEvent WatchAbortPolicy = "ErrorEvent"
+	// WatchAbortPolicyClose ends the response cleanly after the last complete event,
+	// client-go reflectors rewatch from the last resourceVersion they received.
+	WatchAbortPolicyClose WatchAbortPolicy = "Close"
+	// WatchAbortPolicyAbort aborts the response, the client may get a truncated event.
+	WatchAbortPolicyAbort WatchAbortPolicy = "Abort"
+)
+
+const (
+	watchAbortResultErrorEvent = "error_event"
+	watchAbortResultClose      = "close"
+	watchAbortResultAbort      = "abort"
+
+	// watchStreamReadBufferBytes is the size of each read from upstream watch stream
+	watchStreamReadBufferBytes = 32 * 1024
+)
+
+// watchAbortTransport terminates watch responses according to the WatchAbortPolicy when
+// the upstream stream is aborted, and records the aborted streams. Streams canceled because
+// they last for the max watch duration are always closed cleanly after the last complete event.
+type watchAbortTransport struct {
+	cluster  string
+	resource string
+	policy   WatchAbortPolicy
+	codecs   serializer.CodecFactory
+	// clientCtx is the context of the client request, it is done if the client disconnects
+	clientCtx context.Context
+	transport http.RoundTripper
+	// expired is set to 1 if the watch lasts for the max watch duration
+	expired int32
+}
+
+// expire marks the watch as lasting for the max watch duration, the upstream request must
+// be canceled after it so that the response is closed.
+func (t *watchAbortTransport) expire() {
+	atomic.StoreInt32(&t.expired, 1)
+}
+
+func (t *watchAbortTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK || len(resp.Header.Get("Content-Encoding")) > 0 {
+		return resp, err
+	}
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil {
+		return resp, nil
+	}
+	info, ok := runtime.SerializerInfoForMediaType(t.codecs.SupportedMediaTypes(), mediaType)
+	if !ok || info.MediaType != mediaType || info.StreamSerializer == nil {
+		return resp, nil
+	}
+	resp.Body = newWatchStreamReader(resp.Body, !info.EncodesAsText, func(err error) []byte {
+		return t.terminate(req, info, err)
+	})
+	return resp, nil
+}
+
+// terminate returns the bytes written to the client after the last complete event when upstream
+// watch stream is aborted with err, it returns nil if the response should be aborted.
+func (t *watchAbortTransport) terminate(req *http.Request, info runtime.SerializerInfo, err error) []byte {
+	if t.clientCtx.Err() != nil {
+		// the client is gone, nothing should be written
+		return nil
+	}
+	if atomic.LoadInt32(&t.expired) == 1 {
+		// the client rewatches from the last resourceVersion it received, possibly through another gateway
+		klog.V(4).Infof("[proxy watch] cluster=%q uri=%q lasts for max watch duration, closed", t.cluster, req.RequestURI)
+		metrics.RecordWatchMaxDurationExceeded(t.cluster, t.resource)
+		return []byte{}
+	}
+
+	policy := t.policy
+	var event []byte
+	if policy == WatchAbortPolicyErrorEvent {
+		status := errors.NewInternalError(fmt.Errorf("upstream watch stream aborted: %v", err)).Status()
+		var encodeErr error
+		event, encodeErr = encodeWatchErrorEvent(t.codecs, info, &status)
+		if encodeErr != nil {
+			klog.Errorf("[proxy watch] failed to encode watch error event: %v", encodeErr)
+			policy = WatchAbortPolicyClose
+		}
+	}
+
+	result := watchAbortResultAbort
+	trailer := []byte(nil)
+	switch policy {
+	case WatchAbortPolicyErrorEvent:
+		result, trailer = watchAbortResultErrorEvent, event
+	case WatchAbortPolicyClose:
+		result, trailer = watchAbortResultClose, []byte{}
+	}
+	klog.V(2).Infof("[proxy watch] cluster=%q uri=%q upstream watch stream aborted: %v, terminated with %s", t.cluster, req.RequestURI, err, result)
+	metrics.RecordUpstreamWatchAbort(t.cluster, t.resource, result)
+	return trailer
+}
+
+// jitterWatchDuration returns a duration up to 10% shorter than maxDuration, so that watches
+// established at the same time, e.g. after a gateway restarts, do not expire all at once.
+func jitterWatchDuration(maxDuration time.Duration) time.Duration {
+	return maxDuration - time.Duration(rand.Int63n(int64(maxDuration)/10+1))
+}
+
+// encodeWatchErrorEvent encodes an ERROR watch event with the status in the same way as kube-apiserver
+// serves watch, the event is framed by the stream serializer of info.
+func encodeWatchErrorEvent(codecs serializer.CodecFactory, info runtime.SerializerInfo, status *metav1.Status) ([]byte, error) {
+	if info.StreamSerializer == nil || info.StreamSerializer.Framer == nil {
+		return nil, fmt.Errorf("no stream serializer defined for %q", info.MediaType)
+	}
+	gv := schema.GroupVersion{Group: "", Version: "v1"}
+
+	object := &bytes.Buffer{}
+	if err := codecs.EncoderForVersion(info.Serializer, gv).Encode(status, object); err != nil {
+		return nil, err
+	}
+	event := &metav1.WatchEvent{
+		Type:   string(watch.Error),
+		Object: runtime.RawExtension{Raw: object.Bytes()},
+	}
+
+	buf := &bytes.Buffer{}
+	encoder := streaming.NewEncoder(info.StreamSerializer.Framer.NewFrameWriter(buf), codecs.EncoderForVersion(info.StreamSerializer.Serializer, gv))
+	if err := encoder.Encode(event); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// watchStreamReader only returns complete frames of watch events, so that a stream aborted
+// in the middle of an event can still be terminated cleanly. Text streams are framed by
+// newlines and others are framed by 4 bytes big-endian length prefix.
+type watchStreamReader struct {
+	body            io.ReadCloser
+	lengthDelimited bool
+	// onAbort returns the bytes appended after the last complete frame when body is aborted
+	// with a non-EOF error, the error is returned as is if onAbort returns nil.
+	onAbort func(err error) []byte
+
+	buf []byte
+	// pending are the bytes read from body but not returned yet
+	pending []byte
+	// complete is the length of complete frames at the head of pending
+	complete int
+	// err is returned after pending is drained
+	err error
+}
+
+func newWatchStreamReader(body io.ReadCloser, lengthDelimited bool, onAbort func(err error) []byte) *watchStreamReader {
+	return &watchStreamReader{
+		body:            body,
+		lengthDelimited: lengthDelimited,
+		onAbort:         onAbort,
+		buf:             make([]byte, watchStreamReadBufferBytes),
+	}
+}
+
+func (r *watchStreamReader) Read(p []byte) (int, error) {
+	for r.complete == 0 && r.err == nil {
+		r.fill()
+	}
+	if r.complete == 0 {
+		return 0, r.err
+	}
+	n := copy(p, r.pending[:r.complete])
+	r.pending = r.pending[n:]
+	r.complete -= n
+	return n, nil
+}
+
+func (r *watchStreamReader) Close() error {
+	return r.body.Close()
+}
+
+func (r *watchStreamReader) fill() {
+	n, err := r.body.Read(r.buf)
+	start := len(r.pending)
+	r.pending = append(r.pending, r.buf[:n]...)
+	r.complete = r.completeFrames(start)
+	switch {
+	case err == nil:
+	case err == io.EOF:
+		// upstream ends the stream, returns whatever it sends
+		r.complete, r.err = len(r.pending), io.EOF
+	default:
+		trailer := r.onAbort(err)
+		if trailer == nil {
+			r.complete, r.err = len(r.pending), err
+			return
+		}
+		r.pending = append(r.pending[:r.complete], trailer...)
+		r.complete, r.err = len(r.pending), io.EOF
+	}
+}
+
+// completeFrames returns the length of complete frames at the head of pending,
+// bytes before start have been scanned.
+func (r *watchStreamReader) completeFrames(start int) int {
+	if !r.lengthDelimited {
+		if i := bytes.LastIndexByte(r.pending[start:], '\n'); i >= 0 {
+			return start + i + 1
+		}
+		return r.complete
+	}
+	end := r.complete
+	for len(r.pending)-end >= 4 {
+		size := 4 + int(binary.BigEndian.Uint32(r.pending[end:]))
+		if len(r.pending)-end < size {
+			break
+		}
+		end += size
+	}
+	return end
+}
@@ -0,0 +1,260 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dispatcher
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer/streaming"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/scheme"
+	restclientwatch "k8s.io/client-go/rest/watch"
+)
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// abortedBody returns data in chunks and then fails with err
+type abortedBody struct {
+	data  []byte
+	chunk int
+	err   error
+}
+
+func (b *abortedBody) Read(p []byte) (int, error) {
+	if len(b.data) == 0 {
+		return 0, b.err
+	}
+	n := b.chunk
+	if n > len(b.data) {
+		n = len(b.data)
+	}
+	n = copy(p, b.data[:n])
+	b.data = b.data[n:]
+	return n, nil
+}
+
+func (b *abortedBody) Close() error {
+	return nil
+}
+
+func encodeTestWatchEvent(t *testing.T, info runtime.SerializerInfo, eventType watch.EventType, obj runtime.Object) []byte {
+	gv := schema.GroupVersion{Version: "v1"}
+	object := &bytes.Buffer{}
+	if err := scheme.Codecs.EncoderForVersion(info.Serializer, gv).Encode(obj, object); err != nil {
+		t.Fatalf("failed to encode object: %v", err)
+	}
+	buf := &bytes.Buffer{}
+	encoder := streaming.NewEncoder(info.StreamSerializer.Framer.NewFrameWriter(buf), scheme.Codecs.EncoderForVersion(info.StreamSerializer.Serializer, gv))
+	if err := encoder.Encode(&metav1.WatchEvent{Type: string(eventType), Object: runtime.RawExtension{Raw: object.Bytes()}}); err != nil {
+		t.Fatalf("failed to encode watch event: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func Test_watchAbortTransport(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default", ResourceVersion: "10"}}
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name      string
+		mediaType string
+		policy    WatchAbortPolicy
+		clientCtx context.Context
+		upstream  error
+		want      []watch.EventType
+		// wantErr is true if reading the response fails
+		wantErr bool
+		// truncated is true if the last event in response is truncated
+		truncated bool
+	}{
+		{"json error event", "application/json", WatchAbortPolicyErrorEvent, context.Background(), io.ErrUnexpectedEOF, []watch.EventType{watch.Added, watch.Error}, false, false},
+		{"json close", "application/json", WatchAbortPolicyClose, context.Background(), io.ErrUnexpectedEOF, []watch.EventType{watch.Added}, false, false},
+		{"json abort", "application/json", WatchAbortPolicyAbort, context.Background(), io.ErrUnexpectedEOF, []watch.EventType{watch.Added}, true, true},
+		{"protobuf error event", "application/vnd.kubernetes.protobuf", WatchAbortPolicyErrorEvent, context.Background(), io.ErrUnexpectedEOF, []watch.EventType{watch.Added, watch.Error}, false, false},
+		{"protobuf close", "application/vnd.kubernetes.protobuf", WatchAbortPolicyClose, context.Background(), io.ErrUnexpectedEOF, []watch.EventType{watch.Added}, false, false},
+		{"protobuf abort", "application/vnd.kubernetes.protobuf", WatchAbortPolicyAbort, context.Background(), io.ErrUnexpectedEOF, []watch.EventType{watch.Added}, true, true},
+		{"client disconnected", "application/json", WatchAbortPolicyErrorEvent, canceled, io.ErrUnexpectedEOF, []watch.EventType{watch.Added}, true, true},
+		{"upstream ends", "application/json", WatchAbortPolicyErrorEvent, context.Background(), io.EOF, []watch.EventType{watch.Added}, false, true},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			info, _ := runtime.SerializerInfoForMediaType(scheme.Codecs.SupportedMediaTypes(), tt.mediaType)
+			first := encodeTestWatchEvent(t, info, watch.Added, pod)
+			second := encodeTestWatchEvent(t, info, watch.Modified, pod)
+			// upstream is aborted in the middle of the second event
+			data := append(append([]byte{}, first...), second[:len(second)/2]...)
+
+			transport := &watchAbortTransport{
+				cluster:   "test",
+				resource:  "pods",
+				policy:    tt.policy,
+				codecs:    scheme.Codecs,
+				clientCtx: tt.clientCtx,
+				transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+					header := http.Header{}
+					header.Set("Content-Type", tt.mediaType+";stream=watch")
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Header:     header,
+						Body:       &abortedBody{data: data, chunk: 7, err: tt.upstream},
+					}, nil
+				}),
+			}
+			resp, err := transport.RoundTrip(httptest.NewRequest(http.MethodGet, "/api/v1/pods?watch=true", nil))
+			if err != nil {
+				t.Fatalf("watchAbortTransport.RoundTrip() error = %v", err)
+			}
+			body, readErr := ioutil.ReadAll(resp.Body)
+			if gotErr := readErr != nil; gotErr != tt.wantErr {
+				t.Errorf("reading response error = %v, wantErr %v", readErr, tt.wantErr)
+			}
+
+			decoder := restclientwatch.NewDecoder(
+				streaming.NewDecoder(info.StreamSerializer.Framer.NewFrameReader(ioutil.NopCloser(bytes.NewReader(body))), info.StreamSerializer.Serializer),
+				scheme.Codecs.UniversalDeserializer(),
+			)
+			var got []watch.EventType
+			for {
+				eventType, obj, err := decoder.Decode()
+				if err != nil {
+					if truncated := !errors.Is(err, io.EOF); truncated != tt.truncated {
+						t.Errorf("decoding watch event error = %v, truncated %v", err, tt.truncated)
+					}
+					break
+				}
+				got = append(got, eventType)
+				if eventType == watch.Error {
+					status, ok := obj.(*metav1.Status)
+					if !ok || status.Code != http.StatusInternalServerError {
+						t.Errorf("unexpected error event object %#v", obj)
+					}
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decoded events = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_watchStreamReader_completeFrames(t *testing.T) {
+	tests := []struct {
+		name            string
+		lengthDelimited bool
+		data            string
+		want            int
+	}{
+		{"json no frame", false, `{"type":"ADDED"`, 0},
+		{"json complete frames", false, "{\"type\":\"ADDED\"}\n{\"type\"", 17},
+		{"protobuf short header", true, "\x00\x00", 0},
+		{"protobuf partial frame", true, "\x00\x00\x00\x02a", 0},
+		{"protobuf complete frames", true, "\x00\x00\x00\x01a\x00\x00\x00\x00\x00\x00\x00\x02b", 9},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			r := &watchStreamReader{lengthDelimited: tt.lengthDelimited, pending: []byte(tt.data)}
+			if got := r.completeFrames(0); got != tt.want {
+				t.Errorf("watchStreamReader.completeFrames() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// blockingBody returns data and then blocks until ctx is done
+type blockingBody struct {
+	data []byte
+	ctx  context.Context
+}
+
+func (b *blockingBody) Read(p []byte) (int, error) {
+	if len(b.data) == 0 {
+		<-b.ctx.Done()
+		return 0, b.ctx.Err()
+	}
+	n := copy(p, b.data)
+	b.data = b.data[n:]
+	return n, nil
+}
+
+func (b *blockingBody) Close() error {
+	return nil
+}
+
+func Test_watchAbortTransport_expire(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default", ResourceVersion: "10"}}
+	info, _ := runtime.SerializerInfoForMediaType(scheme.Codecs.SupportedMediaTypes(), "application/json")
+	event := encodeTestWatchEvent(t, info, watch.Added, pod)
+
+	transport := &watchAbortTransport{
+		cluster:   "test",
+		resource:  "pods",
+		policy:    WatchAbortPolicyErrorEvent,
+		codecs:    scheme.Codecs,
+		clientCtx: context.Background(),
+		transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			header := http.Header{}
+			header.Set("Content-Type", "application/json;stream=watch")
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     header,
+				Body:       &blockingBody{data: event, ctx: req.Context()},
+			}, nil
+		}),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	resp, err := transport.RoundTrip(httptest.NewRequest(http.MethodGet, "/api/v1/pods?watch=true", nil).WithContext(ctx))
+	if err != nil {
+		t.Fatalf("watchAbortTransport.RoundTrip() error = %v", err)
+	}
+	buf := make([]byte, len(event))
+	if _, err := io.ReadFull(resp.Body, buf); err != nil {
+		t.Fatalf("reading the first event error = %v", err)
+	}
+
+	transport.expire()
+	cancel()
+	rest, err := ioutil.ReadAll(resp.Body)
+	if err != nil || len(rest) != 0 {
+		t.Errorf("reading after the watch expires = %q, %v, want it closed cleanly", rest, err)
+	}
+}
+
+func Test_jitterWatchDuration(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		if got := jitterWatchDuration(time.Minute); got > time.Minute || got < 54*time.Second {
+			t.Fatalf("jitterWatchDuration() = %v, want between 54s and 1m", got)
+		}
+	}
+}
@@ -26,24 +26,39 @@ import (
 	openapicommon "k8s.io/kube-openapi/pkg/common"
 
 	"github.com/kubewharf/kubegateway/pkg/clusters"
+	"github.com/kubewharf/kubegateway/pkg/gateway/authentication/clientcert"
 	proxyauthenticator "github.com/kubewharf/kubegateway/pkg/gateway/proxy/authenticator"
 )
 
 type AuthenticationOptions struct {
 	TokenSuccessCacheTTL time.Duration
 	TokenFailureCacheTTL time.Duration
+	// TokenCacheMaxEntries is the maximum number of cached token review results of each upstream cluster
+	TokenCacheMaxEntries int
+	// TokenReviewMaxInflight is the maximum number of inflight token reviews to each upstream cluster
+	TokenReviewMaxInflight int
+	// ClientCertSerialAllowlistFile is a file of serial numbers of client certificates which are allowed
+	ClientCertSerialAllowlistFile string
 }
 
 func NewAuthenticationOptions() *AuthenticationOptions {
 	o := &AuthenticationOptions{
 		TokenSuccessCacheTTL: 600 * time.Second, // 10 minutes
 		TokenFailureCacheTTL: 10 * time.Second,
+		TokenCacheMaxEntries: 32768,
 	}
 	return o
 }
 
 func (o *AuthenticationOptions) Validate() []error {
-	return nil
+	var errs []error
+	if o.TokenCacheMaxEntries < 0 {
+		errs = append(errs, newFlagError("proxy-authentication-token-cache-max-entries", "set it to 0 for no limit", "can not be negative, got %d", o.TokenCacheMaxEntries))
+	}
+	if o.TokenReviewMaxInflight < 0 {
+		errs = append(errs, newFlagError("proxy-authentication-token-review-max-inflight", "set it to 0 for no limit", "can not be negative, got %d", o.TokenReviewMaxInflight))
+	}
+	return errs
 }
 
 func (o *AuthenticationOptions) AddFlags(fs *pflag.FlagSet) {
@@ -51,6 +66,16 @@ func (o *AuthenticationOptions) AddFlags(fs *pflag.FlagSet) {
 		"The duration to cache seccess responses from the upstream token request authenticator.")
 	fs.DurationVar(&o.TokenFailureCacheTTL, "proxy-authentication-token-failure-cache-ttl", o.TokenFailureCacheTTL,
 		"The duration to cache failure responses from the upstream token request authenticator.")
+	fs.IntVar(&o.TokenCacheMaxEntries, "proxy-authentication-token-cache-max-entries", o.TokenCacheMaxEntries, ""+
+		"The maximum number of token review results cached for each upstream cluster, 0 means no limit. The least "+
+		"recently used result is evicted when the cache is full.")
+	fs.IntVar(&o.TokenReviewMaxInflight, "proxy-authentication-token-review-max-inflight", o.TokenReviewMaxInflight, ""+
+		"The maximum number of inflight TokenReviews sent to each upstream cluster, 0 means no limit. Concurrent reviews "+
+		"of the same token always share one TokenReview. Reviews waiting for a slot longer than the review timeout fail.")
+	fs.StringVar(&o.ClientCertSerialAllowlistFile, "proxy-client-cert-serial-allowlist-file", o.ClientCertSerialAllowlistFile, ""+
+		"If set, a client certificate must both chain to the client CA and have its serial number listed in this file "+
+		"to authenticate, certificates absent from the list are rejected. The file contains a hex serial number per line, "+
+		"lines starting with '#' are ignored. It is reloaded when it changes.")
 }
 
 func (o *AuthenticationOptions) ToAuthenticationConfig(
@@ -62,10 +87,12 @@ func (o *AuthenticationOptions) ToAuthenticationConfig(
 		return nil, nil
 	}
 	cfg := proxyauthenticator.AuthenricatorConfig{
-		TokenSuccessCacheTTL: o.TokenSuccessCacheTTL,
-		TokenFailureCacheTTL: o.TokenFailureCacheTTL,
-		APIAudiences:         controlplaneAutheNConfig.GetAPIAudiences(),
-		Anonymous:            true,
+		TokenSuccessCacheTTL:   o.TokenSuccessCacheTTL,
+		TokenFailureCacheTTL:   o.TokenFailureCacheTTL,
+		TokenCacheMaxEntries:   o.TokenCacheMaxEntries,
+		TokenReviewMaxInflight: o.TokenReviewMaxInflight,
+		APIAudiences:           controlplaneAutheNConfig.GetAPIAudiences(),
+		Anonymous:              true,
 	}
 
 	if clientCert := controlplaneAutheNConfig.GetClientCert(); clientCert != nil {
@@ -87,6 +114,14 @@ func (o *AuthenticationOptions) ToAuthenticationConfig(
 		cfg.ClientCert.SNIVerifyOptionsPorvider = sniVerifyOptionsProvider
 	}
 
+	if len(o.ClientCertSerialAllowlistFile) > 0 && cfg.ClientCert != nil {
+		allowlist, err := clientcert.NewSerialAllowlist(o.ClientCertSerialAllowlistFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client cert serial allowlist: %v", err)
+		}
+		cfg.ClientCert.SerialAllowlist = allowlist
+	}
+
 	if clientProvider != nil {
 		cfg.TokenRequest = &proxyauthenticator.TokenAuthenticationConfig{
 			ClusterClientProvider: clientProvider,
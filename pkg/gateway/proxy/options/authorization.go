@@ -27,18 +27,29 @@ import (
 type AuthorizationOptions struct {
 	CacheAuthorizedTTL   time.Duration
 	CacheUnauthorizedTTL time.Duration
+	// ImpersonationQPS and ImpersonationBurst limit impersonation requests of each impersonator in each cluster
+	ImpersonationQPS   float32
+	ImpersonationBurst int
 }
 
 func NewAuthorizationOptions() *AuthorizationOptions {
 	o := &AuthorizationOptions{
 		CacheAuthorizedTTL:   5 * time.Minute,
 		CacheUnauthorizedTTL: 30 * time.Second,
+		ImpersonationBurst:   10,
 	}
 	return o
 }
 
 func (o *AuthorizationOptions) Validate() []error {
-	return nil
+	var errs []error
+	if o.ImpersonationQPS < 0 {
+		errs = append(errs, newFlagError("proxy-impersonation-qps", "set it to 0 for no limit", "can not be negative, got %v", o.ImpersonationQPS))
+	}
+	if o.ImpersonationQPS > 0 && o.ImpersonationBurst < 1 {
+		errs = append(errs, newFlagError("proxy-impersonation-burst", "", "must be positive when --proxy-impersonation-qps is set, got %d", o.ImpersonationBurst))
+	}
+	return errs
 }
 
 func (o *AuthorizationOptions) ToAuthorizationConfig(clientProvider clusters.ClientProvider) *authorizer.AuthorizerConfig {
@@ -56,6 +67,12 @@ func (o *AuthorizationOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.DurationVar(&o.CacheUnauthorizedTTL,
 		"proxy-authorization-cache-unauthorized-ttl", o.CacheUnauthorizedTTL,
 		"The duration to cache 'unauthorized' responses from the subject request authorizer.")
+	fs.Float32Var(&o.ImpersonationQPS, "proxy-impersonation-qps", o.ImpersonationQPS, ""+
+		"The maximum QPS of authorized impersonation requests of each impersonator in each cluster, requests over "+
+		"the limit are rejected with 429. Zero means no limit. Impersonation requests are always counted in the metric "+
+		"kubegateway_impersonation_total.")
+	fs.IntVar(&o.ImpersonationBurst, "proxy-impersonation-burst", o.ImpersonationBurst, ""+
+		"The burst of impersonation requests of each impersonator in each cluster, it works with --proxy-impersonation-qps.")
 }
 
 func (o *AuthorizationOptions) ApplyTo(
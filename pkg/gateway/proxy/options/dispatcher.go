@@ -0,0 +1,147 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/kubewharf/kubegateway/pkg/gateway/endpoints/filters"
+	"github.com/kubewharf/kubegateway/pkg/gateway/endpoints/request"
+	gatewaynet "github.com/kubewharf/kubegateway/pkg/gateway/net"
+	"github.com/kubewharf/kubegateway/pkg/gateway/proxy/dispatcher"
+)
+
+type DispatcherOptions struct {
+	MalformedRequestPolicy string
+	HostnameMismatchPolicy string
+	MaxReplayableBodyBytes int64
+	TrustedProxyCIDRs      []string
+	// GatewayServedPaths are well-known paths served by the gateway itself instead of upstream clusters
+	GatewayServedPaths []string
+	// FlowControlAuditPolicy decides which flow control decisions are recorded in audit events
+	FlowControlAuditPolicy string
+	// ListAffinity dispatches watches to the endpoint which responds the list they start from
+	ListAffinity bool
+	// WatchAbortPolicy decides how watch responses are terminated if the upstream stream is aborted
+	WatchAbortPolicy string
+	// MaxRequestDuration is the ceiling of the duration of non long-running requests
+	MaxRequestDuration time.Duration
+	// MaxWatchDuration is the ceiling of the duration of watches, they are closed cleanly after it
+	MaxWatchDuration time.Duration
+}
+
+func NewDispatcherOptions() *DispatcherOptions {
+	return &DispatcherOptions{
+		MalformedRequestPolicy: string(dispatcher.MalformedRequestPolicyReject),
+		HostnameMismatchPolicy: string(request.HostnameMismatchPolicyTrustHost),
+		FlowControlAuditPolicy: string(dispatcher.FlowControlAuditNone),
+		WatchAbortPolicy:       string(dispatcher.WatchAbortPolicyErrorEvent),
+	}
+}
+
+func (o *DispatcherOptions) Validate() []error {
+	var errs []error
+	switch dispatcher.MalformedRequestPolicy(o.MalformedRequestPolicy) {
+	case dispatcher.MalformedRequestPolicyReject, dispatcher.MalformedRequestPolicyNonResourceURL:
+	default:
+		errs = append(errs, newFlagError("proxy-malformed-request-policy", "", "must be one of %q or %q, got %q",
+			dispatcher.MalformedRequestPolicyReject, dispatcher.MalformedRequestPolicyNonResourceURL, o.MalformedRequestPolicy))
+	}
+	switch request.HostnameMismatchPolicy(o.HostnameMismatchPolicy) {
+	case request.HostnameMismatchPolicyTrustHost, request.HostnameMismatchPolicyTrustSNI, request.HostnameMismatchPolicyReject:
+	default:
+		errs = append(errs, newFlagError("proxy-hostname-mismatch-policy", "", "must be one of %q, %q or %q, got %q",
+			request.HostnameMismatchPolicyTrustHost, request.HostnameMismatchPolicyTrustSNI, request.HostnameMismatchPolicyReject, o.HostnameMismatchPolicy))
+	}
+	switch dispatcher.FlowControlAuditPolicy(o.FlowControlAuditPolicy) {
+	case dispatcher.FlowControlAuditNone, dispatcher.FlowControlAuditRejected, dispatcher.FlowControlAuditAll:
+	default:
+		errs = append(errs, newFlagError("proxy-flowcontrol-audit-policy", "", "must be one of %q, %q or %q, got %q",
+			dispatcher.FlowControlAuditNone, dispatcher.FlowControlAuditRejected, dispatcher.FlowControlAuditAll, o.FlowControlAuditPolicy))
+	}
+	switch dispatcher.WatchAbortPolicy(o.WatchAbortPolicy) {
+	case dispatcher.WatchAbortPolicyErrorEvent, dispatcher.WatchAbortPolicyClose, dispatcher.WatchAbortPolicyAbort:
+	default:
+		errs = append(errs, newFlagError("proxy-watch-abort-policy", "", "must be one of %q, %q or %q, got %q",
+			dispatcher.WatchAbortPolicyErrorEvent, dispatcher.WatchAbortPolicyClose, dispatcher.WatchAbortPolicyAbort, o.WatchAbortPolicy))
+	}
+	if _, err := o.TrustedProxies(); err != nil {
+		errs = append(errs, newFlagError("proxy-trusted-proxy-cidrs", "use IPs or CIDRs like 10.0.0.0/8", "%v", err))
+	}
+	if o.MaxRequestDuration < 0 {
+		errs = append(errs, newFlagError("proxy-max-request-duration", "set it to 0 to disable it", "can not be negative, got %v", o.MaxRequestDuration))
+	}
+	if o.MaxWatchDuration < 0 {
+		errs = append(errs, newFlagError("proxy-max-watch-duration", "set it to 0 to disable it", "can not be negative, got %v", o.MaxWatchDuration))
+	} else if o.MaxWatchDuration > 0 && o.MaxWatchDuration < time.Minute {
+		errs = append(errs, newFlagError("proxy-max-watch-duration", "shorter durations make clients rewatch too often, set it to 0 to disable it", "must be at least 1m, got %v", o.MaxWatchDuration))
+	}
+	if o.MaxReplayableBodyBytes < 0 {
+		errs = append(errs, newFlagError("proxy-max-replayable-body-bytes", "set it to 0 to disable buffering", "can not be negative, got %d", o.MaxReplayableBodyBytes))
+	}
+	for _, path := range o.GatewayServedPaths {
+		if !filters.GatewayServablePaths.Has(path) {
+			errs = append(errs, newFlagError("proxy-gateway-served-paths", "remove it from the list to proxy it to upstream clusters", "must be a subset of %q, got %q", filters.GatewayServablePaths.List(), path))
+		}
+	}
+	return errs
+}
+
+// TrustedProxies returns the parsed trusted proxy CIDRs.
+func (o *DispatcherOptions) TrustedProxies() (gatewaynet.TrustedProxies, error) {
+	return gatewaynet.ParseTrustedProxies(o.TrustedProxyCIDRs)
+}
+
+func (o *DispatcherOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.MalformedRequestPolicy, "proxy-malformed-request-policy", o.MalformedRequestPolicy, ""+
+		"How to dispatch requests whose path can not be parsed cleanly into request info. "+
+		"Reject responds 400 BadRequest, NonResourceURL matches them against nonResourceURLs rules only.")
+	fs.StringVar(&o.HostnameMismatchPolicy, "proxy-hostname-mismatch-policy", o.HostnameMismatchPolicy, ""+
+		"Which hostname is used to choose the upstream cluster when TLS SNI and Host header of an HTTP/1.x request "+
+		"indicate different hostnames. Host trusts Host header, SNI trusts TLS server name, Reject responds 421 Misdirected Request.")
+	fs.Int64Var(&o.MaxReplayableBodyBytes, "proxy-max-replayable-body-bytes", o.MaxReplayableBodyBytes, ""+
+		"Request bodies up to this size are buffered in memory so that requests can be replayed on retry, "+
+		"requests with larger bodies are never retried. Zero disables buffering.")
+	fs.StringSliceVar(&o.TrustedProxyCIDRs, "proxy-trusted-proxy-cidrs", o.TrustedProxyCIDRs, ""+
+		"A list of CIDRs or IPs of trusted proxies, e.g. L7 load balancers in front of the gateway. The real client IP "+
+		"is extracted from X-Forwarded-For or Forwarded headers only if the request comes from a trusted proxy. "+
+		"If empty, forwarding headers are ignored and the remote address is used as the client IP.")
+	fs.StringSliceVar(&o.GatewayServedPaths, "proxy-gateway-served-paths", o.GatewayServedPaths, ""+
+		"A list of well-known paths which are served by the gateway itself instead of being dispatched to the upstream "+
+		"cluster, e.g. /version responds the version of gateway. Sub paths of health check paths, such as /healthz/ping, "+
+		"are served by the gateway too. Allowed paths are /, /version, /healthz, /livez and /readyz. "+
+		"If empty, all requests are dispatched to the upstream cluster.")
+	fs.StringVar(&o.FlowControlAuditPolicy, "proxy-flowcontrol-audit-policy", o.FlowControlAuditPolicy, ""+
+		"Which flow control decisions are recorded in audit events as annotations flowcontrol.kubegateway.io/schema and "+
+		"flowcontrol.kubegateway.io/decision. None records nothing, Rejected records requests rejected by flow control, "+
+		"All records both admitted and rejected requests.")
+	fs.BoolVar(&o.ListAffinity, "proxy-list-affinity", o.ListAffinity, ""+
+		"If true, the resourceVersion of list responses is tracked, and a watch starting from it is dispatched to the endpoint "+
+		"which responds the list if it is ready, so that the watch does not land on a lagging endpoint and fail with "+
+		"\"too old resource version\".")
+	fs.StringVar(&o.WatchAbortPolicy, "proxy-watch-abort-policy", o.WatchAbortPolicy, ""+
+		"How to terminate a watch response if the upstream watch stream is aborted before the client disconnects. "+
+		"ErrorEvent ends it with an ERROR watch event after the last complete event, Close ends it cleanly after the "+
+		"last complete event, Abort aborts the response and the client may get a truncated event.")
+	fs.DurationVar(&o.MaxRequestDuration, "proxy-max-request-duration", o.MaxRequestDuration, ""+
+		"The ceiling of the duration of non long-running requests, requests which do not complete within it are responded "+
+		"504 Gateway Timeout, no matter what timeout is requested by the client. Zero disables it.")
+	fs.DurationVar(&o.MaxWatchDuration, "proxy-max-watch-duration", o.MaxWatchDuration, ""+
+		"The ceiling of the duration of watches. A watch is closed cleanly after the last complete event once it lasts for "+
+		"up to 10% less than it, so that the client rewatches, possibly through another gateway replica, and watches are "+
+		"rebalanced after scaling. It must be at least 1m. Zero disables it.")
+}
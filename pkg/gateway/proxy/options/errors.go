@@ -0,0 +1,46 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"fmt"
+)
+
+// FlagError is a validation error of a flag, it tells which flag is invalid, what is
+// wrong and how to fix it.
+type FlagError struct {
+	// Flag is the flag name without leading dashes
+	Flag string
+	// Detail describes what is wrong with the flag value
+	Detail string
+	// Hint describes how to fix it, it is optional
+	Hint string
+}
+
+func newFlagError(flag, hint, format string, args ...interface{}) *FlagError {
+	return &FlagError{
+		Flag:   flag,
+		Detail: fmt.Sprintf(format, args...),
+		Hint:   hint,
+	}
+}
+
+func (e *FlagError) Error() string {
+	msg := fmt.Sprintf("--%s %s", e.Flag, e.Detail)
+	if len(e.Hint) > 0 {
+		msg += "; " + e.Hint
+	}
+	return msg
+}
@@ -0,0 +1,46 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"testing"
+)
+
+func TestFlagError_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *FlagError
+		want string
+	}{
+		{
+			"without hint",
+			newFlagError("proxy-max-request-duration", "", "can not be negative, got %v", "-1s"),
+			"--proxy-max-request-duration can not be negative, got -1s",
+		},
+		{
+			"with hint",
+			newFlagError("proxy-max-request-duration", "set it to 0 to disable it", "can not be negative, got %v", "-1s"),
+			"--proxy-max-request-duration can not be negative, got -1s; set it to 0 to disable it",
+		},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.want {
+				t.Errorf("FlagError.Error() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -14,22 +14,61 @@
 
 package options
 
-import "github.com/spf13/pflag"
+import (
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"github.com/kubewharf/kubegateway/pkg/gateway/proxy/dispatcher"
+)
 
 type LoggingOptions struct {
 	EnableProxyAccessLog bool
+	// AccessLogIncludeFields and AccessLogExcludeFields select fields in access log
+	AccessLogIncludeFields []string
+	AccessLogExcludeFields []string
+	// AccessLogFormat is the layout of access log
+	AccessLogFormat string
+	// EnableTLSHandshakeLog logs connections which fail in tls handshakes, they never reach access logs
+	EnableTLSHandshakeLog bool
 }
 
 func NewLoggingOptions() *LoggingOptions {
 	return &LoggingOptions{
 		EnableProxyAccessLog: false,
+		AccessLogFormat:      string(dispatcher.AccessLogFormatDefault),
 	}
 }
 
 func (o *LoggingOptions) Validate() []error {
-	return nil
+	var errs []error
+	if _, err := o.AccessLogFields(); err != nil {
+		errs = append(errs, err)
+	}
+	switch dispatcher.AccessLogFormat(o.AccessLogFormat) {
+	case dispatcher.AccessLogFormatDefault, dispatcher.AccessLogFormatEnvoy:
+	default:
+		errs = append(errs, newFlagError("proxy-access-log-format", "", "must be one of %q or %q, got %q", dispatcher.AccessLogFormatDefault, dispatcher.AccessLogFormatEnvoy, o.AccessLogFormat))
+	}
+	return errs
+}
+
+// AccessLogFields returns the selected access log fields
+func (o *LoggingOptions) AccessLogFields() (dispatcher.AccessLogFields, error) {
+	return dispatcher.NewAccessLogFields(o.AccessLogIncludeFields, o.AccessLogExcludeFields)
 }
 
 func (o *LoggingOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.BoolVar(&o.EnableProxyAccessLog, "enable-proxy-access-log", o.EnableProxyAccessLog, "Enable proxy access log")
+	fs.StringSliceVar(&o.AccessLogIncludeFields, "proxy-access-log-include-fields", o.AccessLogIncludeFields, ""+
+		"Fields that appear in proxy access log, defaults to "+strings.Join(dispatcher.DefaultAccessLogFields, ",")+". "+
+		"Known fields are "+strings.Join(dispatcher.AllAccessLogFields(), ",")+".")
+	fs.StringSliceVar(&o.AccessLogExcludeFields, "proxy-access-log-exclude-fields", o.AccessLogExcludeFields,
+		"Fields that are removed from proxy access log.")
+	fs.StringVar(&o.AccessLogFormat, "proxy-access-log-format", o.AccessLogFormat, ""+
+		"Format of proxy access log, one of default or envoy. The envoy format writes access logs to stdout "+
+		"in the layout of Envoy's default access log format, and the selected fields are ignored.")
+	fs.BoolVar(&o.EnableTLSHandshakeLog, "enable-proxy-tls-handshake-log", o.EnableTLSHandshakeLog, ""+
+		"Enable connection logs of failed tls handshakes with the client address and failure reason, e.g. client "+
+		"certificates which are rejected. These connections never reach proxy access logs.")
 }
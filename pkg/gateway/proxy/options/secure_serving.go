@@ -15,7 +15,7 @@
 package options
 
 import (
-	"fmt"
+	"time"
 
 	"github.com/spf13/pflag"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -23,10 +23,26 @@ import (
 	"k8s.io/apiserver/pkg/server/options"
 
 	contronplaneoptions "github.com/kubewharf/kubegateway/pkg/gateway/controlplane/options"
+	"github.com/kubewharf/kubegateway/pkg/gateway/metrics"
+	gatewaynet "github.com/kubewharf/kubegateway/pkg/gateway/net"
 )
 
 type SecureServingOptions struct {
 	Ports []int
+	// GracefulDrainTimeout bounds how long the proxy waits for in-flight proxied requests,
+	// including watches, to finish during shutdown before forcing them to close.
+	// Zero keeps the generic server behavior.
+	GracefulDrainTimeout time.Duration
+	// MaxRequestsInflightPerConnection limits in-flight requests of each downstream
+	// connection, zero means no limit.
+	MaxRequestsInflightPerConnection int
+	// RequireSNI rejects tls handshakes without SNI
+	RequireSNI bool
+	// MaxConnectionsPerSourceIP limits concurrent downstream connections of each source IP,
+	// zero means no limit.
+	MaxConnectionsPerSourceIP int
+	// ConnectionLimitExemptCIDRs are source IPs which are not limited by MaxConnectionsPerSourceIP
+	ConnectionLimitExemptCIDRs []string
 }
 
 func NewSecureServingOptions() *SecureServingOptions {
@@ -45,32 +61,69 @@ func (s *SecureServingOptions) ValidateWith(controlplaneSecureServingOptions con
 	}
 
 	if len(s.Ports) == 0 {
-		errors = append(errors, fmt.Errorf("--proxy-secure-ports must be set"))
+		errors = append(errors, newFlagError("proxy-secure-ports", "set at least one port, e.g. --proxy-secure-ports=9443", "must be set"))
 	}
 	for _, port := range s.Ports {
 		if port < 1 || port > 65535 {
-			errors = append(errors, fmt.Errorf("ports in --proxy-secure-ports %v must be between 1 and 65535, inclusive. It cannot be turned off with 0", port))
+			errors = append(errors, newFlagError("proxy-secure-ports", "it can not be turned off with 0", "port %v must be between 1 and 65535, inclusive", port))
 		}
 		if usedPorts.Has(port) {
-			errors = append(errors, fmt.Errorf("ports in --proxy-secure-ports %v is duplicate in --secure-port or --other-secure-ports", port))
+			errors = append(errors, newFlagError("proxy-secure-ports", "use ports which are not used by --secure-port or --other-secure-ports", "port %v is duplicate", port))
 		} else {
 			usedPorts.Insert(port)
 		}
 	}
 
+	if s.GracefulDrainTimeout < 0 {
+		errors = append(errors, newFlagError("proxy-graceful-drain-timeout", "set it to 0 to wait as the generic server does", "can not be negative, got %v", s.GracefulDrainTimeout))
+	}
+	if s.MaxRequestsInflightPerConnection < 0 {
+		errors = append(errors, newFlagError("proxy-max-requests-inflight-per-connection", "set it to 0 for no limit", "can not be negative, got %d", s.MaxRequestsInflightPerConnection))
+	}
+	if s.MaxConnectionsPerSourceIP < 0 {
+		errors = append(errors, newFlagError("proxy-max-connections-per-source-ip", "set it to 0 for no limit", "can not be negative, got %d", s.MaxConnectionsPerSourceIP))
+	}
+	if _, err := s.ConnectionLimitExempt(); err != nil {
+		errors = append(errors, newFlagError("proxy-connection-limit-exempt-cidrs", "use IPs or CIDRs like 10.0.0.0/8", "%v", err))
+	}
+
 	return errors
 }
 
+// ConnectionLimitExempt returns the parsed CIDRs exempt from the connection limit per source IP.
+func (s *SecureServingOptions) ConnectionLimitExempt() (gatewaynet.TrustedProxies, error) {
+	return gatewaynet.ParseTrustedProxies(s.ConnectionLimitExemptCIDRs)
+}
+
 func (s *SecureServingOptions) AddFlags(fs *pflag.FlagSet) {
 	if s == nil {
 		return
 	}
 	fs.IntSliceVar(&s.Ports, "proxy-secure-ports", s.Ports, "A list of ports which to serve HTTPS for apiserver proxy with authentication and authorization.")
+	fs.DurationVar(&s.GracefulDrainTimeout, "proxy-graceful-drain-timeout", s.GracefulDrainTimeout, ""+
+		"The maximum duration the proxy waits for in-flight proxied requests, including long-running watches, "+
+		"to finish after it stops accepting new connections during shutdown. Requests still in flight are forced to close "+
+		"after the timeout. Zero means waiting as the generic server does, long-running requests are not waited for.")
+	fs.IntVar(&s.MaxRequestsInflightPerConnection, "proxy-max-requests-inflight-per-connection", s.MaxRequestsInflightPerConnection, ""+
+		"The maximum number of in-flight requests of each client connection, requests over the limit are rejected with 429. "+
+		"It applies to both HTTP/1.1 and HTTP/2, and long-running requests are not limited. Zero means no limit.")
+	fs.IntVar(&s.MaxConnectionsPerSourceIP, "proxy-max-connections-per-source-ip", s.MaxConnectionsPerSourceIP, ""+
+		"The maximum number of concurrent client connections from each source IP, new connections over the limit are "+
+		"closed before TLS handshakes. The source IP is the remote address of the connection, or the address in the PROXY "+
+		"protocol header if it is used. Connections from --proxy-trusted-proxy-cidrs and --proxy-connection-limit-exempt-cidrs "+
+		"are not limited, because connections of a proxy are shared by its clients. Zero means no limit.")
+	fs.StringSliceVar(&s.ConnectionLimitExemptCIDRs, "proxy-connection-limit-exempt-cidrs", s.ConnectionLimitExemptCIDRs, ""+
+		"A list of CIDRs or IPs whose connections are not limited by --proxy-max-connections-per-source-ip.")
+	fs.BoolVar(&s.RequireSNI, "proxy-require-sni", s.RequireSNI, ""+
+		"If true, TLS handshakes without SNI are rejected instead of choosing the cluster by the local IP which clients "+
+		"connect to, so that clients can not bypass name-based routing by connecting to an IP directly. "+
+		"Note that probes connecting to the proxy ports by IP fail as well.")
 }
 
 func (s *SecureServingOptions) ApplyTo(
 	secureServingInfo **server.SecureServingInfo,
 	controlplaneSecureServingOptions contronplaneoptions.SecureServingOptions,
+	trustedProxies gatewaynet.TrustedProxies,
 ) error {
 	options := deepcopySecureServingOptions(controlplaneSecureServingOptions)
 
@@ -84,7 +137,17 @@ func (s *SecureServingOptions) ApplyTo(
 	}
 
 	// we don't need loopbackconfig for proxy
-	return options.ApplyTo(secureServingInfo, nil)
+	if err := options.ApplyTo(secureServingInfo, nil); err != nil {
+		return err
+	}
+
+	if s.MaxConnectionsPerSourceIP > 0 {
+		// exempt CIDRs are validated in ValidateWith
+		exempt, _ := s.ConnectionLimitExempt()
+		exempt = append(exempt, trustedProxies...)
+		(*secureServingInfo).Listener = gatewaynet.LimitConnectionsPerSourceIP((*secureServingInfo).Listener, s.MaxConnectionsPerSourceIP, exempt, metrics.RecordSourceIPConnectionRejected)
+	}
+	return nil
 }
 
 func deepcopySecureServingOptions(in contronplaneoptions.SecureServingOptions) contronplaneoptions.SecureServingOptions {
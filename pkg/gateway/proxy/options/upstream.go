@@ -0,0 +1,116 @@
+// Copyright 2022 ByteDance and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"github.com/spf13/pflag"
+
+	"github.com/kubewharf/kubegateway/pkg/gateway/controllers"
+)
+
+// UpstreamOptions holds options about upstream clusters
+type UpstreamOptions struct {
+	SelfTestPolicy       string
+	EmptyUpstreamsPolicy string
+	// MaxEndpointsPerCluster limits the number of endpoints of each cluster, zero means no limit
+	MaxEndpointsPerCluster int32
+	// ReconcileEphemeralEndpoints removes ephemeral endpoints of a cluster once servers in its spec change
+	ReconcileEphemeralEndpoints bool
+	// ConfigFailureThreshold is the number of consecutive failures of applying an UpstreamCluster
+	// config to escalate, zero disables escalation
+	ConfigFailureThreshold int32
+	// DegradeReadinessOnConfigFailures fails readiness while any cluster's failures are escalated
+	DegradeReadinessOnConfigFailures bool
+	// DispatchPoliciesWarningThreshold is the number of dispatch policies of a cluster above which
+	// warnings are logged, zero disables warnings
+	DispatchPoliciesWarningThreshold int32
+	// MaxDispatchPolicies limits the number of dispatch policies of each cluster, zero means no limit
+	MaxDispatchPolicies int32
+	// HealthCheckVerboseReadyz health checks upstream endpoints with /readyz?verbose instead of /healthz
+	HealthCheckVerboseReadyz bool
+}
+
+func NewUpstreamOptions() *UpstreamOptions {
+	return &UpstreamOptions{
+		SelfTestPolicy:                   string(controllers.SelfTestDisabled),
+		EmptyUpstreamsPolicy:             string(controllers.EmptyUpstreamsWarn),
+		ConfigFailureThreshold:           5,
+		DispatchPoliciesWarningThreshold: 100,
+	}
+}
+
+func (o *UpstreamOptions) Validate() []error {
+	var errs []error
+	switch controllers.SelfTestPolicy(o.SelfTestPolicy) {
+	case controllers.SelfTestDisabled, controllers.SelfTestLog, controllers.SelfTestFailIfNoneReachable:
+	default:
+		errs = append(errs, newFlagError("proxy-upstream-self-test-policy", "", "must be one of %q, %q or %q, got %q",
+			controllers.SelfTestDisabled, controllers.SelfTestLog, controllers.SelfTestFailIfNoneReachable, o.SelfTestPolicy))
+	}
+	switch controllers.EmptyUpstreamsPolicy(o.EmptyUpstreamsPolicy) {
+	case controllers.EmptyUpstreamsIgnore, controllers.EmptyUpstreamsWarn, controllers.EmptyUpstreamsFail:
+	default:
+		errs = append(errs, newFlagError("proxy-empty-upstreams-policy", "", "must be one of %q, %q or %q, got %q",
+			controllers.EmptyUpstreamsIgnore, controllers.EmptyUpstreamsWarn, controllers.EmptyUpstreamsFail, o.EmptyUpstreamsPolicy))
+	}
+	if o.MaxEndpointsPerCluster < 0 {
+		errs = append(errs, newFlagError("proxy-max-endpoints-per-cluster", "set it to 0 for no limit", "can not be negative, got %d", o.MaxEndpointsPerCluster))
+	}
+	if o.ConfigFailureThreshold < 0 {
+		errs = append(errs, newFlagError("proxy-upstream-config-failure-threshold", "set it to 0 to disable escalation", "can not be negative, got %d", o.ConfigFailureThreshold))
+	}
+	if o.DegradeReadinessOnConfigFailures && o.ConfigFailureThreshold == 0 {
+		errs = append(errs, newFlagError("proxy-upstream-config-failure-degrade-readiness", "set --proxy-upstream-config-failure-threshold to a positive number",
+			"requires escalation of config failures to be enabled"))
+	}
+	if o.DispatchPoliciesWarningThreshold < 0 {
+		errs = append(errs, newFlagError("proxy-dispatch-policies-warning-threshold", "set it to 0 to disable warnings", "can not be negative, got %d", o.DispatchPoliciesWarningThreshold))
+	}
+	if o.MaxDispatchPolicies < 0 {
+		errs = append(errs, newFlagError("proxy-max-dispatch-policies", "set it to 0 for no limit", "can not be negative, got %d", o.MaxDispatchPolicies))
+	}
+	return errs
+}
+
+func (o *UpstreamOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.SelfTestPolicy, "proxy-upstream-self-test-policy", o.SelfTestPolicy, ""+
+		"Whether to test connectivity and authentication of every upstream endpoint once before the proxy is ready. "+
+		"Disabled skips the test, Log logs a pass/fail summary, FailIfNoneReachable also fails startup if none of endpoints passes.")
+	fs.StringVar(&o.EmptyUpstreamsPolicy, "proxy-empty-upstreams-policy", o.EmptyUpstreamsPolicy, ""+
+		"What to do if no upstream cluster has enabled endpoints after all UpstreamClusters are loaded at startup, "+
+		"e.g. a botched config empties the server list. Ignore does nothing, Warn logs a warning, "+
+		"Fail fails startup so that the proxy never becomes ready.")
+	fs.Int32Var(&o.MaxEndpointsPerCluster, "proxy-max-endpoints-per-cluster", o.MaxEndpointsPerCluster, ""+
+		"The maximum number of endpoints of each upstream cluster, endpoints beyond it are rejected with error logs and "+
+		"are never health checked or proxied to. Existing endpoints are kept in preference to new ones. Zero means no limit.")
+	fs.BoolVar(&o.ReconcileEphemeralEndpoints, "proxy-reconcile-ephemeral-endpoints", o.ReconcileEphemeralEndpoints, ""+
+		"If true, endpoints added to a running cluster by the ephemeral endpoints admin API are removed once servers "+
+		"in its UpstreamCluster spec change, otherwise they are kept until removed by the API or restarting.")
+	fs.Int32Var(&o.ConfigFailureThreshold, "proxy-upstream-config-failure-threshold", o.ConfigFailureThreshold, ""+
+		"The number of consecutive failures of applying an UpstreamCluster config after which the failures are escalated with "+
+		"config drift error logs and metric. A cluster always keeps running its last good config if the new one fails. "+
+		"Zero disables escalation.")
+	fs.BoolVar(&o.DegradeReadinessOnConfigFailures, "proxy-upstream-config-failure-degrade-readiness", o.DegradeReadinessOnConfigFailures, ""+
+		"If true, readiness of the proxy fails while failures of applying any UpstreamCluster config are escalated.")
+	fs.Int32Var(&o.DispatchPoliciesWarningThreshold, "proxy-dispatch-policies-warning-threshold", o.DispatchPoliciesWarningThreshold, ""+
+		"Warnings are logged if an upstream cluster has more dispatch policies than it, since policies are evaluated one by one "+
+		"for each request. Zero disables warnings.")
+	fs.Int32Var(&o.MaxDispatchPolicies, "proxy-max-dispatch-policies", o.MaxDispatchPolicies, ""+
+		"The maximum number of dispatch policies of each upstream cluster, a cluster with more policies fails to apply its "+
+		"UpstreamCluster config and keeps running the last good config. Zero means no limit.")
+	fs.BoolVar(&o.HealthCheckVerboseReadyz, "proxy-upstream-health-check-verbose-readyz", o.HealthCheckVerboseReadyz, ""+
+		"If true, upstream endpoints are health checked with /readyz?verbose instead of /healthz, and the names of failed "+
+		"readyz checks, e.g. etcd, are logged and reported in endpoint status. Upstreams must be Kubernetes v1.16 or later.")
+}
@@ -15,12 +15,20 @@
 package server
 
 import (
+	"fmt"
+	"time"
+
 	apiserver "github.com/kubewharf/apiserver-runtime/pkg/server"
+	"github.com/prometheus/client_golang/prometheus"
 	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/apiserver/pkg/server/healthz"
 	serverstorage "k8s.io/apiserver/pkg/server/storage"
+	"k8s.io/klog"
 	"k8s.io/kubernetes/pkg/master"
 
 	"github.com/kubewharf/kubegateway/pkg/gateway/controllers"
+	"github.com/kubewharf/kubegateway/pkg/gateway/endpoints/filters"
+	"github.com/kubewharf/kubegateway/pkg/gateway/metrics"
 	// RESTStorage installers
 )
 
@@ -31,10 +39,29 @@ type Config struct {
 
 type ExtraConfig struct {
 	UpstreamClusterController *controllers.UpstreamClusterController
+	// MetricsRegisterer is an optional registerer that proxy metrics are registered into.
+	// Proxy metrics are registered into the global gateway registry if it is not set.
+	MetricsRegisterer prometheus.Registerer
+	// RequestDrainer tracks in-flight proxied requests, it must be installed in the handler chain.
+	// If it is set, in-flight requests are drained within GracefulDrainTimeout during shutdown.
+	RequestDrainer       *filters.RequestDrainer
+	GracefulDrainTimeout time.Duration
+	// SelfTestPolicy decides whether upstream endpoints are tested before the proxy is ready
+	SelfTestPolicy controllers.SelfTestPolicy
+	// EmptyUpstreamsPolicy decides how startup is handled if no upstream cluster is routable
+	EmptyUpstreamsPolicy controllers.EmptyUpstreamsPolicy
+	// DegradeReadinessOnConfigFailures fails readiness while failures of applying any UpstreamCluster
+	// config are escalated
+	DegradeReadinessOnConfigFailures bool
 }
 
 // Complete fills in any fields not set that are required to have valid data. It's mutating the receiver.
 func (c *Config) Complete() *CompletedConfig {
+	if c.ExtraConfig.UpstreamClusterController != nil && c.ExtraConfig.DegradeReadinessOnConfigFailures {
+		// readiness only, restarting does not help since the config is probably wrong
+		check := healthz.NamedCheck("kube-gateway-upstream-config", c.ExtraConfig.UpstreamClusterController.CheckConfigFailures)
+		c.RecommendedConfig.Config.ReadyzChecks = append(c.RecommendedConfig.Config.ReadyzChecks, check)
+	}
 	cfg := completedConfig{
 		GenericConfig: c.RecommendedConfig.Complete(),
 		ExtraConfig:   &c.ExtraConfig,
@@ -59,6 +86,10 @@ func (c *CompletedConfig) New(delegationTarget genericapiserver.DelegationTarget
 		return nil, err
 	}
 
+	if err := metrics.RegisterTo(c.ExtraConfig.MetricsRegisterer); err != nil {
+		return nil, err
+	}
+
 	if c.ExtraConfig.UpstreamClusterController != nil {
 		// start upstream controller
 		startUpstreamControllerHookName := "kube-gateway-start-upstream-controller"
@@ -71,6 +102,58 @@ func (c *CompletedConfig) New(delegationTarget genericapiserver.DelegationTarget
 		}
 	}
 
+	if c.ExtraConfig.UpstreamClusterController != nil && len(c.ExtraConfig.SelfTestPolicy) > 0 && c.ExtraConfig.SelfTestPolicy != controllers.SelfTestDisabled {
+		// readyz fails until all post start hooks finish, so the proxy is not ready before the self-test
+		selfTestHookName := "kube-gateway-upstream-self-test"
+		err := s.AddPostStartHook(selfTestHookName, func(context genericapiserver.PostStartHookContext) error {
+			results := c.ExtraConfig.UpstreamClusterController.SelfTest(context.StopCh)
+			if c.ExtraConfig.SelfTestPolicy != controllers.SelfTestFailIfNoneReachable || len(results) == 0 {
+				return nil
+			}
+			for _, r := range results {
+				if r.Err == nil {
+					return nil
+				}
+			}
+			return fmt.Errorf("none of %d upstream endpoints passes the self-test", len(results))
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.ExtraConfig.UpstreamClusterController != nil && len(c.ExtraConfig.EmptyUpstreamsPolicy) > 0 && c.ExtraConfig.EmptyUpstreamsPolicy != controllers.EmptyUpstreamsIgnore {
+		checkHookName := "kube-gateway-check-routable-upstreams"
+		err := s.AddPostStartHook(checkHookName, func(context genericapiserver.PostStartHookContext) error {
+			if c.ExtraConfig.EmptyUpstreamsPolicy == controllers.EmptyUpstreamsFail {
+				// the proxy is not ready until the check passes
+				return c.ExtraConfig.UpstreamClusterController.CheckRoutableUpstreams(context.StopCh, controllers.EmptyUpstreamsFail)
+			}
+			// warning does not need to delay readiness
+			go c.ExtraConfig.UpstreamClusterController.CheckRoutableUpstreams(context.StopCh, c.ExtraConfig.EmptyUpstreamsPolicy) //nolint:errcheck
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.ExtraConfig.RequestDrainer != nil && c.ExtraConfig.GracefulDrainTimeout > 0 {
+		// http server shutdown must not give up before in-flight requests are drained
+		s.ShutdownTimeout = c.ExtraConfig.GracefulDrainTimeout
+		drainHookName := "kube-gateway-drain-inflight-requests"
+		err := s.AddPreShutdownHook(drainHookName, func() error {
+			// listeners are closed after ShutdownDelayDuration, requests can still come in before that
+			time.Sleep(s.ShutdownDelayDuration)
+			klog.Infof("[graceful-termination] draining %v in-flight proxied requests, timeout=%v", c.ExtraConfig.RequestDrainer.Inflight(), c.ExtraConfig.GracefulDrainTimeout)
+			c.ExtraConfig.RequestDrainer.Drain(c.ExtraConfig.GracefulDrainTimeout)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return apiserver.New(name, s), nil
 }
 